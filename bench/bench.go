@@ -0,0 +1,513 @@
+// Package bench is the timing harness shared by the Go examples.
+//
+// Every example compares several implementations of the same problem
+// (vibe, human, expert). Instead of sprinkling time.Now/time.Since
+// boilerplate through each main(), an example describes its
+// implementations once and lets Compare time them consistently:
+//
+//	results := bench.Compare(
+//		bench.Implementation{Name: "Vibe coding", Complexity: "O(n²)", Run: func() { vibe(n) }},
+//		bench.Implementation{Name: "Expert coding", Complexity: "O(n log log n)", Run: func() { expert(n) }},
+//	)
+//	bench.Print(os.Stdout, results)
+//
+// Single-shot timings are noisy for small inputs, so CompareWith can
+// repeat each implementation, discard warm-up runs and report summary
+// statistics instead.
+//
+// Implementations that return a value can be described as an Impl
+// instead; CompareImpls checks each one's output against the expected
+// answer, with a caller-supplied equality function, before timing it.
+//
+// Options.Concurrency runs every implementation on several goroutines at
+// once, so code built for concurrent use is timed under contention rather
+// than alone.
+//
+// Long comparisons can be cancelled: CompareContext stops between runs
+// once its context is done, and implementations that set RunContext are
+// expected to stop part-way through a run as well. Options.Limit applies
+// the same to each implementation on its own: one that runs out of time
+// is stopped and reported as DNF, and the rest are still timed.
+//
+// A Profiler attached to the context with WithProfiler writes a CPU or
+// allocation profile of every implementation's measured runs, and a
+// trace.Tracer attached with trace.WithTracer records a span for every
+// implementation and every run, warm-up runs included.
+//
+// Alongside wall time, every Result records how many bytes the
+// implementation allocated and in how many allocations, so the space side
+// of a space/time trade-off is visible in the same report. It also counts
+// the goroutines alive around every run, so an implementation that
+// leaves goroutines running is caught leaking them.
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/iportilla/ai-coding/style"
+	"github.com/iportilla/ai-coding/trace"
+)
+
+// Implementation is one approach to the problem being benchmarked.
+type Implementation struct {
+	Name       string // Label shown in the report, e.g. "Vibe coding"
+	Complexity string // Big-O annotation shown next to the timing, e.g. "O(n²)"
+	Run        func() // The work to time; capture results via closure
+
+	// RunContext, if set, is used instead of Run by CompareContext. It
+	// should return ctx.Err() soon after ctx is done, so a run that would
+	// take minutes can be abandoned part-way through.
+	RunContext func(ctx context.Context) error
+
+	// RunWorker, if set, is used instead of both. Each run calls it from
+	// Options.Concurrency goroutines at once, numbered 0 to
+	// Concurrency-1, and lasts until every call returns.
+	RunWorker func(ctx context.Context, worker int) error
+}
+
+// run executes one run of impl on the given number of goroutines.
+func (impl Implementation) run(ctx context.Context, concurrency int) error {
+	if concurrency <= 1 {
+		return impl.runWorker(ctx, 0)
+	}
+	errs := make([]error, concurrency)
+	var wg sync.WaitGroup
+	for worker := range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[worker] = impl.runWorker(ctx, worker)
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runWorker executes one goroutine's share of a run, preferring
+// RunWorker, then RunContext, then Run.
+func (impl Implementation) runWorker(ctx context.Context, worker int) error {
+	switch {
+	case impl.RunWorker != nil:
+		return impl.RunWorker(ctx, worker)
+	case impl.RunContext != nil:
+		return impl.RunContext(ctx)
+	}
+	impl.Run()
+	return ctx.Err()
+}
+
+// Result is the measured outcome of running one Implementation.
+type Result struct {
+	Name       string
+	Complexity string
+	Duration   time.Duration // Representative timing: the median when repeated
+	Stats      Stats         // Summary over all measured (non warm-up) runs
+	Bytes      uint64        // Heap bytes allocated per run, averaged over the measured runs
+	Allocs     uint64        // Heap allocations (mallocs) per run, averaged likewise
+
+	Samples []time.Duration // Every measured run, in the order they ran
+	GCs     uint32          // Garbage collections completed during the measured runs
+	GCPause time.Duration   // Total stop-the-world GC pause during the measured runs
+
+	// Goroutines counts the goroutines alive before and after each
+	// measured run, in the order they ran. Leaked is how many more are
+	// still alive once the runs are over and given a moment to exit:
+	// goroutines the implementation started and left running. Both count
+	// the whole process, so they are only exact when nothing else starts
+	// or stops goroutines meanwhile.
+	Goroutines []GoroutineCount
+	Leaked     int
+
+	Concurrency int // Goroutines running the implementation at once in each run
+
+	// DNF is set if the implementation did not finish within
+	// Options.Limit. Duration is then the limit, a lower bound on its
+	// real time, and Samples holds only the runs that finished in time.
+	DNF   bool
+	Limit time.Duration // The Options.Limit it was stopped at, if DNF
+
+	Target time.Duration // The Options.Target it was measured against, if any
+}
+
+// GoroutineCount is how many goroutines were alive before and after one
+// run.
+type GoroutineCount struct {
+	Before, After int
+}
+
+// Options controls how many times each implementation is run.
+type Options struct {
+	Runs   int // Measured runs per implementation; values < 1 mean 1
+	Warmup int // Runs executed and discarded before measuring
+
+	// Concurrency is how many goroutines run the implementation at once
+	// in every run; values < 1 mean 1. Use it to measure contention,
+	// e.g. on a shared cache, with Implementation.RunWorker.
+	Concurrency int
+
+	// Limit, if positive, is how long each implementation may take,
+	// warm-up included. One that runs out of time is stopped - part-way
+	// through a run if it honors its context, otherwise once the run
+	// under way returns - and reported as DNF, and the comparison moves
+	// on to the next implementation.
+	Limit time.Duration
+
+	// Target, if positive, is how long a run should take at least, for
+	// work that is meant to be slow, such as hashing a password so that
+	// guessing it is expensive. Results are then judged by whether they
+	// reach it, not by which is fastest.
+	Target time.Duration
+}
+
+// OnTarget reports whether r took at least its Target. A result that did
+// not finish took at least its limit, so it is on target if that is.
+func (r Result) OnTarget() bool {
+	return r.Target > 0 && r.Duration >= r.Target
+}
+
+// Milliseconds returns the duration as fractional milliseconds, the unit
+// used throughout the example output.
+func (r Result) Milliseconds() float64 {
+	return ms(r.Duration)
+}
+
+// GCsPerRun returns how many garbage collections completed per measured
+// run, on average. Below 1, collections happened only every few runs.
+func (r Result) GCsPerRun() float64 {
+	if r.Stats.Runs == 0 {
+		return 0
+	}
+	return float64(r.GCs) / float64(r.Stats.Runs)
+}
+
+// GCPausePerRun returns the stop-the-world GC pause per measured run, on
+// average: time the implementation's goroutines could not run at all.
+func (r Result) GCPausePerRun() time.Duration {
+	if r.Stats.Runs == 0 {
+		return 0
+	}
+	return r.GCPause / time.Duration(r.Stats.Runs)
+}
+
+// DNFLabel describes a result that did not finish, e.g. "DNF (>10s)".
+func (r Result) DNFLabel() string {
+	return fmt.Sprintf("DNF (>%s)", r.Limit)
+}
+
+func ms(d time.Duration) float64 {
+	return d.Seconds() * 1000
+}
+
+// Compare runs each implementation once, in order, and returns one Result
+// per implementation in the same order.
+func Compare(impls ...Implementation) []Result {
+	return CompareWith(Options{Runs: 1}, impls...)
+}
+
+// CompareWith runs each implementation opts.Warmup times without timing
+// it, then opts.Runs timed times, and returns one Result per
+// implementation in the same order. Duration is the median run, which is
+// far less sensitive to scheduler and GC noise than a single sample.
+func CompareWith(opts Options, impls ...Implementation) []Result {
+	results, _ := CompareContext(context.Background(), opts, impls...)
+	return results
+}
+
+// CompareContext is like CompareWith but stops as soon as ctx is done,
+// returning the results of the implementations that completed every run
+// together with ctx.Err(). Implementations that run out of opts.Limit
+// don't stop the comparison; their results are marked DNF.
+func CompareContext(ctx context.Context, opts Options, impls ...Implementation) ([]Result, error) {
+	prof := profilerFrom(ctx)
+	prof.next()
+
+	results := make([]Result, 0, len(impls))
+	for _, impl := range impls {
+		ctx, span := trace.Start(ctx, impl.Name, trace.String("complexity", impl.Complexity))
+		r, err := measure(ctx, opts, prof, impl)
+		span.SetAttrs(trace.Int("median_ns", int(r.Duration)), trace.Bool("dnf", r.DNF))
+		span.End(err)
+		if err != nil {
+			return results, err
+		}
+		r.Target = opts.Target
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// measure times one implementation for CompareContext.
+func measure(ctx context.Context, opts Options, prof *Profiler, impl Implementation) (Result, error) {
+	runs := max(opts.Runs, 1)
+	concurrency := max(opts.Concurrency, 1)
+	limited, cancel := withLimit(ctx, opts.Limit)
+	defer cancel()
+
+	for j := range opts.Warmup {
+		start := time.Now()
+		err := impl.run(limited, concurrency)
+		trace.Record(ctx, "warmup", start, time.Now(), trace.Int("run", j+1))
+		if err != nil {
+			if timedOut(ctx, limited) {
+				return dnf(impl.Name, impl.Complexity, opts.Limit, nil), nil
+			}
+			return Result{}, err
+		}
+	}
+
+	samples := make([]time.Duration, runs)
+	starts := make([]time.Time, runs) // for the runs' spans, recorded after the timing
+	goroutines := make([]GoroutineCount, runs)
+	stopProfile, err := prof.start(impl.Name)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// Collect the garbage left by warm-up and by earlier implementations
+	// first, so the collections counted below are the ones this
+	// implementation's own allocations caused. ReadMemStats stops the
+	// world, so it brackets the whole batch of runs rather than each one
+	// and stays out of the timed sections.
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	for j := range samples {
+		goroutines[j].Before = runtime.NumGoroutine()
+		starts[j] = time.Now()
+		err := impl.run(limited, concurrency)
+		samples[j] = time.Since(starts[j])
+		goroutines[j].After = runtime.NumGoroutine()
+		if err != nil {
+			traceRuns(ctx, starts[:j+1], samples[:j+1])
+			if err := stopProfile(); err != nil {
+				return Result{}, err
+			}
+			if timedOut(ctx, limited) {
+				return dnf(impl.Name, impl.Complexity, opts.Limit, samples[:j]), nil
+			}
+			return Result{}, err
+		}
+	}
+
+	runtime.ReadMemStats(&after)
+	if err := stopProfile(); err != nil {
+		return Result{}, err
+	}
+	traceRuns(ctx, starts, samples)
+	leaked := LeakedGoroutines(goroutines[0].Before)
+
+	stats := Summarize(samples)
+	return Result{
+		Name:       impl.Name,
+		Complexity: impl.Complexity,
+		Duration:   stats.Median,
+		Stats:      stats,
+		Bytes:      (after.TotalAlloc - before.TotalAlloc) / uint64(runs),
+		Allocs:     (after.Mallocs - before.Mallocs) / uint64(runs),
+		Samples:    samples,
+		GCs:        after.NumGC - before.NumGC,
+		GCPause:    time.Duration(after.PauseTotalNs - before.PauseTotalNs),
+		Goroutines: goroutines,
+		Leaked:     leaked,
+
+		Concurrency: concurrency,
+	}, nil
+}
+
+// settleTime is how long LeakedGoroutines waits for goroutines to exit.
+const settleTime = 100 * time.Millisecond
+
+// LeakedGoroutines returns how many more goroutines are alive than the
+// given count from earlier. Goroutines told to stop may take a moment to
+// notice, so it first waits up to settleTime for the count to fall back.
+func LeakedGoroutines(before int) int {
+	deadline := time.Now().Add(settleTime)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	return max(runtime.NumGoroutine()-before, 0)
+}
+
+// traceRuns records a span for each measured run, started at starts[j]
+// and lasting samples[j].
+func traceRuns(ctx context.Context, starts []time.Time, samples []time.Duration) {
+	for j, start := range starts {
+		trace.Record(ctx, "run", start, start.Add(samples[j]), trace.Int("run", j+1))
+	}
+}
+
+// withLimit returns a context that is also done after limit, if limit is
+// positive.
+func withLimit(ctx context.Context, limit time.Duration) (context.Context, context.CancelFunc) {
+	if limit <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, limit)
+}
+
+// timedOut reports whether limited, derived from ctx by withLimit, ran
+// out of time while ctx itself is still live.
+func timedOut(ctx, limited context.Context) bool {
+	return ctx.Err() == nil && limited.Err() != nil
+}
+
+// dnf returns the result of an implementation stopped at limit, after
+// the given measured runs finished.
+func dnf(name, complexity string, limit time.Duration, samples []time.Duration) Result {
+	return Result{
+		Name:       name,
+		Complexity: complexity,
+		Duration:   limit,
+		Stats:      Summarize(samples),
+		Samples:    samples,
+		DNF:        true,
+		Limit:      limit,
+	}
+}
+
+// Speedup reports how many times faster fast is than slow. If slow did
+// not finish, the speedup is at least the value returned.
+func Speedup(slow, fast Result) float64 {
+	if fast.Duration <= 0 {
+		return 0
+	}
+	return float64(slow.Duration) / float64(fast.Duration)
+}
+
+// Print writes the "Performance comparison" block used by every example,
+// with names padded so the timings line up, followed by the bytes and
+// number of allocations each implementation made. Repeated results also show min/mean/stddev so
+// readers can judge whether a difference is real, and an implementation
+// that leaked goroutines says how many. On a writer marked by
+// style.Color, the fastest timing is green and the slowest red - or,
+// with a Target, the timings that reach it green and the rest red.
+func Print(w io.Writer, results []Result) {
+	nameWidth, timeWidth, complexityWidth, allocsWidth := 0, 0, 0, 0
+	for _, r := range results {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(r.Name))
+		timeWidth = max(timeWidth, len(fmt.Sprintf("%.4f", r.Milliseconds())))
+		complexityWidth = max(complexityWidth, utf8.RuneCountInString(complexityLabel(r)))
+		allocsWidth = max(allocsWidth, len(strconv.FormatUint(r.Allocs, 10)))
+	}
+
+	fmt.Fprintln(w, "\nPerformance comparison:")
+	for i, r := range results {
+		label := fmt.Sprintf("%s:", r.Name)
+		if r.DNF {
+			head := fmt.Sprintf("%-*s %*s", nameWidth+1, label, timeWidth+2, r.DNFLabel())
+			fmt.Fprintf(w, "  %s %s\n", style.Red.Paint(w, head), complexityLabel(r))
+			continue
+		}
+		head := fmt.Sprintf("%-*s %*.4fms", nameWidth+1, label, timeWidth, r.Milliseconds())
+		pad := complexityWidth - utf8.RuneCountInString(complexityLabel(r))
+		line := fmt.Sprintf("  %s %s%s  %10s in %*d allocs",
+			rankStyle(results, i).Paint(w, head), complexityLabel(r), strings.Repeat(" ", pad),
+			FormatBytes(r.Bytes), allocsWidth, r.Allocs)
+		if r.Stats.Runs > 1 {
+			line += fmt.Sprintf("  [median of %d; min %.4fms, mean %.4fms ± %.4fms]",
+				r.Stats.Runs, ms(r.Stats.Min), ms(r.Stats.Mean), ms(r.Stats.StdDev))
+		}
+		if r.Leaked > 0 {
+			line += style.Red.Paint(w, fmt.Sprintf("  %d goroutines left running", r.Leaked))
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+// rankStyle colors the fastest of several results green and the slowest
+// red. Results that did not finish are slower than any that did, so
+// they take the red instead. Against a target, where slower is better,
+// results that reach it are green and the others red.
+func rankStyle(results []Result, i int) style.Style {
+	if results[i].Target > 0 {
+		if results[i].OnTarget() {
+			return style.Green
+		}
+		return style.Red
+	}
+	fastest, slowest, dnf := -1, -1, false
+	for j, r := range results {
+		if r.DNF {
+			dnf = true
+			continue
+		}
+		if fastest < 0 || r.Duration < results[fastest].Duration {
+			fastest = j
+		}
+		if slowest < 0 || r.Duration > results[slowest].Duration {
+			slowest = j
+		}
+	}
+	switch {
+	case results[i].DNF:
+		return style.Red
+	case len(results) < 2:
+		return ""
+	case i == fastest:
+		return style.Green
+	case i == slowest && !dnf:
+		return style.Red
+	}
+	return ""
+}
+
+// PrintRuns writes the detail behind each line of Print: every measured
+// run's timing in order, so warm-up effects and outliers are visible, how
+// much garbage collection happened while they ran, and the goroutines
+// alive before and after each.
+func PrintRuns(w io.Writer, results []Result) {
+	fmt.Fprintln(w, "\nIndividual runs:")
+	for _, r := range results {
+		if r.DNF {
+			fmt.Fprintf(w, "  %s: %s after %d finished run(s)\n", r.Name, r.DNFLabel(), len(r.Samples))
+			continue
+		}
+		timings := make([]string, len(r.Samples))
+		for i, d := range r.Samples {
+			timings[i] = fmt.Sprintf("%.4f", ms(d))
+		}
+		fmt.Fprintf(w, "  %s: %s ms\n", r.Name, strings.Join(timings, ", "))
+		fmt.Fprintf(w, "    %d GC cycles, %.4fms paused\n", r.GCs, ms(r.GCPause))
+		counts := make([]string, len(r.Goroutines))
+		for i, g := range r.Goroutines {
+			counts[i] = fmt.Sprintf("%d→%d", g.Before, g.After)
+		}
+		fmt.Fprintf(w, "    goroutines before→after each run: %s; %d left running\n", strings.Join(counts, ", "), r.Leaked)
+	}
+}
+
+// FormatBytes renders a byte count with a binary unit suffix, e.g.
+// "9.5 MiB".
+func FormatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := uint64(unit), 0
+	for q := b / unit; q >= unit; q /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
+
+func complexityLabel(r Result) string {
+	if r.Complexity == "" {
+		return ""
+	}
+	return "(" + r.Complexity + ")"
+}
@@ -0,0 +1,176 @@
+package bench
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+)
+
+// Curve is a candidate complexity class, e.g. O(n log n).
+type Curve struct {
+	Name string
+	F    func(n float64) float64
+}
+
+// Curves are the complexity classes FitComplexity considers by default,
+// from slowest-growing to fastest-growing.
+var Curves = []Curve{
+	{"O(n)", func(n float64) float64 { return n }},
+	{"O(n log log n)", func(n float64) float64 { return n * math.Log(math.Log(n)) }},
+	{"O(n log n)", func(n float64) float64 { return n * math.Log(n) }},
+	{"O(n√n)", func(n float64) float64 { return n * math.Sqrt(n) }},
+	{"O(n²)", func(n float64) float64 { return n * n }},
+	{"O(n³)", func(n float64) float64 { return n * n * n }},
+}
+
+// Fit is how well one Curve explains a set of timings.
+type Fit struct {
+	Curve Curve
+	Scale float64 // Best constant c in t ≈ c·f(n), in nanoseconds
+	Error float64 // RMS error in log space: 0.1 ≈ typically 10% off
+}
+
+// FitComplexity fits t ≈ c·f(n) for each candidate curve (Curves if none
+// are given) and returns the fits sorted from best to worst.
+//
+// The fit is done in log space, log t = log c + log f(n), so every point
+// counts by its relative error - otherwise the largest n would dominate.
+// Sizes below 3 are ignored since log log n is undefined there.
+func FitComplexity(sizes []int, durations []time.Duration, curves ...Curve) []Fit {
+	if len(curves) == 0 {
+		curves = Curves
+	}
+
+	var ns, logT []float64
+	for i, n := range sizes {
+		if n < 3 || durations[i] <= 0 {
+			continue
+		}
+		ns = append(ns, float64(n))
+		logT = append(logT, math.Log(float64(durations[i])))
+	}
+
+	fits := make([]Fit, 0, len(curves))
+	for _, c := range curves {
+		if len(ns) == 0 {
+			fits = append(fits, Fit{Curve: c, Error: math.Inf(1)})
+			continue
+		}
+		// Least squares for log c is just the mean residual.
+		residuals := make([]float64, len(ns))
+		var logC float64
+		for i, n := range ns {
+			residuals[i] = logT[i] - math.Log(c.F(n))
+			logC += residuals[i]
+		}
+		logC /= float64(len(ns))
+
+		var sq float64
+		for _, r := range residuals {
+			sq += (r - logC) * (r - logC)
+		}
+		fits = append(fits, Fit{
+			Curve: c,
+			Scale: math.Exp(logC),
+			Error: math.Sqrt(sq / float64(len(ns))),
+		})
+	}
+
+	slices.SortStableFunc(fits, func(a, b Fit) int {
+		switch {
+		case a.Error < b.Error:
+			return -1
+		case a.Error > b.Error:
+			return 1
+		}
+		return 0
+	})
+	return fits
+}
+
+// Series is one implementation's results across a sweep of input sizes.
+type Series struct {
+	Name       string
+	Complexity string
+	Sizes      []int
+	Results    []Result
+}
+
+// Fit fits the series' median timings to the candidate curves; see
+// FitComplexity. Results that did not finish are left out.
+func (s Series) Fit(curves ...Curve) []Fit {
+	var sizes []int
+	var durations []time.Duration
+	for i, r := range s.Results {
+		if !r.DNF {
+			sizes = append(sizes, s.Sizes[i])
+			durations = append(durations, r.Duration)
+		}
+	}
+	return FitComplexity(sizes, durations, curves...)
+}
+
+// Sweep compares the implementations returned by impls at every size and
+// regroups the results into one Series per implementation name, in order
+// of first appearance. impls may leave an implementation out at sizes
+// where it would be too slow; its Series simply has fewer points. An
+// implementation that runs out of opts.Limit at one size is recorded as
+// DNF there and not run at the sizes after it.
+func Sweep(opts Options, sizes []int, impls func(n int) []Implementation) []Series {
+	series, _ := SweepContext(context.Background(), opts, sizes, impls)
+	return series
+}
+
+// SweepContext is like Sweep but stops once ctx is done, returning the
+// series measured so far together with ctx.Err().
+func SweepContext(ctx context.Context, opts Options, sizes []int, impls func(n int) []Implementation) ([]Series, error) {
+	var series []Series
+	index := map[string]int{}
+	stopped := map[string]bool{}
+	for _, n := range sizes {
+		var active []Implementation
+		for _, impl := range impls(n) {
+			if !stopped[impl.Name] {
+				active = append(active, impl)
+			}
+		}
+		results, err := CompareContext(ctx, opts, active...)
+		for _, r := range results {
+			stopped[r.Name] = stopped[r.Name] || r.DNF
+			i, ok := index[r.Name]
+			if !ok {
+				i = len(series)
+				index[r.Name] = i
+				series = append(series, Series{Name: r.Name, Complexity: r.Complexity})
+			}
+			series[i].Sizes = append(series[i].Sizes, n)
+			series[i].Results = append(series[i].Results, r)
+		}
+		if err != nil {
+			return series, err
+		}
+	}
+	return series, nil
+}
+
+// GeometricSizes returns perDecade roughly evenly spaced sizes per power
+// of ten from start up to and including end, e.g. 1000, 2154, 4641,
+// 10000, ... for perDecade = 3.
+func GeometricSizes(start, end, perDecade int) []int {
+	if start < 1 || end < start || perDecade < 1 {
+		return nil
+	}
+	var sizes []int
+	ratio := math.Pow(10, 1/float64(perDecade))
+	for f := float64(start); ; f *= ratio {
+		n := int(math.Round(f))
+		if n > end {
+			break
+		}
+		if len(sizes) == 0 || n != sizes[len(sizes)-1] {
+			sizes = append(sizes, n)
+		}
+	}
+	return sizes
+}
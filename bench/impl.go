@@ -0,0 +1,127 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iportilla/ai-coding/trace"
+)
+
+// Impl is one implementation of a function from In to Out. Where an
+// Implementation times an opaque closure, an Impl exposes its result, so
+// CompareImpls can check every implementation's answer before timing it.
+// The same harness then serves examples returning slices, maps, structs
+// or big numbers alike; only the equality function differs.
+//
+//	impls := []bench.Impl[uint64, []uint64]{
+//		{Name: "Human coding", Complexity: "O(√n / log n)", Func: humanFactor},
+//		{Name: "Expert coding", Complexity: "O(n^¼)", Func: expertFactor},
+//	}
+//	results, err := bench.CompareImpls(ctx, opts, n, want, bench.DiffSlices, impls...)
+type Impl[In, Out any] struct {
+	Name       string       // Label shown in the report, e.g. "Vibe coding"
+	Complexity string       // Big-O annotation shown next to the timing
+	Func       func(In) Out // The function being compared
+
+	// FuncContext, if set, is used instead of Func. Like
+	// Implementation.RunContext it should return ctx.Err() soon after
+	// ctx is done.
+	FuncContext func(ctx context.Context, in In) (Out, error)
+}
+
+// call applies impl to in, preferring FuncContext when it is set.
+func (impl Impl[In, Out]) call(ctx context.Context, in In) (Out, error) {
+	if impl.FuncContext != nil {
+		return impl.FuncContext(ctx, in)
+	}
+	out := impl.Func(in)
+	return out, ctx.Err()
+}
+
+// Implementation returns an Implementation that applies impl to in on
+// every run and discards the result.
+func (impl Impl[In, Out]) Implementation(in In) Implementation {
+	return Implementation{
+		Name:       impl.Name,
+		Complexity: impl.Complexity,
+		RunContext: func(ctx context.Context) error {
+			_, err := impl.call(ctx, in)
+			return err
+		},
+	}
+}
+
+// Equal is an equality function for comparable outputs such as numbers,
+// strings and booleans, for use with Check and CompareImpls.
+func Equal[T comparable](got, want T) error {
+	if got != want {
+		return fmt.Errorf("got %v, want %v", got, want)
+	}
+	return nil
+}
+
+// Check applies every impl to in and compares its output with want using
+// equal, which returns nil for equal outputs and otherwise describes the
+// difference. The first mismatch is returned as a "verification failed"
+// error naming the implementation; if ctx is done, Check returns
+// ctx.Err() instead.
+func Check[In, Out any](ctx context.Context, in In, want Out, equal func(got, want Out) error, impls ...Impl[In, Out]) (err error) {
+	ctx, span := trace.Start(ctx, "check")
+	defer func() { span.End(err) }()
+	for _, impl := range impls {
+		got, err := impl.call(ctx, in)
+		if err != nil {
+			return err
+		}
+		if err := equal(got, want); err != nil {
+			return fmt.Errorf("verification failed: %s: %w", impl.Name, err)
+		}
+	}
+	return nil
+}
+
+// CompareImpls checks every impl against want like Check, then times
+// them on in like CompareContext. Nothing is timed unless every
+// implementation gives the right answer.
+//
+// With opts.Limit, the check is limited too: an implementation that
+// can't give its answer in time is reported as DNF without being timed,
+// and the limit starts afresh for the others' timed runs.
+func CompareImpls[In, Out any](ctx context.Context, opts Options, in In, want Out, equal func(got, want Out) error, impls ...Impl[In, Out]) ([]Result, error) {
+	finished := make([]bool, len(impls))
+	var timed []Implementation
+	for i, impl := range impls {
+		limited, cancel := withLimit(ctx, opts.Limit)
+		got, err := impl.call(limited, in)
+		stopped := timedOut(ctx, limited)
+		cancel()
+		if stopped {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := equal(got, want); err != nil {
+			return nil, fmt.Errorf("verification failed: %s: %w", impl.Name, err)
+		}
+		finished[i] = true
+		timed = append(timed, impl.Implementation(in))
+	}
+
+	measured, err := CompareContext(ctx, opts, timed...)
+	results := make([]Result, 0, len(impls))
+	for i, impl := range impls {
+		switch {
+		case !finished[i]:
+			r := dnf(impl.Name, impl.Complexity, opts.Limit, nil)
+			r.Target = opts.Target
+			results = append(results, r)
+		case len(measured) > 0:
+			results = append(results, measured[0])
+			measured = measured[1:]
+		default:
+			return results, err // ctx was done before impl was timed
+		}
+	}
+	return results, err
+}
@@ -0,0 +1,308 @@
+package bench
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Profiler writes a pprof profile of each implementation's measured runs,
+// one file per algorithm, so its CPU time or allocations can be explored
+// on their own:
+//
+//	go tool pprof -http=: cpu-03-sorting-1-Vibe_coding.prof
+//
+// Attach one to a context with WithProfiler; CompareContext then profiles
+// every comparison run with that context. Profiling slows the runs it
+// watches, so timings from a profiled run should not be compared with
+// others.
+type Profiler struct {
+	CPUProfile string // Name pattern for CPU profiles, e.g. "cpu.prof"; empty for none
+	MemProfile string // Name pattern for allocation profiles, e.g. "mem.prof"; empty for none
+	Label      string // Added to every file name, e.g. the example name
+
+	Written []string // Every file written so far, in order
+
+	comparison int // Comparisons profiled so far, numbering the files
+}
+
+type profilerKey struct{}
+
+// WithProfiler returns a copy of ctx that makes CompareContext profile
+// its runs with p.
+func WithProfiler(ctx context.Context, p *Profiler) context.Context {
+	return context.WithValue(ctx, profilerKey{}, p)
+}
+
+// profilerFrom returns ctx's Profiler, or nil if it has none.
+func profilerFrom(ctx context.Context) *Profiler {
+	p, _ := ctx.Value(profilerKey{}).(*Profiler)
+	return p
+}
+
+// path returns the file for one algorithm's profile: the pattern with
+// the label, the comparison number and the algorithm inserted before its
+// extension, e.g. "cpu-03-sorting-2-Vibe_coding.prof".
+func (p *Profiler) path(pattern, algorithm string) string {
+	ext := filepath.Ext(pattern)
+	name := strings.TrimSuffix(pattern, ext)
+	for _, part := range []string{p.Label, strconv.Itoa(p.comparison), algorithm} {
+		if part != "" {
+			name += "-" + strings.Join(strings.Fields(part), "_")
+		}
+	}
+	return name + ext
+}
+
+// next starts a new comparison. A nil Profiler does nothing.
+func (p *Profiler) next() {
+	if p != nil {
+		p.comparison++
+	}
+}
+
+// start begins profiling algorithm and returns the function that stops
+// it and writes its files. A nil Profiler profiles nothing.
+func (p *Profiler) start(algorithm string) (stop func() error, err error) {
+	if p == nil || p.CPUProfile == "" && p.MemProfile == "" {
+		return func() error { return nil }, nil
+	}
+
+	var cpu *os.File
+	if p.CPUProfile != "" {
+		path := p.path(p.CPUProfile, algorithm)
+		if cpu, err = os.Create(path); err != nil {
+			return nil, err
+		}
+		if err := pprof.StartCPUProfile(cpu); err != nil {
+			cpu.Close()
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	var before map[[32]uintptr]allocCount
+	if p.MemProfile != "" {
+		before = allocsByStack()
+	}
+	started := time.Now()
+
+	return func() error {
+		if cpu != nil {
+			pprof.StopCPUProfile()
+			if err := cpu.Close(); err != nil {
+				return err
+			}
+			p.Written = append(p.Written, cpu.Name())
+		}
+		if before != nil {
+			path := p.path(p.MemProfile, algorithm)
+			if err := writeAllocProfile(path, before, allocsByStack(), started); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			p.Written = append(p.Written, path)
+		}
+		return nil
+	}, nil
+}
+
+// allocCount is the allocations recorded for one call stack.
+type allocCount struct {
+	objects, bytes int64
+}
+
+// allocsByStack returns the runtime's allocation profile so far, keyed by
+// call stack. The runtime only ever adds to it, so the allocations of one
+// algorithm are the difference between two of these.
+func allocsByStack() map[[32]uintptr]allocCount {
+	// The profile is brought up to date by garbage collections, and only
+	// includes allocations made before the one finished before the last.
+	runtime.GC()
+	runtime.GC()
+	var records []runtime.MemProfileRecord
+	n, ok := runtime.MemProfile(nil, true)
+	for !ok {
+		records = make([]runtime.MemProfileRecord, n+50)
+		n, ok = runtime.MemProfile(records, true)
+	}
+	counts := make(map[[32]uintptr]allocCount, n)
+	for _, r := range records[:n] {
+		counts[r.Stack0] = allocCount{r.AllocObjects, r.AllocBytes}
+	}
+	return counts
+}
+
+// writeAllocProfile writes the allocations made between before and after
+// to path as a gzipped pprof profile, the format `go tool pprof` reads.
+// runtime/pprof can only write the allocations since the program
+// started, hence the hand-written encoding.
+func writeAllocProfile(path string, before, after map[[32]uintptr]allocCount, started time.Time) error {
+	var p protoBuffer
+	strs := map[string]int64{}
+	str := func(s string) int64 {
+		if i, ok := strs[s]; ok {
+			return i
+		}
+		strs[s] = int64(len(strs))
+		return strs[s]
+	}
+	str("")
+	valueType := func(typ, unit string) []byte {
+		var m protoBuffer
+		m.int64(1, str(typ))
+		m.int64(2, str(unit))
+		return m.data
+	}
+	p.bytes(1, valueType("alloc_objects", "count")) // sample_type
+	p.bytes(1, valueType("alloc_space", "bytes"))
+
+	// Locations and functions are numbered from 1 as they are first seen.
+	type frameKey struct {
+		pc       uintptr
+		function string
+		line     int
+	}
+	locations := map[frameKey]uint64{}
+	functions := map[string]uint64{}
+	var locs, funcs protoBuffer
+	rate := int64(runtime.MemProfileRate)
+	for stack, a := range after {
+		objects, bytes := scaleHeapSample(a.objects-before[stack].objects, a.bytes-before[stack].bytes, rate)
+		if objects <= 0 {
+			continue
+		}
+		var ids []uint64
+		n := 0
+		for n < len(stack) && stack[n] != 0 {
+			n++
+		}
+		frames := runtime.CallersFrames(stack[:n])
+		for hideRuntime := true; ; {
+			f, more := frames.Next()
+			// Like runtime/pprof, start at the code that asked for the
+			// memory rather than inside the allocator.
+			if hideRuntime = hideRuntime && more && strings.HasPrefix(f.Function, "runtime."); hideRuntime {
+				continue
+			}
+			k := frameKey{f.PC, f.Function, f.Line}
+			id, ok := locations[k]
+			if !ok {
+				fn, ok := functions[f.Function]
+				if !ok {
+					fn = uint64(len(functions) + 1)
+					functions[f.Function] = fn
+					var m protoBuffer
+					m.uint64(1, fn)
+					m.int64(2, str(f.Function))
+					m.int64(3, str(f.Function))
+					m.int64(4, str(f.File))
+					funcs.bytes(5, m.data) // function
+				}
+				id = uint64(len(locations) + 1)
+				locations[k] = id
+				var line, m protoBuffer
+				line.uint64(1, fn)
+				line.int64(2, int64(f.Line))
+				m.uint64(1, id)
+				m.uint64(3, uint64(f.PC))
+				m.bytes(4, line.data)
+				locs.bytes(4, m.data) // location
+			}
+			ids = append(ids, id)
+			if !more {
+				break
+			}
+		}
+		var sample protoBuffer
+		sample.packed(1, ids)
+		sample.packed(2, []uint64{uint64(objects), uint64(bytes)})
+		p.bytes(2, sample.data)
+	}
+	p.data = append(p.data, locs.data...)
+	p.data = append(p.data, funcs.data...)
+
+	p.int64(9, started.UnixNano())           // time_nanos
+	p.int64(10, int64(time.Since(started)))  // duration_nanos
+	p.bytes(11, valueType("space", "bytes")) // period_type
+	p.int64(12, rate)                        // period
+	p.int64(14, str("alloc_space"))          // default_sample_type
+	table := make([]string, len(strs))
+	for s, i := range strs {
+		table[i] = s
+	}
+	for _, s := range table {
+		p.bytes(6, []byte(s)) // string_table
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(f)
+	zw.Write(p.data)
+	if err := zw.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// scaleHeapSample estimates the true allocation counts from the sampled
+// ones, as runtime/pprof does: an allocation of s bytes is recorded with
+// probability 1 - e^(-s/rate).
+func scaleHeapSample(objects, bytes, rate int64) (int64, int64) {
+	if objects <= 0 || bytes <= 0 || rate <= 1 {
+		return objects, bytes
+	}
+	avg := float64(bytes) / float64(objects)
+	scale := 1 / (1 - math.Exp(-avg/float64(rate)))
+	return int64(float64(objects) * scale), int64(float64(bytes) * scale)
+}
+
+// protoBuffer encodes protocol buffer fields, just enough of them for a
+// pprof profile.
+type protoBuffer struct {
+	data []byte
+}
+
+func (b *protoBuffer) varint(x uint64) {
+	for x >= 0x80 {
+		b.data = append(b.data, byte(x)|0x80)
+		x >>= 7
+	}
+	b.data = append(b.data, byte(x))
+}
+
+// uint64 writes a varint field; zero, the default, is left out.
+func (b *protoBuffer) uint64(field int, x uint64) {
+	if x != 0 {
+		b.varint(uint64(field) << 3)
+		b.varint(x)
+	}
+}
+
+func (b *protoBuffer) int64(field int, x int64) {
+	b.uint64(field, uint64(x))
+}
+
+// bytes writes a length-delimited field: a string or a nested message.
+func (b *protoBuffer) bytes(field int, data []byte) {
+	b.varint(uint64(field)<<3 | 2)
+	b.varint(uint64(len(data)))
+	b.data = append(b.data, data...)
+}
+
+// packed writes a repeated varint field in packed form.
+func (b *protoBuffer) packed(field int, xs []uint64) {
+	var m protoBuffer
+	for _, x := range xs {
+		m.varint(x)
+	}
+	b.bytes(field, m.data)
+}
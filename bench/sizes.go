@@ -0,0 +1,73 @@
+package bench
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Sizes is a list of input sizes that can be used as a flag.Value. It
+// accepts comma-separated values in plain, underscore-grouped or
+// scientific notation:
+//
+//	-n 10,100,1000
+//	-n 1e6,1e7
+//	-n 1_000_000
+type Sizes []int
+
+// String implements flag.Value.
+func (s *Sizes) String() string {
+	parts := make([]string, len(*s))
+	for i, n := range *s {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set implements flag.Value. It replaces the list rather than appending,
+// so a flag's default is discarded once the user sets it.
+func (s *Sizes) Set(value string) error {
+	var sizes Sizes
+	for _, part := range strings.Split(value, ",") {
+		n, err := ParseSize(part)
+		if err != nil {
+			return err
+		}
+		sizes = append(sizes, n)
+	}
+	*s = sizes
+	return nil
+}
+
+// ParseSize parses a single input size such as "1000", "1_000" or "1e6",
+// in base 10, so "010" is ten. Sizes can't be negative.
+func ParseSize(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	digits := strings.ReplaceAll(s, "_", "")
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		f, err := strconv.ParseFloat(digits, 64)
+		if err != nil || f != math.Trunc(f) || math.Abs(f) >= math.MaxInt64 {
+			return 0, fmt.Errorf("invalid size %q: want an integer like 1000, 1_000 or 1e3", s)
+		}
+		n = int64(f)
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("invalid size %q: sizes can't be negative", s)
+	}
+	return int(n), nil
+}
+
+// PowersOfTen returns 10, 100, ... up to and including limit. It is the
+// usual way to sweep a demo across orders of magnitude.
+func PowersOfTen(limit int) []int {
+	var sizes []int
+	for n := 10; n <= limit; n *= 10 {
+		sizes = append(sizes, n)
+		if n > math.MaxInt/10 {
+			break
+		}
+	}
+	return sizes
+}
@@ -0,0 +1,72 @@
+package bench
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Stats summarises the timed runs of one implementation.
+type Stats struct {
+	Runs   int
+	Min    time.Duration
+	Median time.Duration
+	Mean   time.Duration
+	StdDev time.Duration
+}
+
+// Summarize computes Stats over a set of samples. It returns the zero
+// Stats when samples is empty.
+func Summarize(samples []time.Duration) Stats {
+	if len(samples) == 0 {
+		return Stats{}
+	}
+
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+
+	var sum float64
+	for _, d := range sorted {
+		sum += float64(d)
+	}
+	mean := sum / float64(len(sorted))
+
+	// Sample standard deviation (n-1) - we are estimating the spread of
+	// the true timing distribution from a handful of runs.
+	var sqDiff float64
+	for _, d := range sorted {
+		diff := float64(d) - mean
+		sqDiff += diff * diff
+	}
+	var stddev float64
+	if len(sorted) > 1 {
+		stddev = math.Sqrt(sqDiff / float64(len(sorted)-1))
+	}
+
+	mid := len(sorted) / 2
+	median := sorted[mid]
+	if len(sorted)%2 == 0 {
+		median = (sorted[mid-1] + sorted[mid]) / 2
+	}
+
+	return Stats{
+		Runs:   len(sorted),
+		Min:    sorted[0],
+		Median: median,
+		Mean:   time.Duration(mean),
+		StdDev: time.Duration(stddev),
+	}
+}
+
+// Percentile returns the p-th percentile of samples, 0 ≤ p ≤ 100, by the
+// nearest-rank method: the smallest sample at least p percent of the
+// samples are no greater than. It returns 0 when samples is empty.
+func Percentile(samples []time.Duration, p float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[min(max(rank, 1), len(sorted))-1]
+}
@@ -0,0 +1,23 @@
+package bench
+
+import "fmt"
+
+// DiffSlices returns an error describing the first difference between
+// got and want, or nil if they are equal. Examples use it to cross-check
+// every implementation against a reference before timing anything - a
+// fast but wrong answer must never make it into a results table.
+func DiffSlices[T comparable](got, want []T) error {
+	i := 0
+	for i < len(got) && i < len(want) && got[i] == want[i] {
+		i++
+	}
+	switch {
+	case i < len(got) && i < len(want):
+		return fmt.Errorf("element %d is %v, want %v", i, got[i], want[i])
+	case len(got) > len(want):
+		return fmt.Errorf("got %d elements, want %d; first extra is %v", len(got), len(want), got[i])
+	case len(got) < len(want):
+		return fmt.Errorf("got %d elements, want %d; first missing is %v", len(got), len(want), want[i])
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/report"
+	"github.com/iportilla/ai-coding/trace"
+)
+
+// exampleInfo describes one example in GET /examples.
+type exampleInfo struct {
+	Name        string        `json:"name"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	Category    string        `json:"category"`
+	Difficulty  string        `json:"difficulty"`
+	Tiers       []report.Tier `json:"tiers"`
+	DefaultN    int           `json:"default_n,omitempty"` // Set if the dashboard can time it at any n
+}
+
+// runRequest is the body of POST /run. Params are the example's own
+// flags without the dash, e.g. {"n": "1e4", "runs": 3, "q": true}.
+type runRequest struct {
+	Example string         `json:"example"`
+	Params  map[string]any `json:"params"`
+}
+
+// runResponse is the reply to POST /run: one result per algorithm and
+// input measured, the example's text output, and the error that stopped
+// it, if any.
+type runResponse struct {
+	Example    string          `json:"example"`
+	Args       []string        `json:"args"`
+	DurationMs float64         `json:"duration_ms"`
+	Results    []report.CSVRow `json:"results"`
+	Output     string          `json:"output"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// fileParams are example flags that write files, which a client must not
+// be able to make the server do.
+var fileParams = []string{"csv", "report", "o"}
+
+// api serves the JSON API for scripts, notebooks and CI jobs: GET
+// /examples lists the registry and POST /run runs one example to
+// completion and returns its results. Runs take turns, so their timings
+// don't disturb each other.
+type api struct {
+	mu      sync.Mutex
+	metrics *metrics
+	tracer  *trace.Tracer // nil unless runs are traced
+}
+
+// examples lists every registered example.
+func (a *api) examples(w http.ResponseWriter, r *http.Request) {
+	list := []exampleInfo{}
+	for _, ex := range examples.All() {
+		info := exampleInfo{
+			Name:        ex.Name,
+			Title:       ex.Title,
+			Description: ex.Description,
+			Category:    ex.Category,
+			Difficulty:  ex.Difficulty.String(),
+			Tiers:       ex.Lesson.Tiers,
+		}
+		if ex.Impls != nil {
+			info.DefaultN = ex.DefaultN
+		}
+		list = append(list, info)
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// run runs one example with the requested flags. Its results are
+// collected through the example's -csv flag and a temporary file, as
+// bench-all -save does.
+func (a *api) run(w http.ResponseWriter, r *http.Request) {
+	var req runRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	dec.UseNumber() // so 1e6 reaches the flag as written
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request: "+err.Error())
+		return
+	}
+	ex, err := examples.Lookup(req.Example)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	args, err := req.args()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	f, err := os.CreateTemp("", "ai-coding-*.csv")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var out bytes.Buffer
+	a.metrics.run(ex.Name, "api")
+	started := time.Now()
+	ctx, span := trace.Start(trace.WithTracer(r.Context(), a.tracer), ex.Name,
+		trace.String("source", "api"), trace.String("args", strings.Join(args, " ")))
+	runErr := ex.Run(ctx, &out, append(args, "-csv", f.Name()))
+	span.End(runErr)
+	if err := flushTrace(a.tracer); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+	resp := runResponse{
+		Example:    ex.Name,
+		Args:       args,
+		DurationMs: float64(time.Since(started)) / 1e6,
+		Output:     out.String(),
+	}
+	if resp.Results, err = report.ReadCSV(f); err != nil {
+		writeError(w, http.StatusInternalServerError, "reading results: "+err.Error())
+		return
+	}
+	if resp.Results == nil {
+		resp.Results = []report.CSVRow{}
+	}
+	for _, row := range resp.Results {
+		a.metrics.observe(ex.Name, row.Algorithm, "api", row.Median)
+	}
+	status := http.StatusOK
+	if runErr != nil {
+		a.metrics.fail(ex.Name, "api")
+		resp.Error = runErr.Error()
+		status = http.StatusUnprocessableEntity
+	}
+	writeJSON(w, status, resp)
+}
+
+// args turns the request's params into command-line flags, in name
+// order so the same request always runs the same command.
+func (req runRequest) args() ([]string, error) {
+	var args []string
+	for _, name := range slices.Sorted(maps.Keys(req.Params)) {
+		flag := strings.TrimLeft(name, "-")
+		if !isFlagName(flag) {
+			return nil, fmt.Errorf("param %q isn't a flag name", name)
+		}
+		if slices.Contains(fileParams, flag) {
+			return nil, fmt.Errorf("param %q would write files on the server", flag)
+		}
+		args = append(args, fmt.Sprintf("-%s=%v", flag, req.Params[name]))
+	}
+	return args, nil
+}
+
+// isFlagName reports whether name is a plain flag name, such as runs or
+// dnf-after, and so can't carry a value of its own: "csv=x" would set
+// -csv while slipping past fileParams.
+func isFlagName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// writeJSON replies with v as indented JSON.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// writeError replies with {"error": msg}.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
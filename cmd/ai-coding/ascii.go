@@ -0,0 +1,97 @@
+package main
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+)
+
+// unicodeTerminal guesses whether the terminal shows emoji and box
+// drawing characters, or prints them as mojibake. The classic Windows
+// console can't, though Windows Terminal and VS Code can, and elsewhere a
+// locale that doesn't name UTF-8 - LANG=C in many CI containers and cron
+// jobs, say - means the bytes won't be decoded as UTF-8.
+func unicodeTerminal() bool {
+	if runtime.GOOS == "windows" {
+		return os.Getenv("WT_SESSION") != "" || os.Getenv("TERM_PROGRAM") == "vscode"
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := strings.ToLower(os.Getenv(name)); locale != "" {
+			return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+		}
+	}
+	return true
+}
+
+// asciiReplacer spells out the characters the examples print in plain
+// ASCII. Longer patterns come first, so "n√n" is matched before "√".
+// Widths change, so columns lined up around these characters can end up
+// a little ragged.
+var asciiReplacer = strings.NewReplacer(
+	// Complexities and maths
+	"n√n", "n^1.5", "√n", "sqrt(n)", "√p", "sqrt(p)", "√", "sqrt",
+	"^¼", "^(1/4)", "¼", "1/4", "²", "^2", "³", "^3", "ⁿ", "^n",
+	"φ", "phi", "π", "pi", "Σ", "sum", "×", "x", "÷", "/", "·", ".",
+	"±", "+/-", "≈", "~", "≤", "<=", "≥", ">=", "µ", "u",
+	"–", "-", "—", "--", "…", "...",
+	"→", "->", "←", "<-", "↑", "^", "↓", "v", "▸", ">",
+	// Markers
+	"✅", "+", "✔", "+", "❌", "-", "⚠", "!", "💡", "*", "⏭", ">>", "⏱", "(t)",
+	// The emoji presentation selector, and the second space that follows
+	// an emoji drawn two columns wide
+	"️ ", "", "️", "",
+	// Bars, plots and boxes
+	"█", "#", "▉", "#", "▊", "#", "▋", "#", "▌", "#", "▍", "|", "▎", "|", "▏", "|",
+	"●", "o", "▲", "^", "■", "#", "◆", "@", "★", "*", "✚", "+", "✱", "X",
+	"─", "-", "┄", ".", "═", "=", "│", "|", "┤", "+", "┬", "+", "└", "+",
+)
+
+// asciiWriter writes to w with every character outside ASCII replaced:
+// those in asciiReplacer by their spelled-out form, other symbols and
+// emoji by '*' and anything else by '?'.
+type asciiWriter struct {
+	w       io.Writer
+	partial []byte // The start of a character split across writes
+}
+
+func (a *asciiWriter) Write(p []byte) (int, error) {
+	buf := append(a.partial, p...)
+	end := len(buf)
+	for i := max(end-utf8.UTFMax+1, 0); i < end; i++ {
+		if utf8.RuneStart(buf[i]) && !utf8.FullRune(buf[i:]) {
+			end = i
+			break
+		}
+	}
+	a.partial = append([]byte(nil), buf[end:]...)
+	if _, err := io.WriteString(a.w, toASCII(string(buf[:end]))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// toASCII replaces the characters of s that aren't ASCII, as asciiWriter
+// does.
+func toASCII(s string) string {
+	ascii := true
+	for i := 0; i < len(s) && ascii; i++ {
+		ascii = s[i] < utf8.RuneSelf
+	}
+	if ascii {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r < utf8.RuneSelf:
+			return r
+		case r >= 0x2190 && r <= 0x2BFF || r >= 0x1F000:
+			return '*' // arrows, symbols, dingbats and emoji
+		}
+		return '?'
+	}, asciiReplacer.Replace(s))
+}
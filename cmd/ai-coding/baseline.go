@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/iportilla/ai-coding/report"
+)
+
+// takeFlags sets the flags in args that fs defines and returns the rest
+// in order, so bench-all can have flags of its own while passing every
+// other flag through to the examples. Flags may be written -name value,
+// -name=value, or with two dashes; boolean flags also as just -name.
+func takeFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		if !strings.HasPrefix(args[i], "-") || fs.Lookup(name) == nil {
+			rest = append(rest, args[i])
+			continue
+		}
+		if b, ok := fs.Lookup(name).Value.(interface{ IsBoolFlag() bool }); !hasValue && ok && b.IsBoolFlag() {
+			value, hasValue = "true", true
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				return nil, fmt.Errorf("flag needs an argument: -%s", name)
+			}
+			i++
+			value = args[i]
+		}
+		if err := fs.Set(name, value); err != nil {
+			return nil, fmt.Errorf("invalid value %q for flag -%s: %w", value, name, err)
+		}
+	}
+	return rest, nil
+}
+
+// hasFlag reports whether args sets the named flag.
+func hasFlag(args []string, name string) bool {
+	for _, a := range args {
+		if n, _, _ := strings.Cut(strings.TrimLeft(a, "-"), "="); strings.HasPrefix(a, "-") && n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// flagValue returns the value args last sets the named flag to, and
+// whether it sets it at all. The flag must take a value.
+func flagValue(args []string, name string) (value string, ok bool) {
+	for i := 0; i < len(args); i++ {
+		n, v, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		if !strings.HasPrefix(args[i], "-") || n != name {
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			i++
+			v = args[i]
+		}
+		value, ok = v, true
+	}
+	return value, ok
+}
+
+// printComparison writes the results that moved by more than threshold
+// percent since the baseline saved at path.
+func printComparison(w io.Writer, path string, old, cur *report.Baseline, threshold float64) report.Comparison {
+	c := report.CompareBaselines(old, cur, threshold/100)
+
+	fmt.Fprintln(w, "\n"+strings.Repeat("#", 60))
+	fmt.Fprintf(w, "# Compared with %s\n", path)
+	fmt.Fprintln(w, strings.Repeat("#", 60))
+	fmt.Fprintf(w, "Baseline: %s on %s (%s/%s, %d CPUs, %s)\n",
+		old.Created.Format("2006-01-02 15:04"), old.Host, old.OS, old.Arch, old.CPUs, old.GoVersion)
+	if old.Host != cur.Host || old.CPUs != cur.CPUs || old.GoVersion != cur.GoVersion {
+		fmt.Fprintf(w, "⚠️  This run: %s (%d CPUs, %s) - differences may be the machine, not the code\n",
+			cur.Host, cur.CPUs, cur.GoVersion)
+	}
+
+	if len(c.Changes) > 0 {
+		fmt.Fprintln(w)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "EXAMPLE\tINPUT\tN\tALGORITHM\tBASELINE\tNOW\tCHANGE\t")
+		for _, ch := range c.Changes {
+			verdict := "⚠️  faster"
+			if ch.Regression() {
+				verdict = "❌ slower"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%.4fms\t%.4fms\t%+.0f%%\t%s\n",
+				ch.New.Example, ch.New.Input, ch.New.N, ch.New.Algorithm,
+				float64(ch.Old.Median)/1e6, float64(ch.New.Median)/1e6, (ch.Ratio()-1)*100, verdict)
+		}
+		tw.Flush()
+	}
+
+	regressions := c.Regressions()
+	fmt.Fprintf(w, "\n%d of %d results moved by more than %g%%: %d slower, %d faster\n",
+		len(c.Changes), c.Matched, threshold, regressions, len(c.Changes)-regressions)
+	if len(c.Unmatched) > 0 {
+		fmt.Fprintf(w, "%d results had nothing to compare with (new examples, or different -n)\n", len(c.Unmatched))
+	}
+	return c
+}
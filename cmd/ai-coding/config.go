@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/iportilla/ai-coding/examples"
+)
+
+// defaultConfig is the config file read from the current directory when
+// -config isn't given. It is fine for it not to exist.
+const defaultConfig = "ai-coding.toml"
+
+// sharedFlags are the flags every example takes, so the only ones a
+// config file may set for all of them at once.
+var sharedFlags = []string{"runs", "warmup", "timeout", "dnf-after", "csv", "q", "v", "benchfmt", "report", "o"}
+
+// config holds the defaults read from a config file, so a course can
+// version its setup instead of passing long command lines around:
+//
+//	# The examples bench-all runs and list shows
+//	examples = ["02", "03", "07"]
+//
+//	# Flags for every example
+//	runs = 10
+//	report = "markdown"
+//
+//	# Flags for one example, named in full or by number
+//	[02]
+//	n = 1e6
+//	dnf-after = "10s"
+//
+//	[03-sorting]
+//	n = [1e4, 1e5, 1e6]    # a list is passed comma-separated
+//
+// Flags on the command line override the example's table, which
+// overrides the top level. A nil *config has no defaults and enables
+// every example.
+type config struct {
+	enabled  []string            // Names of the enabled examples, or nil for all
+	shared   []string            // Flags for every example, as -name=value
+	perEntry map[string][]string // Flags by example name, as -name=value
+}
+
+// loadConfig reads the config file at path. A missing file is only an
+// error if the path was given explicitly; an empty path reads none.
+func loadConfig(path string, explicit bool) (*config, error) {
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	defer f.Close()
+
+	c := &config{perEntry: map[string][]string{}}
+	var table string // the example whose table we're in, or "" at the top level
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(stripComment(scanner.Text()))
+		if text == "" {
+			continue
+		}
+		fail := func(format string, args ...any) error {
+			return fmt.Errorf("%s:%d: %s", path, line, fmt.Sprintf(format, args...))
+		}
+		if strings.HasPrefix(text, "[") {
+			name, ok := strings.CutSuffix(text[1:], "]")
+			if !ok {
+				return nil, fail("missing ] after table name")
+			}
+			name = unquoteKey(strings.TrimSpace(name))
+			ex, err := examples.Lookup(name)
+			if err != nil {
+				return nil, fail("%v", err)
+			}
+			if _, dup := c.perEntry[ex.Name]; dup {
+				return nil, fail("table for %s given twice", ex.Name)
+			}
+			table = ex.Name
+			c.perEntry[table] = []string{}
+			continue
+		}
+
+		key, raw, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fail("want key = value or [example], got %q", text)
+		}
+		key = unquoteKey(strings.TrimSpace(key))
+		values, err := parseValue(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fail("%s: %v", key, err)
+		}
+		switch {
+		case key == "examples" && table == "":
+			if c.enabled != nil {
+				return nil, fail("examples given twice")
+			}
+			c.enabled = []string{}
+			for _, v := range values {
+				ex, err := examples.Lookup(v)
+				if err != nil {
+					return nil, fail("examples: %v", err)
+				}
+				c.enabled = append(c.enabled, ex.Name)
+			}
+		case table == "":
+			if !slices.Contains(sharedFlags, key) {
+				return nil, fail("%s isn't a flag every example takes (%s); set it in an example's table instead", key, strings.Join(sharedFlags, ", "))
+			}
+			c.shared = append(c.shared, "-"+key+"="+strings.Join(values, ","))
+		default:
+			c.perEntry[table] = append(c.perEntry[table], "-"+key+"="+strings.Join(values, ","))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	return c, nil
+}
+
+// args returns the flags c sets for ex, to go before those given on the
+// command line: the flag package keeps the last value a flag is given,
+// so the command line wins.
+func (c *config) args(ex examples.Example) []string {
+	if c == nil {
+		return nil
+	}
+	return slices.Concat(c.shared, c.perEntry[ex.Name])
+}
+
+// enables reports whether ex is one of the config's examples: every
+// example is, unless the config lists them.
+func (c *config) enables(ex examples.Example) bool {
+	return c == nil || c.enabled == nil || slices.Contains(c.enabled, ex.Name)
+}
+
+// examples returns the enabled examples, in the order registered.
+func (c *config) examples() []examples.Example {
+	return slices.DeleteFunc(examples.All(), func(ex examples.Example) bool { return !c.enables(ex) })
+}
+
+// stripComment cuts a # comment off the end of line, leaving any # in a
+// quoted string alone.
+func stripComment(line string) string {
+	var quote rune
+	escaped := false
+	for i, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && r == '\\':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// unquoteKey strips the quotes from a quoted key or table name, which
+// TOML allows and a name like "02" may look better with.
+func unquoteKey(key string) string {
+	if len(key) > 1 && (key[0] == '"' || key[0] == '\'') {
+		if s, err := parseScalar(key); err == nil {
+			return s
+		}
+	}
+	return key
+}
+
+// parseValue parses the TOML value of a key: a string, number or
+// boolean, or a single-line array of them. It returns each value as
+// the text to give a flag.
+func parseValue(raw string) ([]string, error) {
+	if raw == "" {
+		return nil, errors.New("missing value")
+	}
+	if !strings.HasPrefix(raw, "[") {
+		v, err := parseScalar(raw)
+		if err != nil {
+			return nil, err
+		}
+		return []string{v}, nil
+	}
+	inner, ok := strings.CutSuffix(raw[1:], "]")
+	if !ok {
+		return nil, errors.New("arrays must open and close on the same line")
+	}
+	var values []string
+	for _, item := range splitArray(inner) {
+		if item = strings.TrimSpace(item); item == "" {
+			continue // a trailing comma
+		}
+		v, err := parseScalar(item)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// splitArray splits the items of an array at the commas outside quotes.
+func splitArray(s string) []string {
+	var items []string
+	var quote rune
+	start := 0
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote && (quote == '\'' || i == 0 || s[i-1] != '\\') {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == ',':
+			items, start = append(items, s[start:i]), i+1
+		}
+	}
+	return append(items, s[start:])
+}
+
+// parseScalar parses a string, number or boolean. Numbers are passed
+// through as written, less TOML's underscores, so the flags can read
+// them as they like: 1e6 is a valid size, for instance.
+func parseScalar(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return "", fmt.Errorf("bad string %s", s)
+		}
+		return v, nil
+	case strings.HasPrefix(s, "'"):
+		if len(s) < 2 || !strings.HasSuffix(s, "'") || strings.Contains(s[1:len(s)-1], "'") {
+			return "", fmt.Errorf("bad string %s", s)
+		}
+		return s[1 : len(s)-1], nil
+	case s == "true" || s == "false":
+		return s, nil
+	}
+	number := strings.ReplaceAll(s, "_", "")
+	if _, err := strconv.ParseFloat(number, 64); err != nil {
+		return "", fmt.Errorf("want a string, number or true/false, got %s", s)
+	}
+	return number, nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/iportilla/ai-coding/examples"
+)
+
+// listCmd prints the examples cfg enables, optionally narrowed down by
+// category and difficulty. With -v it also lists each example's three
+// implementations.
+func listCmd(w io.Writer, cfg *config, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	category := fs.String("category", "", `only list examples in this category, e.g. "sorting"`)
+	difficulty := fs.String("difficulty", "", "only list examples of this difficulty: beginner, intermediate or advanced")
+	verbose := fs.Bool("v", false, "also show each example's description and implementations")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var level examples.Difficulty
+	if *difficulty != "" {
+		var err error
+		if level, err = examples.ParseDifficulty(*difficulty); err != nil {
+			return err
+		}
+	}
+
+	matched := slices.DeleteFunc(examples.Filter(*category, level), func(ex examples.Example) bool { return !cfg.enables(ex) })
+	if len(matched) == 0 {
+		return fmt.Errorf("no examples match (categories: %s)", categories())
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tCATEGORY\tDIFFICULTY\tTITLE")
+	for _, ex := range matched {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", ex.Name, ex.Category, ex.Difficulty, ex.Title)
+		if *verbose && ex.Description != "" {
+			fmt.Fprintf(tw, "\t\t\t  %s\n", ex.Description)
+		}
+		if *verbose {
+			for _, t := range ex.Lesson.Tiers {
+				fmt.Fprintf(tw, "\t\t\t  - %s: %s, %s\n", t.Label, t.Approach, t.Complexity)
+			}
+		}
+	}
+	return tw.Flush()
+}
+
+// categories lists the distinct categories of the registered examples,
+// for error messages.
+func categories() string {
+	seen := map[string]bool{}
+	var list string
+	for _, ex := range examples.All() {
+		if seen[ex.Category] {
+			continue
+		}
+		seen[ex.Category] = true
+		if list != "" {
+			list += ", "
+		}
+		list += ex.Category
+	}
+	return list
+}
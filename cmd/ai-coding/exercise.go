@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// exerciseCmd sets an example as a task. Students are shown the Vibe
+// implementation they start from, rewrite it in the exercise's solution
+// file, and run the command again: it checks the solution against the
+// hidden Expert implementation, then times the two. The Expert's code is
+// never shown, only the time to beat. Until the solution passes, the
+// command returns an error, so a script or CI job can tell.
+func exerciseCmd(ctx context.Context, w io.Writer, args []string) error {
+	if len(args) == 0 {
+		return listExercises(w)
+	}
+	ex, err := examples.Lookup(args[0])
+	if err != nil {
+		return fmt.Errorf("%w (see 'ai-coding exercise')", err)
+	}
+	task := ex.Exercise
+	if task == nil {
+		return fmt.Errorf("exercise: %s has no exercise (see 'ai-coding exercise')", ex.Name)
+	}
+
+	fs := flag.NewFlagSet("exercise", flag.ContinueOnError)
+	show := fs.Bool("show", false, "print the task and the Vibe implementation it starts from, without checking anything")
+	runs := fs.Int("runs", 5, "timed runs of the solution and the expert (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs of each")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 1m (0 means no limit; Ctrl-C also stops)")
+	n := fs.Int("n", task.N, "input size to time the solution at")
+	seed := input.Flag(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "EXERCISE: %s\n", ex.Title)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "\nWrite %s in %s.\n", task.Signature, task.File)
+	fmt.Fprintf(w, "It passes once it gives the same answers as the expert's and takes\nat most %g× the expert's time at n=%d.\n", task.Target, *n)
+	if *show {
+		vibe := ex.Lesson.Tiers[0]
+		fmt.Fprintf(w, "\nIt starts out as the %s implementation (%s, %s):\n\n", vibe.Label, vibe.Approach, vibe.Complexity)
+		for _, line := range strings.Split(task.Vibe, "\n") {
+			fmt.Fprintln(w, strings.TrimRight("    "+line, " "))
+		}
+		return nil
+	}
+	fmt.Fprintf(w, "(ai-coding exercise %s -show prints the code it starts from.)\n", ex.Name)
+
+	fmt.Fprintf(w, "\nChecking against the expert (seed %s)...\n", seed)
+	if err := task.Check(*seed); err != nil {
+		fmt.Fprintf(w, "❌ %v\n", err)
+		return fmt.Errorf("exercise %s: wrong answer", ex.Name)
+	}
+	fmt.Fprintln(w, "✅ Same answers as the expert")
+
+	solution, expert := task.Impls(*n)
+	results, err := bench.CompareContext(ctx, bench.Options{Runs: *runs, Warmup: *warmup}, solution, expert)
+	if err != nil {
+		return fmt.Errorf("exercise %s: %w", ex.Name, err)
+	}
+	bench.Print(w, results)
+	report.WriteBars(w, results)
+
+	ratio := bench.Speedup(results[0], results[1])
+	target := float64(results[1].Duration) * task.Target / 1e6
+	fmt.Fprintf(w, "\nTarget: %.4fms (%g× the expert)\n", target, task.Target)
+	if ratio > task.Target {
+		fmt.Fprintf(w, "❌ %.1f× the expert's time: not there yet\n", ratio)
+		return fmt.Errorf("exercise %s: %.1f× the expert's time, want at most %g×", ex.Name, ratio, task.Target)
+	}
+	fmt.Fprintf(w, "✅ %.1f× the expert's time: passed!\n", ratio)
+	return nil
+}
+
+// listExercises prints the examples that have an exercise, with the
+// file students edit for each.
+func listExercises(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTITLE\tEDIT\tTARGET")
+	found := false
+	for _, ex := range examples.All() {
+		if ex.Exercise == nil {
+			continue
+		}
+		found = true
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%g× the expert's time\n", ex.Name, ex.Title, ex.Exercise.File, ex.Exercise.Target)
+	}
+	if !found {
+		return errors.New("exercise: no examples have exercises")
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "\nRun 'ai-coding exercise <name> -show' to start one.")
+	return nil
+}
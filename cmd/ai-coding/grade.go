@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/grade"
+	"github.com/iportilla/ai-coding/input"
+)
+
+// gradeCmd grades one student's solutions to the exercises - every
+// exercise, or those named - prints the scores and saves them as a JSON
+// report for the instructor. Wrong or slow solutions lower the score
+// rather than failing the command, so a script can grade a whole class.
+func gradeCmd(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("grade", flag.ContinueOnError)
+	student := fs.String("student", "", "name or ID of the student whose solutions are graded (required)")
+	out := fs.String("o", "", "JSON score report to write (default <student>.json)")
+	runs := fs.Int("runs", 3, "timed runs of the solution and the expert at each size (median is used)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs of each at each size")
+	dnfAfter := fs.Duration("dnf-after", 30*time.Second, "stop timing a solution that takes longer than this at one size (0 means never)")
+	rubric := grade.DefaultRubric
+	fs.Var(&rubric, "rubric", "points for correctness, complexity and performance, e.g. 50,25,25")
+	seed := input.Flag(fs)
+	names, err := takeFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("grade: %w", err)
+	}
+	if *student == "" {
+		return errors.New("grade: -student is required, to name the report")
+	}
+	if *out == "" {
+		*out = fileName(*student) + ".json"
+	}
+
+	var exs []examples.Example
+	for _, name := range names {
+		ex, err := examples.Lookup(name)
+		if err != nil {
+			return fmt.Errorf("grade: %w (see 'ai-coding exercise')", err)
+		}
+		if ex.Exercise == nil {
+			return fmt.Errorf("grade: %s has no exercise (see 'ai-coding exercise')", ex.Name)
+		}
+		exs = append(exs, ex)
+	}
+	if len(names) == 0 {
+		for _, ex := range examples.All() {
+			if ex.Exercise != nil {
+				exs = append(exs, ex)
+			}
+		}
+	}
+
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	rep := grade.NewReport(*student, rubric)
+	for _, ex := range exs {
+		fmt.Fprintf(w, "  grading %s...\n", ex.Name)
+		score, err := grade.Exercise(ctx, opts, *seed, rubric, ex.Name, ex.Exercise)
+		if err != nil {
+			return fmt.Errorf("grade %s: %w", ex.Name, err)
+		}
+		rep.Add(score)
+	}
+
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "EXERCISE\tCORRECT\tCOMPLEXITY (EXPERT)\tTIME (TARGET)\tPOINTS")
+	for _, s := range rep.Scores {
+		if !s.Correct {
+			fmt.Fprintf(tw, "%s\t❌\t-\t-\t%g / %g\n", s.Exercise, s.Total, s.Max)
+			continue
+		}
+		growth := "too few sizes to tell"
+		if s.Complexity != "" {
+			growth = fmt.Sprintf("%s, n^%.2f (%s, n^%.2f)", s.Complexity, s.Slope, s.ExpertComplexity, s.ExpertSlope)
+		}
+		time := fmt.Sprintf("%.1f× expert (%g×)", s.Ratio, s.Target)
+		if s.DNF {
+			time = fmt.Sprintf("DNF, ≥%.1f× expert (%g×)", s.Ratio, s.Target)
+		}
+		fmt.Fprintf(tw, "%s\t✅\t%s\t%s\t%g + %g + %g = %g / %g\n", s.Exercise, growth, time,
+			s.Points.Correctness, s.Points.Complexity, s.Points.Performance, s.Total, s.Max)
+	}
+	tw.Flush()
+	for _, s := range rep.Scores {
+		if s.Error != "" {
+			fmt.Fprintf(w, "\n%s: %s\n", s.Exercise, s.Error)
+		}
+	}
+	fmt.Fprintf(w, "\nTotal for %s: %g / %g\n", rep.Student, rep.Total, rep.Max)
+
+	if err := rep.WriteFile(*out); err != nil {
+		return fmt.Errorf("grade: %w", err)
+	}
+	fmt.Fprintf(w, "📝 Scores written to %s\n", *out)
+	return nil
+}
+
+// fileName turns a student's name into one safe to name a file by,
+// keeping letters, digits, '-', '_' and '.' and replacing the rest.
+func fileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-_.", r) {
+			return r
+		}
+		return '_'
+	}, name)
+}
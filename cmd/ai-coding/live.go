@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+)
+
+// maxLiveN caps the n that tui and serve let users pick: large enough to
+// show every example's curves, small enough that no example's input runs
+// out of memory.
+const maxLiveN = 10_000_000
+
+// liveLimit is the largest n tui and serve let users pick for ex.
+func liveLimit(ex examples.Example) int {
+	if ex.MaxN > 0 {
+		return min(ex.MaxN, maxLiveN)
+	}
+	return maxLiveN
+}
+
+// sample is one timed run of one implementation, or the error that
+// stopped the measurement.
+type sample struct {
+	gen   int // Which measurement it belongs to; older ones are dropped
+	index int // Position in the implementations being measured
+	d     time.Duration
+	err   error
+}
+
+// measure times impls one run at a time, taking turns so that every bar
+// appears early, and sends each run to samples. An implementation that
+// doesn't check its context finishes its run before measure notices it
+// has been cancelled.
+func measure(ctx context.Context, gen int, impls []bench.Implementation, runs int, samples chan<- sample) {
+	for range runs {
+		for i, impl := range impls {
+			results, err := bench.CompareContext(ctx, bench.Options{Runs: 1}, impl)
+			if ctx.Err() != nil {
+				return
+			}
+			s := sample{gen: gen, index: i, err: err}
+			if err == nil {
+				s.d = results[0].Duration
+			}
+			select {
+			case samples <- s:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,367 @@
+// Command ai-coding lists and runs the repository's Go examples from the
+// examples registry.
+//
+// Usage:
+//
+//	ai-coding list [flags]              List the examples, optionally filtered
+//	ai-coding run <example> [flags]     Run one example, passing flags through
+//	ai-coding bench-all [flags]         Run every Go example in turn
+//	ai-coding verify [flags]            Fuzz-check the implementations agree
+//	ai-coding exercise [<example>]      Beat the hidden expert with your own code
+//	ai-coding grade -student <name>     Score the exercise solutions as JSON
+//	ai-coding tui [flags]               Pick an example and n, watch live bars
+//	ai-coding serve [flags]             The same as a web dashboard
+//
+// Examples can be named in full ("02-prime-algorithms") or by number
+// ("02"). Programs on PATH named ai-coding-<something>, built with
+// examples.ServePlugin, are run as examples too. Every command takes -ascii, to print plain ASCII where the
+// terminal can't show emoji and box drawing, -color, on by default in a
+// terminal unless NO_COLOR is set, and -config, to read default flags and
+// the examples to run from a TOML file (ai-coding.toml if there is one).
+// Install it with:
+//
+//	go install github.com/iportilla/ai-coding/cmd/ai-coding@latest
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	_ "github.com/iportilla/ai-coding/examples/all"
+	"github.com/iportilla/ai-coding/report"
+	"github.com/iportilla/ai-coding/style"
+	"github.com/iportilla/ai-coding/trace"
+)
+
+const usage = `Usage: ai-coding <command> [arguments]
+
+Commands:
+  list [flags]              List the examples (-category, -difficulty, -v)
+  run <example> [flags]     Run one example; remaining flags go to the example,
+                            except -cpuprofile, -memprofile and -trace
+  bench-all [flags]         Run every Go example; flags go to each example,
+                            except -save, -compare, -threshold (percent),
+                            -parallel, -cpuprofile, -memprofile and -trace
+  sweep <example> [flags]   Time one example across a geometric range of n, append
+                            the medians to sweep.csv and plot them log-log
+                            (-from, -to, -per-decade 3, -runs 5, -dnf-after, -csv)
+  verify [flags]            Cross-check all prime implementations on random n
+                            (-iterations 200, -max 2e6, -seed 0)
+  exercise [<example>]      List the exercises, or check and time your solution to
+                            one against the hidden expert implementation
+                            (-show to see the code it starts from, -runs 5, -n)
+  grade -student <name> [<example>...]
+                            Score a student's exercise solutions for correctness,
+                            complexity and speed against the expert, and save the
+                            scores as <name>.json (-o, -rubric 40,30,30, -runs 3)
+  tui [flags]               Interactive: pick an example with ↑/↓, change n with
+                            ←/→, and watch the timing bars update (-runs 5)
+  serve [flags]             Web dashboard of the examples, shared by everyone
+                            watching, with a JSON API and Prometheus metrics at
+                            /metrics (-addr localhost:8080, -runs 5, -trace)
+
+Every command also takes -ascii to print plain ASCII instead of emoji and
+box drawing, which is the default where the terminal looks unable to show
+them (the classic Windows console, or a locale that isn't UTF-8); -ascii=false
+forces them on. -color marks the fastest result green and the slowest red; it
+is on in a terminal unless the NO_COLOR environment variable is set, and
+-color=false turns it off.
+
+Plugins: any program on PATH named ai-coding-<name> and built with the
+examples package's ServePlugin is listed and run as one more example.
+
+-config names a TOML file of default flags for the examples and the list of
+examples bench-all runs and list shows; ai-coding.toml is read if it exists.
+Top-level keys go to every example, [02]-style tables to one, and flags on
+the command line override both.
+
+Examples:
+  ai-coding list -category "number theory" -v
+  ai-coding run 02-prime-algorithms -runs 20
+  ai-coding run 05
+  ai-coding bench-all -runs 3 -q    (-q: results tables only; -v: more detail)
+  ai-coding run 03 -benchfmt > old.txt    (Go benchmark lines for benchstat)
+  ai-coding run 07 -n 1e5 -cpuprofile cpu.prof -memprofile mem.prof
+  ai-coding bench-all -trace http://localhost:4318    (OpenTelemetry spans to a collector)
+  ai-coding bench-all -save baseline.json
+  ai-coding bench-all -parallel 4 -q    (4 examples at a time, each on its share of the CPUs)
+  ai-coding bench-all -compare baseline.json -threshold 25
+  ai-coding sweep 03 -from 1e2 -to 1e6
+  ai-coding verify -iterations 1000 -seed 42
+  ai-coding exercise 03 -show
+  ai-coding grade -student alice -o scores/alice.json
+  ai-coding tui
+  ai-coding serve -addr :8080    (open http://<this machine>:8080/ to watch)
+  ai-coding -config course.toml bench-all
+`
+
+func main() {
+	// Ctrl-C cancels the running example instead of killing the process,
+	// so it can stop cleanly and bench-all can still print its summary.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "ai-coding:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	global := flag.NewFlagSet("ai-coding", flag.ContinueOnError)
+	ascii := global.Bool("ascii", !unicodeTerminal(), "print plain ASCII instead of emoji, box drawing and maths symbols (default: guessed from the terminal)")
+	color := global.Bool("color", style.Terminal(os.Stdout), "color the fastest result green and the slowest red (default: on for a terminal, unless NO_COLOR is set)")
+	configPath := global.String("config", defaultConfig, "read default flags and the enabled examples from this TOML file")
+	args, err := takeFlags(global, args)
+	if err != nil {
+		return err
+	}
+	stdout, stderr := io.Writer(os.Stdout), io.Writer(os.Stderr)
+	if *ascii {
+		stdout, stderr = &asciiWriter{w: os.Stdout}, &asciiWriter{w: os.Stderr}
+	}
+	if *color {
+		stdout = style.Color(stdout)
+	}
+	registerPlugins(stderr)
+	explicit := false
+	global.Visit(func(f *flag.Flag) { explicit = explicit || f.Name == "config" })
+	cfg, err := loadConfig(*configPath, explicit)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return errors.New("no command given")
+	}
+
+	cmd, args := args[0], args[1:]
+	if cmd == "help" || cmd == "-h" || cmd == "-help" || cmd == "--help" {
+		fmt.Fprint(stdout, usage)
+		return nil
+	}
+
+	switch cmd {
+	case "list":
+		return listCmd(stdout, cfg, args)
+	case "run":
+		if len(args) == 0 {
+			return errors.New("run: missing example name (see 'ai-coding list')")
+		}
+		ex, err := examples.Lookup(args[0])
+		if err != nil {
+			return fmt.Errorf("%w (see 'ai-coding list')", err)
+		}
+		fs := flag.NewFlagSet("run", flag.ContinueOnError)
+		prof := profileFlags(fs)
+		traceDest := traceFlag(fs)
+		args, err := takeFlags(fs, args[1:])
+		if err != nil {
+			return fmt.Errorf("run: %w", err)
+		}
+		return runExample(ctx, stdout, ex, slices.Concat(cfg.args(ex), args), *prof, *traceDest)
+	case "bench-all":
+		return benchAllCmd(ctx, stdout, cfg, args)
+	case "sweep":
+		return sweepCmd(ctx, stdout, args)
+	case "verify":
+		return verifyCmd(ctx, stdout, stderr, args)
+	case "exercise":
+		return exerciseCmd(ctx, stdout, args)
+	case "grade":
+		return gradeCmd(ctx, stdout, args)
+	case "tui":
+		return tuiCmd(ctx, stdout, args)
+	case "serve":
+		return serveCmd(ctx, args)
+	default:
+		fmt.Fprint(stderr, usage)
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+}
+
+// runExample runs one registered example in this process, writing its
+// output to w. If prof names any profile files, every algorithm the
+// example times is profiled, and the files written are listed at the end.
+// If traceDest is set, the run is traced and its spans sent there.
+func runExample(ctx context.Context, w io.Writer, ex examples.Example, args []string, prof bench.Profiler, traceDest string) error {
+	profiling := prof.CPUProfile != "" || prof.MemProfile != ""
+	if profiling {
+		prof.Label = ex.Name
+		ctx = bench.WithProfiler(ctx, &prof)
+	}
+	var tracer *trace.Tracer
+	if traceDest != "" {
+		tracer = trace.New("ai-coding", traceDest)
+	}
+	ctx, span := trace.Start(trace.WithTracer(ctx, tracer), ex.Name, trace.String("args", strings.Join(args, " ")))
+	err := ex.Run(ctx, w, args)
+	span.End(err)
+	if ferr := flushTrace(tracer); ferr != nil && err == nil {
+		err = ferr
+	}
+	if profiling && len(prof.Written) > 0 {
+		fmt.Fprintf(w, "\n📈 %d profiles written, numbered by comparison in the order above:\n", len(prof.Written))
+		for _, path := range prof.Written {
+			fmt.Fprintln(w, "   "+path)
+		}
+		fmt.Fprintf(w, "   Open one with: go tool pprof -http=: %s\n", prof.Written[0])
+	}
+	if err != nil {
+		return fmt.Errorf("run %s: %w", ex.Name, err)
+	}
+	return nil
+}
+
+// flushTrace sends t's spans on, with a few seconds to do it in even if
+// the run was interrupted. A nil Tracer sends nothing.
+func flushTrace(t *trace.Tracer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := t.Flush(ctx); err != nil {
+		return fmt.Errorf("trace: %w", err)
+	}
+	return nil
+}
+
+// traceFlag defines the -trace flag on fs, defaulting to the standard
+// OpenTelemetry environment variable, and returns its value.
+func traceFlag(fs *flag.FlagSet) *string {
+	return fs.String("trace", os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		"send OpenTelemetry spans of every example, implementation and run to this OTLP/HTTP collector URL, e.g. http://localhost:4318, or append them to this file as OTLP JSON (default $OTEL_EXPORTER_OTLP_ENDPOINT)")
+}
+
+// profileFlags defines the -cpuprofile and -memprofile flags on fs and
+// returns the Profiler they configure.
+func profileFlags(fs *flag.FlagSet) *bench.Profiler {
+	p := &bench.Profiler{}
+	fs.StringVar(&p.CPUProfile, "cpuprofile", "", "write a CPU profile of each algorithm's timed runs, to files named like this one, e.g. cpu.prof")
+	fs.StringVar(&p.MemProfile, "memprofile", "", "write an allocation profile of each algorithm's timed runs, to files named like this one")
+	return p
+}
+
+// benchAllCmd runs every Go example the config enables in turn, with
+// the config's flags under those on the command line, and prints how
+// long each one took, carrying on past failures so one broken example
+// doesn't hide the rest. Everything, including the summary, is written
+// to w.
+//
+// With -parallel N it runs up to N examples at once, each in a process
+// of its own; see benchParallel.
+//
+// With -save or -compare it also records every median timing, through
+// the examples' own -csv flag and a temporary file, to save as a
+// baseline or compare with one saved earlier.
+func benchAllCmd(ctx context.Context, w io.Writer, cfg *config, args []string) error {
+	fs := flag.NewFlagSet("bench-all", flag.ContinueOnError)
+	save := fs.String("save", "", "save every median timing to this JSON baseline file")
+	compare := fs.String("compare", "", "compare every median timing with this JSON baseline file")
+	threshold := fs.Float64("threshold", 20, "with -compare, flag timings that moved by more than this percentage")
+	parallel := fs.Int("parallel", 1, "run up to this many examples at once, each in a process of its own with its share of the CPUs")
+	prof := profileFlags(fs)
+	traceDest := traceFlag(fs)
+	args, err := takeFlags(fs, args)
+	if err != nil {
+		return fmt.Errorf("bench-all: %w", err)
+	}
+	var old *report.Baseline
+	if *compare != "" {
+		if old, err = report.ReadBaseline(*compare); err != nil {
+			return fmt.Errorf("bench-all: -compare: %w", err)
+		}
+	}
+	var csvPath string
+	if *save != "" || *compare != "" {
+		csvSet := hasFlag(args, "csv")
+		for _, ex := range cfg.examples() {
+			csvSet = csvSet || hasFlag(cfg.args(ex), "csv")
+		}
+		if csvSet {
+			return errors.New("bench-all: -save and -compare record results through -csv, so they can't be combined with it")
+		}
+		f, err := os.CreateTemp("", "ai-coding-*.csv")
+		if err != nil {
+			return fmt.Errorf("bench-all: %w", err)
+		}
+		f.Close()
+		csvPath = f.Name()
+		defer os.Remove(csvPath)
+		args = append(slices.Clip(args), "-csv", csvPath)
+	}
+
+	argsFor := func(ex examples.Example) []string { return slices.Concat(cfg.args(ex), args) }
+	var outcomes []outcome
+	if *parallel > 1 {
+		if outcomes, err = benchParallel(ctx, w, cfg.examples(), argsFor, *parallel, *prof, *traceDest); err != nil {
+			return fmt.Errorf("bench-all: -parallel: %w", err)
+		}
+	} else {
+		for _, ex := range cfg.examples() {
+			if ctx.Err() != nil {
+				break // interrupted: skip the rest, but still summarise
+			}
+			fmt.Fprintln(w, strings.Repeat("#", 60))
+			fmt.Fprintf(w, "# %s\n", ex.Name)
+			fmt.Fprintln(w, strings.Repeat("#", 60))
+
+			start := time.Now()
+			err := runExample(ctx, w, ex, argsFor(ex), *prof, *traceDest)
+			outcomes = append(outcomes, outcome{ex.Name, time.Since(start), err})
+		}
+	}
+
+	fmt.Fprintln(w, "\n"+strings.Repeat("#", 60))
+	fmt.Fprintln(w, "# bench-all summary")
+	fmt.Fprintln(w, strings.Repeat("#", 60))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	failed := 0
+	for _, o := range outcomes {
+		status := "ok"
+		if o.err != nil {
+			status = "FAILED: " + o.err.Error()
+			failed++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", o.name, o.elapsed.Round(time.Millisecond), status)
+	}
+	tw.Flush()
+
+	if csvPath != "" {
+		f, err := os.Open(csvPath)
+		if err != nil {
+			return fmt.Errorf("bench-all: %w", err)
+		}
+		cur, err := report.BaselineFromCSV(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("bench-all: %w", err)
+		}
+		if *save != "" {
+			if err := cur.WriteFile(*save); err != nil {
+				return fmt.Errorf("bench-all: -save: %w", err)
+			}
+			fmt.Fprintf(w, "\n📝 Baseline of %d results written to %s\n", len(cur.Results), *save)
+		}
+		if old != nil {
+			if c := printComparison(w, *compare, old, cur, *threshold); c.Regressions() > 0 && failed == 0 {
+				return fmt.Errorf("%d results more than %g%% slower than %s", c.Regressions(), *threshold, *compare)
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d examples failed", failed, len(outcomes))
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// durationBuckets are the upper bounds, in seconds, of the timing
+// histogram's buckets: a decade each from a microsecond to 100 seconds,
+// wide enough for the fastest Expert lookup and the slowest Vibe sort.
+var durationBuckets = []float64{1e-6, 1e-5, 1e-4, 1e-3, 1e-2, 1e-1, 1, 10, 100}
+
+// metrics counts what serve has run, for Prometheus to scrape from GET
+// /metrics: how many runs were started and how many failed, by example
+// and by where they were started from - the dashboard or the JSON API -
+// and a histogram of every timing measured, by example and
+// implementation. The metrics are written in Prometheus's text format by
+// hand, to keep the module free of dependencies.
+type metrics struct {
+	mu       sync.Mutex
+	started  time.Time
+	runs     map[runKey]int
+	failures map[runKey]int
+	timings  map[timingKey]*histogram
+}
+
+// runKey labels a run count.
+type runKey struct{ example, source string }
+
+// timingKey labels a timing histogram.
+type timingKey struct{ example, implementation, source string }
+
+// histogram counts observations into durationBuckets, not cumulatively;
+// they are added up when written.
+type histogram struct {
+	buckets []int // One per bound in durationBuckets, then one for +Inf
+	count   int
+	sum     float64
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		started:  time.Now(),
+		runs:     map[runKey]int{},
+		failures: map[runKey]int{},
+		timings:  map[timingKey]*histogram{},
+	}
+}
+
+// run counts a run of example started from source.
+func (m *metrics) run(example, source string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.runs[runKey{example, source}]++
+}
+
+// fail counts a run of example started from source that ended in an
+// error.
+func (m *metrics) fail(example, source string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failures[runKey{example, source}]++
+}
+
+// observe adds one timing of implementation to the histogram.
+func (m *metrics) observe(example, implementation, source string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := timingKey{example, implementation, source}
+	h := m.timings[key]
+	if h == nil {
+		h = &histogram{buckets: make([]int, len(durationBuckets)+1)}
+		m.timings[key] = h
+	}
+	s := d.Seconds()
+	i, _ := slices.BinarySearch(durationBuckets, s) // the first bound ≥ s, or +Inf
+	h.buckets[i]++
+	h.count++
+	h.sum += s
+}
+
+// ServeHTTP writes the metrics in Prometheus's text exposition format.
+func (m *metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.write(w)
+}
+
+// write writes the metrics to w, sorted by label so that scrapes are
+// easy to compare. The caller must hold m.mu.
+func (m *metrics) write(w io.Writer) {
+	fmt.Fprintln(w, "# HELP ai_coding_uptime_seconds Seconds since the server started.")
+	fmt.Fprintln(w, "# TYPE ai_coding_uptime_seconds gauge")
+	fmt.Fprintf(w, "ai_coding_uptime_seconds %s\n", formatFloat(time.Since(m.started).Seconds()))
+
+	for _, c := range []struct {
+		name, help string
+		counts     map[runKey]int
+	}{
+		{"ai_coding_runs_total", "Runs of each example started, from the dashboard or the JSON API.", m.runs},
+		{"ai_coding_run_failures_total", "Runs of each example that ended in an error.", m.failures},
+	} {
+		fmt.Fprintf(w, "# HELP %s %s\n", c.name, c.help)
+		fmt.Fprintf(w, "# TYPE %s counter\n", c.name)
+		keys := slices.SortedFunc(maps.Keys(c.counts), func(a, b runKey) int {
+			return cmp.Or(cmp.Compare(a.example, b.example), cmp.Compare(a.source, b.source))
+		})
+		for _, k := range keys {
+			fmt.Fprintf(w, "%s{%s} %d\n", c.name, labels("example", k.example, "source", k.source), c.counts[k])
+		}
+	}
+
+	const name = "ai_coding_implementation_duration_seconds"
+	fmt.Fprintf(w, "# HELP %s Timings of each implementation: every run on the dashboard, the median of each API run.\n", name)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+	keys := slices.SortedFunc(maps.Keys(m.timings), func(a, b timingKey) int {
+		return cmp.Or(cmp.Compare(a.example, b.example), cmp.Compare(a.implementation, b.implementation), cmp.Compare(a.source, b.source))
+	})
+	for _, k := range keys {
+		h := m.timings[k]
+		l := labels("example", k.example, "implementation", k.implementation, "source", k.source)
+		cumulative := 0
+		for i, n := range h.buckets {
+			cumulative += n
+			le := "+Inf"
+			if i < len(durationBuckets) {
+				le = formatFloat(durationBuckets[i])
+			}
+			fmt.Fprintf(w, "%s_bucket{%s,le=%q} %d\n", name, l, le, cumulative)
+		}
+		fmt.Fprintf(w, "%s_sum{%s} %s\n", name, l, formatFloat(h.sum))
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, l, h.count)
+	}
+}
+
+// labels formats name-value pairs as Prometheus labels, escaping the
+// values.
+func labels(pairs ...string) string {
+	var b strings.Builder
+	for i := 0; i < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(pairs[i+1])
+		fmt.Fprintf(&b, `%s="%s"`, pairs[i], value)
+	}
+	return b.String()
+}
+
+// formatFloat formats f as Prometheus reads it.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/style"
+)
+
+// outcome is how one example went in bench-all.
+type outcome struct {
+	name    string
+	elapsed time.Duration
+	err     error
+}
+
+// benchParallel runs exs as bench-all does, but up to n at a time, each
+// in a process of its own: this program again, as 'ai-coding run'. A
+// process of its own gives each example its own heap, so the allocation
+// and GC columns count only its algorithms, and GOMAXPROCS set to its
+// share of the CPUs, so the examples running side by side don't take
+// cores from each other's timed runs. Each example's output is held
+// until it finishes, then written to w in the order of exs.
+//
+// argsFor returns the flags to run an example with. If they include
+// -csv, each process appends to a file of its own, copied into the real
+// one in order as it finishes, so rows never interleave.
+func benchParallel(ctx context.Context, w io.Writer, exs []examples.Example, argsFor func(examples.Example) []string, n int, prof bench.Profiler, traceDest string) ([]outcome, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "ai-coding-parallel-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	procs := max(runtime.NumCPU()/n, 1)
+	if n > runtime.NumCPU() {
+		fmt.Fprintf(w, "⚠️  -parallel %d is more than the %d CPUs: examples will share cores and time slower\n\n", n, runtime.NumCPU())
+	}
+
+	type job struct {
+		out     bytes.Buffer
+		csv     string // the file this example's -csv rows go to, if any
+		outcome outcome
+		done    chan struct{}
+	}
+	jobs := make([]*job, len(exs))
+	for i, ex := range exs {
+		jobs[i] = &job{done: make(chan struct{}), outcome: outcome{name: ex.Name}}
+	}
+	// Start the examples in order as slots free up, so that an interrupt
+	// leaves the first ones run, as it does running them in turn.
+	var wg sync.WaitGroup
+	go func() {
+		slots := make(chan struct{}, n)
+		for i, ex := range exs {
+			j := jobs[i]
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				j.outcome.err = ctx.Err()
+				close(j.done)
+				continue
+			}
+			args := argsFor(ex)
+			if csv, ok := flagValue(args, "csv"); ok {
+				j.csv = csv
+				args = append(args, "-csv", filepath.Join(dir, ex.Name+".csv"))
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-slots }()
+				defer close(j.done)
+				start := time.Now()
+				j.outcome.err = runChild(ctx, &j.out, self, procs, ex.Name, args, prof, traceDest, style.Enabled(w))
+				j.outcome.elapsed = time.Since(start)
+			}()
+		}
+	}()
+
+	var outcomes []outcome
+	for i, j := range jobs {
+		<-j.done
+		if j.outcome.elapsed == 0 && j.outcome.err != nil {
+			continue // never started: skip it, as bench-all does once interrupted
+		}
+		fmt.Fprintln(w, strings.Repeat("#", 60))
+		fmt.Fprintf(w, "# %s\n", j.outcome.name)
+		fmt.Fprintln(w, strings.Repeat("#", 60))
+		w.Write(j.out.Bytes())
+		if j.csv != "" {
+			if err := appendCSV(j.csv, filepath.Join(dir, exs[i].Name+".csv")); err != nil && j.outcome.err == nil {
+				j.outcome.err = fmt.Errorf("csv: %w", err)
+			}
+		}
+		outcomes = append(outcomes, j.outcome)
+	}
+	wg.Wait()
+	return outcomes, nil
+}
+
+// runChild runs the example called name in a new process of the program
+// at self, with GOMAXPROCS set to procs and its output written to w. The
+// config has already been applied to args, so the process reads none.
+func runChild(ctx context.Context, w io.Writer, self string, procs int, name string, args []string, prof bench.Profiler, traceDest string, color bool) error {
+	cmdArgs := []string{"-ascii=false", "-color=" + strconv.FormatBool(color), "-config=", "run", name}
+	if prof.CPUProfile != "" {
+		cmdArgs = append(cmdArgs, "-cpuprofile", prof.CPUProfile)
+	}
+	if prof.MemProfile != "" {
+		cmdArgs = append(cmdArgs, "-memprofile", prof.MemProfile)
+	}
+	if traceDest != "" {
+		cmdArgs = append(cmdArgs, "-trace", traceDest)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, self, append(cmdArgs, args...)...)
+	cmd.Env = append(os.Environ(), "GOMAXPROCS="+strconv.Itoa(procs))
+	cmd.Stdout, cmd.Stderr = w, &stderr
+	cmd.Cancel = func() error {
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	cmd.WaitDelay = 5 * time.Second
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		// The process's last words are its error, as main prints it.
+		lines := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+		if msg := strings.TrimPrefix(lines[len(lines)-1], "ai-coding: "); msg != "" {
+			return errors.New(msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// appendCSV appends the rows of the CSV file at src to the one at dst,
+// with src's header line only if dst is new or empty.
+func appendCSV(dst, src string) error {
+	data, err := os.ReadFile(src)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil // the example wrote no rows
+	}
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		_, data, _ = bytes.Cut(data, []byte("\n"))
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
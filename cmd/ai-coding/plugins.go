@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/iportilla/ai-coding/examples"
+)
+
+// registerPlugins finds the plugin programs on PATH - executables named
+// examples.PluginPrefix + something, built with examples.ServePlugin -
+// asks each to describe itself and registers it as an example that runs
+// the program. A plugin that fails to describe itself, or takes a name
+// already registered, is skipped with a warning on stderr, so one broken
+// plugin can't stop the built-in examples from running.
+func registerPlugins(stderr io.Writer) {
+	seen := map[string]bool{}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name := strings.TrimSuffix(e.Name(), ".exe")
+			if !strings.HasPrefix(name, examples.PluginPrefix) || e.IsDir() || seen[name] {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			if info, err := e.Info(); err != nil || runtime.GOOS != "windows" && info.Mode()&0o111 == 0 {
+				continue // not executable
+			}
+			seen[name] = true // the first on PATH wins, as for commands
+
+			m, err := describePlugin(path)
+			if err != nil {
+				fmt.Fprintf(stderr, "⚠️  skipping plugin %s: %v\n", path, err)
+				continue
+			}
+			if _, err := examples.Lookup(m.Name); err == nil {
+				fmt.Fprintf(stderr, "⚠️  skipping plugin %s: an example called %s is already registered\n", path, m.Name)
+				continue
+			}
+			examples.Register(examples.Example{
+				Name:        m.Name,
+				Title:       m.Title,
+				Description: m.Description,
+				Category:    m.Category,
+				Difficulty:  m.Difficulty,
+				Lesson:      m.Lesson,
+				Run:         runPlugin(path, stderr),
+			})
+		}
+	}
+}
+
+// describePlugin runs the plugin program at path for its manifest.
+func describePlugin(path string) (examples.Manifest, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var m examples.Manifest
+	data, err := exec.CommandContext(ctx, path, examples.DescribeArg).Output()
+	if err != nil {
+		return m, err
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("bad manifest: %w", err)
+	}
+	if m.Name == "" {
+		return m, errors.New("manifest has no name")
+	}
+	return m, nil
+}
+
+// runPlugin returns the Run of the plugin program at path: it runs the
+// program with the example's arguments, its output going to w and its
+// errors to stderr. When ctx is done the program is interrupted, to stop
+// cleanly, and killed if it hasn't within a few seconds.
+func runPlugin(path string, stderr io.Writer) func(ctx context.Context, w io.Writer, args []string) error {
+	return func(ctx context.Context, w io.Writer, args []string) error {
+		cmd := exec.CommandContext(ctx, path, args...)
+		cmd.Stdout, cmd.Stderr = w, stderr
+		cmd.Cancel = func() error {
+			if err := cmd.Process.Signal(os.Interrupt); err != nil {
+				return cmd.Process.Kill()
+			}
+			return nil
+		}
+		cmd.WaitDelay = 5 * time.Second
+		if err := cmd.Run(); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		return nil
+	}
+}
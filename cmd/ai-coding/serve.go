@@ -0,0 +1,444 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/trace"
+)
+
+// serveCmd starts an HTTP server with a dashboard of the examples. The
+// comparison on screen is shared: whoever starts one, every browser
+// watching sees its bars grow, so a class can follow the projector on
+// their own laptops. GET /metrics serves what has been run, for
+// Prometheus to scrape, and -trace sends a trace of every run to an
+// OpenTelemetry collector.
+func serveCmd(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", "localhost:8080", "address to listen on; :8080 lets other machines connect")
+	runs := fs.Int("runs", 5, "timed runs per implementation (median is shown)")
+	traceDest := traceFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return fmt.Errorf("serve: %w", err)
+	}
+	d := newDashboard(ctx, max(*runs, 1))
+	if *traceDest != "" {
+		d.tracer = trace.New("ai-coding", *traceDest)
+	}
+	srv := &http.Server{
+		Handler:     d.handler(),
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	fmt.Printf("Dashboard at http://%s/ - press Ctrl-C to stop\n", ln.Addr())
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve: %w", err)
+	}
+	return nil
+}
+
+// liveState is the comparison on the dashboard, sent to every browser as
+// JSON whenever it changes.
+type liveState struct {
+	Example string       `json:"example"`
+	Title   string       `json:"title"`
+	N       int          `json:"n,omitempty"` // 0 for examples run in full
+	Runs    int          `json:"runs"`
+	Done    int          `json:"done"` // Runs every implementation has finished
+	Running bool         `json:"running"`
+	Results []liveResult `json:"results"`
+	Skipped []string     `json:"skipped"`
+	Output  string       `json:"output,omitempty"` // Text of an example run in full
+	Error   string       `json:"error,omitempty"`
+}
+
+// liveResult is one implementation's timing so far.
+type liveResult struct {
+	Name       string  `json:"name"`
+	Complexity string  `json:"complexity"`
+	MedianMs   float64 `json:"median_ms"`
+	Runs       int     `json:"runs"`
+}
+
+// dashboard runs one comparison at a time and tells every watching
+// browser when it changes. Examples that set Impls are timed one run at
+// a time at the n the user picks, like in the TUI; the others are run in
+// full and their text output is shown as it is written.
+type dashboard struct {
+	ctx     context.Context
+	runs    int
+	metrics *metrics
+	tracer  *trace.Tracer // nil unless runs are traced
+
+	mu       sync.Mutex
+	state    liveState
+	gen      int
+	cancel   context.CancelFunc
+	impls    []bench.Implementation
+	samples  [][]time.Duration
+	watchers map[chan struct{}]bool
+}
+
+func newDashboard(ctx context.Context, runs int) *dashboard {
+	return &dashboard{ctx: ctx, runs: runs, metrics: newMetrics(), watchers: map[chan struct{}]bool{}}
+}
+
+func (d *dashboard) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", d.page)
+	mux.HandleFunc("POST /start", d.startHandler)
+	mux.HandleFunc("GET /events", d.events)
+
+	api := &api{metrics: d.metrics, tracer: d.tracer}
+	mux.HandleFunc("GET /examples", api.examples)
+	mux.HandleFunc("POST /run", api.run)
+
+	mux.Handle("GET /metrics", d.metrics)
+	return mux
+}
+
+// page serves the dashboard itself.
+func (d *dashboard) page(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := dashboardTemplate.Execute(w, examples.All()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// startHandler starts the comparison named by the form values example
+// and n, replacing the one under way.
+func (d *dashboard) startHandler(w http.ResponseWriter, r *http.Request) {
+	ex, err := examples.Lookup(r.FormValue("example"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	n := ex.DefaultN
+	if s := r.FormValue("n"); s != "" {
+		if n, err = bench.ParseSize(s); err != nil {
+			http.Error(w, "n: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	d.start(ex, min(max(n, 1), liveLimit(ex)))
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// events streams the state to one browser as server-sent events: once
+// on connecting, then after every change.
+func (d *dashboard) events(w http.ResponseWriter, r *http.Request) {
+	changed := make(chan struct{}, 1)
+	changed <- struct{}{}
+	d.mu.Lock()
+	d.watchers[changed] = true
+	d.mu.Unlock()
+	defer func() {
+		d.mu.Lock()
+		delete(d.watchers, changed)
+		d.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	rc := http.NewResponseController(w)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-changed:
+		}
+		d.mu.Lock()
+		data, err := json.Marshal(d.state)
+		d.mu.Unlock()
+		if err != nil {
+			return
+		}
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// start abandons the comparison under way, if any, and starts ex at n.
+func (d *dashboard) start(ex examples.Example, n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.cancel != nil {
+		d.cancel()
+	}
+	d.gen++
+	gen := d.gen
+	ctx, cancel := context.WithCancel(d.ctx)
+	d.cancel = cancel
+	d.state = liveState{Example: ex.Name, Title: ex.Title, Runs: d.runs, Running: true}
+	d.metrics.run(ex.Name, "dashboard")
+	ctx, span := trace.Start(trace.WithTracer(ctx, d.tracer), ex.Name, trace.String("source", "dashboard"))
+	finish := func(err error) {
+		span.End(err)
+		if err := flushTrace(d.tracer); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+		}
+		d.finish(gen, err)
+	}
+
+	if ex.Impls == nil {
+		d.impls, d.samples = nil, nil
+		go func() {
+			finish(ex.Run(ctx, outputWriter{d, gen}, nil))
+		}()
+		d.notify()
+		return
+	}
+
+	d.state.N = n
+	span.SetAttrs(trace.Int("n", n))
+	d.impls = ex.Impls(n)
+	d.samples = make([][]time.Duration, len(d.impls))
+	for _, tier := range ex.Lesson.Tiers {
+		if !slices.ContainsFunc(d.impls, func(impl bench.Implementation) bool { return impl.Name == tier.Label }) {
+			d.state.Skipped = append(d.state.Skipped, fmt.Sprintf("%s skipped: %s is impractical at this n", tier.Label, tier.Complexity))
+		}
+	}
+	impls, samples := d.impls, make(chan sample)
+	go func() {
+		measure(ctx, gen, impls, d.runs, samples)
+		close(samples)
+	}()
+	go func() {
+		var err error
+		for s := range samples {
+			if s.err != nil {
+				err = s.err
+				continue
+			}
+			d.record(s)
+		}
+		finish(err)
+	}()
+	d.notify()
+}
+
+// record adds one timed run to the state, unless a newer comparison has
+// replaced the one it belongs to.
+func (d *dashboard) record(s sample) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if s.gen != d.gen {
+		return
+	}
+	d.samples[s.index] = append(d.samples[s.index], s.d)
+	d.metrics.observe(d.state.Example, d.impls[s.index].Name, "dashboard", s.d)
+
+	d.state.Results = d.state.Results[:0]
+	d.state.Done = d.runs
+	for i, impl := range d.impls {
+		d.state.Done = min(d.state.Done, len(d.samples[i]))
+		if len(d.samples[i]) == 0 {
+			continue
+		}
+		d.state.Results = append(d.state.Results, liveResult{
+			Name:       impl.Name,
+			Complexity: impl.Complexity,
+			MedianMs:   float64(bench.Summarize(d.samples[i]).Median) / 1e6,
+			Runs:       len(d.samples[i]),
+		})
+	}
+	d.notify()
+}
+
+// finish marks comparison gen as over, with the error that ended it, if
+// it is still the one on screen. Being replaced is not an error.
+func (d *dashboard) finish(gen int, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if gen != d.gen {
+		return
+	}
+	d.state.Running = false
+	if err != nil && d.ctx.Err() == nil {
+		d.state.Error = err.Error()
+		d.metrics.fail(d.state.Example, "dashboard")
+	}
+	d.notify()
+}
+
+// notify tells every watcher the state changed. A watcher that hasn't
+// caught up with the last change yet will pick this one up with it. The
+// caller must hold d.mu.
+func (d *dashboard) notify() {
+	for changed := range d.watchers {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// outputWriter appends an example's text output to the state of
+// comparison gen.
+type outputWriter struct {
+	d   *dashboard
+	gen int
+}
+
+func (o outputWriter) Write(p []byte) (int, error) {
+	o.d.mu.Lock()
+	defer o.d.mu.Unlock()
+	if o.gen == o.d.gen {
+		o.d.state.Output += string(p)
+		o.d.notify()
+	}
+	return len(p), nil
+}
+
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>AI Coding: live comparison</title>
+<style>
+  body { font-family: system-ui, sans-serif; max-width: 1100px; margin: 2em auto; padding: 0 1em; color: #222; }
+  h1 { margin-bottom: 0.2em; }
+  .meta { color: #666; font-size: 0.9em; }
+  .layout { display: grid; grid-template-columns: 340px 1fr; gap: 2em; }
+  table { border-collapse: collapse; font-size: 0.9em; width: 100%; }
+  th, td { border: 1px solid #ddd; padding: 0.3em 0.6em; text-align: left; }
+  th { background: #f5f5f5; }
+  tr.example { cursor: pointer; }
+  tr.example:hover, tr.selected { background: #eef8ee; }
+  .controls { margin: 1em 0; }
+  .controls input { width: 8em; }
+  .bar-row { display: grid; grid-template-columns: 150px 1fr; align-items: center; margin: 0.4em 0; }
+  .bar { height: 22px; min-width: 2px; background: #f0ad4e; transition: width 0.3s; }
+  .bar.fastest { background: #5cb85c; }
+  .bar.slowest { background: #d9534f; }
+  .value { font-size: 0.85em; color: #444; margin-top: 2px; font-variant-numeric: tabular-nums; }
+  .note { color: #8a6d3b; background: #fcf8e3; padding: 0.4em 0.8em; border-left: 4px solid #f0ad4e; }
+  .error { color: #a94442; background: #f2dede; padding: 0.4em 0.8em; border-left: 4px solid #d9534f; }
+  pre { background: #f8f8f8; padding: 1em; max-height: 30em; overflow: auto; font-size: 0.8em; }
+</style>
+</head>
+<body>
+<h1>AI Coding: live comparison</h1>
+<p class="meta">Pick an example to run it here; everyone watching this page sees the same comparison.</p>
+<div class="layout">
+<div>
+<table>
+<tr><th>Example</th><th>Tiers</th></tr>
+{{range .}}<tr class="example" data-name="{{.Name}}" data-n="{{.DefaultN}}" title="{{.Description}}">
+<td><strong>{{.Name}}</strong><br>{{.Title}}</td>
+<td>{{range .Lesson.Tiers}}{{.Label}}: {{.Approach}}, {{.Complexity}}<br>{{end}}</td></tr>
+{{end}}</table>
+</div>
+<div>
+<h2 id="title">Choose an example</h2>
+<form class="controls" id="controls" hidden>
+  n = <input name="n" id="n">
+  <button type="button" id="halve">÷2</button>
+  <button type="button" id="double">×2</button>
+  <button type="submit">Run</button>
+</form>
+<p class="meta" id="status"></p>
+<div id="bars"></div>
+<div id="notes"></div>
+<pre id="output" hidden></pre>
+</div>
+</div>
+<script>
+const $ = id => document.getElementById(id);
+let current = {};
+
+function start(example, n) {
+  const body = new URLSearchParams({example});
+  if (n) body.set("n", n);
+  fetch("/start", {method: "POST", body});
+}
+
+document.querySelectorAll("tr.example").forEach(row => {
+  row.onclick = () => start(row.dataset.name, row.dataset.n !== "0" ? row.dataset.n : "");
+});
+$("controls").onsubmit = e => { e.preventDefault(); start(current.example, $("n").value); };
+$("halve").onclick = () => start(current.example, Math.max(1, Math.floor(current.n / 2)));
+$("double").onclick = () => start(current.example, current.n * 2);
+
+function render(s) {
+  current = s;
+  document.querySelectorAll("tr.example").forEach(row =>
+    row.classList.toggle("selected", row.dataset.name === s.example));
+  if (!s.example) return;
+  $("title").textContent = s.title + (s.n ? " at n = " + s.n : "");
+  $("controls").hidden = !s.n;
+  if (s.n && document.activeElement !== $("n")) $("n").value = s.n;
+  $("status").textContent = s.running
+    ? (s.n ? "Measuring: run " + Math.min(s.done + 1, s.runs) + " of " + s.runs : "Running...")
+    : (s.n ? "Median of " + s.done + " runs" : "Finished");
+
+  const results = s.results || [];
+  const slowest = Math.max(...results.map(r => r.median_ms), 0);
+  const fastest = Math.min(...results.map(r => r.median_ms));
+  $("bars").replaceChildren(...results.map(r => {
+    const row = document.createElement("div");
+    row.className = "bar-row";
+    const label = document.createElement("div");
+    label.textContent = r.name;
+    const cell = document.createElement("div");
+    const bar = document.createElement("div");
+    bar.className = "bar" + (r.median_ms === fastest ? " fastest" : r.median_ms === slowest ? " slowest" : "");
+    bar.style.width = (slowest > 0 ? 100 * r.median_ms / slowest : 0) + "%";
+    const value = document.createElement("div");
+    value.className = "value";
+    value.textContent = r.median_ms.toFixed(4) + " ms, " + r.complexity + " (" +
+      (r.median_ms === fastest ? "fastest" : (r.median_ms / fastest).toFixed(1) + "x slower") + ")";
+    cell.append(bar, value);
+    row.append(label, cell);
+    return row;
+  }));
+
+  const notes = (s.skipped || []).map(text => {
+    const p = document.createElement("p");
+    p.className = "note";
+    p.textContent = "⏭️ " + text;
+    return p;
+  });
+  if (s.error) {
+    const p = document.createElement("p");
+    p.className = "error";
+    p.textContent = "❌ " + s.error;
+    notes.push(p);
+  }
+  $("notes").replaceChildren(...notes);
+  $("output").hidden = !s.output;
+  $("output").textContent = s.output || "";
+}
+
+new EventSource("/events").onmessage = e => render(JSON.parse(e.data));
+</script>
+</body>
+</html>
+`))
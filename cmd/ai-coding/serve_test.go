@@ -0,0 +1,22 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/iportilla/ai-coding/examples"
+)
+
+// TestDashboardTemplate renders the dashboard against every registered
+// example, so a field the template names but Example no longer has fails
+// here rather than halfway through the page.
+func TestDashboardTemplate(t *testing.T) {
+	all := examples.All()
+	if len(all) == 0 {
+		t.Fatal("no examples registered")
+	}
+	var b strings.Builder
+	if err := dashboardTemplate.Execute(&b, all); err != nil {
+		t.Fatal(err)
+	}
+}
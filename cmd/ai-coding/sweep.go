@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// sweepCmd times one example's implementations across a geometric range
+// of n instead of the few sizes its Run compares, appends every median
+// to a CSV file, one row per implementation and n, and plots the curves
+// on log-log axes with the complexity each one fits best.
+func sweepCmd(ctx context.Context, w io.Writer, args []string) error {
+	if len(args) == 0 {
+		return errors.New("sweep: missing example name (see 'ai-coding list')")
+	}
+	ex, err := examples.Lookup(args[0])
+	if err != nil {
+		return fmt.Errorf("%w (see 'ai-coding list')", err)
+	}
+	if ex.Impls == nil {
+		var names []string
+		for _, e := range examples.All() {
+			if e.Impls != nil {
+				names = append(names, e.Name)
+			}
+		}
+		return fmt.Errorf("sweep: %s can't be timed at a chosen n; try one of %s", ex.Name, strings.Join(names, ", "))
+	}
+
+	fs := flag.NewFlagSet("sweep", flag.ContinueOnError)
+	from := fs.String("from", "", "smallest n (default: the example's default n / 100)")
+	to := fs.String("to", "", "largest n (default: the example's default n × 10)")
+	perDecade := fs.Int("per-decade", 3, "sizes per power of ten, spaced evenly on a log scale")
+	runs := fs.Int("runs", 5, "timed runs per implementation at each n (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per implementation at each n")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an implementation that takes longer than this at one size, e.g. 10s, and leave it out of larger sizes (0 means never)")
+	timeout := fs.Duration("timeout", 0, "stop after this long and report the sizes finished so far, e.g. 5m (0 means no limit)")
+	csvPath := fs.String("csv", "sweep.csv", "append one row per implementation and n to this CSV file (empty writes none)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	start, end := max(ex.DefaultN/100, 1), max(ex.DefaultN*10, 1)
+	if *from != "" {
+		if start, err = bench.ParseSize(*from); err != nil {
+			return fmt.Errorf("sweep: -from: %w", err)
+		}
+	}
+	if *to != "" {
+		if end, err = bench.ParseSize(*to); err != nil {
+			return fmt.Errorf("sweep: -to: %w", err)
+		}
+	}
+	if ex.MaxN > 0 && end > ex.MaxN {
+		fmt.Fprintf(w, "⚠️  %s times n up to %d; stopping the sweep there\n", ex.Name, ex.MaxN)
+		end = ex.MaxN
+	}
+	sizes := bench.GeometricSizes(start, end, *perDecade)
+	if len(sizes) == 0 {
+		return fmt.Errorf("sweep: no sizes from %d to %d: want 1 ≤ -from ≤ -to and -per-decade ≥ 1", start, end)
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "SWEEP: %s, %d sizes from n=%d to n=%d\n", ex.Title, len(sizes), sizes[0], sizes[len(sizes)-1])
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	series, sweepErr := bench.SweepContext(ctx, opts, sizes, func(n int) []bench.Implementation {
+		fmt.Fprintf(w, "  timing n=%d...\n", n)
+		return ex.Impls(n)
+	})
+	if sweepErr != nil && len(series) == 0 {
+		return fmt.Errorf("sweep: %w", sweepErr)
+	}
+
+	csvLog := report.NewCSVLog(*csvPath, ex.Name)
+	rows := 0
+	for _, s := range series {
+		for i, n := range s.Sizes {
+			if s.Results[i].DNF {
+				continue // no timing to record
+			}
+			if err := csvLog.Append("", uint64(n), s.Results[i:i+1]); err != nil {
+				return fmt.Errorf("sweep: csv: %w", err)
+			}
+			rows++
+		}
+	}
+
+	fmt.Fprintln(w)
+	for _, s := range series {
+		last := len(s.Results) - 1
+		finished := len(s.Sizes)
+		if s.Results[last].DNF {
+			finished--
+		}
+		fit := "too few to fit a curve"
+		if finished >= 3 {
+			best := s.Fit()[0]
+			fit = fmt.Sprintf("best fit %s (error %.3f)", best.Curve.Name, best.Error)
+		}
+		fmt.Fprintf(w, "  %s (%s): %d sizes, %s\n", s.Name, s.Complexity, finished, fit)
+		if s.Results[last].DNF {
+			fmt.Fprintf(w, "    ⏱️  %s at n=%d, so no larger n was tried\n", s.Results[last].DNFLabel(), s.Sizes[last])
+		}
+	}
+	report.WritePlot(w, series)
+	if csvLog != nil {
+		fmt.Fprintf(w, "\n📄 %d rows appended to %s\n", rows, *csvPath)
+	}
+	if sweepErr != nil {
+		return fmt.Errorf("sweep: stopped early, after the sizes above: %w", sweepErr)
+	}
+	return nil
+}
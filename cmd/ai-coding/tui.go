@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// key is a key press the TUI acts on.
+type key int
+
+const (
+	keyUp key = iota + 1
+	keyDown
+	keyLeft
+	keyRight
+	keyMore
+	keyLess
+	keyRerun
+	keyQuit
+)
+
+// tui is the state of the interactive terminal UI: which example is
+// selected, the n chosen for each, and the runs measured so far.
+type tui struct {
+	list []examples.Example
+	ns   []int
+	sel  int
+	runs int
+
+	gen     int
+	cancel  context.CancelFunc
+	impls   []bench.Implementation
+	samples [][]time.Duration
+	err     error
+}
+
+// tuiCmd runs the interactive terminal UI: pick an example with the up
+// and down arrows, change n with left and right, and watch the bars
+// redraw as each run finishes. The terminal is driven with stty and ANSI
+// escape codes, so it needs a Unix-like terminal but no libraries.
+func tuiCmd(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("tui", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per implementation at each n (median is shown)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	t := &tui{runs: max(*runs, 1)}
+	for _, ex := range examples.All() {
+		if ex.Impls != nil {
+			t.list = append(t.list, ex)
+			t.ns = append(t.ns, max(ex.DefaultN, 1))
+		}
+	}
+	if len(t.list) == 0 {
+		return errors.New("tui: no examples can be timed at a chosen n")
+	}
+
+	restore, err := rawTerminal()
+	if err != nil {
+		return fmt.Errorf("tui: %w", err)
+	}
+	defer restore()
+
+	keys := make(chan key)
+	go readKeys(os.Stdin, keys)
+	samples := make(chan sample)
+	t.start(ctx, samples)
+	defer func() { t.cancel() }()
+
+	for {
+		t.draw(w)
+		select {
+		case <-ctx.Done():
+			return nil
+		case s := <-samples:
+			if s.gen != t.gen {
+				continue
+			}
+			if s.err != nil {
+				t.err = s.err
+				continue
+			}
+			t.samples[s.index] = append(t.samples[s.index], s.d)
+		case k, ok := <-keys:
+			if !ok || k == keyQuit {
+				return nil
+			}
+			n, limit := t.ns[t.sel], liveLimit(t.list[t.sel])
+			switch k {
+			case keyUp:
+				t.sel = (t.sel + len(t.list) - 1) % len(t.list)
+			case keyDown:
+				t.sel = (t.sel + 1) % len(t.list)
+			case keyLeft:
+				t.ns[t.sel] = max(n/2, 1)
+			case keyRight:
+				t.ns[t.sel] = min(n*2, limit)
+			case keyLess:
+				t.ns[t.sel] = max(n-max(n/10, 1), 1)
+			case keyMore:
+				t.ns[t.sel] = min(n+max(n/10, 1), limit)
+			}
+			t.start(ctx, samples)
+		}
+	}
+}
+
+// start abandons the measurement under way, if any, and starts timing
+// the selected example at its chosen n.
+func (t *tui) start(ctx context.Context, samples chan<- sample) {
+	if t.cancel != nil {
+		t.cancel()
+	}
+	ex := t.list[t.sel]
+	t.gen++
+	t.impls = ex.Impls(t.ns[t.sel])
+	t.samples = make([][]time.Duration, len(t.impls))
+	t.err = nil
+
+	ctx, t.cancel = context.WithCancel(ctx)
+	go measure(ctx, t.gen, t.impls, t.runs, samples)
+}
+
+// draw redraws the whole screen: the examples, the bars for the selected
+// one, and the keys.
+func (t *tui) draw(w io.Writer) {
+	var b bytes.Buffer
+	ex := t.list[t.sel]
+	fmt.Fprintln(&b, "AI CODING: live comparison")
+	fmt.Fprintln(&b, strings.Repeat("=", 60))
+	for i, e := range t.list {
+		marker := "  "
+		if i == t.sel {
+			marker = "▸ "
+		}
+		fmt.Fprintf(&b, "%s%-22s %s\n", marker, e.Name, e.Title)
+	}
+	fmt.Fprintln(&b, strings.Repeat("-", 60))
+
+	done := t.runs
+	for _, s := range t.samples {
+		done = min(done, len(s))
+	}
+	status := fmt.Sprintf("run %d of %d", min(done+1, t.runs), t.runs)
+	if done == t.runs {
+		status = fmt.Sprintf("median of %d runs", t.runs)
+	}
+	fmt.Fprintf(&b, "%s at n = %d   (%s)\n", ex.Title, t.ns[t.sel], status)
+
+	var results []bench.Result
+	var waiting []string
+	for i, impl := range t.impls {
+		if len(t.samples[i]) == 0 {
+			waiting = append(waiting, impl.Name)
+			continue
+		}
+		stats := bench.Summarize(t.samples[i])
+		results = append(results, bench.Result{Name: impl.Name, Complexity: impl.Complexity, Duration: stats.Median, Stats: stats})
+	}
+	if len(results) > 0 {
+		report.WriteBars(&b, results)
+	} else {
+		fmt.Fprintln(&b)
+	}
+	for _, name := range waiting {
+		fmt.Fprintf(&b, "  %s: measuring...\n", name)
+	}
+	for _, tier := range ex.Lesson.Tiers {
+		if !slices.ContainsFunc(t.impls, func(impl bench.Implementation) bool { return impl.Name == tier.Label }) {
+			fmt.Fprintf(&b, "  ⏭️  %s skipped: %s is impractical at this n\n", tier.Label, tier.Complexity)
+		}
+	}
+	if t.err != nil {
+		fmt.Fprintf(&b, "  ❌ %v\n", t.err)
+	}
+
+	fmt.Fprintln(&b, strings.Repeat("-", 60))
+	fmt.Fprintln(&b, "↑/↓ example   ←/→ halve/double n   -/+ n by 10%   r re-run   q quit")
+
+	// Home the cursor and overwrite in place, clearing what is left of
+	// every line and below the last, so the screen doesn't flicker.
+	frame := strings.ReplaceAll(b.String(), "\n", "\x1b[K\n")
+	fmt.Fprint(w, "\x1b[H"+frame+"\x1b[J")
+}
+
+// rawTerminal switches the terminal on standard input to passing on
+// every key press at once, without echoing it, and to the alternate
+// screen, and returns the function that switches both back. It shells
+// out to stty rather than depend on a terminal library; Ctrl-C still
+// interrupts.
+func rawTerminal() (restore func(), err error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, errors.New("needs an interactive terminal (stty failed)")
+	}
+	if _, err := stty("-icanon", "-echo", "min", "1"); err != nil {
+		return nil, err
+	}
+	fmt.Print("\x1b[?1049h\x1b[?25l") // alternate screen, hidden cursor
+	return func() {
+		fmt.Print("\x1b[?25h\x1b[?1049l")
+		stty(strings.TrimSpace(saved))
+	}, nil
+}
+
+// stty runs stty on the terminal on standard input.
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// readKeys sends the keys pressed on r to keys, closing it once r fails.
+func readKeys(r io.Reader, keys chan<- key) {
+	defer close(keys)
+	buf := make([]byte, 64)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			return
+		}
+		for b := buf[:n]; len(b) > 0; {
+			k, size := parseKey(b)
+			b = b[size:]
+			if k != 0 {
+				keys <- k
+			}
+		}
+	}
+}
+
+// parseKey decodes the key press at the start of b, returning 0 for keys
+// the TUI ignores, and how many bytes it took up. Arrow keys arrive as
+// ESC [ A to ESC [ D, or ESC O A to ESC O D; vi's hjkl work too.
+func parseKey(b []byte) (key, int) {
+	if len(b) >= 3 && b[0] == 0x1b && (b[1] == '[' || b[1] == 'O') {
+		switch b[2] {
+		case 'A':
+			return keyUp, 3
+		case 'B':
+			return keyDown, 3
+		case 'C':
+			return keyRight, 3
+		case 'D':
+			return keyLeft, 3
+		}
+		return 0, 3
+	}
+	switch b[0] {
+	case 'k':
+		return keyUp, 1
+	case 'j':
+		return keyDown, 1
+	case 'l':
+		return keyRight, 1
+	case 'h':
+		return keyLeft, 1
+	case '+', '=':
+		return keyMore, 1
+	case '-', '_':
+		return keyLess, 1
+	case 'r':
+		return keyRerun, 1
+	case 'q', 'Q', 0x1b:
+		return keyQuit, 1
+	}
+	return 0, 1
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/primes"
+)
+
+// Above these limits the slow tiers are left out of a fuzz iteration,
+// otherwise a single large n would take minutes.
+const (
+	verifyMaxVibeN  = 20_000
+	verifyMaxHumanN = 1_000_000
+)
+
+// verifyCmd cross-checks every prime implementation against the
+// reference sieve on edge cases and random n, so a fast-but-wrong
+// implementation is caught even when no example happens to exercise the
+// n that breaks it.
+func verifyCmd(ctx context.Context, stdout, stderr io.Writer, args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	iterations := fs.Int("iterations", 200, "number of random n values to check")
+	maxN := fs.String("max", "2e6", "largest random n to check")
+	seed := fs.Uint64("seed", 0, "random seed (0 picks one at random)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	limit, err := bench.ParseSize(*maxN)
+	if err != nil {
+		return fmt.Errorf("verify: -max: %w", err)
+	}
+	if *seed == 0 {
+		*seed = rand.Uint64()
+	}
+	rng := rand.New(rand.NewPCG(*seed, 0))
+
+	// Edge cases first: no primes, the first primes, and the boundaries of
+	// the segmented sieve's windows where off-by-one bugs like to hide.
+	values := []int{-5, 0, 1, 2, 3, 4, 5, 100}
+	for _, k := range []int{1, 2, 3} {
+		w := 1 + 2*primes.SegmentSize*k // first odd number of window k+1
+		values = append(values, w-2, w-1, w, w+1)
+	}
+	for range *iterations {
+		values = append(values, rng.IntN(limit+1))
+	}
+
+	fmt.Fprintf(stdout, "Verifying %d values of n (seed %d)...\n", len(values), *seed)
+	for _, n := range values {
+		var impls []primes.Implementation
+		for _, impl := range primes.Implementations() {
+			if impl.Name == "VibeFindPrimes" && n > verifyMaxVibeN ||
+				impl.Name == "HumanFindPrimes" && n > verifyMaxHumanN {
+				continue
+			}
+			impls = append(impls, impl)
+		}
+		if err := primes.VerifyContext(ctx, n, impls...); err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("verify: %w", err)
+			}
+			fmt.Fprintf(stderr, "❌ %v\n", err)
+			return fmt.Errorf("verify: mismatch found (reproduce with -seed %d)", *seed)
+		}
+	}
+	fmt.Fprintf(stdout, "✔ All %d prime implementations agree on every value\n", len(primes.Implementations()))
+	return nil
+}
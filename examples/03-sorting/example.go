@@ -0,0 +1,258 @@
+// Package sorting compares three ways to sort a slice of integers.
+package sorting
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// Bubble sort above this size takes seconds per run on random input.
+const maxVibeN = 20_000
+
+// inputKind is one shape of input data; sorting algorithms that look
+// alike on random data can behave very differently on structured data.
+type inputKind struct {
+	name     string
+	generate func(seed input.Seed, n int) []int
+}
+
+var inputKinds = []inputKind{
+	{"random", func(seed input.Seed, n int) []int {
+		return seed.Ints("random", n, max(n, 1))
+	}},
+	{"already sorted", func(_ input.Seed, n int) []int {
+		a := make([]int, n)
+		for i := range a {
+			a[i] = i
+		}
+		return a
+	}},
+	{"reversed", func(_ input.Seed, n int) []int {
+		a := make([]int, n)
+		for i := range a {
+			a[i] = n - i
+		}
+		return a
+	}},
+}
+
+type sorter struct {
+	name, complexity string
+	sort             func([]int)
+}
+
+// sortersFor returns the sorters to compare on n elements, leaving out
+// bubble sort where it would take seconds.
+func sortersFor(n int) []sorter {
+	sorters := []sorter{}
+	if n <= maxVibeN {
+		sorters = append(sorters, sorter{"Vibe coding", "bubble sort, O(n²)", vibeSort})
+	}
+	return append(sorters,
+		sorter{"Human coding", "quicksort, O(n log n) avg", humanSort},
+		sorter{"Expert coding", "pdqsort, O(n log n)", expertSort},
+	)
+}
+
+// timed returns an implementation that sorts a fresh copy of input on
+// every run; the O(n) copy is noise next to the sort itself.
+func (s sorter) timed(input []int) bench.Implementation {
+	buf := make([]int, len(input))
+	return bench.Implementation{
+		Name: s.name, Complexity: s.complexity,
+		Run: func() { copy(buf, input); s.sort(buf) },
+	}
+}
+
+// impls returns the sorters as implementations timed on n random
+// integers.
+func impls(n int) []bench.Implementation {
+	data := inputKinds[0].generate(input.DefaultSeed, n)
+	var list []bench.Implementation
+	for _, s := range sortersFor(n) {
+		list = append(list, s.timed(data))
+	}
+	return list
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "bubble sort", Complexity: "O(n²)", Notes: []report.Note{
+			report.Pitfall("O(n²) comparisons on random input"),
+			report.Pitfall("Unusable beyond a few tens of thousands of elements"),
+			report.Strength("Trivial to write, and O(n) on already-sorted input"),
+		}},
+		{Label: "Human coding", Approach: "median-of-three quicksort", Complexity: "O(n log n) avg", Notes: []report.Note{
+			report.Strength("Median-of-three pivot avoids the classic sorted-input O(n²) trap"),
+			report.Pitfall("Still O(n²) in adversarial cases"),
+			report.Pitfall("Easy to get subtly wrong (partition bounds, recursion depth)"),
+		}},
+		{Label: "Expert coding", Approach: "slices.Sort (pdqsort)", Complexity: "O(n log n)", Notes: []report.Note{
+			report.Strength("O(n log n) worst case, O(n) on sorted and reversed runs"),
+			report.Strength("Battle-tested, generic, no interface overhead"),
+			report.Strength("One line of code"),
+		}},
+	},
+	Takeaway: "Sorting is a solved problem - use the standard library. Write your own " +
+		"only to learn, and always test on sorted, reversed and duplicate-heavy " +
+		"data, not just random input.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "03-sorting",
+		Title:       "Sorting Algorithms",
+		Description: "Sort a slice of integers, and see why random test data hides worst cases.",
+		Category:    "sorting",
+		Difficulty:  examples.Beginner,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    1_000,
+		Exercise:    exerciseSpec,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("03-sorting", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{1_000, 10_000, 100_000}
+	fs.Var(&sizes, "n", "comma-separated input sizes, e.g. 1e4,1e5,1e6")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Sorting Algorithms", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Sorting Algorithms")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "Random inputs from seed %d\n", *seed)
+
+	for _, n := range sizes {
+		for _, kind := range inputKinds {
+			fmt.Fprintf(out.Table, "\nSorting %d integers (%s):\n", n, kind.name)
+			fmt.Fprintln(w, strings.Repeat("-", 60))
+
+			data := kind.generate(*seed, n)
+			sorters := sortersFor(n)
+
+			// Cross-check every algorithm before timing it.
+			want := slices.Sorted(slices.Values(data))
+			for _, s := range sorters {
+				got := slices.Clone(data)
+				s.sort(got)
+				if err := bench.DiffSlices(got, want); err != nil {
+					return fmt.Errorf("verification failed: %s: %w", s.name, err)
+				}
+			}
+			fmt.Fprintf(w, "✔ All %d implementations agree\n", len(sorters))
+
+			impls := make([]bench.Implementation, len(sorters))
+			for i, s := range sorters {
+				impls[i] = s.timed(data)
+			}
+			results, err := bench.CompareContext(ctx, opts, impls...)
+			if err != nil {
+				return err
+			}
+			if err := csvLog.Append(kind.name, uint64(n), results); err != nil {
+				return fmt.Errorf("csv: %w", err)
+			}
+			benchLog.Append(kind.name, uint64(n), results)
+			bench.Print(out.Table, results)
+			report.WriteBars(w, results)
+			bench.PrintRuns(out.Detail, results)
+			section := rep.Add(fmt.Sprintf("n = %d, %s", n, kind.name), results)
+
+			if n > maxVibeN {
+				note := fmt.Sprintf("Vibe coding skipped: bubble sort is impractical above n=%d", maxVibeN)
+				fmt.Fprintln(w, "  ⏭️  "+note)
+				section.Notes = append(section.Notes, note)
+			} else if vibe, human := results[0], results[1]; vibe.Duration > human.Duration {
+				fmt.Fprintf(w, "  ❌ Vibe is %.1fx slower than Human\n", bench.Speedup(vibe, human))
+			} else {
+				fmt.Fprintf(w, "  💡 Bubble sort's early exit makes it %.1fx faster here!\n", bench.Speedup(human, vibe))
+			}
+			if human, expert := results[len(results)-2], results[len(results)-1]; human.Duration > expert.Duration {
+				fmt.Fprintf(w, "  ✅ Expert is %.1fx faster than Human\n", bench.Speedup(human, expert))
+			}
+		}
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		input []int
+		desc  string
+	}{
+		{[]int{}, "empty slice"},
+		{[]int{42}, "single element"},
+		{[]int{3, 3, 3, 3}, "all equal"},
+		{[]int{2, -1, 0, -7, 5}, "negative numbers"},
+	}
+	for _, tc := range edgeCases {
+		ok := true
+		for _, sort := range []func([]int){vibeSort, humanSort, expertSort} {
+			got := slices.Clone(tc.input)
+			sort(got)
+			ok = ok && slices.IsSorted(got)
+		}
+		sorted := slices.Sorted(slices.Values(tc.input))
+		status := "✅"
+		if !ok {
+			status = "❌"
+		}
+		fmt.Fprintf(w, "%s %s: %v -> %v\n", status, tc.desc, tc.input, sorted)
+		rep.AddEdgeCase(tc.desc, fmt.Sprint(sorted))
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
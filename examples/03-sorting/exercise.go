@@ -0,0 +1,64 @@
+package sorting
+
+import (
+	_ "embed"
+	"fmt"
+	"slices"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	exercise "github.com/iportilla/ai-coding/exercises/03-sorting"
+	"github.com/iportilla/ai-coding/input"
+)
+
+//go:embed sorting.go
+var source string
+
+// exerciseN is the size solutions are timed at: bubble sort takes a
+// tenth of a second or so, a good sort well under a millisecond.
+const exerciseN = 10_000
+
+// exerciseSpec is the example as a task: write exercise.Sort.
+var exerciseSpec = &examples.Exercise{
+	File:      "exercises/03-sorting/solution.go",
+	Signature: "func Sort(a []int)",
+	Vibe:      examples.FuncSource(source, "vibeSort"),
+	N:         exerciseN,
+	Target:    2,
+	Check:     checkExercise,
+	Impls: func(n int) (solution, expert bench.Implementation) {
+		data := inputKinds[0].generate(input.DefaultSeed, n)
+		return sorter{"Your solution", "", exercise.Sort}.timed(data),
+			sorter{"Expert coding", "", expertSort}.timed(data)
+	},
+}
+
+// checkExercise sorts every kind of input at every size up to 64, and a
+// few larger ones, with exercise.Sort and compares the result with the
+// expert's. Random inputs are drawn from [0, n), so they repeat values.
+func checkExercise(seed input.Seed) error {
+	var sizes []int
+	for n := range 65 {
+		sizes = append(sizes, n)
+	}
+	for _, n := range append(sizes, 100, 1_000, exerciseN) {
+		for _, kind := range inputKinds {
+			in := kind.generate(seed, n)
+			got, want := slices.Clone(in), slices.Clone(in)
+			exercise.Sort(got)
+			expertSort(want)
+			if slices.Equal(got, want) {
+				continue
+			}
+			if n <= 16 {
+				return fmt.Errorf("Sort(%v) gives %v, want %v", in, got, want)
+			}
+			i := 0
+			for got[i] == want[i] {
+				i++
+			}
+			return fmt.Errorf("sorting %d %s integers gives %d at index %d, want %d", n, kind.name, got[i], i, want[i])
+		}
+	}
+	return nil
+}
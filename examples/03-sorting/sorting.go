@@ -0,0 +1,99 @@
+package sorting
+
+import "slices"
+
+// VIBE CODING: Bubble sort - the first algorithm everyone learns
+func vibeSort(a []int) {
+	/*
+	   Sort a in place by repeatedly swapping adjacent out-of-order pairs
+
+	   Each pass bubbles the largest remaining element to the end. Stops
+	   early when a pass makes no swaps, so already-sorted input is fast.
+	*/
+	for end := len(a) - 1; end > 0; end-- {
+		swapped := false
+		for i := 0; i < end; i++ {
+			if a[i] > a[i+1] {
+				a[i], a[i+1] = a[i+1], a[i]
+				swapped = true
+			}
+		}
+		if !swapped {
+			return
+		}
+	}
+	// O(n²) comparisons and swaps on random input
+}
+
+// HUMAN CODING: Quicksort with median-of-three pivot selection
+func humanSort(a []int) {
+	/*
+	   Sort a in place with a hand-written quicksort
+
+	   Thoughtful touches a careful human adds:
+	   1. Median-of-three pivot, so sorted/reversed input isn't O(n²)
+	   2. Hoare partitioning, which copes well with duplicates
+	   3. Recurse into the smaller half, loop on the larger: O(log n) stack
+	   4. Insertion sort for tiny ranges, where recursion costs more
+	*/
+	for len(a) > 12 {
+		p := partition(a)
+		if p < len(a)-p {
+			humanSort(a[:p])
+			a = a[p:]
+		} else {
+			humanSort(a[p:])
+			a = a[:p]
+		}
+	}
+	insertionSort(a)
+	// O(n log n) on average
+}
+
+// partition splits a around a median-of-three pivot and returns p such
+// that every element of a[:p] is ≤ every element of a[p:].
+func partition(a []int) int {
+	lo, mid, hi := 0, len(a)/2, len(a)-1
+	if a[mid] < a[lo] {
+		a[mid], a[lo] = a[lo], a[mid]
+	}
+	if a[hi] < a[lo] {
+		a[hi], a[lo] = a[lo], a[hi]
+	}
+	if a[hi] < a[mid] {
+		a[hi], a[mid] = a[mid], a[hi]
+	}
+	pivot := a[mid]
+
+	i, j := -1, len(a)
+	for {
+		for i++; a[i] < pivot; i++ {
+		}
+		for j--; a[j] > pivot; j-- {
+		}
+		if i >= j {
+			return j + 1
+		}
+		a[i], a[j] = a[j], a[i]
+	}
+}
+
+func insertionSort(a []int) {
+	for i := 1; i < len(a); i++ {
+		for j := i; j > 0 && a[j] < a[j-1]; j-- {
+			a[j], a[j-1] = a[j-1], a[j]
+		}
+	}
+}
+
+// EXPERT CODING: The standard library's pattern-defeating quicksort
+func expertSort(a []int) {
+	/*
+	   Sort a in place with slices.Sort
+
+	   pdqsort (also behind sort.Slice) combines quicksort, insertion
+	   sort and heapsort: O(n log n) worst case, O(n) on sorted or
+	   reversed runs, and it's generic so there's no interface overhead.
+	*/
+	slices.Sort(a)
+}
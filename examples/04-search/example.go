@@ -0,0 +1,286 @@
+// Package search compares three ways to find a value in a sorted slice.
+package search
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// Every timed run looks up this many targets; one lookup alone is too
+// quick to time.
+const lookupsPerRun = 1_000
+
+// A linear scan reads ~n/2 elements per lookup: 5 billion reads per run
+// at n=10,000,000.
+const maxVibeN = 1_000_000
+
+// workload is a sorted slice and the targets one timed run looks up.
+type workload struct {
+	xs      []int
+	targets []int
+}
+
+// makeWorkload returns n sorted values from [0, 4n), so some repeat, and
+// lookupsPerRun targets from a slightly wider range, so some miss.
+func makeWorkload(seed input.Seed, n int) workload {
+	rng := seed.Rand("workload", n)
+	xs := make([]int, n)
+	for i := range xs {
+		xs[i] = rng.IntN(4 * n)
+	}
+	slices.Sort(xs)
+	targets := make([]int, lookupsPerRun)
+	for i := range targets {
+		targets[i] = rng.IntN(4*n+2) - 1
+	}
+	return workload{xs, targets}
+}
+
+// lookupAll turns a single-target search into one answering every target
+// of a workload, returning the index found for each.
+func lookupAll(search func([]int, int) int) func(workload) []int {
+	return func(wl workload) []int {
+		found := make([]int, len(wl.targets))
+		for i, t := range wl.targets {
+			found[i] = search(wl.xs, t)
+		}
+		return found
+	}
+}
+
+// searchers lists the implementations under test, in tier order.
+var searchers = []struct {
+	name, complexity string
+	search           func([]int, int) int
+}{
+	{"Vibe coding", "linear scan, O(n)", vibeSearch},
+	{"Human coding", "binary search, O(log n)", humanSearch},
+	{"Expert coding", "sort.SearchInts, O(log n)", expertSearch},
+}
+
+// searchesFor returns the searches to compare on a slice of n values,
+// leaving out the linear scan where it would take seconds per run.
+func searchesFor(n int) []bench.Impl[workload, []int] {
+	impls := []bench.Impl[workload, []int]{}
+	for _, s := range searchers {
+		if s.name == "Vibe coding" && n > maxVibeN {
+			continue
+		}
+		impls = append(impls, bench.Impl[workload, []int]{Name: s.name, Complexity: s.complexity, Func: lookupAll(s.search)})
+	}
+	return impls
+}
+
+// impls returns the searches as implementations timed on n random sorted
+// values.
+func impls(n int) []bench.Implementation {
+	wl := makeWorkload(input.DefaultSeed, n)
+	var list []bench.Implementation
+	for _, s := range searchesFor(n) {
+		list = append(list, s.Implementation(wl))
+	}
+	return list
+}
+
+// fuzz cross-checks the three searches on random sorted slices. The
+// slices are short and drawn from a narrow range, so empty slices, runs
+// of duplicates and targets outside the range all come up often.
+func fuzz(rng *rand.Rand, iterations int) error {
+	for range iterations {
+		n := rng.IntN(64)
+		xs := make([]int, n)
+		for i := range xs {
+			xs[i] = rng.IntN(2*n+1) - n
+		}
+		slices.Sort(xs)
+
+		for target := -n - 1; target <= n+1; target++ {
+			want := searchers[0].search(xs, target)
+			for _, s := range searchers[1:] {
+				if got := s.search(xs, target); got != want {
+					return fmt.Errorf("%s returns %d, %s returns %d, searching for %d in %v",
+						s.name, got, searchers[0].name, want, target, xs)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "linear scan", Complexity: "O(n)", Notes: []report.Note{
+			report.Pitfall("Reads up to n elements per lookup, all n on a miss"),
+			report.Pitfall("Ignores that the slice is sorted"),
+			report.Strength("Impossible to get wrong, works on unsorted data too"),
+		}},
+		{Label: "Human coding", Approach: "hand-rolled binary search", Complexity: "O(log n)", Notes: []report.Note{
+			report.Strength("Halves the candidates each step"),
+			report.Strength("20 comparisons for a million elements, 30 for a billion"),
+			report.Pitfall("Easy to get subtly wrong: off-by-one bounds, infinite loops, the (lo+hi)/2 overflow, returning any match instead of the first"),
+		}},
+		{Label: "Expert coding", Approach: "sort.SearchInts", Complexity: "O(log n)", Notes: []report.Note{
+			report.Strength("The same O(log n) algorithm, already correct"),
+			report.Strength("Returns the insertion point, useful for ranges and inserts"),
+			report.Strength("slices.BinarySearch and sort.Search generalise it to any type"),
+		}},
+	},
+	Takeaway: "Sorted data turns O(n) into O(log n) - the biggest win here. Writing " +
+		"the binary search yourself buys nothing but risk; fuzz it against a " +
+		"trivially correct version if you must.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "04-search",
+		Title:       "Searching a Sorted Slice",
+		Description: "Find values in a sorted slice by scanning, by hand-rolled binary search and with sort.SearchInts.",
+		Category:    "searching",
+		Difficulty:  examples.Beginner,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    10_000,
+		Exercise:    exerciseSpec,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("04-search", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{100, 10_000, 1_000_000}
+	fs.Var(&sizes, "n", "comma-separated slice lengths, e.g. 1e4,1e7")
+	iterations := fs.Int("fuzz", 1_000, "random sorted slices to cross-check the searches on before timing")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Searching a Sorted Slice", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Searching a Sorted Slice")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	// Off-by-one errors hide in binary search for years, so fuzz before
+	// trusting any timing.
+	fmt.Fprintf(w, "\nFuzzing %d random sorted slices (seed %d)...\n", *iterations, *seed)
+	if err := fuzz(seed.Rand("fuzz", 0), *iterations); err != nil {
+		return fmt.Errorf("verification failed: %w (reproduce with -seed %d)", err, *seed)
+	}
+	fmt.Fprintf(w, "✔ All %d implementations agree on every target\n", len(searchers))
+
+	for _, n := range sizes {
+		fmt.Fprintf(out.Table, "\nSearching %d sorted integers (%d lookups per run):\n", n, lookupsPerRun)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		wl := makeWorkload(*seed, n)
+		want := lookupAll(expertSearch)(wl)
+		results, err := bench.CompareImpls(ctx, opts, wl, want, bench.DiffSlices, searchesFor(n)...)
+		if err != nil {
+			return err
+		}
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d", n), results)
+
+		human, expert := results[len(results)-2], results[len(results)-1]
+		if n > maxVibeN {
+			note := fmt.Sprintf("Vibe coding skipped: a linear scan is impractical above n=%d", maxVibeN)
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		} else if vibe := results[0]; vibe.Duration > human.Duration {
+			fmt.Fprintf(w, "  ❌ Vibe is %.1fx slower than Human\n", bench.Speedup(vibe, human))
+		}
+		fmt.Fprintf(w, "  🔢 Per lookup: ~%d comparisons for binary search, up to %d for a scan (all of them on a miss)\n",
+			bits.Len(uint(n)), n)
+		fmt.Fprintf(w, "  🤝 Hand-rolled and library binary search: %.4fms vs %.4fms - same algorithm\n",
+			human.Milliseconds(), expert.Milliseconds())
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		xs     []int
+		target int
+		desc   string
+	}{
+		{nil, 5, "empty slice"},
+		{[]int{5}, 5, "single element, present"},
+		{[]int{5}, 7, "single element, absent"},
+		{[]int{1, 3, 5}, 0, "target below every element"},
+		{[]int{1, 3, 5}, 9, "target above every element"},
+		{[]int{2, 4, 4, 4, 4, 6}, 4, "duplicates (first index wins)"},
+	}
+	for _, tc := range edgeCases {
+		got := []int{vibeSearch(tc.xs, tc.target), humanSearch(tc.xs, tc.target), expertSearch(tc.xs, tc.target)}
+		status := "✅"
+		if got[0] != got[1] || got[1] != got[2] {
+			status = "❌"
+		}
+		fmt.Fprintf(w, "%s %s: %d in %v → %d\n", status, tc.desc, tc.target, tc.xs, got[2])
+		rep.AddEdgeCase(tc.desc, fmt.Sprint(got[2]))
+	}
+
+	// The midpoint bug, shown with int32 bounds where it is easy to hit.
+	lo, hi := int32(math.MaxInt32-10), int32(math.MaxInt32)
+	fmt.Fprintf(w, "\nMidpoint of lo=%d and hi=%d:\n", lo, hi)
+	fmt.Fprintf(w, "  ❌ (lo+hi)/2    = %d - the sum overflowed\n", (lo+hi)/2)
+	fmt.Fprintf(w, "  ✅ lo+(hi-lo)/2 = %d\n", lo+(hi-lo)/2)
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
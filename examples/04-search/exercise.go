@@ -0,0 +1,57 @@
+package search
+
+import (
+	_ "embed"
+	"fmt"
+	"slices"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	exercise "github.com/iportilla/ai-coding/exercises/04-search"
+	"github.com/iportilla/ai-coding/input"
+)
+
+//go:embed search.go
+var source string
+
+// exerciseSpec is the example as a task: write exercise.Search.
+var exerciseSpec = &examples.Exercise{
+	File:      "exercises/04-search/solution.go",
+	Signature: "func Search(xs []int, target int) int",
+	Vibe:      examples.FuncSource(source, "vibeSearch"),
+	N:         100_000,
+	Target:    2,
+	Check:     checkExercise,
+	Impls: func(n int) (solution, expert bench.Implementation) {
+		wl := makeWorkload(input.DefaultSeed, n)
+		return bench.Impl[workload, []int]{Name: "Your solution", Func: lookupAll(exercise.Search)}.Implementation(wl),
+			bench.Impl[workload, []int]{Name: "Expert coding", Func: lookupAll(expertSearch)}.Implementation(wl)
+	},
+}
+
+// checkExercise looks up every value in and just around short sorted
+// slices, heavy with duplicates, with exercise.Search and compares the
+// index with the expert's; then every target of a full workload.
+func checkExercise(seed input.Seed) error {
+	rng := seed.Rand("exercise", 0)
+	for range 200 {
+		n := rng.IntN(64)
+		xs := make([]int, n)
+		for i := range xs {
+			xs[i] = rng.IntN(2*n+1) - n
+		}
+		slices.Sort(xs)
+		for target := -n - 1; target <= n+1; target++ {
+			if got, want := exercise.Search(xs, target), expertSearch(xs, target); got != want {
+				return fmt.Errorf("Search(%v, %d) = %d, want %d", xs, target, got, want)
+			}
+		}
+	}
+	wl := makeWorkload(seed, 100_000)
+	for _, target := range wl.targets {
+		if got, want := exercise.Search(wl.xs, target), expertSearch(wl.xs, target); got != want {
+			return fmt.Errorf("searching %d sorted values for %d gives %d, want %d", len(wl.xs), target, got, want)
+		}
+	}
+	return nil
+}
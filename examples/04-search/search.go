@@ -0,0 +1,62 @@
+package search
+
+import "sort"
+
+// VIBE CODING: Check every element in turn
+func vibeSearch(xs []int, target int) int {
+	/*
+	   Find the first index of target in sorted xs, or -1 if absent
+
+	   Walks the slice from the start. Correct for any slice, sorted or
+	   not - which is exactly the problem: it ignores the one property
+	   that makes searching fast.
+	*/
+	for i, x := range xs {
+		if x == target {
+			return i
+		}
+	}
+	return -1 // O(n) comparisons
+}
+
+// HUMAN CODING: Hand-rolled binary search
+func humanSearch(xs []int, target int) int {
+	/*
+	   Find the first index of target in sorted xs, or -1 if absent
+
+	   Keep a half-open window [lo, hi) that must contain the first
+	   element >= target, and halve it each step. The midpoint is
+	   lo + (hi-lo)/2 rather than (lo+hi)/2: the sum can overflow when
+	   both bounds are large, the bug that sat in the JDK's binary search
+	   for nine years.
+	*/
+	lo, hi := 0, len(xs)
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if xs[mid] < target {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if lo < len(xs) && xs[lo] == target {
+		return lo
+	}
+	return -1 // O(log n) comparisons
+}
+
+// EXPERT CODING: The standard library
+func expertSearch(xs []int, target int) int {
+	/*
+	   Find the first index of target in sorted xs, or -1 if absent
+
+	   sort.SearchInts is the same lower-bound binary search, already
+	   tested against every edge case above. It returns where target
+	   would be inserted, so the caller still checks it is really there.
+	*/
+	i := sort.SearchInts(xs, target)
+	if i < len(xs) && xs[i] == target {
+		return i
+	}
+	return -1 // O(log n) comparisons, zero bugs to write
+}
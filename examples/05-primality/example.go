@@ -0,0 +1,265 @@
+// Package primality compares three ways to test whether one number is prime.
+package primality
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/primes"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// Beyond this, trial division needs tens of millions of divisions per
+// call and the demo would stall on the vibe implementation.
+const maxTrialDivision = 1 << 52
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "trial division", Complexity: "O(√n)", Notes: []report.Note{
+			report.Strength("Obviously correct"),
+			report.Pitfall("O(√n) divisions - hopeless for 60+ bit numbers"),
+			report.Pitfall("Cost grows with the size of the number, not its number of digits"),
+		}},
+		{Label: "Human coding", Approach: "deterministic Miller–Rabin", Complexity: "O(k log³ n)", Notes: []report.Note{
+			report.Strength("Polynomial in the number of digits"),
+			report.Strength("Fixed witness set makes it exact for every uint64"),
+			report.Strength("Catches Carmichael numbers and strong pseudoprimes to small bases"),
+			report.Pitfall("Easy to get subtly wrong (overflowing a·b mod n, weak base sets)"),
+		}},
+		{Label: "Expert coding", Approach: "math/big ProbablyPrime", Complexity: "O(k log³ n)", Notes: []report.Note{
+			report.Strength("Miller–Rabin plus a Lucas test (Baillie–PSW), exact below 2^64"),
+			report.Strength("Works for numbers of any size - RSA-sized primes included"),
+			report.Strength("Maintained and tested by the Go team"),
+			report.Pitfall("Arbitrary-precision arithmetic is slower than a tuned uint64 version"),
+		}},
+		{Label: "Beyond 64 bits", Approach: "primes.FindPrimesBig", Complexity: "O(k log³ n) per candidate", Notes: []report.Note{
+			report.Strength("Sieving out multiples of small primes first skips ~95% of the tests"),
+			report.Strength("The same search crypto/rand.Prime runs to make RSA keys"),
+			report.Pitfall("Above 2^64 the answers are probable primes - no known exception"),
+		}},
+	},
+	Takeaway: "For a single large number, the algorithm's growth rate matters more " +
+		"than micro-optimizations - and a well-tested library beats a clever " +
+		"hand-rolled version unless you have measured a reason to switch.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "05-primality",
+		Title:       "Primality Testing",
+		Description: "Decide whether one large number is prime, where sieving is the wrong tool.",
+		Category:    "number theory",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("05-primality", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	bits := fs.Int("bits", 256, "size of the numbers in the big prime search (0 skips it)")
+	window := fs.Int("window", 2000, "how many consecutive numbers the big prime search covers")
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Primality Testing", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Primality Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	testValues := []struct {
+		n    uint64
+		desc string
+	}{
+		{561, "Carmichael number (fools the Fermat test)"},
+		{3_215_031_751, "strong pseudoprime to bases 2, 3, 5, 7"},
+		{1_000_000_007, "10-digit prime"},
+		{998_244_359_987_710_471, "product of two 30-bit primes"},
+		{1<<50 - 27, "largest prime below 2^50"},
+		{1<<61 - 1, "Mersenne prime 2^61-1"},
+		{18_446_744_073_709_551_557, "largest 64-bit prime"},
+	}
+
+	for _, tc := range testValues {
+		fmt.Fprintf(out.Table, "\nIs %d prime? (%s)\n", tc.n, tc.desc)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		var trial, millerRabin, library bool
+		impls := []bench.Implementation{}
+		if tc.n <= maxTrialDivision {
+			impls = append(impls, bench.Implementation{
+				Name: "Vibe coding", Complexity: "trial division, O(√n)",
+				Run: func() { trial = primes.IsPrimeTrialDivision(tc.n) },
+			})
+		}
+		impls = append(impls,
+			bench.Implementation{
+				Name: "Human coding", Complexity: "Miller–Rabin, O(k log³ n)",
+				Run: func() { millerRabin = primes.IsPrimeMillerRabin(tc.n) },
+			},
+			bench.Implementation{
+				Name: "Expert coding", Complexity: "big.ProbablyPrime",
+				Run: func() { library = new(big.Int).SetUint64(tc.n).ProbablyPrime(0) },
+			},
+		)
+		results, err := bench.CompareContext(ctx, opts, impls...)
+		if err != nil {
+			return err
+		}
+		if err := csvLog.Append(tc.desc, tc.n, results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append(tc.desc, tc.n, results)
+
+		fmt.Fprintf(w, "Answer: %v\n", library)
+		if millerRabin != library || (tc.n <= maxTrialDivision && trial != library) {
+			fmt.Fprintln(w, "  ⚠️ Implementations disagree!")
+		}
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("%d (%s)", tc.n, tc.desc), results)
+
+		if tc.n > maxTrialDivision {
+			note := "Vibe coding skipped: trial division would need ~√n/2 divisions"
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		} else if slow, mr := results[0], results[1]; slow.Duration > mr.Duration {
+			fmt.Fprintf(w, "  ❌ Vibe is %.1fx slower than Human\n", bench.Speedup(slow, mr))
+		}
+	}
+
+	if *bits > 1 && *window > 0 {
+		if err := bigSearch(ctx, out, opts, csvLog, benchLog, rep, uint(*bits), *window); err != nil {
+			return err
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
+
+// bigSearch times three ways to find the primes among window consecutive
+// numbers starting at 2^(bits-1), the search behind generating a
+// cryptographic key, where numbers are far too large for uint64.
+func bigSearch(ctx context.Context, out examples.Output, opts bench.Options, csvLog *report.CSVLog, benchLog *report.BenchLog, rep *report.Report, bits uint, window int) error {
+	w := out.Text
+	a := new(big.Int).Lsh(big.NewInt(1), bits-1)
+	b := new(big.Int).Add(a, big.NewInt(int64(window-1)))
+	input := fmt.Sprintf("%d-bit window", bits)
+
+	fmt.Fprintln(out.Table)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(out.Table, "Primes among %d numbers from 2^%d (%d-bit)\n", window, bits-1, bits)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	var everyNumber, oddOnly, sieved []*big.Int
+	testFrom := func(ctx context.Context, start *big.Int, step int64) ([]*big.Int, error) {
+		found := []*big.Int{}
+		for x := new(big.Int).Set(start); x.Cmp(b) <= 0; x.Add(x, big.NewInt(step)) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if primes.IsPrimeBig(x) {
+				found = append(found, new(big.Int).Set(x))
+			}
+		}
+		return found, nil
+	}
+	results, err := bench.CompareContext(ctx, opts,
+		bench.Implementation{
+			Name: "Vibe coding", Complexity: "test every number",
+			RunContext: func(ctx context.Context) (err error) {
+				everyNumber, err = testFrom(ctx, a, 1)
+				return err
+			},
+		},
+		bench.Implementation{
+			Name: "Human coding", Complexity: "test odd numbers",
+			RunContext: func(ctx context.Context) (err error) {
+				oddOnly, err = testFrom(ctx, new(big.Int).Add(a, big.NewInt(1)), 2)
+				return err
+			},
+		},
+		bench.Implementation{
+			Name: "Expert coding", Complexity: "sieve, then test",
+			RunContext: func(ctx context.Context) (err error) {
+				sieved, err = primes.FindPrimesBigContext(ctx, a, b)
+				return err
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if err := csvLog.Append(input, uint64(window), results); err != nil {
+		return fmt.Errorf("csv: %w", err)
+	}
+	benchLog.Append(input, uint64(window), results)
+
+	if len(everyNumber) != len(sieved) || len(oddOnly) != len(sieved) {
+		return fmt.Errorf("verification failed: found %d, %d and %d primes", len(everyNumber), len(oddOnly), len(sieved))
+	}
+	// Near x about one number in ln x is prime.
+	expected := float64(window) / (float64(bits-1) * math.Ln2)
+	fmt.Fprintf(w, "Found %d probable primes (the prime number theorem expects about %.0f)\n", len(sieved), expected)
+	if len(sieved) > 0 {
+		fmt.Fprintf(w, "First: 2^%d + %d\n", bits-1, new(big.Int).Sub(sieved[0], a))
+	}
+	bench.Print(out.Table, results)
+	report.WriteBars(w, results)
+	bench.PrintRuns(out.Detail, results)
+	rep.Add(fmt.Sprintf("Primes among %d numbers from 2^%d", window, bits-1), results)
+	if slow, fast := results[0], results[2]; slow.Duration > fast.Duration {
+		fmt.Fprintf(w, "  ❌ Vibe is %.1fx slower than Expert\n", bench.Speedup(slow, fast))
+	}
+	fmt.Fprintln(w, "\n  💡 Skipping even numbers barely helps: ProbablyPrime rejects them at once.")
+	fmt.Fprintln(w, "     The sieve skips the costly composites, but each prime still has to pass")
+	fmt.Fprintln(w, "     every round, and that is most of the time left.")
+	fmt.Fprintln(w, "  💡 A 2048-bit RSA key needs two 1024-bit primes: try -bits 1024.")
+	fmt.Fprintln(w, "     For real keys use crypto/rand.Prime, which starts from a random number.")
+	return nil
+}
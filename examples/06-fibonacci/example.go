@@ -0,0 +1,219 @@
+// Package fibonacci compares three ways to compute the nth Fibonacci number.
+package fibonacci
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// The recursive version makes ~F(n) calls: about 30 million at n=35 and
+// over 300 million at n=40.
+const maxVibeN = 35
+
+// Largest n whose Fibonacci number fits in a uint64.
+const maxUint64Fib = 93
+
+// describe summarises a potentially huge number for display.
+func describe(x *big.Int) string {
+	s := x.String()
+	if len(s) <= 40 {
+		return s
+	}
+	return fmt.Sprintf("%s...%s (%d digits)", s[:15], s[len(s)-15:], len(s))
+}
+
+// sameNumber is the equality function the implementations are checked
+// with; *big.Int values must be compared by value, not by pointer.
+func sameNumber(got, want *big.Int) error {
+	if got.Cmp(want) != 0 {
+		return fmt.Errorf("got %s, want %s", describe(got), describe(want))
+	}
+	return nil
+}
+
+// fibsFor returns the implementations to compare at n, leaving out the
+// recursive one where it would make hundreds of millions of calls.
+func fibsFor(n int) []bench.Impl[int, *big.Int] {
+	impls := []bench.Impl[int, *big.Int]{}
+	if n <= maxVibeN {
+		impls = append(impls, bench.Impl[int, *big.Int]{Name: "Vibe coding", Complexity: "recursive, O(φⁿ)", Func: func(n int) *big.Int {
+			return new(big.Int).SetUint64(vibeFib(n))
+		}})
+	}
+	return append(impls,
+		bench.Impl[int, *big.Int]{Name: "Human coding", Complexity: "iterative, O(n)", Func: humanFib},
+		bench.Impl[int, *big.Int]{Name: "Expert coding", Complexity: "matrix power, O(log n)", Func: expertFib},
+	)
+}
+
+// impls returns the implementations timed computing F(n).
+func impls(n int) []bench.Implementation {
+	var list []bench.Implementation
+	for _, f := range fibsFor(n) {
+		list = append(list, f.Implementation(n))
+	}
+	return list
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "naive recursion", Complexity: "O(φⁿ)", Notes: []report.Note{
+			report.Pitfall("Recomputes the same subproblems: O(φⁿ) calls"),
+			report.Pitfall("Overflows uint64 silently past F(93)"),
+			report.Strength("Reads exactly like the mathematical definition"),
+		}},
+		{Label: "Human coding", Approach: "iteration with math/big", Complexity: "O(n)", Notes: []report.Note{
+			report.Strength("Each value computed once: O(n) additions"),
+			report.Strength("big.Int gives exact answers at any size"),
+			report.Pitfall("Still linear in n - F(10,000,000) takes a while"),
+		}},
+		{Label: "Expert coding", Approach: "2×2 matrix power", Complexity: "O(log n)", Notes: []report.Note{
+			report.Strength("O(log n) multiplications via repeated squaring"),
+			report.Strength("The same trick powers fast modular exponentiation and RSA"),
+			report.Strength("Large numbers multiplied with Karatsuba by math/big"),
+			report.Pitfall("More overhead than the loop for small n"),
+		}},
+	},
+	Takeaway: `Exponential → linear is the difference between "never finishes" and ` +
+		`"instant". Linear → logarithmic only matters once n is huge - know which ` +
+		`regime you're in before optimizing.`,
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "06-fibonacci",
+		Title:       "Fibonacci Numbers",
+		Description: "Compute the nth Fibonacci number in exponential, linear and logarithmic time.",
+		Category:    "recursion",
+		Difficulty:  examples.Beginner,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    30,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("06-fibonacci", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	testValues := bench.Sizes{20, 30, 90, 1_000, 100_000}
+	fs.Var(&testValues, "n", "comma-separated values of n, e.g. 35,1e4,1e6")
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Fibonacci Numbers", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Fibonacci Numbers")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	for _, n := range testValues {
+		fmt.Fprintf(out.Table, "\nComputing F(%d):\n", n)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		// The recursive version is only comparable while F(n) fits in a
+		// uint64.
+		want := expertFib(n)
+		fmt.Fprintf(w, "F(%d) = %s\n", n, describe(want))
+
+		results, err := bench.CompareImpls(ctx, opts, n, want, sameNumber, fibsFor(n)...)
+		if err != nil {
+			return err
+		}
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d", n), results)
+
+		if n > maxVibeN {
+			note := fmt.Sprintf("Vibe coding skipped: exponential recursion is impractical above n=%d", maxVibeN)
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		} else if vibe, human := results[0], results[1]; vibe.Duration > human.Duration {
+			fmt.Fprintf(w, "  ❌ Vibe is %.1fx slower than Human\n", bench.Speedup(vibe, human))
+		}
+		if human, expert := results[len(results)-2], results[len(results)-1]; human.Duration > expert.Duration {
+			fmt.Fprintf(w, "  ✅ Expert is %.1fx faster than Human\n", bench.Speedup(human, expert))
+		} else {
+			fmt.Fprintln(w, "  💡 For small n the matrix overhead outweighs its O(log n) advantage")
+		}
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		n    int
+		desc string
+	}{
+		{0, "F(0) (base case)"},
+		{1, "F(1) (base case)"},
+		{2, "F(2) (first sum)"},
+		{-3, "F(-3) (negative, treated as 0)"},
+		{maxUint64Fib, "F(93) (largest that fits in uint64)"},
+		{maxUint64Fib + 1, "F(94) (overflows uint64)"},
+	}
+	for _, tc := range edgeCases {
+		result := expertFib(tc.n)
+		fmt.Fprintf(w, "%s: %s\n", tc.desc, result)
+		rep.AddEdgeCase(tc.desc, result.String())
+	}
+
+	// What a uint64 version silently returns past F(93).
+	a, b := uint64(0), uint64(1)
+	for range maxUint64Fib + 1 {
+		a, b = b, a+b
+	}
+	fmt.Fprintf(w, "  ❌ A uint64 implementation returns %d for F(94) - wrapped around, no error!\n", a)
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package fibonacci
+
+import "math/big"
+
+// VIBE CODING: The textbook recursive definition
+func vibeFib(n int) uint64 {
+	/*
+	   Compute the nth Fibonacci number straight from the definition
+
+	   F(n) = F(n-1) + F(n-2) - but both branches recompute the same
+	   values over and over, so the call tree has ~φⁿ nodes. It also
+	   silently overflows uint64 past F(93).
+	*/
+	if n <= 1 {
+		return uint64(max(n, 0))
+	}
+	return vibeFib(n-1) + vibeFib(n-2) // O(φⁿ) ≈ O(1.618ⁿ) - exponential!
+}
+
+// HUMAN CODING: Iterate, remembering only the last two values
+func humanFib(n int) *big.Int {
+	/*
+	   Compute the nth Fibonacci number bottom-up
+
+	   This is memoization taken to its logical end: each value depends
+	   only on the previous two, so keep just those. big.Int removes the
+	   overflow problem.
+	*/
+	a, b := big.NewInt(0), big.NewInt(1)
+	for range max(n, 0) {
+		a.Add(a, b)
+		a, b = b, a
+	}
+	return a // O(n) additions of O(n)-bit numbers
+}
+
+// EXPERT CODING: Matrix exponentiation by squaring
+func expertFib(n int) *big.Int {
+	/*
+	   Compute the nth Fibonacci number in O(log n) matrix multiplications
+
+	   Uses the identity
+	       | 1 1 |ⁿ   | F(n+1) F(n)   |
+	       | 1 0 |  = | F(n)   F(n-1) |
+	   and computes the power by repeated squaring, like fast modular
+	   exponentiation. math/big multiplies large numbers with Karatsuba.
+	*/
+	if n <= 0 {
+		return big.NewInt(0)
+	}
+	result := identity()
+	base := matrix{big.NewInt(1), big.NewInt(1), big.NewInt(1), big.NewInt(0)}
+	for e := n; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			result = result.mul(base)
+		}
+		base = base.mul(base)
+	}
+	return result[1] // O(log n) multiplications
+}
+
+// matrix is a 2x2 matrix stored row-major: [a b; c d].
+type matrix [4]*big.Int
+
+func identity() matrix {
+	return matrix{big.NewInt(1), big.NewInt(0), big.NewInt(0), big.NewInt(1)}
+}
+
+func (m matrix) mul(o matrix) matrix {
+	dot := func(a, b, c, d *big.Int) *big.Int {
+		x := new(big.Int).Mul(a, b)
+		return x.Add(x, new(big.Int).Mul(c, d))
+	}
+	return matrix{
+		dot(m[0], o[0], m[1], o[2]), dot(m[0], o[1], m[1], o[3]),
+		dot(m[2], o[0], m[3], o[2]), dot(m[2], o[1], m[3], o[3]),
+	}
+}
@@ -0,0 +1,56 @@
+package stringbuilding
+
+import "strings"
+
+// VIBE CODING: Concatenate with += in a loop
+func vibeJoin(parts []string) string {
+	/*
+	   Build one string from many parts with +=
+
+	   Go strings are immutable, so every += allocates a new string and
+	   copies everything built so far. n appends copy ~n²/2 parts' worth
+	   of bytes in total.
+	*/
+	s := ""
+	for _, p := range parts {
+		s += p
+	}
+	return s // O(n²) bytes copied, one allocation per part
+}
+
+// HUMAN CODING: strings.Builder
+func humanJoin(parts []string) string {
+	/*
+	   Build one string from many parts with strings.Builder
+
+	   The builder appends into a growing []byte, doubling its capacity
+	   when full, and hands the bytes over as a string without a final
+	   copy. Amortized O(1) per byte, O(log n) allocations.
+	*/
+	var b strings.Builder
+	for _, p := range parts {
+		b.WriteString(p)
+	}
+	return b.String()
+}
+
+// EXPERT CODING: Preallocate the exact capacity
+func expertJoin(parts []string) string {
+	/*
+	   Build one string from many parts into a preallocated []byte
+
+	   When the final size is knowable up front, compute it and allocate
+	   once: no regrowth, no wasted capacity. The final string(buf)
+	   conversion costs one more copy - strings.Builder with Grow avoids
+	   even that, and is what strings.Join does internally.
+	*/
+	total := 0
+	for _, p := range parts {
+		total += len(p)
+	}
+	buf := make([]byte, 0, total)
+	for _, p := range parts {
+		buf = append(buf, p...)
+	}
+	return string(buf) // exactly 2 allocations regardless of n
+}
@@ -0,0 +1,212 @@
+// Package stringbuilding compares three ways to build a large string from many parts.
+package stringbuilding
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// Past this many parts += copies gigabytes and takes seconds per run.
+const maxVibeN = 20_000
+
+// makeParts returns n short, distinct strings like "line 42\n".
+func makeParts(n int) []string {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = "line " + strconv.Itoa(i) + "\n"
+	}
+	return parts
+}
+
+// sameString is the equality function the implementations are checked
+// with. The strings run to megabytes, so it reports where they first
+// differ rather than printing them.
+func sameString(got, want string) error {
+	if got == want {
+		return nil
+	}
+	i := 0
+	for i < len(got) && i < len(want) && got[i] == want[i] {
+		i++
+	}
+	return fmt.Errorf("built a different string: %d bytes, want %d; first difference at byte %d", len(got), len(want), i)
+}
+
+// joinsFor returns the implementations to compare on n parts, leaving
+// out += where it would copy gigabytes.
+func joinsFor(n int) []bench.Impl[[]string, string] {
+	impls := []bench.Impl[[]string, string]{}
+	if n <= maxVibeN {
+		impls = append(impls, bench.Impl[[]string, string]{Name: "Vibe coding", Complexity: "+= in a loop, O(n²)", Func: vibeJoin})
+	}
+	return append(impls,
+		bench.Impl[[]string, string]{Name: "Human coding", Complexity: "strings.Builder, O(n)", Func: humanJoin},
+		bench.Impl[[]string, string]{Name: "Expert coding", Complexity: "preallocated []byte, O(n)", Func: expertJoin},
+	)
+}
+
+// impls returns the implementations timed joining n parts.
+func impls(n int) []bench.Implementation {
+	parts := makeParts(n)
+	var list []bench.Implementation
+	for _, j := range joinsFor(n) {
+		list = append(list, j.Implementation(parts))
+	}
+	return list
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "+= in a loop", Complexity: "O(n²)", Notes: []report.Note{
+			report.Pitfall("Every += allocates a new string and copies everything so far"),
+			report.Pitfall("O(n²) bytes copied, n allocations"),
+			report.Strength("Perfectly fine for a handful of parts"),
+		}},
+		{Label: "Human coding", Approach: "strings.Builder", Complexity: "O(n)", Notes: []report.Note{
+			report.Strength("Appends into a growing buffer: amortized O(1) per byte"),
+			report.Strength("O(log n) allocations from capacity doubling"),
+			report.Strength("The idiomatic default"),
+		}},
+		{Label: "Expert coding", Approach: "preallocated []byte", Complexity: "O(n)", Notes: []report.Note{
+			report.Strength("Compute the final size, allocate exactly once"),
+			report.Strength("Allocation count independent of n"),
+			report.Pitfall("Only possible when the size is knowable up front"),
+			report.Tip("strings.Builder + Grow, or strings.Join, get the same effect"),
+		}},
+	},
+	Takeaway: "String building in a loop is one of the most common hidden O(n²) " +
+		"traps. Allocation counts reveal it long before timings do.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "07-string-building",
+		Title:       "String Building",
+		Description: "Build one large string from many parts, and count the allocations it takes.",
+		Category:    "strings",
+		Difficulty:  examples.Beginner,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    1_000,
+		Exercise:    exerciseSpec,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("07-string-building", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{100, 1_000, 10_000, 100_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of parts to join, e.g. 1e4,1e6")
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("String Building", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: String Building")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	for _, n := range sizes {
+		parts := makeParts(n)
+		want := expertJoin(parts)
+		fmt.Fprintf(out.Table, "\nJoining %d parts (%s of text):\n", n, bench.FormatBytes(uint64(len(want))))
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		results, err := bench.CompareImpls(ctx, opts, parts, want, sameString, joinsFor(n)...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "✔ All implementations build the same string")
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d parts", n), results)
+
+		expert := results[len(results)-1]
+		if n > maxVibeN {
+			note := fmt.Sprintf("Vibe coding skipped: += copies O(n²) bytes, impractical above n=%d", maxVibeN)
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		} else if vibe := results[0]; expert.Bytes > 0 {
+			fmt.Fprintf(w, "  ❌ Vibe allocates %.1fx the bytes of Expert, in %d allocations instead of %d\n",
+				float64(vibe.Bytes)/float64(expert.Bytes), vibe.Allocs, expert.Allocs)
+		}
+		if human := results[len(results)-2]; human.Allocs > expert.Allocs {
+			fmt.Fprintf(w, "  💾 Builder regrew its buffer: %d allocations vs %d preallocated\n", human.Allocs, expert.Allocs)
+		}
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		parts []string
+		desc  string
+	}{
+		{nil, "no parts"},
+		{[]string{""}, "one empty part"},
+		{[]string{"a", "", "b"}, "empty part in the middle"},
+		{[]string{"héllo, ", "世界"}, "multi-byte UTF-8"},
+	}
+	for _, tc := range edgeCases {
+		results := []string{vibeJoin(tc.parts), humanJoin(tc.parts), expertJoin(tc.parts)}
+		status := "✅"
+		if results[0] != results[1] || results[1] != results[2] {
+			status = "❌"
+		}
+		fmt.Fprintf(w, "%s %s: %q\n", status, tc.desc, results[2])
+		rep.AddEdgeCase(tc.desc, strconv.Quote(results[2]))
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
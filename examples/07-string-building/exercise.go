@@ -0,0 +1,56 @@
+package stringbuilding
+
+import (
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	exercise "github.com/iportilla/ai-coding/exercises/07-string-building"
+	"github.com/iportilla/ai-coding/input"
+)
+
+//go:embed building.go
+var source string
+
+// exerciseSpec is the example as a task: write exercise.Join.
+var exerciseSpec = &examples.Exercise{
+	File:      "exercises/07-string-building/solution.go",
+	Signature: "func Join(parts []string) string",
+	Vibe:      examples.FuncSource(source, "vibeJoin"),
+	N:         10_000,
+	Target:    3,
+	Check:     checkExercise,
+	Impls: func(n int) (solution, expert bench.Implementation) {
+		parts := makeParts(n)
+		return bench.Impl[[]string, string]{Name: "Your solution", Func: exercise.Join}.Implementation(parts),
+			bench.Impl[[]string, string]{Name: "Expert coding", Func: expertJoin}.Implementation(parts)
+	},
+}
+
+// checkExercise joins no parts, empty parts, parts of random lengths and
+// the parts the example times with exercise.Join, and compares the
+// result with the expert's.
+func checkExercise(seed input.Seed) error {
+	inputs := [][]string{nil, {}, {""}, {"", "", ""}, {"a"}, {"a", "", "bc"}, makeParts(maxVibeN)}
+	rng := seed.Rand("exercise", 0)
+	for range 100 {
+		parts := make([]string, rng.IntN(50))
+		for i := range parts {
+			parts[i] = strings.Repeat(strconv.Itoa(i), rng.IntN(3))
+		}
+		inputs = append(inputs, parts)
+	}
+	for _, parts := range inputs {
+		got, want := exercise.Join(parts), expertJoin(parts)
+		if err := sameString(got, want); err != nil {
+			if len(parts) <= 8 {
+				return fmt.Errorf("Join(%q) = %q, want %q", parts, got, want)
+			}
+			return fmt.Errorf("joining %d parts %w", len(parts), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+// Package factorization compares three ways to split a 64-bit integer
+// into its prime factors.
+package factorization
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// Work limits for the slower tiers, in divisions (vibe) and in the
+// largest prime tried (human). Past these a single run takes seconds to
+// hours, so the tier is reported as skipped. Both are worked out from
+// the expert factorization before anything is timed.
+const (
+	maxVibeWork   = 50_000_000
+	maxHumanBound = 100_000_000
+)
+
+// trialDivisionBound returns the largest divisor trial division tries
+// before it has fully factored a number with the given prime factors
+// (in increasing order): the second-largest factor, or the square root
+// of the largest if that is bigger.
+func trialDivisionBound(factors []uint64) uint64 {
+	if len(factors) == 0 {
+		return 0
+	}
+	largest := factors[len(factors)-1]
+	bound := isqrt(largest)
+	if len(factors) > 1 {
+		bound = max(bound, factors[len(factors)-2])
+	}
+	return bound
+}
+
+// formatFactors writes a factorization with exponents, e.g. "2^3 × 3^2 × 5".
+func formatFactors(factors []uint64) string {
+	if len(factors) == 0 {
+		return "(no prime factors)"
+	}
+	var parts []string
+	for i := 0; i < len(factors); {
+		j := i
+		for j < len(factors) && factors[j] == factors[i] {
+			j++
+		}
+		if j-i == 1 {
+			parts = append(parts, fmt.Sprint(factors[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d^%d", factors[i], j-i))
+		}
+		i = j
+	}
+	return strings.Join(parts, " × ")
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "trial division by every integer", Complexity: "O(n)", Notes: []report.Note{
+			report.Pitfall("Keeps dividing until it reaches the largest prime factor"),
+			report.Pitfall("O(n) divisions when n is prime - hopeless past ~10^9"),
+			report.Strength("Trivially correct, and fast when all factors are small"),
+		}},
+		{Label: "Human coding", Approach: "trial division by primes up to √n", Complexity: "O(√n / log n)", Notes: []report.Note{
+			report.Strength("Only primes are tried, from the shared segmented sieve"),
+			report.Strength("Stops at √n: whatever is left over must be prime"),
+			report.Pitfall("Two 10-digit factors still mean ~50 million divisions"),
+		}},
+		{Label: "Expert coding", Approach: "Pollard's rho + Miller–Rabin", Complexity: "O(n^¼)", Notes: []report.Note{
+			report.Strength("Finds a factor p in about √p steps"),
+			report.Strength("Miller–Rabin recognises prime cofactors instantly"),
+			report.Strength("Factors any 64-bit integer in milliseconds"),
+			report.Pitfall("Subtle: cycle detection, retries on failure, 128-bit modular maths"),
+		}},
+	},
+	Takeaway: "Trial division's cost depends on the factors you haven't found yet. For " +
+		"numbers with large prime factors, only a different algorithm helps - the " +
+		"gap between √n and n^¼ is the gap between hours and milliseconds.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "08-factorization",
+		Title:       "Prime Factorization",
+		Description: "Split 64-bit integers into prime factors, from naive division to Pollard's rho.",
+		Category:    "number theory",
+		Difficulty:  examples.Advanced,
+		Lesson:      lesson,
+		Run:         Run,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("08-factorization", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Prime Factorization", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Prime Factorization")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	testValues := []struct {
+		n    uint64
+		desc string
+	}{
+		{360, "many small factors"},
+		{600_851_475_143, "Project Euler #3"},
+		{1_000_036_000_099, "two 7-digit primes"},
+		{18_446_744_073_709_551_615, "2^64 - 1"},
+		{1_000_000_007, "10-digit prime"},
+		{1_000_000_016_000_000_063, "two 10-digit primes"},
+		{1<<61 - 1, "Mersenne prime 2^61 - 1"},
+		{18_446_743_979_220_271_189, "two 32-bit primes"},
+	}
+
+	for _, tc := range testValues {
+		fmt.Fprintf(out.Table, "\nFactoring %d (%s):\n", tc.n, tc.desc)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		// The expert answer tells us how much work the slower tiers
+		// would do, so the hopeless ones can be skipped up front.
+		want := expertFactor(tc.n)
+		vibeWork := uint64(0)
+		if len(want) > 0 {
+			vibeWork = want[len(want)-1]
+		}
+		runVibe := vibeWork <= maxVibeWork
+		runHuman := trialDivisionBound(want) <= maxHumanBound
+
+		product := uint64(1)
+		for _, f := range want {
+			product *= f
+		}
+		if product != tc.n {
+			return fmt.Errorf("verification failed: Expert coding factors multiply to %d, want %d", product, tc.n)
+		}
+		fmt.Fprintf(w, "%d = %s\n", tc.n, formatFactors(want))
+
+		impls := []bench.Impl[uint64, []uint64]{}
+		if runVibe {
+			impls = append(impls, bench.Impl[uint64, []uint64]{Name: "Vibe coding", Complexity: "every integer, O(n)", Func: vibeFactor})
+		}
+		if runHuman {
+			impls = append(impls, bench.Impl[uint64, []uint64]{Name: "Human coding", Complexity: "primes to √n, O(√n / log n)", Func: humanFactor})
+		}
+		impls = append(impls, bench.Impl[uint64, []uint64]{Name: "Expert coding", Complexity: "Pollard's rho, O(n^¼)", Func: expertFactor})
+
+		results, err := bench.CompareImpls(ctx, opts, tc.n, want, bench.DiffSlices, impls...)
+		if err != nil {
+			return err
+		}
+		if err := csvLog.Append(tc.desc, tc.n, results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append(tc.desc, tc.n, results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("%d (%s)", tc.n, tc.desc), results)
+
+		if !runVibe {
+			note := fmt.Sprintf("Vibe coding skipped: would try ~%.1e divisors", float64(vibeWork))
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		}
+		if !runHuman {
+			note := fmt.Sprintf("Human coding skipped: would sieve and try primes up to ~%.1e", float64(trialDivisionBound(want)))
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		}
+		expert := results[len(results)-1]
+		if runVibe && runHuman && results[0].Duration > results[1].Duration {
+			fmt.Fprintf(w, "  ❌ Vibe is %.1fx slower than Human\n", bench.Speedup(results[0], results[1]))
+		}
+		if runHuman && results[len(results)-2].Duration > expert.Duration {
+			fmt.Fprintf(w, "  ✅ Expert is %.1fx faster than Human\n", bench.Speedup(results[len(results)-2], expert))
+		}
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		n    uint64
+		desc string
+	}{
+		{0, "n = 0 (no factorization)"},
+		{1, "n = 1 (empty product)"},
+		{2, "n = 2 (smallest prime)"},
+		{1_000_003 * 1_000_003, "square of a 7-digit prime"},
+		{1 << 63, "2^63"},
+	}
+	for _, tc := range edgeCases {
+		got := expertFactor(tc.n)
+		status := "✅"
+		if bench.DiffSlices(humanFactor(tc.n), got) != nil {
+			status = "❌"
+		}
+		fmt.Fprintf(w, "%s %s: %s\n", status, tc.desc, formatFactors(got))
+		rep.AddEdgeCase(tc.desc, formatFactors(got))
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package factorization
+
+import (
+	"math"
+	"math/bits"
+	"slices"
+
+	"github.com/iportilla/ai-coding/numtheory"
+	"github.com/iportilla/ai-coding/primes"
+)
+
+// VIBE CODING: Try every divisor until nothing is left
+func vibeFactor(n uint64) []uint64 {
+	/*
+	   Factor n by dividing out 2, 3, 4, 5, 6, ... in turn
+
+	   Always correct, and fast when every prime factor is small. But it
+	   keeps going all the way up to the largest prime factor - for a
+	   prime n that is n divisions, and it wastes time on composite
+	   divisors that can never divide what's left.
+	*/
+	factors := []uint64{}
+	for d := uint64(2); n > 1; d++ {
+		for n%d == 0 {
+			factors = append(factors, d)
+			n /= d
+		}
+	}
+	return factors // O(largest prime factor) divisions - O(n) for a prime!
+}
+
+// HUMAN CODING: Divide by primes only, and stop at √n
+func humanFactor(n uint64) []uint64 {
+	/*
+	   Factor n by trial division with primes up to √n
+
+	   Two mathematical insights: only primes need to be tried, and once
+	   p² exceeds what is left of n, what is left must itself be prime.
+	   The primes come lazily from the shared segmented sieve, so a large
+	   √n costs time but never a huge table.
+	*/
+	factors := []uint64{}
+	if n < 2 {
+		return factors
+	}
+	for p := range primes.UpTo(int(isqrt(n))) {
+		d := uint64(p)
+		if d*d > n {
+			break
+		}
+		for n%d == 0 {
+			factors = append(factors, d)
+			n /= d
+		}
+	}
+	if n > 1 {
+		factors = append(factors, n) // the remaining cofactor is prime
+	}
+	return factors // O(π(√n)) divisions in the worst case
+}
+
+// smallPrimes are divided out before Pollard's rho, which is slow at
+// finding tiny factors and needs an odd n.
+var smallPrimes = primes.ExpertFindPrimes(1000)
+
+// EXPERT CODING: Pollard's rho with a Miller–Rabin primality check
+func expertFactor(n uint64) []uint64 {
+	/*
+	   Factor n by splitting it recursively with Pollard's rho
+
+	   Strip small prime factors by trial division, then repeatedly:
+	   if what's left is prime (deterministic Miller–Rabin), it is a
+	   factor; otherwise Pollard's rho finds some divisor d and both d
+	   and n/d are factored the same way. Rho finds a factor p in about
+	   √p steps, so even a product of two 32-bit primes splits in
+	   milliseconds.
+	*/
+	factors := []uint64{}
+	if n < 2 {
+		return factors
+	}
+	for _, p := range smallPrimes {
+		d := uint64(p)
+		for n%d == 0 {
+			factors = append(factors, d)
+			n /= d
+		}
+	}
+
+	var split func(m uint64)
+	split = func(m uint64) {
+		switch {
+		case m == 1:
+		case primes.IsPrimeMillerRabin(m):
+			factors = append(factors, m)
+		default:
+			d := pollardRho(m)
+			split(d)
+			split(m / d)
+		}
+	}
+	split(n)
+
+	slices.Sort(factors)
+	return factors // O(n^¼) expected steps per split
+}
+
+// pollardRho returns a non-trivial divisor of the odd composite n.
+//
+// It iterates x → x² + c (mod n) at two speeds (Floyd's cycle finding).
+// Modulo an unknown prime factor p the sequence must repeat within about
+// √p steps, and when the two runners meet mod p, gcd(|x-y|, n) reveals
+// p. If they meet mod n as well the attempt failed, and a new c is tried.
+func pollardRho(n uint64) uint64 {
+	for c := uint64(1); ; c++ {
+		f := func(x uint64) uint64 { return addMod(numtheory.MulMod(x, x, n), c, n) }
+		x, y, d := uint64(2), uint64(2), uint64(1)
+		for d == 1 {
+			x = f(x)
+			y = f(f(y))
+			d = numtheory.BinaryGCD(max(x, y)-min(x, y), n)
+		}
+		if d != n {
+			return d
+		}
+	}
+}
+
+// addMod returns a+b mod m for a, b < m without overflow.
+func addMod(a, b, m uint64) uint64 {
+	sum, carry := bits.Add64(a, b, 0)
+	if carry != 0 || sum >= m {
+		sum -= m
+	}
+	return sum
+}
+
+// isqrt returns ⌊√n⌋, correcting float64 rounding near 2^64.
+func isqrt(n uint64) uint64 {
+	r := min(uint64(math.Sqrt(float64(n))), 1<<32-1)
+	for r*r > n {
+		r--
+	}
+	for r < 1<<32-1 && (r+1)*(r+1) <= n {
+		r++
+	}
+	return r
+}
@@ -0,0 +1,281 @@
+// Package workerpool compares three ways to process many tasks concurrently.
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/metrics"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// A goroutine per task keeps up to n goroutine stacks alive at once, each
+// at least 2 KiB.
+const maxVibeN = 100_000
+
+// Rounds of mixing per task, a few microseconds of CPU work.
+const taskRounds = 1_000
+
+// hashTask is the unit of work: it mixes i through taskRounds rounds of
+// the splitmix64 finalizer. A task that starts after ctx is done returns
+// ctx.Err() instead.
+func hashTask(ctx context.Context, i int) (uint64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	x := uint64(i)
+	for range taskRounds {
+		x += 0x9e3779b97f4a7c15
+		x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+		x = (x ^ x>>27) * 0x94d049bb133111eb
+		x ^= x >> 31
+	}
+	return x, nil
+}
+
+// processor is the signature shared by the three implementations.
+type processor func(ctx context.Context, n int, do func(ctx context.Context, i int) (uint64, error)) ([]uint64, error)
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	process          processor
+}{
+	{"Vibe coding", "goroutine per task", vibeProcess},
+	{"Human coding", "worker pool + WaitGroup", humanProcess},
+	{"Expert coding", "bounded group + cancellation", expertProcess},
+}
+
+// processorsFor returns the implementations to compare on n tasks,
+// leaving out a goroutine per task where the stacks alone would take
+// hundreds of megabytes.
+func processorsFor(n int) []bench.Impl[int, []uint64] {
+	impls := []bench.Impl[int, []uint64]{}
+	for _, t := range tiers {
+		if t.name == "Vibe coding" && n > maxVibeN {
+			continue
+		}
+		impls = append(impls, bench.Impl[int, []uint64]{
+			Name: t.name, Complexity: t.complexity,
+			FuncContext: func(ctx context.Context, n int) ([]uint64, error) {
+				return t.process(ctx, n, hashTask)
+			},
+		})
+	}
+	return impls
+}
+
+// impls returns the implementations timed processing n tasks.
+func impls(n int) []bench.Implementation {
+	var list []bench.Implementation
+	for _, p := range processorsFor(n) {
+		list = append(list, p.Implementation(n))
+	}
+	return list
+}
+
+// peakUsage runs f while sampling the number of goroutines and the
+// memory used by goroutine stacks, which the heap figures in the results
+// table leave out, and returns the largest values seen; stack memory is
+// counted above what was in use before f started. Sampling can miss a
+// short spike, so both are lower bounds.
+func peakUsage(f func()) (goroutines int, stackBytes uint64) {
+	stacks := []metrics.Sample{{Name: "/memory/classes/heap/stacks:bytes"}}
+	runtime.GC() // free the stacks of earlier runs' goroutines
+	metrics.Read(stacks)
+	baseline := stacks[0].Value.Uint64()
+	done := make(chan struct{})
+	sampled := make(chan struct{})
+	go func() {
+		defer close(sampled)
+		for {
+			metrics.Read(stacks)
+			goroutines = max(goroutines, runtime.NumGoroutine()-1) // not counting the sampler
+			stackBytes = max(stackBytes, stacks[0].Value.Uint64())
+			select {
+			case <-done:
+				return
+			case <-time.After(50 * time.Microsecond):
+			}
+		}
+	}()
+	f()
+	close(done)
+	<-sampled
+	return goroutines, stackBytes - min(stackBytes, baseline)
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "one goroutine per task", Complexity: "O(n) goroutines", Notes: []report.Note{
+			report.Strength("Shortest code, and goroutines really are cheap"),
+			report.Pitfall("n goroutines alive at once: memory grows with the input"),
+			report.Pitfall("A failure stops nothing - every task still runs"),
+		}},
+		{Label: "Human coding", Approach: "fixed worker pool with sync.WaitGroup", Complexity: "O(P) goroutines", Notes: []report.Note{
+			report.Strength("GOMAXPROCS goroutines no matter how many tasks"),
+			report.Strength("Same throughput for CPU-bound work - there are no more cores to use"),
+			report.Pitfall("Errors are collected, but the queue keeps draining"),
+		}},
+		{Label: "Expert coding", Approach: "errgroup-style bounded group with cancellation", Complexity: "O(P) goroutines", Notes: []report.Note{
+			report.Strength("Limit on running tasks gives backpressure to the producer"),
+			report.Strength("First error cancels the context: no new tasks, running ones give up"),
+			report.Strength("Caller cancellation (Ctrl-C, -timeout) works the same way"),
+			report.Pitfall("Every task must check ctx for cancellation to be prompt"),
+		}},
+	},
+	Takeaway: "For CPU-bound work more goroutines than cores don't add throughput - " +
+		"they add memory. Bound the concurrency, and make failure and " +
+		"cancellation stop the work, not just get reported at the end.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "09-worker-pool",
+		Title:       "Concurrent Task Processing",
+		Description: "Process many tasks concurrently, and see what happens to goroutine count and wasted work when one fails.",
+		Category:    "concurrency",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    10_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("09-worker-pool", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{1_000, 10_000, 100_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of tasks, e.g. 1e4,1e6")
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Concurrent Task Processing", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Concurrent Task Processing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "GOMAXPROCS = %d\n", runtime.GOMAXPROCS(0))
+
+	for _, n := range sizes {
+		fmt.Fprintf(out.Table, "\nProcessing %d tasks:\n", n)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		// Sequential reference answer.
+		want := make([]uint64, n)
+		for i := range want {
+			want[i], _ = hashTask(ctx, i)
+		}
+
+		impls := processorsFor(n)
+		results, err := bench.CompareImpls(ctx, opts, n, want, bench.DiffSlices, impls...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "✔ All implementations produce the same results")
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d tasks", n), results)
+
+		// Throughput from the timed runs; goroutine counts from one more,
+		// untimed run each, since sampling would disturb the timings.
+		fmt.Fprintln(out.Table, "\nThroughput and concurrency:")
+		for i, r := range results {
+			goroutines, stackBytes := peakUsage(func() { impls[i].FuncContext(ctx, n) })
+			fmt.Fprintf(out.Table, "  %-14s %9.0f tasks/s   peak %6d goroutines, %9s of stacks\n",
+				r.Name+":", float64(n)/r.Duration.Seconds(), goroutines, bench.FormatBytes(stackBytes))
+		}
+		if n > maxVibeN {
+			note := fmt.Sprintf("Vibe coding skipped: a goroutine per task is impractical above n=%d", maxVibeN)
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// What happens when a task fails part-way through.
+	fmt.Fprintln(out.Table)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(out.Table, "When one task fails")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	const failN, failAt = 100_000, 1_000
+	errTask := errors.New("task failed")
+	fmt.Fprintf(w, "Task %d of %d returns an error. How many tasks run anyway?\n\n", failAt, failN)
+	for _, t := range tiers {
+		var ran atomic.Int64
+		_, err := t.process(ctx, failN, func(ctx context.Context, i int) (uint64, error) {
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+			ran.Add(1)
+			if i == failAt {
+				return 0, fmt.Errorf("task %d: %w", i, errTask)
+			}
+			return hashTask(ctx, i)
+		})
+		if !errors.Is(err, errTask) {
+			return fmt.Errorf("%s: got error %v, want the failed task's error", t.name, err)
+		}
+		verdict := "❌ finished the whole queue"
+		if int(ran.Load()) < failN {
+			verdict = "✅ stopped early"
+		}
+		fmt.Fprintf(out.Table, "  %-14s %7d of %d tasks ran   %s\n", t.name+":", ran.Load(), failN, verdict)
+		rep.AddEdgeCase(fmt.Sprintf("%s, task %d of %d fails", t.name, failAt, failN), fmt.Sprintf("%d tasks ran", ran.Load()))
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
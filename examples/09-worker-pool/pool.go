@@ -0,0 +1,155 @@
+package workerpool
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// VIBE CODING: One goroutine per task
+func vibeProcess(ctx context.Context, n int, do func(ctx context.Context, i int) (uint64, error)) ([]uint64, error) {
+	/*
+	   Run tasks 0..n-1 concurrently and collect their results
+
+	   Goroutines are cheap, so start one for every task and wait for
+	   them all. Cheap is not free: n tasks means n goroutine stacks
+	   alive at once, and nothing stops the others when one fails.
+	*/
+	results := make([]uint64, n)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := do(ctx, i)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				return
+			}
+			results[i] = r
+		}()
+	}
+	wg.Wait()
+	return results, firstErr // n goroutines, every task runs even after a failure
+}
+
+// HUMAN CODING: A fixed pool of workers
+func humanProcess(ctx context.Context, n int, do func(ctx context.Context, i int) (uint64, error)) ([]uint64, error) {
+	/*
+	   Run tasks 0..n-1 concurrently and collect their results
+
+	   Start one worker per CPU and feed them task numbers over a
+	   channel. The number of goroutines no longer depends on n, but a
+	   failed task still doesn't stop the rest of the queue.
+	*/
+	workers := runtime.GOMAXPROCS(0)
+	results := make([]uint64, n)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				r, err := do(ctx, i)
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+					continue
+				}
+				results[i] = r
+			}
+		}()
+	}
+	for i := range n {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results, firstErr // GOMAXPROCS goroutines, no cancellation
+}
+
+// EXPERT CODING: A bounded group with cancellation
+func expertProcess(ctx context.Context, n int, do func(ctx context.Context, i int) (uint64, error)) ([]uint64, error) {
+	/*
+	   Run tasks 0..n-1 concurrently and collect their results
+
+	   The errgroup pattern: at most GOMAXPROCS tasks run at once, and
+	   starting another blocks until one finishes - backpressure, so a
+	   fast producer can't outrun the workers. The first error cancels
+	   the group's context, which stops new tasks from starting and
+	   tells running ones to give up.
+	*/
+	g, gctx := withLimit(ctx, runtime.GOMAXPROCS(0))
+	results := make([]uint64, n)
+	for i := range n {
+		if gctx.Err() != nil {
+			break // a task failed or the caller gave up: start no more
+		}
+		g.Go(func() error {
+			r, err := do(gctx, i)
+			results[i] = r
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return results, err
+	}
+	return results, ctx.Err() // GOMAXPROCS goroutines at a time, stops at the first error
+}
+
+// group is a minimal version of golang.org/x/sync/errgroup with SetLimit,
+// written out here because the examples use only the standard library.
+// In real code, use errgroup.
+type group struct {
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+	sem    chan struct{}
+	once   sync.Once
+	err    error
+}
+
+// withLimit returns a group running at most limit functions at a time,
+// and a context that is cancelled when one of them fails or Wait returns.
+func withLimit(ctx context.Context, limit int) (*group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &group{cancel: cancel, sem: make(chan struct{}, limit)}, ctx
+}
+
+// Go runs f in a new goroutine, first blocking until fewer than limit
+// are running.
+func (g *group) Go(f func() error) {
+	g.sem <- struct{}{}
+	g.wg.Add(1)
+	go func() {
+		defer func() {
+			<-g.sem
+			g.wg.Done()
+		}()
+		if err := f(); err != nil {
+			g.once.Do(func() {
+				g.err = err
+				g.cancel(err)
+			})
+		}
+	}()
+}
+
+// Wait waits for every function started with Go and returns the first
+// error any of them returned.
+func (g *group) Wait() error {
+	g.wg.Wait()
+	g.cancel(g.err)
+	return g.err
+}
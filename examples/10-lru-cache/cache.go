@@ -0,0 +1,182 @@
+package lrucache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// cache is what the three implementations have in common. All are safe
+// for concurrent use and hold at most their capacity of entries, evicting
+// the least recently used one (or, for the sharded cache, one of the
+// least recently used) to make room.
+type cache interface {
+	Get(key int) (value int, ok bool)
+	Put(key, value int)
+	Len() int
+}
+
+// VIBE CODING: A map with last-used timestamps
+type vibeCache struct {
+	mu       sync.Mutex
+	capacity int
+	clock    uint64 // Incremented on every access
+	entries  map[int]vibeEntry
+}
+
+type vibeEntry struct {
+	value    int
+	lastUsed uint64
+}
+
+func newVibeCache(capacity int) *vibeCache {
+	return &vibeCache{capacity: capacity, entries: make(map[int]vibeEntry, capacity)}
+}
+
+func (c *vibeCache) Get(key int) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return 0, false
+	}
+	c.clock++
+	e.lastUsed = c.clock
+	c.entries[key] = e
+	return e.value, true
+}
+
+func (c *vibeCache) Put(key, value int) {
+	/*
+	   Store value under key, evicting the least recently used entry if
+	   the cache is full
+
+	   Every entry remembers when it was last used, so the victim is
+	   whichever has the oldest timestamp - found by looking at all of
+	   them, while every other goroutine waits for the lock.
+	*/
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock++
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.capacity {
+		oldest, oldestUsed := 0, ^uint64(0)
+		for k, e := range c.entries {
+			if e.lastUsed < oldestUsed {
+				oldest, oldestUsed = k, e.lastUsed
+			}
+		}
+		delete(c.entries, oldest)
+	}
+	c.entries[key] = vibeEntry{value: value, lastUsed: c.clock} // O(capacity) per eviction
+}
+
+func (c *vibeCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// HUMAN CODING: A doubly linked list plus a map
+type humanCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // Front is most recently used; values are *humanEntry
+	entries  map[int]*list.Element
+}
+
+type humanEntry struct {
+	key, value int
+}
+
+func newHumanCache(capacity int) *humanCache {
+	return &humanCache{capacity: capacity, order: list.New(), entries: make(map[int]*list.Element, capacity)}
+}
+
+func (c *humanCache) Get(key int) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return 0, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*humanEntry).value, true
+}
+
+func (c *humanCache) Put(key, value int) {
+	/*
+	   Store value under key, evicting the least recently used entry if
+	   the cache is full
+
+	   The list keeps entries in order of use, so the victim is always
+	   at the back, and the map finds any entry's list node directly:
+	   every operation is O(1). But one mutex still guards it all, so
+	   concurrent callers take turns.
+	*/
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*humanEntry).value = value
+		c.order.MoveToFront(el)
+		return
+	}
+	if c.order.Len() >= c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*humanEntry).key)
+	}
+	c.entries[key] = c.order.PushFront(&humanEntry{key, value}) // O(1)
+}
+
+func (c *humanCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// EXPERT CODING: Lock striping across independent LRU shards
+type expertCache struct {
+	shards []*humanCache
+}
+
+// maxShards bounds how many independently locked shards a cache is split
+// into; more shards mean less contention but a less exact LRU order.
+const maxShards = 16
+
+func newExpertCache(capacity int) *expertCache {
+	/*
+	   Split the cache into shards, each a complete list+map LRU with
+	   its own lock, and send every key to the shard its hash picks
+
+	   Goroutines working on different shards never wait for each
+	   other. The price: each shard evicts its own least recently used
+	   entry, which is not always the globally least recently used one.
+	*/
+	n := min(maxShards, capacity)
+	c := &expertCache{shards: make([]*humanCache, n)}
+	for i := range c.shards {
+		size := capacity / n
+		if i < capacity%n {
+			size++ // shard sizes add up to exactly capacity
+		}
+		c.shards[i] = newHumanCache(size)
+	}
+	return c
+}
+
+// shard returns the shard responsible for key, using a multiplicative
+// hash so that consecutive keys spread across shards.
+func (c *expertCache) shard(key int) *humanCache {
+	h := uint64(key) * 0x9e3779b97f4a7c15
+	return c.shards[(h>>32)%uint64(len(c.shards))]
+}
+
+func (c *expertCache) Get(key int) (int, bool) { return c.shard(key).Get(key) }
+func (c *expertCache) Put(key, value int)      { c.shard(key).Put(key, value) }
+
+func (c *expertCache) Len() int {
+	n := 0
+	for _, s := range c.shards {
+		n += s.Len()
+	}
+	return n
+}
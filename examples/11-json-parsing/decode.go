@@ -0,0 +1,394 @@
+package jsonparsing
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// summary is what every implementation computes from a JSON array of
+// orders: how many orders and items there are, and the revenue in cents
+// from the orders that weren't cancelled.
+type summary struct {
+	Orders  int
+	Items   int
+	Revenue int64
+}
+
+// VIBE CODING: Unmarshal into interface{}
+func vibeDecode(data []byte) (summary, error) {
+	/*
+	   Summarize a JSON array of orders
+
+	   Decode everything into maps and slices of interface{} and dig the
+	   fields out with type assertions. It needs no types at all, but
+	   every object becomes a map, every string a new allocation, and
+	   every number a float64 - including the fields nobody reads.
+	*/
+	var orders []interface{}
+	if err := json.Unmarshal(data, &orders); err != nil {
+		return summary{}, err
+	}
+	var s summary
+	for _, o := range orders {
+		order, _ := o.(map[string]interface{})
+		items, _ := order["items"].([]interface{})
+		status, _ := order["status"].(string)
+		s.Orders++
+		for _, it := range items {
+			item, _ := it.(map[string]interface{})
+			qty, _ := item["qty"].(float64)
+			price, _ := item["price_cents"].(float64)
+			s.Items += int(qty)
+			if status != "cancelled" {
+				s.Revenue += int64(qty * price)
+			}
+		}
+	}
+	return s, nil // O(size), an allocation for every value in the document
+}
+
+// HUMAN CODING: Struct tags and a streaming json.Decoder
+type order struct {
+	ID       int    `json:"id"`
+	Customer string `json:"customer"`
+	Status   string `json:"status"`
+	Items    []item `json:"items"`
+}
+
+type item struct {
+	SKU        string `json:"sku"`
+	Qty        int    `json:"qty"`
+	PriceCents int64  `json:"price_cents"`
+}
+
+func humanDecode(data []byte) (summary, error) {
+	/*
+	   Summarize a JSON array of orders
+
+	   Declare the fields we need as a struct and let json.Decoder fill
+	   one order at a time: unknown fields are skipped instead of
+	   stored, numbers land in ints, and only one order is in memory at
+	   once, so the same code works on a stream of any length.
+	*/
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil {
+		return summary{}, err
+	} else if tok != json.Delim('[') {
+		return summary{}, fmt.Errorf("expected an array, got %v", tok)
+	}
+	var s summary
+	for dec.More() {
+		var o order
+		if err := dec.Decode(&o); err != nil {
+			return summary{}, err
+		}
+		s.Orders++
+		for _, it := range o.Items {
+			s.Items += it.Qty
+			if o.Status != "cancelled" {
+				s.Revenue += int64(it.Qty) * it.PriceCents
+			}
+		}
+	}
+	if _, err := dec.Token(); err != nil { // the closing ]
+		return summary{}, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return summary{}, errors.New("invalid data after top-level value")
+	}
+	return s, nil // O(size), allocations only for the fields in the struct
+}
+
+// EXPERT CODING: A hand-rolled scanner for exactly this schema
+func expertDecode(data []byte) (summary, error) {
+	/*
+	   Summarize a JSON array of orders
+
+	   Walk the bytes once, looking only at the three fields the summary
+	   needs and skipping every other value without building it. Strings
+	   are compared in place, numbers parsed straight into ints: no
+	   reflection and no allocations. The price is two hundred lines
+	   that must reject malformed input as carefully as encoding/json.
+	*/
+	sc := scanner{data: data}
+	var s summary
+	err := sc.array(func() error {
+		s.Orders++
+		var status []byte
+		var qty, cents int64 // sums over the order's items
+		err := sc.object(func(key []byte) error {
+			var err error
+			switch string(key) { // compiled to a comparison, no allocation
+			case "status":
+				status, err = sc.str()
+			case "items":
+				err = sc.array(func() error {
+					var q, c int64
+					err := sc.object(func(key []byte) error {
+						var err error
+						switch string(key) {
+						case "qty":
+							q, err = sc.integer()
+						case "price_cents":
+							c, err = sc.integer()
+						default:
+							err = sc.skip()
+						}
+						return err
+					})
+					qty += q
+					cents += q * c
+					return err
+				})
+			default:
+				err = sc.skip()
+			}
+			return err
+		})
+		s.Items += int(qty)
+		if string(status) != "cancelled" {
+			s.Revenue += cents
+		}
+		return err
+	})
+	if err != nil {
+		return summary{}, err
+	}
+	if sc.space(); sc.pos < len(sc.data) {
+		return summary{}, sc.errorf("data after the top-level array")
+	}
+	return s, nil // O(size), zero allocations
+}
+
+// scanner reads JSON values from data, starting at pos.
+type scanner struct {
+	data []byte
+	pos  int
+}
+
+func (sc *scanner) errorf(format string, args ...any) error {
+	return fmt.Errorf("offset %d: "+format, append([]any{sc.pos}, args...)...)
+}
+
+// space skips whitespace.
+func (sc *scanner) space() {
+	for sc.pos < len(sc.data) {
+		switch sc.data[sc.pos] {
+		case ' ', '\t', '\n', '\r':
+			sc.pos++
+		default:
+			return
+		}
+	}
+}
+
+// peek skips whitespace and returns the next byte, or 0 at the end.
+func (sc *scanner) peek() byte {
+	sc.space()
+	if sc.pos == len(sc.data) {
+		return 0
+	}
+	return sc.data[sc.pos]
+}
+
+// expect consumes c, which must be the next byte after whitespace.
+func (sc *scanner) expect(c byte) error {
+	if got := sc.peek(); got != c {
+		if got == 0 {
+			return sc.errorf("unexpected end of input, expected %q", c)
+		}
+		return sc.errorf("got %q, expected %q", got, c)
+	}
+	sc.pos++
+	return nil
+}
+
+// array reads an array, calling elem to read each element.
+func (sc *scanner) array(elem func() error) error {
+	if err := sc.expect('['); err != nil {
+		return err
+	}
+	if sc.peek() == ']' {
+		sc.pos++
+		return nil
+	}
+	for {
+		if err := elem(); err != nil {
+			return err
+		}
+		if sc.peek() != ',' {
+			return sc.expect(']')
+		}
+		sc.pos++
+	}
+}
+
+// object reads an object, calling field with each key to read its value.
+func (sc *scanner) object(field func(key []byte) error) error {
+	if err := sc.expect('{'); err != nil {
+		return err
+	}
+	if sc.peek() == '}' {
+		sc.pos++
+		return nil
+	}
+	for {
+		key, err := sc.str()
+		if err != nil {
+			return err
+		}
+		if err := sc.expect(':'); err != nil {
+			return err
+		}
+		if err := field(key); err != nil {
+			return err
+		}
+		if sc.peek() != ',' {
+			return sc.expect('}')
+		}
+		sc.pos++
+	}
+}
+
+// str reads a string and returns its contents. Escaped strings are rare,
+// so they are handed to encoding/json to decode, which allocates.
+func (sc *scanner) str() ([]byte, error) {
+	start := sc.pos
+	raw, escaped, err := sc.rawString()
+	if err != nil || !escaped {
+		return raw, err
+	}
+	var s string
+	if err := json.Unmarshal(sc.data[start:sc.pos], &s); err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+// rawString reads a string and returns its contents with any escapes
+// left as they are, and whether there were any.
+func (sc *scanner) rawString() (raw []byte, escaped bool, err error) {
+	if err := sc.expect('"'); err != nil {
+		return nil, false, err
+	}
+	start := sc.pos
+	for sc.pos < len(sc.data) {
+		switch c := sc.data[sc.pos]; {
+		case c == '"':
+			sc.pos++
+			return sc.data[start : sc.pos-1], escaped, nil
+		case c == '\\':
+			escaped = true
+			sc.pos += 2
+		case c < ' ':
+			return nil, false, sc.errorf("control character in string")
+		default:
+			sc.pos++
+		}
+	}
+	return nil, false, sc.errorf("unterminated string")
+}
+
+// integer reads a number with no fraction or exponent.
+func (sc *scanner) integer() (int64, error) {
+	sc.space()
+	start := sc.pos
+	if err := sc.number(); err != nil {
+		return 0, err
+	}
+	digits := sc.data[start:sc.pos]
+	neg := digits[0] == '-'
+	if neg {
+		digits = digits[1:]
+	}
+	var n int64
+	for _, d := range digits {
+		if d < '0' || d > '9' {
+			return 0, sc.errorf("expected an integer, got %q", sc.data[start:sc.pos])
+		}
+		if n > (1<<63-1-int64(d-'0'))/10 {
+			return 0, sc.errorf("integer %q overflows int64", sc.data[start:sc.pos])
+		}
+		n = n*10 + int64(d-'0')
+	}
+	if neg {
+		n = -n
+	}
+	return n, nil
+}
+
+// skip reads any value and discards it.
+func (sc *scanner) skip() error {
+	switch c := sc.peek(); {
+	case c == '{':
+		return sc.object(func([]byte) error { return sc.skip() })
+	case c == '[':
+		return sc.array(sc.skip)
+	case c == '"':
+		_, _, err := sc.rawString()
+		return err
+	case c == '-' || '0' <= c && c <= '9':
+		return sc.number()
+	case c == 't':
+		return sc.literal("true")
+	case c == 'f':
+		return sc.literal("false")
+	case c == 'n':
+		return sc.literal("null")
+	case c == 0:
+		return sc.errorf("unexpected end of input")
+	default:
+		return sc.errorf("unexpected %q", c)
+	}
+}
+
+// number reads any JSON number: -?(0|[1-9][0-9]*)(.[0-9]+)?([eE][+-]?[0-9]+)?
+func (sc *scanner) number() error {
+	sc.space()
+	start := sc.pos
+	sc.accept("-")
+	if !sc.accept("0") && sc.digits() == 0 {
+		return sc.errorf("invalid number %q", sc.data[start:sc.pos])
+	}
+	if sc.accept(".") && sc.digits() == 0 {
+		return sc.errorf("invalid number %q", sc.data[start:sc.pos])
+	}
+	if sc.accept("eE") {
+		sc.accept("+-")
+		if sc.digits() == 0 {
+			return sc.errorf("invalid number %q", sc.data[start:sc.pos])
+		}
+	}
+	return nil
+}
+
+// accept consumes the next byte if it is one of chars.
+func (sc *scanner) accept(chars string) bool {
+	if sc.pos < len(sc.data) && strings.IndexByte(chars, sc.data[sc.pos]) >= 0 {
+		sc.pos++
+		return true
+	}
+	return false
+}
+
+// digits consumes a run of decimal digits and returns its length.
+func (sc *scanner) digits() int {
+	start := sc.pos
+	for sc.pos < len(sc.data) && '0' <= sc.data[sc.pos] && sc.data[sc.pos] <= '9' {
+		sc.pos++
+	}
+	return sc.pos - start
+}
+
+// literal reads the literal word, e.g. true.
+func (sc *scanner) literal(word string) error {
+	if !bytes.HasPrefix(sc.data[sc.pos:], []byte(word)) {
+		return sc.errorf("invalid literal, expected %s", word)
+	}
+	sc.pos += len(word)
+	return nil
+}
@@ -0,0 +1,258 @@
+// Package jsonparsing compares three ways to decode a JSON document.
+package jsonparsing
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// payload is a sample of 24 orders from an online shop, with the nested
+// objects, nulls, escapes and non-ASCII text real documents have.
+//
+//go:embed payload.json
+var payload []byte
+
+// makeDocument returns a JSON array of n orders, cycling through the
+// orders in payload.
+func makeDocument(n int) ([]byte, error) {
+	var orders []json.RawMessage
+	if err := json.Unmarshal(payload, &orders); err != nil {
+		return nil, fmt.Errorf("payload.json: %w", err)
+	}
+	var buf bytes.Buffer
+	buf.WriteString("[\n")
+	for i := range n {
+		if i > 0 {
+			buf.WriteString(",\n")
+		}
+		buf.Write(orders[i%len(orders)])
+	}
+	buf.WriteString("\n]\n")
+	return buf.Bytes(), nil
+}
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	decode           func([]byte) (summary, error)
+}{
+	{"Vibe coding", "map[string]interface{}", vibeDecode},
+	{"Human coding", "structs + json.Decoder", humanDecode},
+	{"Expert coding", "hand-rolled scanner", expertDecode},
+}
+
+// decoders returns the implementations to compare, each failing with
+// its name on a document it cannot decode.
+func decoders() []bench.Impl[[]byte, summary] {
+	impls := make([]bench.Impl[[]byte, summary], len(tiers))
+	for i, t := range tiers {
+		impls[i] = bench.Impl[[]byte, summary]{
+			Name: t.name, Complexity: t.complexity,
+			FuncContext: func(ctx context.Context, doc []byte) (summary, error) {
+				s, err := t.decode(doc)
+				if err != nil {
+					return s, fmt.Errorf("%s: %w", t.name, err)
+				}
+				return s, ctx.Err()
+			},
+		}
+	}
+	return impls
+}
+
+// impls returns the implementations timed decoding n orders.
+func impls(n int) []bench.Implementation {
+	doc, err := makeDocument(n)
+	if err != nil {
+		panic(err) // payload.json is embedded, so this fails on every run
+	}
+	var list []bench.Implementation
+	for _, d := range decoders() {
+		list = append(list, d.Implementation(doc))
+	}
+	return list
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "json.Unmarshal into map[string]interface{}", Complexity: "O(size), allocates every value", Notes: []report.Note{
+			report.Strength("No types to declare - one line decodes anything"),
+			report.Pitfall("Builds a map for every object and boxes every value, read or not"),
+			report.Pitfall("Numbers become float64; typos in field names fail silently"),
+		}},
+		{Label: "Human coding", Approach: "struct tags + streaming json.Decoder", Complexity: "O(size), allocates the fields it keeps", Notes: []report.Note{
+			report.Strength("Only the declared fields are kept; the rest is skipped"),
+			report.Strength("Streams one order at a time: only one decoded order in memory"),
+			report.Strength("The compiler checks the field types"),
+			report.Pitfall("Still pays for reflection on every field"),
+		}},
+		{Label: "Expert coding", Approach: "hand-rolled scanner for one schema", Complexity: "O(size), no allocations", Notes: []report.Note{
+			report.Strength("No reflection, no allocations, several times faster"),
+			report.Pitfall("Hundreds of lines that know one schema, and must reject bad input as carefully as encoding/json - every edge case above is a bug someone once shipped"),
+			report.Tip("Code generators such as easyjson write this code for you"),
+		}},
+	},
+	Takeaway: "Decode into structs by default. Hand-roll a parser only for a hot " +
+		"path you have profiled, and test it against encoding/json.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "11-json-parsing",
+		Title:       "JSON Parsing",
+		Description: "Decode a JSON array of orders three ways, and count what each allocates.",
+		Category:    "parsing",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    1_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("11-json-parsing", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{100, 1_000, 10_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of orders in the document, e.g. 1e3,1e5")
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	for _, n := range sizes {
+		if n < 1 {
+			return fmt.Errorf("-n must be at least 1, not %d", n)
+		}
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("JSON Parsing", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: JSON Parsing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "Sample payload: payload.json, %s\n", bench.FormatBytes(uint64(len(payload))))
+
+	for _, n := range sizes {
+		doc, err := makeDocument(n)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out.Table, "\nDecoding %d orders (%s of JSON):\n", n, bench.FormatBytes(uint64(len(doc))))
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		want, err := humanDecode(doc)
+		if err != nil {
+			return fmt.Errorf("verification failed: Human coding: %w", err)
+		}
+		results, err := bench.CompareImpls(ctx, opts, doc, want, bench.Equal, decoders()...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "✔ All implementations agree: %d orders, %d items, revenue $%d.%02d\n",
+			want.Orders, want.Items, want.Revenue/100, want.Revenue%100)
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		rep.Add(fmt.Sprintf("n = %d orders", n), results)
+
+		fmt.Fprintln(out.Table, "\nThroughput and allocations:")
+		for _, r := range results {
+			fmt.Fprintf(out.Table, "  %-14s %8.1f MB/s   %8.1f allocs per order\n",
+				r.Name+":", float64(len(doc))/1e6/r.Duration.Seconds(), float64(r.Allocs)/float64(n))
+		}
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		doc     string
+		desc    string
+		invalid bool
+	}{
+		{`[]`, "empty array", false},
+		{` [ { "items" : [ { "qty" : 2 , "price_cents" : 150 } ] } ] `, "whitespace everywhere", false},
+		{`[{"status":"cancel\u006ced","items":[{"qty":1,"price_cents":999}]}]`, "escaped status", false},
+		{`[{"extra":{"deep":[[{"x":null}],true,-1.5e3]},"items":[]}]`, "unknown nested fields", false},
+		{`[{"items":[{"qty":1,"price_cents":100}]`, "truncated document", true},
+		{`[{"items":[]},]`, "trailing comma", true},
+		{`[{"items":[{"qty":01}]}]`, "leading zero", true},
+		{`[] []`, "two documents", true},
+	}
+	for _, tc := range edgeCases {
+		var got []string
+		agree, rejected := true, 0
+		for _, t := range tiers {
+			s, err := t.decode([]byte(tc.doc))
+			if err != nil {
+				rejected++
+				got = append(got, "error")
+				continue
+			}
+			got = append(got, fmt.Sprintf("%+v", s))
+			agree = agree && got[len(got)-1] == got[0]
+		}
+		status := "✅"
+		switch {
+		case tc.invalid && rejected < len(tiers), !tc.invalid && (rejected > 0 || !agree):
+			status = "❌"
+		}
+		result := got[0]
+		if tc.invalid {
+			result = fmt.Sprintf("rejected by %d of %d", rejected, len(tiers))
+		}
+		fmt.Fprintf(w, "%s %s: %s\n", status, tc.desc, result)
+		rep.AddEdgeCase(tc.desc, result)
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
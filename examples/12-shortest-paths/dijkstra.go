@@ -0,0 +1,198 @@
+package shortestpaths
+
+import (
+	"container/heap"
+	"math"
+	"slices"
+)
+
+// edge is a directed edge to vertex to that costs w to follow. Weights
+// are never negative, which is all Dijkstra's algorithm needs.
+type edge struct {
+	to, w int
+}
+
+// graph is a weighted directed graph stored as adjacency lists: adj[v]
+// holds the edges leaving vertex v.
+type graph struct {
+	adj [][]edge
+}
+
+// route is what a search found: the cost of a cheapest path and the
+// vertices along it from source to target, or -1 and nil if the target
+// cannot be reached, and how many vertices it settled on the way.
+type route struct {
+	dist    int
+	path    []int
+	settled int
+}
+
+// unreached marks a vertex whose distance is not known yet.
+const unreached = math.MaxInt
+
+// VIBE CODING: Scan every vertex for the closest one, every step
+func vibeDijkstra(g *graph, src, dst int) route {
+	/*
+	   Find a cheapest path from src to dst
+
+	   Dijkstra's algorithm as the textbook first draws it: repeatedly
+	   settle the closest vertex not yet settled, and relax its edges.
+	   Finding that vertex means scanning the whole distance array, so a
+	   million-vertex grid costs a million scans of a million entries -
+	   even though each vertex has only four neighbours.
+	*/
+	n := len(g.adj)
+	dist := make([]int, n)
+	prev := make([]int, n)
+	done := make([]bool, n)
+	for v := range dist {
+		dist[v], prev[v] = unreached, -1
+	}
+	dist[src] = 0
+
+	settled := 0
+	for {
+		u := -1
+		for v := range n {
+			if !done[v] && dist[v] != unreached && (u < 0 || dist[v] < dist[u]) {
+				u = v
+			}
+		}
+		if u < 0 {
+			break // everything reachable is settled
+		}
+		done[u] = true
+		settled++
+		if u == dst {
+			break
+		}
+		for _, e := range g.adj[u] {
+			if d := dist[u] + e.w; d < dist[e.to] {
+				dist[e.to], prev[e.to] = d, u
+			}
+		}
+	}
+	return newRoute(dist, prev, dst, settled) // O(V²) - a full scan per vertex settled
+}
+
+// HUMAN CODING: Keep the frontier in a binary heap
+func humanDijkstra(g *graph, src, dst int) route {
+	/*
+	   Find a cheapest path from src to dst
+
+	   The same algorithm, but the frontier lives in a binary heap, so the
+	   closest vertex comes off the top in O(log V). container/heap has no
+	   decrease-key, so an improved distance is pushed again and the
+	   stale entry skipped when it surfaces ("lazy deletion") - simpler
+	   than an indexed heap and just as fast in practice.
+	*/
+	dist := make([]int, len(g.adj))
+	prev := make([]int, len(g.adj))
+	for v := range dist {
+		dist[v], prev[v] = unreached, -1
+	}
+	dist[src] = 0
+	pq := &frontier{{v: src}}
+
+	settled := 0
+	for pq.Len() > 0 {
+		it := heap.Pop(pq).(item)
+		if it.g > dist[it.v] {
+			continue // a cheaper path to it.v was found after this push
+		}
+		settled++
+		if it.v == dst {
+			break
+		}
+		for _, e := range g.adj[it.v] {
+			if d := it.g + e.w; d < dist[e.to] {
+				dist[e.to], prev[e.to] = d, it.v
+				heap.Push(pq, item{v: e.to, f: d, g: d})
+			}
+		}
+	}
+	return newRoute(dist, prev, dst, settled) // O((V + E) log V)
+}
+
+// EXPERT CODING: A* - let a heuristic pull the search towards the goal
+func expertAStar(g *graph, src, dst int, h func(v int) int) route {
+	/*
+	   Find a cheapest path from src to dst, guided by h
+
+	   Dijkstra grows a circle around src; A* orders the heap by
+	   f = g + h, the cost so far plus an estimate of the cost still to
+	   go, so the search stretches towards dst and leaves most of the map
+	   alone. The answer stays optimal as long as h never overestimates
+	   (admissible) and never drops by more than an edge's weight along
+	   it (consistent) - then, as in Dijkstra, a vertex's cost is final
+	   when it leaves the heap. On a grid, Manhattan distance times the
+	   cheapest step is both. Among equal f, the deeper entry goes first,
+	   so open ground with many equally good paths is not explored
+	   breadth-first.
+	*/
+	dist := make([]int, len(g.adj))
+	prev := make([]int, len(g.adj))
+	for v := range dist {
+		dist[v], prev[v] = unreached, -1
+	}
+	dist[src] = 0
+	pq := &frontier{{v: src, f: h(src)}}
+
+	settled := 0
+	for pq.Len() > 0 {
+		it := heap.Pop(pq).(item)
+		if it.g > dist[it.v] {
+			continue
+		}
+		settled++
+		if it.v == dst {
+			break
+		}
+		for _, e := range g.adj[it.v] {
+			if d := it.g + e.w; d < dist[e.to] {
+				dist[e.to], prev[e.to] = d, it.v
+				heap.Push(pq, item{v: e.to, f: d + h(e.to), g: d})
+			}
+		}
+	}
+	return newRoute(dist, prev, dst, settled) // O((V + E) log V) worst case, far fewer vertices with a good h
+}
+
+// newRoute walks prev back from dst to build the route a search found.
+func newRoute(dist, prev []int, dst, settled int) route {
+	if dist[dst] == unreached {
+		return route{dist: -1, settled: settled}
+	}
+	var path []int
+	for v := dst; v >= 0; v = prev[v] {
+		path = append(path, v)
+	}
+	slices.Reverse(path)
+	return route{dist: dist[dst], path: path, settled: settled}
+}
+
+// item is a frontier entry: vertex v reached at cost g, with priority f
+// (g itself for Dijkstra, g plus the heuristic for A*).
+type item struct {
+	v, f, g int
+}
+
+// frontier is a min-heap of items by f, deepest first among ties, for
+// container/heap.
+type frontier []item
+
+func (q frontier) Len() int { return len(q) }
+func (q frontier) Less(i, j int) bool {
+	if q[i].f != q[j].f {
+		return q[i].f < q[j].f
+	}
+	return q[i].g > q[j].g
+}
+func (q frontier) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *frontier) Push(x any)   { *q = append(*q, x.(item)) }
+func (q *frontier) Pop() any {
+	old := *q
+	it := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return it
+}
@@ -0,0 +1,560 @@
+// Package shortestpaths compares three ways to find a cheapest path
+// through a weighted map.
+package shortestpaths
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// The array-scan Dijkstra reads every vertex for each one it settles:
+// 10^12 reads on a million-cell map.
+const maxVibeV = 20_000
+
+// maxCells bounds the maps and graphs built: adjacency lists for a
+// million cells already take over 100MB.
+const maxCells = 2_000_000
+
+// A quarter of a random map is wall. By default a tenth of the rest is
+// rough ground, costing 2 to 9 to enter instead of 1.
+const (
+	wallDensity  = 0.25
+	roughDensity = 0.1
+)
+
+// graphDegree is the number of edges leaving each vertex of a random
+// graph.
+const graphDegree = 4
+
+// grid is a w×h map. cost[y*w+x] is what stepping onto cell (x, y)
+// costs, or 0 if it is a wall. Moves go up, down, left and right.
+type grid struct {
+	w, h int
+	cost []int
+}
+
+// randomGrid returns a w×h map of random walls, with the given fraction
+// of the open ground rough, and the top-left and bottom-right corners
+// open.
+func randomGrid(rng *rand.Rand, w, h int, rough float64) *grid {
+	m := &grid{w: w, h: h, cost: make([]int, w*h)}
+	for i := range m.cost {
+		switch r := rng.Float64(); {
+		case r < wallDensity:
+			m.cost[i] = 0
+		case r < wallDensity+(1-wallDensity)*rough:
+			m.cost[i] = 2 + rng.IntN(8)
+		default:
+			m.cost[i] = 1
+		}
+	}
+	m.cost[0], m.cost[len(m.cost)-1] = 1, 1
+	return m
+}
+
+// parseGrid builds a map from rows of text: '#' is a wall, '.' open
+// ground, '2' to '9' rough ground, 'S' the start and 'G' the goal. With
+// no 'G', the goal is the start.
+func parseGrid(rows ...string) (m *grid, src, dst int) {
+	m = &grid{w: len(rows[0]), h: len(rows)}
+	src, dst = -1, -1
+	for _, row := range rows {
+		for _, c := range row {
+			switch {
+			case c == '#':
+				m.cost = append(m.cost, 0)
+			case c >= '2' && c <= '9':
+				m.cost = append(m.cost, int(c-'0'))
+			default:
+				if c == 'S' {
+					src = len(m.cost)
+				} else if c == 'G' {
+					dst = len(m.cost)
+				}
+				m.cost = append(m.cost, 1)
+			}
+		}
+	}
+	if dst < 0 {
+		dst = src
+	}
+	return m, src, dst
+}
+
+// graph returns the map as a graph with a vertex per cell and an edge
+// into every open neighbour of an open cell, weighted by its cost.
+func (m *grid) graph() *graph {
+	g := &graph{adj: make([][]edge, len(m.cost))}
+	for i, c := range m.cost {
+		if c == 0 {
+			continue
+		}
+		x := i % m.w
+		for _, j := range []int{i - m.w, i + m.w, i - 1, i + 1} {
+			switch {
+			case j < 0, j >= len(m.cost), m.cost[j] == 0:
+			case j == i-1 && x == 0, j == i+1 && x == m.w-1:
+			default:
+				g.adj[i] = append(g.adj[i], edge{to: j, w: m.cost[j]})
+			}
+		}
+	}
+	return g
+}
+
+// heuristic returns A*'s estimate of the cost from any cell to dst: the
+// Manhattan distance times the cheapest cost of a step. No path can be
+// cheaper, so the estimate is admissible, and it changes by at most one
+// step's cost per step, so it is consistent too.
+func (m *grid) heuristic(dst int) func(v int) int {
+	cheapest := 0
+	for _, c := range m.cost {
+		if c > 0 && (cheapest == 0 || c < cheapest) {
+			cheapest = c
+		}
+	}
+	gx, gy := dst%m.w, dst/m.w
+	return func(v int) int {
+		return (abs(v%m.w-gx) + abs(v/m.w-gy)) * cheapest
+	}
+}
+
+// render draws the map with the cells of path marked '*'.
+func (m *grid) render(path []int) string {
+	on := make(map[int]bool, len(path))
+	for _, v := range path {
+		on[v] = true
+	}
+	var b strings.Builder
+	for i, c := range m.cost {
+		switch {
+		case c == 0:
+			b.WriteString("██")
+		case on[i]:
+			b.WriteString("* ")
+		case c == 1:
+			b.WriteString("· ")
+		default:
+			fmt.Fprintf(&b, "%d ", c)
+		}
+		if i%m.w == m.w-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// walls counts the map's wall cells.
+func (m *grid) walls() int {
+	n := 0
+	for _, c := range m.cost {
+		if c == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// query is one search to run: from src to dst in g, with A*'s estimate
+// of the distance left, or a nil h where there is no geometry to
+// estimate from.
+type query struct {
+	g        *graph
+	src, dst int
+	h        func(v int) int
+}
+
+// gridQuery returns a random map of about n cells and the search across
+// it, corner to corner. Maps where walls cut the corners apart are drawn
+// again.
+func gridQuery(rng *rand.Rand, n int, rough float64) (*grid, query) {
+	side := 1
+	for (side+1)*(side+1) <= n {
+		side++
+	}
+	for {
+		m := randomGrid(rng, side, side, rough)
+		q := query{g: m.graph(), src: 0, dst: side*side - 1}
+		q.h = m.heuristic(q.dst)
+		if humanDijkstra(q.g, q.src, q.dst).dist >= 0 {
+			return m, q
+		}
+	}
+}
+
+// randomGraph returns a graph of n vertices, each with degree edges to
+// random vertices weighing 0 to maxW.
+func randomGraph(rng *rand.Rand, n, degree, maxW int) *graph {
+	g := &graph{adj: make([][]edge, n)}
+	for v := range g.adj {
+		for range degree {
+			g.adj[v] = append(g.adj[v], edge{to: rng.IntN(n), w: rng.IntN(maxW + 1)})
+		}
+	}
+	return g
+}
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	search           func(query) route
+}{
+	{"Vibe coding", "Dijkstra, array scan, O(V²)", func(q query) route { return vibeDijkstra(q.g, q.src, q.dst) }},
+	{"Human coding", "Dijkstra, binary heap, O(E log V)", func(q query) route { return humanDijkstra(q.g, q.src, q.dst) }},
+	{"Expert coding", "A*, Manhattan heuristic", func(q query) route { return expertAStar(q.g, q.src, q.dst, q.h) }},
+}
+
+// searchesFor returns the searches to compare on q, leaving out the
+// array scan on large graphs and A* where q has no heuristic.
+func searchesFor(q query) []bench.Impl[query, route] {
+	impls := []bench.Impl[query, route]{}
+	for _, t := range tiers {
+		if t.name == "Vibe coding" && len(q.g.adj) > maxVibeV || t.name == "Expert coding" && q.h == nil {
+			continue
+		}
+		impls = append(impls, bench.Impl[query, route]{Name: t.name, Complexity: t.complexity, Func: t.search})
+	}
+	return impls
+}
+
+// impls returns the searches as implementations timed crossing a random
+// map of about n cells, at most maxCells.
+func impls(n int) []bench.Implementation {
+	n = min(n, maxCells)
+	_, q := gridQuery(input.DefaultSeed.Rand("map", n), n, roughDensity)
+	var list []bench.Implementation
+	for _, s := range searchesFor(q) {
+		list = append(list, s.Implementation(q))
+	}
+	return list
+}
+
+// checkRoute returns an error unless r is a path in g from src to dst
+// whose edges add up to its cost, and that cost is want (-1 meaning dst
+// is unreachable).
+func checkRoute(g *graph, src, dst int, r route, want int) error {
+	if r.dist != want {
+		return fmt.Errorf("path costs %d, want %d", r.dist, want)
+	}
+	if want < 0 {
+		if r.path != nil {
+			return fmt.Errorf("returned a path %v to an unreachable vertex", r.path)
+		}
+		return nil
+	}
+	if len(r.path) == 0 || r.path[0] != src || r.path[len(r.path)-1] != dst {
+		return fmt.Errorf("path %v does not lead from %d to %d", r.path, src, dst)
+	}
+	sum := 0
+	for i, u := range r.path[:len(r.path)-1] {
+		v, w := r.path[i+1], -1
+		for _, e := range g.adj[u] {
+			if e.to == v && (w < 0 || e.w < w) {
+				w = e.w
+			}
+		}
+		if w < 0 {
+			return fmt.Errorf("path steps from %d to %d without an edge", u, v)
+		}
+		sum += w
+	}
+	if sum != r.dist {
+		return fmt.Errorf("path edges add up to %d, but its cost is given as %d", sum, r.dist)
+	}
+	return nil
+}
+
+// bellmanFord returns the cost of a cheapest path from src to every
+// vertex, -1 where there is none, by relaxing every edge until nothing
+// changes. At O(VE) it is too slow to time, but it shares no code with
+// the searches, so it makes an independent judge.
+func bellmanFord(g *graph, src int) []int {
+	dist := make([]int, len(g.adj))
+	for v := range dist {
+		dist[v] = -1
+	}
+	dist[src] = 0
+	for changed := true; changed; {
+		changed = false
+		for u, edges := range g.adj {
+			for _, e := range edges {
+				if dist[u] >= 0 && (dist[e.to] < 0 || dist[u]+e.w < dist[e.to]) {
+					dist[e.to] = dist[u] + e.w
+					changed = true
+				}
+			}
+		}
+	}
+	return dist
+}
+
+// fuzz cross-checks the three searches against bellmanFord on small
+// random maps and graphs, between random vertices - walls, unreachable
+// targets, zero-weight edges and cycles all come up often. On graphs,
+// A* gets h = 0, which is admissible and turns it back into Dijkstra.
+func fuzz(rng *rand.Rand, iterations int) error {
+	for i := range iterations {
+		var q query
+		kind := "map"
+		if i%2 == 0 {
+			m := randomGrid(rng, 1+rng.IntN(10), 1+rng.IntN(10), rng.Float64())
+			q.g = m.graph()
+			q.src, q.dst = rng.IntN(len(m.cost)), rng.IntN(len(m.cost))
+			q.h = m.heuristic(q.dst)
+		} else {
+			n := 1 + rng.IntN(30)
+			kind = "graph"
+			q.g = randomGraph(rng, n, rng.IntN(graphDegree), 9)
+			q.src, q.dst = rng.IntN(n), rng.IntN(n)
+			q.h = func(int) int { return 0 }
+		}
+
+		want := bellmanFord(q.g, q.src)[q.dst]
+		for _, t := range tiers {
+			if err := checkRoute(q.g, q.src, q.dst, t.search(q), want); err != nil {
+				return fmt.Errorf("%s from %d to %d on a %d-vertex %s: %w", t.name, q.src, q.dst, len(q.g.adj), kind, err)
+			}
+		}
+	}
+	return nil
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "Dijkstra, scanning every vertex for the closest", Complexity: "O(V²)", Notes: []report.Note{
+			report.Strength("Correct, and the easiest version to write and check"),
+			report.Pitfall("Scans every vertex to find the next one to settle"),
+			report.Pitfall("Ignores that a map cell has only four neighbours"),
+		}},
+		{Label: "Human coding", Approach: "Dijkstra with a binary heap", Complexity: "O((V + E) log V)", Notes: []report.Note{
+			report.Strength("The closest vertex comes off a heap in O(log V)"),
+			report.Strength("Works on any graph with non-negative weights"),
+			report.Pitfall("Still spreads out evenly in every direction from the start"),
+		}},
+		{Label: "Expert coding", Approach: "A* with an admissible heuristic", Complexity: "O((V + E) log V), settles far fewer", Notes: []report.Note{
+			report.Strength("Heads for the goal, settling a fraction of the map"),
+			report.Strength("Still exact: an admissible heuristic never skips a cheaper path"),
+			report.Pitfall("Needs a heuristic - geometry on a map; on an arbitrary graph the only safe one is h = 0, which is Dijkstra again"),
+			report.Pitfall("An overestimating heuristic is faster still, and silently wrong"),
+		}},
+	},
+	Takeaway: "Use a heap before anything clever. Then make the search smarter, " +
+		"not the code faster: the best speed-up comes from settling fewer " +
+		"vertices, and the cross-check against Bellman-Ford is what proves " +
+		"the shortcut is safe.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "12-shortest-paths",
+		Title:       "Shortest Paths",
+		Description: "Find the cheapest path across a weighted map with Dijkstra's algorithm, a binary heap and A*.",
+		Category:    "graphs",
+		Difficulty:  examples.Advanced,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    10_000,
+		MaxN:        maxCells,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("12-shortest-paths", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{1_000, 10_000, 1_000_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of map cells, e.g. 1e4,1e6 (maps are square)")
+	rough := fs.Float64("rough", roughDensity, "fraction of open ground that is rough, from 0 to 1 (the more, the weaker A*'s estimate)")
+	graphN := fs.Int("graph", 10_000, "vertices in the random graph searched after the maps (0 skips it)")
+	iterations := fs.Int("fuzz", 1_000, "random maps and graphs to cross-check the searches on before timing")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	for _, n := range append([]int{*graphN}, sizes...) {
+		if n > maxCells {
+			return fmt.Errorf("n = %d: above %d vertices the graph would need gigabytes of memory", n, maxCells)
+		}
+	}
+	if *rough < 0 || *rough > 1 {
+		return fmt.Errorf("-rough %g: want a fraction from 0 to 1", *rough)
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Shortest Paths", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Shortest Paths")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	sample, q := gridQuery(seed.Rand("sample", 16*16), 16*16, *rough)
+	r := expertAStar(q.g, q.src, q.dst, q.h)
+	fmt.Fprintf(w, "\nA random 16×16 map and the cheapest path across it, costing %d:\n", r.dist)
+	fmt.Fprintln(w, "(██ wall, · open ground costing 1, 2-9 rough ground costing that much)")
+	fmt.Fprint(w, sample.render(r.path))
+
+	// A wrong shortest path still looks like a path, so check every
+	// route edge by edge against an independent judge before timing.
+	fmt.Fprintf(w, "\nFuzzing %d random maps and graphs (seed %d)...\n", *iterations, *seed)
+	if err := fuzz(seed.Rand("fuzz", 0), *iterations); err != nil {
+		return fmt.Errorf("verification failed: %w (reproduce with -seed %d)", err, *seed)
+	}
+	fmt.Fprintf(w, "✔ All %d implementations find valid cheapest paths, as Bellman-Ford does\n", len(tiers))
+
+	type search struct {
+		title string
+		n     int
+		q     query
+	}
+	var searches []search
+	for _, n := range sizes {
+		m, q := gridQuery(seed.Rand("map", n), n, *rough)
+		searches = append(searches, search{
+			fmt.Sprintf("Crossing a %d×%d map (%d cells, %d walls), corner to corner:", m.w, m.h, len(m.cost), m.walls()),
+			len(m.cost), q,
+		})
+	}
+	if *graphN > 0 {
+		rng := seed.Rand("graph", *graphN)
+		g := randomGraph(rng, *graphN, graphDegree, 100)
+		searches = append(searches, search{
+			fmt.Sprintf("A random graph of %d vertices and %d edges, weights 0-100:", *graphN, *graphN*graphDegree),
+			*graphN, query{g: g, src: 0, dst: rng.IntN(*graphN)},
+		})
+	}
+
+	for _, s := range searches {
+		fmt.Fprintf(out.Table, "\n%s\n", s.title)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		want := humanDijkstra(s.q.g, s.q.src, s.q.dst)
+		equal := func(got, want route) error { return checkRoute(s.q.g, s.q.src, s.q.dst, got, want.dist) }
+		impls := searchesFor(s.q)
+		results, err := bench.CompareImpls(ctx, opts, s.q, want, equal, impls...)
+		if err != nil {
+			return err
+		}
+		if want.dist < 0 {
+			fmt.Fprintln(w, "✔ All implementations agree: the target is unreachable")
+		} else {
+			fmt.Fprintf(w, "✔ All implementations agree: cost %d, %d steps\n", want.dist, len(want.path)-1)
+		}
+		label := "graph"
+		if s.q.h != nil {
+			label = "grid"
+		}
+		if err := csvLog.Append(label, uint64(s.n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append(label, uint64(s.n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(s.title, results)
+
+		var settled []string
+		for _, impl := range impls {
+			r := impl.Func(s.q)
+			settled = append(settled, fmt.Sprintf("%s %d", strings.TrimSuffix(impl.Name, " coding"), r.settled))
+		}
+		fmt.Fprintf(w, "  🔍 Vertices settled of %d: %s\n", s.n, strings.Join(settled, ", "))
+		if len(s.q.g.adj) > maxVibeV {
+			note := fmt.Sprintf("Vibe coding skipped: scanning every vertex per step is impractical above %d vertices", maxVibeV)
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		}
+		if s.q.h == nil {
+			note := "Expert coding skipped: a random graph has no geometry for A* to estimate the distance left from"
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		}
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		rows []string
+		desc string
+	}{
+		{[]string{"S"}, "1×1 map"},
+		{[]string{"...", ".S.", "..."}, "start is the goal"},
+		{[]string{"S..#.", "...#G", "...##"}, "goal walled off"},
+		{[]string{"S.........", "..........", "..........", "..........", ".........G"}, "open ground, every step costs 1"},
+		{[]string{"S9999", ".###9", "....G"}, "detour around rough ground"},
+		{[]string{"S.#....", "..#.##.", "..#..#.", "..##.#.", ".....#G"}, "maze with one way through"},
+	}
+	for _, tc := range edgeCases {
+		m, src, dst := parseGrid(tc.rows...)
+		q := query{g: m.graph(), src: src, dst: dst, h: m.heuristic(dst)}
+		want := bellmanFord(q.g, src)[dst]
+		status := "✅"
+		var settled []string
+		for _, t := range tiers {
+			r := t.search(q)
+			if checkRoute(q.g, src, dst, r, want) != nil {
+				status = "❌"
+			}
+			settled = append(settled, fmt.Sprint(r.settled))
+		}
+		result := fmt.Sprintf("cost %d, settled %s", want, strings.Join(settled, "/"))
+		if want < 0 {
+			result = "unreachable, settled " + strings.Join(settled, "/")
+		}
+		fmt.Fprintf(w, "%s %s: %s\n", status, tc.desc, result)
+		rep.AddEdgeCase(tc.desc, result)
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,455 @@
+// Package hashmaps compares three hand-built hash maps, and Go's own,
+// under mixes of inserts, lookups and deletes.
+package hashmaps
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// opsPerKey is how many operations follow the inserts that fill a map of
+// n keys, in every mix but "build".
+const opsPerKey = 4
+
+// tiers lists the implementations in order, with Go's builtin map last
+// as the baseline.
+var tiers = []struct {
+	name, complexity string
+	newMap           func() hashMap
+}{
+	{"Vibe coding", "chaining, key mod size", func() hashMap { return newVibeMap() }},
+	{"Human coding", "linear probing + tombstones", func() hashMap { return newHumanMap() }},
+	{"Expert coding", "Robin Hood, backward shift", func() hashMap { return newExpertMap() }},
+	{"Go map", "builtin Swiss table", func() hashMap { return goMap{} }},
+}
+
+// opKind is what an op does.
+type opKind uint8
+
+const (
+	get opKind = iota
+	put
+	del
+)
+
+func (k opKind) String() string {
+	return [...]string{"get", "put", "delete"}[k]
+}
+
+// op is one operation on a map.
+type op struct {
+	kind opKind
+	key  uint64
+}
+
+// mix is a workload: n inserts to fill a map, then opsPerKey·n
+// operations in the given percentages. Puts add new keys, deletes remove
+// keys that are present, and half the gets look for keys that are not.
+type mix struct {
+	name          string
+	get, put, del int
+}
+
+var mixes = []mix{
+	{name: "build"},
+	{name: "read-heavy", get: 90, put: 5, del: 5},
+	{name: "churn", get: 20, put: 40, del: 40},
+}
+
+// valueFor is the value stored under key, so lookups can be checked.
+func valueFor(key uint64) uint64 { return ^key }
+
+// makeOps returns the operations of mx on a map of n random keys.
+func makeOps(seed input.Seed, n int, mx mix) []op {
+	rng := seed.Rand(mx.name, n)
+	ops := make([]op, 0, n+opsPerKey*n)
+	var live []uint64
+	for range n {
+		k := rng.Uint64()
+		ops = append(ops, op{put, k})
+		live = append(live, k)
+	}
+	if mx.get+mx.put+mx.del == 0 {
+		return ops
+	}
+	for range opsPerKey * n {
+		switch r := rng.IntN(mx.get + mx.put + mx.del); {
+		case r < mx.put || len(live) == 0:
+			k := rng.Uint64()
+			ops = append(ops, op{put, k})
+			live = append(live, k)
+		case r < mx.put+mx.del:
+			i := rng.IntN(len(live))
+			ops = append(ops, op{del, live[i]})
+			live[i] = live[len(live)-1]
+			live = live[:len(live)-1]
+		case rng.IntN(2) == 0:
+			ops = append(ops, op{get, live[rng.IntN(len(live))]})
+		default:
+			ops = append(ops, op{get, rng.Uint64()})
+		}
+	}
+	return ops
+}
+
+// tally is what replaying ops observed: how many gets hit and missed,
+// the sum of the values they found, how many deletes removed a key, and
+// the size of the map at the end.
+type tally struct {
+	hits, misses, deleted, size int
+	sum                         uint64
+}
+
+// replay applies ops to m in order.
+func replay(m hashMap, ops []op) tally {
+	var t tally
+	for _, o := range ops {
+		switch o.kind {
+		case put:
+			m.Put(o.key, valueFor(o.key))
+		case del:
+			if m.Delete(o.key) {
+				t.deleted++
+			}
+		case get:
+			if v, ok := m.Get(o.key); ok {
+				t.hits++
+				t.sum += v
+			} else {
+				t.misses++
+			}
+		}
+	}
+	t.size = m.Len()
+	return t
+}
+
+// mapsFor returns the maps to compare, each replaying ops into a new map.
+func mapsFor() []bench.Impl[[]op, tally] {
+	impls := make([]bench.Impl[[]op, tally], len(tiers))
+	for i, t := range tiers {
+		impls[i] = bench.Impl[[]op, tally]{
+			Name: t.name, Complexity: t.complexity,
+			Func: func(ops []op) tally { return replay(t.newMap(), ops) },
+		}
+	}
+	return impls
+}
+
+// impls returns the maps as implementations timed on the read-heavy mix
+// over n keys.
+func impls(n int) []bench.Implementation {
+	ops := makeOps(input.DefaultSeed, n, mixes[1])
+	var list []bench.Implementation
+	for _, m := range mapsFor() {
+		list = append(list, m.Implementation(ops))
+	}
+	return list
+}
+
+// strides are the gaps between keys the fuzzer uses: consecutive keys,
+// keys that share factors with every power-of-two table size, and keys
+// that differ only in their high bits.
+var strides = []uint64{1, 8, 1024, 1 << 32}
+
+// fuzz cross-checks the maps against Go's builtin map, operation by
+// operation, on short random workloads over a few dozen keys, so tables
+// fill, grow, fill with tombstones and empty again.
+func fuzz(rng *rand.Rand, iterations int) error {
+	for range iterations {
+		stride := strides[rng.IntN(len(strides))]
+		keys := 1 + rng.IntN(64)
+		maps := make([]hashMap, len(tiers))
+		for i, t := range tiers {
+			maps[i] = t.newMap()
+		}
+		want := maps[len(maps)-1]
+
+		for step := range 300 {
+			o := op{opKind(rng.IntN(3)), stride * rng.Uint64N(uint64(keys))}
+			wantV, wantOK := want.Get(o.key)
+			switch o.kind {
+			case put:
+				want.Put(o.key, valueFor(o.key)+uint64(step))
+			case del:
+				want.Delete(o.key)
+			}
+			for i, m := range maps[:len(maps)-1] {
+				var v uint64
+				var ok bool
+				switch o.kind {
+				case get:
+					v, ok = m.Get(o.key)
+				case put:
+					v, ok = m.Get(o.key)
+					m.Put(o.key, valueFor(o.key)+uint64(step))
+				case del:
+					v, ok = m.Get(o.key)
+					if deleted := m.Delete(o.key); deleted != ok {
+						return fmt.Errorf("%s: step %d: delete %d returns %t for a key it has %t", tiers[i].name, step, o.key, deleted, ok)
+					}
+				}
+				if v != wantV || ok != wantOK {
+					return fmt.Errorf("%s: step %d: before %s %d, get returns (%d, %t), want (%d, %t)",
+						tiers[i].name, step, o.kind, o.key, v, ok, wantV, wantOK)
+				}
+				if m.Len() != want.Len() {
+					return fmt.Errorf("%s: step %d: after %s %d, Len is %d, want %d",
+						tiers[i].name, step, o.kind, o.key, m.Len(), want.Len())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// prober is implemented by the hand-built maps: the mean number of
+// entries a lookup that hits reads, and the most any one does.
+type prober interface {
+	probes() (mean float64, longest int)
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "array of linked lists, key mod size", Complexity: "O(1) expected, a pointer chase per entry", Notes: []report.Note{
+			report.Strength("The textbook table, and deletes are trivial"),
+			report.Pitfall("An allocation per key, and a pointer chase per entry probed"),
+			report.Pitfall("key mod size piles patterned keys into a few long chains"),
+		}},
+		{Label: "Human coding", Approach: "open addressing, linear probing, tombstones", Complexity: "O(1) expected, flat arrays", Notes: []report.Note{
+			report.Strength("Flat arrays: no per-key allocations, probes walk cached memory"),
+			report.Strength("A real hash function spreads patterned keys out"),
+			report.Pitfall("Deletes leave tombstones that lengthen probes until a rebuild"),
+			report.Pitfall("Must stay 3/4 empty-or-live, or probes grow long"),
+		}},
+		{Label: "Expert coding", Approach: "Robin Hood hashing, backward-shift deletion", Complexity: "O(1) expected, short probes at 7/8 full", Notes: []report.Note{
+			report.Strength("Evens out probe lengths, so it runs well at 7/8 full - the least memory of the three flat tables"),
+			report.Strength("Misses stop early; deletes shift back, leaving no tombstones"),
+			report.Pitfall("Inserts move entries around - trickier to get right"),
+		}},
+		{Label: "Go map", Approach: "builtin Swiss table", Complexity: "O(1) expected", Notes: []report.Note{
+			report.Strength("Checks 8 slots at once against a byte of each key's hash"),
+			report.Strength("Any key type, seeded hashing that resists flooding attacks, and already tested - the one to use"),
+			report.Pitfall("That generality costs time against a table built for uint64 keys with a one-multiply hash"),
+		}},
+	},
+	Takeaway: "Memory layout decides hash map speed as much as Big-O: all four " +
+		"are O(1), yet flat arrays beat pointers and short probes beat " +
+		"long ones. Reach for Go's map unless you have measured why not - " +
+		"and fuzzed your replacement against it.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "13-hash-maps",
+		Title:       "Hash Maps",
+		Description: "Build a hash map three ways - chaining, open addressing and Robin Hood hashing - and race them against Go's map.",
+		Category:    "data structures",
+		Difficulty:  examples.Advanced,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    100_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("13-hash-maps", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{1_000, 100_000, 1_000_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of keys, e.g. 1e4,1e6")
+	mixNames := fs.String("mix", "build,read-heavy,churn", "comma-separated workloads to time: build, read-heavy, churn")
+	iterations := fs.Int("fuzz", 1_000, "random workloads to cross-check the maps against Go's map before timing")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var active []mix
+	for _, name := range strings.Split(*mixNames, ",") {
+		i := slices.IndexFunc(mixes, func(mx mix) bool { return mx.name == strings.TrimSpace(name) })
+		if i < 0 {
+			return fmt.Errorf("-mix: unknown workload %q (want build, read-heavy or churn)", name)
+		}
+		active = append(active, mixes[i])
+	}
+	for _, n := range sizes {
+		if n < 1 {
+			return fmt.Errorf("-n must be at least 1, not %d", n)
+		}
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Hash Maps", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Hash Maps")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	// A hash map that loses one key in a million still looks fine in a
+	// benchmark, so check every operation against Go's map first.
+	fmt.Fprintf(w, "\nFuzzing %d random workloads (seed %d)...\n", *iterations, *seed)
+	if err := fuzz(seed.Rand("fuzz", 0), *iterations); err != nil {
+		return fmt.Errorf("verification failed: %w (reproduce with -seed %d)", err, *seed)
+	}
+	fmt.Fprintf(w, "✔ All %d hand-built maps match Go's map after every operation\n", len(tiers)-1)
+
+	for _, n := range sizes {
+		for _, mx := range active {
+			ops := makeOps(*seed, n, mx)
+			title := fmt.Sprintf("%s: %d inserts", mx.name, n)
+			if len(ops) > n {
+				title += fmt.Sprintf(", then %d ops (%d%% get, %d%% put, %d%% delete)", len(ops)-n, mx.get, mx.put, mx.del)
+			}
+			fmt.Fprintf(out.Table, "\n%s:\n", title)
+			fmt.Fprintln(w, strings.Repeat("-", 60))
+
+			want := replay(goMap{}, ops)
+			results, err := bench.CompareImpls(ctx, opts, ops, want, bench.Equal, mapsFor()...)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "✔ All implementations agree: %d hits, %d misses, %d deleted, %d keys left\n",
+				want.hits, want.misses, want.deleted, want.size)
+			if err := csvLog.Append(mx.name, uint64(n), results); err != nil {
+				return fmt.Errorf("csv: %w", err)
+			}
+			benchLog.Append(mx.name, uint64(n), results)
+			bench.Print(out.Table, results)
+			report.WriteBars(w, results)
+			bench.PrintRuns(out.Detail, results)
+			rep.Add(title, results)
+
+			fmt.Fprintln(out.Table, "\nPer operation, and probes per hit at the end:")
+			for i, r := range results {
+				line := fmt.Sprintf("  %-14s %7.1f ns/op  %6.2f allocs/op", r.Name+":",
+					float64(r.Duration.Nanoseconds())/float64(len(ops)), float64(r.Allocs)/float64(len(ops)))
+				m := tiers[i].newMap()
+				replay(m, ops)
+				if p, ok := m.(prober); ok {
+					mean, longest := p.probes()
+					line += fmt.Sprintf("   probes mean %.2f, longest %d", mean, longest)
+				}
+				fmt.Fprintln(out.Table, line)
+			}
+		}
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		desc string
+		run  func(m hashMap) string
+	}{
+		{"key 0", func(m hashMap) string {
+			m.Put(0, 42)
+			v, ok := m.Get(0)
+			return fmt.Sprintf("get → (%d, %t)", v, ok)
+		}},
+		{"largest key", func(m hashMap) string {
+			m.Put(^uint64(0), 7)
+			v, ok := m.Get(^uint64(0))
+			return fmt.Sprintf("get → (%d, %t)", v, ok)
+		}},
+		{"overwrite", func(m hashMap) string {
+			m.Put(5, 1)
+			m.Put(5, 2)
+			v, _ := m.Get(5)
+			return fmt.Sprintf("value %d, Len %d", v, m.Len())
+		}},
+		{"delete a missing key", func(m hashMap) string {
+			m.Put(1, 1)
+			return fmt.Sprintf("delete → %t, Len %d", m.Delete(2), m.Len())
+		}},
+		{"delete, then put again", func(m hashMap) string {
+			m.Put(9, 1)
+			m.Delete(9)
+			_, ok := m.Get(9)
+			m.Put(9, 3)
+			v, _ := m.Get(9)
+			return fmt.Sprintf("gone %t, then value %d", !ok, v)
+		}},
+		{"fill with 10,000 keys, delete them all", func(m hashMap) string {
+			for k := range uint64(10_000) {
+				m.Put(k, k)
+			}
+			for k := range uint64(10_000) {
+				m.Delete(k)
+			}
+			_, ok := m.Get(1234)
+			return fmt.Sprintf("Len %d, get → %t", m.Len(), ok)
+		}},
+	}
+	for _, tc := range edgeCases {
+		var got []string
+		for _, t := range tiers {
+			got = append(got, tc.run(t.newMap()))
+		}
+		status := "✅"
+		for _, g := range got {
+			if g != got[len(got)-1] {
+				status = "❌"
+			}
+		}
+		fmt.Fprintf(w, "%s %s: %s\n", status, tc.desc, got[len(got)-1])
+		rep.AddEdgeCase(tc.desc, got[len(got)-1])
+	}
+
+	// Key mod size only works for keys that look random.
+	fmt.Fprintln(w, "\n10,000 keys stepping by 1024, longest probe for a hit:")
+	for _, t := range tiers[:len(tiers)-1] {
+		m := t.newMap()
+		for k := range uint64(10_000) {
+			m.Put(1024*k, k)
+		}
+		_, longest := m.(prober).probes()
+		fmt.Fprintf(w, "  %-14s %d\n", t.name+":", longest)
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
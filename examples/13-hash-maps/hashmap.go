@@ -0,0 +1,356 @@
+package hashmaps
+
+import "math/bits"
+
+// hashMap maps uint64 keys to uint64 values.
+type hashMap interface {
+	Get(key uint64) (uint64, bool)
+	Put(key, val uint64)
+	Delete(key uint64) bool
+	Len() int
+}
+
+// hash scrambles key with Fibonacci hashing: multiplying by 2^64/φ mixes
+// every bit of the key into the top bits of the product, which the open
+// addressing tables use as the slot index.
+func hash(key uint64) uint64 {
+	return key * 0x9E3779B97F4A7C15
+}
+
+// minSlots is the capacity every table starts with.
+const minSlots = 8
+
+// VIBE CODING: An array of linked lists
+type vibeMap struct {
+	buckets []*node
+	n       int
+}
+
+// node is one entry in a bucket's list.
+type node struct {
+	key, val uint64
+	next     *node
+}
+
+func newVibeMap() *vibeMap {
+	return &vibeMap{buckets: make([]*node, minSlots)}
+}
+
+func (m *vibeMap) bucket(key uint64) int {
+	/*
+	   Pick the bucket for key as key mod the number of buckets
+
+	   The textbook hash table: a slot per bucket, a linked list per slot,
+	   and the key itself as its hash. Random keys spread out fine, but
+	   keys that share a factor with the table size - IDs that step by
+	   1024, say - all land in the same few lists.
+	*/
+	return int(key % uint64(len(m.buckets)))
+}
+
+func (m *vibeMap) Get(key uint64) (uint64, bool) {
+	for e := m.buckets[m.bucket(key)]; e != nil; e = e.next {
+		if e.key == key {
+			return e.val, true
+		}
+	}
+	return 0, false // O(1) expected, one pointer chase per entry in the list
+}
+
+func (m *vibeMap) Put(key, val uint64) {
+	b := m.bucket(key)
+	for e := m.buckets[b]; e != nil; e = e.next {
+		if e.key == key {
+			e.val = val
+			return
+		}
+	}
+	m.buckets[b] = &node{key: key, val: val, next: m.buckets[b]}
+	m.n++
+	if m.n > len(m.buckets) {
+		// Relink every node into twice as many buckets.
+		old := m.buckets
+		m.buckets = make([]*node, 2*len(old))
+		for _, e := range old {
+			for e != nil {
+				next := e.next
+				b := m.bucket(e.key)
+				e.next, m.buckets[b] = m.buckets[b], e
+				e = next
+			}
+		}
+	}
+} // O(1) amortized, plus an allocation for every new key
+
+func (m *vibeMap) Delete(key uint64) bool {
+	for p := &m.buckets[m.bucket(key)]; *p != nil; p = &(*p).next {
+		if (*p).key == key {
+			*p = (*p).next
+			m.n--
+			return true
+		}
+	}
+	return false
+}
+
+func (m *vibeMap) Len() int { return m.n }
+
+// probes returns the mean number of entries a lookup that hits reads,
+// and the most any one does.
+func (m *vibeMap) probes() (mean float64, longest int) {
+	total := 0
+	for _, e := range m.buckets {
+		for i := 1; e != nil; i, e = i+1, e.next {
+			total += i
+			longest = max(longest, i)
+		}
+	}
+	return float64(total) / float64(max(m.n, 1)), longest
+}
+
+// slot states for humanMap.
+const (
+	empty uint8 = iota
+	full
+	tombstone
+)
+
+// HUMAN CODING: Open addressing with linear probing and tombstones
+type humanMap struct {
+	keys, vals []uint64
+	state      []uint8
+	shift      uint // 64 - log2(len(keys)): hash >> shift is a slot
+	n, used    int  // live entries; live entries plus tombstones
+}
+
+func newHumanMap() *humanMap {
+	m := &humanMap{}
+	m.resize(minSlots)
+	return m
+}
+
+func (m *humanMap) resize(slots int) {
+	/*
+	   Rebuild the table with the given number of slots
+
+	   Everything lives in three flat arrays: no pointers, no allocation
+	   per entry, and a probe walks forward through memory the CPU has
+	   already fetched. A deleted entry can't simply be emptied - that
+	   would cut off the keys that probed past it - so it becomes a
+	   tombstone that lookups step over and inserts may reuse. Tombstones
+	   still lengthen probes until the next rebuild clears them.
+	*/
+	keys, vals, state := m.keys, m.vals, m.state
+	m.keys = make([]uint64, slots)
+	m.vals = make([]uint64, slots)
+	m.state = make([]uint8, slots)
+	m.shift = uint(64 - bits.TrailingZeros(uint(slots)))
+	m.n, m.used = 0, 0
+	for i, s := range state {
+		if s == full {
+			m.Put(keys[i], vals[i])
+		}
+	}
+}
+
+func (m *humanMap) Get(key uint64) (uint64, bool) {
+	mask := len(m.keys) - 1
+	for i := int(hash(key) >> m.shift); m.state[i] != empty; i = (i + 1) & mask {
+		if m.state[i] == full && m.keys[i] == key {
+			return m.vals[i], true
+		}
+	}
+	return 0, false // O(1) expected while the table is at most 3/4 used
+}
+
+func (m *humanMap) Put(key, val uint64) {
+	// Keep at least a quarter of the slots empty, so every probe ends.
+	// Grow if live entries fill half the table, else just sweep out the
+	// tombstones.
+	if m.used+1 > len(m.keys)*3/4 {
+		if m.n+1 > len(m.keys)/2 {
+			m.resize(2 * len(m.keys))
+		} else {
+			m.resize(len(m.keys))
+		}
+	}
+	mask := len(m.keys) - 1
+	reuse := -1
+	i := int(hash(key) >> m.shift)
+	for ; m.state[i] != empty; i = (i + 1) & mask {
+		switch {
+		case m.state[i] == tombstone && reuse < 0:
+			reuse = i
+		case m.state[i] == full && m.keys[i] == key:
+			m.vals[i] = val
+			return
+		}
+	}
+	if reuse >= 0 {
+		i = reuse
+	} else {
+		m.used++
+	}
+	m.keys[i], m.vals[i], m.state[i] = key, val, full
+	m.n++
+} // O(1) amortized
+
+func (m *humanMap) Delete(key uint64) bool {
+	mask := len(m.keys) - 1
+	for i := int(hash(key) >> m.shift); m.state[i] != empty; i = (i + 1) & mask {
+		if m.state[i] == full && m.keys[i] == key {
+			m.state[i] = tombstone
+			m.n--
+			return true
+		}
+	}
+	return false
+}
+
+func (m *humanMap) Len() int { return m.n }
+
+// probes returns the mean number of slots a lookup that hits reads, and
+// the most any one does.
+func (m *humanMap) probes() (mean float64, longest int) {
+	mask := len(m.keys) - 1
+	total := 0
+	for i, s := range m.state {
+		if s == full {
+			d := (i-int(hash(m.keys[i])>>m.shift))&mask + 1
+			total += d
+			longest = max(longest, d)
+		}
+	}
+	return float64(total) / float64(max(m.n, 1)), longest
+}
+
+// EXPERT CODING: Robin Hood hashing with backward-shift deletion
+type expertMap struct {
+	keys, vals []uint64
+	dist       []uint32 // 1 + how far each entry sits from its home slot; 0 if empty
+	shift      uint
+	n          int
+}
+
+func newExpertMap() *expertMap {
+	m := &expertMap{}
+	m.resize(minSlots)
+	return m
+}
+
+func (m *expertMap) resize(slots int) {
+	/*
+	   Rebuild the table with the given number of slots
+
+	   Linear probing again, with one rule on insert: an entry that has
+	   probed further from home than the one in its way takes the slot,
+	   and the evicted entry probes on ("take from the rich, give to the
+	   poor"). Probe lengths even out, so the table can run 7/8 full, and
+	   two things fall out of the ordering: a lookup can stop as soon as
+	   it meets an entry closer to home than it has come, and a delete
+	   shifts the entries after it back a slot instead of leaving a
+	   tombstone.
+	*/
+	keys, vals, dist := m.keys, m.vals, m.dist
+	m.keys = make([]uint64, slots)
+	m.vals = make([]uint64, slots)
+	m.dist = make([]uint32, slots)
+	m.shift = uint(64 - bits.TrailingZeros(uint(slots)))
+	m.n = 0
+	for i, d := range dist {
+		if d > 0 {
+			m.Put(keys[i], vals[i])
+		}
+	}
+}
+
+// find returns the slot holding key, or -1.
+func (m *expertMap) find(key uint64) int {
+	mask := len(m.keys) - 1
+	i := int(hash(key) >> m.shift)
+	for d := uint32(1); d <= m.dist[i]; d++ {
+		if m.keys[i] == key {
+			return i
+		}
+		i = (i + 1) & mask
+	}
+	return -1 // a key this far from home would have claimed this slot
+}
+
+func (m *expertMap) Get(key uint64) (uint64, bool) {
+	if i := m.find(key); i >= 0 {
+		return m.vals[i], true
+	}
+	return 0, false // O(1) expected, with short probes even when 7/8 full
+}
+
+func (m *expertMap) Put(key, val uint64) {
+	if m.n+1 > len(m.keys)*7/8 {
+		m.resize(2 * len(m.keys))
+	}
+	mask := len(m.keys) - 1
+	i := int(hash(key) >> m.shift)
+	for d := uint32(1); ; d++ {
+		switch {
+		case m.dist[i] == 0:
+			m.keys[i], m.vals[i], m.dist[i] = key, val, d
+			m.n++
+			return
+		case m.dist[i] == d && m.keys[i] == key:
+			m.vals[i] = val
+			return
+		case m.dist[i] < d:
+			// The resident is closer to home: swap, and carry it on.
+			m.keys[i], key = key, m.keys[i]
+			m.vals[i], val = val, m.vals[i]
+			m.dist[i], d = d, m.dist[i]
+		}
+		i = (i + 1) & mask
+	}
+} // O(1) amortized
+
+func (m *expertMap) Delete(key uint64) bool {
+	i := m.find(key)
+	if i < 0 {
+		return false
+	}
+	mask := len(m.keys) - 1
+	for j := (i + 1) & mask; m.dist[j] > 1; i, j = j, (j+1)&mask {
+		m.keys[i], m.vals[i], m.dist[i] = m.keys[j], m.vals[j], m.dist[j]-1
+	}
+	m.dist[i] = 0
+	m.n--
+	return true
+}
+
+func (m *expertMap) Len() int { return m.n }
+
+// probes returns the mean number of slots a lookup that hits reads, and
+// the most any one does.
+func (m *expertMap) probes() (mean float64, longest int) {
+	total := 0
+	for _, d := range m.dist {
+		total += int(d)
+		longest = max(longest, int(d))
+	}
+	return float64(total) / float64(max(m.n, 1)), longest
+}
+
+// goMap is Go's builtin map - Swiss tables since Go 1.24 - behind the
+// same interface, as the baseline.
+type goMap map[uint64]uint64
+
+func (m goMap) Get(key uint64) (uint64, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+func (m goMap) Put(key, val uint64) { m[key] = val }
+
+func (m goMap) Delete(key uint64) bool {
+	_, ok := m[key]
+	delete(m, key)
+	return ok
+}
+
+func (m goMap) Len() int { return len(m) }
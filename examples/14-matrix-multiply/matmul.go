@@ -0,0 +1,108 @@
+package matmul
+
+import "sync"
+
+// VIBE CODING: The formula, loop for loop
+func vibeMultiply(a, b []float64, n int) []float64 {
+	/*
+	   Compute C = A·B with c[i][j] = Σ a[i][k]·b[k][j]
+
+	   The i, j, k loops straight from the definition. The inner loop
+	   walks along a row of A but down a column of B, jumping n·8 bytes
+	   each step - a new cache line for every multiply once B no longer
+	   fits in the cache, and when n is a power of two the column maps
+	   onto the same few cache sets, so even small matrices thrash.
+	*/
+	c := make([]float64, n*n)
+	for i := range n {
+		for j := range n {
+			sum := 0.0
+			for k := range n {
+				sum += a[i*n+k] * b[k*n+j]
+			}
+			c[i*n+j] = sum
+		}
+	}
+	return c // O(n³), a cache miss per multiply for large n
+}
+
+// HUMAN CODING: Swap the loops to i, k, j
+func humanMultiply(a, b []float64, n int) []float64 {
+	/*
+	   Compute C = A·B with the loops in i, k, j order
+
+	   Same arithmetic, same O(n³), same order of additions into each
+	   c[i][j] - but now the inner loop runs along a row of B and a row
+	   of C, touching memory in order. Every cache line fetched is used
+	   in full, the hardware prefetcher sees the pattern coming, and the
+	   compiler can drop the bounds checks from a range loop.
+	*/
+	c := make([]float64, n*n)
+	for i := range n {
+		ci := c[i*n : i*n+n]
+		for k := range n {
+			aik := a[i*n+k]
+			bk := b[k*n : k*n+n]
+			for j := range ci {
+				ci[j] += aik * bk[j]
+			}
+		}
+	}
+	return c // O(n³), streaming through B once per row of A
+}
+
+// EXPERT CODING: Multiply tile by tile, tiles spread over goroutines
+func expertMultiply(a, b []float64, n, block, workers int) []float64 {
+	/*
+	   Compute C = A·B in block×block tiles, on workers goroutines
+
+	   The i, k, j order still streams through all of B for every row of
+	   A: n² values, which stop fitting in the cache long before n gets
+	   large. Tiling works on a block×block piece of B at a time and uses
+	   it for a whole block of rows before moving on, so each tile is
+	   loaded once per block of rows instead of once per row. Each
+	   goroutine takes whole blocks of rows, so no two ever write the
+	   same part of C and no locking is needed.
+	*/
+	c := make([]float64, n*n)
+	rows := make(chan int)
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i0 := range rows {
+				multiplyRows(a, b, c, n, block, i0)
+			}
+		}()
+	}
+	for i0 := 0; i0 < n; i0 += block {
+		rows <- i0
+	}
+	close(rows)
+	wg.Wait()
+	return c // O(n³), each tile of B loaded once per block of rows
+}
+
+// multiplyRows adds the block of rows of A·B starting at row i0 into C,
+// one tile of B at a time. Each c[i][j] still sums its terms in
+// increasing k, as the other two implementations do.
+func multiplyRows(a, b, c []float64, n, block, i0 int) {
+	i1 := min(i0+block, n)
+	for k0 := 0; k0 < n; k0 += block {
+		k1 := min(k0+block, n)
+		for j0 := 0; j0 < n; j0 += block {
+			j1 := min(j0+block, n)
+			for i := i0; i < i1; i++ {
+				ci := c[i*n+j0 : i*n+j1]
+				for k := k0; k < k1; k++ {
+					aik := a[i*n+k]
+					bk := b[k*n+j0 : k*n+j1]
+					for j := range ci {
+						ci[j] += aik * bk[j]
+					}
+				}
+			}
+		}
+	}
+}
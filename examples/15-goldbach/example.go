@@ -0,0 +1,227 @@
+// Package goldbach checks Goldbach's conjecture - every even number
+// greater than 2 is the sum of two primes - for every even number up to
+// n, with three ways to ask "is this number prime?".
+package goldbach
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// Trial division costs O(√n) per split found, so past this n a single
+// vibe run takes many seconds, and the tier is reported as skipped.
+const maxVibeN = 1_000_000
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	check            func(ctx context.Context, n int) (verdict, error)
+}{
+	{"Vibe coding", "trial division per pair", vibeGoldbach},
+	{"Human coding", "sieve + map set", humanGoldbach},
+	{"Expert coding", "sieve + odd-only bitset", expertGoldbach},
+}
+
+// checkersFor returns the implementations to compare up to n, leaving
+// out trial division where it would take too long.
+func checkersFor(n int) []bench.Impl[int, verdict] {
+	var impls []bench.Impl[int, verdict]
+	for _, t := range tiers {
+		if t.name == "Vibe coding" && n > maxVibeN {
+			continue
+		}
+		impls = append(impls, bench.Impl[int, verdict]{
+			Name: t.name, Complexity: t.complexity, FuncContext: t.check,
+		})
+	}
+	return impls
+}
+
+// impls returns the implementations timed checking every even number up
+// to n.
+func impls(n int) []bench.Implementation {
+	var list []bench.Implementation
+	for _, c := range checkersFor(n) {
+		list = append(list, c.Implementation(n))
+	}
+	return list
+}
+
+// describe summarises v in a line.
+func describe(v verdict) string {
+	if v.checked == 0 {
+		return "no even numbers to check"
+	}
+	if v.counterexample != 0 {
+		return fmt.Sprintf("%d is not the sum of two primes - Goldbach was wrong!", v.counterexample)
+	}
+	return fmt.Sprintf("%d even numbers, every one split; hardest %d = %d + %d",
+		v.checked, v.hardest, v.hardestP, v.hardest-v.hardestP)
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "try every pair, trial division for both", Complexity: "O(n√n)", Notes: []report.Note{
+			report.Strength("No setup and no memory: fine for checking one number"),
+			report.Pitfall("Rediscovers the same primes for every even number"),
+			report.Pitfall("Each split found costs a trial division of m - p, √n divisions"),
+		}},
+		{Label: "Human coding", Approach: "sieve once, primes in a map[int]bool", Complexity: "O(n log log n + n·k)", Notes: []report.Note{
+			report.Strength("One sieve, then every prime test is a lookup"),
+			report.Strength("Only primes are tried for p, never composites"),
+			report.Pitfall("Each lookup hashes and probes; the map takes megabytes for 78,498 keys"),
+		}},
+		{Label: "Expert coding", Approach: "sieve once, odd numbers in a bitset", Complexity: "O(n log log n + n·k)", Notes: []report.Note{
+			report.Strength("A lookup is a shift and a mask, with no hashing"),
+			report.Strength("n/16 bytes: 62 KiB at n = 10^6, small enough to stay in cache"),
+			report.Pitfall("Only works for dense sets of small integers with a known bound"),
+		}},
+	},
+	Takeaway: "Answering the same question over and over is a job for a set: " +
+		"sieve once, then look up. Which set matters as well - for dense " +
+		"small integers, a bitset beats a hash map in both time and memory.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "15-goldbach",
+		Title:       "Goldbach's Conjecture",
+		Description: "Check that every even number up to n is the sum of two primes, with trial division, a map and a bitset.",
+		Category:    "number theory",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    10_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("15-goldbach", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{10_000, 100_000, 1_000_000}
+	fs.Var(&sizes, "n", "comma-separated limits to check every even number up to, e.g. 1e5,1e7")
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Goldbach's Conjecture", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Goldbach's Conjecture")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	for _, n := range sizes {
+		fmt.Fprintf(out.Table, "\nEvery even number up to n = %d:\n", n)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		want, err := expertGoldbach(ctx, n)
+		if err != nil {
+			return err
+		}
+		results, err := bench.CompareImpls(ctx, opts, n, want, bench.Equal, checkersFor(n)...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "✔ All implementations agree: %s\n", describe(want))
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d", n), results)
+		if n > maxVibeN {
+			note := fmt.Sprintf("Vibe coding skipped: trial division is impractical above n=%d", maxVibeN)
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		}
+
+		// Both sieving tiers pay for the same sieve, so the difference in
+		// memory between them is what each kind of set costs.
+		fmt.Fprintln(out.Table, "\nPer even number, and memory per run:")
+		for _, r := range results {
+			fmt.Fprintf(out.Table, "  %-14s %9.1f ns   %10s\n", r.Name+":",
+				float64(r.Duration.Nanoseconds())/float64(max(want.checked, 1)), bench.FormatBytes(r.Bytes))
+		}
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		n    int
+		desc string
+	}{
+		{0, "n = 0"},
+		{3, "n = 3, below the first even number"},
+		{4, "n = 4, the only split using 2"},
+		{5, "n = 5"},
+		{6, "n = 6, the first odd + odd split"},
+		{100, "n = 100"},
+	}
+	for _, tc := range edgeCases {
+		var got []string
+		for _, t := range tiers {
+			v, err := t.check(ctx, tc.n)
+			if err != nil {
+				return err
+			}
+			got = append(got, describe(v))
+		}
+		status := "✅"
+		for _, g := range got {
+			if g != got[len(got)-1] {
+				status = "❌"
+			}
+		}
+		fmt.Fprintf(w, "%s %s: %s\n", status, tc.desc, got[len(got)-1])
+		rep.AddEdgeCase(tc.desc, got[len(got)-1])
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
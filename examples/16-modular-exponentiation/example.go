@@ -0,0 +1,310 @@
+// Package modexp compares three ways to compute base^exp mod m, the
+// building block of Miller–Rabin, Diffie–Hellman and RSA.
+package modexp
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"math/bits"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// Repeated multiplication takes about a second per 10^8 multiplications,
+// so past this exponent it is reported as skipped.
+const maxVibeExp = 100_000_000
+
+// modulus is the largest prime below 2^64: every multiplication needs the
+// full 128-bit product, and no power of the base wraps round to 1 early.
+const modulus = 18_446_744_073_709_551_557
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	pow              func(ctx context.Context, p power) (uint64, error)
+}{
+	{"Vibe coding", "repeated multiplication", vibePowMod},
+	{"Human coding", "square-and-multiply", func(_ context.Context, p power) (uint64, error) { return humanPowMod(p), nil }},
+	{"Expert coding", "math/big Exp", func(_ context.Context, p power) (uint64, error) { return expertPowMod(p), nil }},
+}
+
+// powersFor returns the implementations to compare on p, leaving out
+// repeated multiplication where it would take too long.
+func powersFor(p power) []bench.Impl[power, uint64] {
+	var impls []bench.Impl[power, uint64]
+	for _, t := range tiers {
+		if t.name == "Vibe coding" && p.exp > maxVibeExp {
+			continue
+		}
+		impls = append(impls, bench.Impl[power, uint64]{
+			Name: t.name, Complexity: t.complexity, FuncContext: t.pow,
+		})
+	}
+	return impls
+}
+
+// powerFor returns the power timed with exponent exp: a random base
+// below modulus, from seed.
+func powerFor(seed input.Seed, exp int) power {
+	return power{base: seed.Rand("base", exp).Uint64N(modulus), exp: uint64(exp), mod: modulus}
+}
+
+// impls returns the implementations timed raising a random base to the
+// power n.
+func impls(n int) []bench.Implementation {
+	p := powerFor(input.DefaultSeed, n)
+	var list []bench.Implementation
+	for _, impl := range powersFor(p) {
+		list = append(list, impl.Implementation(p))
+	}
+	return list
+}
+
+// sameBig is the equality function for big.Int results.
+func sameBig(got, want *big.Int) error {
+	if got.Cmp(want) != 0 {
+		return fmt.Errorf("got %d, want %d", got, want)
+	}
+	return nil
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "multiply by base exp times, reducing as it goes", Complexity: "O(exp)", Notes: []report.Note{
+			report.Strength("Straight from the definition, and reducing each step keeps it from overflowing"),
+			report.Pitfall("A 64-bit exponent means 10^19 multiplications - centuries"),
+			report.Pitfall("a*b % m overflows once m passes 2^32: it needs a 128-bit product"),
+		}},
+		{Label: "Human coding", Approach: "square-and-multiply, numtheory.PowMod", Complexity: "O(log exp)", Notes: []report.Note{
+			report.Strength("One squaring per bit of the exponent: at most 128 multiplications for any uint64"),
+			report.Strength("The step Miller–Rabin repeats for every base, shared through the numtheory package"),
+			report.Pitfall("Only for moduli that fit in a word; for bigger ones each step is a long division"),
+		}},
+		{Label: "Expert coding", Approach: "math/big Exp: 4-bit windows, Montgomery form", Complexity: "O(log exp) big multiplications", Notes: []report.Note{
+			report.Strength("Any size: the modular exponentiation behind RSA and Diffie–Hellman"),
+			report.Strength("Montgomery reduction replaces the division after each multiplication"),
+			report.Pitfall("For a single word it only adds allocations - slower than PowMod"),
+			report.Pitfall("Not constant-time: crypto code uses dedicated implementations"),
+		}},
+	},
+	Takeaway: "Repeated squaring turns a linear number of multiplications into a " +
+		"logarithmic one - the difference between impossible and instant. " +
+		"Past that, the cost is in each multiplication, which is where " +
+		"math/big's Montgomery arithmetic earns its keep.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "16-modular-exponentiation",
+		Title:       "Modular Exponentiation",
+		Description: "Compute base^exp mod m by repeated multiplication, square-and-multiply and math/big's Montgomery arithmetic.",
+		Category:    "number theory",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    1_000_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("16-modular-exponentiation", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{1_000, 1_000_000, 1_000_000_000_000_000_000}
+	fs.Var(&sizes, "n", "comma-separated exponents, e.g. 1e3,1e18")
+	bigBits := fs.Int("bits", 2048, "size of the modulus and exponent in the big-number comparison (0 skips it)")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	for _, n := range sizes {
+		if n < 0 {
+			return fmt.Errorf("-n: exponent %d is negative", n)
+		}
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Modular Exponentiation", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Modular Exponentiation")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	for _, n := range sizes {
+		p := powerFor(*seed, n)
+		fmt.Fprintf(out.Table, "\n%d^%d mod %d:\n", p.base, p.exp, p.mod)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		want := expertPowMod(p)
+		results, err := bench.CompareImpls(ctx, opts, p, want, bench.Equal, powersFor(p)...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "✔ All implementations agree: %d\n", want)
+		fmt.Fprintf(w, "Multiplications: %d one at a time, %d by square-and-multiply\n",
+			p.exp, bits.Len64(p.exp)+bits.OnesCount64(p.exp))
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("exponent %d", n), results)
+		if p.exp > maxVibeExp {
+			note := fmt.Sprintf("Vibe coding skipped: %d multiplications would take about %s", p.exp, estimate(p.exp))
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		}
+	}
+
+	if *bigBits > 1 {
+		if err := bigComparison(ctx, out, opts, csvLog, benchLog, rep, *seed, *bigBits); err != nil {
+			return err
+		}
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		p    power
+		desc string
+	}{
+		{power{7, 0, 13}, "exponent 0"},
+		{power{0, 0, 13}, "0^0"},
+		{power{7, 5, 1}, "modulus 1"},
+		{power{26, 5, 13}, "base a multiple of the modulus"},
+		{power{1<<64 - 2, 3, 1<<64 - 1}, "largest modulus, base ≡ -1"},
+		{power{3, 65_536, 65_537}, "Fermat: 3^(p-1) mod p, p = 65537"},
+	}
+	for _, tc := range edgeCases {
+		var got []string
+		for _, t := range tiers {
+			v, err := t.pow(ctx, tc.p)
+			if err != nil {
+				return err
+			}
+			got = append(got, fmt.Sprint(v))
+		}
+		status := "✅"
+		for _, g := range got {
+			if g != got[len(got)-1] {
+				status = "❌"
+			}
+		}
+		desc := fmt.Sprintf("%s: %d^%d mod %d", tc.desc, tc.p.base, tc.p.exp, tc.p.mod)
+		fmt.Fprintf(w, "%s %s = %s\n", status, desc, got[len(got)-1])
+		rep.AddEdgeCase(desc, got[len(got)-1])
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
+
+// estimate is roughly how long repeated multiplication would take for
+// exp multiplications, at 10 ns each.
+func estimate(exp uint64) string {
+	const year = 365.25 * 24 * 3600
+	seconds := float64(exp) * 1e-8
+	if seconds < year {
+		return fmt.Sprintf("%.0f seconds", seconds)
+	}
+	return fmt.Sprintf("%.0f years", seconds/year)
+}
+
+// bigComparison times square-and-multiply against big.Int.Exp on random
+// numbers of the given size, the sizes RSA and Diffie–Hellman use, with
+// an odd modulus as theirs always are.
+func bigComparison(ctx context.Context, out examples.Output, opts bench.Options, csvLog *report.CSVLog, benchLog *report.BenchLog, rep *report.Report, seed input.Seed, size int) error {
+	w := out.Text
+	rng := seed.Rand("big", size)
+	random := func() *big.Int {
+		x := new(big.Int)
+		for range (size + 63) / 64 {
+			x.Lsh(x, 64).Or(x, new(big.Int).SetUint64(rng.Uint64()))
+		}
+		x.SetBit(x, size-1, 1) // exactly size bits
+		for i := size; i < x.BitLen(); i++ {
+			x.SetBit(x, i, 0)
+		}
+		return x
+	}
+	p := bigPower{random(), random(), random()}
+	p.mod.SetBit(p.mod, 0, 1)
+	title := fmt.Sprintf("%d-bit base, exponent and odd modulus", size)
+
+	fmt.Fprintln(out.Table)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(out.Table, title)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	want := bigExp(p)
+	results, err := bench.CompareImpls(ctx, opts, p, want, sameBig,
+		bench.Impl[bigPower, *big.Int]{Name: "Human coding", Complexity: "square-and-multiply, Mul + Mod", FuncContext: bigSquareAndMultiply},
+		bench.Impl[bigPower, *big.Int]{Name: "Expert coding", Complexity: "math/big Exp", Func: bigExp},
+	)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "✔ Both implementations agree")
+	input := fmt.Sprintf("%d-bit", size)
+	if err := csvLog.Append(input, uint64(size), results); err != nil {
+		return fmt.Errorf("csv: %w", err)
+	}
+	benchLog.Append(input, uint64(size), results)
+	bench.Print(out.Table, results)
+	report.WriteBars(w, results)
+	bench.PrintRuns(out.Detail, results)
+	section := rep.Add(title, results)
+	note := fmt.Sprintf("Vibe coding skipped: a %d-bit exponent means about 2^%d multiplications", size, size-1)
+	fmt.Fprintln(w, "  ⏭️  "+note)
+	section.Notes = append(section.Notes, note)
+
+	fmt.Fprintf(w, "\n  💡 Both square %d times. Exp takes the exponent 4 bits at a time, so\n", p.exp.BitLen()-1)
+	fmt.Fprintf(w, "     it multiplies about %d times more instead of %d, and in Montgomery\n", p.exp.BitLen()/4, p.exp.BitLen()/2)
+	fmt.Fprintln(w, "     form, where reducing mod m takes multiplications and shifts")
+	fmt.Fprintln(w, "     instead of a long division.")
+	return nil
+}
@@ -0,0 +1,87 @@
+package modexp
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/iportilla/ai-coding/numtheory"
+)
+
+// power is one modular exponentiation to compute: base^exp mod mod.
+type power struct {
+	base, exp, mod uint64
+}
+
+// ctxEvery is how many multiplications vibePowMod does between looks at
+// ctx.
+const ctxEvery = 1 << 20
+
+// vibePowMod multiplies 1 by base exp times, reducing mod m after each
+// multiplication so the numbers stay below 2^64.
+//
+// VIBE CODING: straight from the definition, and reducing as it goes
+// keeps it correct - but it is O(exp) multiplications, and the exponents
+// in cryptography and primality tests are as large as the modulus.
+func vibePowMod(ctx context.Context, p power) (uint64, error) {
+	result := 1 % p.mod
+	for i := range p.exp {
+		if i%ctxEvery == 0 && ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		result = numtheory.MulMod(result, p.base, p.mod)
+	}
+	return result, nil
+}
+
+// humanPowMod is numtheory.PowMod, the square-and-multiply that Miller–Rabin
+// runs for every base.
+//
+// HUMAN CODING: walks the bits of exp, squaring base at each and
+// multiplying it into the result where the bit is 1: O(log exp)
+// multiplications of 64-bit numbers, each a 128-bit product and
+// remainder.
+func humanPowMod(p power) uint64 {
+	return numtheory.PowMod(p.base, p.exp, p.mod)
+}
+
+// expertPowMod hands the numbers to math/big.
+//
+// EXPERT CODING: big.Int.Exp is square-and-multiply too, taking the
+// exponent 4 bits at a time from a table of the first 16 powers, and for
+// an odd modulus and an exponent longer than a word, in Montgomery form,
+// where reducing mod m needs no division. For numbers that fit in a word
+// it mostly adds allocations; it is built for numbers that don't.
+func expertPowMod(p power) uint64 {
+	m := new(big.Int).SetUint64(p.mod)
+	x := new(big.Int).SetUint64(p.base)
+	return x.Exp(x, new(big.Int).SetUint64(p.exp), m).Uint64()
+}
+
+// bigPower is base^exp mod mod on numbers too large for a uint64.
+type bigPower struct {
+	base, exp, mod *big.Int
+}
+
+// bigSquareAndMultiply is humanPowMod rewritten for big.Int: a squaring
+// per bit of exp and a multiplication per 1 bit, each followed by a
+// long division to reduce the product mod m.
+func bigSquareAndMultiply(ctx context.Context, p bigPower) (*big.Int, error) {
+	result := big.NewInt(1)
+	result.Mod(result, p.mod)
+	base := new(big.Int).Mod(p.base, p.mod)
+	for i := range p.exp.BitLen() {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if p.exp.Bit(i) == 1 {
+			result.Mod(result.Mul(result, base), p.mod)
+		}
+		base.Mod(base.Mul(base, base), p.mod)
+	}
+	return result, nil
+}
+
+// bigExp is big.Int.Exp.
+func bigExp(p bigPower) *big.Int {
+	return new(big.Int).Exp(p.base, p.exp, p.mod)
+}
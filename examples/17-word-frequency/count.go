@@ -0,0 +1,212 @@
+package wordfreq
+
+import (
+	"bufio"
+	"cmp"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wordCount is how many times a word occurs.
+type wordCount struct {
+	word  string
+	count int
+}
+
+// isWordRune reports whether r belongs in a word. A word is a run of
+// letters and digits, lowercased: "Nature's" is "nature" and "s", and
+// "self-evident" is "self" and "evident".
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// byFrequency orders word counts from the most frequent, ties broken
+// alphabetically, so every implementation returns the same list.
+func byFrequency(a, b wordCount) int {
+	return cmp.Or(cmp.Compare(b.count, a.count), strings.Compare(a.word, b.word))
+}
+
+// VIBE CODING: Split, count, bubble sort
+func vibeCount(text string) []wordCount {
+	/*
+	   Lowercase the whole text, split it into a slice of every word,
+	   count them in a map, then bubble sort the counts.
+
+	   Every step is easy to follow, and each one costs: a lowercased copy
+	   of the text, a slice holding all n words at once, two lookups in
+	   the map per word to check and then update, and a sort with O(v²)
+	   comparisons for v distinct words.
+	*/
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !isWordRune(r) })
+	counts := map[string]int{}
+	for _, w := range words {
+		if _, ok := counts[w]; ok {
+			counts[w] = counts[w] + 1
+		} else {
+			counts[w] = 1
+		}
+	}
+
+	var list []wordCount
+	for w, c := range counts {
+		list = append(list, wordCount{w, c})
+	}
+	for i := range list {
+		for j := 0; j < len(list)-1-i; j++ {
+			if byFrequency(list[j], list[j+1]) > 0 {
+				list[j], list[j+1] = list[j+1], list[j]
+			}
+		}
+	}
+	return list
+}
+
+// HUMAN CODING: Stream words with a Scanner, sort.Slice
+func humanCount(text string) []wordCount {
+	/*
+	   A bufio.Scanner hands over one word at a time, so the text is never
+	   split into a slice of every word, and counts[w]++ is one lookup.
+	   sort.Slice sorts the distinct words in O(v log v).
+
+	   Still allocates a lowercased string per word, even for words
+	   already counted.
+	*/
+	sc := bufio.NewScanner(strings.NewReader(text))
+	sc.Split(scanWords)
+	counts := map[string]int{}
+	for sc.Scan() {
+		counts[strings.ToLower(sc.Text())]++
+	}
+
+	list := make([]wordCount, 0, len(counts))
+	for w, c := range counts {
+		list = append(list, wordCount{w, c})
+	}
+	sort.Slice(list, func(i, j int) bool { return byFrequency(list[i], list[j]) < 0 })
+	return list
+}
+
+// scanWords is a bufio.SplitFunc that returns each word, as isWordRune
+// defines them.
+func scanWords(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	start := 0
+	for start < len(data) {
+		if !atEOF && !utf8.FullRune(data[start:]) {
+			return start, nil, nil // wait for the rest of the rune
+		}
+		r, size := utf8.DecodeRune(data[start:])
+		if isWordRune(r) {
+			break
+		}
+		start += size
+	}
+	for i := start; i < len(data); {
+		if !atEOF && !utf8.FullRune(data[i:]) {
+			break
+		}
+		r, size := utf8.DecodeRune(data[i:])
+		if !isWordRune(r) {
+			return i + size, data[start:i], nil
+		}
+		i += size
+	}
+	if atEOF && len(data) > start {
+		return len(data), data[start:], nil
+	}
+	return start, nil, nil // ask for more data
+}
+
+// EXPERT CODING: Count shards in parallel, then merge
+func expertCount(text string) []wordCount {
+	/*
+	   Cut the text into one shard per CPU, at word boundaries, and count
+	   each shard in a goroutine of its own with a map of its own - no
+	   locks, since nothing is shared until the end. Each word is
+	   lowercased into a reused buffer, and counts[string(buf)] looks it
+	   up without allocating. The map holds a pointer to each count, and
+	   adding one goes through the pointer: counts[string(buf)]++ would
+	   allocate the key every time. Only a word's first occurrence in a
+	   shard allocates. Then merge the shards' maps, which hold only the
+	   distinct words, and sort.
+	*/
+	shards := splitShards(text, runtime.GOMAXPROCS(0))
+	counts := make([]map[string]*int, len(shards))
+	var wg sync.WaitGroup
+	for i, shard := range shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			counts[i] = countShard(shard)
+		}()
+	}
+	wg.Wait()
+
+	total := counts[0]
+	for _, c := range counts[1:] {
+		for w, n := range c {
+			if t := total[w]; t != nil {
+				*t += *n
+			} else {
+				total[w] = n
+			}
+		}
+	}
+	list := make([]wordCount, 0, len(total))
+	for w, c := range total {
+		list = append(list, wordCount{w, *c})
+	}
+	slices.SortFunc(list, byFrequency)
+	return list
+}
+
+// splitShards cuts text into about n pieces of equal length, moving each
+// cut forward to the end of the word it falls in.
+func splitShards(text string, n int) []string {
+	var shards []string
+	for n > 1 && len(text) > 0 {
+		cut := len(text) / n
+		for cut < len(text) {
+			r, size := utf8.DecodeRuneInString(text[cut:])
+			if r != utf8.RuneError && !isWordRune(r) {
+				break
+			}
+			cut += max(size, 1)
+		}
+		shards = append(shards, text[:cut])
+		text = text[cut:]
+		n--
+	}
+	return append(shards, text)
+}
+
+// countShard counts the words of one shard.
+func countShard(text string) map[string]*int {
+	counts := map[string]*int{}
+	var buf []byte
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		if isWordRune(r) {
+			buf = utf8.AppendRune(buf, unicode.ToLower(r))
+			if i < len(text) {
+				continue
+			}
+		}
+		if len(buf) > 0 {
+			if c := counts[string(buf)]; c != nil { // no allocation to look up
+				*c++
+			} else {
+				c = new(int)
+				*c = 1
+				counts[string(buf)] = c
+			}
+			buf = buf[:0]
+		}
+	}
+	return counts
+}
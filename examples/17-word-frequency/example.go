@@ -0,0 +1,239 @@
+// Package wordfreq compares three ways to count how often each word
+// occurs in a text.
+package wordfreq
+
+import (
+	"context"
+	_ "embed"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// corpus is the text counted: the Declaration of Independence and the
+// Gettysburg Address, both in the public domain.
+//
+//go:embed corpus.txt
+var corpus string
+
+// makeText returns a text of n whitespace-separated tokens, cycling
+// through corpus, twelve to a line.
+func makeText(n int) string {
+	tokens := strings.Fields(corpus)
+	var b strings.Builder
+	for i := range n {
+		switch {
+		case i == 0:
+		case i%12 == 0:
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(' ')
+		}
+		b.WriteString(tokens[i%len(tokens)])
+	}
+	return b.String()
+}
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	count            func(string) []wordCount
+}{
+	{"Vibe coding", "split + map + bubble sort", vibeCount},
+	{"Human coding", "Scanner + map + sort.Slice", humanCount},
+	{"Expert coding", "sharded maps, merged", expertCount},
+}
+
+// counters returns the implementations to compare.
+func counters() []bench.Impl[string, []wordCount] {
+	impls := make([]bench.Impl[string, []wordCount], len(tiers))
+	for i, t := range tiers {
+		impls[i] = bench.Impl[string, []wordCount]{Name: t.name, Complexity: t.complexity, Func: t.count}
+	}
+	return impls
+}
+
+// impls returns the implementations timed counting a text of n tokens.
+func impls(n int) []bench.Implementation {
+	text := makeText(n)
+	var list []bench.Implementation
+	for _, c := range counters() {
+		list = append(list, c.Implementation(text))
+	}
+	return list
+}
+
+// top formats the first k word counts, e.g. "the 12, of 9".
+func top(counts []wordCount, k int) string {
+	if len(counts) == 0 {
+		return "no words"
+	}
+	var parts []string
+	for _, c := range counts[:min(k, len(counts))] {
+		parts = append(parts, fmt.Sprintf("%s %d", c.word, c.count))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "lowercase, split into a slice, map, bubble sort", Complexity: "O(n + v²)", Notes: []report.Note{
+			report.Strength("Every step is a line anyone can read"),
+			report.Pitfall("Holds a lowercased copy of the text and a slice of every word at once"),
+			report.Pitfall("Two map lookups per word: one to check, one to update"),
+			report.Pitfall("Bubble sort is O(v²) in the distinct words - it grows with the vocabulary"),
+		}},
+		{Label: "Human coding", Approach: "bufio.Scanner + map + sort.Slice", Complexity: "O(n + v log v)", Notes: []report.Note{
+			report.Strength("Streams: one word in hand at a time, so it works on a file of any size"),
+			report.Strength("counts[w]++ is one lookup, and a missing key starts at 0"),
+			report.Pitfall("Still allocates a lowercased string for every word"),
+		}},
+		{Label: "Expert coding", Approach: "a map per CPU over shards of the text, merged", Complexity: "O(n/P + P·v + v log v)", Notes: []report.Note{
+			report.Strength("No locks: each goroutine counts into its own map"),
+			report.Strength("Lowercases into a reused buffer; only a word's first occurrence allocates"),
+			report.Strength("Merging touches only the distinct words, far fewer than n"),
+			report.Pitfall("Needs the whole text in memory to cut it into shards"),
+		}},
+	},
+	Takeaway: "Counting is dominated by allocation: a string per word costs more " +
+		"than the map itself. Look up with a reused buffer, give each goroutine " +
+		"its own map instead of sharing one behind a lock, and never bubble sort.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "17-word-frequency",
+		Title:       "Word Frequency",
+		Description: "Count how often each word occurs in a text, from split and bubble sort to sharded maps, with allocations.",
+		Category:    "strings",
+		Difficulty:  examples.Beginner,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    10_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("17-word-frequency", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{10_000, 100_000, 1_000_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of words in the text, e.g. 1e4,1e6")
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	for _, n := range sizes {
+		if n < 1 {
+			return fmt.Errorf("-n must be at least 1, not %d", n)
+		}
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Word Frequency", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Word Frequency")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "Corpus: corpus.txt, %d words, %s; GOMAXPROCS = %d\n",
+		len(strings.Fields(corpus)), bench.FormatBytes(uint64(len(corpus))), runtime.GOMAXPROCS(0))
+
+	for _, n := range sizes {
+		text := makeText(n)
+		fmt.Fprintf(out.Table, "\nCounting %d words (%s of text):\n", n, bench.FormatBytes(uint64(len(text))))
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		want := humanCount(text)
+		results, err := bench.CompareImpls(ctx, opts, text, want, bench.DiffSlices, counters()...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "✔ All implementations agree: %d distinct words; most frequent: %s\n", len(want), top(want, 5))
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		rep.Add(fmt.Sprintf("n = %d words", n), results)
+
+		fmt.Fprintln(out.Table, "\nPer word:")
+		for _, r := range results {
+			fmt.Fprintf(out.Table, "  %-14s %7.1f ns   %6.1f B   %5.2f allocs\n", r.Name+":",
+				float64(r.Duration.Nanoseconds())/float64(n), float64(r.Bytes)/float64(n), float64(r.Allocs)/float64(n))
+		}
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		text string
+		desc string
+	}{
+		{"", "empty text"},
+		{" -- ... !? ", "punctuation only"},
+		{"Hello, HELLO hello!", "mixed case"},
+		{"b a b a", "a tie, broken alphabetically"},
+		{"Nature's self-evident", "apostrophe and hyphen split words"},
+		{"Café CAFÉ café", "non-ASCII letters"},
+		{"July 4, 1776. 1776!", "numbers"},
+	}
+	for _, tc := range edgeCases {
+		var got []string
+		for _, t := range tiers {
+			got = append(got, top(t.count(tc.text), 5))
+		}
+		status := "✅"
+		for _, g := range got {
+			if g != got[len(got)-1] {
+				status = "❌"
+			}
+		}
+		fmt.Fprintf(w, "%s %s %q: %s\n", status, tc.desc, tc.text, got[len(got)-1])
+		rep.AddEdgeCase(fmt.Sprintf("%s %q", tc.desc, tc.text), got[len(got)-1])
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
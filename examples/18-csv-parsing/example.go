@@ -0,0 +1,415 @@
+// Package csvparsing compares three ways to parse CSV, and runs them
+// through the inputs that break naive parsers.
+package csvparsing
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// summary is what parsing a document found: how many records and
+// fields, the total of the amount column, in cents, and a checksum of
+// every field, so two parsers agree only if they split every field the
+// same way.
+type summary struct {
+	Records, Fields int
+	Cents           int64
+	Checksum        uint64
+}
+
+// amountColumn is the column of the generated documents holding an
+// amount with two decimals, e.g. 12.50.
+const amountColumn = 3
+
+// summarize returns a function that summarises a document with parse.
+func summarize[F string | []byte](parse func([]byte, func([]F)) error) func([]byte) (summary, error) {
+	return func(data []byte) (summary, error) {
+		var s summary
+		s.Checksum = 14695981039346656037 // FNV-1a
+		err := parse(data, func(fields []F) {
+			s.Records++
+			s.Fields += len(fields)
+			for i, f := range fields {
+				for j := range len(f) {
+					s.Checksum = (s.Checksum ^ uint64(f[j])) * 1099511628211
+				}
+				s.Checksum = (s.Checksum ^ 0x100) * 1099511628211 // the end of a field
+				if i == amountColumn {
+					s.Cents += cents(f)
+				}
+			}
+			s.Checksum = (s.Checksum ^ 0x200) * 1099511628211 // the end of a record
+		})
+		return s, err
+	}
+}
+
+// cents reads an amount such as 12.50 as 1250, skipping anything but
+// digits.
+func cents[F string | []byte](f F) int64 {
+	var n int64
+	for i := range len(f) {
+		if c := f[i]; '0' <= c && c <= '9' {
+			n = n*10 + int64(c-'0')
+		}
+	}
+	return n
+}
+
+// collect returns a function that returns every record of a document
+// parsed with parse, as strings.
+func collect[F string | []byte](parse func([]byte, func([]F)) error) func([]byte) ([][]string, error) {
+	return func(data []byte) ([][]string, error) {
+		records := [][]string{}
+		err := parse(data, func(fields []F) {
+			record := make([]string, len(fields))
+			for i, f := range fields {
+				record[i] = string(f)
+			}
+			records = append(records, record)
+		})
+		return records, err
+	}
+}
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	summarize        func([]byte) (summary, error)
+	records          func([]byte) ([][]string, error)
+}{
+	{"Vibe coding", "strings.Split, twice", summarize(vibeParse), collect(vibeParse)},
+	{"Human coding", "encoding/csv", summarize(humanParse), collect(humanParse)},
+	{"Expert coding", "streaming, reused buffers", summarize(expertParse), collect(expertParse)},
+}
+
+// parsersFor returns the implementations to compare.
+func parsersFor(vibe bool) []bench.Impl[[]byte, summary] {
+	var impls []bench.Impl[[]byte, summary]
+	for _, t := range tiers {
+		if t.name == "Vibe coding" && !vibe {
+			continue
+		}
+		impls = append(impls, bench.Impl[[]byte, summary]{
+			Name: t.name, Complexity: t.complexity,
+			FuncContext: func(ctx context.Context, data []byte) (summary, error) {
+				s, err := t.summarize(data)
+				if err != nil {
+					return s, fmt.Errorf("%s: %w", t.name, err)
+				}
+				return s, ctx.Err()
+			},
+		})
+	}
+	return impls
+}
+
+// mix is a kind of document: plain has nothing a naive parser gets
+// wrong, while quoted has what a spreadsheet exports - names with commas
+// in them, quotes, notes over several lines, and \r\n line endings.
+type mix struct {
+	name   string
+	quoted bool
+}
+
+var mixes = []mix{{"plain", false}, {"quoted", true}}
+
+var (
+	firstNames = []string{"Ada", "Grace", "Alan", "Edsger", "Barbara", "Donald", "Frances", "Ken"}
+	lastNames  = []string{"Lovelace", "Hopper", "Turing", "Dijkstra", "Liskov", "Knuth", "Allen", "Thompson"}
+	cities     = []string{"London", "New York", "Zürich", "São Paulo", "Kyoto", "Nairobi", "Oslo", "Lima"}
+	notes      = []string{"", "fragile", "leave at the door", "gift", "call on arrival"}
+)
+
+// makeDocument returns a CSV document of mx with a header and n rows of
+// orders, from seed.
+func makeDocument(seed input.Seed, n int, mx mix) []byte {
+	rng := seed.Rand(mx.name, n)
+	eol := "\n"
+	if mx.quoted {
+		eol = "\r\n"
+	}
+	var b strings.Builder
+	b.WriteString("id,customer,city,amount,note" + eol)
+	for i := range n {
+		first, last := firstNames[rng.IntN(len(firstNames))], lastNames[rng.IntN(len(lastNames))]
+		customer := first + " " + last
+		note := notes[rng.IntN(len(notes))]
+		if mx.quoted {
+			switch r := rng.IntN(100); {
+			case r < 30:
+				customer = `"` + last + ", " + first + `"`
+			case r < 40:
+				note = `"said ""` + note + `"" twice"`
+			case r < 45:
+				note = "\"" + note + eol + "then " + notes[rng.IntN(len(notes))] + "\""
+			}
+		}
+		fmt.Fprintf(&b, "%d,%s,%s,%d.%02d,%s%s", i+1, customer, cities[rng.IntN(len(cities))],
+			rng.IntN(500), rng.IntN(100), note, eol)
+	}
+	return []byte(b.String())
+}
+
+// impls returns the implementations timed parsing n rows of quoted CSV,
+// the kind the vibe parser gets wrong, so it is left out.
+func impls(n int) []bench.Implementation {
+	doc := makeDocument(input.DefaultSeed, n, mixes[1])
+	var list []bench.Implementation
+	for _, p := range parsersFor(false) {
+		list = append(list, p.Implementation(doc))
+	}
+	return list
+}
+
+// fuzzAlphabet is what fuzzed documents are made of: few plain
+// characters, and every one the CSV rules treat specially.
+var fuzzAlphabet = []string{"a", "é", ",", `"`, `""`, "\n", "\r", "\r\n", " "}
+
+// fuzz cross-checks the expert parser against encoding/csv on random
+// short documents: they must return the same records, and fail - or
+// not - on the same one.
+func fuzz(rng *rand.Rand, iterations int) error {
+	human, expert := tiers[1].records, tiers[2].records
+	for range iterations {
+		var b strings.Builder
+		for range rng.IntN(24) {
+			b.WriteString(fuzzAlphabet[rng.IntN(len(fuzzAlphabet))])
+		}
+		doc := []byte(b.String())
+		want, wantErr := human(doc)
+		got, err := expert(doc)
+		if !slices.EqualFunc(got, want, slices.Equal) {
+			return fmt.Errorf("%q: got %q, want %q", doc, got, want)
+		}
+		if (err != nil) != (wantErr != nil) || err != nil && !errParse(err) {
+			return fmt.Errorf("%q: got error %v, want %v", doc, err, wantErr)
+		}
+	}
+	return nil
+}
+
+// formatRecords writes records compactly, e.g. [a b] ["b,c"].
+func formatRecords(records [][]string, err error) string {
+	if err != nil {
+		return "error"
+	}
+	if len(records) == 0 {
+		return "no records"
+	}
+	var parts []string
+	for _, r := range records {
+		parts = append(parts, fmt.Sprintf("%q", r))
+	}
+	return strings.Join(parts, " ")
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "strings.Split on newlines, then on commas", Complexity: "O(size), wrong on quotes", Notes: []report.Note{
+			report.Strength("Two lines, and right for files with no quotes in them"),
+			report.Pitfall("Splits quoted fields at their commas and newlines, and keeps the quotes"),
+			report.Pitfall("Leaves \\r from Windows line endings in the last field"),
+			report.Pitfall("Never reports malformed input - it just returns wrong data"),
+		}},
+		{Label: "Human coding", Approach: "encoding/csv", Complexity: "O(size), 2 allocations per record", Notes: []report.Note{
+			report.Strength("RFC 4180: quotes, embedded newlines, \\r\\n, errors with line and column"),
+			report.Strength("Streams record by record from any io.Reader"),
+			report.Pitfall("A new []string and string per record, even with the fields unused"),
+			report.Tip("ReuseRecord = true reuses the []string; the string is still new"),
+		}},
+		{Label: "Expert coding", Approach: "streaming scanner with reused buffers", Complexity: "O(size), no allocations per record", Notes: []report.Note{
+			report.Strength("[]byte views into the input, or one reused buffer where quotes are removed"),
+			report.Strength("Fuzzed against encoding/csv: same records, same errors"),
+			report.Pitfall("The fields are only valid until the next record - copy what you keep"),
+			report.Pitfall("Every rule encoding/csv gets right had to be rewritten and tested"),
+		}},
+	},
+	Takeaway: "CSV looks like split-on-commas until the first quoted field. Use " +
+		"encoding/csv; write your own parser only for a hot path you have " +
+		"measured, and fuzz it against encoding/csv.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "18-csv-parsing",
+		Title:       "CSV Parsing",
+		Description: "Parse CSV with strings.Split, encoding/csv and a zero-allocation scanner, and see which survive quoted fields.",
+		Category:    "parsing",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    1_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("18-csv-parsing", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{1_000, 10_000, 100_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of rows, e.g. 1e3,1e6")
+	mixNames := fs.String("mix", "plain,quoted", "comma-separated documents to time: plain, quoted")
+	iterations := fs.Int("fuzz", 10_000, "random documents to cross-check the expert parser against encoding/csv before timing")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var active []mix
+	for _, name := range strings.Split(*mixNames, ",") {
+		i := slices.IndexFunc(mixes, func(mx mix) bool { return mx.name == strings.TrimSpace(name) })
+		if i < 0 {
+			return fmt.Errorf("-mix: unknown document %q (want plain or quoted)", name)
+		}
+		active = append(active, mixes[i])
+	}
+	for _, n := range sizes {
+		if n < 1 {
+			return fmt.Errorf("-n must be at least 1, not %d", n)
+		}
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("CSV Parsing", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: CSV Parsing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	// A parser that is fast on the easy inputs and wrong on the hard ones
+	// is easy to write, so check the expert one against encoding/csv on
+	// every awkward combination of quotes and line endings first.
+	fmt.Fprintf(w, "\nFuzzing %d random documents (seed %d)...\n", *iterations, *seed)
+	if err := fuzz(seed.Rand("fuzz", 0), *iterations); err != nil {
+		return fmt.Errorf("verification failed: Expert coding: %w (reproduce with -seed %d)", err, *seed)
+	}
+	fmt.Fprintln(w, "✔ Expert coding returns the same records and errors as encoding/csv")
+
+	for _, n := range sizes {
+		for _, mx := range active {
+			doc := makeDocument(*seed, n, mx)
+			title := fmt.Sprintf("%s: %d rows", mx.name, n)
+			fmt.Fprintf(out.Table, "\n%s (%s of CSV):\n", title, bench.FormatBytes(uint64(len(doc))))
+			fmt.Fprintln(w, strings.Repeat("-", 60))
+
+			want, err := tiers[1].summarize(doc)
+			if err != nil {
+				return fmt.Errorf("verification failed: Human coding: %w", err)
+			}
+			// The vibe parser only takes part where its answer is right.
+			vibe, _ := tiers[0].summarize(doc)
+			results, err := bench.CompareImpls(ctx, opts, doc, want, bench.Equal, parsersFor(vibe == want)...)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "✔ Parsers agree: %d records, %d fields, amounts total $%d.%02d\n",
+				want.Records, want.Fields, want.Cents/100, want.Cents%100)
+			if err := csvLog.Append(mx.name, uint64(n), results); err != nil {
+				return fmt.Errorf("csv: %w", err)
+			}
+			benchLog.Append(mx.name, uint64(n), results)
+			bench.Print(out.Table, results)
+			report.WriteBars(w, results)
+			bench.PrintRuns(out.Detail, results)
+			section := rep.Add(title, results)
+			if vibe != want {
+				note := fmt.Sprintf("Vibe coding left out: wrong answer, %d records and %d fields instead of %d and %d",
+					vibe.Records, vibe.Fields, want.Records, want.Fields)
+				fmt.Fprintln(w, "  ❌ "+note)
+				section.Notes = append(section.Notes, note)
+			}
+
+			fmt.Fprintln(out.Table, "\nThroughput and allocations:")
+			for _, r := range results {
+				fmt.Fprintf(out.Table, "  %-14s %8.1f MB/s   %6.2f allocs per row\n",
+					r.Name+":", float64(len(doc))/1e6/r.Duration.Seconds(), float64(r.Allocs)/float64(n))
+			}
+		}
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing: what encoding/csv returns, and who else does")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		doc  string
+		desc string
+	}{
+		{"a,b,c\n", "simple"},
+		{"a,b", "no final newline"},
+		{"a,,c\n", "empty field"},
+		{"a,\"b,c\",d\n", "comma inside quotes"},
+		{"\"say \"\"hi\"\"\",x\n", "doubled quotes"},
+		{"\"line 1\nline 2\",x\n", "newline inside quotes"},
+		{"a,b\r\nc,d\r\n", "\\r\\n line endings"},
+		{"a,b\n\nc,d\n", "blank line"},
+		{"\"\",x\n", "empty quoted field"},
+		{" a , b \n", "spaces are kept"},
+		{"héllo,wörld\n", "UTF-8"},
+		{"a,\"b\n", "unterminated quote"},
+		{"a\"b,c\n", "quote inside an unquoted field"},
+		{"\"a\"b,c\n", "text after a closing quote"},
+	}
+	for _, tc := range edgeCases {
+		want := formatRecords(tiers[1].records([]byte(tc.doc)))
+		var wrong []string
+		for _, t := range tiers {
+			if got := formatRecords(t.records([]byte(tc.doc))); got != want {
+				wrong = append(wrong, fmt.Sprintf("%s returns %s", t.name, got))
+			}
+		}
+		status, result := "✅", want
+		if len(wrong) > 0 {
+			status = "❌"
+			result += "; " + strings.Join(wrong, "; ")
+		}
+		fmt.Fprintf(w, "%s %s %q: %s\n", status, tc.desc, tc.doc, result)
+		rep.AddEdgeCase(fmt.Sprintf("%s %q", tc.desc, tc.doc), result)
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,206 @@
+package csvparsing
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// VIBE CODING: Split on newlines, then on commas
+func vibeParse(data []byte, emit func(fields []string)) error {
+	/*
+	   A CSV file is lines of comma-separated values, so split it into
+	   lines, skip the blank ones, and split each line on commas.
+
+	   Short, fast, and right for every file without quotes in it. But a
+	   quoted field may hold commas, newlines and "" for a quote, and this
+	   cuts straight through all three; \r from Windows line endings ends
+	   up in the last field; and malformed input is never an error, just
+	   wrong answers.
+	*/
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "" {
+			continue
+		}
+		emit(strings.Split(line, ","))
+	}
+	return nil
+}
+
+// HUMAN CODING: encoding/csv
+func humanParse(data []byte, emit func(fields []string)) error {
+	/*
+	   encoding/csv implements RFC 4180: quoted fields with commas,
+	   newlines and doubled quotes, \r\n line endings, and errors with a
+	   line and column for malformed quotes. FieldsPerRecord = -1 lets
+	   records have different numbers of fields, as the other two allow.
+
+	   Each record is a new []string, and its fields share one new string:
+	   two allocations per row, however little of it the caller keeps.
+	*/
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		emit(record)
+	}
+}
+
+// EXPERT CODING: A streaming parser that reuses its buffers
+func expertParse(data []byte, emit func(fields [][]byte)) error {
+	/*
+	   The same rules as encoding/csv, field for field and error for
+	   error, but the fields handed to emit are []byte views: into data
+	   itself where a field is used as is, and into one reused buffer
+	   where quotes had to be removed. Nothing is allocated per record
+	   once the buffers have grown to the longest one, so the cost is
+	   scanning the bytes. The views are only valid until emit returns.
+	*/
+	s := scanner{data: data}
+	for {
+		fields, err := s.next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		emit(fields)
+	}
+}
+
+// scanner reads CSV records from data, reusing its buffers.
+type scanner struct {
+	data   []byte
+	pos    int // Where the next line starts
+	line   int // The number of the last line read, for errors
+	buf    []byte
+	ends   []int    // The end of each field in buf, or -1 for a view into data
+	views  [][]byte // Fields that are views into data
+	fields [][]byte
+}
+
+// readLine returns the next line of s.data without its line ending, and
+// whether it had one; ok is false at the end of the data. Like
+// encoding/csv, it drops a \r before the \n, and one at the very end.
+func (s *scanner) readLine() (line []byte, nl, ok bool) {
+	if s.pos >= len(s.data) {
+		return nil, false, false
+	}
+	s.line++
+	rest := s.data[s.pos:]
+	if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+		line, nl = rest[:i], true
+		s.pos += i + 1
+	} else {
+		line = rest
+		s.pos = len(s.data)
+	}
+	if n := len(line); n > 0 && line[n-1] == '\r' {
+		line = line[:n-1]
+	}
+	return line, nl, true
+}
+
+// next returns the fields of the next record, skipping blank lines, or
+// io.EOF after the last.
+func (s *scanner) next() ([][]byte, error) {
+	var line []byte
+	var nl bool
+	for {
+		var ok bool
+		if line, nl, ok = s.readLine(); !ok {
+			return nil, io.EOF
+		}
+		if len(line) > 0 {
+			break
+		}
+	}
+
+	s.buf, s.ends, s.views = s.buf[:0], s.ends[:0], s.views[:0]
+	for {
+		if len(line) == 0 || line[0] != '"' {
+			field := line
+			i := bytes.IndexByte(line, ',')
+			if i >= 0 {
+				field = line[:i]
+			}
+			if bytes.IndexByte(field, '"') >= 0 {
+				return nil, fmt.Errorf("line %d: %w", s.line, csv.ErrBareQuote)
+			}
+			s.ends = append(s.ends, -1)
+			s.views = append(s.views, field)
+			if i < 0 {
+				break
+			}
+			line = line[i+1:]
+			continue
+		}
+
+		// A quoted field: copy it to buf without the quotes.
+		line = line[1:]
+		end := false
+		for !end {
+			i := bytes.IndexByte(line, '"')
+			switch {
+			case i >= 0:
+				s.buf = append(s.buf, line[:i]...)
+				line = line[i+1:]
+				switch {
+				case len(line) > 0 && line[0] == '"': // "" is a quote
+					s.buf = append(s.buf, '"')
+					line = line[1:]
+				case len(line) > 0 && line[0] == ',': // the end of the field
+					line = line[1:]
+					end = true
+				case len(line) == 0: // the end of the record
+					s.ends = append(s.ends, len(s.buf))
+					return s.record(), nil
+				default:
+					return nil, fmt.Errorf("line %d: %w", s.line, csv.ErrQuote)
+				}
+			case len(line) > 0 || nl: // the field goes on to the next line
+				s.buf = append(s.buf, line...)
+				if nl {
+					s.buf = append(s.buf, '\n')
+				}
+				line, nl, _ = s.readLine()
+			default:
+				return nil, fmt.Errorf("line %d: %w", s.line, csv.ErrQuote)
+			}
+		}
+		s.ends = append(s.ends, len(s.buf))
+	}
+	return s.record(), nil
+}
+
+// record slices the fields of the record just read out of buf and views.
+func (s *scanner) record() [][]byte {
+	s.fields = s.fields[:0]
+	start, view := 0, 0
+	for _, end := range s.ends {
+		if end < 0 {
+			s.fields = append(s.fields, s.views[view])
+			view++
+			continue
+		}
+		s.fields = append(s.fields, s.buf[start:end:end])
+		start = end
+	}
+	return s.fields
+}
+
+// errParse reports whether err is one of the errors encoding/csv returns
+// for malformed quotes, which the expert parser returns too.
+func errParse(err error) bool {
+	return errors.Is(err, csv.ErrQuote) || errors.Is(err, csv.ErrBareQuote)
+}
@@ -0,0 +1,281 @@
+// Package httpretry compares three ways to call a server that sometimes
+// fails: not retrying, retrying after a fixed pause, and exponential
+// backoff with jitter under a deadline.
+package httpretry
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	get              func(ctx context.Context, url string) error
+}{
+	{"Vibe coding", "one http.Get", vibeGet},
+	{"Human coding", "3 attempts, fixed pause", humanGet},
+	{"Expert coding", "backoff + jitter + deadline", expertGet},
+}
+
+// batch is how one implementation's calls went.
+type batch struct {
+	ok        int
+	latencies []time.Duration // Of every call, successful or not
+	attempts  int64           // Requests the server received
+}
+
+// runBatch makes requests calls with get, clients at a time, each for a
+// path of its own, against s injecting f.
+func runBatch(ctx context.Context, s *server, f faults, get func(context.Context, string) error, requests, clients int) batch {
+	s.reset(f)
+	latencies := make([]time.Duration, requests)
+	var ok, next atomic.Int64
+	var wg sync.WaitGroup
+	for range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1)) - 1
+				if i >= requests || ctx.Err() != nil {
+					return
+				}
+				start := time.Now()
+				if get(ctx, fmt.Sprintf("%s/item/%d", s.URL, i)) == nil {
+					ok.Add(1)
+				}
+				latencies[i] = time.Since(start)
+			}
+		}()
+	}
+	wg.Wait()
+	return batch{ok: int(ok.Load()), latencies: latencies, attempts: s.attempts.Load()}
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "one http.Get", Complexity: "1 attempt", Notes: []report.Note{
+			report.Strength("One line, and fine against a server that never fails"),
+			report.Pitfall("Every failed attempt is a failed call"),
+			report.Pitfall("http.DefaultClient has no timeout: a hung server hangs the caller"),
+			report.Pitfall("Ignores ctx, so the caller can't give up either"),
+		}},
+		{Label: "Human coding", Approach: "3 attempts, a fixed pause apart, client timeout", Complexity: "≤ 3 attempts", Notes: []report.Note{
+			report.Strength("Rides out occasional failures"),
+			report.Strength("A client timeout bounds each attempt"),
+			report.Pitfall("Retries 404 too, which can never succeed"),
+			report.Pitfall("Fixed pauses: an outage longer than two of them fails every call, and all clients retry in step"),
+			report.Pitfall("time.Sleep ignores ctx: the caller's deadline means nothing"),
+		}},
+		{Label: "Expert coding", Approach: "exponential backoff with full jitter, per-attempt timeout, one deadline", Complexity: "until the deadline", Notes: []report.Note{
+			report.Strength("Retries only network errors, timeouts, 429 and 5xx"),
+			report.Strength("Growing, random pauses ride out outages without flooding the server"),
+			report.Strength("A short timeout per attempt: a hung response costs one attempt, not the call"),
+			report.Strength("Stops at the caller's deadline, and skips a pause that would end past it"),
+			report.Pitfall("More attempts per call: retries multiply load, so cap them and the deadline"),
+		}},
+	},
+	Takeaway: "Networks fail, so retry - but only what can succeed, with growing " +
+		"and jittered pauses, a timeout on every attempt and a deadline on the " +
+		"whole call that comes from the caller's context.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "19-http-retry",
+		Title:       "HTTP Retries",
+		Description: "Call a local server that fails on purpose, without retries, with fixed pauses and with exponential backoff and jitter.",
+		Category:    "networking",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("19-http-retry", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	failRates := bench.Sizes{0, 10, 30}
+	fs.Var(&failRates, "fail", "comma-separated percentages of attempts the server fails with 503, e.g. 0,50")
+	slowRate := fs.Int("slow", 2, "percentage of attempts the server hangs on before answering")
+	hang := fs.Duration("hang", 200*time.Millisecond, "how long a hanging attempt hangs")
+	requests := fs.Int("requests", 200, "calls per run")
+	clients := fs.Int("clients", 10, "calls in flight at once")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	for _, p := range append(failRates, *slowRate) {
+		if p < 0 || p > 100 {
+			return fmt.Errorf("-fail and -slow are percentages, got %d", p)
+		}
+	}
+	if *requests < 1 || *clients < 1 {
+		return errors.New("-requests and -clients must be at least 1")
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("HTTP Retries", opts)
+	rep.Lesson = lesson
+
+	s := newServer(*seed)
+	defer s.Close()
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: HTTP Retries")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "Local server at %s; %d calls per run, %d at a time\n", s.URL, *requests, *clients)
+	fmt.Fprintf(w, "Injected failures from seed %d\n", *seed)
+
+	for _, p := range failRates {
+		f := faults{fail: float64(p) / 100, slow: float64(*slowRate) / 100, hang: *hang}
+		title := fmt.Sprintf("%d%% of attempts fail, %d%% hang for %v", p, *slowRate, *hang)
+		fmt.Fprintf(out.Table, "\n%s:\n", title)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		// A call can fail, so there is no answer to check: each run
+		// records how its calls went, and the last run's are reported.
+		batches := make([]batch, len(tiers))
+		impls := make([]bench.Implementation, len(tiers))
+		for i, t := range tiers {
+			impls[i] = bench.Implementation{
+				Name: t.name, Complexity: t.complexity,
+				RunContext: func(ctx context.Context) error {
+					batches[i] = runBatch(ctx, s, f, t.get, *requests, *clients)
+					return ctx.Err()
+				},
+			}
+		}
+		results, err := bench.CompareContext(ctx, opts, impls...)
+		if err != nil {
+			return err
+		}
+		if err := csvLog.Append(fmt.Sprintf("%d%% fail", p), uint64(*requests), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append(fmt.Sprintf("%d%% fail", p), uint64(*requests), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(title, results)
+
+		fmt.Fprintln(out.Table, "\nSuccess rate, load and latency per call:")
+		for i, r := range results {
+			b := batches[i]
+			fmt.Fprintf(out.Table, "  %-14s %5.1f%% succeeded   %4.2f attempts per call   p50 %8s   p99 %8s   max %8s\n",
+				r.Name+":", 100*float64(b.ok)/float64(*requests), float64(b.attempts)/float64(*requests),
+				millis(bench.Percentile(b.latencies, 50)), millis(bench.Percentile(b.latencies, 99)),
+				millis(bench.Percentile(b.latencies, 100)))
+			section.Notes = append(section.Notes, fmt.Sprintf("%s: %d of %d calls succeeded, %d attempts",
+				r.Name, b.ok, *requests, b.attempts))
+		}
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing: one call each")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	const callerDeadline = 50 * time.Millisecond
+	edgeCases := []struct {
+		desc   string
+		path   string
+		faults faults
+		limit  time.Duration // The caller's deadline, if any
+		want   string
+		good   func(err error, attempts int64, elapsed time.Duration) bool
+	}{
+		{"healthy server", "/item/0", faults{}, 0, "succeeds",
+			func(err error, _ int64, _ time.Duration) bool { return err == nil }},
+		{"404 Not Found", "/missing", faults{}, 0, "fails after 1 attempt: retrying can't help",
+			func(err error, attempts int64, _ time.Duration) bool { return err != nil && attempts == 1 }},
+		{"first response hangs for " + hang.String(), "/hang-once", faults{hang: *hang}, 0, "succeeds without waiting it out",
+			func(err error, _ int64, elapsed time.Duration) bool { return err == nil && elapsed < *hang }},
+		{"server down for 300ms", "/item/0", faults{down: 300 * time.Millisecond}, 0, "succeeds once it is back",
+			func(err error, _ int64, _ time.Duration) bool { return err == nil }},
+		{"first response hangs, caller gives up after " + callerDeadline.String(), "/hang-once", faults{hang: *hang}, callerDeadline,
+			"returns by the deadline",
+			func(_ error, _ int64, elapsed time.Duration) bool { return elapsed < callerDeadline+callerDeadline/2 }},
+	}
+	for _, tc := range edgeCases {
+		fmt.Fprintf(w, "%s (want: %s):\n", tc.desc, tc.want)
+		for _, t := range tiers {
+			callCtx, cancel := ctx, context.CancelFunc(func() {})
+			if tc.limit > 0 {
+				callCtx, cancel = context.WithTimeout(ctx, tc.limit)
+			}
+			s.reset(tc.faults)
+			start := time.Now()
+			err := t.get(callCtx, s.URL+tc.path)
+			elapsed := time.Since(start)
+			cancel()
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			attempts := s.attempts.Load()
+			result := fmt.Sprintf("ok, %d attempt(s) in %s", attempts, millis(elapsed))
+			if err != nil {
+				result = fmt.Sprintf("failed, %d attempt(s) in %s: %v", attempts, millis(elapsed), err)
+			}
+			status := "✅"
+			if !tc.good(err, attempts, elapsed) {
+				status = "❌"
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
+
+// millis formats d in milliseconds, e.g. 12.3ms.
+func millis(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+}
@@ -0,0 +1,177 @@
+package httpretry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// statusError is a response that came back, but not with 200 OK.
+type statusError struct {
+	code int
+}
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("status %d %s", e.code, http.StatusText(e.code))
+}
+
+// VIBE CODING: One http.Get, and hope
+func vibeGet(ctx context.Context, url string) error {
+	/*
+	   Fetch url, and fail if it doesn't answer 200 OK.
+
+	   http.Get is one line, and on a good day it is enough. But a
+	   server that fails one request in ten fails one call in ten, and
+	   http.DefaultClient has no timeout: a server that never answers
+	   keeps the caller waiting forever. ctx is ignored, so the caller
+	   can't give up either.
+	*/
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return statusError{resp.StatusCode}
+	}
+	_, err = io.ReadAll(resp.Body)
+	return err
+}
+
+// Retries of the human version: three attempts in all, a fixed pause
+// apart, each with a client timeout.
+const (
+	humanAttempts = 3
+	humanPause    = 50 * time.Millisecond
+	humanTimeout  = time.Second
+)
+
+var humanClient = &http.Client{Timeout: humanTimeout}
+
+// HUMAN CODING: Retry a few times, sleeping in between
+func humanGet(ctx context.Context, url string) error {
+	/*
+	   Fetch url, and fail if it doesn't answer 200 OK.
+
+	   Failures are usually brief, so try again: up to three attempts, a
+	   fixed pause apart, and a client timeout so no attempt hangs
+	   forever. That rides out one failed attempt in ten.
+
+	   But every failure is retried, even 404 Not Found, which will never
+	   succeed. The pause is the same every time, so an outage longer
+	   than two pauses fails every call, and every client hammers the
+	   server in step while it is down. time.Sleep ignores ctx, so the
+	   caller still can't give up.
+	*/
+	var err error
+	for attempt := 1; attempt <= humanAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(humanPause)
+		}
+		var resp *http.Response
+		resp, err = humanClient.Get(url)
+		if err != nil {
+			continue
+		}
+		_, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err == nil && resp.StatusCode != http.StatusOK {
+			err = statusError{resp.StatusCode}
+		}
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// Retries of the expert version: each attempt has its own timeout, the
+// pauses grow exponentially from expertBase up to expertCap, and the
+// whole call gives up at expertBudget unless ctx says sooner.
+const (
+	expertTimeout = 50 * time.Millisecond
+	expertBase    = 5 * time.Millisecond
+	expertCap     = 250 * time.Millisecond
+	expertBudget  = 2 * time.Second
+)
+
+// EXPERT CODING: Exponential backoff with jitter, within a deadline
+func expertGet(ctx context.Context, url string) error {
+	/*
+	   Fetch url, and fail if it doesn't answer 200 OK.
+
+	   Retry only what can succeed next time: network errors, timeouts,
+	   429 Too Many Requests and 5xx. A 404 fails at once.
+
+	   Each attempt gets a short timeout of its own, so one hung
+	   response costs one attempt, not the whole call. The pause before
+	   attempt k is a random duration up to base·2^k, capped: "full
+	   jitter". Growing pauses ride out a long outage without flooding
+	   the server while it recovers, and the randomness spreads clients
+	   out instead of having them all retry at the same instant.
+
+	   The whole call runs under one deadline, the caller's ctx or
+	   expertBudget, whichever is sooner. A pause that would end past
+	   the deadline isn't taken: the call fails at once with the last
+	   error, instead of sleeping only to give up.
+	*/
+	ctx, cancel := context.WithTimeout(ctx, expertBudget)
+	defer cancel()
+	for attempt := 0; ; attempt++ {
+		err := expertAttempt(ctx, url)
+		if err == nil || !retryable(err) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("attempt %d: %w", attempt+1, err)
+		}
+
+		pause := rand.N(min(expertCap, expertBase<<attempt)) // full jitter
+		if deadline, _ := ctx.Deadline(); time.Until(deadline) < pause {
+			return fmt.Errorf("attempt %d, no time for another: %w", attempt+1, err)
+		}
+		timer := time.NewTimer(pause)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("attempt %d: %w", attempt+1, err)
+		}
+	}
+}
+
+// expertAttempt makes one attempt at fetching url, within
+// expertTimeout. The body is always read to the end and closed, so the
+// connection can be reused.
+func expertAttempt(ctx context.Context, url string) error {
+	ctx, cancel := context.WithTimeout(ctx, expertTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, err = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return statusError{resp.StatusCode}
+	}
+	return err
+}
+
+// retryable reports whether a request that failed with err might
+// succeed if tried again.
+func retryable(err error) bool {
+	var status statusError
+	if errors.As(err, &status) {
+		return status.code == http.StatusTooManyRequests || status.code >= 500
+	}
+	return true // the network, or the attempt's timeout
+}
@@ -0,0 +1,85 @@
+package httpretry
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/iportilla/ai-coding/input"
+)
+
+// faults is what the test server does wrong on purpose.
+type faults struct {
+	fail float64       // Fraction of attempts answered 503 Service Unavailable
+	slow float64       // Fraction of attempts that hang before answering
+	hang time.Duration // How long those hang
+	down time.Duration // Answer 503 to everything for this long after reset
+}
+
+// server is a local HTTP server that fails on purpose. Whether an
+// attempt fails depends only on the seed, the path and how many times
+// that path was requested before, so every implementation meets the same
+// failures in the same places, however its requests interleave.
+//
+// Two paths are special: /missing is always 404 Not Found, and the first
+// request for /hang-once hangs.
+type server struct {
+	*httptest.Server
+	seed input.Seed
+
+	mu        sync.Mutex
+	faults    faults
+	tries     map[string]int // Requests so far, per path
+	downUntil time.Time
+
+	attempts atomic.Int64 // Requests since reset
+}
+
+// newServer starts a server injecting failures from seed. Close it when
+// done.
+func newServer(seed input.Seed) *server {
+	s := &server{seed: seed}
+	s.reset(faults{})
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// reset forgets every request so far and starts injecting f.
+func (s *server) reset(f faults) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = f
+	s.tries = map[string]int{}
+	s.downUntil = time.Now().Add(f.down)
+	s.attempts.Store(0)
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.attempts.Add(1)
+	if r.URL.Path == "/missing" {
+		http.NotFound(w, r)
+		return
+	}
+	s.mu.Lock()
+	f, try := s.faults, s.tries[r.URL.Path]
+	s.tries[r.URL.Path]++
+	down := time.Now().Before(s.downUntil)
+	s.mu.Unlock()
+
+	roll := s.seed.Rand(r.URL.Path, try).Float64()
+	switch {
+	case down || roll < f.fail:
+		http.Error(w, "injected failure", http.StatusServiceUnavailable)
+		return
+	case roll < f.fail+f.slow || r.URL.Path == "/hang-once" && try == 0:
+		select {
+		case <-time.After(f.hang):
+		case <-r.Context().Done(): // the client gave up
+			return
+		}
+	}
+	io.WriteString(w, "ok\n")
+}
@@ -0,0 +1,283 @@
+// Package ratelimit compares three rate limiters shared by concurrent
+// callers: sleeping between calls, a token bucket filled by a ticker, and
+// a golang.org/x/time/rate-style bucket computed from the clock.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	newLimiter       func(rate float64, burst int) limiter
+}{
+	{"Vibe coding", "time.Sleep per call", func(r float64, b int) limiter { return newVibeLimiter(r, b) }},
+	{"Human coding", "ticker fills a channel", func(r float64, b int) limiter { return newHumanLimiter(r, b) }},
+	{"Expert coding", "reservations from the clock", func(r float64, b int) limiter { return newExpertLimiter(r, b) }},
+}
+
+// callThrough makes up to calls calls through l, callers at a time,
+// until ctx is done, and returns how many it made.
+func callThrough(ctx context.Context, l limiter, calls, callers int) int {
+	var next, made atomic.Int64
+	var wg sync.WaitGroup
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil && next.Add(1) <= int64(calls) {
+				if l.Wait(ctx) != nil {
+					return
+				}
+				made.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	return int(made.Load())
+}
+
+// verdict compares an achieved rate with the target.
+func verdict(achieved, target float64) string {
+	switch ratio := achieved / target; {
+	case ratio > 1.05:
+		return fmt.Sprintf("❌ %.0f%% over the limit", 100*(ratio-1))
+	case ratio < 0.9:
+		return fmt.Sprintf("⚠️  %.0f%% short", 100*(1-ratio))
+	}
+	return "✅ on target"
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "time.Sleep(1/rate) before each call", Complexity: "no shared state", Notes: []report.Note{
+			report.Strength("One line, and right for a single caller at a low rate"),
+			report.Pitfall("Each caller sleeps on its own: N callers get N times the rate"),
+			report.Pitfall("Sleeps are never shorter than tens of microseconds, so high rates fall short"),
+			report.Pitfall("No burst, and ctx is ignored"),
+		}},
+		{Label: "Human coding", Approach: "buffered channel of tokens, filled by a ticker goroutine", Complexity: "a goroutine per limiter", Notes: []report.Note{
+			report.Strength("Shared by every caller, so the limit holds however many there are"),
+			report.Strength("Bursts up to the channel's capacity, and Wait selects on ctx"),
+			report.Pitfall("A goroutine and a ticker that leak unless Stop is called"),
+			report.Pitfall("Tickers drop ticks at high rates, so the rate falls short"),
+			report.Pitfall("Can't tell when the next token comes, so it waits out a deadline that is too soon"),
+		}},
+		{Label: "Expert coding", Approach: "x/time/rate-style token bucket, reservations computed from the clock", Complexity: "O(1) per call, one short lock", Notes: []report.Note{
+			report.Strength("Nothing runs in the background: the bucket is brought up to date on each call"),
+			report.Strength("Each caller reserves a turn and sleeps exactly until it; overshooting sleeps don't add up"),
+			report.Strength("Fails at once when the caller's deadline comes before its turn"),
+			report.Tip("In real code, use golang.org/x/time/rate"),
+		}},
+	},
+	Takeaway: "A rate limit is a property of the limiter, not of each caller: keep " +
+		"one shared bucket, compute it from the clock rather than counting on " +
+		"sleeps or ticks, and let callers give up as soon as their turn is too late.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "20-rate-limiter",
+		Title:       "Rate Limiting",
+		Description: "Limit concurrent callers to a rate three ways, and measure the rate each one really lets through.",
+		Category:    "concurrency",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("20-rate-limiter", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	rates := bench.Sizes{1_000, 10_000}
+	fs.Var(&rates, "rate", "comma-separated rates to limit to, in calls per second, e.g. 100,1e6")
+	callers := bench.Sizes{1, 8}
+	fs.Var(&callers, "callers", "comma-separated numbers of goroutines sharing the limiter, e.g. 1,64")
+	burst := fs.Int("burst", 10, "calls let through at once before the rate applies")
+	window := fs.Duration("window", 100*time.Millisecond, "how long each run should take at the target rate, after the burst")
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	for _, r := range rates {
+		if r < 1 {
+			return fmt.Errorf("-rate must be at least 1, got %d", r)
+		}
+	}
+	for _, c := range callers {
+		if c < 1 {
+			return fmt.Errorf("-callers must be at least 1, got %d", c)
+		}
+	}
+	if *burst < 1 || *window <= 0 {
+		return errors.New("-burst must be at least 1 and -window positive")
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Rate Limiting", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Rate Limiting")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "Burst %d; each run makes the burst plus %v's worth of calls at the target rate,\n", *burst, *window)
+	fmt.Fprintf(w, "and stops at %v if a limiter is too slow to make them all\n", 2**window)
+
+	for _, rate := range rates {
+		for _, c := range callers {
+			// A limiter that lets calls through at the target rate takes
+			// window for them, after letting the burst through at once.
+			calls := *burst + max(1, int(float64(rate)*window.Seconds()))
+			title := fmt.Sprintf("%d calls/s, %d caller(s)", rate, c)
+			fmt.Fprintf(out.Table, "\n%s: %d calls, should take %v:\n", title, calls, *window)
+			fmt.Fprintln(w, strings.Repeat("-", 60))
+
+			// Each run records how many calls it made: all of them, unless
+			// the limiter was too slow to make them in twice the time.
+			made := make([]int, len(tiers))
+			impls := make([]bench.Implementation, len(tiers))
+			for i, t := range tiers {
+				impls[i] = bench.Implementation{
+					Name: t.name, Complexity: t.complexity,
+					RunContext: func(ctx context.Context) error {
+						runCtx, cancel := context.WithTimeout(ctx, 2**window)
+						defer cancel()
+						l := t.newLimiter(float64(rate), *burst)
+						defer l.Stop()
+						made[i] = callThrough(runCtx, l, calls, c)
+						return ctx.Err()
+					},
+				}
+			}
+			results, err := bench.CompareContext(ctx, opts, impls...)
+			if err != nil {
+				return err
+			}
+			label := fmt.Sprintf("%d callers", c)
+			if err := csvLog.Append(label, uint64(rate), results); err != nil {
+				return fmt.Errorf("csv: %w", err)
+			}
+			benchLog.Append(label, uint64(rate), results)
+			bench.Print(out.Table, results)
+			report.WriteBars(w, results)
+			bench.PrintRuns(out.Detail, results)
+			section := rep.Add(title, results)
+
+			fmt.Fprintln(out.Table, "\nRate achieved after the burst:")
+			for i, r := range results {
+				achieved := float64(max(made[i]-*burst, 0)) / r.Duration.Seconds()
+				v := verdict(achieved, float64(rate))
+				fmt.Fprintf(out.Table, "  %-14s %10.0f calls/s   %s\n", r.Name+":", achieved, v)
+				section.Notes = append(section.Notes, fmt.Sprintf("%s: %.0f calls/s, %s", r.Name, achieved, v))
+			}
+		}
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing: one caller")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		desc         string
+		rate         float64
+		burst        int
+		idle         time.Duration // How long to wait after emptying the bucket
+		deadline     time.Duration // The caller's deadline for its calls
+		want         int           // Calls through by the deadline
+		wantPromptly bool          // Whether the call that fails should fail at once
+	}{
+		{"10/s, burst 5: calls through in the first 50ms", 10, 5, 0, 50 * time.Millisecond, 5, false},
+		{"10/s, burst 5, bucket emptied, then idle 250ms: calls through at once", 10, 5, 250 * time.Millisecond, 10 * time.Millisecond, 2, false},
+		{"1/s, bucket emptied, caller's deadline 50ms away: the call fails at once", 1, 1, 0, 50 * time.Millisecond, 0, true},
+	}
+	for _, tc := range edgeCases {
+		fmt.Fprintf(w, "%s (want %d):\n", tc.desc, tc.want)
+		for _, t := range tiers {
+			l := t.newLimiter(tc.rate, tc.burst)
+			if tc.idle > 0 || tc.wantPromptly {
+				// Empty the bucket first.
+				for range tc.burst {
+					l.Wait(ctx)
+				}
+				time.Sleep(tc.idle)
+			}
+			callCtx, cancel := context.WithTimeout(ctx, tc.deadline)
+			start := time.Now()
+			through, elapsed := 0, time.Duration(0)
+			for {
+				err := l.Wait(callCtx)
+				elapsed = time.Since(start)
+				if err != nil || elapsed > tc.deadline {
+					break
+				}
+				through++
+			}
+			cancel()
+			l.Stop()
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			result := fmt.Sprintf("%d through; gave up after %s", through, millis(elapsed))
+			status := "✅"
+			if through != tc.want || tc.wantPromptly && elapsed > tc.deadline/2 {
+				status = "❌"
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
+
+// millis formats d in milliseconds, e.g. 12.3ms.
+func millis(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+}
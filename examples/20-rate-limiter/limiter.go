@@ -0,0 +1,172 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// limiter lets calls through at most rate times a second, after an
+// initial burst. Wait blocks until the caller may proceed.
+type limiter interface {
+	Wait(ctx context.Context) error
+	Stop() // Releases anything the limiter runs in the background
+}
+
+// VIBE CODING: Sleep between calls
+type vibeLimiter struct {
+	interval time.Duration
+}
+
+func newVibeLimiter(rate float64, burst int) *vibeLimiter {
+	/*
+	   Allow rate calls a second: sleep 1/rate before each one.
+
+	   Right for one caller doing nothing else - and only for them. Every
+	   caller sleeps on its own, so eight goroutines sharing the limiter
+	   get eight times the rate. The time spent on the work itself comes
+	   on top of the sleep, so the rate is always under target, and
+	   time.Sleep can't sleep much less than 50µs, so high rates are out
+	   of reach. There is no burst, and ctx is ignored.
+	*/
+	return &vibeLimiter{interval: time.Duration(float64(time.Second) / rate)}
+}
+
+func (l *vibeLimiter) Wait(ctx context.Context) error {
+	time.Sleep(l.interval)
+	return nil
+}
+
+func (l *vibeLimiter) Stop() {}
+
+// HUMAN CODING: A token bucket filled by a ticker
+type humanLimiter struct {
+	tokens chan struct{}
+	ticker *time.Ticker
+	done   chan struct{}
+}
+
+func newHumanLimiter(rate float64, burst int) *humanLimiter {
+	/*
+	   Allow rate calls a second, after a burst of up to burst calls.
+
+	   A token bucket: a buffered channel holds up to burst tokens, a
+	   goroutine adds one every 1/rate, and each call takes one. The
+	   channel is shared, so the limit holds for any number of callers,
+	   and waiting on it can be combined with ctx in a select.
+
+	   But the limiter owns a goroutine and a ticker, which leak unless
+	   someone calls Stop. A ticker drops ticks it can't deliver in time,
+	   so at tens of thousands of tokens a second the rate falls short.
+	   And a caller whose ctx has a deadline still waits for it to pass,
+	   even when the next token is known to come later.
+	*/
+	l := &humanLimiter{
+		tokens: make(chan struct{}, burst),
+		ticker: time.NewTicker(time.Duration(float64(time.Second) / rate)),
+		done:   make(chan struct{}),
+	}
+	for range burst {
+		l.tokens <- struct{}{}
+	}
+	go func() {
+		for {
+			select {
+			case <-l.ticker.C:
+				select {
+				case l.tokens <- struct{}{}:
+				default: // the bucket is full
+				}
+			case <-l.done:
+				return
+			}
+		}
+	}()
+	return l
+}
+
+func (l *humanLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-l.tokens:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *humanLimiter) Stop() {
+	l.ticker.Stop()
+	close(l.done)
+}
+
+// errWouldExceedDeadline is returned by the expert limiter's Wait when
+// the caller's deadline comes before its turn.
+var errWouldExceedDeadline = errors.New("rate: Wait would exceed the context deadline")
+
+// EXPERT CODING: Reservations computed from the clock
+type expertLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // Tokens per second
+	burst  float64
+	tokens float64   // Tokens in the bucket at last; negative once reserved ahead
+	last   time.Time // When tokens was last brought up to date
+}
+
+func newExpertLimiter(rate float64, burst int) *expertLimiter {
+	/*
+	   Allow rate calls a second, after a burst of up to burst calls.
+
+	   The token bucket of golang.org/x/time/rate, written out here
+	   because the examples use only the standard library; in real
+	   code, use that package. Nothing runs in the background: the
+	   bucket is brought up to date from the clock whenever someone
+	   calls, as tokens + elapsed·rate, up to burst.
+
+	   A caller that finds the bucket empty takes a token anyway, leaving
+	   it negative: a reservation for the moment the bucket will be back
+	   at zero. The next caller reserves the token after that, so every
+	   caller knows exactly when its turn comes, under one short lock,
+	   and sleeps just that long. The times come from the clock, not
+	   from how long each sleep really took, so a sleep that overshoots
+	   doesn't slow the rate down: the next caller finds its turn already
+	   passed. And a caller whose deadline comes before its turn fails
+	   at once, without taking a token.
+	*/
+	return &expertLimiter{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+func (l *expertLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	now := time.Now()
+	tokens := min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	var wait time.Duration
+	if tokens < 1 {
+		wait = time.Duration((1 - tokens) / l.rate * float64(time.Second))
+	}
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(wait)) {
+		l.mu.Unlock()
+		return errWouldExceedDeadline
+	}
+	l.tokens, l.last = tokens-1, now
+	l.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// x/time/rate gives the token back here, if no one has
+		// reserved one after it; this version keeps it simple.
+		return ctx.Err()
+	}
+}
+
+func (l *expertLimiter) Stop() {}
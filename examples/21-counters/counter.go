@@ -0,0 +1,150 @@
+package counters
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// counter is a count shared by many goroutines. Each goroutine passes
+// its own worker number to Inc, which a counter may use to spread the
+// goroutines out.
+type counter interface {
+	Inc(worker int)
+	Load() int64
+}
+
+// VIBE CODING: A plain int, incremented from every goroutine
+type vibeCounter struct {
+	n int64
+}
+
+func (c *vibeCounter) Inc(worker int) {
+	/*
+	   c.n++ reads n, adds one and writes it back: three steps, not one.
+	   Two goroutines that read the same value both write back that value
+	   plus one, and an increment is lost. That is a data race, and the
+	   Go memory model makes no promises at all about a program with one:
+	   the compiler may even keep n in a register for the whole loop.
+
+	   On one core the steps rarely interleave, so it usually looks
+	   right. Build with -race to see it reported every time.
+	*/
+	c.n++
+}
+
+func (c *vibeCounter) Load() int64 { return c.n }
+
+// HUMAN CODING: A mutex around the int
+type humanCounter struct {
+	mu sync.Mutex
+	n  int64
+}
+
+func (c *humanCounter) Inc(worker int) {
+	/*
+	   Only one goroutine at a time may hold the lock, so the read, add
+	   and write happen together. Correct, and the obvious fix.
+
+	   But every increment takes and releases the lock, and with many
+	   goroutines on many cores they queue up for it: the goroutines
+	   add nothing to throughput, and the waiting makes it worse.
+	*/
+	c.mu.Lock()
+	c.n++
+	c.mu.Unlock()
+}
+
+func (c *humanCounter) Load() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n
+}
+
+// cacheLine is the size of a CPU cache line on common hardware.
+const cacheLine = 64
+
+// paddedInt64 is an atomic counter alone on its cache line.
+type paddedInt64 struct {
+	atomic.Int64
+	_ [cacheLine - 8]byte
+}
+
+// EXPERT CODING: Sharded atomic counters
+type expertCounter struct {
+	shards []paddedInt64
+}
+
+func newExpertCounter(shards int) *expertCounter {
+	return &expertCounter{shards: make([]paddedInt64, shards)}
+}
+
+func (c *expertCounter) Inc(worker int) {
+	/*
+	   An atomic add is one instruction the CPU makes indivisible: no
+	   lock, no lost increments. But one atomic counter is still one
+	   cache line, and every core that adds to it must take that line
+	   from the core that added last.
+
+	   So give each goroutine a shard of its own, each on its own cache
+	   line, and add the shards up only when the count is read. Adding
+	   stays on the core's own cache line. Load reads every shard, so
+	   it costs more, and a count read while goroutines are still adding
+	   is a snapshot no single moment matches - fine for statistics.
+	*/
+	c.shards[worker%len(c.shards)].Add(1)
+}
+
+func (c *expertCounter) Load() int64 {
+	var n int64
+	for i := range c.shards {
+		n += c.shards[i].Load()
+	}
+	return n
+}
+
+// atomicCounter is a single atomic.Int64: no lock and no lost increments,
+// but every goroutine adds to the same cache line.
+type atomicCounter struct {
+	n atomic.Int64
+}
+
+func (c *atomicCounter) Inc(worker int) { c.n.Add(1) }
+func (c *atomicCounter) Load() int64    { return c.n.Load() }
+
+// channelCounter is a count owned by one goroutine, which the others
+// send increments to: "share memory by communicating". Correct, but
+// every increment is a channel send and a goroutine switch.
+type channelCounter struct {
+	inc  chan struct{}
+	load chan chan int64
+}
+
+func newChannelCounter() *channelCounter {
+	c := &channelCounter{inc: make(chan struct{}), load: make(chan chan int64)}
+	go func() {
+		var n int64
+		for {
+			select {
+			case <-c.inc:
+				n++
+			case reply, ok := <-c.load:
+				if !ok {
+					return
+				}
+				reply <- n
+			}
+		}
+	}()
+	return c
+}
+
+func (c *channelCounter) Inc(worker int) { c.inc <- struct{}{} }
+
+func (c *channelCounter) Load() int64 {
+	reply := make(chan int64)
+	c.load <- reply
+	return <-reply
+}
+
+// Stop ends the goroutine that owns the count.
+func (c *channelCounter) Stop() { close(c.load) }
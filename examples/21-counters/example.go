@@ -0,0 +1,292 @@
+// Package counters compares three ways for many goroutines to share a
+// counter: a data race, a mutex, and sharded atomic counters.
+package counters
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// ctxEvery is how many increments a goroutine makes between checks for
+// cancellation.
+const ctxEvery = 1 << 14
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	newCounter       func(goroutines int) counter
+}{
+	{"Vibe coding", "plain int64, data race", func(int) counter { return &vibeCounter{} }},
+	{"Human coding", "sync.Mutex", func(int) counter { return &humanCounter{} }},
+	{"Expert coding", "atomic shard per goroutine", func(g int) counter { return newExpertCounter(g) }},
+}
+
+// share is how many of ops increments worker makes, of goroutines.
+func share(ops, goroutines, worker int) int {
+	n := ops / goroutines
+	if worker < ops%goroutines {
+		n++
+	}
+	return n
+}
+
+// increment makes share(ops, goroutines, worker) increments of c, as
+// worker, stopping early if ctx is done.
+func increment(ctx context.Context, c counter, ops, goroutines, worker int) error {
+	for i := range share(ops, goroutines, worker) {
+		if i%ctxEvery == 0 && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		c.Inc(worker)
+	}
+	return nil
+}
+
+// count increments c ops times from goroutines goroutines at once and
+// returns what it holds afterwards.
+func count(ctx context.Context, c counter, ops, goroutines int) (int64, error) {
+	errs := make([]error, goroutines)
+	var wg sync.WaitGroup
+	for worker := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[worker] = increment(ctx, c, ops, goroutines, worker)
+		}()
+	}
+	wg.Wait()
+	return c.Load(), errors.Join(errs...)
+}
+
+// timed returns an Implementation for bench.Options.Concurrency
+// goroutines sharing c, which together make ops increments per run.
+func timed(name, complexity string, c counter, ops, goroutines int) bench.Implementation {
+	return bench.Implementation{
+		Name: name, Complexity: complexity,
+		RunWorker: func(ctx context.Context, worker int) error {
+			return increment(ctx, c, ops, goroutines, worker)
+		},
+	}
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "n++ on a shared int64", Complexity: "a data race", Notes: []report.Note{
+			report.Strength("As fast as it gets - because it skips the one thing that matters"),
+			report.Pitfall("n++ is read, add, write: goroutines that interleave lose increments"),
+			report.Pitfall("A data race is undefined behaviour in Go, not just a wrong count"),
+			report.Pitfall("Often looks right on one core or in a quick test; go run -race catches it"),
+		}},
+		{Label: "Human coding", Approach: "sync.Mutex around n++", Complexity: "one lock for all", Notes: []report.Note{
+			report.Strength("Correct, simple, and the right default for protecting several fields together"),
+			report.Pitfall("Every increment takes the lock: goroutines queue up, and more of them add nothing"),
+		}},
+		{Label: "Expert coding", Approach: "an atomic counter per goroutine, each on its own cache line", Complexity: "no shared writes", Notes: []report.Note{
+			report.Strength("Atomic adds lose nothing and take no lock"),
+			report.Strength("Each goroutine adds to its own cache line, so cores don't fight over one"),
+			report.Pitfall("Load adds up every shard, and sees no single moment's count"),
+			report.Tip("One atomic.Int64 is enough until profiles show cores contending for it"),
+		}},
+	},
+	Takeaway: "Shared memory needs synchronisation: a counter with a data race is " +
+		"not a fast counter but a broken one. A mutex is the safe default; an " +
+		"atomic is cheaper for a single number; shard it only when many cores " +
+		"hammer it at once.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "21-counters",
+		Title:       "Concurrent Counters",
+		Description: "Share a counter between goroutines with a data race, a mutex and sharded atomics, and measure throughput as goroutines are added.",
+		Category:    "concurrency",
+		Difficulty:  examples.Beginner,
+		Lesson:      lesson,
+		Run:         Run,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("21-counters", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	goroutines := bench.Sizes{1, 4, 16}
+	fs.Var(&goroutines, "goroutines", "comma-separated numbers of goroutines sharing the counter, e.g. 1,8,64")
+	ops := fs.Int("ops", 1_000_000, "increments per run, shared between the goroutines")
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *ops < 1 || slices.Min(goroutines) < 1 {
+		return errors.New("-ops and -goroutines must be at least 1")
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Concurrent Counters", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Concurrent Counters")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "GOMAXPROCS = %d; %d increments per run\n", runtime.GOMAXPROCS(0), *ops)
+	if raceEnabled {
+		fmt.Fprintln(w, "Built with -race: expect the detector's DATA RACE report for Vibe coding")
+	} else {
+		fmt.Fprintln(w, "💡 Run with go run -race ./cmd/ai-coding run 21-counters to see the data race reported")
+	}
+
+	for _, g := range goroutines {
+		fmt.Fprintf(out.Table, "\n%d goroutine(s) sharing one counter:\n", g)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		// A counter that loses increments gives a wrong count, but it
+		// still runs: it is timed like the others, with its loss noted.
+		var lost []string
+		impls := make([]bench.Implementation, len(tiers))
+		for i, t := range tiers {
+			got, err := count(ctx, t.newCounter(g), *ops, g)
+			if err != nil {
+				return err
+			}
+			switch {
+			case got != int64(*ops) && t.name != "Vibe coding":
+				return fmt.Errorf("verification failed: %s: counted %d, want %d", t.name, got, *ops)
+			case got != int64(*ops):
+				lost = append(lost, fmt.Sprintf("%s lost %d of %d increments", t.name, int64(*ops)-got, *ops))
+			}
+			impls[i] = timed(t.name, t.complexity, t.newCounter(g), *ops, g)
+		}
+		if len(lost) == 0 {
+			fmt.Fprintf(w, "✔ All implementations counted %d - Vibe coding by luck, its race can lose any run\n", *ops)
+		}
+		levelOpts := opts
+		levelOpts.Concurrency = g
+		results, err := bench.CompareContext(ctx, levelOpts, impls...)
+		if err != nil {
+			return err
+		}
+		label := fmt.Sprintf("%d goroutines", g)
+		if err := csvLog.Append(label, uint64(*ops), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append(label, uint64(*ops), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("%d goroutine(s)", g), results)
+		for _, note := range lost {
+			fmt.Fprintln(w, "  ❌ "+note+" - a data race")
+			section.Notes = append(section.Notes, note)
+		}
+
+		fmt.Fprintln(out.Table, "\nThroughput:")
+		for _, r := range results {
+			fmt.Fprintf(out.Table, "  %-14s %12.0f increments/s   %6.2f ns each\n", r.Name+":",
+				float64(*ops)/r.Duration.Seconds(), float64(r.Duration.Nanoseconds())/float64(*ops))
+		}
+	}
+
+	// Two more correct counters: a single atomic, which the sharded one
+	// improves on, and a goroutine owning the count, the way channels
+	// suggest.
+	g := slices.Max(goroutines)
+	fmt.Fprintln(out.Table)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(out.Table, "More ways to count, %d goroutine(s):\n", g)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	channel := newChannelCounter()
+	defer channel.Stop()
+	alternatives := []struct {
+		name, complexity string
+		c                counter
+	}{
+		{"Mutex", "sync.Mutex", &humanCounter{}},
+		{"Channel", "a goroutine owns the count", channel},
+		{"One atomic", "atomic.Int64", &atomicCounter{}},
+		{"Sharded atomics", "atomic shard per goroutine", newExpertCounter(g)},
+	}
+	impls := make([]bench.Implementation, len(alternatives))
+	for i, a := range alternatives {
+		impls[i] = timed(a.name, a.complexity, a.c, *ops, g)
+	}
+	levelOpts := opts
+	levelOpts.Concurrency = g
+	results, err := bench.CompareContext(ctx, levelOpts, impls...)
+	if err != nil {
+		return err
+	}
+	bench.Print(out.Table, results)
+	report.WriteBars(w, results)
+	rep.Add(fmt.Sprintf("More ways to count, %d goroutine(s)", g), results)
+	fmt.Fprintln(w, "💡 A channel serialises every increment through one goroutine: it is")
+	fmt.Fprintln(w, "   for handing over work and ownership, not for guarding a number.")
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing: 100,000 increments per goroutine")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	const perGoroutine = 100_000
+	for _, g := range []int{1, 2, 8, 64} {
+		want := int64(g * perGoroutine)
+		fmt.Fprintf(w, "%d goroutine(s) (want %d):\n", g, want)
+		for _, t := range tiers {
+			got, err := count(ctx, t.newCounter(g), int(want), g)
+			if err != nil {
+				return err
+			}
+			status, result := "✅", fmt.Sprintf("%d", got)
+			switch {
+			case got != want:
+				status, result = "❌", fmt.Sprintf("%d, lost %d", got, want-got)
+			case t.name == "Vibe coding" && g > 1:
+				status, result = "⚠️ ", fmt.Sprintf("%d, this time - it is still a data race", got)
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(fmt.Sprintf("%s, %d goroutine(s)", t.name, g), result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
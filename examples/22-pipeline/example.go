@@ -0,0 +1,280 @@
+// Package pipeline compares three ways to build a multi-stage channel
+// pipeline that finds primes.
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/primes"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// base is where the numbers start: large enough that testing one for
+// primality takes real work.
+const base = 1_000_000_000_000
+
+// pipeliner is the signature shared by the three implementations: it
+// tallies the primes in [lo, hi).
+type pipeliner func(ctx context.Context, lo, hi uint64) (tally, error)
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	run              pipeliner
+}{
+	{"Vibe coding", "unbuffered stages", vibePipeline},
+	{"Human coding", "buffered fan-out/fan-in", humanPipeline},
+	{"Expert coding", "batched errgroup pipeline", expertPipeline},
+}
+
+// pipelinesFor returns the implementations to compare, each tallying the
+// primes among n numbers from base.
+func pipelinesFor() []bench.Impl[uint64, tally] {
+	impls := []bench.Impl[uint64, tally]{}
+	for _, t := range tiers {
+		impls = append(impls, bench.Impl[uint64, tally]{
+			Name: t.name, Complexity: t.complexity,
+			FuncContext: func(ctx context.Context, n uint64) (tally, error) {
+				return t.run(ctx, base, base+n)
+			},
+		})
+	}
+	return impls
+}
+
+// impls returns the implementations timed on n numbers.
+func impls(n int) []bench.Implementation {
+	var list []bench.Implementation
+	for _, p := range pipelinesFor() {
+		list = append(list, p.Implementation(uint64(n)))
+	}
+	return list
+}
+
+// reference tallies the primes in [lo, hi) with a segmented sieve, which
+// shares no code with the pipelines.
+func reference(lo, hi uint64) tally {
+	var t tally
+	if hi <= lo {
+		return t
+	}
+	for _, p := range primes.PrimesInRange(int(lo), int(hi-1)) {
+		t.add(uint64(p))
+	}
+	return t
+}
+
+// cancelAfter is how long the cancellation test lets a pipeline run, and
+// settle how long it then waits before counting goroutines.
+const (
+	cancelAfter = 20 * time.Millisecond
+	settle      = 100 * time.Millisecond
+)
+
+// cancelRun starts p on n numbers, cancels it after cancelAfter, and
+// reports how long p took to return, what it returned, and how many of
+// its goroutines were still running settle after that.
+func cancelRun(ctx context.Context, p pipeliner, n uint64) (elapsed time.Duration, err error, leaked int) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	timer := time.AfterFunc(cancelAfter, cancel)
+	defer timer.Stop()
+	start := time.Now()
+	_, err = p(ctx, base, base+n)
+	elapsed = time.Since(start)
+	time.Sleep(settle)
+	return elapsed, err, max(runtime.NumGoroutine()-before, 0)
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "a goroutine per stage, unbuffered channels", Complexity: "2 channel handoffs per number", Notes: []report.Note{
+			report.Strength("Reads exactly like the pipeline diagram"),
+			report.Pitfall("Stages wait for each other at every number"),
+			report.Pitfall("One goroutine does all the prime testing"),
+			report.Pitfall("Ignores ctx: a cancelled call runs to the end"),
+		}},
+		{Label: "Human coding", Approach: "buffered stages, fan-out to GOMAXPROCS workers, fan-in", Complexity: "2 channel handoffs per number", Notes: []report.Note{
+			report.Strength("Buffers decouple the stages; the slow stage uses every core"),
+			report.Pitfall("Still a channel operation per number per stage"),
+			report.Pitfall("Returns on cancel, but leaves every other stage blocked forever"),
+		}},
+		{Label: "Expert coding", Approach: "batches of numbers through an errgroup-style group", Complexity: "2 channel handoffs per batch", Notes: []report.Note{
+			report.Strength("Channel costs spread over thousands of numbers"),
+			report.Strength("Every send selects on ctx: cancel or failure stops every stage"),
+			report.Strength("Returns only after every goroutine has: nothing leaks"),
+			report.Pitfall("More code, and batches delay the first result"),
+		}},
+	},
+	Takeaway: "A channel handoff costs about as much as a small unit of work, so " +
+		"send batches, not items. And a pipeline owns its goroutines: " +
+		"every stage must stop on cancel, and the call must not return " +
+		"until they have.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "22-pipeline",
+		Title:       "Producer/Consumer Pipeline",
+		Description: "Build a generate → filter primes → aggregate channel pipeline, and see what buffering, batching and cancellation do to it.",
+		Category:    "concurrency",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    100_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("22-pipeline", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{10_000, 100_000, 1_000_000}
+	fs.Var(&sizes, "n", "comma-separated counts of numbers to test, from 10^12, e.g. 1e4,1e6")
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Producer/Consumer Pipeline", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Producer/Consumer Pipeline")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "GOMAXPROCS = %d\n", runtime.GOMAXPROCS(0))
+
+	for _, n := range sizes {
+		fmt.Fprintf(out.Table, "\nTallying the primes in [10^12, 10^12 + %d):\n", n)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		want := reference(base, base+uint64(n))
+		results, err := bench.CompareImpls(ctx, opts, uint64(n), want, bench.Equal[tally], pipelinesFor()...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "✔ All implementations find the same %d primes\n", want.Count)
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		rep.Add(fmt.Sprintf("n = %d numbers", n), results)
+
+		fmt.Fprintln(out.Table, "\nThroughput:")
+		for _, r := range results {
+			fmt.Fprintf(out.Table, "  %-14s %12.0f numbers/s\n", r.Name+":", float64(n)/r.Duration.Seconds())
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// What happens when the caller gives up part-way through.
+	fmt.Fprintln(out.Table)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(out.Table, "When the caller cancels")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	cancelN := uint64(sizes[len(sizes)-1])
+	fmt.Fprintf(w, "Each pipeline starts on %d numbers and is cancelled after %v.\n", cancelN, cancelAfter)
+	fmt.Fprintf(w, "Goroutines are counted %v after it returns.\n\n", settle)
+	for _, t := range tiers {
+		elapsed, err, leaked := cancelRun(ctx, t.run, cancelN)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var verdict string
+		switch {
+		case !errors.Is(err, context.Canceled):
+			verdict = "❌ ignored the cancel and finished"
+		case leaked > 0:
+			verdict = "❌ returned, but left its stages blocked"
+		default:
+			verdict = "✅ stopped every stage"
+		}
+		fmt.Fprintf(out.Table, "  %-14s returned after %8v   %3d goroutines left   %s\n",
+			t.name+":", elapsed.Round(time.Millisecond), leaked, verdict)
+		rep.AddEdgeCase(fmt.Sprintf("%s, cancelled after %v", t.name, cancelAfter),
+			fmt.Sprintf("returned after %v, %d goroutines left", elapsed.Round(time.Millisecond), leaked))
+	}
+
+	// Edge cases
+	fmt.Fprintln(out.Table)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(out.Table, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		lo, hi uint64
+		desc   string
+	}{
+		{0, 2, "[0, 2): no primes"},
+		{2, 3, "[2, 3): just 2"},
+		{base, base, "empty range"},
+		{base, base + 100, "[10^12, 10^12 + 100)"},
+		{1<<32 - 100, 1<<32 + 100, "around 2^32"},
+	}
+	for _, tc := range edgeCases {
+		want := reference(tc.lo, tc.hi)
+		fmt.Fprintf(out.Table, "\n%s (%d primes):\n", tc.desc, want.Count)
+		for _, t := range tiers {
+			got, err := t.run(ctx, tc.lo, tc.hi)
+			if err != nil {
+				return err
+			}
+			status := "✅"
+			if got != want {
+				status = "❌"
+			}
+			fmt.Fprintf(out.Table, "  %s %-14s %+v\n", status, t.name+":", got)
+			rep.AddEdgeCase(fmt.Sprintf("%s: %s", t.name, tc.desc), fmt.Sprintf("%s %+v", status, got))
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,256 @@
+package pipeline
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/iportilla/ai-coding/primes"
+)
+
+// tally is what the last stage makes of the primes it receives. Every
+// field can be combined in any order, so stages may deliver the primes
+// out of order.
+type tally struct {
+	Count   int
+	Sum     uint64
+	Largest uint64
+}
+
+// add counts the prime p.
+func (t *tally) add(p uint64) {
+	t.Count++
+	t.Sum += p
+	t.Largest = max(t.Largest, p)
+}
+
+// merge adds the primes counted in u.
+func (t *tally) merge(u tally) {
+	t.Count += u.Count
+	t.Sum += u.Sum
+	t.Largest = max(t.Largest, u.Largest)
+}
+
+// VIBE CODING: A goroutine per stage, unbuffered channels
+func vibePipeline(ctx context.Context, lo, hi uint64) (tally, error) {
+	/*
+	   Tally the primes in [lo, hi): generate the numbers, keep the
+	   primes, add them up.
+
+	   One goroutine per stage, joined by unbuffered channels. It reads
+	   like the diagram, but each number is handed over twice, and an
+	   unbuffered send waits for the receiver to arrive: the stages run
+	   in lockstep, paying for a goroutine switch at every step. Only
+	   one goroutine tests for primes, so extra cores sit idle. And ctx
+	   is ignored: once started, the pipeline runs to the end.
+	*/
+	nums := make(chan uint64)
+	go func() {
+		for n := lo; n < hi; n++ {
+			nums <- n
+		}
+		close(nums)
+	}()
+
+	ps := make(chan uint64)
+	go func() {
+		for n := range nums {
+			if primes.IsPrimeMillerRabin(n) {
+				ps <- n
+			}
+		}
+		close(ps)
+	}()
+
+	var t tally
+	for p := range ps {
+		t.add(p)
+	}
+	return t, nil
+}
+
+// humanBuffer is the capacity of each channel in the human pipeline.
+const humanBuffer = 1024
+
+// HUMAN CODING: Buffered stages, fan-out and fan-in
+func humanPipeline(ctx context.Context, lo, hi uint64) (tally, error) {
+	/*
+	   Tally the primes in [lo, hi).
+
+	   Buffered channels let each stage run ahead of the next instead of
+	   waiting for it at every number. The filter stage fans out to one
+	   worker per CPU, and their results fan back in to one channel,
+	   closed once every worker is done. The slow stage now uses every
+	   core.
+
+	   Each number still crosses two channels, one at a time. And the
+	   last stage returns as soon as ctx is done - but nothing tells the
+	   earlier stages, which block forever on their next send: every
+	   cancelled call leaks a goroutine per stage and worker.
+	*/
+	nums := make(chan uint64, humanBuffer)
+	go func() {
+		for n := lo; n < hi; n++ {
+			nums <- n
+		}
+		close(nums)
+	}()
+
+	// Fan out: every worker reads from nums.
+	workers := runtime.GOMAXPROCS(0)
+	outs := make([]chan uint64, workers)
+	for i := range outs {
+		outs[i] = make(chan uint64, humanBuffer)
+		go func() {
+			for n := range nums {
+				if primes.IsPrimeMillerRabin(n) {
+					outs[i] <- n
+				}
+			}
+			close(outs[i])
+		}()
+	}
+
+	// Fan in: copy every worker's primes into ps, and close it after the
+	// last worker is done.
+	ps := make(chan uint64, humanBuffer)
+	var wg sync.WaitGroup
+	for _, out := range outs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range out {
+				ps <- p
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(ps)
+	}()
+
+	var t tally
+	for {
+		select {
+		case p, ok := <-ps:
+			if !ok {
+				return t, nil
+			}
+			t.add(p)
+		case <-ctx.Done():
+			return t, ctx.Err() // the other stages are left blocked
+		}
+	}
+}
+
+// expertBatch is how many numbers the expert pipeline hands over at once.
+const expertBatch = 4096
+
+// span is the numbers in [lo, hi).
+type span struct {
+	lo, hi uint64
+}
+
+// EXPERT CODING: Batches through a cancellable group
+func expertPipeline(ctx context.Context, lo, hi uint64) (tally, error) {
+	/*
+	   Tally the primes in [lo, hi).
+
+	   Send work in batches, not one item at a time: the generator hands
+	   out spans of numbers, and each worker sends back one tally per
+	   span. A channel operation costs about as much as testing a number
+	   for primality, and now there are two per 4096 numbers instead of
+	   two per number.
+
+	   Every stage runs in an errgroup-style group. Every send selects on
+	   ctx, so once ctx is done - the caller gave up, or a stage failed -
+	   each stage stops at its next step instead of blocking forever. The
+	   group's Wait returns only when every stage has, and the last
+	   stage's channel is closed after that: a call that returns leaves
+	   nothing running.
+	*/
+	g, ctx := withContext(ctx)
+	spans := make(chan span)
+	g.Go(func() error {
+		defer close(spans)
+		for b := lo; b < hi; b = min(b+expertBatch, hi) {
+			select {
+			case spans <- span{b, min(b+expertBatch, hi)}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+
+	workers := runtime.GOMAXPROCS(0)
+	tallies := make(chan tally, workers)
+	for range workers {
+		g.Go(func() error {
+			for s := range spans {
+				var t tally
+				for n := s.lo; n < s.hi; n++ {
+					if primes.IsPrimeMillerRabin(n) {
+						t.add(n)
+					}
+				}
+				select {
+				case tallies <- t:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+	}
+
+	var err error
+	go func() {
+		err = g.Wait()
+		close(tallies)
+	}()
+	var total tally
+	for t := range tallies {
+		total.merge(t)
+	}
+	return total, err
+}
+
+// group is a minimal version of golang.org/x/sync/errgroup, as in example
+// 09, written out because the examples use only the standard library. In
+// real code, use errgroup.
+type group struct {
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+	once   sync.Once
+	err    error
+}
+
+// withContext returns a group and a context that is cancelled when a
+// function run by the group fails or Wait returns.
+func withContext(ctx context.Context) (*group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &group{cancel: cancel}, ctx
+}
+
+// Go runs f in a new goroutine.
+func (g *group) Go(f func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := f(); err != nil {
+			g.once.Do(func() {
+				g.err = err
+				g.cancel(err)
+			})
+		}
+	}()
+}
+
+// Wait waits for every function started with Go and returns the first
+// error any of them returned.
+func (g *group) Wait() error {
+	g.wg.Wait()
+	g.cancel(g.err)
+	return g.err
+}
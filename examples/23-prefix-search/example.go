@@ -0,0 +1,352 @@
+// Package prefixsearch compares three ways to find the words that start
+// with a prefix, as an autocomplete box does on every keystroke.
+package prefixsearch
+
+import (
+	"context"
+	_ "embed"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// dictionaryText is the words searched: common English words in
+// lowercase ASCII, one per line.
+//
+//go:embed dictionary.txt
+var dictionaryText string
+
+// dictionary is dictionaryText as a list of words.
+var dictionary = strings.Fields(dictionaryText)
+
+// defaultLimit is how many matches a query shows, like the rows of an
+// autocomplete dropdown.
+const defaultLimit = 10
+
+// answer is what a query returns.
+type answer struct {
+	matches []string
+	total   int
+}
+
+// makeQueries returns n prefixes to complete. Most are the first one to
+// four letters of a dictionary word, as typed so far; one in ten is two
+// or three random letters, which often match nothing.
+func makeQueries(seed input.Seed, n int) []string {
+	rng := seed.Rand("queries", n)
+	queries := make([]string, n)
+	for i := range queries {
+		if rng.IntN(10) == 0 {
+			b := make([]byte, 2+rng.IntN(2))
+			for j := range b {
+				b[j] = byte('a' + rng.IntN(26))
+			}
+			queries[i] = string(b)
+			continue
+		}
+		w := dictionary[rng.IntN(len(dictionary))]
+		queries[i] = w[:1+rng.IntN(min(len(w), 4))]
+	}
+	return queries
+}
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	build            func(words []string) completer
+}{
+	{"Vibe coding", "O(n) per query", func(words []string) completer { return newVibeIndex(words) }},
+	{"Human coding", "O(log n) per query", func(words []string) completer { return newHumanIndex(words) }},
+	{"Expert coding", "O(len(prefix)) per query", func(words []string) completer { return newExpertIndex(words) }},
+}
+
+// completeAll returns a function answering every query with c.
+func completeAll(c completer, limit int) func(queries []string) []answer {
+	return func(queries []string) []answer {
+		answers := make([]answer, len(queries))
+		for i, q := range queries {
+			answers[i].matches, answers[i].total = c.Complete(q, limit)
+		}
+		return answers
+	}
+}
+
+// diffAnswers describes the first query whose answers differ, or returns
+// nil if none do.
+func diffAnswers(got, want []answer) error {
+	if len(got) != len(want) {
+		return fmt.Errorf("got %d answers, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].total != want[i].total || !slices.Equal(got[i].matches, want[i].matches) {
+			return fmt.Errorf("query %d: got %d matches %v, want %d matches %v",
+				i, got[i].total, got[i].matches, want[i].total, want[i].matches)
+		}
+	}
+	return nil
+}
+
+// completers returns the implementations to compare, answering with the
+// indexes in built.
+func completers(built []completer, limit int) []bench.Impl[[]string, []answer] {
+	impls := make([]bench.Impl[[]string, []answer], len(tiers))
+	for i, t := range tiers {
+		impls[i] = bench.Impl[[]string, []answer]{Name: t.name, Complexity: t.complexity, Func: completeAll(built[i], limit)}
+	}
+	return impls
+}
+
+// buildAll builds every tier's index on dictionary.
+func buildAll() []completer {
+	built := make([]completer, len(tiers))
+	for i, t := range tiers {
+		built[i] = t.build(dictionary)
+	}
+	return built
+}
+
+// impls returns the implementations timed answering n queries.
+func impls(n int) []bench.Implementation {
+	queries := makeQueries(input.DefaultSeed, n)
+	var list []bench.Implementation
+	for _, c := range completers(buildAll(), defaultLimit) {
+		list = append(list, c.Implementation(queries))
+	}
+	return list
+}
+
+// measureBuild builds an index on words, and returns it, how long that
+// took and how many bytes it allocated.
+func measureBuild(build func([]string) completer, words []string) (completer, time.Duration, uint64) {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	c := build(words)
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	return c, elapsed, after.TotalAlloc - before.TotalAlloc
+}
+
+// latencies times every query on its own.
+func latencies(c completer, queries []string, limit int) []time.Duration {
+	samples := make([]time.Duration, len(queries))
+	for i, q := range queries {
+		start := time.Now()
+		c.Complete(q, limit)
+		samples[i] = time.Since(start)
+	}
+	return samples
+}
+
+// clockOverhead estimates how much of a latency sample is spent reading
+// the clock: the median of timing nothing.
+func clockOverhead() time.Duration {
+	samples := make([]time.Duration, 1000)
+	for i := range samples {
+		start := time.Now()
+		samples[i] = time.Since(start)
+	}
+	return bench.Percentile(samples, 50)
+}
+
+// show formats an answer, e.g. "3 matches: cab, cabin, cable".
+func show(a answer) string {
+	switch a.total {
+	case 0:
+		return "no matches"
+	case 1:
+		return "1 match: " + a.matches[0]
+	}
+	s := fmt.Sprintf("%d matches: %s", a.total, strings.Join(a.matches, ", "))
+	if a.total > len(a.matches) {
+		s += ", ..."
+	}
+	return s
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "strings.HasPrefix on every word, then sort", Complexity: "O(n) per query", Notes: []report.Note{
+			report.Strength("No index to build or keep up to date"),
+			report.Pitfall("Reads the whole dictionary on every keystroke"),
+			report.Pitfall("Short prefixes collect and sort hundreds of words to show ten"),
+		}},
+		{Label: "Human coding", Approach: "two binary searches in a sorted slice", Complexity: "O(log n) per query", Notes: []report.Note{
+			report.Strength("Matches are a slice of the sorted words: nothing copied or sorted"),
+			report.Strength("No memory beyond one sorted copy of the words"),
+			report.Pitfall("Each step compares whole strings, scattered through memory"),
+		}},
+		{Label: "Expert coding", Approach: "a trie in flat arrays, each node knowing its range of words", Complexity: "O(len(prefix)) per query", Notes: []report.Note{
+			report.Strength("Cost depends on the prefix typed, not the dictionary size"),
+			report.Strength("Counts every match without visiting one"),
+			report.Pitfall("A node per distinct prefix: many times the memory of the words, built up front"),
+		}},
+	},
+	Takeaway: "Sort once and every prefix is a contiguous range: binary search " +
+		"finds it in O(log n), and a trie in O(len(prefix)). Look at the tail " +
+		"latencies, not just the average - a keystroke that reads the whole " +
+		"dictionary is the one the user notices.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "23-prefix-search",
+		Title:       "Prefix Search",
+		Description: "Autocomplete words from a bundled dictionary by linear scan, binary search and a trie, with per-query latency percentiles.",
+		Category:    "searching",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    10_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("23-prefix-search", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{1_000, 10_000, 100_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of queries, e.g. 1e3,1e5")
+	limit := fs.Int("limit", defaultLimit, "matches shown per query")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *limit < 1 {
+		return fmt.Errorf("-limit must be at least 1, got %d", *limit)
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Prefix Search", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Prefix Search")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "Dictionary: dictionary.txt, %d words, %s; showing up to %d matches per query\n",
+		len(dictionary), bench.FormatBytes(uint64(len(dictionaryText))), *limit)
+
+	// Build each index once; the queries below share them.
+	fmt.Fprintln(out.Table, "\nBuilding each index:")
+	built := make([]completer, len(tiers))
+	for i, t := range tiers {
+		c, elapsed, bytes := measureBuild(t.build, dictionary)
+		built[i] = c
+		fmt.Fprintf(out.Table, "  %-14s %10v   %9s allocated\n", t.name+":", elapsed.Round(time.Microsecond), bench.FormatBytes(bytes))
+		rep.AddEdgeCase(fmt.Sprintf("%s: building the index", t.name), fmt.Sprintf("%v, %s allocated", elapsed.Round(time.Microsecond), bench.FormatBytes(bytes)))
+	}
+	overhead := clockOverhead()
+
+	for _, n := range sizes {
+		queries := makeQueries(*seed, n)
+		fmt.Fprintf(out.Table, "\nAnswering %d queries (seed %d):\n", n, *seed)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		impls := completers(built, *limit)
+		want := impls[0].Func(queries)
+		results, err := bench.CompareImpls(ctx, opts, queries, want, diffAnswers, impls...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "✔ All implementations give the same answers")
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d queries", n), results)
+
+		// Percentiles from one more, untimed pass each, timing every
+		// query on its own.
+		fmt.Fprintf(out.Table, "\nLatency per query (each includes about %v of reading the clock):\n", overhead)
+		for i, t := range tiers {
+			samples := latencies(built[i], queries, *limit)
+			p50, p90, p99, worst := bench.Percentile(samples, 50), bench.Percentile(samples, 90),
+				bench.Percentile(samples, 99), bench.Percentile(samples, 100)
+			fmt.Fprintf(out.Table, "  %-14s p50 %9v   p90 %9v   p99 %9v   max %9v\n", t.name+":", p50, p90, p99, worst)
+			section.Notes = append(section.Notes, fmt.Sprintf("%s: p50 %v, p99 %v, max %v", t.name, p50, p99, worst))
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		prefix string
+		desc   string
+	}{
+		{"", "empty prefix: every word"},
+		{"car", "a word that is also a prefix"},
+		{"zoo", "the last word"},
+		{"zebras", "longer than any match"},
+		{"qx", "no word starts with it"},
+		{"Car", "uppercase: words are lowercase"},
+		{"é", "non-ASCII"},
+	}
+	for _, tc := range edgeCases {
+		var got []string
+		for _, c := range built {
+			matches, total := c.Complete(tc.prefix, *limit)
+			got = append(got, show(answer{matches, total}))
+		}
+		status := "✅"
+		for _, g := range got {
+			if g != got[0] {
+				status = "❌"
+			}
+		}
+		fmt.Fprintf(w, "%s %s %q: %s\n", status, tc.desc, tc.prefix, got[0])
+		rep.AddEdgeCase(fmt.Sprintf("%s %q", tc.desc, tc.prefix), got[0])
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package prefixsearch
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// completer answers autocomplete queries on a fixed list of words.
+type completer interface {
+	// Complete returns the first limit words that start with prefix, in
+	// byte order, and how many words start with it in all.
+	Complete(prefix string, limit int) (matches []string, total int)
+}
+
+// VIBE CODING: Scan every word
+type vibeIndex struct {
+	words []string
+}
+
+func newVibeIndex(words []string) *vibeIndex {
+	return &vibeIndex{words: words}
+}
+
+func (x *vibeIndex) Complete(prefix string, limit int) ([]string, int) {
+	/*
+	   Check every word with strings.HasPrefix, collect the matches, sort
+	   them and keep the first few.
+
+	   Nothing to build, and obviously right. But every keystroke reads
+	   the whole dictionary, even when nothing matches, and a short
+	   prefix like "s" collects and sorts hundreds of words only to show
+	   ten of them.
+	*/
+	var matches []string
+	for _, w := range x.words {
+		if strings.HasPrefix(w, prefix) {
+			matches = append(matches, w)
+		}
+	}
+	sort.Strings(matches)
+	total := len(matches)
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, total
+}
+
+// HUMAN CODING: Binary search a sorted slice
+type humanIndex struct {
+	words []string // sorted, without duplicates
+}
+
+func newHumanIndex(words []string) *humanIndex {
+	sorted := slices.Clone(words)
+	slices.Sort(sorted)
+	return &humanIndex{words: slices.Compact(sorted)}
+}
+
+func (x *humanIndex) Complete(prefix string, limit int) ([]string, int) {
+	/*
+	   In a sorted list, the words that start with prefix sit next to
+	   each other, starting at the first word not less than prefix. One
+	   binary search finds where they start, a second where they stop,
+	   and the answer is the slice between: nothing to copy or sort.
+
+	   Each search compares O(log n) whole strings, and the words they
+	   compare are scattered through memory.
+	*/
+	lo := sort.SearchStrings(x.words, prefix)
+	total := sort.Search(len(x.words)-lo, func(i int) bool {
+		return !strings.HasPrefix(x.words[lo+i], prefix)
+	})
+	hi := lo + min(total, limit)
+	return x.words[lo:hi:hi], total
+}
+
+// trieNode is a node of a trie over a sorted list of words: the words
+// that start with the node's prefix are words[first:last], and its
+// children are edges[edgeLo:edgeHi], in order of label.
+type trieNode struct {
+	first, last    int32
+	edgeLo, edgeHi int32
+}
+
+// trieEdge leads from a node to the child whose prefix adds label.
+type trieEdge struct {
+	label byte
+	child int32
+}
+
+// EXPERT CODING: A flat trie over the sorted words
+type expertIndex struct {
+	words []string // sorted, without duplicates
+	nodes []trieNode
+	edges []trieEdge
+}
+
+func newExpertIndex(words []string) *expertIndex {
+	sorted := slices.Clone(words)
+	slices.Sort(sorted)
+	x := &expertIndex{words: slices.Compact(sorted)}
+	x.build(0, len(x.words), 0)
+	return x
+}
+
+// build adds the node for the words in words[lo:hi], which share their
+// first depth bytes, and the nodes below it, and returns its index.
+// Each node's edges are reserved before its children are built, so
+// they end up next to each other.
+func (x *expertIndex) build(lo, hi, depth int) int32 {
+	n := int32(len(x.nodes))
+	x.nodes = append(x.nodes, trieNode{first: int32(lo), last: int32(hi)})
+	if lo < hi && len(x.words[lo]) == depth {
+		lo++ // the prefix itself is a word, and sorts first
+	}
+
+	// Group the remaining words by their next byte.
+	var starts []int
+	for i := lo; i < hi; i++ {
+		if i == lo || x.words[i][depth] != x.words[i-1][depth] {
+			starts = append(starts, i)
+		}
+	}
+	edgeLo := len(x.edges)
+	x.edges = append(x.edges, make([]trieEdge, len(starts))...)
+	x.nodes[n].edgeLo, x.nodes[n].edgeHi = int32(edgeLo), int32(len(x.edges))
+	for i, start := range starts {
+		end := hi
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		child := x.build(start, end, depth+1)
+		x.edges[edgeLo+i] = trieEdge{label: x.words[start][depth], child: child}
+	}
+	return n
+}
+
+func (x *expertIndex) Complete(prefix string, limit int) ([]string, int) {
+	/*
+	   Walk the trie one byte of prefix at a time: the node reached knows
+	   the range of sorted words below it, so the answer is a slice of
+	   the word list, and the count comes free. The cost depends on the
+	   length of prefix, not on how many words there are.
+
+	   The nodes and edges live in two flat slices, not a pointer per
+	   child, so a lookup touches a few small neighbouring structs and
+	   never compares a string. The price is memory: a node for every
+	   distinct prefix in the dictionary, built once up front.
+	*/
+	n := int32(0)
+	for i := 0; i < len(prefix); i++ {
+		node := x.nodes[n]
+		next := int32(-1)
+		for _, e := range x.edges[node.edgeLo:node.edgeHi] {
+			if e.label == prefix[i] {
+				next = e.child
+				break
+			}
+		}
+		if next < 0 {
+			return nil, 0
+		}
+		n = next
+	}
+	node := x.nodes[n]
+	total := int(node.last - node.first)
+	hi := int(node.first) + min(total, limit)
+	return x.words[node.first:hi:hi], total
+}
@@ -0,0 +1,306 @@
+// Package priorityqueue compares three ways to keep a min-priority queue
+// of ints.
+package priorityqueue
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// Sorting before every pop takes seconds per run above this many
+// operations.
+const maxVibeN = 10_000
+
+// op is one operation on a queue: push value, or pop the smallest.
+type op struct {
+	pop   bool
+	value int
+}
+
+// makeOps returns a stream of n operations. The first quarter are
+// pushes, filling the queue; after that each is a push or a pop with
+// equal odds, so the queue's size wanders around n/4. There is never a
+// pop on an empty queue.
+func makeOps(seed input.Seed, n int) []op {
+	rng := seed.Rand("ops", n)
+	ops := make([]op, n)
+	size := 0
+	for i := range ops {
+		if i < n/4 || size == 0 || rng.IntN(2) == 0 {
+			ops[i] = op{value: rng.IntN(1 << 30)}
+			size++
+		} else {
+			ops[i] = op{pop: true}
+			size--
+		}
+	}
+	return ops
+}
+
+// peak returns the largest size a queue reaches running ops.
+func peak(ops []op) int {
+	size, largest := 0, 0
+	for _, o := range ops {
+		if o.pop {
+			size--
+		} else {
+			size++
+		}
+		largest = max(largest, size)
+	}
+	return largest
+}
+
+// levels returns how many levels a heap of n values has when each node
+// has d children.
+func levels(n, d int) int {
+	l := 0
+	for full, width := 0, 1; full < n; width *= d {
+		full += width
+		l++
+	}
+	return l
+}
+
+// replay runs ops on q and returns the popped values, in order.
+func replay(q queue, ops []op) []int {
+	var popped []int
+	for _, o := range ops {
+		if o.pop {
+			popped = append(popped, q.Pop())
+		} else {
+			q.Push(o.value)
+		}
+	}
+	return popped
+}
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	new              func() queue
+}{
+	{"Vibe coding", "sort per pop, O(n log n)", func() queue { return &vibeQueue{} }},
+	{"Human coding", "container/heap, O(log n)", func() queue { return &humanQueue{} }},
+	{"Expert coding", "4-ary heap, O(log n)", func() queue { return &expertQueue{} }},
+}
+
+// queuesFor returns the implementations to compare on n operations,
+// leaving out sorting before every pop where it would take seconds.
+func queuesFor(n int) []bench.Impl[[]op, []int] {
+	impls := []bench.Impl[[]op, []int]{}
+	for _, t := range tiers {
+		if t.name == "Vibe coding" && n > maxVibeN {
+			continue
+		}
+		impls = append(impls, bench.Impl[[]op, []int]{
+			Name: t.name, Complexity: t.complexity,
+			Func: func(ops []op) []int { return replay(t.new(), ops) },
+		})
+	}
+	return impls
+}
+
+// impls returns the implementations timed on a stream of n operations.
+func impls(n int) []bench.Implementation {
+	ops := makeOps(input.DefaultSeed, n)
+	var list []bench.Implementation
+	for _, q := range queuesFor(n) {
+		list = append(list, q.Implementation(ops))
+	}
+	return list
+}
+
+// pushes returns operations pushing each of values, in order.
+func pushes(values ...int) []op {
+	ops := make([]op, len(values))
+	for i, v := range values {
+		ops[i] = op{value: v}
+	}
+	return ops
+}
+
+// pops returns k pop operations.
+func pops(k int) []op {
+	return slices.Repeat([]op{{pop: true}}, k)
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "append to a slice, sort it before every pop", Complexity: "O(n log n) per pop", Notes: []report.Note{
+			report.Strength("Pushes are a plain append, and the code is obviously right"),
+			report.Pitfall("Every pop sorts the whole queue to find one value"),
+			report.Pitfall("Popping from the front keeps the popped values' memory alive"),
+		}},
+		{Label: "Human coding", Approach: "binary heap with container/heap", Complexity: "O(log n) per operation", Notes: []report.Note{
+			report.Strength("The standard library's heap: correct and well known"),
+			report.Strength("Works for any type that implements heap.Interface"),
+			report.Pitfall("Every Less and Swap is an interface method call"),
+			report.Pitfall("Push and Pop box each int in an interface value, which allocates"),
+		}},
+		{Label: "Expert coding", Approach: "4-ary heap written out for ints", Complexity: "O(log n) per operation", Notes: []report.Note{
+			report.Strength("Half as many levels as a binary heap"),
+			report.Strength("A node's four children share a cache line"),
+			report.Strength("Moves a hole instead of swapping; no interfaces, no boxing"),
+			report.Pitfall("Written for one type; Go generics or a copy for each other type"),
+		}},
+	},
+	Takeaway: "A priority queue needs the smallest value, not a sorted list: a heap " +
+		"finds it in O(log n). Past that, the constant factor is memory: fewer, " +
+		"wider levels and no interface boxing make the same O(log n) faster.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "24-priority-queue",
+		Title:       "Priority Queue",
+		Description: "Keep a min-priority queue by sorting before every pop, with container/heap, and with a 4-ary heap, on a stream of mixed pushes and pops.",
+		Category:    "data structures",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    10_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("24-priority-queue", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{1_000, 10_000, 100_000, 1_000_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of operations, e.g. 1e4,1e6")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	for _, n := range sizes {
+		if n < 1 {
+			return fmt.Errorf("-n must be at least 1, not %d", n)
+		}
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Priority Queue", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Priority Queue")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	for _, n := range sizes {
+		ops := makeOps(*seed, n)
+		largest := peak(ops)
+		fmt.Fprintf(out.Table, "\n%d operations (seed %d), up to %d values queued:\n", n, *seed, largest)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		want := replay(&humanQueue{}, ops)
+		results, err := bench.CompareImpls(ctx, opts, ops, want, bench.DiffSlices, queuesFor(n)...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "✔ All implementations pop the same %d values\n", len(want))
+		fmt.Fprintf(w, "  A binary heap of %d values has %d levels, a 4-ary heap %d\n",
+			largest, levels(largest, 2), levels(largest, arity))
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d operations", n), results)
+
+		fmt.Fprintln(out.Table, "\nPer operation:")
+		for _, r := range results {
+			fmt.Fprintf(out.Table, "  %-14s %9.1f ns   %5.2f allocs\n", r.Name+":",
+				float64(r.Duration.Nanoseconds())/float64(n), float64(r.Allocs)/float64(n))
+		}
+		if n > maxVibeN {
+			note := fmt.Sprintf("Vibe coding skipped: sorting before every pop is impractical above n=%d", maxVibeN)
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		ops  []op
+		want []int
+		desc string
+	}{
+		{slices.Concat(pushes(7), pops(1)), []int{7}, "one value"},
+		{slices.Concat(pushes(3, 3, 3), pops(3)), []int{3, 3, 3}, "duplicates"},
+		{slices.Concat(pushes(1, 2, 3, 4, 5, 6), pops(6)), []int{1, 2, 3, 4, 5, 6}, "pushed in order"},
+		{slices.Concat(pushes(6, 5, 4, 3, 2, 1), pops(6)), []int{1, 2, 3, 4, 5, 6}, "pushed in reverse"},
+		{slices.Concat(pushes(-1, 5, -10), pops(3)), []int{-10, -1, 5}, "negative values"},
+		{slices.Concat(pushes(math.MaxInt, 0, math.MinInt), pops(3)), []int{math.MinInt, 0, math.MaxInt}, "extreme values"},
+		{slices.Concat(pushes(5), pops(1), pushes(2, 9), pops(1), pushes(1), pops(2)), []int{5, 2, 1, 9}, "interleaved"},
+		{slices.Concat(pushes(4), pops(1), pushes(8), pops(1)), []int{4, 8}, "refilled after emptying"},
+	}
+	for _, tc := range edgeCases {
+		status := "✅"
+		var wrong []string
+		for _, t := range tiers {
+			if got := replay(t.new(), tc.ops); !slices.Equal(got, tc.want) {
+				status = "❌"
+				wrong = append(wrong, fmt.Sprintf("%s popped %v", t.name, got))
+			}
+		}
+		fmt.Fprintf(w, "%s %s: %v\n", status, tc.desc, tc.want)
+		for _, msg := range wrong {
+			fmt.Fprintln(w, "   "+msg)
+		}
+		rep.AddEdgeCase(tc.desc, fmt.Sprintf("%s %v", status, tc.want))
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
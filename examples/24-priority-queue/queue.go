@@ -0,0 +1,147 @@
+package priorityqueue
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// queue is a min-priority queue of ints: Pop returns the smallest value
+// pushed and not yet popped. Pop must not be called on an empty queue.
+type queue interface {
+	Push(v int)
+	Pop() int
+	Len() int
+}
+
+// VIBE CODING: Sort the slice before every pop
+type vibeQueue struct {
+	items []int
+}
+
+func (q *vibeQueue) Push(v int) {
+	q.items = append(q.items, v)
+}
+
+func (q *vibeQueue) Pop() int {
+	/*
+	   Keep the values in a slice; to pop, sort it and take the first.
+
+	   Pushing is free, and the code is obviously right. But every pop
+	   sorts the whole queue again, O(n log n), to find one value - and
+	   a queue of n values that sees n pops does O(n² log n) work.
+	   Slicing off the front also keeps the popped values' memory alive
+	   until the slice grows into a new array.
+	*/
+	sort.Ints(q.items)
+	v := q.items[0]
+	q.items = q.items[1:]
+	return v
+}
+
+func (q *vibeQueue) Len() int { return len(q.items) }
+
+// intHeap implements heap.Interface, as in the container/heap docs.
+type intHeap []int
+
+func (h intHeap) Len() int           { return len(h) }
+func (h intHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h intHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *intHeap) Push(x any)        { *h = append(*h, x.(int)) }
+
+func (h *intHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// HUMAN CODING: container/heap
+type humanQueue struct {
+	h intHeap
+}
+
+func (q *humanQueue) Push(v int) {
+	/*
+	   container/heap keeps the slice as a binary heap: every value is
+	   no larger than its two children, so the smallest is at the root.
+	   Push and Pop move one value up or down the tree, O(log n) each.
+
+	   The package works on any type through heap.Interface, and pays
+	   for that: every Less and Swap is a method call through an
+	   interface, and every pushed int is boxed into an interface value,
+	   which allocates for most values.
+	*/
+	heap.Push(&q.h, v)
+}
+
+func (q *humanQueue) Pop() int { return heap.Pop(&q.h).(int) }
+func (q *humanQueue) Len() int { return q.h.Len() }
+
+// arity is how many children each node of the expert heap has.
+const arity = 4
+
+// EXPERT CODING: A 4-ary heap on a plain slice
+type expertQueue struct {
+	items []int
+}
+
+func (q *expertQueue) Push(v int) {
+	/*
+	   The same heap, with four children per node instead of two, and
+	   written out for ints.
+
+	   Four children make the tree half as deep, so a push moves a
+	   value up half as many levels. A pop compares four children per
+	   level instead of two, but they sit next to each other in the
+	   slice - usually in one cache line - so the extra comparisons are
+	   cheap next to the cache misses saved by the shallower tree.
+	   Sifting moves a hole instead of swapping at every level, the
+	   comparisons are plain < on ints, and nothing is boxed.
+	*/
+	q.items = append(q.items, v)
+	i := len(q.items) - 1
+	for i > 0 {
+		parent := (i - 1) / arity
+		if q.items[parent] <= v {
+			break
+		}
+		q.items[i] = q.items[parent]
+		i = parent
+	}
+	q.items[i] = v
+}
+
+func (q *expertQueue) Pop() int {
+	top := q.items[0]
+	n := len(q.items) - 1
+	v := q.items[n]
+	q.items = q.items[:n]
+	if n == 0 {
+		return top
+	}
+
+	// Move the hole at the root down to where the last value belongs.
+	i := 0
+	for {
+		first := i*arity + 1
+		if first >= n {
+			break
+		}
+		smallest := first
+		for c := first + 1; c < min(first+arity, n); c++ {
+			if q.items[c] < q.items[smallest] {
+				smallest = c
+			}
+		}
+		if v <= q.items[smallest] {
+			break
+		}
+		q.items[i] = q.items[smallest]
+		i = smallest
+	}
+	q.items[i] = v
+	return top
+}
+
+func (q *expertQueue) Len() int { return len(q.items) }
@@ -0,0 +1,335 @@
+// Package unionfind compares three ways to answer dynamic connectivity
+// queries: are two elements joined by the unions made so far?
+package unionfind
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// A search per query takes seconds per run above this many elements.
+const maxVibeN = 10_000
+
+// Quick-union's trees grow tall even on random unions, and it takes
+// seconds per run above this many elements.
+const maxHumanN = 100_000
+
+// Quick-union takes seconds on unions in a line above this many
+// elements, as every query walks the whole line.
+const maxHumanLineN = 10_000
+
+// op is one operation: a union of a and b, or a query whether they are
+// connected.
+type op struct {
+	query bool
+	a, b  int
+}
+
+// makeOps returns n operations on n elements, each a union of a random
+// pair with odds 3 in 4, and otherwise a query of a random pair.
+func makeOps(seed input.Seed, n int) []op {
+	rng := seed.Rand("ops", n)
+	ops := make([]op, n)
+	for i := range ops {
+		ops[i] = op{query: rng.IntN(4) == 0, a: rng.IntN(n), b: rng.IntN(n)}
+	}
+	return ops
+}
+
+// lineOps returns operations on n elements joining them in a line, 0
+// to 1 to 2 and so on, then querying whether 0 is connected to each
+// element in turn. Quick-union builds a single chain from them, with 0
+// at the bottom.
+func lineOps(n int) []op {
+	ops := make([]op, 0, 2*n)
+	for i := 0; i+1 < n; i++ {
+		ops = append(ops, op{a: i, b: i + 1})
+	}
+	for i := range n {
+		ops = append(ops, op{query: true, a: 0, b: i})
+	}
+	return ops
+}
+
+// replay runs ops on u and returns the queries' answers, in order.
+func replay(u connectivity, ops []op) []bool {
+	var answers []bool
+	for _, o := range ops {
+		if o.query {
+			answers = append(answers, u.Connected(o.a, o.b))
+		} else {
+			u.Union(o.a, o.b)
+		}
+	}
+	return answers
+}
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	new              func(n int) connectivity
+}{
+	{"Vibe coding", "BFS per query, O(n + m)", func(n int) connectivity { return newVibeUF(n) }},
+	{"Human coding", "quick-union, O(n) worst", func(n int) connectivity { return newHumanUF(n) }},
+	{"Expert coding", "rank + path halving, O(α(n))", func(n int) connectivity { return newExpertUF(n) }},
+}
+
+// unionFindsFor returns the implementations to compare on n elements,
+// leaving out those that would take seconds.
+func unionFindsFor(n int) []bench.Impl[[]op, []bool] {
+	impls := []bench.Impl[[]op, []bool]{}
+	for _, t := range tiers {
+		if t.name == "Vibe coding" && n > maxVibeN || t.name == "Human coding" && n > maxHumanN {
+			continue
+		}
+		impls = append(impls, bench.Impl[[]op, []bool]{
+			Name: t.name, Complexity: t.complexity,
+			Func: func(ops []op) []bool { return replay(t.new(n), ops) },
+		})
+	}
+	return impls
+}
+
+// impls returns the implementations timed on n random operations on n
+// elements.
+func impls(n int) []bench.Implementation {
+	ops := makeOps(input.DefaultSeed, n)
+	var list []bench.Implementation
+	for _, u := range unionFindsFor(n) {
+		list = append(list, u.Implementation(ops))
+	}
+	return list
+}
+
+// count returns how many answers are true.
+func count(answers []bool) int {
+	c := 0
+	for _, a := range answers {
+		if a {
+			c++
+		}
+	}
+	return c
+}
+
+// perOp replays ops once on a new structure of n elements, and returns
+// the links followed and the time taken per operation: zero for no
+// operations.
+func perOp(newUF func(n int) connectivity, n int, ops []op) (steps float64, elapsed time.Duration) {
+	if len(ops) == 0 {
+		return 0, 0
+	}
+	u := newUF(n)
+	start := time.Now()
+	replay(u, ops)
+	elapsed = time.Since(start)
+	return float64(u.Steps()) / float64(len(ops)), elapsed / time.Duration(len(ops))
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "store the edges, breadth-first search per query", Complexity: "O(n + m) per query", Notes: []report.Note{
+			report.Strength("Unions are free, and the answer is plainly right"),
+			report.Pitfall("Every query walks the whole component, and allocates for all n elements"),
+			report.Pitfall("Nothing one search learns helps the next"),
+		}},
+		{Label: "Human coding", Approach: "quick-union: a parent per element, same root means connected", Complexity: "O(n) per operation, worst case", Notes: []report.Note{
+			report.Strength("A few lines, no allocation after setup"),
+			report.Pitfall("Even random unions build tall trees: the cost grows faster than n"),
+			report.Pitfall("Unions in the wrong order build a chain, and every find walks it"),
+		}},
+		{Label: "Expert coding", Approach: "union by rank with path halving", Complexity: "O(α(n)) amortized", Notes: []report.Note{
+			report.Strength("Rank keeps trees O(log n) deep; halving flattens them as they're used"),
+			report.Strength("α(n) ≤ 4 for any n that fits in memory: constant in practice"),
+			report.Strength("int32 parents and uint8 ranks: five bytes per element"),
+			report.Pitfall("Only adds connections: removing one needs another structure"),
+		}},
+	},
+	Takeaway: "Answering by search repeats work that a union-find keeps. Quick-union " +
+		"keeps it but can degrade to a chain; two small fixes - union by rank " +
+		"and path compression - make every operation effectively constant time, " +
+		"whatever the order of unions.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "25-union-find",
+		Title:       "Union-Find",
+		Description: "Answer dynamic connectivity queries by BFS, quick-union and union by rank with path compression, and measure the amortized cost per operation.",
+		Category:    "graphs",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    10_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("25-union-find", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{1_000, 10_000, 100_000, 1_000_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of elements, each with as many operations, e.g. 1e4,1e6")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Union-Find", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Union-Find")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	for _, n := range sizes {
+		ops := makeOps(*seed, n)
+		fmt.Fprintf(out.Table, "\n%d elements, %d random unions and queries (seed %d):\n", n, n, *seed)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		want := replay(newExpertUF(n), ops)
+		results, err := bench.CompareImpls(ctx, opts, ops, want, bench.DiffSlices, unionFindsFor(n)...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "✔ All implementations agree: %d of %d queries connected\n", count(want), len(want))
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d elements", n), results)
+
+		if n > maxVibeN {
+			note := fmt.Sprintf("Vibe coding skipped: a search per query is impractical above n=%d", maxVibeN)
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		}
+		if n > maxHumanN {
+			note := fmt.Sprintf("Human coding skipped: quick-union's tall trees are impractical above n=%d", maxHumanN)
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// The cost per operation as n grows, counted in links followed as
+	// well as timed, on random operations and on quick-union's worst
+	// case.
+	fmt.Fprintln(out.Table)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(out.Table, "Amortized cost per operation")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Links followed per union or query, and time per operation, from one run each.")
+
+	workloads := []struct {
+		desc     string
+		ops      func(n int) []op
+		maxHuman int
+	}{
+		{"random unions and queries", func(n int) []op { return makeOps(*seed, n) }, maxHumanN},
+		{"unions in a line, then queries from its end", lineOps, maxHumanLineN},
+	}
+	for _, wl := range workloads {
+		fmt.Fprintf(out.Table, "\n%s:\n", wl.desc)
+		fmt.Fprintf(out.Table, "  %10s   %-26s %s\n", "n", "Human coding", "Expert coding")
+		for _, n := range sizes {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			ops := wl.ops(n)
+			human := "skipped"
+			if n <= wl.maxHuman {
+				steps, elapsed := perOp(tiers[1].new, n, ops)
+				human = fmt.Sprintf("%9.2f links %8v", steps, elapsed)
+			}
+			steps, elapsed := perOp(tiers[2].new, n, ops)
+			expert := fmt.Sprintf("%5.2f links %8v", steps, elapsed)
+			fmt.Fprintf(out.Table, "  %10d   %-26s %s\n", n, human, expert)
+			rep.AddEdgeCase(fmt.Sprintf("%s, n = %d", wl.desc, n), fmt.Sprintf("Human coding: %s; Expert coding: %s",
+				strings.Join(strings.Fields(human), " "), strings.Join(strings.Fields(expert), " ")))
+		}
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		n    int
+		ops  []op
+		want []bool
+		desc string
+	}{
+		{1, []op{{query: true}}, []bool{true}, "one element, connected to itself"},
+		{3, []op{{query: true, a: 0, b: 2}}, []bool{false}, "no unions"},
+		{3, []op{{a: 1, b: 1}, {query: true, a: 0, b: 1}}, []bool{false}, "union with itself"},
+		{3, []op{{a: 0, b: 1}, {a: 1, b: 2}, {query: true, a: 2, b: 0}}, []bool{true}, "connected through a third element"},
+		{4, []op{{a: 0, b: 1}, {a: 1, b: 0}, {a: 0, b: 1}, {query: true, a: 1, b: 2}, {query: true, a: 1, b: 0}}, []bool{false, true}, "the same union repeated"},
+		{4, []op{{a: 0, b: 1}, {a: 2, b: 3}, {query: true, a: 0, b: 3}, {a: 1, b: 2}, {query: true, a: 0, b: 3}}, []bool{false, true}, "two components merged"},
+	}
+	for _, tc := range edgeCases {
+		status := "✅"
+		var wrong []string
+		for _, t := range tiers {
+			if got := replay(t.new(tc.n), tc.ops); bench.DiffSlices(got, tc.want) != nil {
+				status = "❌"
+				wrong = append(wrong, fmt.Sprintf("%s answered %v", t.name, got))
+			}
+		}
+		fmt.Fprintf(w, "%s %s: %v\n", status, tc.desc, tc.want)
+		for _, msg := range wrong {
+			fmt.Fprintln(w, "   "+msg)
+		}
+		rep.AddEdgeCase(tc.desc, fmt.Sprintf("%s %v", status, tc.want))
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package unionfind
+
+// connectivity tracks which of n elements, 0 to n-1, are connected.
+// Union connects two elements; Connected reports whether a path of
+// unions joins them.
+type connectivity interface {
+	Union(a, b int)
+	Connected(a, b int) bool
+	// Steps returns how many links the structure has followed so far,
+	// a count of its work that doesn't depend on the machine.
+	Steps() int
+}
+
+// VIBE CODING: Store the edges, search on every query
+type vibeUF struct {
+	adj   [][]int
+	steps int
+}
+
+func newVibeUF(n int) *vibeUF {
+	return &vibeUF{adj: make([][]int, n)}
+}
+
+func (u *vibeUF) Union(a, b int) {
+	u.adj[a] = append(u.adj[a], b)
+	u.adj[b] = append(u.adj[b], a)
+}
+
+func (u *vibeUF) Connected(a, b int) bool {
+	/*
+	   Keep every union as an edge of a graph, and answer each query
+	   with a breadth-first search from a, looking for b.
+
+	   Unions are free and the answer is plainly right. But a query
+	   walks the whole component, O(n + m), and starts by allocating a
+	   fresh visited slice for all n elements. Nothing learned by one
+	   search helps the next.
+	*/
+	if a == b {
+		return true
+	}
+	visited := make([]bool, len(u.adj))
+	visited[a] = true
+	queue := []int{a}
+	for len(queue) > 0 {
+		x := queue[0]
+		queue = queue[1:]
+		for _, y := range u.adj[x] {
+			u.steps++
+			if y == b {
+				return true
+			}
+			if !visited[y] {
+				visited[y] = true
+				queue = append(queue, y)
+			}
+		}
+	}
+	return false
+}
+
+func (u *vibeUF) Steps() int { return u.steps }
+
+// HUMAN CODING: Quick-union
+type humanUF struct {
+	parent []int
+	steps  int
+}
+
+func newHumanUF(n int) *humanUF {
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	return &humanUF{parent: parent}
+}
+
+// find returns the root of x's tree.
+func (u *humanUF) find(x int) int {
+	for u.parent[x] != x {
+		x = u.parent[x]
+		u.steps++
+	}
+	return x
+}
+
+func (u *humanUF) Union(a, b int) {
+	/*
+	   Each element points to a parent, and each component is a tree:
+	   two elements are connected when they have the same root. A union
+	   hangs one root under the other.
+
+	   Far better than searching, but nothing keeps the trees short. A
+	   union always hangs a's tree under b's, however big a's is, so the
+	   wrong order of unions builds a chain, and a find walks all of it:
+	   O(n) per operation in the worst case.
+	*/
+	u.parent[u.find(a)] = u.find(b)
+}
+
+func (u *humanUF) Connected(a, b int) bool { return u.find(a) == u.find(b) }
+func (u *humanUF) Steps() int              { return u.steps }
+
+// EXPERT CODING: Union by rank with path compression
+type expertUF struct {
+	parent []int32
+	rank   []uint8
+	steps  int
+}
+
+func newExpertUF(n int) *expertUF {
+	parent := make([]int32, n)
+	for i := range parent {
+		parent[i] = int32(i)
+	}
+	return &expertUF{parent: parent, rank: make([]uint8, n)}
+}
+
+// find returns the root of x's tree, halving the path on the way: each
+// element visited is pointed at its grandparent.
+func (u *expertUF) find(x int32) int32 {
+	for u.parent[x] != x {
+		u.parent[x] = u.parent[u.parent[x]]
+		x = u.parent[x]
+		u.steps++
+	}
+	return x
+}
+
+func (u *expertUF) Union(a, b int) {
+	/*
+	   Two fixes to quick-union, each simple. Union by rank hangs the
+	   shorter tree under the taller, so a tree of height h holds at
+	   least 2^h elements and no path is longer than log n. Path
+	   halving makes every find point the elements it passes at their
+	   grandparents, so paths flatten as they are used.
+
+	   Together they bring the amortized cost per operation to
+	   O(α(n)), where α, the inverse Ackermann function, is at most 4
+	   for any n that fits in memory: constant, in practice. int32
+	   parents and uint8 ranks keep the arrays small, as a rank can't
+	   pass log2(n).
+	*/
+	ra, rb := u.find(int32(a)), u.find(int32(b))
+	switch {
+	case ra == rb:
+	case u.rank[ra] < u.rank[rb]:
+		u.parent[ra] = rb
+	case u.rank[ra] > u.rank[rb]:
+		u.parent[rb] = ra
+	default:
+		u.parent[rb] = ra
+		u.rank[ra]++
+	}
+}
+
+func (u *expertUF) Connected(a, b int) bool { return u.find(int32(a)) == u.find(int32(b)) }
+func (u *expertUF) Steps() int              { return u.steps }
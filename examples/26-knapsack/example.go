@@ -0,0 +1,254 @@
+// Package knapsack compares three ways to solve the 0/1 knapsack problem.
+package knapsack
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// Trying every subset doubles in time with every item, and takes up to
+// seconds per run above this many.
+const maxVibeN = 20
+
+// The memo table is skipped above this many cells, 80 MB of ints.
+const maxMemoCells = 10_000_000
+
+// maxWeight is the heaviest an item can be.
+const maxWeight = 100
+
+// problem is a knapsack to fill.
+type problem struct {
+	items    []item
+	capacity int
+}
+
+// makeProblem returns n items of random weight, from 1 to maxWeight,
+// and value, from 1 to 1000, and a capacity of half their total
+// weight, so about half of them fit.
+func makeProblem(seed input.Seed, n int) problem {
+	rng := seed.Rand("items", n)
+	items := make([]item, n)
+	total := 0
+	for i := range items {
+		items[i] = item{weight: 1 + rng.IntN(maxWeight), value: 1 + rng.IntN(1000)}
+		total += items[i].weight
+	}
+	return problem{items: items, capacity: total / 2}
+}
+
+// memoCells is the size of the memo table for p.
+func memoCells(p problem) int {
+	return len(p.items) * (p.capacity + 1)
+}
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	solve            func(items []item, capacity int) int
+}{
+	{"Vibe coding", "every subset, O(n·2^n)", vibeKnapsack},
+	{"Human coding", "memoized recursion, O(n·W)", humanKnapsack},
+	{"Expert coding", "rolling row, O(n·W), O(W) space", expertKnapsack},
+}
+
+// solversFor returns the implementations to compare on p, leaving out
+// those that would take seconds or too much memory.
+func solversFor(p problem) []bench.Impl[problem, int] {
+	impls := []bench.Impl[problem, int]{}
+	for _, t := range tiers {
+		switch {
+		case t.name == "Vibe coding" && len(p.items) > maxVibeN,
+			t.name == "Human coding" && memoCells(p) > maxMemoCells:
+			continue
+		}
+		impls = append(impls, bench.Impl[problem, int]{
+			Name: t.name, Complexity: t.complexity,
+			Func: func(p problem) int { return t.solve(p.items, p.capacity) },
+		})
+	}
+	return impls
+}
+
+// impls returns the implementations timed on n random items.
+func impls(n int) []bench.Implementation {
+	p := makeProblem(input.DefaultSeed, n)
+	var list []bench.Implementation
+	for _, s := range solversFor(p) {
+		list = append(list, s.Implementation(p))
+	}
+	return list
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "try every subset", Complexity: "O(n·2^n)", Notes: []report.Note{
+			report.Strength("Obviously right: it looks at every possible answer"),
+			report.Pitfall("Each item doubles the time: 20 items take milliseconds, 30 half a minute"),
+			report.Pitfall("Ignores that subsets share almost all their work"),
+		}},
+		{Label: "Human coding", Approach: "top-down recursion with an n × W memo table", Complexity: "O(n·W) time and space", Notes: []report.Note{
+			report.Strength("Reads like the recurrence: skip the item, or take it"),
+			report.Strength("Polynomial in n and W: hundreds of items, where subsets stop at 20"),
+			report.Pitfall("Keeps every state at once: memory runs out before time does"),
+			report.Pitfall("A call per state, n calls deep"),
+		}},
+		{Label: "Expert coding", Approach: "bottom-up over one row, updated from the top down", Complexity: "O(n·W) time, O(W) space", Notes: []report.Note{
+			report.Strength("Memory for one row, however many items"),
+			report.Strength("A tight loop with no calls, skipping capacities not yet reachable"),
+			report.Pitfall("Still O(n·W): a capacity in the billions is out of reach"),
+			report.Pitfall("One row gives the best value, not which items make it"),
+		}},
+	},
+	Takeaway: "Exhaustive search is exponential in the items; dynamic programming " +
+		"is polynomial in the items times the capacity. Each row of the table " +
+		"needs only the one before, so keep one row: same time, a fraction of " +
+		"the memory.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "26-knapsack",
+		Title:       "0/1 Knapsack",
+		Description: "Solve the 0/1 knapsack problem by trying every subset, memoized recursion and a rolling DP row, and see where each stops being feasible.",
+		Category:    "dynamic programming",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    100,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("26-knapsack", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{10, 20, 100, 1_000, 3_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of items, e.g. 15,25,1e3")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("0/1 Knapsack", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: 0/1 Knapsack")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	for _, n := range sizes {
+		p := makeProblem(*seed, n)
+		fmt.Fprintf(out.Table, "\n%d items, capacity %d (seed %d):\n", n, p.capacity, *seed)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+		fmt.Fprintf(w, "  2^%d subsets to try, or %d × %d = %d table cells (%s as a memo table)\n",
+			n, n, p.capacity+1, memoCells(p), bench.FormatBytes(uint64(memoCells(p))*8))
+
+		want := expertKnapsack(p.items, p.capacity)
+		results, err := bench.CompareImpls(ctx, opts, p, want, bench.Equal[int], solversFor(p)...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "✔ All implementations agree: the best value is %d\n", want)
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d items, capacity %d", n, p.capacity), results)
+
+		if n > maxVibeN {
+			note := fmt.Sprintf("Vibe coding skipped: 2^%d subsets is impractical above n=%d", n, maxVibeN)
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		}
+		if memoCells(p) > maxMemoCells {
+			note := fmt.Sprintf("Human coding skipped: a memo table of %d cells needs %s, over the %d-cell limit",
+				memoCells(p), bench.FormatBytes(uint64(memoCells(p))*8), maxMemoCells)
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		items    []item
+		capacity int
+		want     int
+		desc     string
+	}{
+		{nil, 10, 0, "no items"},
+		{[]item{{5, 10}, {3, 7}}, 0, 0, "capacity 0"},
+		{[]item{{11, 100}}, 10, 0, "the only item is too heavy"},
+		{[]item{{2, 3}, {3, 4}, {4, 5}}, 100, 12, "everything fits"},
+		{[]item{{4, 40}, {6, 50}, {5, 45}}, 10, 90, "an exact fit"},
+		{[]item{{6, 30}, {5, 20}, {5, 20}}, 10, 40, "best value per weight first is wrong"},
+		{[]item{{3, 10}, {3, 10}, {3, 10}}, 7, 20, "identical items, each taken once"},
+	}
+	for _, tc := range edgeCases {
+		status := "✅"
+		var wrong []string
+		for _, t := range tiers {
+			if got := t.solve(tc.items, tc.capacity); got != tc.want {
+				status = "❌"
+				wrong = append(wrong, fmt.Sprintf("%s found %d", t.name, got))
+			}
+		}
+		fmt.Fprintf(w, "%s %s: best value %d\n", status, tc.desc, tc.want)
+		for _, msg := range wrong {
+			fmt.Fprintln(w, "   "+msg)
+		}
+		rep.AddEdgeCase(tc.desc, fmt.Sprintf("%s best value %d", status, tc.want))
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
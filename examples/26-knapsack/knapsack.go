@@ -0,0 +1,101 @@
+package knapsack
+
+// item is something that can go in the knapsack.
+type item struct {
+	weight, value int
+}
+
+// VIBE CODING: Try every subset
+func vibeKnapsack(items []item, capacity int) int {
+	/*
+	   Return the largest total value of items whose total weight is at
+	   most capacity, each item taken at most once.
+
+	   Try every subset, one bit per item: if it fits, keep the best
+	   value. Certainly right - it looks at every possible answer. But
+	   n items have 2^n subsets, each summed item by item: O(n·2^n).
+	   Every item added doubles the time, so 20 items take milliseconds,
+	   30 items half a minute, and 64 don't fit in the mask.
+	*/
+	best := 0
+	for mask := uint64(0); mask < 1<<len(items); mask++ {
+		weight, value := 0, 0
+		for i, it := range items {
+			if mask&(1<<i) != 0 {
+				weight += it.weight
+				value += it.value
+			}
+		}
+		if weight <= capacity && value > best {
+			best = value
+		}
+	}
+	return best
+}
+
+// HUMAN CODING: Recursion with a memo table
+func humanKnapsack(items []item, capacity int) int {
+	/*
+	   The best value from items i onwards with room left depends only
+	   on i and room: skip item i, or take it if it fits, and recurse.
+	   Remember each answer in an n × (capacity+1) table, and no state
+	   is ever solved twice: O(n·W) time for capacity W.
+
+	   It reads like the recurrence. But the table holds every state at
+	   once, O(n·W) memory - a thousand items and a capacity of 25,000
+	   take 200 MB - and the recursion is n calls deep.
+	*/
+	memo := make([][]int, len(items))
+	for i := range memo {
+		memo[i] = make([]int, capacity+1)
+		for room := range memo[i] {
+			memo[i][room] = -1
+		}
+	}
+
+	var best func(i, room int) int
+	best = func(i, room int) int {
+		if i == len(items) {
+			return 0
+		}
+		if memo[i][room] >= 0 {
+			return memo[i][room]
+		}
+		v := best(i+1, room)
+		if it := items[i]; it.weight <= room {
+			v = max(v, it.value+best(i+1, room-it.weight))
+		}
+		memo[i][room] = v
+		return v
+	}
+	return best(0, capacity)
+}
+
+// EXPERT CODING: Bottom-up over one rolling row
+func expertKnapsack(items []item, capacity int) int {
+	/*
+	   Fill the same table bottom-up, one item at a time. Each row
+	   depends only on the row before, so keep a single row, best[c] for
+	   every capacity c, and update it in place from the top capacity
+	   down: best[c-w] still holds the previous row's value when it is
+	   read, so no item is taken twice.
+
+	   The same O(n·W) time, in a tight loop with no calls, but O(W)
+	   memory. Capacities above the weight of all the items seen so
+	   far are skipped, as none of them can change yet; best[c] is the
+	   best value within weight c, so best[reach] is the answer.
+	*/
+	best := make([]int, capacity+1)
+	reach := 0 // the total weight of the items so far, at most capacity
+	for _, it := range items {
+		prev := reach
+		reach = min(reach+it.weight, capacity)
+		for c := prev + 1; c <= reach; c++ {
+			best[c] = best[prev] // room for every earlier item
+		}
+		for c := reach; c >= it.weight; c-- {
+			best[c] = max(best[c], best[c-it.weight]+it.value)
+		}
+	}
+	return best[reach]
+}
@@ -0,0 +1,344 @@
+// Package lcs compares three ways to find a longest common subsequence
+// of two strings, and the subsequence itself, not just its length.
+package lcs
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// Plain recursion is skipped when it would make more calls than this,
+// a few hundred milliseconds of work.
+const maxVibeCalls = 100_000_000
+
+// The memo table is skipped above this many cells, 80 MB of ints.
+const maxMemoCells = 10_000_000
+
+// defaultAlphabet is the letters the random strings are made of: DNA
+// bases, as in the sequence comparisons LCS is best known for.
+const defaultAlphabet = "ACGT"
+
+// pair is two strings to compare.
+type pair struct {
+	a, b string
+}
+
+// makePair returns two random strings of n letters from alphabet.
+func makePair(seed input.Seed, n int, alphabet string) pair {
+	rng := seed.Rand("strings", n)
+	letters := func() string {
+		s := make([]byte, n)
+		for i := range s {
+			s[i] = alphabet[rng.IntN(len(alphabet))]
+		}
+		return string(s)
+	}
+	a := letters()
+	return pair{a: a, b: letters()}
+}
+
+// memoCells is the size of the memo table for p.
+func memoCells(p pair) int {
+	return (len(p.a) + 1) * (len(p.b) + 1)
+}
+
+// recursionCalls returns how many calls plain recursion makes on p,
+// counted the way memoization would solve it: once per pair of
+// suffixes, in one row. The count doubles with each mismatch, so it is
+// kept as a float64, and is +Inf past 1.8e308.
+func recursionCalls(p pair) float64 {
+	row := make([]float64, len(p.b)+1) // calls for a[i+1:] and b[j:]
+	for j := range row {
+		row[j] = 1
+	}
+	for i := len(p.a) - 1; i >= 0; i-- {
+		diag := row[len(p.b)] // calls for a[i+1:] and b[j+1:]
+		for j := len(p.b) - 1; j >= 0; j-- {
+			down := row[j]
+			if p.a[i] == p.b[j] {
+				row[j] = 1 + diag
+			} else {
+				row[j] = 1 + down + row[j+1]
+			}
+			diag = down
+		}
+	}
+	return row[0]
+}
+
+// showCalls formats a count from recursionCalls.
+func showCalls(calls float64) string {
+	if math.IsInf(calls, 1) {
+		return "over 1.8e308"
+	}
+	return fmt.Sprintf("%.3g", calls)
+}
+
+// isSubsequence reports whether the letters of s appear in t, in order.
+func isSubsequence(s, t string) bool {
+	for i := 0; i < len(s); i++ {
+		j := strings.IndexByte(t, s[i])
+		if j < 0 {
+			return false
+		}
+		t = t[j+1:]
+	}
+	return true
+}
+
+// checkAnswer compares both parts of an answer for p with want: the
+// length, and the subsequence, which must also have that length and be
+// a subsequence of both strings.
+func checkAnswer(p pair, got, want answer) error {
+	switch {
+	case got.length != want.length:
+		return fmt.Errorf("length %d, want %d", got.length, want.length)
+	case len(got.subsequence) != got.length:
+		return fmt.Errorf("length %d, but a subsequence of %d letters", got.length, len(got.subsequence))
+	case !isSubsequence(got.subsequence, p.a):
+		return fmt.Errorf("%s is not a subsequence of the first string", show(got.subsequence))
+	case !isSubsequence(got.subsequence, p.b):
+		return fmt.Errorf("%s is not a subsequence of the second string", show(got.subsequence))
+	}
+	for i := range got.subsequence {
+		if got.subsequence[i] != want.subsequence[i] {
+			return fmt.Errorf("another subsequence of the same length: letter %d is %q, want %q",
+				i, got.subsequence[i], want.subsequence[i])
+		}
+	}
+	return nil
+}
+
+// show quotes s, or its start if it is long.
+func show(s string) string {
+	const maxShown = 40
+	if len(s) > maxShown {
+		return fmt.Sprintf("%q...", s[:maxShown])
+	}
+	return fmt.Sprintf("%q", s)
+}
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	solve            func(a, b string) answer
+}{
+	{"Vibe coding", "plain recursion, O(2^(n+m))", vibeLCS},
+	{"Human coding", "memoized recursion, O(n·m)", humanLCS},
+	{"Expert coding", "Hirschberg, O(n·m), O(n+m) space", expertLCS},
+}
+
+// solversFor returns the implementations to compare on p, leaving out
+// those that would take seconds or too much memory.
+func solversFor(p pair) []bench.Impl[pair, answer] {
+	impls := []bench.Impl[pair, answer]{}
+	for _, t := range tiers {
+		switch {
+		case t.name == "Vibe coding" && recursionCalls(p) > maxVibeCalls,
+			t.name == "Human coding" && memoCells(p) > maxMemoCells:
+			continue
+		}
+		impls = append(impls, bench.Impl[pair, answer]{
+			Name: t.name, Complexity: t.complexity,
+			Func: func(p pair) answer { return t.solve(p.a, p.b) },
+		})
+	}
+	return impls
+}
+
+// impls returns the implementations timed on two random strings of n
+// letters.
+func impls(n int) []bench.Implementation {
+	p := makePair(input.DefaultSeed, n, defaultAlphabet)
+	var list []bench.Implementation
+	for _, s := range solversFor(p) {
+		list = append(list, s.Implementation(p))
+	}
+	return list
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "plain recursion on the two suffixes", Complexity: "O(2^(n+m))", Notes: []report.Note{
+			report.Strength("The recurrence as written, and it returns the subsequence itself"),
+			report.Pitfall("Solves the same suffixes over and over: each letter doubles the time"),
+			report.Pitfall("Builds a new string on every call"),
+		}},
+		{Label: "Human coding", Approach: "top-down recursion with an (n+1) × (m+1) memo table, then a walk through it", Complexity: "O(n·m) time and space", Notes: []report.Note{
+			report.Strength("Each pair of suffixes solved once"),
+			report.Strength("The table doubles as the map for rebuilding the subsequence"),
+			report.Pitfall("Keeps every length at once: 10,000 letters each need 800 MB"),
+			report.Pitfall("A call per cell, up to n+m calls deep"),
+		}},
+		{Label: "Expert coding", Approach: "Hirschberg's divide and conquer over two rows", Complexity: "O(n·m) time, O(n+m) space", Notes: []report.Note{
+			report.Strength("Linear memory, and still the subsequence, not just its length"),
+			report.Strength("Tight loops over two reused rows, no table to allocate"),
+			report.Pitfall("About twice the cell updates of filling the table once"),
+			report.Pitfall("Still quadratic time: two genomes are out of reach"),
+		}},
+	},
+	Takeaway: "Memoization turns an exponential recursion into a table, and a " +
+		"table whose rows need only the row before can shrink to one row. " +
+		"Divide and conquer gets back what the shrinking lost - here, the " +
+		"subsequence itself - for a constant factor in time.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "27-lcs",
+		Title:       "Longest Common Subsequence",
+		Description: "Find a longest common subsequence of two strings, and the subsequence itself, with plain recursion, a memo table and Hirschberg's linear-space algorithm.",
+		Category:    "dynamic programming",
+		Difficulty:  examples.Advanced,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    1_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("27-lcs", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{10, 20, 100, 1_000, 3_000, 10_000}
+	fs.Var(&sizes, "n", "comma-separated lengths of the two strings, e.g. 16,1e3")
+	alphabet := fs.String("alphabet", defaultAlphabet, "letters the random strings are made of, e.g. 01 or abcdefghijklmnopqrstuvwxyz")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *alphabet == "" {
+		return fmt.Errorf("-alphabet: needs at least one letter")
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Longest Common Subsequence", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Longest Common Subsequence")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	for _, n := range sizes {
+		p := makePair(*seed, n, *alphabet)
+		fmt.Fprintf(out.Table, "\nTwo strings of %d letters from %q (seed %d):\n", n, *alphabet, *seed)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+		calls := recursionCalls(p)
+		fmt.Fprintf(w, "  %s calls of plain recursion, for %d table cells: %s as a memo table, %s as Hirschberg's two rows\n",
+			showCalls(calls), memoCells(p), bench.FormatBytes(uint64(memoCells(p))*8), bench.FormatBytes(uint64(len(p.b)+1)*2*8))
+
+		want := expertLCS(p.a, p.b)
+		equal := func(got, want answer) error { return checkAnswer(p, got, want) }
+		if err := equal(want, want); err != nil {
+			return fmt.Errorf("reference: %w", err)
+		}
+		results, err := bench.CompareImpls(ctx, opts, p, want, equal, solversFor(p)...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "✔ All implementations agree on a subsequence of %d letters: %s\n", want.length, show(want.subsequence))
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d letters", n), results)
+
+		if calls > maxVibeCalls {
+			note := fmt.Sprintf("Vibe coding skipped: plain recursion needs %s calls, more than the %d allowed", showCalls(calls), maxVibeCalls)
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		}
+		if memoCells(p) > maxMemoCells {
+			note := fmt.Sprintf("Human coding skipped: a memo table of %d cells needs %s, over the %d-cell limit",
+				memoCells(p), bench.FormatBytes(uint64(memoCells(p))*8), maxMemoCells)
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		a, b string
+		want string
+		desc string
+	}{
+		{"", "", "", "two empty strings"},
+		{"ACGT", "", "", "one empty string"},
+		{"AAAA", "CCCC", "", "no letter in common"},
+		{"GATTACA", "GATTACA", "GATTACA", "identical strings"},
+		{"ACE", "ABCDE", "ACE", "one string inside the other"},
+		{"AAAA", "AA", "AA", "repeated letters, each matched once"},
+		{"ABCD", "DCBA", "D", "reversed: any single letter is longest"},
+		{"ABCBDAB", "BDCABA", "BDAB", "the textbook pair, with three answers of 4 letters"},
+	}
+	for _, tc := range edgeCases {
+		p := pair{a: tc.a, b: tc.b}
+		want := answer{length: len(tc.want), subsequence: tc.want}
+		status := "✅"
+		var wrong []string
+		for _, t := range tiers {
+			if err := checkAnswer(p, t.solve(tc.a, tc.b), want); err != nil {
+				status = "❌"
+				wrong = append(wrong, fmt.Sprintf("%s: %v", t.name, err))
+			}
+		}
+		fmt.Fprintf(w, "%s %s: %q\n", status, tc.desc, tc.want)
+		for _, msg := range wrong {
+			fmt.Fprintln(w, "   "+msg)
+		}
+		rep.AddEdgeCase(tc.desc, fmt.Sprintf("%s %q", status, tc.want))
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package lcs
+
+import "strings"
+
+// answer is a longest common subsequence of two strings, and its length
+// as the implementation worked it out.
+//
+// Where several subsequences are longest, every implementation returns
+// the one found by walking both strings from the front, taking each
+// matching letter and otherwise skipping a letter of a if that loses
+// nothing, else a letter of b. So they agree on the string, not just
+// its length.
+type answer struct {
+	length      int
+	subsequence string
+}
+
+// VIBE CODING: Plain recursion
+func vibeLCS(a, b string) answer {
+	/*
+	   Find a longest string that is a subsequence of both a and b: its
+	   letters appear in both, in order, though not necessarily side by
+	   side.
+
+	   The recurrence, as written on the board: if the first letters
+	   match, keep the letter and recurse on the rest of both; if not,
+	   try dropping the first letter of each and keep the longer answer.
+	   Correct, and the recursion reconstructs the subsequence for free.
+	   But the same suffixes are solved again and again, and every
+	   mismatch doubles the calls: up to 2^(n+m) of them, each building
+	   a new string.
+	*/
+	var lcs func(a, b string) string
+	lcs = func(a, b string) string {
+		if a == "" || b == "" {
+			return ""
+		}
+		if a[0] == b[0] {
+			return a[:1] + lcs(a[1:], b[1:])
+		}
+		x, y := lcs(a[1:], b), lcs(a, b[1:])
+		if len(x) >= len(y) {
+			return x
+		}
+		return y
+	}
+	s := lcs(a, b)
+	return answer{length: len(s), subsequence: s}
+}
+
+// HUMAN CODING: Recursion with a memo table, then a walk through it
+func humanLCS(a, b string) answer {
+	/*
+	   The answer for the suffixes a[i:] and b[j:] depends only on i and
+	   j, so there are only (n+1) × (m+1) different calls. Remember each
+	   length in a table, and no call is made twice: O(n·m) time. Then
+	   rebuild the subsequence by walking the table from the front,
+	   going wherever the length says the answer lies.
+
+	   The standard textbook solution. But the table holds every length
+	   at once, O(n·m) memory - two strings of 10,000 letters take
+	   800 MB - and the recursion is up to n+m calls deep.
+	*/
+	memo := make([][]int, len(a)+1)
+	for i := range memo {
+		memo[i] = make([]int, len(b)+1)
+		for j := range memo[i] {
+			memo[i][j] = -1
+		}
+	}
+
+	var length func(i, j int) int
+	length = func(i, j int) int {
+		if i == len(a) || j == len(b) {
+			return 0
+		}
+		if memo[i][j] >= 0 {
+			return memo[i][j]
+		}
+		var v int
+		if a[i] == b[j] {
+			v = 1 + length(i+1, j+1)
+		} else {
+			v = max(length(i+1, j), length(i, j+1))
+		}
+		memo[i][j] = v
+		return v
+	}
+
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			sb.WriteByte(a[i])
+			i, j = i+1, j+1
+		case length(i+1, j) >= length(i, j+1):
+			i++
+		default:
+			j++
+		}
+	}
+	return answer{length: length(0, 0), subsequence: sb.String()}
+}
+
+// EXPERT CODING: Hirschberg's algorithm, in linear space
+func expertLCS(a, b string) answer {
+	/*
+	   A row of the table needs only the row before, so one row gives
+	   the length in O(m) memory - but then there is nothing left to
+	   walk back through. Hirschberg's trick recovers the subsequence
+	   anyway: split a in half, and compute one row for the first half
+	   of a against every prefix of b, and one for the second half
+	   against every suffix. The best split point k of b is where the
+	   two add up to most; the answer is the answer for the first
+	   halves, followed by the answer for the second. Recurse.
+
+	   Each level of the recursion does half the work of the one above,
+	   so the total is about twice that of filling one table, still
+	   O(n·m), in O(n + m) memory: two rows, reused all the way down.
+	   Taking the first best split gives the same subsequence as the
+	   other two.
+	*/
+	fwd := make([]int, len(b)+1)
+	bwd := make([]int, len(b)+1)
+	out := make([]byte, 0, min(len(a), len(b)))
+
+	var solve func(a, b string)
+	solve = func(a, b string) {
+		switch {
+		case a == "" || b == "":
+			return
+		case len(a) == 1:
+			if strings.IndexByte(b, a[0]) >= 0 {
+				out = append(out, a[0])
+			}
+			return
+		}
+		mid := len(a) / 2
+		prefixRow(fwd[:len(b)+1], a[:mid], b)
+		suffixRow(bwd[:len(b)+1], a[mid:], b)
+		k := 0
+		for j := 1; j <= len(b); j++ {
+			if fwd[j]+bwd[j] > fwd[k]+bwd[k] {
+				k = j
+			}
+		}
+		solve(a[:mid], b[:k])
+		solve(a[mid:], b[k:])
+	}
+	solve(a, b)
+	return answer{length: len(out), subsequence: string(out)}
+}
+
+// prefixRow sets row[j] to the length of a longest common subsequence
+// of a and b[:j], for every j.
+func prefixRow(row []int, a, b string) {
+	clear(row)
+	for i := 0; i < len(a); i++ {
+		diag := 0 // row[j-1] before this pass
+		for j := 1; j <= len(b); j++ {
+			up := row[j]
+			if a[i] == b[j-1] {
+				row[j] = diag + 1
+			} else {
+				row[j] = max(up, row[j-1])
+			}
+			diag = up
+		}
+	}
+}
+
+// suffixRow sets row[j] to the length of a longest common subsequence
+// of a and b[j:], for every j.
+func suffixRow(row []int, a, b string) {
+	clear(row)
+	for i := len(a) - 1; i >= 0; i-- {
+		diag := 0 // row[j+1] before this pass
+		for j := len(b) - 1; j >= 0; j-- {
+			down := row[j]
+			if a[i] == b[j] {
+				row[j] = diag + 1
+			} else {
+				row[j] = max(down, row[j+1])
+			}
+			diag = down
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Package slicegrowth compares three ways to build a large slice, and
+// counts the reallocations and copies each one costs.
+package slicegrowth
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// defaultRequests is how many requests each timed run serves, one after
+// another, so that reuse across requests shows within a run.
+const defaultRequests = 32
+
+// serve handles requests requests of n readings each with request, and
+// returns the sum of their checksums.
+func serve(request func(r, n int) int64, requests, n int) int64 {
+	var sum int64
+	for r := range requests {
+		sum += request(r, n)
+	}
+	return sum
+}
+
+// checksum is what process returns for request r of n readings,
+// computed without a slice.
+func checksum(r, n int) int64 {
+	var sum int64
+	for i := range n {
+		sum += int64(i+1) * reading(r, i)
+	}
+	return sum
+}
+
+// copiedByGrowth replays appending n values to a nil slice, as
+// vibeRequest does, and returns how many bytes append copied moving the
+// slice to bigger arrays.
+func copiedByGrowth(n int) uint64 {
+	var s []int64
+	var copied uint64
+	for i := range n {
+		if len(s) == cap(s) {
+			copied += uint64(len(s)) * 8
+		}
+		s = append(s, int64(i))
+	}
+	return copied
+}
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	request          func(r, n int) int64
+}{
+	{"Vibe coding", "append to nil, O(log n) allocs", vibeRequest},
+	{"Human coding", "make with capacity, 1 alloc", humanRequest},
+	{"Expert coding", "sync.Pool buffers, ~0 allocs", expertRequest},
+}
+
+// servers returns the implementations to compare, each serving
+// requests requests.
+func servers(requests int) []bench.Impl[int, int64] {
+	impls := make([]bench.Impl[int, int64], len(tiers))
+	for i, t := range tiers {
+		impls[i] = bench.Impl[int, int64]{
+			Name: t.name, Complexity: t.complexity,
+			Func: func(n int) int64 { return serve(t.request, requests, n) },
+		}
+	}
+	return impls
+}
+
+// impls returns the implementations timed serving requests of n
+// readings.
+func impls(n int) []bench.Implementation {
+	var list []bench.Implementation
+	for _, s := range servers(defaultRequests) {
+		list = append(list, s.Implementation(n))
+	}
+	return list
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "append to a nil slice", Complexity: "O(log n) allocations per slice", Notes: []report.Note{
+			report.Strength("Simple, and amortized O(1) per append"),
+			report.Pitfall("Dozens of allocations per slice, each copying everything so far"),
+			report.Pitfall("The outgrown arrays are garbage: more collections"),
+		}},
+		{Label: "Human coding", Approach: "make([]T, 0, n), then append", Complexity: "1 allocation per slice", Notes: []report.Note{
+			report.Strength("One allocation of the right size, no copies"),
+			report.Strength("One extra argument to make: the fix for most slice growth"),
+			report.Pitfall("Still a fresh, zeroed slice per request, garbage straight after"),
+		}},
+		{Label: "Expert coding", Approach: "buffers reused through a sync.Pool", Complexity: "~0 allocations per slice", Notes: []report.Note{
+			report.Strength("Steady streams of requests allocate almost nothing"),
+			report.Strength("Fewer bytes to zero and fewer collections"),
+			report.Pitfall("A buffer used after it is put back is shared by two requests"),
+			report.Pitfall("Pool *[]T, not []T, and don't keep huge buffers"),
+			report.Tip("Measure first: make with capacity is usually enough"),
+		}},
+	},
+	Takeaway: "Append grows a slice by allocating and copying, over and over; " +
+		"telling make the capacity up front removes both. When the same " +
+		"buffers are needed again and again, a sync.Pool removes the " +
+		"allocation too - at the price of managing their lifetime by hand.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "28-slice-growth",
+		Title:       "Slice Growth",
+		Description: "Build large slices by appending to nil, preallocating with make and reusing buffers from a sync.Pool, and count the reallocations and bytes copied.",
+		Category:    "performance",
+		Difficulty:  examples.Beginner,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    10_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("28-slice-growth", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{1_000, 10_000, 100_000, 1_000_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of readings per request, e.g. 1e4,1e6")
+	requests := fs.Int("requests", defaultRequests, "requests served per timed run")
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *requests < 1 {
+		return fmt.Errorf("-requests: must be at least 1, got %d", *requests)
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Slice Growth", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Slice Growth")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	for _, n := range sizes {
+		fmt.Fprintf(out.Table, "\n%d requests of %d readings (%s each):\n", *requests, n, bench.FormatBytes(uint64(n)*8))
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		want := serve(checksum, *requests, n)
+		results, err := bench.CompareImpls(ctx, opts, n, want, bench.Equal[int64], servers(*requests)...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "✔ All implementations give the same checksums")
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d readings per request", n), results)
+
+		fmt.Fprintf(out.Table, "  %-15s %12s %14s %12s\n", "Per request", "allocations", "allocated", "GCs per run")
+		for _, r := range results {
+			fmt.Fprintf(out.Table, "  %-15s %12.2f %14s %12.2f\n", r.Name,
+				float64(r.Allocs)/float64(*requests), bench.FormatBytes(r.Bytes/uint64(*requests)), r.GCsPerRun())
+		}
+		copied := copiedByGrowth(n)
+		note := fmt.Sprintf("Vibe coding copies %s per request as its slice grows, %.1fx the %s it ends up holding",
+			bench.FormatBytes(copied), float64(copied)/float64(max(n*8, 1)), bench.FormatBytes(uint64(n)*8))
+		fmt.Fprintln(w, "  📦 "+note)
+		section.Notes = append(section.Notes, note)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		sizes []int // readings in each request, served in order
+		desc  string
+	}{
+		{[]int{0}, "a request with no readings"},
+		{[]int{1}, "a single reading"},
+		{[]int{1_000, 10}, "a small request after a large one: the reused buffer is cut to length first"},
+		{[]int{10, 1_000}, "a large request after a small one: a reused buffer too small is replaced"},
+		{[]int{maxPooledCap + 1, 5}, "a request too large to pool, then a small one"},
+	}
+	for _, tc := range edgeCases {
+		var want int64
+		for r, n := range tc.sizes {
+			want += checksum(r, n)
+		}
+		status := "✅"
+		var wrong []string
+		for _, t := range tiers {
+			var got int64
+			for r, n := range tc.sizes {
+				got += t.request(r, n)
+			}
+			if got != want {
+				status = "❌"
+				wrong = append(wrong, fmt.Sprintf("%s gave checksum %d", t.name, got))
+			}
+		}
+		fmt.Fprintf(w, "%s %s: checksum %d\n", status, tc.desc, want)
+		for _, msg := range wrong {
+			fmt.Fprintln(w, "   "+msg)
+		}
+		rep.AddEdgeCase(tc.desc, fmt.Sprintf("%s checksum %d", status, want))
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package slicegrowth
+
+import "sync"
+
+// reading returns the i-th value of request r: some cheap, made-up data.
+func reading(r, i int) int64 {
+	return int64((i*7919 + r*104729) % 65536)
+}
+
+// process stands for whatever needs a request's readings all at once,
+// such as a batch insert or a sort. It returns a checksum, so the
+// implementations can be compared.
+func process(s []int64) int64 {
+	var sum int64
+	for i, v := range s {
+		sum += int64(i+1) * v
+	}
+	return sum
+}
+
+// VIBE CODING: Append to a nil slice
+func vibeRequest(r, n int) int64 {
+	/*
+	   Collect the n readings of request r into a slice, and process
+	   them.
+
+	   Start from nil and append. Whenever the slice is full, append
+	   allocates a bigger array - about twice the size while small, a
+	   quarter more once large - and copies everything over. Amortized
+	   O(1) per append, but n readings take dozens of allocations, and
+	   the copies add up to more bytes than the final slice holds. All
+	   the outgrown arrays are garbage for the collector.
+	*/
+	var s []int64
+	for i := range n {
+		s = append(s, reading(r, i))
+	}
+	return process(s)
+}
+
+// HUMAN CODING: Make the slice with its final capacity
+func humanRequest(r, n int) int64 {
+	/*
+	   The request knows it will hold n readings, so say so: one
+	   allocation of exactly the right size, and append never has to
+	   grow it. No copies, no outgrown arrays.
+
+	   The habit that fixes most slice growth. But every request still
+	   allocates n·8 fresh bytes, which make zeroes before they are
+	   written, and which become garbage as soon as the request is done.
+	*/
+	s := make([]int64, 0, n)
+	for i := range n {
+		s = append(s, reading(r, i))
+	}
+	return process(s)
+}
+
+// EXPERT CODING: Reuse buffers with a sync.Pool
+
+// maxPooledCap is the largest buffer put back in bufPool, in elements:
+// one huge request shouldn't pin its buffer for good.
+const maxPooledCap = 1 << 21
+
+// bufPool holds buffers finished with, as *[]int64: a pointer, so that
+// putting one back doesn't allocate.
+var bufPool = sync.Pool{New: func() any { return new([]int64) }}
+
+func expertRequest(r, n int) int64 {
+	/*
+	   Requests come one after another, each needing a buffer for a
+	   moment. Take one from a sync.Pool, cut it to length 0, and grow
+	   it only if it is too small; put it back when done. In a steady
+	   stream of requests almost none allocate, nothing is zeroed twice,
+	   and the collector has little to do.
+
+	   The pool stores pointers to slices, since a slice header stored
+	   in an interface allocates. Buffers over maxPooledCap aren't put
+	   back, or one huge request would keep its memory forever. And the
+	   buffer must not be used after it goes back: process has finished
+	   with it by then. The pool may drop buffers at any collection, so
+	   this saves allocations; it doesn't promise to.
+	*/
+	buf := bufPool.Get().(*[]int64)
+	s := (*buf)[:0]
+	if cap(s) < n {
+		s = make([]int64, 0, n)
+	}
+	for i := range n {
+		s = append(s, reading(r, i))
+	}
+	sum := process(s)
+	if cap(s) <= maxPooledCap {
+		*buf = s
+		bufPool.Put(buf)
+	}
+	return sum
+}
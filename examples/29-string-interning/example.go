@@ -0,0 +1,325 @@
+// Package interning compares three ways to key a map with strings cut
+// from a large buffer, and measures the memory each map keeps alive.
+package interning
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// The log's paths are /api/v1/<resource>/<id>, for maxID ids of each
+// resource: 1,000 distinct paths.
+var resources = []string{"users", "orders", "products", "carts", "reviews"}
+
+const maxID = 200
+
+var (
+	methods    = []string{"GET", "GET", "GET", "POST", "PUT", "DELETE"}
+	statuses   = []int{200, 200, 200, 201, 304, 404, 500}
+	userAgents = []string{
+		`"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0 Safari/537.36"`,
+		`"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_6) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.0 Safari/605.1.15"`,
+		`"curl/8.5.0"`,
+	}
+)
+
+// makeLog returns an access log of n lines, one request per line.
+func makeLog(seed input.Seed, n int) string {
+	rng := seed.Rand("log", n)
+	var b strings.Builder
+	for i := range n {
+		secs := i * 86400 / max(n, 1)
+		fmt.Fprintf(&b, "2026-10-15T%02d:%02d:%02dZ %s /api/v1/%s/%d %d %d %s\n",
+			secs/3600, secs/60%60, secs%60,
+			methods[rng.IntN(len(methods))],
+			resources[rng.IntN(len(resources))], rng.IntN(maxID),
+			statuses[rng.IntN(len(statuses))], 200+rng.IntN(20_000),
+			userAgents[rng.IntN(len(userAgents))])
+	}
+	// Trimmed to its length, so the memory a key can pin is the log's.
+	return strings.Clone(b.String())
+}
+
+// sameCounts is the equality function the implementations are checked
+// with. It names the first path, in sorted order, counted differently.
+func sameCounts(got, want map[string]int) error {
+	keys := make([]string, 0, len(got)+len(want))
+	for k := range want {
+		keys = append(keys, k)
+	}
+	for k := range got {
+		if _, ok := want[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if got[k] != want[k] {
+			return fmt.Errorf("%q counted %d times, want %d", k, got[k], want[k])
+		}
+	}
+	return nil
+}
+
+// keyBytes is the total length of the keys of counts.
+func keyBytes(counts map[string]int) uint64 {
+	var total uint64
+	for k := range counts {
+		total += uint64(len(k))
+	}
+	return total
+}
+
+// pointsInto reports whether any key of counts shares the memory of
+// log: a substring still pointing into it.
+func pointsInto(counts map[string]int, log string) bool {
+	if log == "" {
+		return false
+	}
+	start := uintptr(unsafe.Pointer(unsafe.StringData(log)))
+	end := start + uintptr(len(log))
+	for k := range counts {
+		if k == "" {
+			continue
+		}
+		if p := uintptr(unsafe.Pointer(unsafe.StringData(k))); start <= p && p < end {
+			return true
+		}
+	}
+	return false
+}
+
+// retained counts a fresh log of n lines with count, drops the log, and
+// returns the heap still in use once the garbage is collected, with the
+// counts keeping it alive.
+func retained(count func(string) map[string]int, seed input.Seed, n int) uint64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	counts := count(makeLog(seed, n))
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	runtime.KeepAlive(counts)
+	if after.HeapAlloc < before.HeapAlloc {
+		return 0
+	}
+	return after.HeapAlloc - before.HeapAlloc
+}
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	count            func(string) map[string]int
+}{
+	{"Vibe coding", "substring keys, pins the log", vibeCount},
+	{"Human coding", "strings.Clone per line", humanCount},
+	{"Expert coding", "interned, one copy per key", expertCount},
+}
+
+// counters returns the implementations to compare.
+func counters() []bench.Impl[string, map[string]int] {
+	impls := make([]bench.Impl[string, map[string]int], len(tiers))
+	for i, t := range tiers {
+		impls[i] = bench.Impl[string, map[string]int]{Name: t.name, Complexity: t.complexity, Func: t.count}
+	}
+	return impls
+}
+
+// impls returns the implementations timed counting a log of n lines.
+func impls(n int) []bench.Implementation {
+	log := makeLog(input.DefaultSeed, n)
+	var list []bench.Implementation
+	for _, c := range counters() {
+		list = append(list, c.Implementation(log))
+	}
+	return list
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "substrings of the log as map keys", Complexity: "O(n), no copies", Notes: []report.Note{
+			report.Strength("The fastest to build: nothing is copied"),
+			report.Pitfall("Each key points into the log, so the whole log stays in memory"),
+			report.Pitfall("Nothing in the code or the profile of the build says so"),
+		}},
+		{Label: "Human coding", Approach: "strings.Clone on every key", Complexity: "O(n), one allocation per line", Notes: []report.Note{
+			report.Strength("The log can be collected: only the keys remain"),
+			report.Pitfall("Copies every line's key, though almost all are already in the map"),
+		}},
+		{Label: "Expert coding", Approach: "an interning table, copying each distinct key once", Complexity: "O(n), one allocation per distinct key", Notes: []report.Note{
+			report.Strength("Keeps only the keys, and copies each of them once"),
+			report.Strength("One table can dedupe the strings of many maps and structs"),
+			report.Pitfall("A second lookup per line, and the table must itself be dropped"),
+			report.Tip("Go 1.23's unique.Make interns program-wide, and lets unused strings go"),
+		}},
+	},
+	Takeaway: "A substring, like a subslice, shares the memory it was cut from: " +
+		"keep a few bytes of it and you keep all of it. Copy what you keep - " +
+		"once per distinct value, not once per use.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "29-string-interning",
+		Title:       "String Interning",
+		Description: "Count the paths in an access log with substring map keys, cloned keys and an interning table, and measure how much memory each map keeps alive.",
+		Category:    "performance",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    100_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("29-string-interning", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{10_000, 100_000, 1_000_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of log lines, e.g. 1e4,1e6")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("String Interning", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: String Interning")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	for _, n := range sizes {
+		log := makeLog(*seed, n)
+		want := humanCount(log)
+		fmt.Fprintf(out.Table, "\nCounting %d log lines (%s), %d distinct paths (%s of keys) (seed %d):\n",
+			n, bench.FormatBytes(uint64(len(log))), len(want), bench.FormatBytes(keyBytes(want)), *seed)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		results, err := bench.CompareImpls(ctx, opts, log, want, sameCounts, counters()...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "✔ All implementations give the same counts")
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d lines", n), results)
+
+		fmt.Fprintln(out.Table, "  Heap kept alive by the counts once the log is dropped:")
+		for _, t := range tiers {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			kept := retained(t.count, *seed, n)
+			pins := ""
+			if pointsInto(t.count(log), log) {
+				pins = "  ❌ keys point into the log"
+			}
+			fmt.Fprintf(out.Table, "    %-14s %12s%s\n", t.name, bench.FormatBytes(kept), pins)
+			section.Notes = append(section.Notes, fmt.Sprintf("%s keeps %s alive", t.name, bench.FormatBytes(kept)))
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		log  string
+		want map[string]int
+		desc string
+	}{
+		{"", map[string]int{}, "an empty log"},
+		{"2026-10-15T09:00:00Z GET /a 200 1 \"x\"", map[string]int{"/a": 1}, "no newline after the last line"},
+		{"\n\ngarbage\n2026-10-15T09:00:00Z GET\n", map[string]int{}, "blank and truncated lines are skipped"},
+		{"t GET /a 200\nt GET /A 200\nt POST /a 201\n", map[string]int{"/a": 2, "/A": 1}, "paths differing only in case are different keys"},
+	}
+	for _, tc := range edgeCases {
+		status := "✅"
+		var wrong []string
+		for _, t := range tiers {
+			if err := sameCounts(t.count(tc.log), tc.want); err != nil {
+				status = "❌"
+				wrong = append(wrong, fmt.Sprintf("%s: %v", t.name, err))
+			}
+		}
+		fmt.Fprintf(w, "%s %s: %s\n", status, tc.desc, showCounts(tc.want))
+		for _, msg := range wrong {
+			fmt.Fprintln(w, "   "+msg)
+		}
+		rep.AddEdgeCase(tc.desc, fmt.Sprintf("%s %s", status, showCounts(tc.want)))
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
+
+// showCounts formats counts in sorted order, e.g. "/a 2, /b 1".
+func showCounts(counts map[string]int) string {
+	if len(counts) == 0 {
+		return "no paths"
+	}
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + " " + strconv.Itoa(counts[k])
+	}
+	return strings.Join(parts, ", ")
+}
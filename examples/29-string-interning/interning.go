@@ -0,0 +1,109 @@
+package interning
+
+import (
+	"iter"
+	"strings"
+)
+
+// paths yields the request path of every line of an access log, such as
+// "/api/v1/users/42" from
+//
+//	2026-10-15T09:12:34Z GET /api/v1/users/42 200 512 "curl/8.5.0"
+//
+// Lines without a path are skipped. Each path is a substring of log, so
+// it shares log's memory.
+func paths(log string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for log != "" {
+			var line string
+			line, log, _ = strings.Cut(log, "\n")
+			_, rest, _ := strings.Cut(line, " ") // the time
+			_, rest, _ = strings.Cut(rest, " ")  // the method
+			path, _, _ := strings.Cut(rest, " ")
+			if path != "" && !yield(path) {
+				return
+			}
+		}
+	}
+}
+
+// VIBE CODING: Use the substrings as map keys
+func vibeCount(log string) map[string]int {
+	/*
+	   Count the requests for each path in an access log.
+
+	   Slice each path out of the log and count it. No copying, so it
+	   is the fastest to build. But a Go substring shares the memory of
+	   the string it was cut from, and a map key is kept for as long as
+	   the map. Every key holds a pointer into the log, so the whole
+	   log - megabytes, for a few kilobytes of paths - stays in memory
+	   until the map is gone.
+	*/
+	counts := make(map[string]int)
+	for path := range paths(log) {
+		counts[path]++
+	}
+	return counts
+}
+
+// HUMAN CODING: Clone every key
+func humanCount(log string) map[string]int {
+	/*
+	   The fix, once the leak is understood: strings.Clone copies the
+	   path into its own memory, so the map no longer holds on to the
+	   log. Once the log is dropped, only the keys remain.
+
+	   Correct, but it clones every line's path, to look up a key that
+	   is almost always there already: one allocation per line, all but
+	   a thousand of them garbage the moment the lookup is done.
+	*/
+	counts := make(map[string]int)
+	for path := range paths(log) {
+		counts[strings.Clone(path)]++
+	}
+	return counts
+}
+
+// EXPERT CODING: Intern the keys, copying each once
+
+// interner keeps one private copy of every distinct string given to it.
+type interner struct {
+	strings map[string]string
+}
+
+func newInterner() *interner {
+	return &interner{strings: make(map[string]string)}
+}
+
+// intern returns the copy of s kept by in, making it the first time s
+// is seen. It never returns s itself, so the result doesn't keep
+// whatever s was cut from alive.
+func (in *interner) intern(s string) string {
+	if c, ok := in.strings[s]; ok {
+		return c
+	}
+	c := strings.Clone(s)
+	in.strings[c] = c
+	return c
+}
+
+func expertCount(log string) map[string]int {
+	/*
+	   Look the path up as it is, a substring, and copy it only the
+	   first time it is seen: an interning table hands out one shared
+	   copy of each distinct string. Lookups don't allocate, so a log
+	   of a million lines with a thousand paths makes a thousand
+	   copies, not a million.
+
+	   The table could equally be shared by every map and struct that
+	   stores these paths, so each path is in memory once however many
+	   places use it. Go 1.23's unique package does the same
+	   program-wide, and lets unused strings be collected.
+	*/
+	in := newInterner()
+	counts := make(map[string]int)
+	for path := range paths(log) {
+		counts[in.intern(path)]++
+	}
+	return counts
+}
@@ -0,0 +1,412 @@
+// Package errorhandling compares three ways to handle the errors of
+// loading a config file: ignoring them, wrapping them with %w, and
+// typed errors that callers can inspect, with retries of the ones worth
+// retrying.
+package errorhandling
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	load             func(ctx context.Context, s *store, name string) (config, error)
+}{
+	{"Vibe coding", "errors ignored", vibeLoad},
+	{"Human coding", "fmt.Errorf + %w", humanLoad},
+	{"Expert coding", "typed errors + retries", expertLoad},
+}
+
+// What can happen to a load: the class of its error, or ok.
+const (
+	loaded      = "ok"
+	notFound    = "not found"
+	noAccess    = "permission denied"
+	unavailable = "unavailable"
+	malformed   = "malformed"
+	invalid     = "invalid"
+	unknown     = "unclassified"
+)
+
+// classify names what went wrong in a load that returned err, the way a
+// caller deciding what to do next would: with errors.Is and errors.As,
+// not by reading the message.
+func classify(err error) string {
+	var pe *parseError
+	var ve *validationError
+	switch {
+	case err == nil:
+		return loaded
+	case errors.Is(err, fs.ErrNotExist):
+		return notFound
+	case errors.Is(err, fs.ErrPermission):
+		return noAccess
+	case errors.Is(err, errUnavailable):
+		return unavailable
+	case errors.As(err, &pe):
+		return malformed
+	case errors.As(err, &ve):
+		return invalid
+	}
+	return unknown
+}
+
+// validationErrors returns every *validationError in err's tree,
+// following both Unwrap() error and the Unwrap() []error of
+// errors.Join.
+func validationErrors(err error) []*validationError {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *validationError:
+		return []*validationError{e}
+	case interface{ Unwrap() []error }:
+		var all []*validationError
+		for _, err := range e.Unwrap() {
+			all = append(all, validationErrors(err)...)
+		}
+		return all
+	}
+	return validationErrors(errors.Unwrap(err))
+}
+
+// job is one config file to load, and what loading it should give.
+type job struct {
+	name  string
+	want  config
+	class string
+}
+
+// makeWorkload returns a store of n config files and the jobs loading
+// them. Most files are fine; the rest are missing, unreadable, on a
+// store that fails once or always, malformed or invalid.
+func makeWorkload(seed input.Seed, n int) (*store, []job) {
+	rng := seed.Rand("configs", n)
+	s := newStore()
+	jobs := make([]job, n)
+	for i := range jobs {
+		name := fmt.Sprintf("svc-%05d.conf", i)
+		c := config{host: fmt.Sprintf("api-%d.internal", i), port: 1024 + rng.IntN(60_000), timeout: time.Duration(1+rng.IntN(30)) * time.Second}
+		file := fmt.Sprintf("# service %d\nhost = %s\nport = %d\ntimeout = %v\n", i, c.host, c.port, c.timeout)
+		j := job{name: name, want: c, class: loaded}
+		switch p := rng.IntN(100); {
+		case p < 5:
+			j = job{name: name, class: notFound}
+			file = ""
+		case p < 10:
+			j = job{name: name, class: noAccess}
+			s.faults[name] = denied
+		case p < 15:
+			s.faults[name] = flaky // A retry loads it
+		case p < 18:
+			j = job{name: name, class: unavailable}
+			s.faults[name] = down
+		case p < 21:
+			j = job{name: name, class: malformed}
+			file = strings.Replace(file, fmt.Sprint(c.port), "eighty", 1)
+		case p < 24:
+			j = job{name: name, class: malformed}
+			file = strings.Replace(file, "timeout =", "timeout", 1)
+		case p < 27:
+			j = job{name: name, class: invalid}
+			file = strings.Replace(file, fmt.Sprint(c.port), fmt.Sprint(65536+c.port), 1)
+		case p < 30:
+			j = job{name: name, class: invalid}
+			file = strings.Replace(file, "host = "+c.host+"\n", "", 1)
+		}
+		if file != "" {
+			s.files[name] = file
+		}
+		jobs[i] = j
+	}
+	return s, jobs
+}
+
+// tally is how one implementation's loads went.
+type tally struct {
+	right  int // Loaded the right config, or failed with the right class of error
+	silent int // Returned no error, but should have, or a wrong config
+	wrong  int // Failed with an error of the wrong class, or none a caller can tell
+	reads  int // Reads of the store
+}
+
+// loadAll loads every job's file from s with load.
+func loadAll(ctx context.Context, s *store, jobs []job, load func(context.Context, *store, string) (config, error)) (tally, error) {
+	s.reset()
+	var t tally
+	for i, j := range jobs {
+		if i%1024 == 0 && ctx.Err() != nil {
+			return t, ctx.Err()
+		}
+		c, err := load(ctx, s, j.name)
+		switch {
+		case classify(err) == j.class && (err != nil || c == j.want):
+			t.right++
+		case err == nil:
+			t.silent++
+		default:
+			t.wrong++
+		}
+	}
+	t.reads = s.total
+	return t, nil
+}
+
+// impls returns the implementations timed loading n config files.
+func impls(n int) []bench.Implementation {
+	s, jobs := makeWorkload(input.DefaultSeed, n)
+	list := make([]bench.Implementation, len(tiers))
+	for i, t := range tiers {
+		list[i] = bench.Implementation{
+			Name: t.name, Complexity: t.complexity,
+			RunContext: func(ctx context.Context) error {
+				_, err := loadAll(ctx, s, jobs, t.load)
+				return err
+			},
+		}
+	}
+	return list
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "every error discarded with _", Complexity: "1 read, never fails", Notes: []report.Note{
+			report.Strength("The shortest code, and right whenever nothing goes wrong"),
+			report.Pitfall("A missing file, a typo or an outage all load as a zero-valued config"),
+			report.Pitfall("The failure surfaces later, somewhere else, with no cause attached"),
+		}},
+		{Label: "Human coding", Approach: "every error checked, wrapped with fmt.Errorf and %w", Complexity: "1 read, first error", Notes: []report.Note{
+			report.Strength("Fails loudly, with a message saying what was being done"),
+			report.Strength("%w keeps the cause: errors.Is still finds fs.ErrNotExist"),
+			report.Pitfall("Line numbers and settings are only in the text: callers match strings"),
+			report.Pitfall("Reports only the first problem in a file"),
+			report.Pitfall("A moment's outage fails the load like a missing file does"),
+		}},
+		{Label: "Expert coding", Approach: "typed errors, errors.Join, retries of what is retryable", Complexity: "≤ 3 reads, every problem", Notes: []report.Note{
+			report.Strength("errors.As finds the line of a *parseError, the setting of a *validationError"),
+			report.Strength("Every invalid setting is reported at once"),
+			report.Strength("Retries only errUnavailable, with jittered pauses that honor ctx"),
+			report.Pitfall("More types and code, and retries add latency to the failures"),
+			report.Tip("Keep error types unexported behind errors.As, or they become API to maintain"),
+		}},
+	},
+	Takeaway: "An error is a value for the caller to act on. Never drop it; wrap " +
+		"it with %w so the cause survives; and give callers what they need " +
+		"to decide - a sentinel for errors.Is, a type for errors.As - " +
+		"including whether trying again could work.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "30-error-handling",
+		Title:       "Error Handling",
+		Description: "Load config files from a store that fails on purpose, ignoring errors, wrapping them with %w, and with typed errors and retries.",
+		Category:    "error handling",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    1_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("30-error-handling", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{1_000, 10_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of config files, e.g. 100,1e4")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	for _, n := range sizes {
+		if n < 1 {
+			return fmt.Errorf("-n must be at least 1, not %d", n)
+		}
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Error Handling", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Error Handling")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	for _, n := range sizes {
+		s, jobs := makeWorkload(*seed, n)
+		classes, retried := map[string]int{}, 0
+		for _, j := range jobs {
+			classes[j.class]++
+			if s.faults[j.name] == flaky {
+				retried++
+			}
+		}
+		fmt.Fprintf(out.Table, "\nLoading %d config files (seed %d):\n", n, *seed)
+		fmt.Fprintf(w, "  %d should load (%d after a retry), %d are missing, %d unreadable, %d on a store that is down, %d malformed, %d invalid\n",
+			classes[loaded], retried, classes[notFound], classes[noAccess],
+			classes[unavailable], classes[malformed], classes[invalid])
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		// A load can fail on purpose, so there is no single answer to
+		// check: each run tallies how its loads went, and the last
+		// run's tally is reported.
+		tallies := make([]tally, len(tiers))
+		impls := make([]bench.Implementation, len(tiers))
+		for i, t := range tiers {
+			impls[i] = bench.Implementation{
+				Name: t.name, Complexity: t.complexity,
+				RunContext: func(ctx context.Context) (err error) {
+					tallies[i], err = loadAll(ctx, s, jobs, t.load)
+					return err
+				},
+			}
+		}
+		results, err := bench.CompareContext(ctx, opts, impls...)
+		if err != nil {
+			return err
+		}
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d files", n), results)
+
+		fmt.Fprintln(out.Table, "\nOutcome of every load, and store reads per file:")
+		for i, r := range results {
+			t := tallies[i]
+			pct := func(k int) float64 { return 100 * float64(k) / float64(n) }
+			fmt.Fprintf(out.Table, "  %-14s %5.1f%% right   %5.1f%% silently wrong   %5.1f%% wrong error   %4.2f reads\n",
+				r.Name+":", pct(t.right), pct(t.silent), pct(t.wrong), float64(t.reads)/float64(n))
+			section.Notes = append(section.Notes, fmt.Sprintf("%s: %d of %d loads right, %d silently wrong, %d with the wrong error, %d reads",
+				r.Name, t.right, n, t.silent, t.wrong, t.reads))
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing: one load under each failure mode")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	const good = "host = db.internal\nport = 5432\ntimeout = 2s\n"
+	goodConfig := config{host: "db.internal", port: 5432, timeout: 2 * time.Second}
+	edgeCases := []struct {
+		desc string
+		file string // The file's contents; none if empty
+		f    fault
+		want string
+		good func(c config, err error, reads int) bool
+	}{
+		{"a valid file", good, healthy, "loads",
+			func(c config, err error, _ int) bool { return err == nil && c == goodConfig }},
+		{"comments, blank lines and unknown settings", "# db\n\nhost = db.internal\nreplicas = 3\nport = 5432\ntimeout = 2s", healthy, "loads",
+			func(c config, err error, _ int) bool { return err == nil && c == goodConfig }},
+		{"no such file", "", healthy, "errors.Is fs.ErrNotExist, after 1 read",
+			func(_ config, err error, reads int) bool { return classify(err) == notFound && reads == 1 }},
+		{"permission denied", good, denied, "errors.Is fs.ErrPermission, after 1 read",
+			func(_ config, err error, reads int) bool { return classify(err) == noAccess && reads == 1 }},
+		{"store unavailable once", good, flaky, "loads, after a retry",
+			func(c config, err error, _ int) bool { return err == nil && c == goodConfig }},
+		{"store down", good, down, fmt.Sprintf("errors.Is errUnavailable, after at most %d reads", expertAttempts),
+			func(_ config, err error, reads int) bool {
+				return classify(err) == unavailable && reads <= expertAttempts
+			}},
+		{"port = eighty on line 2", "host = db.internal\nport = eighty\ntimeout = 2s\n", healthy, "errors.As *parseError, line 2",
+			func(_ config, err error, _ int) bool {
+				var pe *parseError
+				return errors.As(err, &pe) && pe.line == 2
+			}},
+		{"no '=' on line 3", "host = db.internal\nport = 5432\ntimeout 2s\n", healthy, "errors.As *parseError, line 3",
+			func(_ config, err error, _ int) bool {
+				var pe *parseError
+				return errors.As(err, &pe) && pe.line == 3
+			}},
+		{"port 70000 and no timeout", "host = db.internal\nport = 70000\n", healthy, "a *validationError for each",
+			func(_ config, err error, _ int) bool { return len(validationErrors(err)) == 2 }},
+		{"an empty file", "\n", healthy, "a *validationError for host, port and timeout",
+			func(_ config, err error, _ int) bool { return len(validationErrors(err)) == 3 }},
+	}
+	for _, tc := range edgeCases {
+		fmt.Fprintf(w, "%s (want: %s):\n", tc.desc, tc.want)
+		for _, t := range tiers {
+			s := newStore()
+			if tc.file != "" {
+				s.files["app.conf"] = tc.file
+			}
+			s.faults["app.conf"] = tc.f
+			c, err := t.load(ctx, s, "app.conf")
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			result := fmt.Sprintf("ok, %s after %d read(s)", showConfig(c), s.total)
+			if err != nil {
+				result = fmt.Sprintf("%s after %d read(s): %s", classify(err), s.total, strings.ReplaceAll(err.Error(), "\n", "; "))
+			}
+			status := "✅"
+			if !tc.good(c, err, s.total) {
+				status = "❌"
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
+
+// showConfig formats c, e.g. `"db.internal":5432, timeout 2s`.
+func showConfig(c config) string {
+	return fmt.Sprintf("%q:%d, timeout %v", c.host, c.port, c.timeout)
+}
@@ -0,0 +1,253 @@
+package errorhandling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// config is a service's settings, read from a file of "key = value"
+// lines such as
+//
+//	# checkout service
+//	host = api-7.internal
+//	port = 8443
+//	timeout = 5s
+//
+// Every setting is required. Blank lines, comments and settings not
+// listed here are ignored.
+type config struct {
+	host    string
+	port    int
+	timeout time.Duration
+}
+
+// VIBE CODING: Ignore the errors
+func vibeLoad(ctx context.Context, s *store, name string) (config, error) {
+	/*
+	   Read the config file called name from s, and parse it.
+
+	   Every call that can fail returns an error, and every error is
+	   thrown away with _. The happy path is all there is: it is short,
+	   and with good files it works. But a missing file reads as empty,
+	   a port of "eighty" parses as 0, and a port of 70000 is accepted.
+	   Whatever goes wrong, the caller gets a config and a nil error,
+	   and finds out much later, somewhere else.
+	*/
+	data, _ := s.ReadFile(name)
+	var c config
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, _ := strings.Cut(line, "=")
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "host":
+			c.host = value
+		case "port":
+			c.port, _ = strconv.Atoi(value)
+		case "timeout":
+			c.timeout, _ = time.ParseDuration(value)
+		}
+	}
+	return c, nil
+}
+
+// HUMAN CODING: Check every error, and wrap it with %w
+func humanLoad(ctx context.Context, s *store, name string) (config, error) {
+	/*
+	   Read the config file called name from s, and parse it.
+
+	   Check every error, return it at once, and wrap it with
+	   fmt.Errorf and %w, adding what was being done:
+
+	       load config.txt: line 3: port: strconv.Atoi: parsing "eighty": invalid syntax
+
+	   The message reads like a stack trace, and %w keeps the cause, so
+	   errors.Is(err, fs.ErrNotExist) still finds a missing file.
+
+	   But everything else the caller might act on is only in the text:
+	   which line, which setting. Callers end up matching strings. Only
+	   the first problem is reported, so fixing a file takes a run per
+	   mistake. And the store failing for a moment fails the load, just
+	   as a missing file does: nothing says which is worth retrying.
+	*/
+	data, err := s.ReadFile(name)
+	if err != nil {
+		return config{}, fmt.Errorf("load %s: %w", name, err)
+	}
+	var c config
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return config{}, fmt.Errorf("load %s: line %d: missing '='", name, i+1)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch key {
+		case "host":
+			c.host = value
+		case "port":
+			if c.port, err = strconv.Atoi(value); err != nil {
+				return config{}, fmt.Errorf("load %s: line %d: port: %w", name, i+1, err)
+			}
+		case "timeout":
+			if c.timeout, err = time.ParseDuration(value); err != nil {
+				return config{}, fmt.Errorf("load %s: line %d: timeout: %w", name, i+1, err)
+			}
+		}
+	}
+	switch {
+	case c.host == "":
+		return config{}, fmt.Errorf("load %s: host missing", name)
+	case c.port < 1 || c.port > 65535:
+		return config{}, fmt.Errorf("load %s: port %d out of range", name, c.port)
+	case c.timeout <= 0:
+		return config{}, fmt.Errorf("load %s: timeout %v not positive", name, c.timeout)
+	}
+	return c, nil
+}
+
+// EXPERT CODING: Typed errors, all problems at once, and retries
+
+// parseError is a line of a config file that couldn't be parsed.
+type parseError struct {
+	name string
+	line int
+	err  error
+}
+
+func (e *parseError) Error() string { return fmt.Sprintf("%s:%d: %v", e.name, e.line, e.err) }
+func (e *parseError) Unwrap() error { return e.err }
+
+// validationError is a setting that is missing or not allowed.
+type validationError struct {
+	field, reason string
+}
+
+func (e *validationError) Error() string { return e.field + ": " + e.reason }
+
+// Reads that fail with errUnavailable are tried expertAttempts times in
+// all, with pauses of up to expertBase·2^k between them. The store is in
+// memory, so the pauses asked for are short, but a timer rarely fires
+// sooner than about a millisecond: against a real service they would be
+// milliseconds anyway.
+const (
+	expertAttempts = 3
+	expertBase     = 20 * time.Microsecond
+)
+
+func expertLoad(ctx context.Context, s *store, name string) (config, error) {
+	/*
+	   Read the config file called name from s, and parse it.
+
+	   Errors are values the caller can act on, not just text: the
+	   causes stay wrapped, so errors.Is finds fs.ErrNotExist or
+	   errUnavailable, and problems in the file are typed, so errors.As
+	   finds the *parseError with its line number, or the
+	   *validationError naming the setting.
+
+	   Every invalid setting is reported at once, with errors.Join, so
+	   one run shows every mistake. And failures are classified: the
+	   store being unavailable is worth retrying, with growing, jittered
+	   pauses, until the caller's ctx ends; a missing file or a bad
+	   line fails at once, as no retry can fix it.
+	*/
+	data, err := readRetrying(ctx, s, name)
+	if err != nil {
+		return config{}, fmt.Errorf("load config: %w", err)
+	}
+	c, err := parseConfig(name, string(data))
+	if err != nil {
+		return config{}, fmt.Errorf("load config: %w", err)
+	}
+	if err := c.validate(); err != nil {
+		return config{}, fmt.Errorf("load config %s: %w", name, err)
+	}
+	return c, nil
+}
+
+// retryable reports whether an operation that failed with err might
+// succeed if tried again.
+func retryable(err error) bool {
+	return errors.Is(err, errUnavailable)
+}
+
+// readRetrying reads the file called name from s, retrying while the
+// error is retryable, up to expertAttempts reads in all.
+func readRetrying(ctx context.Context, s *store, name string) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		data, err := s.ReadFile(name)
+		if err == nil || !retryable(err) || attempt == expertAttempts {
+			return data, err
+		}
+		timer := time.NewTimer(rand.N(expertBase << attempt)) // full jitter
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, errors.Join(ctx.Err(), err)
+		}
+	}
+}
+
+// parseConfig parses the contents of the config file called name,
+// stopping at the first line it can't parse.
+func parseConfig(name, data string) (config, error) {
+	var c config
+	for i, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fail := func(err error) (config, error) {
+			return config{}, &parseError{name: name, line: i + 1, err: err}
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return fail(errors.New("missing '='"))
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		var err error
+		switch key {
+		case "host":
+			c.host = value
+		case "port":
+			if c.port, err = strconv.Atoi(value); err != nil {
+				return fail(fmt.Errorf("port: %w", err))
+			}
+		case "timeout":
+			if c.timeout, err = time.ParseDuration(value); err != nil {
+				return fail(fmt.Errorf("timeout: %w", err))
+			}
+		}
+	}
+	return c, nil
+}
+
+// validate returns every setting of c that is missing or not allowed,
+// joined, or nil if c is valid.
+func (c config) validate() error {
+	var errs []error
+	if c.host == "" {
+		errs = append(errs, &validationError{"host", "missing"})
+	}
+	switch {
+	case c.port == 0:
+		errs = append(errs, &validationError{"port", "missing"})
+	case c.port < 1 || c.port > 65535:
+		errs = append(errs, &validationError{"port", fmt.Sprintf("%d is not in 1-65535", c.port)})
+	}
+	switch {
+	case c.timeout == 0:
+		errs = append(errs, &validationError{"timeout", "missing"})
+	case c.timeout < 0:
+		errs = append(errs, &validationError{"timeout", fmt.Sprintf("%v is negative", c.timeout)})
+	}
+	return errors.Join(errs...)
+}
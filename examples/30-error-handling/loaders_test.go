@@ -0,0 +1,93 @@
+package errorhandling
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iportilla/ai-coding/input"
+)
+
+// TestFailureModes loads one file under each failure mode with every
+// tier, and checks the class of error a caller sees: what the vibe
+// version hides, what the human one reports but can't be told apart,
+// and what the expert one gets right.
+func TestFailureModes(t *testing.T) {
+	const good = "host = db.internal\nport = 5432\ntimeout = 2s\n"
+	want := config{host: "db.internal", port: 5432, timeout: 2 * time.Second}
+	tests := []struct {
+		name  string
+		file  string // The file's contents; none if empty
+		f     fault
+		class [3]string // What classify returns, for vibe, human and expert
+	}{
+		{"valid", good, healthy, [3]string{loaded, loaded, loaded}},
+		{"comments and unknown settings", "# db\n\nhost = db.internal\nreplicas = 3\nport = 5432\ntimeout = 2s", healthy, [3]string{loaded, loaded, loaded}},
+		{"missing", "", healthy, [3]string{loaded, notFound, notFound}},
+		{"permission denied", good, denied, [3]string{loaded, noAccess, noAccess}},
+		{"unavailable once", good, flaky, [3]string{loaded, unavailable, loaded}},
+		{"down", good, down, [3]string{loaded, unavailable, unavailable}},
+		{"port not a number", "host = db.internal\nport = eighty\ntimeout = 2s\n", healthy, [3]string{loaded, unknown, malformed}},
+		{"no '='", "host = db.internal\nport = 5432\ntimeout 2s\n", healthy, [3]string{loaded, unknown, malformed}},
+		{"port out of range", "host = db.internal\nport = 70000\n", healthy, [3]string{loaded, unknown, invalid}},
+		{"empty", "\n", healthy, [3]string{loaded, unknown, invalid}},
+	}
+	for _, tt := range tests {
+		for i, tier := range tiers {
+			t.Run(tt.name+"/"+tier.name, func(t *testing.T) {
+				s := newStore()
+				if tt.file != "" {
+					s.files["app.conf"] = tt.file
+				}
+				s.faults["app.conf"] = tt.f
+				c, err := tier.load(context.Background(), s, "app.conf")
+				if got := classify(err); got != tt.class[i] {
+					t.Fatalf("got %s (%v), want %s", got, err, tt.class[i])
+				}
+				if i > 0 && err == nil && c != want {
+					t.Errorf("loaded %s, want %s", showConfig(c), showConfig(want))
+				}
+			})
+		}
+	}
+}
+
+// TestExpertErrors checks what the expert version's typed errors carry.
+func TestExpertErrors(t *testing.T) {
+	load := func(file string, f fault) (int, error) {
+		s := newStore()
+		s.files["app.conf"] = file
+		s.faults["app.conf"] = f
+		_, err := expertLoad(context.Background(), s, "app.conf")
+		return s.total, err
+	}
+
+	_, err := load("host = db.internal\nport = eighty\ntimeout = 2s\n", healthy)
+	var pe *parseError
+	if !errors.As(err, &pe) || pe.line != 2 {
+		t.Errorf("port = eighty on line 2: got %v, want a *parseError for line 2", err)
+	}
+	if _, err := load("\n", healthy); len(validationErrors(err)) != 3 {
+		t.Errorf("empty file: got %v, want a *validationError for host, port and timeout", err)
+	}
+	if reads, err := load("host = db.internal\n", down); !errors.Is(err, errUnavailable) || reads != expertAttempts {
+		t.Errorf("store down: got %v after %d reads, want errUnavailable after %d", err, reads, expertAttempts)
+	}
+	if reads, err := load("host = db.internal\n", denied); reads != 1 {
+		t.Errorf("permission denied: got %v after %d reads, want no retries", err, reads)
+	}
+}
+
+// TestExpertWorkload loads a whole workload with the expert version,
+// which should get every file right.
+func TestExpertWorkload(t *testing.T) {
+	s, jobs := makeWorkload(input.DefaultSeed, 1_000)
+	got, err := loadAll(context.Background(), s, jobs, expertLoad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.right != len(jobs) {
+		t.Errorf("%d of %d loads right, %d silently wrong, %d with the wrong error", got.right, len(jobs), got.silent, got.wrong)
+	}
+}
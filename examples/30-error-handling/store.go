@@ -0,0 +1,64 @@
+package errorhandling
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// errUnavailable is the store failing to answer, as a network file
+// system or object store does now and then. Trying again may work.
+var errUnavailable = errors.New("store unavailable")
+
+// fault is what the store does wrong, on purpose, for one file.
+type fault int
+
+const (
+	healthy fault = iota
+	denied        // Every read fails with fs.ErrPermission
+	flaky         // The first read fails with errUnavailable
+	down          // Every read fails with errUnavailable
+)
+
+// store is an in-memory file store that fails on purpose. Reads fail
+// the way os.ReadFile does, with an *fs.PathError wrapping the cause,
+// and a missing file wraps fs.ErrNotExist.
+type store struct {
+	files  map[string]string
+	faults map[string]fault
+	reads  map[string]int // Reads so far, per file
+	total  int            // Reads so far, of all files
+}
+
+func newStore() *store {
+	return &store{files: map[string]string{}, faults: map[string]fault{}, reads: map[string]int{}}
+}
+
+// reset forgets every read so far, so flaky files fail once again.
+func (s *store) reset() {
+	clear(s.reads)
+	s.total = 0
+}
+
+// ReadFile returns the contents of the file called name.
+func (s *store) ReadFile(name string) ([]byte, error) {
+	s.reads[name]++
+	s.total++
+	fail := func(err error) ([]byte, error) {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+	switch s.faults[name] {
+	case denied:
+		return fail(fs.ErrPermission)
+	case down:
+		return fail(errUnavailable)
+	case flaky:
+		if s.reads[name] == 1 {
+			return fail(errUnavailable)
+		}
+	}
+	data, ok := s.files[name]
+	if !ok {
+		return fail(fs.ErrNotExist)
+	}
+	return []byte(data), nil
+}
@@ -0,0 +1,111 @@
+package contextprop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/iportilla/ai-coding/input"
+)
+
+// errTransient is a dependency failing one call. Trying again may work.
+var errTransient = errors.New("transient failure")
+
+// latency is how long a call to a dependency takes when nothing is
+// wrong.
+const latency = time.Millisecond
+
+// faults is what a dependency does wrong on purpose.
+type faults struct {
+	fail      float64       // Fraction of calls failing with errTransient
+	slow      float64       // Fraction of calls that hang before answering
+	hang      time.Duration // How long those hang
+	failFirst int           // The first calls for each id fail with errTransient
+}
+
+// dependency is a service a request depends on, such as a database,
+// running in-process and failing on purpose. Whether a call fails or
+// hangs depends only on the seed, the id and how many times that id was
+// asked for before, so every implementation meets the same faults in the
+// same places, however its calls interleave.
+type dependency struct {
+	name   string
+	answer func(id int) string
+	seed   input.Seed
+
+	mu     sync.Mutex
+	faults faults
+	tries  map[int]int // Calls so far, per id
+
+	calls   atomic.Int64 // Calls since reset
+	running atomic.Int64 // Calls in progress right now
+}
+
+func newDependency(name string, answer func(int) string, seed input.Seed, f faults) *dependency {
+	return &dependency{name: name, answer: answer, seed: seed, faults: f, tries: map[int]int{}}
+}
+
+// Get returns the dependency's answer for id. It can't be cancelled:
+// once called, it takes as long as it takes.
+func (d *dependency) Get(id int) (string, error) {
+	return d.GetContext(context.Background(), id)
+}
+
+// GetContext is Get, but gives up as soon as ctx is done, returning
+// ctx.Err().
+func (d *dependency) GetContext(ctx context.Context, id int) (string, error) {
+	d.calls.Add(1)
+	d.running.Add(1)
+	defer d.running.Add(-1)
+
+	d.mu.Lock()
+	f, try := d.faults, d.tries[id]
+	d.tries[id]++
+	d.mu.Unlock()
+
+	delay, err := latency, error(nil)
+	roll := d.seed.Rand(fmt.Sprintf("%s/%d", d.name, id), try).Float64()
+	switch {
+	case try < f.failFirst || roll < f.fail:
+		err = errTransient
+	case roll < f.fail+f.slow:
+		delay = f.hang
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return "", fmt.Errorf("%s: %w", d.name, ctx.Err())
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", d.name, err)
+	}
+	return d.answer(id), nil
+}
+
+// deps are the dependencies a page is built from.
+type deps struct {
+	users, orders *dependency
+}
+
+// newDeps returns fresh dependencies injecting faults from seed: users
+// injects fu and orders fo.
+func newDeps(seed input.Seed, fu, fo faults) *deps {
+	return &deps{
+		users:  newDependency("users", func(id int) string { return fmt.Sprintf("user-%d", id) }, seed, fu),
+		orders: newDependency("orders", func(id int) string { return fmt.Sprintf("%d orders", id%7) }, seed, fo),
+	}
+}
+
+// calls is the number of calls made to d's dependencies so far.
+func (d *deps) calls() int64 { return d.users.calls.Load() + d.orders.calls.Load() }
+
+// running is the number of calls to d's dependencies in progress.
+func (d *deps) running() int64 { return d.users.running.Load() + d.orders.running.Load() }
+
+// want is the page for id.
+func want(id int) string { return fmt.Sprintf("user-%d: %d orders", id, id%7) }
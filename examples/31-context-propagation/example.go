@@ -0,0 +1,314 @@
+// Package contextprop compares three ways to build a response from slow
+// dependencies: blocking calls, a timeout around them, and passing the
+// caller's context all the way down, with cleanup and retries that
+// respect its deadline.
+package contextprop
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	page             func(ctx context.Context, d *deps, id int) (string, error)
+}{
+	{"Vibe coding", "blocking calls", vibePage},
+	{"Human coding", "timeout from Background", humanPage},
+	{"Expert coding", "ctx passed down + retries", expertPage},
+}
+
+// A request is late if it returns more than slack after its deadline:
+// timers can fire a millisecond or so late, so a little is allowed.
+const slack = 5 * time.Millisecond
+
+// batch is how one implementation's requests went.
+type batch struct {
+	ok        int
+	late      int             // Requests returning more than slack after their deadline
+	latencies []time.Duration // Of every request, successful or not
+	calls     int64           // Calls made to the dependencies
+	left      int64           // Calls still running once every request returned
+}
+
+// runBatch makes requests requests for page, clients at a time, each
+// for an id of its own and with deadline to answer, against fresh
+// dependencies injecting f.
+func runBatch(ctx context.Context, seed input.Seed, f faults, page func(context.Context, *deps, int) (string, error), requests, clients int, deadline time.Duration) batch {
+	d := newDeps(seed, f, f)
+	latencies := make([]time.Duration, requests)
+	var ok, late, next atomic.Int64
+	var wg sync.WaitGroup
+	for range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1)) - 1
+				if i >= requests || ctx.Err() != nil {
+					return
+				}
+				reqCtx, cancel := context.WithTimeout(ctx, deadline)
+				start := time.Now()
+				got, err := page(reqCtx, d, i)
+				latencies[i] = time.Since(start)
+				cancel()
+				if err == nil && got == want(i) {
+					ok.Add(1)
+				}
+				if latencies[i] > deadline+slack {
+					late.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return batch{ok: int(ok.Load()), late: int(late.Load()), latencies: latencies, calls: d.calls(), left: d.running()}
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "blocking calls, ctx ignored", Complexity: "as slow as the slowest call", Notes: []report.Note{
+			report.Strength("The simplest code, and fine while every dependency is fast"),
+			report.Pitfall("A hanging dependency hangs the request, whatever the caller's deadline"),
+			report.Pitfall("Nobody can cancel it: not the client, not a server shutting down"),
+			report.Pitfall("One failed call fails the request"),
+		}},
+		{Label: "Human coding", Approach: "context.WithTimeout(context.Background()) around a goroutine", Complexity: "≤ 50ms, work left running", Notes: []report.Note{
+			report.Strength("The request gives up after 50ms instead of hanging"),
+			report.Strength("Retries ride out a failed call"),
+			report.Pitfall("Starts from Background: a caller's shorter deadline is ignored"),
+			report.Pitfall("The calls are never told to stop, so abandoned work piles up on slow dependencies"),
+		}},
+		{Label: "Expert coding", Approach: "the caller's ctx passed to every call, defer cancel, deadline-aware retries", Complexity: "≤ the caller's deadline, nothing left running", Notes: []report.Note{
+			report.Strength("Returns by the sooner of the caller's deadline and its own budget"),
+			report.Strength("Cancellation reaches every call, so nothing runs on after the request"),
+			report.Strength("Retries only while the deadline leaves time, and reports the real cause when it doesn't"),
+			report.Pitfall("Every function on the path must take a ctx and honor it"),
+		}},
+	},
+	Takeaway: "Take a ctx, pass it to everything that can block, and derive " +
+		"timeouts from it rather than from context.Background(). A timeout " +
+		"that doesn't reach the work only stops the waiting - the work goes on.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "31-context-propagation",
+		Title:       "Context Propagation",
+		Description: "Build pages from dependencies that hang and fail on purpose, with blocking calls, a timeout around them, and the caller's context passed all the way down.",
+		Category:    "concurrency",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("31-context-propagation", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	slowRates := bench.Sizes{0, 5, 20}
+	fs.Var(&slowRates, "slow", "comma-separated percentages of dependency calls that hang, e.g. 0,50")
+	failRate := fs.Int("fail", 10, "percentage of dependency calls that fail")
+	hang := fs.Duration("hang", 100*time.Millisecond, "how long a hanging call hangs")
+	deadline := fs.Duration("deadline", 20*time.Millisecond, "the caller's deadline for each request")
+	requests := fs.Int("requests", 200, "requests per run")
+	clients := fs.Int("clients", 20, "requests in flight at once")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	for _, p := range append(slowRates, *failRate) {
+		if p < 0 || p > 100 {
+			return fmt.Errorf("-slow and -fail are percentages, got %d", p)
+		}
+	}
+	if *requests < 1 || *clients < 1 {
+		return errors.New("-requests and -clients must be at least 1")
+	}
+	if *deadline <= 0 || *hang < 0 {
+		return errors.New("-deadline must be positive, and -hang not negative")
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Context Propagation", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Context Propagation")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "Each page calls users, then orders (%v each); %d pages per run, %d at a time, each with a %v deadline\n",
+		latency, *requests, *clients, *deadline)
+	fmt.Fprintf(w, "Injected faults from seed %d\n", *seed)
+
+	for _, p := range slowRates {
+		f := faults{fail: float64(*failRate) / 100, slow: float64(p) / 100, hang: *hang}
+		title := fmt.Sprintf("%d%% of calls hang for %v, %d%% fail", p, *hang, *failRate)
+		fmt.Fprintf(out.Table, "\n%s:\n", title)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		// A request can fail, so there is no answer to check: each run
+		// records how its requests went, and the last run's are
+		// reported.
+		batches := make([]batch, len(tiers))
+		impls := make([]bench.Implementation, len(tiers))
+		for i, t := range tiers {
+			impls[i] = bench.Implementation{
+				Name: t.name, Complexity: t.complexity,
+				RunContext: func(ctx context.Context) error {
+					batches[i] = runBatch(ctx, *seed, f, t.page, *requests, *clients, *deadline)
+					return ctx.Err()
+				},
+			}
+		}
+		results, err := bench.CompareContext(ctx, opts, impls...)
+		if err != nil {
+			return err
+		}
+		if err := csvLog.Append(fmt.Sprintf("%d%% slow", p), uint64(*requests), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append(fmt.Sprintf("%d%% slow", p), uint64(*requests), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(title, results)
+
+		fmt.Fprintln(out.Table, "\nSuccess, lateness and latency per page, and calls left running at the end:")
+		for i, r := range results {
+			b := batches[i]
+			fmt.Fprintf(out.Table, "  %-14s %5.1f%% succeeded   %5.1f%% late   p50 %8s   p99 %8s   max %8s   %3d left running\n",
+				r.Name+":", 100*float64(b.ok)/float64(*requests), 100*float64(b.late)/float64(*requests),
+				millis(bench.Percentile(b.latencies, 50)), millis(bench.Percentile(b.latencies, 99)),
+				millis(bench.Percentile(b.latencies, 100)), b.left)
+			section.Notes = append(section.Notes, fmt.Sprintf("%s: %d of %d pages succeeded, %d late, %d calls, %d left running",
+				r.Name, b.ok, *requests, b.late, b.calls, b.left))
+		}
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing: one page each, with faults injected")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	// outcome is how one page went.
+	type outcome struct {
+		page    string
+		err     error
+		elapsed time.Duration
+		calls   int64 // Calls made to the dependencies
+		left    int64 // Calls still running when the page returned
+	}
+	const id = 42
+	hangs := faults{slow: 1, hang: *hang}
+	edgeCases := []struct {
+		desc          string
+		users, orders faults
+		limit         time.Duration // The caller's deadline, if any
+		cancelled     bool          // The caller gave up before calling
+		want          string
+		good          func(o outcome) bool
+	}{
+		{"fast dependencies", faults{}, faults{}, *deadline, false, "returns the page",
+			func(o outcome) bool { return o.err == nil && o.page == want(id) }},
+		{"users fails once", faults{failFirst: 1}, faults{}, *deadline, false, "returns the page after a retry",
+			func(o outcome) bool { return o.err == nil && o.page == want(id) }},
+		{fmt.Sprintf("users hangs, caller's deadline %v", *deadline), hangs, faults{}, *deadline, false,
+			"returns by the deadline, leaving nothing running",
+			func(o outcome) bool { return o.elapsed <= *deadline+slack && o.left == 0 }},
+		{"orders hangs, caller sets no deadline", faults{}, hangs, 0, false,
+			fmt.Sprintf("gives up within its own %v", expertBudget),
+			func(o outcome) bool { return o.err != nil && o.elapsed <= expertBudget+slack }},
+		{"caller gave up before calling", faults{}, faults{}, 0, true, "returns at once, calling nothing",
+			func(o outcome) bool { return o.err != nil && o.calls == 0 }},
+		{fmt.Sprintf("users always fails, caller's deadline %v", *deadline), faults{fail: 1}, faults{}, *deadline, false,
+			"returns the transient error by the deadline",
+			func(o outcome) bool { return errors.Is(o.err, errTransient) && o.elapsed <= *deadline+slack }},
+	}
+	for _, tc := range edgeCases {
+		fmt.Fprintf(w, "%s (want: %s):\n", tc.desc, tc.want)
+		for _, t := range tiers {
+			callCtx, cancel := context.WithCancel(ctx)
+			if tc.limit > 0 {
+				callCtx, cancel = context.WithTimeout(ctx, tc.limit)
+			}
+			if tc.cancelled {
+				cancel()
+			}
+			d := newDeps(*seed, tc.users, tc.orders)
+			start := time.Now()
+			page, err := t.page(callCtx, d, id)
+			o := outcome{page: page, err: err, elapsed: time.Since(start), calls: d.calls(), left: d.running()}
+			cancel()
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			result := fmt.Sprintf("%q in %s, %d call(s)", o.page, millis(o.elapsed), o.calls)
+			if err != nil {
+				result = fmt.Sprintf("failed in %s, %d call(s): %v", millis(o.elapsed), o.calls, strings.ReplaceAll(err.Error(), "\n", "; "))
+			}
+			if o.left > 0 {
+				result += fmt.Sprintf(" (%d still running)", o.left)
+			}
+			status := "✅"
+			if !tc.good(o) {
+				status = "❌"
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
+
+// millis formats d in milliseconds, e.g. 12.3ms.
+func millis(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+}
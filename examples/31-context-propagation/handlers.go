@@ -0,0 +1,159 @@
+package contextprop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// VIBE CODING: Blocking calls, no cancellation
+func vibePage(ctx context.Context, d *deps, id int) (string, error) {
+	/*
+	   Build the page for user id: look the user up, then their orders.
+
+	   Call one dependency, then the other, and return what they say.
+	   When they answer in a millisecond, so does this. But ctx is
+	   never looked at: when a dependency hangs for 100ms, the request
+	   takes 100ms, whatever the caller's deadline. The caller - a
+	   client that gave up, a server shutting down - can't stop it, and
+	   one failed call fails the page.
+	*/
+	user, err := d.users.Get(id)
+	if err != nil {
+		return "", err
+	}
+	orders, err := d.orders.Get(id)
+	if err != nil {
+		return "", err
+	}
+	return user + ": " + orders, nil
+}
+
+// The human version gives each page humanBudget, and tries each call
+// humanAttempts times.
+const (
+	humanBudget   = 50 * time.Millisecond
+	humanAttempts = 3
+)
+
+// HUMAN CODING: A timeout around the blocking calls
+func humanPage(ctx context.Context, d *deps, id int) (string, error) {
+	/*
+	   Build the page for user id: look the user up, then their orders.
+
+	   Put a timeout on it: run the calls in a goroutine, and wait for
+	   them or the timeout, whichever comes first. Retry each failed
+	   call a couple of times too. The page now fails after 50ms
+	   instead of hanging.
+
+	   But the timeout is made from context.Background(), not ctx, so a
+	   caller with a shorter deadline still waits the whole 50ms. And
+	   nothing tells the calls to stop: when the timeout fires, the
+	   goroutine carries on, blocked in a dependency, then calling the
+	   next one, for a page nobody will read. Under load, the
+	   abandoned work piles up on the very dependencies that are
+	   already slow.
+	*/
+	ctx, cancel := context.WithTimeout(context.Background(), humanBudget)
+	defer cancel()
+
+	get := func(dep *dependency) (answer string, err error) {
+		for range humanAttempts {
+			if answer, err = dep.Get(id); err == nil {
+				return answer, nil
+			}
+		}
+		return "", err
+	}
+	type result struct {
+		page string
+		err  error
+	}
+	done := make(chan result, 1) // Buffered, so the goroutine can always finish
+	go func() {
+		user, err := get(d.users)
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		orders, err := get(d.orders)
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		done <- result{page: user + ": " + orders}
+	}()
+	select {
+	case r := <-done:
+		return r.page, r.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// The expert version gives each page at most expertBudget, less if the
+// caller's deadline is sooner, and pauses expertPause before its first
+// retry of a call, doubling each time after.
+const (
+	expertBudget = 50 * time.Millisecond
+	expertPause  = 2 * time.Millisecond
+)
+
+// EXPERT CODING: Pass ctx down, clean up, and retry only while there is time
+func expertPage(ctx context.Context, d *deps, id int) (string, error) {
+	/*
+	   Build the page for user id: look the user up, then their orders.
+
+	   Derive the page's deadline from the caller's ctx, so the sooner
+	   of the two wins, and pass ctx to every call. A dependency that
+	   hangs is abandoned the moment the deadline passes: the call
+	   returns, and nothing is left running. defer cancel() releases
+	   the timer as soon as the page is done, early or not.
+
+	   Retry transient failures, with growing, jittered pauses - but
+	   only while the deadline leaves time for the pause and another
+	   call. When it doesn't, fail at once with the real cause, rather
+	   than sleep into a deadline error that hides it.
+	*/
+	ctx, cancel := context.WithTimeout(ctx, expertBudget)
+	defer cancel()
+
+	user, err := getRetrying(ctx, d.users, id)
+	if err != nil {
+		return "", fmt.Errorf("page %d: %w", id, err)
+	}
+	orders, err := getRetrying(ctx, d.orders, id)
+	if err != nil {
+		return "", fmt.Errorf("page %d: %w", id, err)
+	}
+	return user + ": " + orders, nil
+}
+
+// getRetrying calls dep for id until it answers, retrying transient
+// failures for as long as ctx's deadline leaves time to.
+func getRetrying(ctx context.Context, dep *dependency, id int) (string, error) {
+	pause := expertPause
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		answer, err := dep.GetContext(ctx, id)
+		if err == nil || !errors.Is(err, errTransient) {
+			return answer, err
+		}
+		wait := rand.N(pause) // full jitter
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait+latency {
+			return "", err // No time to try again
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return "", errors.Join(ctx.Err(), err)
+		}
+		pause *= 2
+	}
+}
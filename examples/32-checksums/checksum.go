@@ -0,0 +1,88 @@
+package checksums
+
+import (
+	"crypto/sha256"
+	"hash/crc32"
+)
+
+// The 32-bit FNV-1a parameters.
+const (
+	fnvOffset32 = 2166136261
+	fnvPrime32  = 16777619
+)
+
+// VIBE CODING: Hand-rolled FNV-1a, a byte at a time
+func vibeChecksum(data []byte) uint32 {
+	/*
+	   Checksum data, so a copy can be checked against it.
+
+	   FNV-1a is the hash everyone can write from memory: for each
+	   byte, xor it in and multiply by a prime. Four lines, no tables,
+	   no imports.
+
+	   But every multiply has to wait for the one before it, so it runs
+	   at a byte every few cycles, however wide the CPU. And it is a
+	   hash for tables, not a checksum: nothing guarantees that a
+	   corrupted copy gets a different value. Short, different strings
+	   collide in practice.
+	*/
+	h := uint32(fnvOffset32)
+	for _, b := range data {
+		h ^= uint32(b)
+		h *= fnvPrime32
+	}
+	return h
+}
+
+// ieeeTable is the standard library's table for the IEEE polynomial,
+// the CRC-32 of Ethernet, gzip and PNG.
+var ieeeTable = crc32.MakeTable(crc32.IEEE)
+
+// HUMAN CODING: CRC-32 with the standard library's table
+func humanChecksum(data []byte) uint32 {
+	/*
+	   Use a real error-detecting code: CRC-32, as in the textbook
+	   loop, one table lookup per byte with the table from hash/crc32.
+
+	   A CRC is designed for the job. It detects every error of 1, 2
+	   or 3 flipped bits, and every burst of errors up to 32 bits long,
+	   in messages of kilobytes. And its value is the same as every
+	   other CRC-32 of the IEEE polynomial, so it can be checked
+	   against zip files or network frames.
+
+	   But a byte at a time, each lookup waiting on the last, it is no
+	   faster than FNV.
+	*/
+	crc := ^uint32(0)
+	for _, b := range data {
+		crc = ieeeTable[byte(crc)^b] ^ crc>>8
+	}
+	return ^crc
+}
+
+// EXPERT CODING: The standard library, which uses the CPU's instructions
+func expertChecksum(data []byte) uint32 {
+	/*
+	   The same CRC-32, from crc32.ChecksumIEEE. On amd64 and arm64 it
+	   uses the CPU's own instructions for CRCs, carry-less multiplies
+	   on amd64, folding 64 bytes at a time; elsewhere, it looks up 8
+	   bytes at a time in 8 tables. Either way it is many times faster
+	   than a loop over bytes, for the same value.
+	*/
+	return crc32.ChecksumIEEE(data)
+}
+
+// EXPERT CODING: SHA-256, when the data might be forged
+func expertSHA256(data []byte) [sha256.Size]byte {
+	/*
+	   A CRC catches accidents, not attacks: anyone can change data and
+	   fix up four bytes to keep its CRC. When the data could be
+	   tampered with - downloads, content addresses - use a
+	   cryptographic hash, which no one knows how to collide.
+
+	   crypto/sha256 uses the CPU's SHA instructions where it has them,
+	   so it costs less than you might think - but still more than a
+	   CRC, for 8 times the bits.
+	*/
+	return sha256.Sum256(data)
+}
@@ -0,0 +1,328 @@
+// Package checksums compares three ways to checksum a buffer - a
+// hand-rolled FNV-1a hash, a CRC-32 from a lookup table, and the
+// standard library's CRC-32 and SHA-256, which use the CPU's own
+// instructions - and the errors each one is sure to catch.
+package checksums
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"hash/fnv"
+	"io"
+	"iter"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	sum              func(b, data []byte) []byte // Appends data's checksum to b, big-endian
+	ref              func() hash.Hash            // The standard library's version, to check against
+	vector, want     string                      // A published test vector, and its checksum
+}{
+	{"Vibe coding", "FNV-1a, a byte at a time",
+		func(b, data []byte) []byte { return binary.BigEndian.AppendUint32(b, vibeChecksum(data)) },
+		func() hash.Hash { return fnv.New32a() }, "a", "e40c292c"},
+	{"Human coding", "CRC-32, a lookup per byte",
+		func(b, data []byte) []byte { return binary.BigEndian.AppendUint32(b, humanChecksum(data)) },
+		func() hash.Hash { return crc32.NewIEEE() }, "123456789", "cbf43926"},
+	{"Expert coding", "CRC-32, CPU instructions",
+		func(b, data []byte) []byte { return binary.BigEndian.AppendUint32(b, expertChecksum(data)) },
+		func() hash.Hash { return crc32.NewIEEE() }, "123456789", "cbf43926"},
+	{"Expert SHA-256", "SHA-256, CPU instructions",
+		func(b, data []byte) []byte { s := expertSHA256(data); return append(b, s[:]...) },
+		sha256.New, "abc", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+}
+
+// makeData returns n bytes of random data.
+func makeData(seed input.Seed, n int) []byte {
+	rng := seed.Rand("data", n)
+	data := make([]byte, 0, n+7)
+	for len(data) < n {
+		data = binary.LittleEndian.AppendUint64(data, rng.Uint64())
+	}
+	return data[:n]
+}
+
+// checksummers returns the implementations timed checksumming data.
+// Each run stores its checksum in sums, without allocating.
+func checksummers(data []byte, sums [][]byte) []bench.Implementation {
+	impls := make([]bench.Implementation, len(tiers))
+	for i, t := range tiers {
+		sums[i] = make([]byte, 0, sha256.Size)
+		impls[i] = bench.Implementation{
+			Name: t.name, Complexity: t.complexity,
+			Run: func() { sums[i] = t.sum(sums[i][:0], data) },
+		}
+	}
+	return impls
+}
+
+// impls returns the implementations timed checksumming n bytes.
+func impls(n int) []bench.Implementation {
+	return checksummers(makeData(input.DefaultSeed, n), make([][]byte, len(tiers)))
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "hand-rolled FNV-1a", Complexity: "O(n), a multiply per byte", Notes: []report.Note{
+			report.Strength("Four lines, no tables and no imports"),
+			report.Pitfall("A hash for tables, not a checksum: it guarantees to catch no errors"),
+			report.Pitfall("Short, different strings collide"),
+			report.Pitfall("Each multiply waits for the last: a byte every few cycles"),
+		}},
+		{Label: "Human coding", Approach: "CRC-32 with hash/crc32's table", Complexity: "O(n), a lookup per byte", Notes: []report.Note{
+			report.Strength("Catches every burst of errors up to 32 bits, and every 1, 2 or 3 flipped bits"),
+			report.Strength("The standard CRC-32: the same value as zip, gzip, PNG and Ethernet"),
+			report.Pitfall("A byte at a time, it is no faster than FNV"),
+		}},
+		{Label: "Expert coding", Approach: "crc32.ChecksumIEEE", Complexity: "O(n), 64 bytes per step", Notes: []report.Note{
+			report.Strength("The same CRC, many times faster, from the CPU's own instructions"),
+			report.Pitfall("Catches accidents, not attacks: anyone can forge data with a given CRC"),
+		}},
+		{Label: "Expert SHA-256", Approach: "sha256.Sum256", Complexity: "O(n), 64-byte blocks", Notes: []report.Note{
+			report.Strength("No one knows how to find two inputs with the same SHA-256"),
+			report.Strength("Uses the CPU's SHA instructions where it has them"),
+			report.Pitfall("Slower than a CRC, and 32 bytes to store instead of 4"),
+		}},
+	},
+	Takeaway: "Pick the checksum for the errors it must catch - a CRC for " +
+		"accidents, a cryptographic hash for attackers - and take it from " +
+		"the standard library, which uses the CPU's instructions for both.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "32-checksums",
+		Title:       "Checksums",
+		Description: "Checksum a buffer with a hand-rolled FNV-1a, a table-driven CRC-32 and the standard library's CRC-32 and SHA-256, in MB/s, and test which errors each one catches.",
+		Category:    "performance",
+		Difficulty:  examples.Beginner,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    1_000_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("32-checksums", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{1_000, 1_000_000, 100_000_000}
+	fs.Var(&sizes, "n", "comma-separated buffer sizes in bytes, e.g. 4096,1e9")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Checksums", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Checksums")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "Running on %s/%s\n", runtime.GOOS, runtime.GOARCH)
+
+	for _, n := range sizes {
+		data := makeData(*seed, n)
+		fmt.Fprintf(out.Table, "\nChecksumming %s of random data (seed %d):\n", bench.FormatBytes(uint64(n)), *seed)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		// Each implementation computes a different checksum, so each is
+		// checked against its own version in the standard library.
+		sums := make([][]byte, len(tiers))
+		results, err := bench.CompareContext(ctx, opts, checksummers(data, sums)...)
+		if err != nil {
+			return err
+		}
+		for i, t := range tiers {
+			h := t.ref()
+			h.Write(data)
+			if want := h.Sum(nil); !slices.Equal(sums[i], want) {
+				return fmt.Errorf("%s: checksum %x, want %x", t.name, sums[i], want)
+			}
+		}
+		fmt.Fprintln(w, "✔ Every checksum matches the standard library's")
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %s", bench.FormatBytes(uint64(n))), results)
+
+		fmt.Fprintln(out.Table, "\nThroughput:")
+		for i, r := range results {
+			mbps := float64(n) / 1e6 / r.Duration.Seconds()
+			fmt.Fprintf(out.Table, "  %-15s %9.1f MB/s   %s\n", r.Name+":", mbps, hex.EncodeToString(sums[i]))
+			section.Notes = append(section.Notes, fmt.Sprintf("%s: %.1f MB/s", r.Name, mbps))
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing: which changes each checksum catches")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	sumOf := func(sum func(b, data []byte) []byte, data []byte) string {
+		return hex.EncodeToString(sum(nil, data))
+	}
+	fmt.Fprintln(w, "the published test vector (want: the published checksum):")
+	for _, t := range tiers {
+		got := sumOf(t.sum, []byte(t.vector))
+		status := "✅"
+		if got != t.want {
+			status = "❌"
+		}
+		result := fmt.Sprintf("%q → %s", t.vector, got)
+		fmt.Fprintf(w, "  %-15s %s %s\n", t.name+":", status, result)
+		rep.AddEdgeCase(t.name+", the published test vector", status+" "+result)
+	}
+
+	kib := makeData(*seed, 1024)
+	edgeCases := []struct {
+		desc  string
+		pairs iter.Seq2[[]byte, []byte] // Inputs that must get different checksums
+	}{
+		{"empty vs one zero byte", one(nil, []byte{0})},
+		{"1 KiB vs each of its 8,192 one-bit flips", bitFlips(kib)},
+		{"1 KiB vs each swap of two adjacent, different bytes", swaps(kib)},
+		{`"costarring" vs "liquid"`, one([]byte("costarring"), []byte("liquid"))},
+		{`"plumless" vs "buckeroo"`, one([]byte("plumless"), []byte("buckeroo"))},
+		{`1 KiB vs it with its last 8 bytes xored with "plumless" ^ "buckeroo"`, one(kib, forge(kib))},
+	}
+	for _, tc := range edgeCases {
+		fmt.Fprintf(w, "%s (want: different checksums):\n", tc.desc)
+		for _, t := range tiers {
+			pairs, same := 0, 0
+			var a, b []byte
+			for x, y := range tc.pairs {
+				a, b = t.sum(a[:0], x), t.sum(b[:0], y)
+				pairs++
+				if slices.Equal(a, b) {
+					same++
+				}
+			}
+			status := "✅"
+			if same > 0 {
+				status = "❌"
+			}
+			var result string
+			switch {
+			case pairs > 1 && same == 0:
+				result = fmt.Sprintf("all %d differ", pairs)
+			case pairs > 1:
+				result = fmt.Sprintf("%d of %d are the same", same, pairs)
+			case same == 0:
+				result = fmt.Sprintf("%x vs %x", a, b)
+			default:
+				result = fmt.Sprintf("both %x", a)
+			}
+			fmt.Fprintf(w, "  %-15s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
+
+// one yields the single pair a, b.
+func one(a, b []byte) iter.Seq2[[]byte, []byte] {
+	return func(yield func([]byte, []byte) bool) { yield(a, b) }
+}
+
+// bitFlips yields data paired with each copy of it with one bit flipped.
+func bitFlips(data []byte) iter.Seq2[[]byte, []byte] {
+	return func(yield func([]byte, []byte) bool) {
+		flipped := slices.Clone(data)
+		for i := range len(data) * 8 {
+			flipped[i/8] ^= 1 << (i % 8)
+			if !yield(data, flipped) {
+				return
+			}
+			flipped[i/8] ^= 1 << (i % 8)
+		}
+	}
+}
+
+// swaps yields data paired with each copy of it with two adjacent bytes
+// swapped, skipping pairs of equal bytes.
+func swaps(data []byte) iter.Seq2[[]byte, []byte] {
+	return func(yield func([]byte, []byte) bool) {
+		swapped := slices.Clone(data)
+		for i := range len(data) - 1 {
+			if data[i] == data[i+1] {
+				continue
+			}
+			swapped[i], swapped[i+1] = swapped[i+1], swapped[i]
+			if !yield(data, swapped) {
+				return
+			}
+			swapped[i], swapped[i+1] = swapped[i+1], swapped[i]
+		}
+	}
+}
+
+// forge returns a copy of data, of at least 8 bytes, changed but with
+// the same CRC-32. A CRC is linear: xoring a message with a pattern
+// changes its CRC by an amount that depends only on the pattern and
+// where it ends. "plumless" and "buckeroo" have the same CRC-32, so
+// their xor, as the last 8 bytes, changes it by nothing.
+func forge(data []byte) []byte {
+	forged := slices.Clone(data)
+	tail := forged[len(forged)-8:]
+	for i := range tail {
+		tail[i] ^= "plumless"[i] ^ "buckeroo"[i]
+	}
+	return forged
+}
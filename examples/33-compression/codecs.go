@@ -0,0 +1,200 @@
+package compression
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/lzw"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// codec is a way to compress data, and to undo it.
+type codec struct {
+	name       string
+	compress   func(data []byte) ([]byte, error)
+	decompress func(data []byte) ([]byte, error)
+}
+
+// codecs are the codecs compared, fastest first, roughly.
+var codecs = []codec{
+	{"LZ, Snappy-style", lzCompress, lzDecompress},
+	{"flate -1", flateCompress(flate.BestSpeed), inflate},
+	{"gzip -1", gzipCompress(gzip.BestSpeed), gunzip},
+	{"gzip -6", gzipCompress(gzip.DefaultCompression), gunzip},
+	{"gzip -9", gzipCompress(gzip.BestCompression), gunzip},
+	{"Huffman only", gzipCompress(gzip.HuffmanOnly), gunzip},
+	{"LZW", lzwCompress, lzwDecompress},
+}
+
+// stored is no compression at all.
+var stored = codec{"none", func(data []byte) ([]byte, error) { return data, nil }, func(data []byte) ([]byte, error) { return data, nil }}
+
+// gzipCompress returns a function compressing with gzip at level.
+func gzipCompress(level int) func([]byte) ([]byte, error) {
+	return func(data []byte) ([]byte, error) {
+		var buf bytes.Buffer
+		zw, err := gzip.NewWriterLevel(&buf, level)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := zw.Write(data); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+}
+
+// gunzip decompresses gzip data, checking its CRC-32.
+func gunzip(data []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(zr)
+}
+
+// flateCompress returns a function compressing with raw DEFLATE at
+// level: gzip without its header and CRC-32.
+func flateCompress(level int) func([]byte) ([]byte, error) {
+	return func(data []byte) ([]byte, error) {
+		var buf bytes.Buffer
+		zw, err := flate.NewWriter(&buf, level)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := zw.Write(data); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+}
+
+// inflate decompresses raw DEFLATE data.
+func inflate(data []byte) ([]byte, error) {
+	return io.ReadAll(flate.NewReader(bytes.NewReader(data)))
+}
+
+// lzwCompress compresses with LZW, the algorithm of Unix compress and
+// GIF.
+func lzwCompress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := lzw.NewWriter(&buf, lzw.MSB, 8)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func lzwDecompress(data []byte) ([]byte, error) {
+	return io.ReadAll(lzw.NewReader(bytes.NewReader(data), lzw.MSB, 8))
+}
+
+// The LZ codec writes its input's length as a uvarint, then a sequence
+// of tags. A tag byte below 0x80 is followed by that many plus one
+// literal bytes. A tag byte of 0x80 or more copies its low 7 bits plus
+// lzMinMatch bytes from an offset back in the output, given in the next
+// two bytes, little-endian.
+const (
+	lzMinMatch   = 4
+	lzMaxMatch   = 0x7f + lzMinMatch
+	lzMaxLiteral = 0x80
+	lzMaxOffset  = 1<<16 - 1
+	lzHashBits   = 14
+)
+
+var errCorrupt = errors.New("lz: corrupt input")
+
+// lzCompress compresses data the way Snappy and LZ4 do: it finds
+// repeats of 4 or more bytes with a hash table, and writes them as
+// byte-aligned copies, with no entropy coding. Where it finds no
+// repeats it looks less and less often, so incompressible data goes
+// by quickly.
+func lzCompress(data []byte) ([]byte, error) {
+	dst := binary.AppendUvarint(make([]byte, 0, len(data)+len(data)/lzMaxLiteral+16), uint64(len(data)))
+	var table [1 << lzHashBits]int32 // One more than the last position of the 4 bytes hashing to each slot
+	lit, skip := 0, 32
+	for i := 0; i+lzMinMatch <= len(data); {
+		v := binary.LittleEndian.Uint32(data[i:])
+		h := v * 0x1e35a7bd >> (32 - lzHashBits)
+		cand := int(table[h]) - 1
+		table[h] = int32(i + 1)
+		if cand < 0 || i-cand > lzMaxOffset || binary.LittleEndian.Uint32(data[cand:]) != v {
+			i += skip >> 5
+			skip++
+			continue
+		}
+		skip = 32
+		n := lzMinMatch
+		for i+n < len(data) && n < lzMaxMatch && data[cand+n] == data[i+n] {
+			n++
+		}
+		dst = lzLiterals(dst, data[lit:i])
+		dst = append(dst, byte(0x80|(n-lzMinMatch)), byte(i-cand), byte((i-cand)>>8))
+		i += n
+		lit = i
+	}
+	return lzLiterals(dst, data[lit:]), nil
+}
+
+// lzLiterals appends lit to dst as literal runs.
+func lzLiterals(dst, lit []byte) []byte {
+	for len(lit) > 0 {
+		n := min(len(lit), lzMaxLiteral)
+		dst = append(dst, byte(n-1))
+		dst = append(dst, lit[:n]...)
+		lit = lit[n:]
+	}
+	return dst
+}
+
+func lzDecompress(src []byte) ([]byte, error) {
+	size, k := binary.Uvarint(src)
+	if k <= 0 || size > uint64(len(src))*lzMaxMatch {
+		return nil, errCorrupt
+	}
+	dst := make([]byte, 0, size)
+	for i := k; i < len(src); {
+		t := src[i]
+		i++
+		if t < 0x80 {
+			n := int(t) + 1
+			if i+n > len(src) {
+				return nil, errCorrupt
+			}
+			dst = append(dst, src[i:i+n]...)
+			i += n
+			continue
+		}
+		if i+2 > len(src) {
+			return nil, errCorrupt
+		}
+		n, off := int(t&0x7f)+lzMinMatch, int(src[i])|int(src[i+1])<<8
+		i += 2
+		if off == 0 || off > len(dst) {
+			return nil, errCorrupt
+		}
+		if off >= n {
+			start := len(dst) - off
+			dst = append(dst, dst[start:start+n]...)
+			continue
+		}
+		for range n { // The copy overlaps what it writes: a run
+			dst = append(dst, dst[len(dst)-off])
+		}
+	}
+	if uint64(len(dst)) != size {
+		return nil, errCorrupt
+	}
+	return dst, nil
+}
@@ -0,0 +1,142 @@
+package compression
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"time"
+)
+
+// VIBE CODING: Always the best compression
+func vibeCompress(data []byte, link float64) ([]byte, error) {
+	/*
+	   Compress data before sending it over a link of link bits per
+	   second.
+
+	   Smaller is better, so ask gzip for its best: level 9. It is
+	   right whenever the link is slow and the data compresses well.
+
+	   But level 9 is several times slower than level 6, for output a
+	   few percent smaller. On a fast link, the time spent
+	   compressing is more than the time saved sending, and random or
+	   already-compressed data is slowed down for nothing at all.
+	*/
+	var buf bytes.Buffer
+	zw, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	zw.Write(data)
+	zw.Close()
+	return buf.Bytes(), nil
+}
+
+// HUMAN CODING: The default level
+func humanCompress(data []byte, link float64) ([]byte, error) {
+	/*
+	   Compress data before sending it over a link of link bits per
+	   second.
+
+	   gzip.NewWriter's default, level 6, is a compromise chosen by
+	   people who measured: most of level 9's ratio, several times as
+	   fast. Errors are checked, though writing to a bytes.Buffer
+	   can't fail.
+
+	   But it is the same compromise for every link and every input. On
+	   a fast link, even level 6 costs more time than it saves, and it
+	   still works through incompressible data byte by byte.
+	*/
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// expertCodecs are the codecs the expert version chooses from. The
+// index of the one chosen is the first byte of its output.
+var expertCodecs = []codec{
+	stored,
+	{"LZ, Snappy-style", lzCompress, lzDecompress},
+	{"flate -1", flateCompress(flate.BestSpeed), inflate},
+	{"flate -6", flateCompress(flate.DefaultCompression), inflate},
+	{"flate -9", flateCompress(flate.BestCompression), inflate},
+}
+
+// expertSample is how much of its input the expert version tries every
+// codec on.
+const expertSample = 64 << 10
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// EXPERT CODING: Measure, and choose for the link
+func expertCompress(data []byte, link float64) ([]byte, error) {
+	/*
+	   Compress data before sending it over a link of link bits per
+	   second.
+
+	   What matters is when the data arrives: the time to compress it
+	   plus the time to send what comes out. So measure it. Compress
+	   the first 64 KiB with every codec, from none at all to flate
+	   -9, time each, and pick the one whose compressing and sending,
+	   scaled up to all the data, is quickest. A slow link buys
+	   ratio, a fast one buys speed, and random data isn't compressed
+	   at all.
+
+	   The output starts with which codec was chosen and ends with a
+	   CRC-32C of the data, as Snappy's framing format does, so the
+	   receiver can decode it and check it. Raw DEFLATE, not gzip,
+	   since the frame already has a checksum.
+	*/
+	sample := data[:min(len(data), expertSample)]
+	scale := float64(len(data)) / float64(max(len(sample), 1))
+	best, bestTime, out := 0, 0.0, []byte(nil)
+	for id, c := range expertCodecs {
+		start := time.Now()
+		compressed, err := c.compress(sample)
+		if err != nil {
+			return nil, err
+		}
+		total := (time.Since(start).Seconds() + float64(len(compressed))*8/link) * scale
+		if id == 0 || total < bestTime {
+			best, bestTime, out = id, total, compressed
+		}
+	}
+	if len(sample) < len(data) {
+		var err error
+		if out, err = expertCodecs[best].compress(data); err != nil {
+			return nil, err
+		}
+	}
+	frame := make([]byte, 0, 1+len(out)+4)
+	frame = append(frame, byte(best))
+	frame = append(frame, out...)
+	return binary.BigEndian.AppendUint32(frame, crc32.Checksum(data, castagnoli)), nil
+}
+
+// expertDecompress undoes expertCompress, checking the data's CRC-32C.
+func expertDecompress(frame []byte) ([]byte, error) {
+	if len(frame) < 5 || int(frame[0]) >= len(expertCodecs) {
+		return nil, errors.New("corrupt frame")
+	}
+	data, err := expertCodecs[frame[0]].decompress(frame[1 : len(frame)-4])
+	if err != nil {
+		return nil, err
+	}
+	if crc32.Checksum(data, castagnoli) != binary.BigEndian.Uint32(frame[len(frame)-4:]) {
+		return nil, errors.New("checksum mismatch")
+	}
+	return data, nil
+}
+
+// expertChoice is the name of the codec expertCompress chose for frame.
+func expertChoice(frame []byte) string {
+	if len(frame) == 0 || int(frame[0]) >= len(expertCodecs) {
+		return "?"
+	}
+	return expertCodecs[frame[0]].name
+}
@@ -0,0 +1,477 @@
+// Package compression compares three ways to choose a compressor for
+// data sent over a link: always the best compression, always the
+// default, and measuring which one gets the data there soonest.
+package compression
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// corpus is the source of the text compressed: the Declaration of
+// Independence and the Gettysburg Address, both in the public domain.
+//
+//go:embed corpus.txt
+var corpus string
+
+// makeText returns n bytes of English text: corpus's words in random
+// order, as often as each appears in it, in lines of up to 72
+// characters. Unlike corpus repeated, it doesn't repeat itself, so it
+// compresses about as well as real prose.
+func makeText(seed input.Seed, n int) []byte {
+	rng := seed.Rand("text", n)
+	words := strings.Fields(corpus)
+	b := make([]byte, 0, n+80)
+	line := 0
+	for len(b) < n {
+		w := words[rng.IntN(len(words))]
+		switch {
+		case line == 0:
+		case line+1+len(w) > 72:
+			b = append(b, '\n')
+			line = 0
+		default:
+			b = append(b, ' ')
+			line++
+		}
+		b = append(b, w...)
+		line += len(w)
+	}
+	return b[:n]
+}
+
+var (
+	methods    = []string{"GET", "GET", "GET", "POST", "PUT", "DELETE"}
+	resources  = []string{"users", "orders", "products", "carts", "reviews"}
+	statuses   = []int{200, 200, 200, 201, 304, 404, 500}
+	userAgents = []string{
+		`"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0 Safari/537.36"`,
+		`"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_6) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.0 Safari/605.1.15"`,
+		`"curl/8.5.0"`,
+	}
+)
+
+// makeLogs returns n bytes of a web server's access log.
+func makeLogs(seed input.Seed, n int) []byte {
+	rng := seed.Rand("logs", n)
+	b := make([]byte, 0, n+300)
+	for i := 0; len(b) < n; i++ {
+		secs := i / 20 % 86400
+		b = fmt.Appendf(b, "2026-10-15T%02d:%02d:%02dZ %s /api/v1/%s/%d %d %d %s\n",
+			secs/3600, secs/60%60, secs%60,
+			methods[rng.IntN(len(methods))],
+			resources[rng.IntN(len(resources))], rng.IntN(10_000),
+			statuses[rng.IntN(len(statuses))], 200+rng.IntN(20_000),
+			userAgents[rng.IntN(len(userAgents))])
+	}
+	return b[:n]
+}
+
+// makeRandom returns n random bytes, as incompressible as data gets -
+// and as data already compressed or encrypted is.
+func makeRandom(seed input.Seed, n int) []byte {
+	rng := seed.Rand("random", n)
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(rng.Uint32())
+	}
+	return b
+}
+
+// datasets are the kinds of data compressed.
+var datasets = []struct {
+	name string
+	make func(seed input.Seed, n int) []byte
+}{
+	{"English text", makeText},
+	{"server logs", makeLogs},
+	{"random bytes", makeRandom},
+}
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	compress         func(data []byte, link float64) ([]byte, error)
+	decompress       func(data []byte) ([]byte, error)
+}{
+	{"Vibe coding", "gzip -9, always", vibeCompress, gunzip},
+	{"Human coding", "gzip -6, always", humanCompress, gunzip},
+	{"Expert coding", "measured for the link", expertCompress, expertDecompress},
+}
+
+// defaultLink is the link the expert version chooses for, in bits per
+// second, unless -link says otherwise.
+const defaultLink = 100e6
+
+// sweepLinks are the link speeds, in Mbit/s, every codec's delivery
+// time is shown for.
+var sweepLinks = []int{10, 100, 1000}
+
+// impls returns the implementations timed compressing n bytes of text.
+func impls(n int) []bench.Implementation {
+	data := makeText(input.DefaultSeed, n)
+	list := make([]bench.Implementation, len(tiers))
+	for i, t := range tiers {
+		list[i] = bench.Implementation{
+			Name: t.name, Complexity: t.complexity,
+			RunContext: func(context.Context) error {
+				_, err := t.compress(data, defaultLink)
+				return err
+			},
+		}
+	}
+	return list
+}
+
+// decompressTimes times decompress[i] undoing each compressed[i], and
+// checks that it gives data back.
+func decompressTimes(ctx context.Context, opts bench.Options, names []string, decompress []func([]byte) ([]byte, error), compressed [][]byte, data []byte) ([]time.Duration, error) {
+	got := make([][]byte, len(names))
+	impls := make([]bench.Implementation, len(names))
+	for i, name := range names {
+		impls[i] = bench.Implementation{
+			Name: name,
+			RunContext: func(context.Context) (err error) {
+				got[i], err = decompress[i](compressed[i])
+				return err
+			},
+		}
+	}
+	results, err := bench.CompareContext(ctx, opts, impls...)
+	if err != nil {
+		return nil, err
+	}
+	times := make([]time.Duration, len(results))
+	for i, r := range results {
+		if !bytes.Equal(got[i], data) {
+			return nil, fmt.Errorf("%s: decompressed %d bytes, not the %d compressed", names[i], len(got[i]), len(data))
+		}
+		times[i] = r.Duration
+	}
+	return times, nil
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "gzip at level 9, always", Complexity: "the best ratio, the slowest", Notes: []report.Note{
+			report.Strength("The smallest gzip output: right for slow links and archives"),
+			report.Pitfall("Several times slower than level 6, for output a few percent smaller"),
+			report.Pitfall("On a fast link, compressing takes longer than sending would have"),
+			report.Pitfall("Spends the most time on data that doesn't compress at all"),
+		}},
+		{Label: "Human coding", Approach: "gzip.NewWriter's default, level 6", Complexity: "a fixed compromise", Notes: []report.Note{
+			report.Strength("Most of level 9's ratio, in much less time"),
+			report.Strength("Checks its errors"),
+			report.Pitfall("The same choice for every link and every kind of data"),
+		}},
+		{Label: "Expert coding", Approach: "tries every codec on a sample, keeps the quickest to deliver", Complexity: "64 KiB × 5 codecs extra", Notes: []report.Note{
+			report.Strength("Optimizes what matters: compressing plus sending"),
+			report.Strength("A slow link buys ratio, a fast link buys speed, random data is sent as is"),
+			report.Strength("A CRC-32C in every frame catches corruption, whichever codec was used"),
+			report.Pitfall("A sample can mislead when data changes part-way through"),
+			report.Pitfall("A framing format of its own, which both ends must agree on"),
+		}},
+	},
+	Takeaway: "There is no best compressor, only a best one for a link and a " +
+		"kind of data: ratio costs time. Decide what you are minimizing - " +
+		"bytes stored, or time until the data arrives - and measure.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "33-compression",
+		Title:       "Compression Trade-offs",
+		Description: "Compress text, logs and random data with gzip levels, raw DEFLATE, LZW and a Snappy-style LZ, and compare ratio against throughput and delivery time over a link.",
+		Category:    "performance",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    1_000_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("33-compression", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{4_000_000}
+	fs.Var(&sizes, "n", "comma-separated sizes of data to compress, in bytes, e.g. 1e5,1e7")
+	linkMbps := fs.Int("link", defaultLink/1e6, "speed of the link the data is sent over, in Mbit/s")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *linkMbps < 1 {
+		return fmt.Errorf("-link must be at least 1 Mbit/s, got %d", *linkMbps)
+	}
+	link := float64(*linkMbps) * 1e6
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Compression Trade-offs", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Compression Trade-offs")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	for _, n := range sizes {
+		for _, ds := range datasets {
+			data := ds.make(*seed, n)
+			title := fmt.Sprintf("%s of %s", bench.FormatBytes(uint64(n)), ds.name)
+			fmt.Fprintf(out.Table, "\nCompressing %s (seed %d), to send over a %d Mbit/s link:\n", title, *seed, *linkMbps)
+			fmt.Fprintln(w, strings.Repeat("-", 60))
+
+			compressed := make([][]byte, len(tiers))
+			impls := make([]bench.Implementation, len(tiers))
+			names := make([]string, len(tiers))
+			decompress := make([]func([]byte) ([]byte, error), len(tiers))
+			for i, t := range tiers {
+				impls[i] = bench.Implementation{
+					Name: t.name, Complexity: t.complexity,
+					RunContext: func(context.Context) (err error) {
+						compressed[i], err = t.compress(data, link)
+						return err
+					},
+				}
+				names[i], decompress[i] = t.name, t.decompress
+			}
+			results, err := bench.CompareContext(ctx, opts, impls...)
+			if err != nil {
+				return err
+			}
+			unpack, err := decompressTimes(ctx, opts, names, decompress, compressed, data)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(w, "✔ Every output decompresses to the input")
+			if err := csvLog.Append(ds.name, uint64(n), results); err != nil {
+				return fmt.Errorf("csv: %w", err)
+			}
+			benchLog.Append(ds.name, uint64(n), results)
+			bench.Print(out.Table, results)
+			report.WriteBars(w, results)
+			bench.PrintRuns(out.Detail, results)
+			section := rep.Add(title, results)
+
+			fmt.Fprintf(out.Table, "\nDelivered over %d Mbit/s: ratio, compress + send + decompress:\n", *linkMbps)
+			for i, r := range results {
+				send := sendTime(len(compressed[i]), link)
+				total := r.Duration + send + unpack[i]
+				chose := ""
+				if i == len(tiers)-1 {
+					chose = "   chose " + expertChoice(compressed[i])
+				}
+				fmt.Fprintf(out.Table, "  %-14s %6.2fx   %9s + %9s + %9s = %9s%s\n",
+					r.Name+":", float64(n)/float64(len(compressed[i])),
+					millis(r.Duration), millis(send), millis(unpack[i]), millis(total), chose)
+				section.Notes = append(section.Notes, fmt.Sprintf("%s: %.2fx smaller, delivered in %s%s",
+					r.Name, float64(n)/float64(len(compressed[i])), millis(total), chose))
+			}
+
+			if err := sweep(ctx, out, opts, data); err != nil {
+				return err
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing: round trips and damaged data")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	text := makeText(*seed, 64<<10)
+	edgeCases := []struct {
+		desc   string
+		data   []byte
+		damage func(compressed []byte) []byte // What happens to the compressed data on the way, if anything
+		want   string
+		good   func(data, compressed, got []byte, err error) bool
+	}{
+		{"empty input", nil, nil, "round-trips",
+			func(data, _, got []byte, err error) bool { return err == nil && bytes.Equal(got, data) }},
+		{"one byte", []byte{'x'}, nil, "round-trips",
+			func(data, _, got []byte, err error) bool { return err == nil && bytes.Equal(got, data) }},
+		{"1 MiB of zeros", make([]byte, 1<<20), nil, "round-trips, 40x smaller or more",
+			func(data, compressed, got []byte, err error) bool {
+				return err == nil && bytes.Equal(got, data) && len(compressed)*40 <= len(data)
+			}},
+		{"64 KiB of random bytes", makeRandom(*seed, 64<<10), nil, "round-trips, no more than 5 bytes bigger",
+			func(data, compressed, got []byte, err error) bool {
+				return err == nil && bytes.Equal(got, data) && len(compressed) <= len(data)+5
+			}},
+		{"64 KiB of text, one bit flipped on the way", text, func(c []byte) []byte { c[len(c)/2] ^= 0x10; return c }, "decompressing fails",
+			func(_, _, _ []byte, err error) bool { return err != nil }},
+		{"64 KiB of text, the last byte lost on the way", text, func(c []byte) []byte { return c[:len(c)-1] }, "decompressing fails",
+			func(_, _, _ []byte, err error) bool { return err != nil }},
+	}
+	for _, tc := range edgeCases {
+		fmt.Fprintf(w, "%s (want: %s):\n", tc.desc, tc.want)
+		for _, t := range tiers {
+			compressed, err := t.compress(tc.data, link)
+			if err != nil {
+				return fmt.Errorf("%s: %w", t.name, err)
+			}
+			sent := bytes.Clone(compressed)
+			if tc.damage != nil {
+				sent = tc.damage(sent)
+			}
+			got, err := t.decompress(sent)
+
+			var result string
+			switch {
+			case err != nil:
+				result = "decompressing failed: " + err.Error()
+			case !bytes.Equal(got, tc.data):
+				result = fmt.Sprintf("decompressed to %d different bytes", len(got))
+			default:
+				result = fmt.Sprintf("round-trips, %d → %d bytes", len(tc.data), len(compressed))
+			}
+			status := "✅"
+			if !tc.good(tc.data, compressed, got, err) {
+				status = "❌"
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
+
+// sweep times every codec on data, and prints its ratio and speeds, a
+// codec at least as good on all three if there is one, and how long it
+// takes to deliver data over each of sweepLinks.
+func sweep(ctx context.Context, out examples.Output, opts bench.Options, data []byte) error {
+	compressed := make([][]byte, len(codecs))
+	impls := make([]bench.Implementation, len(codecs))
+	names := make([]string, len(codecs))
+	decompress := make([]func([]byte) ([]byte, error), len(codecs))
+	for i, c := range codecs {
+		impls[i] = bench.Implementation{
+			Name: c.name,
+			RunContext: func(context.Context) (err error) {
+				compressed[i], err = c.compress(data)
+				return err
+			},
+		}
+		names[i], decompress[i] = c.name, c.decompress
+	}
+	results, err := bench.CompareContext(ctx, opts, impls...)
+	if err != nil {
+		return err
+	}
+	unpack, err := decompressTimes(ctx, opts, names, decompress, compressed, data)
+	if err != nil {
+		return err
+	}
+	bench.PrintRuns(out.Detail, results)
+
+	n := float64(len(data))
+	ratio := func(i int) float64 { return n / float64(len(compressed[i])) }
+	speed := func(i int) float64 { return n / 1e6 / results[i].Duration.Seconds() }
+	unpackSpeed := func(i int) float64 { return n / 1e6 / unpack[i].Seconds() }
+	delivery := func(i int, mbps int) time.Duration {
+		return results[i].Duration + sendTime(len(compressed[i]), float64(mbps)*1e6) + unpack[i]
+	}
+
+	fmt.Fprintln(out.Table, "\nEvery codec: ratio, speed, and delivery time over each link (* quickest):")
+	fmt.Fprintf(out.Table, "  %-17s %7s %12s %12s", "", "ratio", "compress", "decompress")
+	for _, mbps := range sweepLinks {
+		fmt.Fprintf(out.Table, " %12s", fmt.Sprintf("%d Mbit/s", mbps))
+	}
+	fmt.Fprintln(out.Table)
+	quickest := make([]time.Duration, len(sweepLinks))
+	for j, mbps := range sweepLinks {
+		quickest[j] = sendTime(len(data), float64(mbps)*1e6)
+		for i := range codecs {
+			quickest[j] = min(quickest[j], delivery(i, mbps))
+		}
+	}
+	row := func(name string, r, compress, decompress string, times []time.Duration, note string) {
+		fmt.Fprintf(out.Table, "  %-17s %7s %12s %12s", name, r, compress, decompress)
+		for j, d := range times {
+			mark := " "
+			if d == quickest[j] {
+				mark = "*"
+			}
+			fmt.Fprintf(out.Table, " %11s%s", millis(d), mark)
+		}
+		fmt.Fprintln(out.Table, note)
+	}
+	times := make([]time.Duration, len(sweepLinks))
+	for j, mbps := range sweepLinks {
+		times[j] = sendTime(len(data), float64(mbps)*1e6)
+	}
+	row(stored.name, "1.00x", "-", "-", times, "")
+	for i, c := range codecs {
+		for j, mbps := range sweepLinks {
+			times[j] = delivery(i, mbps)
+		}
+		note := ""
+		for k := range codecs {
+			if k != i && ratio(k) >= ratio(i) && speed(k) >= speed(i) && unpackSpeed(k) >= unpackSpeed(i) {
+				note = "   dominated by " + codecs[k].name
+				break
+			}
+		}
+		row(c.name, fmt.Sprintf("%.2fx", ratio(i)), fmt.Sprintf("%.0f MB/s", speed(i)),
+			fmt.Sprintf("%.0f MB/s", unpackSpeed(i)), times, note)
+	}
+	return nil
+}
+
+// sendTime is how long size bytes take to send at link bits per second.
+func sendTime(size int, link float64) time.Duration {
+	return time.Duration(float64(size) * 8 / link * float64(time.Second))
+}
+
+// millis formats d in milliseconds, e.g. 12.3ms.
+func millis(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+}
@@ -0,0 +1,274 @@
+package serialization
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// VIBE CODING: JSON, because everything speaks it
+func vibeEncode(records []Record) ([]byte, error) {
+	/*
+	   Turn records into bytes, to store or send.
+
+	   json.Marshal is one line, and anyone can read the output or
+	   decode it in any language. The struct tags even make it pretty.
+
+	   But every number is printed as decimal text and parsed back,
+	   every field name is repeated in every record, and the decoder
+	   matches keys by reflection: the output is several times bigger
+	   than the data, and decoding is slow. JSON can't represent NaN,
+	   so one bad reading fails the whole batch, and strings that
+	   aren't valid UTF-8 come back changed.
+	*/
+	return json.Marshal(records)
+}
+
+func vibeDecode(data []byte) ([]Record, error) {
+	var records []Record
+	err := json.Unmarshal(data, &records)
+	return records, err
+}
+
+// HUMAN CODING: gob, Go's own binary format
+func humanEncode(records []Record) ([]byte, error) {
+	/*
+	   Use a binary format made for Go: encoding/gob. It sends the
+	   type's description once, then each record's fields as varints,
+	   so field names aren't repeated and numbers aren't printed. NaNs
+	   and odd strings round-trip, and fields can be added or removed
+	   later without breaking old data.
+
+	   But it still walks every value by reflection, so it is several
+	   times slower than packing the bytes by hand. It leaves out
+	   fields equal to zero, and -0 == 0, so a -0 comes back as 0. And
+	   only Go reads it.
+	*/
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func humanDecode(data []byte) ([]Record, error) {
+	var records []Record
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&records)
+	return records, err
+}
+
+// The expert format starts with its version, then the number of
+// records as a uvarint. Each record is then its ID and Time as varint
+// differences from the record before, its sensor as a uvarint index
+// into the sensors seen so far - followed by the name if it is a new
+// one - its Value as 8 bytes, little-endian, and its OK as a byte.
+const (
+	expertVersion   = 1
+	expertMinRecord = 1 + 1 + 1 + 8 + 1 // The smallest a record can be
+)
+
+var errTruncated = errors.New("serialization: truncated data")
+
+// EXPERT CODING: Pack the bytes by hand
+func expertEncode(records []Record) ([]byte, error) {
+	/*
+	   Write the format out by hand, appending to one buffer sized up
+	   front: no reflection and no allocation per record.
+
+	   And use what's known about the data. IDs count up and times
+	   increase, so store each as its difference from the last, a
+	   varint of a byte or a few. There are few sensors, so store each
+	   name once and refer to it by index after. Values are stored as
+	   their 8 bytes, exactly, NaN or not.
+
+	   The cost: the format is this code's alone, so it starts with a
+	   version to change it by, and the decoder must check every
+	   length it reads.
+	*/
+	buf := make([]byte, 0, 16+len(records)*expertMinRecord*3/2)
+	buf = append(buf, expertVersion)
+	buf = binary.AppendUvarint(buf, uint64(len(records)))
+	sensors := make(map[string]int)
+	var id uint64
+	var t int64
+	for _, r := range records {
+		buf = binary.AppendVarint(buf, int64(r.ID-id))
+		buf = binary.AppendVarint(buf, r.Time-t)
+		id, t = r.ID, r.Time
+		k, ok := sensors[r.Sensor]
+		if !ok {
+			k = len(sensors)
+			sensors[r.Sensor] = k
+		}
+		buf = binary.AppendUvarint(buf, uint64(k))
+		if !ok {
+			buf = binary.AppendUvarint(buf, uint64(len(r.Sensor)))
+			buf = append(buf, r.Sensor...)
+		}
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(r.Value))
+		var b byte
+		if r.OK {
+			b = 1
+		}
+		buf = append(buf, b)
+	}
+	return buf, nil
+}
+
+func expertDecode(data []byte) ([]Record, error) {
+	if len(data) == 0 || data[0] != expertVersion {
+		return nil, errors.New("serialization: not version 1 data")
+	}
+	d := decoder{data: data[1:]}
+	n := d.uvarint()
+	if d.err == nil && n > uint64(len(d.data)/expertMinRecord) {
+		return nil, fmt.Errorf("serialization: %d records can't fit in %d bytes", n, len(data))
+	}
+	records := make([]Record, 0, n)
+	var sensors []string
+	var id uint64
+	var t int64
+	for range n {
+		if d.err != nil {
+			break
+		}
+		id += uint64(d.varint())
+		t += d.varint()
+		r := Record{ID: id, Time: t}
+		k := d.uvarint()
+		switch {
+		case k < uint64(len(sensors)):
+			r.Sensor = sensors[k]
+		case k == uint64(len(sensors)):
+			r.Sensor = string(d.bytes(d.uvarint()))
+			sensors = append(sensors, r.Sensor)
+		default:
+			d.fail(fmt.Errorf("serialization: sensor %d of %d", k, len(sensors)))
+		}
+		r.Value = math.Float64frombits(binary.LittleEndian.Uint64(d.bytes(8)))
+		switch b := d.bytes(1); {
+		case d.err != nil:
+		case b[0] > 1:
+			d.fail(fmt.Errorf("serialization: OK is %d", b[0]))
+		default:
+			r.OK = b[0] == 1
+		}
+		records = append(records, r)
+	}
+	if d.err == nil && len(d.data) > 0 {
+		d.fail(fmt.Errorf("serialization: %d bytes after the last record", len(d.data)))
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return records, nil
+}
+
+// decoder reads the expert format from data. After its first error,
+// each read returns zeros and the error is kept in err.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+	d.data = nil
+}
+
+func (d *decoder) uvarint() uint64 {
+	x, k := binary.Uvarint(d.data)
+	if k <= 0 {
+		d.fail(errTruncated)
+		return 0
+	}
+	d.data = d.data[k:]
+	return x
+}
+
+func (d *decoder) varint() int64 {
+	x, k := binary.Varint(d.data)
+	if k <= 0 {
+		d.fail(errTruncated)
+		return 0
+	}
+	d.data = d.data[k:]
+	return x
+}
+
+// bytes returns the next n bytes, or n zeros if there aren't that many.
+func (d *decoder) bytes(n uint64) []byte {
+	if n > uint64(len(d.data)) {
+		d.fail(errTruncated)
+		return make([]byte, min(n, 8))
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+// fixedRecord is Record with a fixed size, as binary.Write needs: its
+// Sensor is cut to, or padded with zeros to, 16 bytes.
+type fixedRecord struct {
+	ID     uint64
+	Time   int64
+	Value  float64
+	Sensor [16]byte
+	OK     bool
+}
+
+// EXPERT CODING: binary.Write, fixed-size records
+func expertFixedEncode(records []Record) ([]byte, error) {
+	/*
+	   encoding/binary writes a struct of fixed-size fields as its raw
+	   bytes, like C: a layout other languages can read, with no
+	   parsing at all. Strings have no fixed size, so the sensor gets
+	   16 bytes, padded with zeros.
+
+	   But binary.Write finds the fields by reflection, for every
+	   record, so it is slower than packing them by hand. Every record
+	   takes 41 bytes, however little is in it. And a longer sensor
+	   name is cut short, silently.
+	*/
+	fixed := make([]fixedRecord, len(records))
+	for i, r := range records {
+		fixed[i] = fixedRecord{ID: r.ID, Time: r.Time, Value: r.Value, OK: r.OK}
+		copy(fixed[i].Sensor[:], r.Sensor)
+	}
+	var buf bytes.Buffer
+	buf.Grow(8 + binary.Size(fixed))
+	if err := binary.Write(&buf, binary.LittleEndian, uint64(len(fixed))); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(&buf, binary.LittleEndian, fixed); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func expertFixedDecode(data []byte) ([]Record, error) {
+	rd := bytes.NewReader(data)
+	var n uint64
+	if err := binary.Read(rd, binary.LittleEndian, &n); err != nil {
+		return nil, err
+	}
+	if size := uint64(binary.Size(fixedRecord{})); n > uint64(rd.Len())/size {
+		return nil, fmt.Errorf("serialization: %d records can't fit in %d bytes", n, len(data))
+	}
+	fixed := make([]fixedRecord, n)
+	if err := binary.Read(rd, binary.LittleEndian, fixed); err != nil {
+		return nil, err
+	}
+	records := make([]Record, n)
+	for i, f := range fixed {
+		sensor, _, _ := bytes.Cut(f.Sensor[:], []byte{0})
+		records[i] = Record{ID: f.ID, Time: f.Time, Sensor: string(sensor), Value: f.Value, OK: f.OK}
+	}
+	return records, nil
+}
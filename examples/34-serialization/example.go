@@ -0,0 +1,301 @@
+// Package serialization compares three ways to turn a slice of structs
+// into bytes and back - encoding/json, encoding/gob, and packing the
+// bytes by hand - by speed, by size, and by what survives the round
+// trip.
+package serialization
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	encode           func([]Record) ([]byte, error)
+	decode           func([]byte) ([]Record, error)
+}{
+	{"Vibe coding", "encoding/json", vibeEncode, vibeDecode},
+	{"Human coding", "encoding/gob", humanEncode, humanDecode},
+	{"Expert coding", "packed by hand, deltas", expertEncode, expertDecode},
+	{"Expert binary.Write", "fixed-size, reflection", expertFixedEncode, expertFixedDecode},
+}
+
+// encoders returns the implementations timed encoding records. Each run
+// stores its output in encoded.
+func encoders(records []Record, encoded [][]byte) []bench.Implementation {
+	impls := make([]bench.Implementation, len(tiers))
+	for i, t := range tiers {
+		impls[i] = bench.Implementation{
+			Name: t.name, Complexity: t.complexity,
+			RunContext: func(context.Context) (err error) {
+				encoded[i], err = t.encode(records)
+				return err
+			},
+		}
+	}
+	return impls
+}
+
+// decoders returns the implementations timed decoding what each one
+// encoded. Each run stores its records in decoded.
+func decoders(encoded [][]byte, decoded [][]Record) []bench.Implementation {
+	impls := make([]bench.Implementation, len(tiers))
+	for i, t := range tiers {
+		impls[i] = bench.Implementation{
+			Name: t.name, Complexity: t.complexity,
+			RunContext: func(context.Context) (err error) {
+				decoded[i], err = t.decode(encoded[i])
+				return err
+			},
+		}
+	}
+	return impls
+}
+
+// impls returns the implementations timed encoding n records.
+func impls(n int) []bench.Implementation {
+	return encoders(makeRecords(input.DefaultSeed, n), make([][]byte, len(tiers)))
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "json.Marshal and json.Unmarshal", Complexity: "text, field names in every record", Notes: []report.Note{
+			report.Strength("One line, readable, and every language can decode it"),
+			report.Pitfall("Several times bigger than the data, and the slowest to decode"),
+			report.Pitfall("One NaN fails the whole batch"),
+			report.Pitfall("Strings that aren't valid UTF-8 come back changed"),
+		}},
+		{Label: "Human coding", Approach: "gob.Encoder and gob.Decoder", Complexity: "binary, the type described once", Notes: []report.Note{
+			report.Strength("NaNs and odd strings round-trip"),
+			report.Strength("Fields can be added and removed without breaking old data"),
+			report.Pitfall("Still reflection on every value"),
+			report.Pitfall("Fields equal to zero are left out, so -0 comes back as 0"),
+			report.Pitfall("Only Go reads it"),
+		}},
+		{Label: "Expert coding", Approach: "append by hand: varint deltas, sensors by index", Complexity: "no reflection, no allocation per record", Notes: []report.Note{
+			report.Strength("The smallest output, and the fastest both ways"),
+			report.Strength("Uses what's known about the data: IDs and times count up, sensors repeat"),
+			report.Pitfall("A format of its own: it needs a version, and a decoder that checks every length"),
+			report.Pitfall("Adding a field means a new version, and code for both"),
+		}},
+		{Label: "Expert binary.Write", Approach: "binary.Write of fixed-size structs", Complexity: "41 bytes per record, reflection", Notes: []report.Note{
+			report.Strength("A C-like layout any language can read without parsing"),
+			report.Pitfall("Reflection on every record: slower than packing by hand"),
+			report.Pitfall("Strings must be cut to a fixed size, silently"),
+		}},
+	},
+	Takeaway: "Choose a serialization format for who reads it and what it " +
+		"must carry, then measure: JSON for people and other languages, gob " +
+		"between Go programs, and a packed format only where size and speed " +
+		"matter enough to own its versions and its checks.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "34-serialization",
+		Title:       "Binary Serialization",
+		Description: "Encode and decode a million records with encoding/json, encoding/gob, bytes packed by hand and binary.Write, comparing speed, size and what survives the round trip.",
+		Category:    "performance",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    100_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("34-serialization", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{1_000, 1_000_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of records, e.g. 1e4,1e7")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	for _, n := range sizes {
+		if n < 1 {
+			return fmt.Errorf("-n must be at least 1, not %d", n)
+		}
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Binary Serialization", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Binary Serialization")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	for _, n := range sizes {
+		records := makeRecords(*seed, n)
+		encoded := make([][]byte, len(tiers))
+		decoded := make([][]Record, len(tiers))
+
+		fmt.Fprintf(out.Table, "\nEncoding %d records (seed %d):\n", n, *seed)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+		encodes, err := bench.CompareContext(ctx, opts, encoders(records, encoded)...)
+		if err != nil {
+			return err
+		}
+		if err := csvLog.Append("encode", uint64(n), encodes); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("encode", uint64(n), encodes)
+		bench.Print(out.Table, encodes)
+		report.WriteBars(w, encodes)
+		bench.PrintRuns(out.Detail, encodes)
+		rep.Add(fmt.Sprintf("Encoding %d records", n), encodes)
+
+		fmt.Fprintf(out.Table, "\nDecoding %d records:\n", n)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+		decodes, err := bench.CompareContext(ctx, opts, decoders(encoded, decoded)...)
+		if err != nil {
+			return err
+		}
+		for i, t := range tiers {
+			if len(decoded[i]) != len(records) {
+				return fmt.Errorf("%s: decoded %d records, want %d", t.name, len(decoded[i]), len(records))
+			}
+			if k := firstDiff(decoded[i], records); k >= 0 {
+				return fmt.Errorf("%s: record %d: %s", t.name, k, diff(decoded[i][k], records[k]))
+			}
+		}
+		fmt.Fprintln(w, "✔ Every implementation decodes the records it encoded")
+		if err := csvLog.Append("decode", uint64(n), decodes); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("decode", uint64(n), decodes)
+		bench.Print(out.Table, decodes)
+		report.WriteBars(w, decodes)
+		bench.PrintRuns(out.Detail, decodes)
+		section := rep.Add(fmt.Sprintf("Decoding %d records", n), decodes)
+
+		fmt.Fprintln(out.Table, "\nSize, and records per second:")
+		for i, t := range tiers {
+			size := len(encoded[i])
+			enc := float64(n) / 1e6 / encodes[i].Duration.Seconds()
+			dec := float64(n) / 1e6 / decodes[i].Duration.Seconds()
+			fmt.Fprintf(out.Table, "  %-20s %10s  %5.1f bytes/record   encode %6.2fM/s   decode %6.2fM/s\n",
+				t.name+":", bench.FormatBytes(uint64(size)), float64(size)/float64(n), enc, dec)
+			section.Notes = append(section.Notes, fmt.Sprintf("%s: %s, %.1f bytes/record; encodes %.2fM and decodes %.2fM records/s",
+				t.name, bench.FormatBytes(uint64(size)), float64(size)/float64(n), enc, dec))
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing: what survives the round trip")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	three := makeRecords(*seed, 3)
+	with := func(change func(r *Record)) []Record {
+		records := slices.Clone(three)
+		change(&records[1])
+		return records
+	}
+	random := make([]byte, 1024)
+	rng := seed.Rand("random", len(random))
+	for i := range random {
+		random[i] = byte(rng.Uint32())
+	}
+	roundTrips := "round-trips"
+	edgeCases := []struct {
+		desc    string
+		records []Record
+		damage  func(encoded []byte) []byte // What happens to the encoded data before it is decoded, if anything
+		want    string
+	}{
+		{"no records", nil, nil, roundTrips},
+		{"a reading of NaN", with(func(r *Record) { r.Value = math.NaN() }), nil, roundTrips},
+		{"a reading of -0", with(func(r *Record) { r.Value = math.Copysign(0, -1) }), nil, roundTrips},
+		{`a sensor named "\xff\xfe", not UTF-8`, with(func(r *Record) { r.Sensor = "\xff\xfe" }), nil, roundTrips},
+		{"a sensor named with 30 bytes", with(func(r *Record) { r.Sensor = "greenhouse-north/soil-moisture" }), nil, roundTrips},
+		{"a clock that went back an hour", with(func(r *Record) { r.Time -= 3600e9 }), nil, roundTrips},
+		{"the last byte lost", three, func(b []byte) []byte { return b[:len(b)-1] }, "decoding fails"},
+		{"1 KiB of random bytes", three, func([]byte) []byte { return random }, "decoding fails"},
+	}
+	for _, tc := range edgeCases {
+		fmt.Fprintf(w, "%s (want: %s):\n", tc.desc, tc.want)
+		for _, t := range tiers {
+			var result string
+			good := false
+			encoded, err := t.encode(tc.records)
+			if err != nil {
+				result = "encoding failed: " + err.Error()
+			} else {
+				if tc.damage != nil {
+					encoded = tc.damage(slices.Clone(encoded))
+				}
+				got, err := t.decode(encoded)
+				switch {
+				case err != nil:
+					result = "decoding failed: " + err.Error()
+					good = tc.want != roundTrips
+				case len(got) != len(tc.records):
+					result = fmt.Sprintf("decoded %d records, not %d", len(got), len(tc.records))
+				case firstDiff(got, tc.records) >= 0:
+					k := firstDiff(got, tc.records)
+					result = "decoded differently: " + diff(got[k], tc.records[k])
+				default:
+					result = fmt.Sprintf("round-trips, in %d bytes", len(encoded))
+					good = tc.want == roundTrips
+				}
+			}
+			status := "✅"
+			if !good {
+				status = "❌"
+			}
+			fmt.Fprintf(w, "  %-20s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
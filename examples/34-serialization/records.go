@@ -0,0 +1,79 @@
+package serialization
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/iportilla/ai-coding/input"
+)
+
+// Record is one reading from a sensor.
+type Record struct {
+	ID     uint64  `json:"id"`
+	Time   int64   `json:"time"` // Unix nanoseconds
+	Sensor string  `json:"sensor"`
+	Value  float64 `json:"value"`
+	OK     bool    `json:"ok"`
+}
+
+// sensorKinds are what the sensors measure.
+var sensorKinds = []string{"temp", "humidity", "pressure", "co2"}
+
+// makeRecords returns n readings from 40 sensors, in the order they
+// were taken: IDs counting up, a few hundred milliseconds apart, and
+// values to two decimal places, as a sensor reports them.
+func makeRecords(seed input.Seed, n int) []Record {
+	rng := seed.Rand("records", n)
+	sensors := make([]string, 40)
+	for i := range sensors {
+		sensors[i] = fmt.Sprintf("%s-%02d", sensorKinds[i%len(sensorKinds)], i)
+	}
+	records := make([]Record, n)
+	id, t := rng.Uint64N(1<<40), int64(1_790_000_000_000_000_000)+rng.Int64N(1e18)
+	for i := range records {
+		t += rng.Int64N(500e6)
+		records[i] = Record{
+			ID:     id + uint64(i),
+			Time:   t,
+			Sensor: sensors[rng.IntN(len(sensors))],
+			Value:  math.Round((20+rng.NormFloat64()*5)*100) / 100,
+			OK:     rng.IntN(100) != 0,
+		}
+	}
+	return records
+}
+
+// same reports whether a and b hold the same records. Values are
+// compared bit for bit, so a NaN equals itself.
+func same(a, b Record) bool {
+	return a.ID == b.ID && a.Time == b.Time && a.Sensor == b.Sensor &&
+		math.Float64bits(a.Value) == math.Float64bits(b.Value) && a.OK == b.OK
+}
+
+// firstDiff returns the index of the first of got that isn't the same
+// as the one in want, or -1 if there is none. They must be as long.
+func firstDiff(got, want []Record) int {
+	for i := range got {
+		if !same(got[i], want[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// diff describes the first field in which got differs from want.
+func diff(got, want Record) string {
+	switch {
+	case got.ID != want.ID:
+		return fmt.Sprintf("ID %d, want %d", got.ID, want.ID)
+	case got.Time != want.Time:
+		return fmt.Sprintf("Time %d, want %d", got.Time, want.Time)
+	case got.Sensor != want.Sensor:
+		return fmt.Sprintf("Sensor %q, want %q", got.Sensor, want.Sensor)
+	case math.Float64bits(got.Value) != math.Float64bits(want.Value):
+		return fmt.Sprintf("Value %v, want %v", got.Value, want.Value)
+	case got.OK != want.OK:
+		return fmt.Sprintf("OK %v, want %v", got.OK, want.OK)
+	}
+	return "no difference"
+}
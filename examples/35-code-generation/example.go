@@ -0,0 +1,275 @@
+// Package codegen compares mapping CSV rows to structs by reflection,
+// naively and carefully, with mapping them by code written for the type
+// by a generator, run by go generate.
+package codegen
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	unmarshal        func(rows [][]string) ([]Trade, error)
+}{
+	{"Vibe coding", "reflection, fields found per value",
+		func(rows [][]string) ([]Trade, error) {
+			var trades []Trade
+			err := vibeUnmarshal(rows, &trades)
+			return trades, err
+		}},
+	{"Human coding", "reflection, columns matched once", humanUnmarshal[Trade]},
+	{"Expert coding", "generated code", expertUnmarshal},
+}
+
+// mappers returns the implementations to compare.
+func mappers() []bench.Impl[[][]string, []Trade] {
+	impls := make([]bench.Impl[[][]string, []Trade], len(tiers))
+	for i, t := range tiers {
+		impls[i] = bench.Impl[[][]string, []Trade]{
+			Name: t.name, Complexity: t.complexity,
+			FuncContext: func(ctx context.Context, rows [][]string) ([]Trade, error) {
+				trades, err := t.unmarshal(rows)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", t.name, err)
+				}
+				return trades, ctx.Err()
+			},
+		}
+	}
+	return impls
+}
+
+// impls returns the implementations timed mapping n rows.
+func impls(n int) []bench.Implementation {
+	rows := tradeRows(makeTrades(input.DefaultSeed, n))
+	var list []bench.Implementation
+	for _, m := range mappers() {
+		list = append(list, m.Implementation(rows))
+	}
+	return list
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "reflection, every field searched for every value", Complexity: "O(rows × columns × fields)", Notes: []report.Note{
+			report.Strength("Short, and works for any struct"),
+			report.Pitfall("Redoes work whose answer is the same for every row: finding fields, parsing tags"),
+			report.Pitfall("Numbers that don't parse become 0, and unknown columns are skipped, silently"),
+			report.Pitfall("A row with an extra field panics"),
+		}},
+		{Label: "Human coding", Approach: "reflection, columns matched to fields once", Complexity: "O(rows × columns)", Notes: []report.Note{
+			report.Strength("Still works for any struct, with an error for every bad row, naming its column"),
+			report.Strength("The lookups done once, outside the loop"),
+			report.Pitfall("Every value still goes through reflect.Value: Field, a Kind switch, Set"),
+		}},
+		{Label: "Expert coding", Approach: "a mapper generated from the struct by go generate", Complexity: "O(rows × columns)", Notes: []report.Note{
+			report.Strength("Plain assignments the compiler sees through: no reflection left"),
+			report.Strength("The same checks and errors as the careful reflection"),
+			report.Pitfall("A generator to maintain, and a build step to rerun when the struct changes"),
+			report.Tip("Generated code is checked in, so go build works without go generate"),
+		}},
+	},
+	Takeaway: "Reflection answers at run time questions whose answers are " +
+		"known at compile time. Off the hot path that is a fair price for " +
+		"generality; in a loop over millions of rows, generate the code " +
+		"instead, or at least hoist every lookup out of the loop.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "35-code-generation",
+		Title:       "Reflection vs Code Generation",
+		Description: "Map CSV rows to structs by reflection, naively and carefully, and by a mapper that go generate writes for the type, with the generator included.",
+		Category:    "performance",
+		Difficulty:  examples.Advanced,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    100_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("35-code-generation", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{10_000, 100_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of rows, e.g. 1e3,1e7")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	for _, n := range sizes {
+		if n < 1 {
+			return fmt.Errorf("-n must be at least 1, not %d", n)
+		}
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Reflection vs Code Generation", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Reflection vs Code Generation")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	for _, n := range sizes {
+		trades := makeTrades(*seed, n)
+		rows := tradeRows(trades)
+		fmt.Fprintf(out.Table, "\nMapping %d rows of %d columns to Trade structs (seed %d):\n", n, len(tradeHeader), *seed)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		results, err := bench.CompareImpls(ctx, opts, rows, trades, bench.DiffSlices, mappers()...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "✔ All implementations map every row to the trade it came from")
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d rows", n), results)
+
+		fmt.Fprintln(out.Table, "\nPer row:")
+		for _, r := range results {
+			perRow := float64(r.Duration.Nanoseconds()) / float64(n)
+			fmt.Fprintf(out.Table, "  %-14s %7.1f ns/row  %5.1f ns/value  %5.2f allocs/row\n",
+				r.Name+":", perRow, perRow/float64(len(tradeHeader)), float64(r.Allocs)/float64(n))
+			section.Notes = append(section.Notes, fmt.Sprintf("%s: %.1f ns and %.2f allocations per row",
+				r.Name, perRow, float64(r.Allocs)/float64(n)))
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing: headers and rows that don't fit")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	trades := makeTrades(*seed, 3)
+	rows := tradeRows(trades)
+	// change returns a copy of rows, with change applied to it.
+	change := func(change func(rows [][]string) [][]string) [][]string {
+		c := make([][]string, len(rows))
+		for i, row := range rows {
+			c[i] = slices.Clone(row)
+		}
+		return change(c)
+	}
+	price := slices.Index(tradeHeader, "price")
+	edgeCases := []struct {
+		desc   string
+		rows   [][]string
+		want   []Trade // The trades to map to, or nil for an error
+		errHas string  // What the error must mention
+	}{
+		{"columns in another order", change(func(r [][]string) [][]string {
+			for _, row := range r {
+				slices.Reverse(row)
+			}
+			return r
+		}), trades, ""},
+		{"the header and no rows", rows[:1], []Trade{}, ""},
+		{`a price of "12,50"`, change(func(r [][]string) [][]string { r[2][price] = "12,50"; return r }), nil, `row 2: column "price"`},
+		{`an unknown column, "notes"`, change(func(r [][]string) [][]string {
+			for i := range r {
+				r[i] = append(r[i], "notes")
+			}
+			return r
+		}), nil, `column "notes"`},
+		{"a row with a field missing", change(func(r [][]string) [][]string { r[3] = r[3][:len(r[3])-1]; return r }), nil, "row 3"},
+		{"a row with a field too many", change(func(r [][]string) [][]string { r[1] = append(r[1], "extra"); return r }), nil, "row 1"},
+	}
+	for _, tc := range edgeCases {
+		want := "an error mentioning " + tc.errHas
+		if tc.want != nil {
+			want = fmt.Sprintf("%d trades", len(tc.want))
+		}
+		fmt.Fprintf(w, "%s (want: %s):\n", tc.desc, want)
+		for _, t := range tiers {
+			got, panicked, err := unmarshalSafely(t.unmarshal, tc.rows)
+			var result string
+			var good bool
+			switch {
+			case panicked != nil:
+				result = fmt.Sprintf("panicked: %v", panicked)
+			case err != nil:
+				result = "error: " + err.Error()
+				good = tc.want == nil && strings.Contains(err.Error(), tc.errHas)
+			case tc.want == nil:
+				result = fmt.Sprintf("no error; %d trades", len(got))
+				if k := slices.IndexFunc(got, func(g Trade) bool { return !slices.Contains(trades, g) }); k >= 0 {
+					result += fmt.Sprintf(", #%d is %+v", k+1, got[k])
+				}
+			case bench.DiffSlices(got, tc.want) != nil:
+				result = fmt.Sprintf("wrong trades: %v", bench.DiffSlices(got, tc.want))
+			default:
+				result = fmt.Sprintf("%d trades, all right", len(got))
+				good = true
+			}
+			status := "✅"
+			if !good {
+				status = "❌"
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
+
+// unmarshalSafely calls unmarshal on rows, returning what it panicked
+// with, if it did.
+func unmarshalSafely(unmarshal func([][]string) ([]Trade, error), rows [][]string) (trades []Trade, panicked any, err error) {
+	defer func() { panicked = recover() }()
+	trades, err = unmarshal(rows)
+	return trades, nil, err
+}
@@ -0,0 +1,247 @@
+// Command gen writes the code that maps CSV rows to a struct, so the
+// mapping is plain Go instead of reflection. It reads the struct from
+// the Go files in the current directory, and is run by go generate:
+//
+//	//go:generate go run ./gen -type Trade
+//
+// Each field with a `csv:"name"` tag is read from the column of that
+// name. Fields may be strings, bools, integers or floats. For a type T,
+// it writes t_csv.go, holding
+//
+//	func tCSVColumns(header []string) ([]int, error)
+//	func decodeTCSV(v *T, cols []int, row []string) error
+//
+// tCSVColumns maps each column of header to a field, and decodeTCSV
+// sets the fields of v from a row with those columns.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// field is a struct field mapped to a column.
+type field struct {
+	Index  int    // Its index among the mapped fields
+	Name   string // Its name in the struct
+	Column string // Its column's name, from its csv tag
+	Parse  string // The statement setting it from s, or "" for a string
+}
+
+// parsers are the statements that set v.<field> from the string s, for
+// each type a field can have, with the name of the field for %[1]s and
+// its type for %[2]s.
+var parsers = map[string]string{
+	"string":  "",
+	"bool":    "v.%[1]s, err = strconv.ParseBool(s)",
+	"int":     "v.%[1]s, err = strconv.Atoi(s)",
+	"int8":    "n, err = strconv.ParseInt(s, 10, 8); v.%[1]s = %[2]s(n)",
+	"int16":   "n, err = strconv.ParseInt(s, 10, 16); v.%[1]s = %[2]s(n)",
+	"int32":   "n, err = strconv.ParseInt(s, 10, 32); v.%[1]s = %[2]s(n)",
+	"int64":   "v.%[1]s, err = strconv.ParseInt(s, 10, 64)",
+	"uint":    "u, err = strconv.ParseUint(s, 10, 0); v.%[1]s = %[2]s(u)",
+	"uint8":   "u, err = strconv.ParseUint(s, 10, 8); v.%[1]s = %[2]s(u)",
+	"uint16":  "u, err = strconv.ParseUint(s, 10, 16); v.%[1]s = %[2]s(u)",
+	"uint32":  "u, err = strconv.ParseUint(s, 10, 32); v.%[1]s = %[2]s(u)",
+	"uint64":  "v.%[1]s, err = strconv.ParseUint(s, 10, 64)",
+	"float32": "f, err = strconv.ParseFloat(s, 32); v.%[1]s = %[2]s(f)",
+	"float64": "v.%[1]s, err = strconv.ParseFloat(s, 64)",
+}
+
+var out = template.Must(template.New("out").Parse(`// Code generated by "go run ./gen {{.Args}}"; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"fmt"
+	{{- if .Strconv}}
+	"strconv"
+	{{- end}}
+)
+
+// {{.Prefix}}CSVColumns returns the index of the field of {{.Type}} each of
+// header's columns is read into, or an error for a column it doesn't
+// have, or has twice.
+func {{.Prefix}}CSVColumns(header []string) ([]int, error) {
+	cols := make([]int, len(header))
+	var seen [{{len .Fields}}]bool
+	for i, name := range header {
+		switch name {
+		{{- range .Fields}}
+		case {{printf "%q" .Column}}:
+			cols[i] = {{.Index}}
+		{{- end}}
+		default:
+			return nil, fmt.Errorf("column %q: no such field in {{.Type}}", name)
+		}
+		if seen[cols[i]] {
+			return nil, fmt.Errorf("column %q: appears twice", name)
+		}
+		seen[cols[i]] = true
+	}
+	return cols, nil
+}
+
+// decode{{.Type}}CSV sets v's fields from row, whose columns are read into
+// the fields cols gives, as {{.Prefix}}CSVColumns returns it.
+func decode{{.Type}}CSV(v *{{.Type}}, cols []int, row []string) error {
+	if len(row) != len(cols) {
+		return fmt.Errorf("%d fields, want %d", len(row), len(cols))
+	}
+	{{- if .N}}
+	var n int64
+	{{- end}}
+	{{- if .U}}
+	var u uint64
+	{{- end}}
+	{{- if .F}}
+	var f float64
+	{{- end}}
+	var err error
+	for i, s := range row {
+		switch cols[i] {
+		{{- range .Fields}}
+		case {{.Index}}:
+			{{if .Parse}}{{.Parse}}{{else}}v.{{.Name}} = s{{end}}
+		{{- end}}
+		}
+		if err != nil {
+			return fmt.Errorf("column %q: %w", {{.Prefix}}CSVNames[cols[i]], err)
+		}
+	}
+	return nil
+}
+
+// {{.Prefix}}CSVNames are the columns of {{.Type}}'s fields, in order.
+var {{.Prefix}}CSVNames = [...]string{ {{- range $i, $f := .Fields}}{{if $i}}, {{end}}{{printf "%q" .Column}}{{end -}} }
+`))
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("gen: ")
+	typeName := flag.String("type", "", "the struct type to map CSV rows to")
+	output := flag.String("output", "", "the file to write (default <type>_csv.go, in lower case)")
+	flag.Parse()
+	if *typeName == "" {
+		log.Fatal("-type is required")
+	}
+	if *output == "" {
+		*output = strings.ToLower(*typeName) + "_csv.go"
+	}
+
+	pkg, st, err := findStruct(".", *typeName, *output)
+	if err != nil {
+		log.Fatal(err)
+	}
+	data := struct {
+		Args, Package, Type, Prefix string
+		Fields                      []field
+		Strconv, N, U, F            bool // Whether the parsers use strconv, n, u and f
+	}{
+		Args:    strings.Join(os.Args[1:], " "),
+		Package: pkg,
+		Type:    *typeName,
+		Prefix:  string(unicode.ToLower(rune((*typeName)[0]))) + (*typeName)[1:],
+	}
+	for _, f := range st.Fields.List {
+		if f.Tag == nil {
+			continue
+		}
+		tag, err := strconv.Unquote(f.Tag.Value)
+		if err != nil {
+			log.Fatalf("%s: tag %s: %v", *typeName, f.Tag.Value, err)
+		}
+		column, ok := reflect.StructTag(tag).Lookup("csv")
+		if !ok || column == "-" {
+			continue
+		}
+		ident, ok := f.Type.(*ast.Ident)
+		if !ok || len(f.Names) != 1 {
+			log.Fatalf("%s: field %s: only single fields of basic types are supported", *typeName, exprString(f.Type))
+		}
+		parse, ok := parsers[ident.Name]
+		if !ok {
+			log.Fatalf("%s.%s: can't read a %s from CSV", *typeName, f.Names[0].Name, ident.Name)
+		}
+		if parse != "" {
+			parse = fmt.Sprintf(parse, f.Names[0].Name, ident.Name)
+		}
+		data.Strconv = data.Strconv || parse != ""
+		data.N = data.N || strings.HasPrefix(parse, "n,")
+		data.U = data.U || strings.HasPrefix(parse, "u,")
+		data.F = data.F || strings.HasPrefix(parse, "f,")
+		data.Fields = append(data.Fields, field{len(data.Fields), f.Names[0].Name, column, parse})
+	}
+	if len(data.Fields) == 0 {
+		log.Fatalf("%s has no fields with csv tags", *typeName)
+	}
+
+	var buf bytes.Buffer
+	if err := out.Execute(&buf, data); err != nil {
+		log.Fatal(err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("formatting the output: %v\n%s", err, buf.Bytes())
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// findStruct returns the package name and the struct type named name
+// declared in the Go files of dir, other than skip and tests.
+func findStruct(dir, name, skip string) (string, *ast.StructType, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", nil, err
+	}
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		if filepath.Base(path) == skip || strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", nil, err
+		}
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if ts.Name.Name != name {
+					continue
+				}
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					return "", nil, fmt.Errorf("%s is not a struct", name)
+				}
+				return f.Name.Name, st, nil
+			}
+		}
+	}
+	return "", nil, fmt.Errorf("no type %s in %s", name, dir)
+}
+
+// exprString formats the type expression e, for errors.
+func exprString(e ast.Expr) string {
+	var buf bytes.Buffer
+	format.Node(&buf, token.NewFileSet(), e)
+	return buf.String()
+}
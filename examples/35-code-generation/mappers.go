@@ -0,0 +1,191 @@
+package codegen
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// VIBE CODING: Reflection, looking everything up for every value
+func vibeUnmarshal(rows [][]string, out interface{}) error {
+	/*
+	   Map CSV rows, a header first, into a slice of any struct, reading
+	   each column into the field whose csv tag names it - like
+	   json.Unmarshal does, and in as few lines.
+
+	   For every value of every row, it walks the struct's fields to
+	   find the one whose tag matches the column, parsing each tag
+	   again, and then sets it through reflection and appends the row
+	   with reflect.Append. All of that is work whose answer is the same
+	   for every row.
+
+	   And it trusts its input: numbers that don't parse become 0,
+	   columns that match no field are skipped, short rows leave fields
+	   empty, and a long row panics.
+	*/
+	if len(rows) == 0 {
+		return nil
+	}
+	slice := reflect.ValueOf(out).Elem()
+	elemType := slice.Type().Elem()
+	header := rows[0]
+	for _, row := range rows[1:] {
+		item := reflect.New(elemType).Elem()
+		for i, value := range row {
+			for j := 0; j < elemType.NumField(); j++ {
+				field := elemType.Field(j)
+				if field.Tag.Get("csv") != header[i] {
+					continue
+				}
+				switch field.Type.Kind() {
+				case reflect.String:
+					item.Field(j).SetString(value)
+				case reflect.Int, reflect.Int64:
+					n, _ := strconv.ParseInt(value, 10, 64)
+					item.Field(j).SetInt(n)
+				case reflect.Float64:
+					f, _ := strconv.ParseFloat(value, 64)
+					item.Field(j).SetFloat(f)
+				case reflect.Bool:
+					b, _ := strconv.ParseBool(value)
+					item.Field(j).SetBool(b)
+				}
+			}
+		}
+		slice.Set(reflect.Append(slice, item))
+	}
+	return nil
+}
+
+// HUMAN CODING: Reflection, with the columns matched once
+func humanUnmarshal[T any](rows [][]string) ([]T, error) {
+	/*
+	   The same job, still for any struct, done with care. The header
+	   is matched to fields once, into a slice of field indexes, and
+	   each row is decoded straight into its element of a slice made
+	   the right size. Every error names its row and column: a number
+	   that doesn't parse, a column with no field, a row of the wrong
+	   length.
+
+	   But each value still goes through reflect.Value: a Field call
+	   to find it, a Kind switch to choose how to parse it, a Set call
+	   that checks the field is settable and of the right kind. That
+	   is several times the work of an assignment, on every value of
+	   every row, and the compiler can't inline or optimize any of it.
+	*/
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s is not a struct", t)
+	}
+	cols, err := reflectColumns(t, rows[0])
+	if err != nil {
+		return nil, err
+	}
+	out := make([]T, len(rows)-1)
+	for r, row := range rows[1:] {
+		if len(row) != len(cols) {
+			return nil, fmt.Errorf("row %d: %d fields, want %d", r+1, len(row), len(cols))
+		}
+		v := reflect.ValueOf(&out[r]).Elem()
+		for i, s := range row {
+			if err := setField(v.Field(cols[i]), s); err != nil {
+				return nil, fmt.Errorf("row %d: column %q: %w", r+1, rows[0][i], err)
+			}
+		}
+	}
+	return out, nil
+}
+
+// reflectColumns returns the index of the field of t each of header's
+// columns is read into, by their csv tags.
+func reflectColumns(t reflect.Type, header []string) ([]int, error) {
+	fields := make(map[string]int)
+	for i := range t.NumField() {
+		if name, ok := t.Field(i).Tag.Lookup("csv"); ok && name != "-" {
+			fields[name] = i
+		}
+	}
+	cols := make([]int, len(header))
+	seen := make(map[int]bool)
+	for i, name := range header {
+		j, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("column %q: no such field in %s", name, t.Name())
+		}
+		if seen[j] {
+			return nil, fmt.Errorf("column %q: appears twice", name)
+		}
+		cols[i], seen[j] = j, true
+	}
+	return cols, nil
+}
+
+// setField parses s into v, by v's kind.
+func setField(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("can't read a %s from CSV", v.Type())
+	}
+	return nil
+}
+
+// EXPERT CODING: Code generated for the type, by go generate
+func expertUnmarshal(rows [][]string) ([]Trade, error) {
+	/*
+	   Everything the reflection looks up at run time - which fields
+	   there are, their tags, their types, how to parse each - is
+	   known when the program is compiled. So look it up then: gen, in
+	   gen/main.go, reads the Trade struct with go/parser and writes
+	   trade_csv.go, a switch on the column with a plain assignment for
+	   each field. go generate reruns it whenever Trade changes.
+
+	   The result is the code you would write by hand, with the same
+	   checks and errors as the careful reflection, but with nothing
+	   left to look up: the compiler sees every type, and inlines and
+	   optimizes it like any other code. The cost is a build step, and
+	   generated code to keep up to date and in version control.
+	*/
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	cols, err := tradeCSVColumns(rows[0])
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Trade, len(rows)-1)
+	for r, row := range rows[1:] {
+		if err := decodeTradeCSV(&out[r], cols, row); err != nil {
+			return nil, fmt.Errorf("row %d: %w", r+1, err)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,76 @@
+package codegen
+
+import (
+	"strconv"
+
+	"github.com/iportilla/ai-coding/input"
+)
+
+//go:generate go run ./gen -type Trade
+
+// Trade is one row of a broker's trade export. Each field is read from
+// the CSV column named by its csv tag.
+type Trade struct {
+	ID       int64   `csv:"id"`
+	Time     int64   `csv:"time"` // Unix milliseconds
+	Symbol   string  `csv:"symbol"`
+	Side     string  `csv:"side"`
+	Price    float64 `csv:"price"`
+	Quantity int     `csv:"qty"`
+	Venue    string  `csv:"venue"`
+	Filled   bool    `csv:"filled"`
+}
+
+// tradeHeader is the header row of the generated exports: the columns
+// in the order a broker might write them, not the struct's.
+var tradeHeader = []string{"id", "time", "symbol", "side", "qty", "price", "filled", "venue"}
+
+var (
+	symbols = []string{"AAPL", "MSFT", "GOOG", "AMZN", "NVDA", "TSLA", "META", "BRK.B"}
+	venues  = []string{"XNAS", "XNYS", "ARCX", "BATS", "IEXG"}
+)
+
+// makeTrades returns n trades, in the order they were made.
+func makeTrades(seed input.Seed, n int) []Trade {
+	rng := seed.Rand("trades", n)
+	trades := make([]Trade, n)
+	id, ms := 1+rng.Int64N(1e9), int64(1_790_000_000_000)+rng.Int64N(1e11)
+	for i := range trades {
+		ms += rng.Int64N(50)
+		side := "buy"
+		if rng.IntN(2) == 0 {
+			side = "sell"
+		}
+		trades[i] = Trade{
+			ID:       id + int64(i),
+			Time:     ms,
+			Symbol:   symbols[rng.IntN(len(symbols))],
+			Side:     side,
+			Price:    float64(100+rng.IntN(90_000)) / 100,
+			Quantity: 1 + rng.IntN(1000),
+			Venue:    venues[rng.IntN(len(venues))],
+			Filled:   rng.IntN(20) != 0,
+		}
+	}
+	return trades
+}
+
+// tradeRows returns trades as the rows of a CSV export, with
+// tradeHeader first, as encoding/csv's ReadAll would return them.
+func tradeRows(trades []Trade) [][]string {
+	rows := make([][]string, 0, len(trades)+1)
+	rows = append(rows, tradeHeader)
+	for _, t := range trades {
+		rows = append(rows, []string{
+			strconv.FormatInt(t.ID, 10),
+			strconv.FormatInt(t.Time, 10),
+			t.Symbol,
+			t.Side,
+			strconv.Itoa(t.Quantity),
+			strconv.FormatFloat(t.Price, 'f', 2, 64),
+			strconv.FormatBool(t.Filled),
+			t.Venue,
+		})
+	}
+	return rows
+}
@@ -0,0 +1,89 @@
+package dispatch
+
+// VIBE CODING: An interface call per number
+func vibeSum(s Summer, xs []int64) int64 {
+	/*
+	   Program to the interface: any Summer will do, and new ones can
+	   be added without touching this code.
+
+	   But s.Add is a dynamic call: load the method from s's itab,
+	   call through the pointer. The compiler can't see which Add it
+	   is, so it can't inline it, and every number costs a call - and
+	   the Summer's total goes back to memory each time. Passing s as
+	   an interface makes it escape, too: whoever made it allocated it
+	   on the heap.
+	*/
+	for _, x := range xs {
+		s.Add(x)
+	}
+	return s.Sum()
+}
+
+// HUMAN CODING: A type switch for the Summer everyone uses
+func humanSum(s Summer, xs []int64) int64 {
+	/*
+	   Almost every caller passes an *intSummer, so check for it once,
+	   outside the loop. In that case the loop calls
+	   (*intSummer).Add directly, which the compiler inlines into an
+	   add. Any other Summer still works, through the interface.
+
+	   But it is only fast for the types listed in the switch, and
+	   someone has to keep the list in step with the Summers people
+	   use. A new Summer silently takes the slow path.
+	*/
+	if s, ok := s.(*intSummer); ok {
+		for _, x := range xs {
+			s.Add(x)
+		}
+		return s.Sum()
+	}
+	for _, x := range xs {
+		s.Add(x)
+	}
+	return s.Sum()
+}
+
+// Number is the types expertSum can add.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~float32 | ~float64
+}
+
+// EXPERT CODING: A generic function over the numbers themselves
+func expertSum[T Number](xs []T) T {
+	/*
+	   Go compiles a generic function once per "GC shape": per
+	   underlying type, except that all pointers share one. int64 has
+	   its own, so expertSum[int64] is compiled just for it: + is an
+	   add instruction, the total stays in a register, and nothing is
+	   called at all. The same code adds float64s, or a named type
+	   like type Cents int64, each at full speed.
+
+	   The catch is that it is fast because it has no abstraction
+	   left: there is no Summer to swap in. A Summer that checks for
+	   overflow can't be used here.
+	*/
+	var total T
+	for _, x := range xs {
+		total += x
+	}
+	return total
+}
+
+// EXPERT CODING: A generic function over Summers - no faster
+func expertSumWith[S Summer](s S, xs []int64) int64 {
+	/*
+	   It looks as if making the Summer a type parameter should let the
+	   compiler see which Add it is, as C++ templates would. It
+	   doesn't. S is always a pointer, all pointers share one GC shape,
+	   so one copy of this function serves every Summer, and it finds
+	   Add through a dictionary passed in at run time: a dynamic call
+	   per number, like the interface.
+
+	   Generics specialize on shapes, not types. They devirtualize
+	   methods only when the type argument has a shape of its own.
+	*/
+	for _, x := range xs {
+		s.Add(x)
+	}
+	return s.Sum()
+}
@@ -0,0 +1,50 @@
+package dispatch
+
+import "math"
+
+// Summer adds up int64s, one at a time.
+type Summer interface {
+	Add(x int64)
+	Sum() int64
+}
+
+// intSummer is the plain Summer: its sum wraps around on overflow,
+// like +.
+type intSummer struct{ total int64 }
+
+func (s *intSummer) Add(x int64) { s.total += x }
+func (s *intSummer) Sum() int64  { return s.total }
+
+// checkedSummer is a Summer that notices when its sum overflows.
+type checkedSummer struct {
+	total    int64
+	overflow bool
+}
+
+func (s *checkedSummer) Add(x int64) {
+	t := s.total + x
+	if (x > 0 && t < s.total) || (x < 0 && t > s.total) {
+		s.overflow = true
+	}
+	s.total = t
+}
+
+func (s *checkedSummer) Sum() int64 { return s.total }
+
+// Overflowed reports whether the sum has overflowed.
+func (s *checkedSummer) Overflowed() bool { return s.overflow }
+
+// summers are the Summers the example sums with, by name. They are
+// looked up at run time, so the compiler can't know which one a Summer
+// holds.
+var summers = []struct {
+	name string
+	make func() Summer
+}{
+	{"*intSummer", func() Summer { return &intSummer{} }},
+	{"*checkedSummer", func() Summer { return &checkedSummer{} }},
+}
+
+// maxValue bounds the numbers summed, so that a billion of them can't
+// overflow.
+const maxValue = math.MaxInt64 >> 32
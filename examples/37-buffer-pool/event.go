@@ -0,0 +1,79 @@
+package bufpool
+
+import (
+	"bytes"
+	"strconv"
+	"time"
+
+	"github.com/iportilla/ai-coding/input"
+)
+
+// event is one request a server logs.
+type event struct {
+	At      time.Time
+	Level   string
+	User    string
+	Method  string
+	Path    string
+	Status  int
+	Latency time.Duration
+}
+
+// users, paths and the rest are what events are made from.
+var (
+	users    = []string{"alice", "bob", "carol", "dave", "erin", "frank", "grace", "heidi"}
+	methods  = []string{"GET", "GET", "GET", "POST", "PUT", "DELETE"}
+	paths    = []string{"/", "/login", "/api/orders", "/api/orders/42", "/api/users/me", "/static/app.js"}
+	statuses = []int{200, 200, 200, 200, 201, 204, 304, 400, 404, 500}
+)
+
+// makeEvents returns n events, a few milliseconds apart.
+func makeEvents(seed input.Seed, n int) []event {
+	rng := seed.Rand("events", n)
+	events := make([]event, n)
+	at := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	for i := range events {
+		at = at.Add(time.Duration(rng.Int64N(int64(5 * time.Millisecond))))
+		status := statuses[rng.IntN(len(statuses))]
+		level := "INFO"
+		if status >= 500 {
+			level = "ERROR"
+		}
+		events[i] = event{
+			At:      at,
+			Level:   level,
+			User:    users[rng.IntN(len(users))],
+			Method:  methods[rng.IntN(len(methods))],
+			Path:    paths[rng.IntN(len(paths))],
+			Status:  status,
+			Latency: time.Duration(rng.ExpFloat64() * float64(2*time.Millisecond)),
+		}
+	}
+	return events
+}
+
+// writeEvent appends e to b as one log line, such as
+//
+//	2026-10-15T09:00:00.004Z level=INFO user=alice method=GET path=/login status=200 latency_us=1834
+//
+// It appends straight into b's spare capacity, so it allocates only if
+// b has to grow: every implementation formats with it, and differs only
+// in where b comes from.
+func writeEvent(b *bytes.Buffer, e *event) {
+	p := b.AvailableBuffer()
+	p = e.At.AppendFormat(p, "2006-01-02T15:04:05.000Z07:00")
+	p = append(p, " level="...)
+	p = append(p, e.Level...)
+	p = append(p, " user="...)
+	p = append(p, e.User...)
+	p = append(p, " method="...)
+	p = append(p, e.Method...)
+	p = append(p, " path="...)
+	p = append(p, e.Path...)
+	p = append(p, " status="...)
+	p = strconv.AppendInt(p, int64(e.Status), 10)
+	p = append(p, " latency_us="...)
+	p = strconv.AppendInt(p, e.Latency.Microseconds(), 10)
+	p = append(p, '\n')
+	b.Write(p)
+}
@@ -0,0 +1,324 @@
+// Package bufpool compares three ways for many goroutines to get a
+// buffer to format log lines in: one shared buffer, a fresh buffer per
+// line, and buffers reused through a sync.Pool.
+package bufpool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// ctxEvery is how many messages a goroutine formats between checks for
+// cancellation.
+const ctxEvery = 1 << 12
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	format           func(e *event, sink func([]byte))
+}{
+	{"Vibe coding", "one shared buffer, data race", vibeFormat},
+	{"Human coding", "fresh buffer, 1 alloc each", humanFormat},
+	{"Expert coding", "sync.Pool buffers, ~0 allocs", expertFormat},
+}
+
+// formatSafely formats e with format, handing the line to sink, and
+// reports whether format panicked. Only a buffer shared without a lock
+// panics, but a panic shouldn't take the other goroutines down with it.
+func formatSafely(format func(*event, func([]byte)), e *event, sink func([]byte)) (panicked bool) {
+	defer func() { panicked = recover() != nil }()
+	format(e, sink)
+	return false
+}
+
+// wantLines returns the line each of events should be formatted as.
+func wantLines(events []event) []string {
+	lines := make([]string, len(events))
+	var b bytes.Buffer
+	for i := range events {
+		b.Reset()
+		writeEvent(&b, &events[i])
+		lines[i] = b.String()
+	}
+	return lines
+}
+
+// check formats events with format from goroutines goroutines at once,
+// each taking every goroutines-th event, and returns how many lines
+// came out wrong and how many calls panicked.
+func check(ctx context.Context, format func(*event, func([]byte)), events []event, want []string, goroutines int) (wrong, panics int, err error) {
+	wrongs := make([]int, goroutines)
+	panicked := make([]int, goroutines)
+	errs := make([]error, goroutines)
+	var wg sync.WaitGroup
+	for worker := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := worker; i < len(events); i += goroutines {
+				if i%ctxEvery == worker && ctx.Err() != nil {
+					errs[worker] = ctx.Err()
+					return
+				}
+				sink := func(p []byte) {
+					if string(p) != want[i] {
+						wrongs[worker]++
+					}
+				}
+				if formatSafely(format, &events[i], sink) {
+					panicked[worker]++
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	for worker := range goroutines {
+		wrong += wrongs[worker]
+		panics += panicked[worker]
+	}
+	return wrong, panics, errors.Join(errs...)
+}
+
+// timed returns an Implementation for bench.Options.Concurrency
+// goroutines formatting events with format, each taking every
+// goroutines-th event. The sink checksums each line, standing in for
+// writing it out.
+func timed(name, complexity string, format func(*event, func([]byte)), events []event, goroutines int) bench.Implementation {
+	return bench.Implementation{
+		Name: name, Complexity: complexity,
+		RunWorker: func(ctx context.Context, worker int) error {
+			var sum uint32
+			sink := func(p []byte) { sum += crc32.ChecksumIEEE(p) }
+			for i := worker; i < len(events); i += goroutines {
+				if i%ctxEvery == worker && ctx.Err() != nil {
+					return ctx.Err()
+				}
+				formatSafely(format, &events[i], sink)
+			}
+			return nil
+		},
+	}
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "one package-level bytes.Buffer, reset for every line", Complexity: "a data race", Notes: []report.Note{
+			report.Strength("Allocates nothing - because it skips the one thing that matters"),
+			report.Pitfall("Goroutines overwrite each other's lines: the sink gets mixed-up or missing text"),
+			report.Pitfall("bytes.Buffer isn't safe for concurrent use, and can panic with its length out of range"),
+			report.Pitfall("Looks fine in a single-goroutine test; go run -race catches it"),
+		}},
+		{Label: "Human coding", Approach: "a new bytes.Buffer, sized for a line, per message", Complexity: "1 allocation per line", Notes: []report.Note{
+			report.Strength("Correct and simple: goroutines share nothing"),
+			report.Pitfall("Every line allocates, and the collector has to clear it all away again"),
+		}},
+		{Label: "Expert coding", Approach: "bytes.Buffers from a sync.Pool, reset and put back", Complexity: "~0 allocations per line", Notes: []report.Note{
+			report.Strength("Each buffer has one user at a time, and is reused afterwards"),
+			report.Strength("Per-P caches, so goroutines rarely contend for the pool"),
+			report.Pitfall("The bytes must not be used after Put, and big buffers shouldn't go back"),
+			report.Tip("A goroutine that formats many lines in a loop can simply keep one buffer of its own"),
+		}},
+	},
+	Takeaway: "Reusing memory saves allocations only if each buffer has one " +
+		"user at a time. A shared buffer is a data race, not an optimization; " +
+		"a fresh buffer is the safe default; a sync.Pool gives each goroutine " +
+		"a buffer of its own and takes it back for the next.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "37-buffer-pool",
+		Title:       "Buffer Reuse with sync.Pool",
+		Description: "Format log lines from many goroutines into one shared bytes.Buffer, a fresh buffer per line and buffers from a sync.Pool, and count the allocations per line and the lines a data race garbles.",
+		Category:    "concurrency",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("37-buffer-pool", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	goroutines := bench.Sizes{1, 4, 16}
+	fs.Var(&goroutines, "goroutines", "comma-separated numbers of goroutines formatting at once, e.g. 1,8,64")
+	messages := fs.Int("messages", 100_000, "log lines per run, shared between the goroutines")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *messages < 1 || slices.Min(goroutines) < 1 {
+		return errors.New("-messages and -goroutines must be at least 1")
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Buffer Reuse with sync.Pool", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Buffer Reuse with sync.Pool")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "GOMAXPROCS = %d; %d log lines per run (seed %d)\n", runtime.GOMAXPROCS(0), *messages, *seed)
+	if raceEnabled {
+		fmt.Fprintln(w, "Built with -race: expect the detector's DATA RACE report for Vibe coding")
+	} else {
+		fmt.Fprintln(w, "💡 Run with go run -race ./cmd/ai-coding run 37-buffer-pool to see the data race reported")
+	}
+
+	events := makeEvents(*seed, *messages)
+	want := wantLines(events)
+	for _, g := range goroutines {
+		fmt.Fprintf(out.Table, "\n%d goroutine(s) formatting log lines:\n", g)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		// A shared buffer garbles lines, but it still runs: it is timed
+		// like the others, with the damage noted.
+		var garbled []string
+		impls := make([]bench.Implementation, len(tiers))
+		for i, t := range tiers {
+			wrong, panics, err := check(ctx, t.format, events, want, g)
+			if err != nil {
+				return err
+			}
+			switch {
+			case wrong+panics > 0 && t.name != "Vibe coding":
+				return fmt.Errorf("verification failed: %s: %d wrong lines and %d panics of %d", t.name, wrong, panics, *messages)
+			case wrong+panics > 0:
+				garbled = append(garbled, fmt.Sprintf("%s garbled %d and panicked on %d of %d lines", t.name, wrong, panics, *messages))
+			}
+			impls[i] = timed(t.name, t.complexity, t.format, events, g)
+		}
+		if len(garbled) == 0 {
+			fmt.Fprintln(w, "✔ All implementations formatted every line right - Vibe coding by luck, its race can garble any run")
+		}
+		levelOpts := opts
+		levelOpts.Concurrency = g
+		results, err := bench.CompareContext(ctx, levelOpts, impls...)
+		if err != nil {
+			return err
+		}
+		label := fmt.Sprintf("%d goroutines", g)
+		if err := csvLog.Append(label, uint64(*messages), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append(label, uint64(*messages), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("%d goroutine(s)", g), results)
+		for _, note := range garbled {
+			fmt.Fprintln(w, "  ❌ "+note+" - a data race")
+			section.Notes = append(section.Notes, note)
+		}
+
+		fmt.Fprintln(out.Table, "\nPer line:")
+		for _, r := range results {
+			allocs := float64(r.Allocs) / float64(*messages)
+			fmt.Fprintf(out.Table, "  %-14s %7.1f ns  %5.2f allocs  %7.1f B   %5.1f GCs/run\n", r.Name+":",
+				float64(r.Duration.Nanoseconds())/float64(*messages), allocs,
+				float64(r.Bytes)/float64(*messages), r.GCsPerRun())
+			section.Notes = append(section.Notes, fmt.Sprintf("%s: %.2f allocations and %.1f bytes per line",
+				r.Name, allocs, float64(r.Bytes)/float64(*messages)))
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing: is every line the one it should be?")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	const perGoroutine = 10_000
+	for _, g := range []int{1, 2, 8, 64} {
+		events := makeEvents(*seed, g*perGoroutine)
+		want := wantLines(events)
+		fmt.Fprintf(w, "%d goroutine(s), %d lines (want: every line right):\n", g, len(events))
+		for _, t := range tiers {
+			wrong, panics, err := check(ctx, t.format, events, want, g)
+			if err != nil {
+				return err
+			}
+			status, result := "✅", "every line right"
+			switch {
+			case wrong+panics > 0:
+				status, result = "❌", fmt.Sprintf("%d of %d lines garbled, %d panics", wrong, len(events), panics)
+			case t.name == "Vibe coding" && g > 1:
+				status, result = "⚠️ ", "every line right, this time - it is still a data race"
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(fmt.Sprintf("%s, %d goroutine(s)", t.name, g), status+" "+result)
+		}
+	}
+
+	// A huge line, such as one with a request body logged by mistake,
+	// shouldn't leave its buffer behind, or stain the line after it.
+	huge := makeEvents(*seed, 2)
+	huge[0].Path = "/upload?data=" + strings.Repeat("x", 1<<20)
+	hugeWant := wantLines(huge)
+	fmt.Fprintln(w, "A short line after a 1 MiB one (want: both right, no 1 MiB buffer kept):")
+	for _, t := range tiers {
+		wrong, panics, err := check(ctx, t.format, huge, hugeWant, 1)
+		if err != nil {
+			return err
+		}
+		status, result := "✅", "both right"
+		switch {
+		case wrong+panics > 0:
+			status, result = "❌", fmt.Sprintf("%d of %d lines garbled, %d panics", wrong, len(events), panics)
+		case t.name == "Vibe coding" && sharedBuf.Cap() > maxPooledSize:
+			status, result = "⚠️ ", fmt.Sprintf("both right, but the shared buffer keeps %s for good", bench.FormatBytes(uint64(sharedBuf.Cap())))
+		}
+		fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+		rep.AddEdgeCase(t.name+", a short line after a 1 MiB one", status+" "+result)
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
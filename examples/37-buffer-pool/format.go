@@ -0,0 +1,82 @@
+package bufpool
+
+import (
+	"bytes"
+	"sync"
+)
+
+// lineSize is room for a typical log line, with some to spare.
+const lineSize = 256
+
+// VIBE CODING: One buffer, shared by every goroutine
+var sharedBuf bytes.Buffer
+
+func vibeFormat(e *event, sink func([]byte)) {
+	/*
+	   Allocating a buffer for every message is wasteful, so keep one
+	   and reset it each time. With one goroutine this is the fastest
+	   of the three: after the first message, nothing is allocated.
+
+	   But every goroutine logs through the same bytes.Buffer, and
+	   nothing stops two of them using it at once. One resets it while
+	   another is half-way through a line; the sink is handed a line
+	   with another's fields in it, or a slice of a buffer that is
+	   being overwritten. bytes.Buffer isn't safe for concurrent use,
+	   so it can also panic with its length out of range. It is a data
+	   race: build with -race to see it reported every time.
+	*/
+	sharedBuf.Reset()
+	writeEvent(&sharedBuf, e)
+	sink(sharedBuf.Bytes())
+}
+
+// HUMAN CODING: A fresh buffer for every message
+func humanFormat(e *event, sink func([]byte)) {
+	/*
+	   Each message gets a buffer of its own, so goroutines share
+	   nothing, and it is made big enough for a typical line, so it
+	   doesn't grow while it is written.
+
+	   Correct, and the right default. But the sink is a function
+	   value, so the compiler can't prove the bytes don't outlive the
+	   call: the buffer escapes to the heap. Every message allocates,
+	   and a busy server makes the collector run to throw away memory
+	   it will ask for again a microsecond later.
+	*/
+	b := bytes.NewBuffer(make([]byte, 0, lineSize))
+	writeEvent(b, e)
+	sink(b.Bytes())
+}
+
+// EXPERT CODING: Buffers reused through a sync.Pool
+
+// maxPooledSize is the largest buffer put back in bufPool: one huge
+// message shouldn't pin its buffer for good.
+const maxPooledSize = 64 << 10
+
+// bufPool holds buffers finished with.
+var bufPool = sync.Pool{New: func() any { return bytes.NewBuffer(make([]byte, 0, lineSize)) }}
+
+func expertFormat(e *event, sink func([]byte)) {
+	/*
+	   Take a buffer from a sync.Pool, reset it, and put it back once
+	   the sink is done with it. Each goroutine has its own buffer
+	   while it writes, as with a fresh one, but buffers are reused:
+	   in a steady stream of messages almost none allocate. The pool
+	   keeps a cache per P, so goroutines rarely contend for it.
+
+	   Three rules keep it safe. Reset what comes out, since it holds
+	   the last message. Don't touch the bytes after Put: the sink must
+	   have finished with them, or copied them. And don't put back
+	   buffers over maxPooledSize, or one huge message would keep its
+	   memory forever. The pool may drop buffers at any collection, so
+	   this saves allocations; it doesn't promise to.
+	*/
+	b := bufPool.Get().(*bytes.Buffer)
+	b.Reset()
+	writeEvent(b, e)
+	sink(b.Bytes())
+	if b.Cap() <= maxPooledSize {
+		bufPool.Put(b)
+	}
+}
@@ -0,0 +1,6 @@
+//go:build !race
+
+package bufpool
+
+// raceEnabled reports whether the program was built with -race.
+const raceEnabled = false
@@ -0,0 +1,260 @@
+// Package goroutineleak compares three ways to ask several replicas the
+// same question and take the first answer: goroutines left blocked on
+// their sends, a done channel, and a context with a buffered channel.
+package goroutineleak
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	lookup           func(ctx context.Context, table []int64, key int64, replicas int) (int, error)
+}{
+	{"Vibe coding", "unbuffered, losers blocked", vibeLookup},
+	{"Human coding", "done channel", humanLookup},
+	{"Expert coding", "context + buffered channel", expertLookup},
+}
+
+// lookups returns the implementations to compare, each looking up
+// every key in turn, asking replicas replicas.
+func lookups(table []int64, replicas int) []bench.Impl[[]int64, []int] {
+	impls := make([]bench.Impl[[]int64, []int], len(tiers))
+	for i, t := range tiers {
+		impls[i] = bench.Impl[[]int64, []int]{
+			Name: t.name, Complexity: t.complexity,
+			FuncContext: func(ctx context.Context, keys []int64) ([]int, error) {
+				found := make([]int, len(keys))
+				for k, key := range keys {
+					i, err := t.lookup(ctx, table, key, replicas)
+					if err != nil {
+						return nil, err
+					}
+					found[k] = i
+				}
+				return found, nil
+			},
+		}
+	}
+	return impls
+}
+
+// pickKeys returns n keys from table, and the index of each. An empty
+// table has no keys to pick.
+func pickKeys(seed input.Seed, table []int64, n int) (keys []int64, want []int) {
+	if len(table) == 0 {
+		return nil, nil
+	}
+	rng := seed.Rand("keys", n)
+	keys, want = make([]int64, n), make([]int, n)
+	for k := range keys {
+		want[k] = rng.IntN(len(table))
+		keys[k] = table[want[k]]
+	}
+	return keys, want
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "a goroutine per replica, sending on an unbuffered channel", Complexity: "replicas-1 goroutines leaked per lookup", Notes: []report.Note{
+			report.Strength("Short, reads well, and returns the first answer"),
+			report.Pitfall("The losers block on their sends forever: nothing will ever receive"),
+			report.Pitfall("A blocked goroutine is never collected, nor is anything it refers to"),
+			report.Pitfall("Ignores the caller's context, so a lookup can't be cancelled"),
+		}},
+		{Label: "Human coding", Approach: "every send also selects on a done channel, closed on return", Complexity: "no leaks, losers scan on", Notes: []report.Note{
+			report.Strength("Nothing leaks: the losers see done closed and exit"),
+			report.Strength("Gives up when the caller's context is done"),
+			report.Pitfall("Done only unblocks the send: the losers still finish their scans, for nothing"),
+		}},
+		{Label: "Expert coding", Approach: "a context cancelled on return, and room in the channel for every answer", Complexity: "no leaks, losers stop", Notes: []report.Note{
+			report.Strength("No send can block, so no goroutine can be stranded"),
+			report.Strength("The losers stop within microseconds, and so does everything on the caller's deadline"),
+			report.Pitfall("Only as prompt as the work is at checking its context"),
+			report.Tip("Before starting a goroutine, know how it will stop"),
+		}},
+	},
+	Takeaway: "Every goroutine needs a way to finish. A send nobody will " +
+		"receive blocks forever, and a leaked goroutine holds its memory for " +
+		"good. A done channel lets goroutines exit; a context also stops their " +
+		"work, and a buffered channel means they never wait to be heard.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "38-goroutine-leak",
+		Title:       "Goroutine Leaks",
+		Description: "Ask several replicas for a key and take the first answer, leaving the losers blocked on their sends, releasing them with a done channel, and cancelling them with a context, and count the goroutines left running.",
+		Category:    "concurrency",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("38-goroutine-leak", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{10_000, 1_000_000}
+	fs.Var(&sizes, "n", "comma-separated table sizes, e.g. 1e3,1e7")
+	replicas := fs.Int("replicas", 4, "replicas asked for every key")
+	queries := fs.Int("queries", 100, "keys looked up per run")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *replicas < 1 || *queries < 1 {
+		return errors.New("-replicas and -queries must be at least 1")
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Goroutine Leaks", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Goroutine Leaks")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "GOMAXPROCS = %d; %d replicas asked for each of %d keys per run\n", runtime.GOMAXPROCS(0), *replicas, *queries)
+
+	for _, n := range sizes {
+		table := makeTable(*seed, n)
+		keys, want := pickKeys(*seed, table, *queries)
+		fmt.Fprintf(out.Table, "\nLooking up %d keys in a table of %d (seed %d):\n", len(keys), n, *seed)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		results, err := bench.CompareImpls(ctx, opts, keys, want, bench.DiffSlices, lookups(table, *replicas)...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "✔ All implementations found every key")
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d", n), results)
+
+		fmt.Fprintln(out.Table, "\nGoroutines:")
+		for _, r := range results {
+			var added int
+			for _, g := range r.Goroutines {
+				added += g.After - g.Before
+			}
+			perRun := float64(added) / float64(len(r.Goroutines))
+			fmt.Fprintf(out.Table, "  %-14s %7.1f more after each run than before   %5d left running afterwards\n",
+				r.Name+":", perRun, r.Leaked)
+			section.Notes = append(section.Notes, fmt.Sprintf("%s: %.1f more goroutines after each run, %d left running afterwards",
+				r.Name, perRun, r.Leaked))
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing: the right answer, and nothing left running")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	small := makeTable(*seed, 10_000)
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	edgeCases := []struct {
+		desc     string
+		ctx      func() (context.Context, context.CancelFunc)
+		table    []int64
+		key      int64
+		replicas int
+		want     int   // The index to find, if err is nil
+		err      error // The error to return instead
+	}{
+		{"a key in the table", nil, small, small[1234], *replicas, 1234, nil},
+		{"a key not in the table", nil, small, -1, *replicas, -1, nil},
+		{"one replica", nil, small, small[9999], 1, 9999, nil},
+		{"the caller has already given up", func() (context.Context, context.CancelFunc) { return cancelled, func() {} },
+			small, small[1234], *replicas, 0, context.Canceled},
+	}
+	for _, tc := range edgeCases {
+		want := fmt.Sprintf("index %d", tc.want)
+		if tc.err != nil {
+			want = tc.err.Error()
+		}
+		fmt.Fprintf(w, "%s (want: %s, nothing left running):\n", tc.desc, want)
+		for _, t := range tiers {
+			callCtx, cancel := ctx, context.CancelFunc(func() {})
+			if tc.ctx != nil {
+				callCtx, cancel = tc.ctx()
+			}
+			before := runtime.NumGoroutine()
+			got, err := t.lookup(callCtx, tc.table, tc.key, tc.replicas)
+			running := runtime.NumGoroutine() - before
+			cancel()
+			left := bench.LeakedGoroutines(before)
+
+			result := fmt.Sprintf("index %d", got)
+			if err != nil {
+				result = "error: " + err.Error()
+			}
+			switch {
+			case left > 0:
+				result += fmt.Sprintf("; %d goroutines left running", left)
+			case running > 0:
+				result += fmt.Sprintf("; %d still running when it returned, none left", running)
+			}
+			status := "✅"
+			if !errors.Is(err, tc.err) || (tc.err == nil && got != tc.want) || left > 0 {
+				status = "❌"
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package goroutineleak
+
+import "context"
+
+// VIBE CODING: Ask every replica, take the first answer
+func vibeLookup(ctx context.Context, table []int64, key int64, replicas int) (int, error) {
+	/*
+	   Ask every replica at once, each in a goroutine of its own, and
+	   return whichever answers first. Fast, and it reads well.
+
+	   But the channel is unbuffered, and only the first answer is ever
+	   received. Every other replica's goroutine finishes its scan and
+	   blocks on its send forever: nothing will receive, and nothing can
+	   stop it. A goroutine blocked forever is never collected, nor is
+	   anything it refers to - here, the whole table. Each lookup leaks
+	   replicas-1 goroutines, about 8 KiB of stack each, and a server
+	   making lookups all day runs out of memory. The caller's ctx is
+	   ignored too, so a lookup can't be cancelled.
+	*/
+	answers := make(chan int)
+	for r := range replicas {
+		go func() {
+			i, _ := scan(context.Background(), table, key, start(r, replicas, len(table)))
+			answers <- i
+		}()
+	}
+	return <-answers, nil
+}
+
+// HUMAN CODING: A done channel, closed when the lookup returns
+func humanLookup(ctx context.Context, table []int64, key int64, replicas int) (int, error) {
+	/*
+	   Every send selects on done as well, and done is closed when the
+	   lookup returns. The replicas that lose the race see done closed
+	   instead of blocking, and exit: nothing leaks. The lookup also
+	   gives up when the caller's ctx is done.
+
+	   But done only unblocks the send at the end. The losing replicas
+	   still scan the whole table first, for an answer nobody wants,
+	   and keep running after the lookup has returned - and after the
+	   caller has given up. That is CPU taken from the next lookup.
+	*/
+	done := make(chan struct{})
+	defer close(done)
+	answers := make(chan int)
+	for r := range replicas {
+		go func() {
+			i, _ := scan(context.Background(), table, key, start(r, replicas, len(table)))
+			select {
+			case answers <- i:
+			case <-done:
+			}
+		}()
+	}
+	select {
+	case i := <-answers:
+		return i, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// EXPERT CODING: A context to cancel the losers, a buffer for their answers
+func expertLookup(ctx context.Context, table []int64, key int64, replicas int) (int, error) {
+	/*
+	   Derive a context, and cancel it when the lookup returns. The
+	   scans check it as they go, so the losing replicas stop within a
+	   few microseconds instead of finishing their scans. The caller's
+	   own deadline and cancellation reach them the same way.
+
+	   The channel has room for every replica's answer, so no send can
+	   ever block, whoever is or isn't still receiving: no done channel
+	   is needed, and no goroutine can be stranded. A scan that was
+	   cancelled sends nothing, so the first answer received is always
+	   a real one.
+	*/
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	answers := make(chan int, replicas)
+	for r := range replicas {
+		go func() {
+			if i, ok := scan(ctx, table, key, start(r, replicas, len(table))); ok {
+				answers <- i
+			}
+		}()
+	}
+	select {
+	case i := <-answers:
+		return i, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
@@ -0,0 +1,49 @@
+package goroutineleak
+
+import (
+	"context"
+
+	"github.com/iportilla/ai-coding/input"
+)
+
+// checkEvery is how many entries a scan checks between looks at its
+// context.
+const checkEvery = 1 << 10
+
+// makeTable returns n distinct keys, in no particular order.
+func makeTable(seed input.Seed, n int) []int64 {
+	rng := seed.Rand("table", n)
+	table := make([]int64, n)
+	for i, k := range rng.Perm(n) {
+		table[i] = int64(k)*7 + 3
+	}
+	return table
+}
+
+// start returns where replica r of replicas begins its scan of a table
+// of n entries. The replicas start evenly spread out, so whichever is
+// nearest to the key finds it first.
+func start(r, replicas, n int) int {
+	return r * n / replicas
+}
+
+// scan stands for asking one replica for key: it looks through the
+// whole table, starting at from and wrapping round, and returns the
+// index of key, or -1 if it isn't there. It gives up, returning false,
+// once ctx is done.
+func scan(ctx context.Context, table []int64, key int64, from int) (int, bool) {
+	n := len(table)
+	for j := range n {
+		if j%checkEvery == 0 && ctx.Err() != nil {
+			return 0, false
+		}
+		i := from + j
+		if i >= n {
+			i -= n
+		}
+		if table[i] == key {
+			return i, true
+		}
+	}
+	return -1, true
+}
@@ -0,0 +1,103 @@
+package echo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/iportilla/ai-coding/input"
+)
+
+// clientTimeout bounds a whole connection's conversation, so that a
+// server that never answers fails the load instead of hanging it.
+const clientTimeout = 30 * time.Second
+
+// makeLines returns n lines of size bytes each, the last a newline,
+// for client c to send.
+func makeLines(seed input.Seed, c, n, size int) [][]byte {
+	rng := seed.Rand(fmt.Sprintf("lines-%d", c), n*size)
+	lines := make([][]byte, n)
+	for i := range lines {
+		line := make([]byte, size)
+		for j := range size - 1 {
+			line[j] = byte('a' + rng.IntN(26))
+		}
+		line[size-1] = '\n'
+		lines[i] = line
+	}
+	return lines
+}
+
+// load is what a load run measured.
+type load struct {
+	latencies []time.Duration // Of every line, from sending it to reading its echo
+	bytes     int64           // Echoed, in total
+	elapsed   time.Duration
+}
+
+// runLoad connects one client per element of lines to addr, all at
+// once, and has each send its lines one at a time, waiting for every
+// echo before sending the next. It fails if any echo is wrong.
+func runLoad(ctx context.Context, addr string, lines [][][]byte) (load, error) {
+	latencies := make([][]time.Duration, len(lines))
+	errs := make([]error, len(lines))
+	start := time.Now()
+	var wg sync.WaitGroup
+	for c := range lines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			latencies[c], errs[c] = converse(ctx, addr, lines[c])
+		}()
+	}
+	wg.Wait()
+	l := load{elapsed: time.Since(start)}
+	if err := errors.Join(errs...); err != nil {
+		return l, err
+	}
+	for c := range lines {
+		l.latencies = append(l.latencies, latencies[c]...)
+		for _, line := range lines[c] {
+			l.bytes += int64(len(line))
+		}
+	}
+	return l, nil
+}
+
+// converse sends lines to addr over one connection, one at a time, and
+// returns how long each took to come back.
+func converse(ctx context.Context, addr string, lines [][]byte) ([]time.Duration, error) {
+	var d net.Dialer
+	c, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+	stop := context.AfterFunc(ctx, func() { c.Close() })
+	defer stop()
+	c.SetDeadline(time.Now().Add(clientTimeout))
+
+	latencies := make([]time.Duration, len(lines))
+	var buf []byte
+	for i, line := range lines {
+		sent := time.Now()
+		if _, err := c.Write(line); err != nil {
+			return nil, err
+		}
+		buf = slices.Grow(buf[:0], len(line))[:len(line)]
+		if _, err := io.ReadFull(c, buf); err != nil {
+			return nil, err
+		}
+		latencies[i] = time.Since(sent)
+		if !bytes.Equal(buf, line) {
+			return nil, fmt.Errorf("line %d: echoed %q, want %q", i, buf, line)
+		}
+	}
+	return latencies, ctx.Err()
+}
@@ -0,0 +1,370 @@
+// Package echo compares three TCP echo servers: one that serves one
+// connection at a time, one with a goroutine per connection, and one that
+// adds deadlines, limits and a graceful shutdown, driven by a load
+// generator that reports throughput and latency.
+package echo
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	newServer        func(l net.Listener) server
+}{
+	{"Vibe coding", "one connection at a time", newVibeServer},
+	{"Human coding", "goroutine per connection", newHumanServer},
+	{"Expert coding", "+ deadlines, limits, shutdown", newExpertServer},
+}
+
+// start starts a server made by newServer on a port of its own, and
+// returns it with its address.
+func start(newServer func(net.Listener) server) (server, string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, "", err
+	}
+	s := newServer(l)
+	go s.Serve()
+	return s, l.Addr().String(), nil
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "accept, io.Copy, close, accept the next", Complexity: "one connection at a time", Notes: []report.Note{
+			report.Strength("Short and obviously right, for one client"),
+			report.Pitfall("Every client waits for all the ones before it: latency grows with the queue"),
+			report.Pitfall("One client that connects and says nothing stops the server for everyone"),
+		}},
+		{Label: "Human coding", Approach: "a goroutine per connection, running io.Copy", Complexity: "connections served at once", Notes: []report.Note{
+			report.Strength("A slow client only holds up itself, and goroutines are cheap"),
+			report.Pitfall("Nothing times out: a quiet client keeps its goroutine and socket for good"),
+			report.Pitfall("No limit on connections, so enough clients exhaust memory or file descriptors"),
+			report.Pitfall("Closing the listener leaves every open connection running"),
+		}},
+		{Label: "Expert coding", Approach: "a goroutine per connection, with deadlines, limits and a graceful shutdown", Complexity: "connections served at once, up to a limit", Notes: []report.Note{
+			report.Strength("Idle, slow and non-reading clients are disconnected"),
+			report.Strength("Connections and line lengths are capped; extra clients wait to be accepted"),
+			report.Strength("Shutdown finishes lines in flight, closes the rest, and waits for every goroutine"),
+			report.Pitfall("More code and more state: every limit is a choice, and needs tuning"),
+			report.Tip("net/http.Server does all this for HTTP; ReadTimeout, IdleTimeout and Shutdown are its knobs"),
+		}},
+	},
+	Takeaway: "A goroutine per connection is the Go way to serve many clients, " +
+		"but a server facing the network also needs deadlines for every read " +
+		"and write, a limit on what it takes on, and a shutdown that waits for " +
+		"what it started.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "39-echo-server",
+		Title:       "TCP Echo Servers",
+		Description: "Serve a line-based echo protocol one connection at a time, with a goroutine per connection, and with deadlines, limits and a graceful shutdown, under load from many clients.",
+		Category:    "networking",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("39-echo-server", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	clients := bench.Sizes{1, 16, 128}
+	fs.Var(&clients, "clients", "comma-separated numbers of clients connected at once, e.g. 1,64,512")
+	lines := fs.Int("lines", 20, "lines each client sends per run, one at a time")
+	size := fs.Int("size", 64, "bytes per line, the newline included")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *lines < 1 || *size < 1 || *size > maxLine {
+		return fmt.Errorf("-lines must be at least 1, and -size from 1 to %d", maxLine)
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("TCP Echo Servers", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: TCP Echo Servers")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "GOMAXPROCS = %d; each client sends %d lines of %d bytes per run, one at a time\n", runtime.GOMAXPROCS(0), *lines, *size)
+
+	addrs := make([]string, len(tiers))
+	for i, t := range tiers {
+		s, addr, err := start(t.newServer)
+		if err != nil {
+			return err
+		}
+		defer s.Shutdown(context.Background())
+		addrs[i] = addr
+	}
+
+	for _, n := range clients {
+		sent := make([][][]byte, n)
+		for c := range sent {
+			sent[c] = makeLines(*seed, c, *lines, *size)
+		}
+		fmt.Fprintf(out.Table, "\n%d client(s), %d lines each (seed %d):\n", n, *lines, *seed)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		// Every run checks every echo, so a server that gets one wrong
+		// fails the comparison.
+		loads := make([]load, len(tiers))
+		impls := make([]bench.Implementation, len(tiers))
+		for i, t := range tiers {
+			impls[i] = bench.Implementation{
+				Name: t.name, Complexity: t.complexity,
+				RunContext: func(ctx context.Context) error {
+					l, err := runLoad(ctx, addrs[i], sent)
+					if err != nil {
+						return fmt.Errorf("%s: %w", t.name, err)
+					}
+					loads[i] = l
+					return nil
+				},
+			}
+		}
+		results, err := bench.CompareContext(ctx, opts, impls...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "✔ Every server echoed every line right")
+		label := fmt.Sprintf("%d clients", n)
+		if err := csvLog.Append(label, uint64(n**lines), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append(label, uint64(n**lines), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("%d client(s)", n), results)
+
+		fmt.Fprintln(out.Table, "\nThroughput and latency per line, from the last run:")
+		for i, r := range results {
+			l := loads[i]
+			p99 := bench.Percentile(l.latencies, 99)
+			fmt.Fprintf(out.Table, "  %-14s %9.0f lines/s  %6.2f MB/s   p50 %8s   p99 %8s   max %8s\n",
+				r.Name+":", float64(len(l.latencies))/l.elapsed.Seconds(), float64(l.bytes)/1e6/l.elapsed.Seconds(),
+				latency(bench.Percentile(l.latencies, 50)), latency(p99), latency(bench.Percentile(l.latencies, 100)))
+			section.Notes = append(section.Notes, fmt.Sprintf("%s: %.0f lines/s, p99 %s",
+				r.Name, float64(len(l.latencies))/l.elapsed.Seconds(), latency(p99)))
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing: clients that misbehave, and shutting down")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	// Goroutines serving an earlier case's clients may take a moment to
+	// exit, so each case waits for the count to settle back first.
+	baseline := runtime.NumGoroutine()
+	for _, tc := range edgeCases {
+		fmt.Fprintf(w, "%s (want: %s):\n", tc.desc, tc.want)
+		for _, t := range tiers {
+			bench.LeakedGoroutines(baseline)
+			before := runtime.NumGoroutine()
+			s, addr, err := start(t.newServer)
+			if err != nil {
+				return err
+			}
+			result, good := tc.check(s, addr, before)
+			s.Shutdown(context.Background())
+
+			status := "✅"
+			if !good {
+				status = "❌"
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
+
+// wait is how long an edge case waits for an answer it expects.
+const wait = 500 * time.Millisecond
+
+// edgeCases each check one thing about a server started for them,
+// with before the goroutines alive before it started, and close their
+// connections before returning.
+var edgeCases = []struct {
+	desc, want string
+	check      func(s server, addr string, before int) (result string, good bool)
+}{
+	{"one client, one line", "the line echoed", func(s server, addr string, _ int) (string, bool) {
+		c, err := net.Dial("tcp", addr)
+		if err != nil {
+			return err.Error(), false
+		}
+		defer c.Close()
+		return echoed(c, "hello\n")
+	}},
+	{"a second client, while the first says nothing", "the second's line echoed", func(s server, addr string, _ int) (string, bool) {
+		quiet, err := net.Dial("tcp", addr)
+		if err != nil {
+			return err.Error(), false
+		}
+		defer quiet.Close()
+		time.Sleep(20 * time.Millisecond) // Let the server take the quiet client first
+		c, err := net.Dial("tcp", addr)
+		if err != nil {
+			return err.Error(), false
+		}
+		defer c.Close()
+		return echoed(c, "hello\n")
+	}},
+	{fmt.Sprintf("a client that says nothing for %s", 2*idleTimeout), "the server hangs up", func(s server, addr string, _ int) (string, bool) {
+		c, err := net.Dial("tcp", addr)
+		if err != nil {
+			return err.Error(), false
+		}
+		defer c.Close()
+		c.SetReadDeadline(time.Now().Add(2 * idleTimeout))
+		return hungUp(c)
+	}},
+	{"shutdown while a client is half-way through a line", "the line echoed, then the connection closed and nothing left running", func(s server, addr string, before int) (string, bool) {
+		c, err := net.Dial("tcp", addr)
+		if err != nil {
+			return err.Error(), false
+		}
+		defer c.Close()
+		if _, err := io.WriteString(c, "hel"); err != nil {
+			return err.Error(), false
+		}
+		time.Sleep(20 * time.Millisecond)
+		shutdown := make(chan error, 1)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*wait)
+			defer cancel()
+			shutdown <- s.Shutdown(ctx)
+		}()
+		time.Sleep(20 * time.Millisecond)
+		if _, err := io.WriteString(c, "lo\n"); err != nil {
+			return "the line's end couldn't be sent: " + err.Error(), false
+		}
+		result, good := answered(c, "hello\n", time.Now())
+		if !good {
+			return result, false
+		}
+		c.SetReadDeadline(time.Now().Add(wait))
+		closed, good := hungUp(c)
+		if err := <-shutdown; err != nil {
+			return fmt.Sprintf("%s; %s; shutdown: %v", result, closed, err), false
+		}
+		if left := bench.LeakedGoroutines(before); left > 0 {
+			return fmt.Sprintf("%s; %s; %d goroutines left running", result, closed, left), false
+		}
+		return result + "; " + closed, good
+	}},
+}
+
+// echoed sends line over c and reports whether it comes back within
+// wait.
+func echoed(c net.Conn, line string) (string, bool) {
+	start := time.Now()
+	c.SetWriteDeadline(start.Add(wait))
+	if _, err := io.WriteString(c, line); err != nil {
+		return "couldn't send: " + err.Error(), false
+	}
+	return answered(c, line, start)
+}
+
+// answered reports whether line, sent at start, comes back over c
+// within wait.
+func answered(c net.Conn, line string, start time.Time) (string, bool) {
+	c.SetReadDeadline(start.Add(wait))
+	defer c.SetReadDeadline(time.Time{})
+	buf := make([]byte, len(line))
+	if _, err := io.ReadFull(c, buf); err != nil {
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return fmt.Sprintf("no answer in %s", wait), false
+		}
+		return "no answer: " + err.Error(), false
+	}
+	if string(buf) != line {
+		return fmt.Sprintf("echoed %q", buf), false
+	}
+	return fmt.Sprintf("echoed in %s", latency(time.Since(start))), true
+}
+
+// hungUp reports whether the server closes c before c's read deadline.
+func hungUp(c net.Conn) (string, bool) {
+	start := time.Now()
+	_, err := c.Read(make([]byte, 1))
+	switch {
+	case errors.Is(err, os.ErrDeadlineExceeded):
+		return fmt.Sprintf("still open after %s", millis(time.Since(start))), false
+	case err != nil:
+		return fmt.Sprintf("closed after %s", millis(time.Since(start))), true
+	}
+	return "sent something unasked", false
+}
+
+// millis formats d in milliseconds, e.g. 12.3ms.
+func millis(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+}
+
+// latency formats d in microseconds below a millisecond, e.g. 85µs, and
+// in milliseconds above.
+func latency(d time.Duration) string {
+	if d < time.Millisecond {
+		return fmt.Sprintf("%.0fµs", float64(d)/float64(time.Microsecond))
+	}
+	return millis(d)
+}
@@ -0,0 +1,258 @@
+package echo
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// server is an echo server: it writes back every line a client sends.
+type server interface {
+	// Serve accepts connections until Shutdown is called.
+	Serve() error
+	// Shutdown stops the server, giving up when ctx is done.
+	Shutdown(ctx context.Context) error
+}
+
+// VIBE CODING: One connection at a time
+type vibeServer struct{ l net.Listener }
+
+func newVibeServer(l net.Listener) server { return &vibeServer{l: l} }
+
+func (s *vibeServer) Serve() error {
+	/*
+	   Accept a connection, echo it until the client hangs up, then
+	   accept the next. io.Copy does the echoing, so it is short and
+	   obviously right - with one client.
+
+	   With more, every client waits for all the ones before it to
+	   finish, however little they are doing. Their connections are
+	   accepted by the kernel and sit in the listen backlog, and their
+	   lines go unanswered. One client that connects and says nothing
+	   stops the server dead for everyone.
+	*/
+	for {
+		c, err := s.l.Accept()
+		if err != nil {
+			return err
+		}
+		io.Copy(c, c)
+		c.Close()
+	}
+}
+
+func (s *vibeServer) Shutdown(ctx context.Context) error { return s.l.Close() }
+
+// HUMAN CODING: A goroutine per connection
+type humanServer struct{ l net.Listener }
+
+func newHumanServer(l net.Listener) server { return &humanServer{l: l} }
+
+func (s *humanServer) Serve() error {
+	/*
+	   Hand each connection to a goroutine of its own and go straight
+	   back to Accept. Goroutines are cheap, so a thousand clients get
+	   a thousand goroutines, and a slow one only holds up itself. This
+	   is how most Go servers start, and it scales well.
+
+	   But nothing ever times out: a client that connects and goes
+	   quiet keeps its goroutine and its socket for good, and enough of
+	   them run the server out of file descriptors. There is no limit
+	   on connections either. And closing the listener only stops new
+	   connections: every connection already open carries on, with
+	   nothing left to wait for it or stop it.
+	*/
+	for {
+		c, err := s.l.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			defer c.Close()
+			io.Copy(c, c)
+		}()
+	}
+}
+
+func (s *humanServer) Shutdown(ctx context.Context) error { return s.l.Close() }
+
+// EXPERT CODING: Deadlines, limits and a graceful shutdown
+
+// Limits for expertServer. The idle timeout is short so that the
+// example's edge cases don't wait long; a real server would allow a
+// minute or more.
+const (
+	idleTimeout  = 250 * time.Millisecond // To start a line
+	lineTimeout  = 5 * time.Second        // To finish one once started
+	writeTimeout = 5 * time.Second        // To take an echoed line
+	maxConns     = 64                     // Served at once; more wait to be accepted
+	maxLine      = 4 << 10                // Longest line, in bytes, and the read buffer's size
+)
+
+type expertServer struct {
+	l       net.Listener
+	slots   chan struct{} // One for each connection being served
+	closing atomic.Bool
+	done    chan struct{} // Closed by Shutdown
+
+	mu    sync.Mutex
+	conns map[*expertConn]struct{}
+	wg    sync.WaitGroup
+}
+
+// expertConn is a connection expertServer is serving.
+type expertConn struct {
+	net.Conn
+	mu   sync.Mutex
+	idle bool // Waiting for a line to start
+}
+
+func newExpertServer(l net.Listener) server {
+	return &expertServer{
+		l:     l,
+		slots: make(chan struct{}, maxConns),
+		done:  make(chan struct{}),
+		conns: make(map[*expertConn]struct{}),
+	}
+}
+
+func (s *expertServer) Serve() error {
+	/*
+	   A goroutine per connection, as before, with the limits every
+	   server exposed to real clients needs:
+
+	   - Deadlines. A client has idleTimeout to start a line and
+	     lineTimeout to finish it, and must take the echo within
+	     writeTimeout. A client that goes quiet, or stops reading,
+	     loses its connection instead of keeping it forever.
+	   - Limits. At most maxConns connections are served at once; the
+	     rest wait to be accepted, rather than running the server out
+	     of memory or file descriptors. A line longer than maxLine
+	     ends the connection instead of being buffered.
+	   - Graceful shutdown. Shutdown stops accepting, closes the
+	     connections waiting for a line at once, lets the ones part-way
+	     through a line finish it, and waits for every goroutine to
+	     exit - or closes whatever is left when its context is done.
+	*/
+	for {
+		select {
+		case s.slots <- struct{}{}:
+		case <-s.done:
+			return nil
+		}
+		c, err := s.l.Accept()
+		if err != nil {
+			<-s.slots
+			if s.closing.Load() {
+				return nil
+			}
+			return err
+		}
+		// Register the connection under s.mu, which Shutdown takes after
+		// setting closing: either Shutdown sees the connection, or the
+		// connection sees Shutdown.
+		ec := &expertConn{Conn: c}
+		s.mu.Lock()
+		if s.closing.Load() {
+			s.mu.Unlock()
+			c.Close()
+			<-s.slots
+			return nil
+		}
+		s.conns[ec] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+		go s.serveConn(ec)
+	}
+}
+
+// serveConn echoes c's lines until the client hangs up, breaks a limit
+// or the server shuts down.
+func (s *expertServer) serveConn(c *expertConn) {
+	defer func() {
+		c.Close()
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+		<-s.slots
+		s.wg.Done()
+	}()
+	r := bufio.NewReaderSize(c, maxLine)
+	for {
+		if !s.setIdle(c, true) {
+			return
+		}
+		if _, err := r.Peek(1); err != nil {
+			return // Hung up, idle too long, or shutting down
+		}
+		s.setIdle(c, false)
+		c.SetReadDeadline(time.Now().Add(lineTimeout))
+		line, err := r.ReadSlice('\n')
+		if err != nil {
+			return // Hung up part-way, too slow, or too long
+		}
+		c.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if _, err := c.Write(line); err != nil {
+			return
+		}
+	}
+}
+
+// setIdle marks c as waiting for a line, with idleTimeout to start one,
+// or as part-way through one. It returns false, instead of marking c
+// idle, if the server is shutting down.
+func (s *expertServer) setIdle(c *expertConn, idle bool) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if idle && s.closing.Load() {
+		return false
+	}
+	c.idle = idle
+	if idle {
+		c.SetReadDeadline(time.Now().Add(idleTimeout))
+	}
+	return true
+}
+
+func (s *expertServer) Shutdown(ctx context.Context) error {
+	if s.closing.Swap(true) {
+		return errors.New("echo: already shut down")
+	}
+	close(s.done)
+	err := s.l.Close()
+
+	// Wake the connections waiting for a line; the rest notice once
+	// their line is done.
+	s.mu.Lock()
+	for c := range s.conns {
+		c.mu.Lock()
+		if c.idle {
+			c.SetReadDeadline(time.Now())
+		}
+		c.mu.Unlock()
+	}
+	s.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return err
+	case <-ctx.Done():
+		s.mu.Lock()
+		for c := range s.conns {
+			c.Close()
+		}
+		s.mu.Unlock()
+		<-finished
+		return ctx.Err()
+	}
+}
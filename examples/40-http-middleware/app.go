@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// The service's routes, whichever way it is built:
+//
+//	GET /hello/{name}  greets name
+//	GET /report        builds a report, reportWork long, honoring its context
+//	GET /latest?id=..  echoes the first id, and panics if there is none
+//	GET /legacy        calls a library that takes legacyWork and no context
+const (
+	reportWork = 80 * time.Millisecond
+	legacyWork = 300 * time.Millisecond
+)
+
+// greet is the greeting for name.
+func greet(name string) string { return "hello, " + name }
+
+// buildReport does reportWork of work, a step at a time, giving up when
+// ctx is done.
+func buildReport(ctx context.Context) (string, error) {
+	const steps = 8
+	for range steps {
+		t := time.NewTimer(reportWork / steps)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return "", ctx.Err()
+		}
+	}
+	return fmt.Sprintf("report: %d sections", steps), nil
+}
+
+// latest returns the first of ids. It has a bug: it indexes before
+// checking there is anything to index, so a request without an id
+// panics.
+func latest(ids []string) string { return ids[0] }
+
+// legacyLookup stands for a call into a library that takes no context:
+// once called, nothing can stop it.
+func legacyLookup() string {
+	time.Sleep(legacyWork)
+	return "legacy: ok"
+}
+
+// The handlers for the routes, shared by the human and expert versions.
+
+func helloHandler(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintln(w, greet(r.PathValue("name")))
+}
+
+func reportHandler(w http.ResponseWriter, r *http.Request) {
+	report, err := buildReport(r.Context())
+	if err != nil {
+		http.Error(w, "report: "+err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprintln(w, report)
+}
+
+func latestHandler(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintln(w, latest(r.URL.Query()["id"]))
+}
+
+func legacyHandler(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintln(w, legacyLookup())
+}
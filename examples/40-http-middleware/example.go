@@ -0,0 +1,341 @@
+// Package middleware compares three ways to build an HTTP service with
+// logging, panic recovery and timeouts: one handler with no structure,
+// middleware written and chained by hand, and a composable middleware
+// stack configured per route, checked by requests through httptest.
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	newService       func(logs io.Writer) http.Handler
+}{
+	{"Vibe coding", "one handler, switch on path", newVibeService},
+	{"Human coding", "hand-chained middleware", newHumanService},
+	{"Expert coding", "middleware stack per route", newExpertService},
+}
+
+// greeting is what every timed request asks for, and wantBody the answer.
+const greeting = "/hello/gopher"
+
+var wantBody = []byte("hello, gopher\n")
+
+// serve sends h n requests for greeting, in process, and returns how
+// many it answered correctly.
+func serve(h http.Handler, n int) int {
+	ok := 0
+	for range n {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, greeting, nil))
+		if rec.Code == http.StatusOK && bytes.Equal(rec.Body.Bytes(), wantBody) {
+			ok++
+		}
+	}
+	return ok
+}
+
+// services returns the implementations to compare, each logging to
+// io.Discard.
+func services() []bench.Impl[int, int] {
+	impls := make([]bench.Impl[int, int], len(tiers))
+	for i, t := range tiers {
+		h := t.newService(io.Discard)
+		impls[i] = bench.Impl[int, int]{
+			Name: t.name, Complexity: t.complexity,
+			Func: func(n int) int { return serve(h, n) },
+		}
+	}
+	return impls
+}
+
+// lockedBuffer is a bytes.Buffer that a server's goroutines can log to
+// while the example reads it.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// take returns what has been logged since it was last called.
+func (b *lockedBuffer) take() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := b.buf.String()
+	b.buf.Reset()
+	return s
+}
+
+// exchange is how one request over the network went.
+type exchange struct {
+	status  int
+	body    string
+	err     error
+	elapsed time.Duration
+	logged  string // What the service logged meanwhile
+}
+
+// loggedStatus finds the status in a log line.
+var loggedStatus = regexp.MustCompile(`status=(\d+)`)
+
+// send makes one request to srv, and collects what it logged to logs.
+func send(srv *httptest.Server, logs *lockedBuffer, method, path string) exchange {
+	logs.take()
+	req, err := http.NewRequest(method, srv.URL+path, nil)
+	if err != nil {
+		return exchange{err: err}
+	}
+	start := time.Now()
+	resp, err := srv.Client().Do(req)
+	var e exchange
+	if err == nil {
+		var body []byte
+		body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		e.status, e.body = resp.StatusCode, string(body)
+	}
+	if ue, ok := err.(*url.Error); ok {
+		err = ue.Err // Without the URL, whose port changes every run
+	}
+	e.err, e.elapsed, e.logged = err, time.Since(start), logs.take()
+	return e
+}
+
+// String describes e, e.g. 200 "hello, gopher" in 0.2ms, logged as 200.
+func (e exchange) String() string {
+	s := fmt.Sprintf("no response after %s: %v", millis(e.elapsed), e.err)
+	if e.err == nil {
+		s = fmt.Sprintf("%d %q in %s", e.status, strings.TrimSpace(e.body), millis(e.elapsed))
+	}
+	switch m := loggedStatus.FindStringSubmatch(e.logged); {
+	case m != nil:
+		s += ", logged as " + m[1]
+	case e.logged != "":
+		s += ", logged without a status"
+	default:
+		s += ", not logged"
+	}
+	return s
+}
+
+// loggedAs reports whether e was logged with its own status.
+func (e exchange) loggedAs() bool {
+	m := loggedStatus.FindStringSubmatch(e.logged)
+	return m != nil && m[1] == fmt.Sprint(e.status)
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "one handler switching on the path, logging at the end", Complexity: "no middleware", Notes: []report.Note{
+			report.Strength("Every route in one place, and nothing else to learn"),
+			report.Pitfall("Methods aren't checked, and the log has no status"),
+			report.Pitfall("A panic drops the connection: no response, and nothing logged"),
+			report.Pitfall("No time limits, and every new concern is pasted into every route"),
+		}},
+		{Label: "Human coding", Approach: "ServeMux, with logging, recovery and a context deadline nested by hand", Complexity: "one stack for every route", Notes: []report.Note{
+			report.Strength("Method patterns, and panics answered with a logged 500"),
+			report.Pitfall("The status recorder misses the implicit 200, so most requests are logged as 0"),
+			report.Pitfall("A context deadline only stops handlers that check it"),
+			report.Pitfall("One timeout for every route: too long for some, too short for others"),
+		}},
+		{Label: "Expert coding", Approach: "typed middleware composed by chain, with settings and a stack per route", Complexity: "+ a goroutine per request, for TimeoutHandler", Notes: []report.Note{
+			report.Strength("Every request logged with its real status, size and time"),
+			report.Strength("Each route gets its own timeout, answered with a 503 even if the handler can't stop"),
+			report.Strength("The stack reads in the order a request meets it"),
+			report.Pitfall("TimeoutHandler buffers responses, so streaming routes need a different timeout"),
+		}},
+	},
+	Takeaway: "Cross-cutting concerns belong in middleware, composed in one " +
+		"readable order. Wrapping a ResponseWriter means handling the implicit " +
+		"200 and unwrapping; a timeout only protects the client if it answers " +
+		"for the handler; and routes rarely want the same limits.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "40-http-middleware",
+		Title:       "HTTP Middleware",
+		Description: "Build an HTTP service with logging, panic recovery and timeouts, as one unstructured handler, hand-chained middleware, and a composable middleware stack configured per route, checked with httptest.",
+		Category:    "networking",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("40-http-middleware", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{1_000, 100_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of requests per run, e.g. 1e3,1e6")
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	for _, n := range sizes {
+		if n < 1 {
+			return fmt.Errorf("-n must be at least 1, not %d", n)
+		}
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("HTTP Middleware", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: HTTP Middleware")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "Every run sends GET %s through the whole service, in process with httptest, logging to io.Discard\n", greeting)
+
+	impls := services()
+	for _, n := range sizes {
+		fmt.Fprintf(out.Table, "\nServing %d requests:\n", n)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		results, err := bench.CompareImpls(ctx, opts, int(n), int(n), bench.Equal[int], impls...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "✔ All implementations answered every request")
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d", n), results)
+
+		fmt.Fprintln(out.Table, "\nPer request:")
+		for _, r := range results {
+			allocs := float64(r.Allocs) / float64(n)
+			fmt.Fprintf(out.Table, "  %-14s %7.2f µs  %5.1f allocs  %7.1f B\n", r.Name+":",
+				float64(r.Duration.Nanoseconds())/float64(n)/1e3, allocs, float64(r.Bytes)/float64(n))
+			section.Notes = append(section.Notes, fmt.Sprintf("%s: %.2f µs, %.1f allocations and %.1f bytes per request",
+				r.Name, float64(r.Duration.Nanoseconds())/float64(n)/1e3, allocs, float64(r.Bytes)/float64(n)))
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing: requests to a real server, from httptest.NewServer")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		desc, method, path, want string
+		good                     func(e exchange) bool
+		thenServes               bool // The server must still answer a greeting afterwards
+	}{
+		{"GET " + greeting, http.MethodGet, greeting, `200 "hello, gopher", logged as 200`,
+			func(e exchange) bool { return e.status == http.StatusOK && e.body == string(wantBody) && e.loggedAs() }, false},
+		{"POST " + greeting, http.MethodPost, greeting, "405 Method Not Allowed",
+			func(e exchange) bool { return e.status == http.StatusMethodNotAllowed }, false},
+		{"GET /latest with no id, which panics", http.MethodGet, "/latest", "500, logged as 500, and still serving",
+			func(e exchange) bool { return e.status == http.StatusInternalServerError && e.loggedAs() }, true},
+		{fmt.Sprintf("GET /report, which takes %v", reportWork), http.MethodGet, "/report", "200 with the report",
+			func(e exchange) bool { return e.status == http.StatusOK }, false},
+		{fmt.Sprintf("GET /legacy, which can't be stopped for %v", legacyWork), http.MethodGet, "/legacy",
+			"503 well before the call returns",
+			func(e exchange) bool { return e.status == http.StatusServiceUnavailable && e.elapsed < legacyWork/2 }, false},
+	}
+
+	servers := make([]*httptest.Server, len(tiers))
+	logs := make([]*lockedBuffer, len(tiers))
+	for i, t := range tiers {
+		logs[i] = new(lockedBuffer)
+		servers[i] = httptest.NewUnstartedServer(t.newService(logs[i]))
+		// net/http logs the panics that reach it, with a stack trace.
+		servers[i].Config.ErrorLog = log.New(io.Discard, "", 0)
+		servers[i].Start()
+		defer servers[i].Close()
+	}
+	for _, tc := range edgeCases {
+		fmt.Fprintf(w, "%s (want: %s):\n", tc.desc, tc.want)
+		for i, t := range tiers {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			e := send(servers[i], logs[i], tc.method, tc.path)
+			result, good := e.String(), tc.good(e)
+			if tc.thenServes {
+				next := send(servers[i], logs[i], http.MethodGet, greeting)
+				then := fmt.Sprint(next.status)
+				if next.err != nil {
+					then = next.err.Error()
+				}
+				result += fmt.Sprintf("; then GET %s: %s", greeting, then)
+				good = good && next.status == http.StatusOK
+			}
+			status := "✅"
+			if !good {
+				status = "❌"
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
+
+// millis formats d in milliseconds, e.g. 12.3ms.
+func millis(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+}
@@ -0,0 +1,256 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// VIBE CODING: One handler, no structure
+func newVibeService(logs io.Writer) http.Handler {
+	/*
+	   One handler for everything: switch on the path, do the work, and
+	   log the request at the end. Nothing else to learn, and every
+	   route is in one place.
+
+	   But there is no structure to put anything in. The method is
+	   never checked, so POST /hello/x is greeted like GET. The log
+	   line has no status, because the handler never kept it. A panic
+	   in any route escapes to net/http, which logs a stack trace and
+	   drops the connection: the client gets no response at all, and
+	   the request is never logged. And nothing puts a time limit on
+	   anything. Every new concern - auth, metrics, timeouts - has to
+	   be pasted into every case.
+	*/
+	logger := log.New(logs, "", log.LstdFlags)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/hello/"):
+			fmt.Fprintln(w, greet(strings.TrimPrefix(r.URL.Path, "/hello/")))
+		case r.URL.Path == "/report":
+			report, err := buildReport(r.Context())
+			if err != nil {
+				http.Error(w, "report: "+err.Error(), http.StatusServiceUnavailable)
+				break
+			}
+			fmt.Fprintln(w, report)
+		case r.URL.Path == "/latest":
+			fmt.Fprintln(w, latest(r.URL.Query()["id"]))
+		case r.URL.Path == "/legacy":
+			fmt.Fprintln(w, legacyLookup())
+		default:
+			http.NotFound(w, r)
+		}
+		logger.Printf("%s %s %v", r.Method, r.URL.Path, time.Since(start))
+	})
+}
+
+// humanTimeout is how long the human version gives every request.
+const humanTimeout = 50 * time.Millisecond
+
+// HUMAN CODING: Hand-rolled middleware, chained by hand
+func newHumanService(logs io.Writer) http.Handler {
+	/*
+	   Route with a ServeMux, using method patterns, and wrap it in
+	   middleware: functions that take a handler and return one that
+	   does something around it. Logging records the status through a
+	   wrapped ResponseWriter, recovery turns a panic into a 500, and
+	   a timeout puts a deadline on every request's context.
+
+	   The pieces are right in outline, and wrong in the details:
+
+	   - The status recorder only sees WriteHeader. A handler that
+	     just writes its body gets an implicit 200, which is logged as
+	     status 0 - most requests, in other words.
+	   - The timeout only sets a deadline on the context. A handler
+	     that honors its context stops; one that doesn't (a library
+	     call that takes no context) runs on, and the client waits.
+	   - One timeout for every route: 50ms is plenty for a greeting,
+	     and too short for a report that takes 80ms.
+	   - The chain is nested by hand and reads inside out, so putting
+	     recovery outside logging - and losing panics from the log -
+	     is an easy mistake.
+	*/
+	logger := log.New(logs, "", log.LstdFlags)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /hello/{name}", helloHandler)
+	mux.HandleFunc("GET /report", reportHandler)
+	mux.HandleFunc("GET /latest", latestHandler)
+	mux.HandleFunc("GET /legacy", legacyHandler)
+	return humanLogging(logger, humanRecovery(logger, humanDeadline(mux)))
+}
+
+// statusRecorder remembers the status a handler sets.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func humanLogging(logger *log.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		logger.Printf("%s %s status=%d %v", r.Method, r.URL.Path, rec.status, time.Since(start))
+	})
+}
+
+func humanRecovery(logger *log.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if err := recover(); err != nil {
+				logger.Printf("panic: %v", err)
+				http.Error(w, "internal server error", http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func humanDeadline(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), humanTimeout)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// EXPERT CODING: A composable middleware stack, configured per route
+
+// middleware wraps a handler in another that does something around it.
+type middleware func(http.Handler) http.Handler
+
+// chain wraps h in mws, the first outermost, so that a chain reads in
+// the order a request meets it.
+func chain(h http.Handler, mws ...middleware) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// route is one of the expert version's routes, with its own settings.
+type route struct {
+	pattern string
+	handler http.HandlerFunc
+	timeout time.Duration // To answer, or be answered for with a 503
+}
+
+// expertRoutes are the expert version's routes: a report is allowed
+// longer than the rest.
+var expertRoutes = []route{
+	{"GET /hello/{name}", helloHandler, 50 * time.Millisecond},
+	{"GET /report", reportHandler, 250 * time.Millisecond},
+	{"GET /latest", latestHandler, 50 * time.Millisecond},
+	{"GET /legacy", legacyHandler, 50 * time.Millisecond},
+}
+
+func newExpertService(logs io.Writer) http.Handler {
+	/*
+	   Middleware have a type, and chain composes any number of them
+	   in the order a request meets them: logging outermost, so it
+	   sees every response, recovery inside it, so panics are logged
+	   as the 500s they become. Each route carries its own settings,
+	   and gets its own stack: here a timeout, which could as well be
+	   auth, a body limit or a rate limit.
+
+	   The timeout is http.TimeoutHandler, which cancels the handler's
+	   context and answers 503 at the deadline whether or not the
+	   handler notices - the client isn't kept waiting by a library
+	   that can't be stopped. It buffers the response to do that, so
+	   it isn't for streaming routes. The status writer records the
+	   implicit 200 of a handler that only writes a body, and unwraps
+	   to the real ResponseWriter, so http.ResponseController still
+	   reaches Flush and deadlines. Recovery re-panics
+	   http.ErrAbortHandler, which is how a handler asks net/http to
+	   abort a response, and logs the stack of any other panic.
+	*/
+	logger := slog.New(slog.NewTextHandler(logs, nil))
+	mux := http.NewServeMux()
+	for _, rt := range expertRoutes {
+		mux.Handle(rt.pattern, chain(rt.handler, withTimeout(rt.timeout)))
+	}
+	return chain(mux, withLogging(logger), withRecovery(logger))
+}
+
+// statusWriter records the status and size of a response.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK // Written implicitly by the first Write
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the ResponseWriter beneath.
+func (w *statusWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+func withLogging(logger *slog.Logger) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			sw := &statusWriter{ResponseWriter: w}
+			next.ServeHTTP(sw, r)
+			if sw.status == 0 {
+				sw.status = http.StatusOK // Nothing written: net/http sends a 200
+			}
+			logger.LogAttrs(r.Context(), slog.LevelInfo, "request",
+				slog.String("method", r.Method), slog.String("path", r.URL.Path),
+				slog.Int("status", sw.status), slog.Int("bytes", sw.bytes),
+				slog.Duration("elapsed", time.Since(start)))
+		})
+	}
+}
+
+func withRecovery(logger *slog.Logger) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				logger.LogAttrs(r.Context(), slog.LevelError, "panic",
+					slog.String("method", r.Method), slog.String("path", r.URL.Path),
+					slog.Any("panic", v), slog.String("stack", string(debug.Stack())))
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func withTimeout(d time.Duration) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.TimeoutHandler(next, d, "request timed out\n")
+	}
+}
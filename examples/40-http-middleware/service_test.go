@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// startServers starts a real server for every tier, logging to the
+// buffer at the same index.
+func startServers(t *testing.T) ([]*httptest.Server, []*lockedBuffer) {
+	t.Helper()
+	servers := make([]*httptest.Server, len(tiers))
+	logs := make([]*lockedBuffer, len(tiers))
+	for i, tier := range tiers {
+		logs[i] = new(lockedBuffer)
+		servers[i] = httptest.NewUnstartedServer(tier.newService(logs[i]))
+		// net/http logs the panics that reach it, with a stack trace.
+		servers[i].Config.ErrorLog = log.New(io.Discard, "", 0)
+		servers[i].Start()
+		t.Cleanup(servers[i].Close)
+	}
+	return servers, logs
+}
+
+// TestServices sends every tier the same requests over the network, and
+// checks the status each answers with and the status each logs: what
+// the vibe version gets wrong, what the human one still does, and that
+// the expert one gets all of it right.
+func TestServices(t *testing.T) {
+	servers, logs := startServers(t)
+	tests := []struct {
+		method, path string
+		status       [3]int    // For vibe, human and expert; 0 is no response
+		logged       [3]string // The status logged; "" is none
+	}{
+		{http.MethodGet, greeting, [3]int{200, 200, 200}, [3]string{"", "0", "200"}},
+		{http.MethodPost, greeting, [3]int{200, 405, 405}, [3]string{"", "405", "405"}},
+		{http.MethodGet, "/latest?id=7", [3]int{200, 200, 200}, [3]string{"", "0", "200"}},
+		{http.MethodGet, "/latest", [3]int{0, 500, 500}, [3]string{"", "500", "500"}},
+		{http.MethodGet, "/report", [3]int{200, 503, 200}, [3]string{"", "503", "200"}},
+		{http.MethodGet, "/legacy", [3]int{200, 200, 503}, [3]string{"", "0", "503"}},
+	}
+	for _, tt := range tests {
+		for i, tier := range tiers {
+			t.Run(tt.method+" "+tt.path+"/"+tier.name, func(t *testing.T) {
+				e := send(servers[i], logs[i], tt.method, tt.path)
+				if e.status != tt.status[i] {
+					t.Errorf("got %v, want status %d", e, tt.status[i])
+				}
+				logged := ""
+				if m := loggedStatus.FindStringSubmatch(e.logged); m != nil {
+					logged = m[1]
+				}
+				if logged != tt.logged[i] {
+					t.Errorf("got %v, want it logged as %q", e, tt.logged[i])
+				}
+				// However the last request went, the server must still
+				// answer.
+				if next := send(servers[i], logs[i], http.MethodGet, greeting); next.status != http.StatusOK || next.body != string(wantBody) {
+					t.Errorf("then GET %s: got %v", greeting, next)
+				}
+			})
+		}
+	}
+}
+
+// TestExpertTimeout checks the expert version answers a route whose
+// handler can't be stopped well before the handler returns.
+func TestExpertTimeout(t *testing.T) {
+	servers, logs := startServers(t)
+	expert := len(tiers) - 1
+	if e := send(servers[expert], logs[expert], http.MethodGet, "/legacy"); e.elapsed >= legacyWork/2 {
+		t.Errorf("got %v, want a 503 within %v", e, legacyWork/2)
+	}
+}
+
+// TestServe checks the in-process requests the timings make are
+// answered correctly by every tier.
+func TestServe(t *testing.T) {
+	for _, tier := range tiers {
+		if got := serve(tier.newService(io.Discard), 100); got != 100 {
+			t.Errorf("%s: %d of 100 greetings right", tier.name, got)
+		}
+	}
+}
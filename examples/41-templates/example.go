@@ -0,0 +1,309 @@
+// Package templates compares three ways to render an HTML report:
+// string concatenation, text/template parsed on every call, and
+// html/template parsed once, for both safety and speed.
+package templates
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	render           func(w io.Writer, r orders) error
+}{
+	{"Vibe coding", "O(n²) concatenation", vibeRender},
+	{"Human coding", "O(n) + a parse per call", humanRender},
+	{"Expert coding", "O(n), parsed once", expertRender},
+}
+
+// renders returns the implementations to compare, each rendering its
+// report reports times into a reused buffer, and returning the last
+// page.
+func renders(reports int) []bench.Impl[orders, string] {
+	impls := make([]bench.Impl[orders, string], len(tiers))
+	for i, t := range tiers {
+		impls[i] = bench.Impl[orders, string]{
+			Name: t.name, Complexity: t.complexity,
+			FuncContext: func(ctx context.Context, r orders) (string, error) {
+				var buf bytes.Buffer
+				for range reports {
+					if err := ctx.Err(); err != nil {
+						return "", err
+					}
+					buf.Reset()
+					if err := t.render(&buf, r); err != nil {
+						return "", err
+					}
+				}
+				return buf.String(), nil
+			},
+		}
+	}
+	return impls
+}
+
+// renderString renders r with render, and returns the page.
+func renderString(render func(io.Writer, orders) error, r orders) (string, error) {
+	var b strings.Builder
+	err := render(&b, r)
+	return b.String(), err
+}
+
+// row returns the first table row of page after the headings, without
+// its <tr> tags, or "" if there is none.
+func row(page string) string {
+	_, rest, _ := strings.Cut(page, "</th></tr>\n")
+	line, _, _ := strings.Cut(rest, "\n")
+	if !strings.HasPrefix(line, "<tr>") {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(line, "<tr>"), "</tr>")
+}
+
+// parseCost returns how long parse takes, on average over a few
+// hundred calls.
+func parseCost(ctx context.Context, parse func() error) (time.Duration, error) {
+	const calls = 200
+	start := time.Now()
+	for range calls {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		if err := parse(); err != nil {
+			return 0, err
+		}
+	}
+	return time.Since(start) / calls, nil
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "the page built with +=, fields pasted in as they are", Complexity: "O(n²)", Notes: []report.Note{
+			report.Strength("No template language, and the HTML is right there"),
+			report.Pitfall("Nothing is escaped: a user's <script> runs in every reader's browser"),
+			report.Pitfall("A quote in a field ends the attribute and starts the attacker's"),
+			report.Pitfall("+= copies the page so far for every row"),
+		}},
+		{Label: "Human coding", Approach: "text/template with | html on every field, parsed on every call", Complexity: "O(n) + a parse per call", Notes: []report.Note{
+			report.Strength("One layout, in a template, and markup in fields shown as text"),
+			report.Pitfall("Escaping the same everywhere isn't enough: javascript: links get through"),
+			report.Pitfall("Safe only as long as nobody forgets a | html"),
+			report.Pitfall("Parsing on every call costs more than rendering a small page"),
+		}},
+		{Label: "Expert coding", Approach: "html/template, parsed once at start-up", Complexity: "O(n), escaped for its context", Notes: []report.Note{
+			report.Strength("Escapes every value for where it appears: text, attribute or URL"),
+			report.Strength("Unsafe URLs are replaced, so javascript: links can't run"),
+			report.Strength("A broken template fails at start-up, and one template serves every request"),
+			report.Pitfall("Contextual escaping costs more per value than text/template"),
+		}},
+	},
+	Takeaway: "Generate HTML with html/template, never by pasting strings " +
+		"together or with text/template: only html/template knows where each " +
+		"value lands, and escapes it to match. Parse templates once, at " +
+		"start-up, and reuse them.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "41-templates",
+		Title:       "HTML Templates",
+		Description: "Render an HTML report by string concatenation, with text/template parsed on every call, and with html/template parsed once, and feed each markup and javascript: links.",
+		Category:    "strings",
+		Difficulty:  examples.Beginner,
+		Lesson:      lesson,
+		Run:         Run,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("41-templates", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{10, 100, 1_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of entries per report, e.g. 10,10000")
+	reports := fs.Int("reports", 100, "reports rendered per run")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *reports < 1 {
+		return errors.New("-reports must be at least 1")
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("HTML Templates", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: HTML Templates")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "Each run renders the same report %d times; nothing in it needs escaping, so every page must match\n", *reports)
+
+	impls := renders(*reports)
+	for _, n := range sizes {
+		r := makeOrders(*seed, n)
+		want, err := renderString(expertRender, r)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out.Table, "\nRendering a report of %d entries, %s (seed %d):\n", n, bench.FormatBytes(uint64(len(want))), *seed)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		results, err := bench.CompareImpls(ctx, opts, r, want, bench.Equal[string], impls...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "✔ All implementations rendered the same page")
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d", n), results)
+
+		fmt.Fprintln(out.Table, "\nPer report:")
+		for _, res := range results {
+			perReport := float64(res.Duration.Nanoseconds()) / float64(*reports) / 1e3
+			allocs := float64(res.Allocs) / float64(*reports)
+			fmt.Fprintf(out.Table, "  %-14s %9.1f µs  %8.1f allocs  %10s\n", res.Name+":",
+				perReport, allocs, bench.FormatBytes(res.Bytes/uint64(*reports)))
+			section.Notes = append(section.Notes, fmt.Sprintf("%s: %.1f µs and %.1f allocations per report",
+				res.Name, perReport, allocs))
+		}
+	}
+
+	// The human version parses its template on every call, and the
+	// expert version once; html/template also escapes the template the
+	// first time it is executed.
+	fmt.Fprintln(out.Table, "\nParsing the template, per call (the expert version does it once):")
+	parses := []struct {
+		desc  string
+		parse func() error
+	}{
+		{"text/template: Parse", func() error {
+			_, err := template.New("report").Parse(humanText)
+			return err
+		}},
+		{"html/template: Parse and first Execute", func() error {
+			t, err := htmltemplate.New("report").Parse(expertText)
+			if err != nil {
+				return err
+			}
+			return t.Execute(io.Discard, orders{})
+		}},
+	}
+	for _, p := range parses {
+		cost, err := parseCost(ctx, p.parse)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out.Table, "  %-40s %7.1f µs\n", p.desc+":", float64(cost.Nanoseconds())/1e3)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing: one entry each, typed by a user")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	ok := entry{Name: "alice", Website: "https://example.com/u/alice", Comment: "Thanks", Cents: 1999}
+	with := func(change func(e *entry)) []entry {
+		e := ok
+		change(&e)
+		return []entry{e}
+	}
+	edgeCases := []struct {
+		desc, want string
+		entries    []entry
+		good       func(page string) bool
+	}{
+		{"a name of <script>alert(1)</script>", "shown as text, not run",
+			with(func(e *entry) { e.Name = "<script>alert(1)</script>" }),
+			func(page string) bool { return !strings.Contains(page, "<script>") }},
+		{`a comment of "Tom & Jerry" <3`, "escaped to &amp; and &lt;",
+			with(func(e *entry) { e.Comment = `"Tom & Jerry" <3` }),
+			func(page string) bool {
+				return strings.Contains(page, "Tom &amp; Jerry") && strings.Contains(page, "&lt;3")
+			}},
+		{`a website ending in " onmouseover="alert(1)`, "kept inside the href",
+			with(func(e *entry) { e.Website = `https://example.com/" onmouseover="alert(1)` }),
+			func(page string) bool { return !strings.Contains(page, `" onmouseover="`) }},
+		{"a website of javascript:alert(1)", "not a link that runs it",
+			with(func(e *entry) { e.Website = "javascript:alert(1)" }),
+			func(page string) bool { return !strings.Contains(page, `href="javascript:`) }},
+		{"no entries", "just the headings", nil,
+			func(page string) bool { return strings.Contains(page, "</th></tr>\n</table>") }},
+	}
+	for _, tc := range edgeCases {
+		fmt.Fprintf(w, "%s (want: %s):\n", tc.desc, tc.want)
+		r := orders{Title: "Orders", Entries: tc.entries}
+		for _, t := range tiers {
+			page, err := renderString(t.render, r)
+			result := fmt.Sprintf("%q", row(page))
+			if row(page) == "" {
+				result = "no rows"
+			}
+			if err != nil {
+				result = "error: " + err.Error()
+			}
+			status := "✅"
+			if err != nil || !tc.good(page) {
+				status = "❌"
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
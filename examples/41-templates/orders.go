@@ -0,0 +1,48 @@
+package templates
+
+import (
+	"fmt"
+
+	"github.com/iportilla/ai-coding/input"
+)
+
+// orders is the data rendered as an HTML page: a title, and a table of
+// entries.
+type orders struct {
+	Title   string
+	Entries []entry
+}
+
+// entry is one row of a report. Every field but Cents comes from users,
+// so any of them may hold markup.
+type entry struct {
+	Name    string
+	Website string
+	Comment string
+	Cents   int
+}
+
+// Amount formats e's amount in dollars, e.g. 12.34.
+func (e entry) Amount() string { return fmt.Sprintf("%d.%02d", e.Cents/100, e.Cents%100) }
+
+var (
+	names    = []string{"alice", "bob", "carol", "dave", "erin", "frank", "grace", "heidi", "ivan", "judy"}
+	comments = []string{"Great service", "Will order again", "Arrived late", "As described", "Thanks"}
+)
+
+// makeOrders returns orders of n entries. Nothing in it needs
+// escaping, so every implementation renders the same page.
+func makeOrders(seed input.Seed, n int) orders {
+	rng := seed.Rand("report", n)
+	r := orders{Title: fmt.Sprintf("Orders: %d entries", n), Entries: make([]entry, n)}
+	for i := range r.Entries {
+		name := names[rng.IntN(len(names))]
+		r.Entries[i] = entry{
+			Name:    name,
+			Website: "https://example.com/u/" + name,
+			Comment: comments[rng.IntN(len(comments))],
+			Cents:   rng.IntN(100_000),
+		}
+	}
+	return r
+}
@@ -0,0 +1,112 @@
+package templates
+
+import (
+	htmltemplate "html/template"
+	"io"
+	"text/template"
+)
+
+// VIBE CODING: String concatenation
+func vibeRender(w io.Writer, r orders) error {
+	/*
+	   Build the page as a string, a piece at a time, and write it out.
+	   No template language to learn, and the HTML is right there.
+
+	   But nothing is escaped. Whatever a user typed into their name
+	   or comment goes into the page as markup: a <script> tag runs in
+	   the browser of everyone who views the report, a quote in a
+	   website ends the href and starts an attribute of the attacker's
+	   choosing, and even an innocent "Tom & Jerry" is invalid HTML.
+	   That is cross-site scripting, one of the commonest security
+	   bugs on the web.
+
+	   And += copies the whole page so far for every row, so the cost
+	   grows with the square of the rows.
+	*/
+	html := "<!DOCTYPE html>\n<html>\n<head><title>" + r.Title + "</title></head>\n<body>\n<h1>" + r.Title + "</h1>\n" +
+		"<table>\n<tr><th>Name</th><th>Comment</th><th>Amount</th></tr>\n"
+	for _, e := range r.Entries {
+		html += "<tr><td><a href=\"" + e.Website + "\">" + e.Name + "</a></td><td>" + e.Comment + "</td><td>" + e.Amount() + "</td></tr>\n"
+	}
+	html += "</table>\n</body>\n</html>\n"
+	_, err := io.WriteString(w, html)
+	return err
+}
+
+// humanText is the human version's template, every field piped through
+// text/template's html function.
+const humanText = `<!DOCTYPE html>
+<html>
+<head><title>{{.Title | html}}</title></head>
+<body>
+<h1>{{.Title | html}}</h1>
+<table>
+<tr><th>Name</th><th>Comment</th><th>Amount</th></tr>
+{{range .Entries}}<tr><td><a href="{{.Website | html}}">{{.Name | html}}</a></td><td>{{.Comment | html}}</td><td>{{.Amount}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`
+
+// HUMAN CODING: text/template, escaped by hand, parsed on every call
+func humanRender(w io.Writer, r orders) error {
+	/*
+	   Use a template, so the page's layout is in one place, and pipe
+	   every field through html, which escapes <, >, &, ' and ". A
+	   <script> tag is shown as text, and a quote can't end an
+	   attribute.
+
+	   But text/template knows nothing about HTML: the escaping is only
+	   as good as the pipes someone remembers to add, and html escapes
+	   the same way everywhere. In an href, that isn't enough - a
+	   website of javascript:alert(1) has nothing to escape, and runs
+	   when the link is clicked.
+
+	   The template is also parsed on every call. Parsing costs more
+	   than executing a small page, and it is the same work every time.
+	*/
+	t, err := template.New("report").Parse(humanText)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, r)
+}
+
+// expertText is the expert version's template. html/template works out
+// how to escape each field from where it appears: as text, in an
+// attribute, or in a URL.
+const expertText = `<!DOCTYPE html>
+<html>
+<head><title>{{.Title}}</title></head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+<tr><th>Name</th><th>Comment</th><th>Amount</th></tr>
+{{range .Entries}}<tr><td><a href="{{.Website}}">{{.Name}}</a></td><td>{{.Comment}}</td><td>{{.Amount}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`
+
+// expertTemplate is expertText, parsed once when the program starts.
+var expertTemplate = htmltemplate.Must(htmltemplate.New("report").Parse(expertText))
+
+// EXPERT CODING: html/template, parsed once
+func expertRender(w io.Writer, r orders) error {
+	/*
+	   html/template has the same syntax as text/template, but it
+	   parses the HTML around every action and escapes each value for
+	   its context: HTML-escaped as text and in attributes, and, in a
+	   URL attribute, checked for a safe scheme first. javascript:
+	   URLs become #ZgotmplZ, a harmless placeholder. Nothing has to
+	   be remembered, so nothing can be forgotten.
+
+	   The template is parsed once, when the package is initialized,
+	   and template.Must makes a broken template fail at start-up
+	   rather than on some later request. A parsed template is safe
+	   for concurrent use, so one serves every request. Only trusted
+	   markup should bypass the escaping, as an explicit
+	   template.HTML.
+	*/
+	return expertTemplate.Execute(w, r)
+}
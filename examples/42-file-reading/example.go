@@ -0,0 +1,260 @@
+// Package fileread compares three ways to sum the numbers in a large
+// file, one per line: reading it all and splitting it, bufio.Scanner,
+// and a large bufio.Reader parsed by hand, for throughput and peak
+// memory.
+package fileread
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/metrics"
+	"strings"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	sum              func(path string) (int64, error)
+}{
+	{"Vibe coding", "O(file) memory", vibeSum},
+	{"Human coding", "O(1) memory, 2 passes", humanSum},
+	{"Expert coding", "O(1) memory, 1 pass", expertSum},
+}
+
+// sums returns the implementations to compare, each summing the file at
+// its input path.
+func sums() []bench.Impl[string, int64] {
+	impls := make([]bench.Impl[string, int64], len(tiers))
+	for i, t := range tiers {
+		impls[i] = bench.Impl[string, int64]{
+			Name: t.name, Complexity: t.complexity,
+			FuncContext: func(ctx context.Context, path string) (int64, error) {
+				if err := ctx.Err(); err != nil {
+					return 0, err
+				}
+				return t.sum(path)
+			},
+		}
+	}
+	return impls
+}
+
+// peakHeap runs f while sampling the bytes of live heap objects, and
+// returns the largest value seen above what was live before f started.
+// Sampling can miss a short spike, so it is a lower bound.
+func peakHeap(f func()) uint64 {
+	heap := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	runtime.GC() // Free what earlier runs left behind
+	metrics.Read(heap)
+	baseline := heap[0].Value.Uint64()
+	var peak uint64
+	done := make(chan struct{})
+	sampled := make(chan struct{})
+	go func() {
+		defer close(sampled)
+		for {
+			metrics.Read(heap)
+			peak = max(peak, heap[0].Value.Uint64())
+			select {
+			case <-done:
+				return
+			case <-time.After(50 * time.Microsecond):
+			}
+		}
+	}()
+	f()
+	close(done)
+	<-sampled
+	return peak - min(peak, baseline)
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "os.ReadFile, strings.Split and strconv.Atoi", Complexity: "O(file) memory", Notes: []report.Note{
+			report.Strength("Four lines, and no line is too long for it"),
+			report.Pitfall("Holds the file three times over: bytes, a string copy, and a header per line"),
+			report.Pitfall("Windows line endings leave a \\r that Atoi rejects"),
+			report.Pitfall("Errors don't say which line, and the sum can overflow silently"),
+		}},
+		{Label: "Human coding", Approach: "bufio.Scanner, strconv.Atoi(scanner.Text())", Complexity: "O(1) memory, every byte read twice", Notes: []report.Note{
+			report.Strength("Streams the file in small, constant memory"),
+			report.Strength("Handles \\r\\n, and reports read errors through Err"),
+			report.Pitfall("Scan finds each newline, then Atoi reads the line again"),
+			report.Pitfall("Stops at a line over 64 KiB, and fails on a blank line"),
+		}},
+		{Label: "Expert coding", Approach: "a 256 KiB bufio.Reader, Peek and Discard, and a hand-written parser", Complexity: "O(1) memory, every byte read once", Notes: []report.Note{
+			report.Strength("Parses the reader's buffer where it lies: no copies, no strings, one pass"),
+			report.Strength("Any line length, blank lines and \\r\\n; errors name their line"),
+			report.Strength("Checks every number and the sum for int64 overflow"),
+			report.Pitfall("A parser to write and test, where strconv has been tested for you"),
+		}},
+	},
+	Takeaway: "Stream files rather than reading them whole: memory should " +
+		"not grow with the input. bufio.Scanner is the easy way, but it " +
+		"limits line length and reads every byte twice with strconv; when " +
+		"throughput matters, parse the reader's buffer in place.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "42-file-reading",
+		Title:       "Reading Large Files",
+		Description: "Sum the numbers in a large file by reading it whole and splitting it, with bufio.Scanner, and with a large bufio.Reader parsed by hand, and compare throughput and peak memory.",
+		Category:    "performance",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("42-file-reading", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{100_000, 10_000_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of lines in the file, e.g. 1e3,1e8")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Reading Large Files", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Reading Large Files")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "Files of random integers in [-1e9, 1e9], one per line, in %s\n", os.TempDir())
+	fmt.Fprintln(w, "After the warm-up the file is in the OS page cache, so this times reading memory and parsing, not the disk")
+
+	impls := sums()
+	for _, n := range sizes {
+		path, size, want, err := writeNumbers(*seed, int(n))
+		if err != nil {
+			return err
+		}
+		defer os.Remove(path)
+		fmt.Fprintf(out.Table, "\nSumming %d numbers, a %s file (seed %d):\n", n, bench.FormatBytes(uint64(size)), *seed)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		results, err := bench.CompareImpls(ctx, opts, path, want, bench.Equal[int64], impls...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "✔ All implementations summed to %d\n", want)
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d", n), results)
+
+		fmt.Fprintln(out.Table, "\nThroughput and memory:")
+		for i, r := range results {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			peak := peakHeap(func() { tiers[i].sum(path) })
+			mbps := float64(size) / 1e6 / r.Duration.Seconds()
+			fmt.Fprintf(out.Table, "  %-14s %8.1f MB/s   peak heap %10s\n", r.Name+":", mbps, bench.FormatBytes(peak))
+			section.Notes = append(section.Notes, fmt.Sprintf("%s: %.1f MB/s, peak heap %s", r.Name, mbps, bench.FormatBytes(peak)))
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing: small files")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		desc, contents string
+		want           int64
+		errWant        string // If set, the error must contain it instead
+	}{
+		{`"1\n2\n3\n"`, "1\n2\n3\n", 6, ""},
+		{`"1\n2\n3", no newline at the end`, "1\n2\n3", 6, ""},
+		{`"1\r\n2\r\n3\r\n", Windows line endings`, "1\r\n2\r\n3\r\n", 6, ""},
+		{`"1\n\n2\n\n", blank lines`, "1\n\n2\n\n", 3, ""},
+		{`"1\n2x\n3\n"`, "1\n2x\n3\n", 0, "line 2"},
+		{"a line of 100,000 zeros, then 42, then 1", strings.Repeat("0", 100_000) + "42\n1\n", 43, ""},
+		{"MaxInt64, then 1", "9223372036854775807\n1\n", 0, "overflow"},
+	}
+	for _, tc := range edgeCases {
+		want := fmt.Sprint(tc.want)
+		if tc.errWant != "" {
+			want = fmt.Sprintf("an error mentioning %q", tc.errWant)
+		}
+		fmt.Fprintf(w, "%s (want: %s):\n", tc.desc, want)
+		path, err := writeFile(tc.contents)
+		if err != nil {
+			return err
+		}
+		for _, t := range tiers {
+			got, err := t.sum(path)
+			result := fmt.Sprint(got)
+			if err != nil {
+				result = "error: " + err.Error()
+			}
+			good := err == nil && tc.errWant == "" && got == tc.want
+			if tc.errWant != "" {
+				good = err != nil && strings.Contains(err.Error(), tc.errWant)
+			}
+			status := "✅"
+			if !good {
+				status = "❌"
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+result)
+		}
+		os.Remove(path)
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
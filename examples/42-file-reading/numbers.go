@@ -0,0 +1,56 @@
+package fileread
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+
+	"github.com/iportilla/ai-coding/input"
+)
+
+// writeNumbers writes n random integers in [-1e9, 1e9], one per line, to
+// a new file in the temporary directory, and returns its path, its size
+// and the numbers' sum. The caller removes the file.
+func writeNumbers(seed input.Seed, n int) (path string, size, sum int64, err error) {
+	f, err := os.CreateTemp("", "numbers-*.txt")
+	if err != nil {
+		return "", 0, 0, err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(f.Name())
+		}
+	}()
+	rng := seed.Rand("numbers", n)
+	w := bufio.NewWriterSize(f, 64<<10)
+	var line []byte
+	for range n {
+		v := rng.Int64N(2_000_000_001) - 1_000_000_000
+		sum += v
+		line = append(strconv.AppendInt(line[:0], v, 10), '\n')
+		w.Write(line)
+		size += int64(len(line))
+	}
+	return f.Name(), size, sum, w.Flush()
+}
+
+// writeFile writes contents to a new file in the temporary directory,
+// and returns its path. The caller removes the file.
+func writeFile(contents string) (string, error) {
+	f, err := os.CreateTemp("", "numbers-*.txt")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.WriteString(contents)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,212 @@
+package fileread
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// VIBE CODING: Read it all, split it, convert each line
+func vibeSum(path string) (int64, error) {
+	/*
+	   Read the whole file into memory, turn it into a string, split it
+	   into lines and convert each one. Four lines of code, and quick
+	   to write - the way ioutil.ReadAll was used for years.
+
+	   But memory grows with the file, three times over: the bytes, a
+	   string copy of them, and a slice with a string header for every
+	   line. A 100 MB file of short lines takes 350 MB of allocations,
+	   and a file bigger than memory can't be read at all.
+	   Splitting on "\n" also leaves the "\r" of Windows line endings
+	   on every line, which strconv.Atoi rejects. And nothing checks
+	   the sum for overflow.
+	*/
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	var sum int64
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "" {
+			continue
+		}
+		v, err := strconv.Atoi(line)
+		if err != nil {
+			return 0, err
+		}
+		sum += int64(v)
+	}
+	return sum, nil
+}
+
+// HUMAN CODING: bufio.Scanner, one line at a time
+func humanSum(path string) (int64, error) {
+	/*
+	   Stream the file a line at a time with bufio.Scanner, which reads
+	   it in 4 KiB blocks and strips "\r\n" as well as "\n". Memory
+	   stays small and the same whatever the file's size, and
+	   scanner.Err reports anything that went wrong reading.
+
+	   Text returns a new string for every line, but this one is short
+	   and only read by Atoi, so the compiler keeps it on the stack:
+	   no allocations. Every byte is still looked at twice, once by
+	   Scan for the newline and once by Atoi. A line longer than the
+	   Scanner's 64 KiB limit stops the scan with bufio.ErrTooLong. A
+	   blank line, which Scanner passes on as "", is a syntax error to
+	   Atoi, which doesn't say which line it was on. And the sum can
+	   still overflow without a word.
+	*/
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	var sum int64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		v, err := strconv.Atoi(scanner.Text())
+		if err != nil {
+			return 0, err
+		}
+		sum += int64(v)
+	}
+	return sum, scanner.Err()
+}
+
+// readSize is the expert version's read buffer: big enough that reads
+// are few, small enough to stay in cache.
+const readSize = 256 << 10
+
+// EXPERT CODING: A large bufio.Reader, parsed by hand
+func expertSum(path string) (int64, error) {
+	/*
+	   Read through a 256 KiB bufio.Reader and parse the bytes where
+	   they lie, with no strings and no strconv: a digit at a time,
+	   into the number being read, added to the sum at each newline.
+
+	   Peek returns the reader's own buffer, filled, without looking
+	   for newlines, and Discard moves past it - so nothing is copied,
+	   and nothing is allocated, however long the file or its lines.
+	   The parser keeps its state from one buffer to the next, so a
+	   number split across two reads is still one number. Blank lines
+	   and "\r\n" are accepted, and anything else is an error naming
+	   its line, as is a number or sum that doesn't fit in an int64.
+	*/
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	r := bufio.NewReaderSize(f, readSize)
+	var p parser
+	for {
+		buf, err := r.Peek(readSize)
+		if perr := p.feed(buf); perr != nil {
+			return 0, perr
+		}
+		r.Discard(len(buf))
+		switch err {
+		case nil:
+		case io.EOF:
+			return p.finish()
+		default:
+			return 0, err
+		}
+	}
+}
+
+var (
+	errSyntax   = errors.New("invalid number")
+	errRange    = errors.New("number out of range")
+	errOverflow = errors.New("sum overflows int64")
+)
+
+// parser sums the numbers in a stream of bytes, one per line, fed to it
+// in pieces of any size.
+type parser struct {
+	sum    int64
+	line   int    // Lines finished so far
+	n      uint64 // The magnitude of the number being read
+	digits bool   // Whether it has any digits yet
+	sign   bool   // Whether it started with a sign
+	neg    bool   // Whether that sign was -
+	cr     bool   // Whether the line has reached its "\r"
+}
+
+// feed parses b, the next piece of the stream.
+func (p *parser) feed(b []byte) error {
+	// Digits are nearly every byte, so they are parsed in a tight loop,
+	// with the number in a local variable; the rest go through the
+	// parser's fields.
+	const cutoff = (1 << 63) / 10
+	n, digits := p.n, p.digits
+	for _, c := range b {
+		if d := c - '0'; d <= 9 && !p.cr {
+			if n >= cutoff && (n > cutoff || d > (1<<63)%10) {
+				return p.fail(errRange)
+			}
+			n = n*10 + uint64(d)
+			digits = true
+			continue
+		}
+		p.n, p.digits = n, digits
+		switch {
+		case c == '\n':
+			if err := p.endLine(); err != nil {
+				return err
+			}
+		case p.cr:
+			return p.fail(errSyntax) // Something after the "\r"
+		case (c == '-' || c == '+') && !p.digits && !p.sign:
+			p.sign, p.neg = true, c == '-'
+		case c == '\r':
+			p.cr = true
+		default:
+			return p.fail(errSyntax)
+		}
+		n, digits = p.n, p.digits
+	}
+	p.n, p.digits = n, digits
+	return nil
+}
+
+// endLine adds the number on the line just finished, if any, to the sum.
+func (p *parser) endLine() error {
+	if p.digits {
+		if !p.neg && p.n > math.MaxInt64 {
+			return p.fail(errRange)
+		}
+		v := int64(p.n) // -1<<63 if neg and n is 1<<63, as it should be
+		if p.neg {
+			v = -v
+		}
+		if (v > 0 && p.sum > math.MaxInt64-v) || (v < 0 && p.sum < math.MinInt64-v) {
+			return p.fail(errOverflow)
+		}
+		p.sum += v
+	} else if p.sign {
+		return p.fail(errSyntax) // A sign and nothing else
+	}
+	p.line++
+	p.n, p.digits, p.sign, p.neg, p.cr = 0, false, false, false, false
+	return nil
+}
+
+// finish ends the stream, whose last line may lack a newline, and
+// returns the sum.
+func (p *parser) finish() (int64, error) {
+	if err := p.endLine(); err != nil {
+		return 0, err
+	}
+	return p.sum, nil
+}
+
+// fail returns err for the line being parsed.
+func (p *parser) fail(err error) error {
+	return fmt.Errorf("line %d: %w", p.line+1, err)
+}
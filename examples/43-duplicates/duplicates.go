@@ -0,0 +1,82 @@
+package duplicates
+
+import "slices"
+
+// VIBE CODING: Compare every pair
+func vibeDuplicates(values []int) []int {
+	/*
+	   Compare every value with every value after it, and report a match
+	   unless it has been reported already. No extra memory, nothing to
+	   sort, and plainly right.
+
+	   But n values make n(n-1)/2 pairs: ten times the input is a
+	   hundred times the work. And "unless it has been reported
+	   already" is a linear search of the duplicates found so far, run
+	   on every match - a value that appears k times is looked up
+	   k(k-1)/2 times. Fine for a hundred values, a second for a few
+	   tens of thousands, and minutes for a million.
+	*/
+	var dups []int
+	for i := range values {
+		for j := i + 1; j < len(values); j++ {
+			if values[i] == values[j] && !slices.Contains(dups, values[i]) {
+				dups = append(dups, values[i])
+			}
+		}
+	}
+	return dups
+}
+
+// HUMAN CODING: Sort a copy, compare neighbours
+func humanDuplicates(values []int) []int {
+	/*
+	   Sort the values, and equal ones end up next to each other: one
+	   pass comparing each value with the one before finds them all,
+	   and reports each once, at the first repeat of a run.
+
+	   The sort is O(n log n), and it sorts a copy, since sorting the
+	   caller's slice in place would be a surprise to them - so it
+	   costs n more ints of memory. The duplicates come out in order,
+	   which is sometimes what's wanted, but the order of the input is
+	   lost.
+	*/
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	var dups []int
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i] == sorted[i-1] && (i == 1 || sorted[i] != sorted[i-2]) {
+			dups = append(dups, sorted[i])
+		}
+	}
+	return dups
+}
+
+// EXPERT CODING: A set of the values seen, sized up front
+func expertDuplicates(values []int) []int {
+	/*
+	   One pass, remembering every value seen in a map: a value already
+	   in it is a duplicate. The map's bool says whether it has been
+	   reported, so a value that appears many times is reported once, at
+	   its second appearance, and the duplicates come out in the order
+	   the input repeats them.
+
+	   Each lookup is O(1) on average, so the whole is O(n). The map is
+	   sized for every value up front, so it never grows and rehashes
+	   on the way. Hashing costs more per value than a comparison,
+	   though, and the map is bigger than the values - for small inputs
+	   the sort, or even the pairs, can be faster.
+	*/
+	reported := make(map[int]bool, len(values))
+	var dups []int
+	for _, v := range values {
+		done, seen := reported[v]
+		switch {
+		case !seen:
+			reported[v] = false
+		case !done:
+			reported[v] = true
+			dups = append(dups, v)
+		}
+	}
+	return dups
+}
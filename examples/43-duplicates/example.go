@@ -0,0 +1,362 @@
+// Package duplicates compares three ways to find the values that appear
+// more than once in a slice: comparing every pair, sorting, and a set,
+// and sweeps n to find where each overtakes the others.
+package duplicates
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// Comparing every pair takes seconds per run above this many values.
+const maxVibeN = 20_000
+
+// The scaling sweep times every size several times, so it keeps the
+// vibe version to a smaller range.
+const maxSweepVibeN = 10_000
+
+// tiers lists the implementations in order. Their complexities are
+// named as bench.Curves names them, so the sweep can check them.
+var tiers = []struct {
+	name, complexity string
+	find             func(values []int) []int
+}{
+	{"Vibe coding", "O(n²)", vibeDuplicates},
+	{"Human coding", "O(n log n)", humanDuplicates},
+	{"Expert coding", "O(n)", expertDuplicates},
+}
+
+// makeValues returns n random values in [0, n): about a quarter of the
+// distinct values appear more than once.
+func makeValues(seed input.Seed, n int) []int {
+	return seed.Ints("values", n, max(n, 1))
+}
+
+// finders returns the implementations to compare on n values, leaving
+// out the vibe version above limit.
+func finders(n, limit int) []bench.Impl[[]int, []int] {
+	var impls []bench.Impl[[]int, []int]
+	for _, t := range tiers {
+		if t.name == "Vibe coding" && n > limit {
+			continue
+		}
+		impls = append(impls, bench.Impl[[]int, []int]{Name: t.name, Complexity: t.complexity, Func: t.find})
+	}
+	return impls
+}
+
+// impls returns the implementations timed on n random values.
+func impls(n int) []bench.Implementation {
+	values := makeValues(input.DefaultSeed, n)
+	var list []bench.Implementation
+	for _, f := range finders(n, maxVibeN) {
+		list = append(list, f.Implementation(values))
+	}
+	return list
+}
+
+// sameValues reports whether got and want hold the same values, in any
+// order: each tier reports the duplicates in an order of its own.
+func sameValues(got, want []int) error {
+	got, want = slices.Clone(got), slices.Clone(want)
+	slices.Sort(got)
+	slices.Sort(want)
+	return bench.DiffSlices(got, want)
+}
+
+// crossover estimates the n at which fast, the series that grows more
+// slowly, overtakes slow: after the last size they share where fast is
+// still slower, and before the next. It interpolates the ratio of their
+// timings on log scales between those sizes. ok is false if fast is
+// slower at the largest shared size; at is 0 if it is faster at every
+// size.
+func crossover(slow, fast bench.Series) (at, from, to int, ok bool) {
+	timings := map[int]float64{}
+	for i, n := range slow.Sizes {
+		if !slow.Results[i].DNF {
+			timings[n] = slow.Results[i].Duration.Seconds()
+		}
+	}
+	type point struct {
+		n     int
+		ratio float64 // log(fast's time / slow's time): > 0 while fast is slower
+	}
+	var shared []point
+	for i, n := range fast.Sizes {
+		if t, found := timings[n]; found && !fast.Results[i].DNF {
+			shared = append(shared, point{n, math.Log(fast.Results[i].Duration.Seconds() / t)})
+		}
+	}
+	if len(shared) == 0 || shared[len(shared)-1].ratio > 0 {
+		return 0, 0, 0, false
+	}
+	last := -1
+	for i, p := range shared {
+		if p.ratio > 0 {
+			last = i
+		}
+	}
+	if last < 0 {
+		return 0, 0, shared[0].n, true
+	}
+	a, b := shared[last], shared[last+1]
+	frac := a.ratio / (a.ratio - b.ratio)
+	logN := math.Log(float64(a.n)) + frac*(math.Log(float64(b.n))-math.Log(float64(a.n)))
+	return int(math.Round(math.Exp(logN))), a.n, b.n, true
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "compare every pair, and search the duplicates found so far", Complexity: "O(n²)", Notes: []report.Note{
+			report.Strength("No allocation but the answer, and plainly right"),
+			report.Strength("Keeps up with the others for a dozen values or so"),
+			report.Pitfall("Ten times the values is a hundred times the work"),
+		}},
+		{Label: "Human coding", Approach: "sort a copy, compare neighbours", Complexity: "O(n log n)", Notes: []report.Note{
+			report.Strength("Scales to millions, and reports the duplicates in order"),
+			report.Strength("Leaves the caller's slice alone by sorting a copy"),
+			report.Pitfall("n ints of extra memory, and the input's order is lost"),
+		}},
+		{Label: "Expert coding", Approach: "a map of values seen, sized up front", Complexity: "O(n) expected", Notes: []report.Note{
+			report.Strength("One pass; reports each duplicate once, in the order the input repeats it"),
+			report.Strength("Sized for n up front, so it never rehashes"),
+			report.Pitfall("A hash per value costs more than a comparison: below about a thousand values, sorting wins"),
+			report.Pitfall("The map takes several times the memory of the values, and at millions its cache misses bring it back to the sort's speed"),
+		}},
+	},
+	Takeaway: "Big-O says which approach wins for large n, not where it starts " +
+		"winning: constants decide the small cases. Measure the crossover, and " +
+		"pick the approach for the sizes you actually have.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "43-duplicates",
+		Title:       "Finding Duplicates",
+		Description: "Find the duplicated values in a slice by comparing every pair, by sorting and with a set, and sweep n to find where each approach overtakes the others.",
+		Category:    "algorithms",
+		Difficulty:  examples.Beginner,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    10_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("43-duplicates", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{100, 10_000, 1_000_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of values, e.g. 1e3,1e7")
+	sweepMax := fs.String("sweep", "1e6", "largest n in the scaling sweep, e.g. 1e7 (0 skips it)")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	sweepLimit, err := bench.ParseSize(*sweepMax)
+	if err != nil {
+		return fmt.Errorf("-sweep: %w", err)
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Finding Duplicates", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Finding Duplicates")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	for _, n := range sizes {
+		values := makeValues(*seed, n)
+		fmt.Fprintf(out.Table, "\n%d random values in [0, %d) (seed %d):\n", n, max(n, 1), *seed)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		want := humanDuplicates(values)
+		results, err := bench.CompareImpls(ctx, opts, values, want, sameValues, finders(n, maxVibeN)...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "✔ All implementations found the same %d duplicated values\n", len(want))
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d", n), results)
+
+		if n > maxVibeN {
+			note := fmt.Sprintf("Vibe coding skipped: comparing every pair is impractical above n=%d", maxVibeN)
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// The scaling sweep: every tier at sizes a quarter of a decade apart,
+	// fitted to the complexity curves, and the sizes at which each
+	// overtakes the one before it.
+	if sweepLimit >= 10 {
+		fmt.Fprintln(out.Table)
+		fmt.Fprintln(w, strings.Repeat("=", 60))
+		fmt.Fprintln(out.Table, "Scaling sweep: measured complexity and crossovers")
+		fmt.Fprintln(w, strings.Repeat("=", 60))
+
+		sweepSizes := bench.GeometricSizes(10, sweepLimit, 4)
+		fmt.Fprintf(w, "Timing each implementation at %d sizes from n=%d to n=%d (vibe coding up to n=%d)...\n",
+			len(sweepSizes), sweepSizes[0], sweepSizes[len(sweepSizes)-1], maxSweepVibeN)
+		series, err := bench.SweepContext(ctx, opts, sweepSizes, func(n int) []bench.Implementation {
+			values := makeValues(*seed, n)
+			var list []bench.Implementation
+			for _, f := range finders(n, maxSweepVibeN) {
+				list = append(list, f.Implementation(values))
+			}
+			return list
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out.Table, "\n  %10s", "n")
+		for _, s := range series {
+			fmt.Fprintf(out.Table, " %16s", s.Name)
+		}
+		fmt.Fprintln(out.Table)
+		for _, n := range sweepSizes {
+			fmt.Fprintf(out.Table, "  %10d", n)
+			for _, s := range series {
+				cell := "-"
+				if i := slices.Index(s.Sizes, n); i >= 0 {
+					cell = fmt.Sprintf("%.4fms", s.Results[i].Milliseconds())
+				}
+				fmt.Fprintf(out.Table, " %16s", cell)
+			}
+			fmt.Fprintln(out.Table)
+		}
+
+		fmt.Fprintln(out.Table, "\nMeasured complexity:")
+		for _, s := range series {
+			fits := s.Fit()
+			best := fits[0]
+			verdict := "❌ claim not supported"
+			switch {
+			case best.Curve.Name == s.Complexity:
+				verdict = "✅ matches claim"
+			case len(fits) > 1 && fits[1].Curve.Name == s.Complexity:
+				verdict = "≈ claim is the runner-up"
+			}
+			line := fmt.Sprintf("%s (claimed %s): best fit %s, error %.3f", s.Name, s.Complexity, best.Curve.Name, best.Error)
+			fmt.Fprintf(out.Table, "  %-14s best fit %-11s (error %.3f)  claimed %-11s %s\n",
+				s.Name+":", best.Curve.Name, best.Error, s.Complexity, verdict)
+			rep.AddEdgeCase("Sweep fit, "+s.Name, verdict+" "+line)
+		}
+
+		fmt.Fprintln(out.Table, "\nCrossovers:")
+		for i, slow := range series {
+			for _, fast := range series[i+1:] {
+				at, from, to, ok := crossover(slow, fast)
+				var line string
+				switch {
+				case !ok:
+					line = fmt.Sprintf("%s is still slower than %s at n=%d", fast.Name, slow.Name, fast.Sizes[len(fast.Sizes)-1])
+				case at == 0:
+					line = fmt.Sprintf("%s is faster than %s from the smallest size, n=%d", fast.Name, slow.Name, to)
+				default:
+					line = fmt.Sprintf("%s overtakes %s at n ≈ %d (between %d and %d)", fast.Name, slow.Name, at, from, to)
+				}
+				fmt.Fprintln(out.Table, "  "+line)
+				rep.AddEdgeCase(fmt.Sprintf("Crossover, %s and %s", slow.Name, fast.Name), line)
+			}
+		}
+
+		fmt.Fprintln(w, "\n  💡 A crossover is where the line of a slower-growing implementation")
+		fmt.Fprintln(w, "     crosses below a faster-growing one. Below it, constants win: a")
+		fmt.Fprintln(w, "     few hundred ints sort in cache faster than they hash. Timings")
+		fmt.Fprintln(w, "     at small n are a few hundred nanoseconds, and noisy.")
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		desc   string
+		values []int
+		want   []int
+	}{
+		{"no values", nil, nil},
+		{"one value", []int{42}, nil},
+		{"no duplicates", []int{3, 1, 2}, nil},
+		{"all equal", []int{7, 7, 7, 7}, []int{7}},
+		{"three 5s and two 1s", []int{5, 1, 5, 2, 1, 5}, []int{1, 5}},
+		{"negatives and zero", []int{-1, 0, -1, 0, 1}, []int{-1, 0}},
+		{"the smallest and largest ints", []int{math.MinInt, math.MaxInt, math.MinInt, math.MaxInt}, []int{math.MinInt, math.MaxInt}},
+		{"the first and last equal", []int{9, 1, 2, 3, 4, 9}, []int{9}},
+	}
+	for _, tc := range edgeCases {
+		fmt.Fprintf(w, "%s (want: %v):\n", tc.desc, tc.want)
+		for _, t := range tiers {
+			values := slices.Clone(tc.values)
+			got := t.find(values)
+			status := "✅"
+			result := fmt.Sprint(got)
+			switch {
+			case sameValues(got, tc.want) != nil:
+				status = "❌"
+			case !slices.Equal(values, tc.values):
+				status = "❌"
+				result += ", but the input was changed"
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package anagrams
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// VIBE CODING: Compare every pair of words
+func vibeGroups(words []string) [][]string {
+	/*
+	   Take each word not yet in a class, and compare it with every word
+	   after it: those that are anagrams of it join its class. To
+	   compare two words, sort the letters of both and see if they
+	   match.
+
+	   n words make n(n-1)/2 comparisons, and each one sorts two words
+	   afresh, allocating as it goes - the same word is sorted again
+	   for every word it is compared with. The length check skips most
+	   pairs, but the work still grows with the square of the list.
+	   And the letters are compared as they are, so "Listen" is not an
+	   anagram of "silent".
+	*/
+	var groups [][]string
+	used := make([]bool, len(words))
+	for i, w := range words {
+		if used[i] {
+			continue
+		}
+		group := []string{w}
+		for j := i + 1; j < len(words); j++ {
+			if !used[j] && isAnagram(w, words[j]) {
+				group = append(group, words[j])
+				used[j] = true
+			}
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// isAnagram reports whether a and b have the same letters.
+func isAnagram(a, b string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	return sortLetters(a) == sortLetters(b)
+}
+
+// sortLetters returns the letters of s in sorted order.
+func sortLetters(s string) string {
+	letters := strings.Split(s, "")
+	sort.Strings(letters)
+	return strings.Join(letters, "")
+}
+
+// HUMAN CODING: Sorted letters as a map key
+func humanGroups(words []string) [][]string {
+	/*
+	   Two words are anagrams if their letters, sorted, are the same -
+	   so the sorted letters make a key, and a map from key to class
+	   groups the words in one pass. A slice remembers each class's
+	   place, so the classes come out in the order of their first word,
+	   not the map's random order. Lowercasing first makes "Listen" an
+	   anagram of "silent".
+
+	   One pass over n words, each sorted once: O(n·k log k) for words
+	   of k letters. But every word is lowercased, turned into a slice
+	   of runes, sorted and turned back into a string - two
+	   allocations or so per word, just to make a key that is thrown
+	   away when the class already exists.
+	*/
+	index := map[string]int{}
+	var groups [][]string
+	for _, w := range words {
+		runes := []rune(strings.ToLower(w))
+		slices.Sort(runes)
+		key := string(runes)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], w)
+	}
+	return groups
+}
+
+// letterCounts is a word's letters as a count of each of a to z: two
+// words are anagrams when their counts are equal.
+type letterCounts [26]uint8
+
+// countLetters counts the letters of w, ignoring case. ok is false if w
+// has anything but the letters a to z, or more than 255 of one.
+func countLetters(w string) (counts letterCounts, ok bool) {
+	for i := 0; i < len(w); i++ {
+		c := w[i] | 0x20 // Lowercases an ASCII letter, and nothing else becomes one
+		if c < 'a' || c > 'z' || counts[c-'a'] == 255 {
+			return counts, false
+		}
+		counts[c-'a']++
+	}
+	return counts, true
+}
+
+// EXPERT CODING: Letter counts as a map key, sorting only as a fallback
+func expertGroups(words []string) [][]string {
+	/*
+	   Count each word's letters into an array of 26 bytes, and use the
+	   array itself as the map key. Counting is one pass over the word
+	   with no sorting, and an array is a value: building the key
+	   allocates nothing, and the map hashes its 26 bytes directly.
+	   The map is sized for every word up front, and the classes come
+	   out in the order of their first word.
+
+	   Counting only works for the letters a to z, which is every word
+	   in most English word lists. Anything else - an accent, a digit,
+	   a word with 256 of one letter - falls back to the human
+	   version's sorted letters, in a map of its own, so every word
+	   still lands in the right class.
+	*/
+	counted := make(map[letterCounts]int, len(words))
+	var sorted map[string]int
+	groups := make([][]string, 0, len(words))
+	for _, w := range words {
+		var i int
+		var ok bool
+		if counts, fast := countLetters(w); fast {
+			if i, ok = counted[counts]; !ok {
+				i = len(groups)
+				counted[counts] = i
+			}
+		} else {
+			runes := []rune(strings.ToLower(w))
+			slices.Sort(runes)
+			if sorted == nil {
+				sorted = map[string]int{}
+			}
+			if i, ok = sorted[string(runes)]; !ok {
+				i = len(groups)
+				sorted[string(runes)] = i
+			}
+		}
+		if !ok {
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], w)
+	}
+	return groups
+}
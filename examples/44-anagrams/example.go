@@ -0,0 +1,302 @@
+// Package anagrams compares three ways to group words into anagram
+// classes: comparing every pair, sorting each word's letters into a key,
+// and counting its letters into one, on a bundled dictionary.
+package anagrams
+
+import (
+	"context"
+	_ "embed"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// dictionaryText is the words grouped: common English words in
+// lowercase ASCII, one per line.
+//
+//go:embed dictionary.txt
+var dictionaryText string
+
+// dictionary is dictionaryText as a list of words.
+var dictionary = strings.Fields(dictionaryText)
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	group            func(words []string) [][]string
+}{
+	{"Vibe coding", "O(n²·k log k)", vibeGroups},
+	{"Human coding", "O(n·k log k)", humanGroups},
+	{"Expert coding", "O(n·k)", expertGroups},
+}
+
+// pickWords returns n words of the dictionary, chosen at random, in
+// dictionary order. n is at most the dictionary's size.
+func pickWords(seed input.Seed, n int) []string {
+	n = min(n, len(dictionary))
+	picked := seed.Rand("words", n).Perm(len(dictionary))[:n]
+	slices.Sort(picked)
+	words := make([]string, n)
+	for i, p := range picked {
+		words[i] = dictionary[p]
+	}
+	return words
+}
+
+// groupers returns the implementations to compare.
+func groupers() []bench.Impl[[]string, [][]string] {
+	impls := make([]bench.Impl[[]string, [][]string], len(tiers))
+	for i, t := range tiers {
+		impls[i] = bench.Impl[[]string, [][]string]{Name: t.name, Complexity: t.complexity, Func: t.group}
+	}
+	return impls
+}
+
+// impls returns the implementations timed on n words of the dictionary.
+func impls(n int) []bench.Implementation {
+	words := pickWords(input.DefaultSeed, n)
+	var list []bench.Implementation
+	for _, g := range groupers() {
+		list = append(list, g.Implementation(words))
+	}
+	return list
+}
+
+// sameGroups reports whether got and want hold the same classes, with
+// the same words, in the same order.
+func sameGroups(got, want [][]string) error {
+	for i := range min(len(got), len(want)) {
+		if !slices.Equal(got[i], want[i]) {
+			return fmt.Errorf("class %d is %q, want %q", i, got[i], want[i])
+		}
+	}
+	if len(got) != len(want) {
+		return fmt.Errorf("got %d classes, want %d", len(got), len(want))
+	}
+	return nil
+}
+
+// largest returns the classes of more than one word, largest first, and
+// in order of their first word among those of a size.
+func largest(groups [][]string) [][]string {
+	var multi [][]string
+	for _, g := range groups {
+		if len(g) > 1 {
+			multi = append(multi, g)
+		}
+	}
+	slices.SortStableFunc(multi, func(a, b []string) int { return len(b) - len(a) })
+	return multi
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "compare every pair of words, sorting both each time", Complexity: "O(n²·k log k)", Notes: []report.Note{
+			report.Strength("Needs no key: the comparison is the definition of an anagram"),
+			report.Pitfall("Every word is sorted again for every word it meets"),
+			report.Pitfall("Ten times the words is a hundred times the work"),
+			report.Pitfall("Compares letters as they are: \"Listen\" and \"silent\" are strangers"),
+		}},
+		{Label: "Human coding", Approach: "sorted, lowercased letters as a map key", Complexity: "O(n·k log k)", Notes: []report.Note{
+			report.Strength("One pass: each word is sorted once, and its class found by a lookup"),
+			report.Strength("Works for any letters, in any case"),
+			report.Pitfall("Builds a new string key for every word, allocating two or so each time"),
+		}},
+		{Label: "Expert coding", Approach: "an array of 26 letter counts as the map key, sorted keys as a fallback", Complexity: "O(n·k)", Notes: []report.Note{
+			report.Strength("Counting needs no sort, and an array key allocates nothing"),
+			report.Strength("The map is sized for every word up front, so it never grows"),
+			report.Pitfall("The fast path knows only a to z: other words take the slow one"),
+		}},
+	},
+	Takeaway: "When things are equal by some property, compute that property " +
+		"once as a key and group with a map, instead of comparing every pair. " +
+		"Then pick the cheapest key: a fixed-size array of counts is a map " +
+		"key that needs no sorting and no allocation.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "44-anagrams",
+		Title:       "Anagram Grouping",
+		Description: "Group the words of a bundled dictionary into anagram classes by comparing every pair, by sorting each word's letters into a key, and by counting them into an array key.",
+		Category:    "algorithms",
+		Difficulty:  examples.Beginner,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    1_000,
+		MaxN:        len(dictionary),
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("44-anagrams", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{100, 1_000, len(dictionary)}
+	fs.Var(&sizes, "n", fmt.Sprintf("comma-separated numbers of words from the dictionary, at most %d, e.g. 500,2000", len(dictionary)))
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	for _, n := range sizes {
+		if n < 1 {
+			return fmt.Errorf("-n must be at least 1, not %d", n)
+		}
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Anagram Grouping", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Anagram Grouping")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "The bundled dictionary has %d common English words, in lowercase\n", len(dictionary))
+
+	impls := groupers()
+	for _, n := range sizes {
+		words := pickWords(*seed, n)
+		n = len(words)
+		if n == len(dictionary) {
+			fmt.Fprintf(out.Table, "\nAll %d words of the dictionary:\n", n)
+		} else {
+			fmt.Fprintf(out.Table, "\n%d words of the dictionary, chosen at random (seed %d):\n", n, *seed)
+		}
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		want := humanGroups(words)
+		results, err := bench.CompareImpls(ctx, opts, words, want, sameGroups, impls...)
+		if err != nil {
+			return err
+		}
+		multi := largest(want)
+		fmt.Fprintf(w, "✔ All implementations found the same %d classes, %d of them with more than one word\n", len(want), len(multi))
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d words", n), results)
+
+		fmt.Fprintln(out.Table, "\nPer word:")
+		for _, r := range results {
+			perWord := float64(r.Duration.Nanoseconds()) / float64(n)
+			allocs := float64(r.Allocs) / float64(n)
+			fmt.Fprintf(out.Table, "  %-14s %9.1f ns  %6.2f allocs\n", r.Name+":", perWord, allocs)
+			section.Notes = append(section.Notes, fmt.Sprintf("%s: %.1f ns and %.2f allocations per word", r.Name, perWord, allocs))
+		}
+
+		if len(multi) > 0 {
+			shown := multi[:min(len(multi), 5)]
+			fmt.Fprintf(w, "\nLargest classes: ")
+			for i, g := range shown {
+				if i > 0 {
+					fmt.Fprint(w, "; ")
+				}
+				fmt.Fprint(w, strings.Join(g, ", "))
+			}
+			fmt.Fprintln(w)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing: anagrams ignore case, and keep the words' order")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	long := strings.Repeat("a", 300)
+	edgeCases := []struct {
+		desc  string
+		words []string
+		want  [][]string
+	}{
+		{"no words", nil, nil},
+		{"listen, silent, enlist, google", []string{"listen", "silent", "enlist", "google"},
+			[][]string{{"listen", "silent", "enlist"}, {"google"}}},
+		{"Listen, Silent, tinsel", []string{"Listen", "Silent", "tinsel"},
+			[][]string{{"Listen", "Silent", "tinsel"}}},
+		{"the same word twice", []string{"dad", "add", "dad"},
+			[][]string{{"dad", "add", "dad"}}},
+		{"same letters, different counts", []string{"aab", "abb", "bab"},
+			[][]string{{"aab"}, {"abb", "bab"}}},
+		{"accents: thé, hét, the", []string{"thé", "hét", "the"},
+			[][]string{{"thé", "hét"}, {"the"}}},
+		{"300 a's, and 300 A's", []string{long, strings.ToUpper(long), ""},
+			[][]string{{long, strings.ToUpper(long)}, {""}}},
+	}
+	for _, tc := range edgeCases {
+		fmt.Fprintf(w, "%s (want: %s):\n", tc.desc, formatGroups(tc.want))
+		for _, t := range tiers {
+			got := t.group(tc.words)
+			status := "✅"
+			if sameGroups(got, tc.want) != nil {
+				status = "❌"
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, formatGroups(got))
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+formatGroups(got))
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
+
+// formatGroups formats classes for the edge cases, shortening long
+// words.
+func formatGroups(groups [][]string) string {
+	parts := make([]string, len(groups))
+	for i, g := range groups {
+		words := make([]string, len(g))
+		for j, w := range g {
+			if len(w) > 12 {
+				w = fmt.Sprintf("%s… (%d letters)", w[:3], len(w))
+			}
+			words[j] = fmt.Sprintf("%q", w)
+		}
+		parts[i] = "{" + strings.Join(words, " ") + "}"
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
@@ -0,0 +1,341 @@
+// Package topk compares three ways to find the k largest values in a
+// stream: sorting them all, quickselect, and a bounded min-heap, as n,
+// k and the order of the stream change.
+package topk
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// orderedN is the length of the ordered streams: long enough that
+// quickselect's O(n²) shows, short enough that it takes under a second.
+const orderedN = 20_000
+
+// query is one top-k query: the k largest of values, read as a stream.
+type query struct {
+	values []int
+	k      int
+}
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	topK             func(stream iter.Seq[int], k int) []int
+}{
+	{"Vibe coding", "O(n log n)", vibeTopK},
+	{"Human coding", "O(n) average, O(n²) worst", humanTopK},
+	{"Expert coding", "O(n log k)", expertTopK},
+}
+
+// selectors returns the implementations to compare.
+func selectors() []bench.Impl[query, []int] {
+	impls := make([]bench.Impl[query, []int], len(tiers))
+	for i, t := range tiers {
+		impls[i] = bench.Impl[query, []int]{
+			Name: t.name, Complexity: t.complexity,
+			Func: func(q query) []int { return t.topK(slices.Values(q.values), q.k) },
+		}
+	}
+	return impls
+}
+
+// defaultK is the k the registry's Impls use.
+const defaultK = 10
+
+// impls returns the implementations timed finding the 10 largest of n
+// random values.
+func impls(n int) []bench.Implementation {
+	q := query{values: input.DefaultSeed.Ints("values", n, 1_000_000_000), k: defaultK}
+	var list []bench.Implementation
+	for _, s := range selectors() {
+		list = append(list, s.Implementation(q))
+	}
+	return list
+}
+
+// largest returns the k largest of values, largest first, by sorting a
+// copy: the answer every implementation must give.
+func largest(values []int, k int) []int {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	slices.Reverse(sorted)
+	return sorted[:max(0, min(k, len(sorted)))]
+}
+
+// topKSafely calls topK, returning what it panicked with, if it did.
+func topKSafely(topK func(iter.Seq[int], int) []int, values []int, k int) (top []int, panicked any) {
+	defer func() { panicked = recover() }()
+	return topK(slices.Values(values), k), nil
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "collect and sort everything, keep the first k", Complexity: "O(n log n) time, O(n) memory", Notes: []report.Note{
+			report.Strength("Three lines, on a well-tested sort"),
+			report.Pitfall("Orders all n values to keep k of them"),
+			report.Pitfall("The answer is a slice of all n: they stay alive with it"),
+			report.Pitfall("Panics when the stream has fewer than k values"),
+		}},
+		{Label: "Human coding", Approach: "quickselect around the last value, then sort the k", Complexity: "O(n) average, O(n²) worst; O(n) memory", Notes: []report.Note{
+			report.Strength("Linear on average, whatever k is"),
+			report.Pitfall("A stream in descending order makes it quadratic"),
+			report.Pitfall("Still collects, and reorders, the whole stream"),
+		}},
+		{Label: "Expert coding", Approach: "a min-heap of the k largest so far", Complexity: "O(n log k) time, O(k) memory", Notes: []report.Note{
+			report.Strength("Most values cost one comparison with the heap's root"),
+			report.Strength("Holds k values, never n: works on streams of any length"),
+			report.Strength("No input order makes it worse than O(n log k)"),
+			report.Pitfall("As k grows toward n, a sift for most values erodes its lead: by k = n/10 quickselect catches up"),
+		}},
+	},
+	Takeaway: "Don't sort everything to keep a few: a heap of the k best so " +
+		"far looks at each value once, in O(k) memory, and never needs the " +
+		"whole stream. Quickselect keeps up when k is a large part of n, " +
+		"but only with a pivot that ordered input can't defeat.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "45-top-k",
+		Title:       "Top-K Selection",
+		Description: "Find the k largest values in a stream by sorting them all, by quickselect and with a bounded min-heap, as n, k and the stream's order change.",
+		Category:    "algorithms",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    100_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("45-top-k", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{10_000, 1_000_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of values in the stream, e.g. 1e5,1e7")
+	k := fs.Int("k", defaultK, "how many of the largest values to find")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *k < 1 {
+		return fmt.Errorf("-k must be at least 1, not %d", *k)
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Top-K Selection", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Top-K Selection")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	impls := selectors()
+	for _, n := range sizes {
+		q := query{values: seed.Ints("values", n, 1_000_000_000), k: *k}
+		fmt.Fprintf(out.Table, "\nThe %d largest of %d random values (seed %d):\n", *k, n, *seed)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		want := largest(q.values, q.k)
+		timed := impls
+		if *k > n {
+			timed = impls[1:] // The vibe version panics
+		}
+		results, err := bench.CompareImpls(ctx, opts, q, want, bench.DiffSlices, timed...)
+		if err != nil {
+			return err
+		}
+		if len(want) > 0 {
+			fmt.Fprintf(w, "✔ All implementations agree: the largest is %d, and the smallest kept is %d\n", want[0], want[len(want)-1])
+		}
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d, k = %d", n, *k), results)
+
+		if *k > n {
+			note := fmt.Sprintf("Vibe coding skipped: it panics with fewer than k=%d values", *k)
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// How each one's cost changes with k, at the largest n.
+	n := sizes[len(sizes)-1]
+	values := seed.Ints("values", n, 1_000_000_000)
+	fmt.Fprintln(out.Table)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(out.Table, "As k grows, with n = %d\n", n)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	var byK []labelled
+	for _, kk := range []int{1, 100, 10_000, n / 10, n} {
+		if kk >= 1 && kk <= n && (len(byK) == 0 || kk > byK[len(byK)-1].query.k) {
+			byK = append(byK, labelled{fmt.Sprintf("k = %d", kk), query{values, kk}})
+		}
+	}
+	if err := compareTable(ctx, out, opts, rep, "k", byK); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "\n  💡 The heap does a sift for each value that gets in: with k a large")
+	fmt.Fprintln(w, "     part of n, that is most of them, and quickselect catches up.")
+
+	// Streams in an order quickselect's pivot handles badly.
+	fmt.Fprintln(out.Table)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(out.Table, "Ordered streams, with n = %d and k = %d\n", orderedN, *k)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	random := seed.Ints("ordered", orderedN, 1_000_000_000)
+	ascending := slices.Sorted(slices.Values(random))
+	descending := slices.Clone(ascending)
+	slices.Reverse(descending)
+	constant := make([]int, orderedN)
+	for i := range constant {
+		constant[i] = 42
+	}
+	ordered := []labelled{
+		{"random", query{random, *k}},
+		{"ascending", query{ascending, *k}},
+		{"descending", query{descending, *k}},
+		{"all equal", query{constant, *k}},
+	}
+	if err := compareTable(ctx, out, opts, rep, "stream", ordered); err != nil {
+		return err
+	}
+	if *k > orderedN {
+		fmt.Fprintf(w, "  ⏭️  Vibe coding skipped: it panics with fewer than k=%d values\n", *k)
+	}
+	fmt.Fprintln(w, "\n  💡 Quickselect's pivot is the last value. Descending, that is the")
+	fmt.Fprintln(w, "     smallest, so each partition peels off one value from the wrong")
+	fmt.Fprintln(w, "     end, and the work is O(n²). Ascending or all equal, it peels off")
+	fmt.Fprintln(w, "     one of the k it wants, so k passes suffice. A random pivot avoids")
+	fmt.Fprintln(w, "     the worst case; the heap never had it.")
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		desc   string
+		values []int
+		k      int
+		want   []int
+	}{
+		{"k = 2 of 5 values", []int{3, 9, 1, 7, 5}, 2, []int{9, 7}},
+		{"k = 0", []int{3, 9, 1}, 0, []int{}},
+		{"k = 1", []int{3, 9, 1}, 1, []int{9}},
+		{"k equal to n", []int{2, 3, 1}, 3, []int{3, 2, 1}},
+		{"k larger than n", []int{2, 3, 1}, 5, []int{3, 2, 1}},
+		{"an empty stream", nil, 3, []int{}},
+		{"ties at the k-th place", []int{5, 1, 5, 5, 2}, 2, []int{5, 5}},
+		{"negative values", []int{-3, -1, -2, -7}, 2, []int{-1, -2}},
+	}
+	for _, tc := range edgeCases {
+		fmt.Fprintf(w, "%s, %v (want: %v):\n", tc.desc, tc.values, tc.want)
+		for _, t := range tiers {
+			got, panicked := topKSafely(t.topK, tc.values, tc.k)
+			status, result := "✅", fmt.Sprint(got)
+			switch {
+			case panicked != nil:
+				status, result = "❌", fmt.Sprintf("panic: %v", panicked)
+			case bench.DiffSlices(got, tc.want) != nil:
+				status = "❌"
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
+
+// labelled is a query with a label for its row of a table.
+type labelled struct {
+	label string
+	query query
+}
+
+// compareTable times every implementation on each query, and prints a
+// row per query with the median time of each. The vibe version is
+// skipped for a query with fewer values than k.
+func compareTable(ctx context.Context, out examples.Output, opts bench.Options, rep *report.Report, heading string, queries []labelled) error {
+	fmt.Fprintf(out.Table, "\n  %-14s", heading)
+	for _, t := range tiers {
+		fmt.Fprintf(out.Table, " %14s", t.name)
+	}
+	fmt.Fprintln(out.Table)
+	for _, l := range queries {
+		q := l.query
+		timed := selectors()
+		if q.k > len(q.values) {
+			timed = timed[1:] // The vibe version panics
+		}
+		results, err := bench.CompareImpls(ctx, opts, q, largest(q.values, q.k), bench.DiffSlices, timed...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out.Table, "  %-14s", l.label)
+		if len(timed) < len(tiers) {
+			fmt.Fprintf(out.Table, " %14s", "skipped")
+		}
+		for _, r := range results {
+			fmt.Fprintf(out.Table, " %12.3fms", r.Milliseconds())
+		}
+		fmt.Fprintln(out.Table)
+		section := rep.Add(fmt.Sprintf("%s, n = %d", l.label, len(q.values)), results)
+		if len(timed) < len(tiers) {
+			section.Notes = append(section.Notes, fmt.Sprintf("Vibe coding skipped: it panics with fewer than k=%d values", q.k))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package topk
+
+import (
+	"iter"
+	"slices"
+	"sort"
+)
+
+// VIBE CODING: Sort everything, keep the first k
+func vibeTopK(stream iter.Seq[int], k int) []int {
+	/*
+	   Collect the stream into a slice, sort it largest first, and
+	   return the first k. Three lines, and the sort is well tested.
+
+	   But it sorts all n values to keep k of them: O(n log n) work,
+	   nearly all of it ordering values that are thrown away. The
+	   whole stream is held in memory, and the answer is a slice of
+	   that memory, so all n values stay alive as long as the caller
+	   keeps the k. And if the stream has fewer than k values,
+	   values[:k] panics.
+	*/
+	values := slices.Collect(stream)
+	sort.Sort(sort.Reverse(sort.IntSlice(values)))
+	return values[:k]
+}
+
+// HUMAN CODING: Quickselect, then sort only the k
+func humanTopK(stream iter.Seq[int], k int) []int {
+	/*
+	   Quickselect partitions the values around a pivot, larger values
+	   to the left, like one step of quicksort - but then carries on
+	   only into the side that holds the k-th largest. On average each
+	   step halves the work, so it is O(n), and only the k values it
+	   leaves at the front need sorting.
+
+	   The pivot is the last value in the range, as in most textbook
+	   versions. On random values that is fine. But if the stream is
+	   already in descending order, the last value is the smallest, so
+	   each partition peels off a single value from the wrong end, and
+	   quickselect becomes O(n²). It still collects the whole stream
+	   into memory first, and reorders it.
+	*/
+	values := slices.Collect(stream)
+	k = min(k, len(values))
+	lo, hi := 0, len(values)-1
+	for lo < hi {
+		p := partition(values, lo, hi)
+		switch {
+		case p == k-1:
+			lo = hi
+		case p < k-1:
+			lo = p + 1
+		default:
+			hi = p - 1
+		}
+	}
+	top := slices.Clone(values[:k])
+	sort.Sort(sort.Reverse(sort.IntSlice(top)))
+	return top
+}
+
+// partition moves the values in a[lo:hi+1] larger than a[hi] to its
+// front, followed by a[hi] itself, and returns where that ends up.
+func partition(a []int, lo, hi int) int {
+	pivot := a[hi]
+	i := lo
+	for j := lo; j < hi; j++ {
+		if a[j] > pivot {
+			a[i], a[j] = a[j], a[i]
+			i++
+		}
+	}
+	a[i], a[hi] = a[hi], a[i]
+	return i
+}
+
+// EXPERT CODING: A min-heap of the k largest so far
+func expertTopK(stream iter.Seq[int], k int) []int {
+	/*
+	   Keep the k largest values seen so far in a min-heap, whose root
+	   is the smallest of them: the value a newcomer must beat. Most
+	   values don't beat it, and cost one comparison; one that does
+	   replaces the root and sifts down, O(log k). The whole stream is
+	   O(n log k), and on random values only about k·ln(n/k) of them
+	   ever get in.
+
+	   It holds k values, never n, so the stream can be any length -
+	   a file, a network feed - without being collected first. No
+	   input order makes it worse than O(n log k). The heap is sorted
+	   into the answer at the end, which is all of its own memory. As
+	   k grows toward n, most values get in and each costs a sift, so
+	   quickselect catches up.
+	*/
+	if k <= 0 {
+		return []int{}
+	}
+	h := make([]int, 0, k)
+	for v := range stream {
+		switch {
+		case len(h) < k:
+			h = append(h, v)
+			siftUp(h, len(h)-1)
+		case v > h[0]:
+			h[0] = v
+			siftDown(h, 0)
+		}
+	}
+	slices.Sort(h)
+	slices.Reverse(h)
+	return h
+}
+
+// siftUp moves h[i] up the min-heap h until its parent is no larger.
+func siftUp(h []int, i int) {
+	v := h[i]
+	for i > 0 {
+		parent := (i - 1) / 2
+		if h[parent] <= v {
+			break
+		}
+		h[i] = h[parent]
+		i = parent
+	}
+	h[i] = v
+}
+
+// siftDown moves h[i] down the min-heap h until its children are no
+// smaller.
+func siftDown(h []int, i int) {
+	v := h[i]
+	for {
+		child := 2*i + 1
+		if child >= len(h) {
+			break
+		}
+		if child+1 < len(h) && h[child+1] < h[child] {
+			child++
+		}
+		if v <= h[child] {
+			break
+		}
+		h[i] = h[child]
+		i = child
+	}
+	h[i] = v
+}
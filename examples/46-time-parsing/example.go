@@ -0,0 +1,272 @@
+// Package timeparse compares three ways to parse the timestamps of a log
+// written in several formats: trying time.Parse with each layout in
+// turn, detecting the layout and parsing once, and a hand-written parser
+// for the fixed formats, in parses per second.
+package timeparse
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	parse            func(s string) (time.Time, error)
+}{
+	{"Vibe coding", "up to 6 parses per line", vibeParse},
+	{"Human coding", "1 parse per line", humanParse},
+	{"Expert coding", "1 pass per line", expertParse},
+}
+
+// parsers returns the implementations to compare, each summing the
+// timestamps of its input lines.
+func parsers() []bench.Impl[[]string, int64] {
+	impls := make([]bench.Impl[[]string, int64], len(tiers))
+	for i, t := range tiers {
+		impls[i] = bench.Impl[[]string, int64]{
+			Name: t.name, Complexity: t.complexity,
+			FuncContext: func(ctx context.Context, lines []string) (int64, error) {
+				if err := ctx.Err(); err != nil {
+					return 0, err
+				}
+				return sumTimestamps(t.parse, lines)
+			},
+		}
+	}
+	return impls
+}
+
+// impls returns the implementations timed on a log of n lines.
+func impls(n int) []bench.Implementation {
+	lines, _ := makeLog(input.DefaultSeed, n, -1)
+	var list []bench.Implementation
+	for _, p := range parsers() {
+		list = append(list, p.Implementation(lines))
+	}
+	return list
+}
+
+// formatSample is the number of lines timed for each format on its own.
+const formatSample = 100_000
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "time.Parse with each known layout in turn", Complexity: "up to 6 parses per line", Notes: []report.Note{
+			report.Strength("A new format is one more line in the list"),
+			report.Pitfall("Every failed layout costs a parse and an error value"),
+			report.Pitfall("Later layouts in the list are slower to reach"),
+			report.Pitfall("A bad timestamp gets the last layout's error, not the relevant one"),
+		}},
+		{Label: "Human coding", Approach: "pick the layout from a character or two, then time.Parse once", Complexity: "1 parse per line", Notes: []report.Note{
+			report.Strength("One parse per line, and errors from the right layout"),
+			report.Strength("time.Parse is well tested, and has a fast path for RFC 3339"),
+			report.Pitfall("Other layouts are interpreted element by element, every time"),
+		}},
+		{Label: "Expert coding", Approach: "read each field's digits at its fixed position", Complexity: "1 pass per line", Notes: []report.Note{
+			report.Strength("No layout to interpret and no allocations"),
+			report.Strength("Range-checks every field, down to leap years"),
+			report.Pitfall("Knows its formats by heart: each new one is more code to write and test"),
+		}},
+	},
+	Takeaway: "Don't make the parser guess: each failed layout costs a full " +
+		"parse and an error. Detect the format once and parse with the right " +
+		"layout; in a hot loop over a known, fixed format, read the fields " +
+		"by position.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "46-time-parsing",
+		Title:       "Timestamp Parsing",
+		Description: "Parse a million log timestamps in mixed formats by trying each layout in turn, by detecting the layout and parsing once, and with a hand-written fixed-format parser, and compare parses per second.",
+		Category:    "performance",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    100_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("46-time-parsing", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{10_000, 1_000_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of log lines, e.g. 1e5,1e7")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	for _, n := range sizes {
+		if n < 1 {
+			return fmt.Errorf("-n must be at least 1, not %d", n)
+		}
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Timestamp Parsing", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Timestamp Parsing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Log lines of a month, from services writing timestamps in different formats:")
+	for i, f := range formats {
+		example, _ := makeLog(*seed, 1, i)
+		fmt.Fprintf(w, "  %d in 10  %-20s %s\n", f.weight, f.name, example[0])
+	}
+
+	impls := parsers()
+	for _, n := range sizes {
+		lines, want := makeLog(*seed, n, -1)
+		fmt.Fprintf(out.Table, "\nParsing the timestamps of %d log lines (seed %d):\n", n, *seed)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		results, err := bench.CompareImpls(ctx, opts, lines, want, bench.Equal[int64], impls...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "✔ All implementations parsed every timestamp to the same instant")
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d lines", n), results)
+
+		fmt.Fprintln(out.Table, "\nParses per second:")
+		for _, r := range results {
+			perSec := float64(n) / r.Duration.Seconds()
+			allocs := float64(r.Allocs) / float64(n)
+			fmt.Fprintf(out.Table, "  %-14s %8.2fM/s  %5.2f allocs per parse\n", r.Name+":", perSec/1e6, allocs)
+			section.Notes = append(section.Notes, fmt.Sprintf("%s: %.2f million parses per second, %.2f allocations each", r.Name, perSec/1e6, allocs))
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Each format on its own: the vibe version's cost depends on how far
+	// down its list the layout is.
+	fmt.Fprintln(out.Table)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(out.Table, "Nanoseconds per parse, by format (%d lines each)\n", formatSample)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(out.Table, "\n  %-20s", "format")
+	for _, t := range tiers {
+		fmt.Fprintf(out.Table, " %14s", t.name)
+	}
+	fmt.Fprintln(out.Table)
+	for i, f := range formats {
+		lines, want := makeLog(*seed, formatSample, i)
+		results, err := bench.CompareImpls(ctx, opts, lines, want, bench.Equal[int64], impls...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out.Table, "  %-20s", f.name)
+		cells := make([]string, len(results))
+		for j, r := range results {
+			ns := float64(r.Duration.Nanoseconds()) / formatSample
+			fmt.Fprintf(out.Table, " %11.1f ns", ns)
+			cells[j] = fmt.Sprintf("%s %.1f ns", r.Name, ns)
+		}
+		fmt.Fprintln(out.Table)
+		rep.AddEdgeCase("Per parse, "+f.name, strings.Join(cells, "; "))
+	}
+	fmt.Fprintln(w, "\n  💡 The vibe version tries RFC3339 and RFC3339Nano before the")
+	fmt.Fprintln(w, "     database's layout, and both before the Common Log Format's.")
+	fmt.Fprintln(w, "     time.Parse has a fast path for RFC 3339, so the human version")
+	fmt.Fprintln(w, "     is closest to the expert's there.")
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing: the instants parsed, in UTC")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		desc, in string
+		want     string // The instant in RFC 3339, or "" for an error
+		errWant  string // If set, the error must contain it
+	}{
+		{"RFC 3339", "2024-03-05T14:07:09Z", "2024-03-05T14:07:09Z", ""},
+		{"RFC 3339 with µs and an offset", "2024-03-05T14:07:09.123456+02:00", "2024-03-05T12:07:09.123456Z", ""},
+		{"the database's format", "2024-03-05 14:07:09", "2024-03-05T14:07:09Z", ""},
+		{"Common Log Format", "05/Mar/2024:14:07:09 -0700", "2024-03-05T21:07:09Z", ""},
+		{"a leap day", "2024-02-29T12:00:00Z", "2024-02-29T12:00:00Z", ""},
+		{"a leap day in a common year", "2023-02-29T12:00:00Z", "", "day out of range"},
+		{"hour 24", "2024-03-05 24:00:00", "", "hour out of range"},
+		{"no zone in RFC 3339", "2024-03-05T14:07:09", "", ""},
+		{"an empty timestamp", "", "", ""},
+	}
+	for _, tc := range edgeCases {
+		want := tc.want
+		switch {
+		case tc.errWant != "":
+			want = fmt.Sprintf("an error mentioning %q", tc.errWant)
+		case tc.want == "":
+			want = "an error"
+		}
+		fmt.Fprintf(w, "%s, %q (want: %s):\n", tc.desc, tc.in, want)
+		for _, t := range tiers {
+			got, err := t.parse(tc.in)
+			result := got.UTC().Format(time.RFC3339Nano)
+			good := err == nil && result == tc.want
+			if err != nil {
+				result = "error: " + err.Error()
+				good = tc.want == "" && strings.Contains(err.Error(), tc.errWant)
+			}
+			status := "✅"
+			if !good {
+				status = "❌"
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
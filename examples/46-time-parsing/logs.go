@@ -0,0 +1,95 @@
+package timeparse
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iportilla/ai-coding/input"
+)
+
+// The layouts of the database's and the web server's timestamps.
+const (
+	isoLayout = "2006-01-02 15:04:05"        // The database's, in UTC
+	clfLayout = "02/Jan/2006:15:04:05 -0700" // The web server's access log
+)
+
+// separator is between a line's timestamp and its message.
+const separator = " | "
+
+// formats are the forms of timestamp the services write to the log,
+// and how many lines in ten have each.
+var formats = []struct {
+	name   string
+	layout string
+	unit   time.Duration // The timestamps' precision
+	zoned  bool          // Whether they are in a zone other than UTC
+	weight int
+}{
+	{"RFC 3339", time.RFC3339, time.Second, false, 3},
+	{"RFC 3339, ms", "2006-01-02T15:04:05.000Z07:00", time.Millisecond, false, 2},
+	{"RFC 3339, µs, zone", "2006-01-02T15:04:05.000000Z07:00", time.Microsecond, true, 2},
+	{"database", isoLayout, time.Second, false, 2},
+	{"Common Log", clfLayout, time.Second, true, 1},
+}
+
+// zones are the offsets from UTC of the services that don't log in it.
+var zones = []*time.Location{
+	time.FixedZone("", 2*3600),
+	time.FixedZone("", -7*3600),
+	time.FixedZone("", 5*3600+1800),
+}
+
+// messages are what the log lines say after their timestamps.
+var messages = []string{
+	"GET /api/orders 200 12ms",
+	"user 4812 logged in",
+	"cache miss for key session:9f3a",
+	"slow query: 480ms",
+	"POST /api/checkout 201 48ms",
+	"worker 7 finished batch 1193",
+}
+
+// makeLog returns n log lines from March 2024, each starting with a
+// timestamp in formats[format], or, if format is negative, in a mix of
+// them by weight. It also returns the sum of the timestamps as Unix
+// nanoseconds.
+func makeLog(seed input.Seed, n, format int) (lines []string, sum int64) {
+	rng := seed.Rand(fmt.Sprint("log", format), n)
+	start := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	month := int64(31 * 24 * time.Hour)
+	lines = make([]string, n)
+	for i := range lines {
+		f := format
+		if f < 0 {
+			r := rng.IntN(10)
+			for f = 0; r >= formats[f].weight; f++ {
+				r -= formats[f].weight
+			}
+		}
+		t := start.Add(time.Duration(rng.Int64N(month))).Truncate(formats[f].unit)
+		ts := t
+		if formats[f].zoned {
+			ts = t.In(zones[rng.IntN(len(zones))])
+		}
+		lines[i] = ts.Format(formats[f].layout) + separator + messages[rng.IntN(len(messages))]
+		sum += t.UnixNano()
+	}
+	return lines, sum
+}
+
+// sumTimestamps parses the timestamp of every line with parse, and
+// returns their sum as Unix nanoseconds, or the first error, naming its
+// line.
+func sumTimestamps(parse func(string) (time.Time, error), lines []string) (int64, error) {
+	var sum int64
+	for i, line := range lines {
+		ts, _, _ := strings.Cut(line, separator)
+		t, err := parse(ts)
+		if err != nil {
+			return 0, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		sum += t.UnixNano()
+	}
+	return sum, nil
+}
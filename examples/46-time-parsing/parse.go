@@ -0,0 +1,257 @@
+package timeparse
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// vibeLayouts are the layouts the vibe version tries, in turn.
+var vibeLayouts = []string{
+	time.RFC3339,
+	time.RFC3339Nano,
+	isoLayout,
+	clfLayout,
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// VIBE CODING: Try every layout until one parses
+func vibeParse(s string) (time.Time, error) {
+	/*
+	   Try each layout the log might use until time.Parse accepts one.
+	   Adding a format is one more line in the list, and nothing has to
+	   know what the formats look like.
+
+	   But every failed attempt does the work of a parse and builds a
+	   *time.ParseError to say why, which is thrown away: a line from
+	   the web server fails three layouts before the fourth fits.
+	   RFC3339Nano is redundant - time.Parse accepts fractional
+	   seconds after RFC3339's seconds anyway - so it only costs. And
+	   when nothing fits, the error returned is the last layout's, so
+	   a bad date in an RFC 3339 timestamp is reported as a failure to
+	   match RFC 1123.
+	*/
+	var err error
+	for _, layout := range vibeLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// HUMAN CODING: Pick the layout by looking, then parse once
+func humanParse(s string) (time.Time, error) {
+	/*
+	   The formats differ in a character or two: the database's has a
+	   space where RFC 3339 has a T, and the Common Log Format starts
+	   with a two-digit day and a slash. Looking at those picks the
+	   one layout to try, so every line is parsed once, and an error
+	   comes from the layout the timestamp was meant to match.
+
+	   time.Parse is still general: it walks the layout string for
+	   every timestamp, element by element, and builds the time from
+	   the pieces. For RFC 3339 it has a fast path of its own; the
+	   other two layouts go the long way.
+	*/
+	layout := time.RFC3339
+	switch {
+	case len(s) > 10 && s[10] == ' ':
+		layout = isoLayout
+	case len(s) > 2 && s[2] == '/':
+		layout = clfLayout
+	}
+	return time.Parse(layout, s)
+}
+
+// EXPERT CODING: Parse the fixed formats by position
+func expertParse(s string) (time.Time, error) {
+	/*
+	   Every field of these formats is at a fixed position, so read the
+	   digits where they are: no layout to interpret, no allocation.
+	   The same shape check as the human version picks the format,
+	   and each field is range-checked - a 29th of February in a year
+	   that isn't a leap year is an error, not the 1st of March, as
+	   time.Date would make it. The fields are then counted into
+	   seconds since 1970 directly, with no time.Date to normalise
+	   them.
+
+	   The cost is code that knows its formats by heart: a new one
+	   means another function, and its own tests. Anything it doesn't
+	   recognise is an error, where time.Parse with the right layout
+	   would have worked.
+	*/
+	switch {
+	case len(s) >= 19 && s[4] == '-' && s[7] == '-' && (s[10] == 'T' || s[10] == ' '):
+		return parseISO(s)
+	case len(s) == len(clfLayout) && s[2] == '/' && s[6] == '/':
+		return parseCLF(s)
+	}
+	return time.Time{}, parseError(s, errFormat)
+}
+
+var (
+	errFormat = errors.New("unknown format")
+	errSyntax = errors.New("bad syntax")
+)
+
+// parseError returns err for the timestamp s.
+func parseError(s string, err error) error {
+	return fmt.Errorf("parsing time %q: %w", s, err)
+}
+
+// parseISO parses RFC 3339, "2006-01-02T15:04:05.999999999Z07:00", or
+// the database's "2006-01-02 15:04:05.999999999" in UTC. Fractional
+// seconds are optional, and digits past the ninth are ignored, as
+// time.Parse does.
+func parseISO(s string) (time.Time, error) {
+	year, ok1 := digits(s[0:4])
+	month, ok2 := digits(s[5:7])
+	day, ok3 := digits(s[8:10])
+	hour, ok4 := digits(s[11:13])
+	minute, ok5 := digits(s[14:16])
+	sec, ok6 := digits(s[17:19])
+	if !(ok1 && ok2 && ok3 && ok4 && ok5 && ok6) || s[13] != ':' || s[16] != ':' {
+		return time.Time{}, parseError(s, errSyntax)
+	}
+	rest := s[19:]
+	nsec := 0
+	if len(rest) > 0 && rest[0] == '.' {
+		i := 1
+		for scale := 100_000_000; i < len(rest) && rest[i]-'0' <= 9; i++ {
+			nsec += int(rest[i]-'0') * scale
+			scale /= 10
+		}
+		if i == 1 {
+			return time.Time{}, parseError(s, errSyntax)
+		}
+		rest = rest[i:]
+	}
+	offset := 0
+	switch {
+	case s[10] == ' ' && rest == "":
+	case s[10] == 'T' && rest == "Z":
+	case s[10] == 'T' && len(rest) == 6 && rest[3] == ':':
+		var ok bool
+		if offset, ok = zoneOffset(rest[0], rest[1:3], rest[4:6]); !ok {
+			return time.Time{}, parseError(s, errSyntax)
+		}
+	default:
+		return time.Time{}, parseError(s, errSyntax)
+	}
+	return date(s, year, time.Month(month), day, hour, minute, sec, nsec, offset)
+}
+
+// monthNames are the months' abbreviations, in lowercase.
+var monthNames = [12]string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+
+// parseCLF parses the Common Log Format's "02/Jan/2006:15:04:05 -0700".
+// The month's name may be in any case, as time.Parse allows.
+func parseCLF(s string) (time.Time, error) {
+	day, ok1 := digits(s[0:2])
+	year, ok2 := digits(s[7:11])
+	hour, ok3 := digits(s[12:14])
+	minute, ok4 := digits(s[15:17])
+	sec, ok5 := digits(s[18:20])
+	offset, ok6 := zoneOffset(s[21], s[22:24], s[24:26])
+	if !(ok1 && ok2 && ok3 && ok4 && ok5 && ok6) || s[11] != ':' || s[14] != ':' || s[17] != ':' || s[20] != ' ' {
+		return time.Time{}, parseError(s, errSyntax)
+	}
+	name := [3]byte{s[3] | 0x20, s[4] | 0x20, s[5] | 0x20}
+	month := 0
+	for i, m := range monthNames {
+		if string(name[:]) == m {
+			month = i + 1
+		}
+	}
+	if month == 0 {
+		return time.Time{}, parseError(s, errSyntax)
+	}
+	return date(s, year, time.Month(month), day, hour, minute, sec, 0, offset)
+}
+
+// date checks the fields parsed from s and returns the time they name,
+// offset seconds east of UTC.
+func date(s string, year int, month time.Month, day, hour, minute, sec, nsec, offset int) (time.Time, error) {
+	var field string
+	switch {
+	case month < time.January || month > time.December:
+		field = "month"
+	case day < 1 || day > daysIn(year, month):
+		field = "day"
+	case hour > 23:
+		field = "hour"
+	case minute > 59:
+		field = "minute"
+	case sec > 59:
+		field = "second"
+	}
+	if field != "" {
+		return time.Time{}, parseError(s, fmt.Errorf("%s out of range", field))
+	}
+	secs := daysSinceEpoch(year, month, day)*86400 + int64(hour*3600+minute*60+sec-offset)
+	return time.Unix(secs, int64(nsec)).UTC(), nil
+}
+
+// daysSinceEpoch returns the number of days from 1970-01-01 to the given
+// date in the proleptic Gregorian calendar, counting years from March so
+// that the leap day comes last.
+func daysSinceEpoch(year int, month time.Month, day int) int64 {
+	y := int64(year)
+	if month <= time.February {
+		y--
+	}
+	era := y / 400
+	if y < 0 && y%400 != 0 {
+		era--
+	}
+	yearOfEra := y - era*400
+	m := (int64(month) + 9) % 12 // March is 0
+	dayOfYear := (153*m+2)/5 + int64(day) - 1
+	dayOfEra := yearOfEra*365 + yearOfEra/4 - yearOfEra/100 + dayOfYear
+	return era*146097 + dayOfEra - 719468
+}
+
+// daysIn returns the number of days in month of year.
+func daysIn(year int, month time.Month) int {
+	switch month {
+	case time.February:
+		if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+			return 29
+		}
+		return 28
+	case time.April, time.June, time.September, time.November:
+		return 30
+	}
+	return 31
+}
+
+// zoneOffset returns the offset, in seconds east of UTC, of a zone
+// written as a sign, two digits of hours and two of minutes.
+func zoneOffset(sign byte, hours, minutes string) (int, bool) {
+	h, ok1 := digits(hours)
+	m, ok2 := digits(minutes)
+	if !ok1 || !ok2 || m > 59 || (sign != '+' && sign != '-') {
+		return 0, false
+	}
+	offset := h*3600 + m*60
+	if sign == '-' {
+		offset = -offset
+	}
+	return offset, true
+}
+
+// digits returns the decimal number s, which must be all digits.
+func digits(s string) (int, bool) {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		d := s[i] - '0'
+		if d > 9 {
+			return 0, false
+		}
+		n = n*10 + int(d)
+	}
+	return n, true
+}
@@ -0,0 +1,371 @@
+// Package uniqueids compares three ways to generate unique IDs: the time
+// and a random number formatted with fmt.Sprintf, random UUIDs from
+// crypto/rand, and monotonic ULIDs, counting the IDs each one repeats
+// and how many it makes per second.
+package uniqueids
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// generatorFunc makes an ID generator that reads the time from now.
+type generatorFunc func(now func() time.Time) func() string
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	newGenerator     generatorFunc
+}{
+	{"Vibe coding", "Sprintf of time and math/rand", newVibeGenerator},
+	{"Human coding", "crypto/rand UUIDv4", newHumanGenerator},
+	{"Expert coding", "monotonic ULID", newExpertGenerator},
+}
+
+// defaultGoroutines is how many goroutines make IDs at once, like a
+// server's handlers.
+const defaultGoroutines = 4
+
+// ctxEvery is how many IDs a goroutine makes between checks for
+// cancellation.
+const ctxEvery = 1 << 12
+
+// generate makes n IDs with next from goroutines goroutines at once, each
+// filling its share of the result, stopping early if ctx is done.
+func generate(ctx context.Context, next func() string, n, goroutines int) ([]string, error) {
+	ids := make([]string, n)
+	errs := make([]error, goroutines)
+	var wg sync.WaitGroup
+	for worker := range goroutines {
+		lo, hi := n*worker/goroutines, n*(worker+1)/goroutines
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := lo; i < hi; i++ {
+				if (i-lo)%ctxEvery == 0 && ctx.Err() != nil {
+					errs[worker] = ctx.Err()
+					return
+				}
+				ids[i] = next()
+			}
+		}()
+	}
+	wg.Wait()
+	return ids, errors.Join(errs...)
+}
+
+// repeats returns how many of ids are the same as an earlier one.
+func repeats(ids []string) int {
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		seen[id] = struct{}{}
+	}
+	return len(ids) - len(seen)
+}
+
+// frozen returns a clock that always reads t.
+func frozen(t time.Time) func() time.Time {
+	return func() time.Time { return t }
+}
+
+// birthday returns the number of repeats expected among k values drawn
+// at random from m.
+func birthday(k, m float64) float64 {
+	return k - m*(1-math.Pow(1-1/m, k))
+}
+
+// timed returns an Implementation making n IDs per run with a generator
+// from newGenerator, on goroutines goroutines.
+func timed(name, complexity string, newGenerator generatorFunc, n, goroutines int) bench.Implementation {
+	next := newGenerator(time.Now)
+	return bench.Implementation{
+		Name: name, Complexity: complexity,
+		RunContext: func(ctx context.Context) error {
+			_, err := generate(ctx, next, n, goroutines)
+			return err
+		},
+	}
+}
+
+// impls returns the implementations timed making n IDs.
+func impls(n int) []bench.Implementation {
+	list := make([]bench.Implementation, len(tiers))
+	for i, t := range tiers {
+		list[i] = timed(t.name, t.complexity, t.newGenerator, n, defaultGoroutines)
+	}
+	return list
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "the Unix time and six random digits, with fmt.Sprintf", Complexity: "Sprintf of time and math/rand", Notes: []report.Note{
+			report.Strength("One line, and the IDs look unique"),
+			report.Pitfall("A million values a second: repeats are likely after about 1,200"),
+			report.Pitfall("math/rand is predictable, and the order only holds to the second"),
+		}},
+		{Label: "Human coding", Approach: "16 bytes from crypto/rand as a version 4 UUID", Complexity: "crypto/rand UUIDv4", Notes: []report.Note{
+			report.Strength("122 random bits: repeats are out of the question, and IDs can't be guessed"),
+			report.Strength("No clock and no state, in a format everything understands"),
+			report.Pitfall("No order: database inserts land all over the index"),
+			report.Pitfall("fmt.Sprintf costs more than the random bytes"),
+		}},
+		{Label: "Expert coding", Approach: "milliseconds and 80 random bits, incremented within a millisecond", Complexity: "monotonic ULID", Notes: []report.Note{
+			report.Strength("Sorts as text in the order made, even if the clock steps back"),
+			report.Strength("crypto/rand once a millisecond, and encoded by hand"),
+			report.Pitfall("Every ID shows when it was made"),
+			report.Pitfall("IDs from one millisecond are guessable from each other"),
+		}},
+	},
+	Takeaway: "An ID is unique because of how many random bits it has, not how " +
+		"it looks: count them, and the birthday paradox tells you when it will " +
+		"repeat. Use a random UUID when IDs must be unguessable; use a " +
+		"time-ordered one such as a ULID when they are database keys.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "47-unique-ids",
+		Title:       "Unique IDs",
+		Description: "Generate IDs with fmt.Sprintf of the time and a random number, as crypto/rand UUIDs and as monotonic ULIDs, count the repeats, and compare IDs per second.",
+		Category:    "performance",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    100_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("47-unique-ids", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{10_000, 1_000_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of IDs to make, e.g. 1e5,1e7")
+	goroutines := fs.Int("goroutines", defaultGoroutines, "goroutines making IDs at once")
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *goroutines < 1 {
+		return errors.New("-goroutines must be at least 1")
+	}
+	for _, n := range sizes {
+		if n < 1 {
+			return fmt.Errorf("-n must be at least 1, not %d", n)
+		}
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Unique IDs", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Unique IDs")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "GOMAXPROCS = %d; %d goroutine(s) making IDs at once\n", runtime.GOMAXPROCS(0), *goroutines)
+	fmt.Fprintln(w, "An ID from each:")
+	for _, t := range tiers {
+		fmt.Fprintf(w, "  %-14s %s\n", t.name+":", t.newGenerator(time.Now)())
+	}
+
+	for _, n := range sizes {
+		fmt.Fprintf(out.Table, "\nMaking %d IDs on %d goroutine(s):\n", n, *goroutines)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		// IDs that repeat are wrong, but they are still made: Vibe coding
+		// is timed like the others, with its repeats noted.
+		var repeated []string
+		impls := make([]bench.Implementation, len(tiers))
+		for i, t := range tiers {
+			ids, err := generate(ctx, t.newGenerator(time.Now), n, *goroutines)
+			if err != nil {
+				return err
+			}
+			switch r := repeats(ids); {
+			case r > 0 && t.name != "Vibe coding":
+				return fmt.Errorf("verification failed: %s: %d of %d IDs repeated", t.name, r, n)
+			case r > 0:
+				repeated = append(repeated, fmt.Sprintf("%s repeated %d of %d IDs", t.name, r, n))
+			}
+			impls[i] = timed(t.name, t.complexity, t.newGenerator, n, *goroutines)
+		}
+		if len(repeated) == 0 {
+			fmt.Fprintf(w, "✔ All implementations made %d different IDs - Vibe coding by luck\n", n)
+		}
+		results, err := bench.CompareContext(ctx, opts, impls...)
+		if err != nil {
+			return err
+		}
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d IDs", n), results)
+		for _, note := range repeated {
+			fmt.Fprintln(w, "  ❌ "+note)
+			section.Notes = append(section.Notes, note)
+		}
+
+		fmt.Fprintln(out.Table, "\nIDs per second:")
+		for _, r := range results {
+			perSec := float64(n) / r.Duration.Seconds()
+			allocs := float64(r.Allocs) / float64(n)
+			fmt.Fprintf(out.Table, "  %-14s %8.2fM/s  %5.2f allocs per ID\n", r.Name+":", perSec/1e6, allocs)
+			section.Notes = append(section.Notes, fmt.Sprintf("%s: %.2f million IDs per second, %.2f allocations each", r.Name, perSec/1e6, allocs))
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// The birthday paradox: IDs made in the same second share the vibe
+	// version's million values, and repeat far sooner than a million.
+	fmt.Fprintln(out.Table)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(out.Table, "Repeats among IDs made in the same second")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(out.Table, "\n  %9s", "IDs")
+	for _, t := range tiers {
+		fmt.Fprintf(out.Table, " %14s", t.name)
+	}
+	fmt.Fprintf(out.Table, " %14s\n", "Expected, vibe")
+	second := frozen(time.Now().Truncate(time.Second))
+	for _, k := range []int{100, 1_000, 10_000, 100_000} {
+		fmt.Fprintf(out.Table, "  %9d", k)
+		cells := make([]string, len(tiers))
+		for i, t := range tiers {
+			ids, err := generate(ctx, t.newGenerator(second), k, *goroutines)
+			if err != nil {
+				return err
+			}
+			r := repeats(ids)
+			fmt.Fprintf(out.Table, " %14d", r)
+			cells[i] = fmt.Sprintf("%s %d", t.name, r)
+		}
+		expected := birthday(float64(k), 1e6)
+		fmt.Fprintf(out.Table, " %14.1f\n", expected)
+		rep.AddEdgeCase(fmt.Sprintf("Repeats among %d IDs in one second", k), fmt.Sprintf("%s; expected for Vibe coding %.1f", strings.Join(cells, "; "), expected))
+	}
+	fmt.Fprintln(w, "\n  💡 Six random digits are a million values, but by the birthday")
+	fmt.Fprintln(w, "     paradox two of k IDs are more likely than not to match once")
+	fmt.Fprintln(w, "     k passes about 1,200: the square root of 2 ln 2 × 1,000,000.")
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing: uniqueness and order")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	start := time.Now()
+	edgeCases := []struct {
+		desc, want string
+		check      func(newGenerator generatorFunc) (bool, string)
+	}{
+		{"1,000 IDs from one goroutine", "each sorts after the last", func(newGenerator generatorFunc) (bool, string) {
+			ids, _ := generate(ctx, newGenerator(time.Now), 1_000, 1)
+			return orderResult(ids)
+		}},
+		{"1,000 IDs while the clock stands still", "each sorts after the last", func(newGenerator generatorFunc) (bool, string) {
+			ids, _ := generate(ctx, newGenerator(frozen(start)), 1_000, 1)
+			return orderResult(ids)
+		}},
+		{"100 IDs, the clock steps back a second, 100 more", "each sorts after the last", func(newGenerator generatorFunc) (bool, string) {
+			now := start
+			next := newGenerator(func() time.Time { return now })
+			ids, _ := generate(ctx, next, 100, 1)
+			now = now.Add(-time.Second)
+			more, _ := generate(ctx, next, 100, 1)
+			return orderResult(append(ids, more...))
+		}},
+		{"100,000 IDs from 8 goroutines", "no repeats", func(newGenerator generatorFunc) (bool, string) {
+			ids, _ := generate(ctx, newGenerator(time.Now), 100_000, 8)
+			return repeatResult(ids)
+		}},
+		{"two generators, 10,000 IDs each in the same millisecond", "no repeats", func(newGenerator generatorFunc) (bool, string) {
+			a, _ := generate(ctx, newGenerator(frozen(start)), 10_000, 1)
+			b, _ := generate(ctx, newGenerator(frozen(start)), 10_000, 1)
+			return repeatResult(append(a, b...))
+		}},
+	}
+	for _, tc := range edgeCases {
+		fmt.Fprintf(w, "%s (want: %s):\n", tc.desc, tc.want)
+		for _, t := range tiers {
+			good, result := tc.check(t.newGenerator)
+			status := "✅"
+			if !good {
+				status = "❌"
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+result)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
+
+// orderResult reports whether ids are in increasing order, and where
+// they first aren't.
+func orderResult(ids []string) (bool, string) {
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			return false, fmt.Sprintf("ID %d, %s, doesn't sort after ID %d, %s", i+1, ids[i], i, ids[i-1])
+		}
+	}
+	return true, fmt.Sprintf("in order, %s to %s", ids[0], ids[len(ids)-1])
+}
+
+// repeatResult reports whether ids has no repeats, and how many it has.
+func repeatResult(ids []string) (bool, string) {
+	if r := repeats(ids); r > 0 {
+		return false, fmt.Sprintf("%d of %d repeated", r, len(ids))
+	}
+	return true, fmt.Sprintf("all %d different", len(ids))
+}
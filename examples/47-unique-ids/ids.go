@@ -0,0 +1,119 @@
+package uniqueids
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	mathrand "math/rand/v2"
+	"sync"
+	"time"
+)
+
+// VIBE CODING: The time and a random number, formatted
+func newVibeGenerator(now func() time.Time) func() string {
+	/*
+	   The Unix time, so IDs sort by when they were made, then six
+	   random digits so two in the same second differ. It reads well,
+	   every ID has 16 digits, and it looks unique.
+
+	   But six digits are a million values, about 20 bits, for every
+	   ID made in the same second - and by the birthday paradox two of
+	   them are more likely than not to match after about 1,200. A
+	   service making thousands of IDs a second repeats itself all the
+	   time. math/rand is not meant to be unpredictable either, and
+	   the IDs only sort to the second: within it, they're random.
+	*/
+	return func() string {
+		return fmt.Sprintf("%d%06d", now().Unix(), mathrand.IntN(1_000_000))
+	}
+}
+
+// HUMAN CODING: A random UUID, version 4
+func newHumanGenerator(func() time.Time) func() string {
+	/*
+	   Sixteen bytes from crypto/rand, with the version and variant
+	   bits set as RFC 9562 says: 122 random bits. The chance that any
+	   two of a billion of them match is about one in 10¹⁹, so no
+	   check for repeats is needed, and nobody can guess the next ID
+	   from the last. Every language and database understands the
+	   format.
+
+	   It needs no clock and no state, so its IDs are in no order at
+	   all. Used as a database key, each insert lands somewhere random
+	   in the index instead of at its end. fmt.Sprintf formats the
+	   five groups, which costs more than the random bytes.
+	*/
+	return func() string {
+		var b [16]byte
+		rand.Read(b[:]) // It never returns an error, since Go 1.24
+
+		b[6] = b[6]&0x0f | 0x40 // Version 4
+		b[8] = b[8]&0x3f | 0x80 // Variant 10
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+	}
+}
+
+// EXPERT CODING: A monotonic ULID
+func newExpertGenerator(now func() time.Time) func() string {
+	/*
+	   A ULID is 48 bits of Unix milliseconds followed by 80 random
+	   bits, written as 26 characters of Crockford's base 32, whose
+	   alphabet is in ASCII order - so the IDs sort as text in the
+	   order they were made, and a database appends them to the end
+	   of its index.
+
+	   The random bits come from crypto/rand once per millisecond.
+	   Within the same millisecond, the generator adds one to them
+	   instead, so its IDs never repeat and always increase, even if
+	   the clock stops or steps back. A mutex keeps that true across
+	   goroutines, and the encoding is done by hand into a fixed
+	   array: one allocation per ID, for the string.
+
+	   The price is that the time of creation is in every ID, and
+	   that IDs from one millisecond are guessable from each other.
+	*/
+	g := &ulidGenerator{now: now}
+	return g.next
+}
+
+// ulidGenerator makes monotonic ULIDs.
+type ulidGenerator struct {
+	now func() time.Time
+
+	mu sync.Mutex
+	ms int64  // The time of the last ID, in Unix milliseconds
+	hi uint16 // The top 16 of its 80 random bits
+	lo uint64 // The other 64
+}
+
+// next returns a new ULID, greater than any g has returned before.
+func (g *ulidGenerator) next() string {
+	g.mu.Lock()
+	if ms := g.now().UnixMilli(); ms > g.ms {
+		var b [10]byte
+		rand.Read(b[:])
+		g.ms, g.hi, g.lo = ms, binary.BigEndian.Uint16(b[:2]), binary.BigEndian.Uint64(b[2:])
+	} else if g.lo++; g.lo == 0 {
+		if g.hi++; g.hi == 0 {
+			g.ms++ // 2⁸⁰ IDs in one millisecond: borrow the next
+		}
+	}
+	hi, lo := uint64(g.ms)<<16|uint64(g.hi), g.lo
+	g.mu.Unlock()
+	return encodeULID(hi, lo)
+}
+
+// crockford is Crockford's base 32 alphabet, without I, L, O and U.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// encodeULID writes the 128-bit number hi<<64 | lo in base 32, 5 bits to
+// a character, most significant first.
+func encodeULID(hi, lo uint64) string {
+	var s [26]byte
+	for i := len(s) - 1; i >= 0; i-- {
+		s[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(s[:])
+}
@@ -0,0 +1,361 @@
+// Package passwords compares three ways to store passwords: SHA-256 of
+// the password, SHA-256 with a random salt, and PBKDF2 tuned to a
+// target cost. Here slower is better: the results are judged by whether
+// each hash costs at least the target, since that is what every one of
+// an attacker's guesses costs too.
+package passwords
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// tiers lists the implementations in order. Each makes a hasher for a
+// target cost, which only Expert coding can honor.
+var tiers = []struct {
+	name, complexity string
+	newHasher        func(target time.Duration) hasher
+}{
+	{"Vibe coding", "SHA-256", func(time.Duration) hasher { return vibeHasher{} }},
+	{"Human coding", "salted SHA-256", func(time.Duration) hasher { return humanHasher{} }},
+	{"Expert coding", "PBKDF2, tuned", func(t time.Duration) hasher { return newExpertHasher(t) }},
+}
+
+// password is the password hashed and checked in the timings.
+const password = "correct horse battery staple"
+
+// commonPasswords are some of the passwords most often found in leaks:
+// the first an attacker tries.
+var commonPasswords = []string{
+	"123456", "password", "123456789", "12345678", "12345", "qwerty",
+	"1234567", "111111", "123123", "abc123", "iloveyou", "admin",
+	"welcome", "monkey", "dragon", "letmein", "football", "sunshine",
+}
+
+// searchSpaces are the sets of passwords an attacker might try, and how
+// many there are.
+var searchSpaces = []struct {
+	name  string
+	count float64
+}{
+	{"10,000 common passwords", 1e4},
+	{"8 lowercase letters", math.Pow(26, 8)},
+	{"8 letters and digits", math.Pow(62, 8)},
+}
+
+// guessTime is how long guessesPerSecond keeps guessing.
+const guessTime = 250 * time.Millisecond
+
+// guessesPerSecond returns how many wrong passwords h can check against
+// stored in a second, timed over at least guessTime.
+func guessesPerSecond(ctx context.Context, h hasher, stored string) (float64, error) {
+	guesses := 0
+	start := time.Now()
+	for time.Since(start) < guessTime {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		if h.Verify("guess"+strconv.Itoa(guesses), stored) {
+			return 0, fmt.Errorf("verification failed: guess %d was accepted", guesses)
+		}
+		guesses++
+	}
+	return float64(guesses) / time.Since(start).Seconds(), nil
+}
+
+// approx describes a number of seconds in the largest unit that keeps
+// it at least 1, e.g. "3.2 hours".
+func approx(seconds float64) string {
+	units := []struct {
+		name    string
+		seconds float64
+	}{
+		{"years", 365.25 * 24 * 3600},
+		{"days", 24 * 3600},
+		{"hours", 3600},
+		{"minutes", 60},
+		{"s", 1},
+		{"ms", 1e-3},
+		{"µs", 1e-6},
+	}
+	for _, u := range units {
+		if seconds >= u.seconds {
+			return fmt.Sprintf("%.1f %s", seconds/u.seconds, u.name)
+		}
+	}
+	return fmt.Sprintf("%.1f ns", seconds*1e9)
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "hex of SHA-256 of the password", Complexity: "SHA-256", Notes: []report.Note{
+			report.Strength("Never stores the password itself"),
+			report.Pitfall("Fast by design: millions of guesses a second per core"),
+			report.Pitfall("No salt: one table of common passwords' hashes cracks every user"),
+			report.Pitfall("Users with the same password get the same hash"),
+		}},
+		{Label: "Human coding", Approach: "a random 16-byte salt, then SHA-256", Complexity: "salted SHA-256", Notes: []report.Note{
+			report.Strength("Every hash is different: tables computed in advance are useless"),
+			report.Pitfall("Each guess still costs one SHA-256: weak passwords fall in milliseconds"),
+			report.Pitfall("== stops at the first difference, which can leak timing"),
+		}},
+		{Label: "Expert coding", Approach: "PBKDF2-HMAC-SHA256, iterations tuned to a target cost", Complexity: "PBKDF2, tuned", Notes: []report.Note{
+			report.Strength("Every guess costs the attacker what a login costs the server"),
+			report.Strength("The cost is stored in the hash, so it can be raised later"),
+			report.Strength("Compares in constant time"),
+			report.Pitfall("A login costs a tenth of a second of CPU: rate-limit the endpoint"),
+			report.Tip("Prefer Argon2id or bcrypt from golang.org/x/crypto where you can"),
+		}},
+	},
+	Takeaway: "For passwords, a fast hash is a broken hash. Salt every password, " +
+		"then make each hash slow on purpose - tuned to a target cost on your " +
+		"own hardware - so that every guess an attacker makes costs as much " +
+		"as a login.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "48-password-hashing",
+		Title:       "Password Hashing",
+		Description: "Store passwords as SHA-256, as salted SHA-256 and with PBKDF2 tuned to a target cost, where slower is better: judge each by the cost of a guess, not by speed.",
+		Category:    "security",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("48-password-hashing", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	target := fs.Duration("target", 100*time.Millisecond, "the least a hash should cost, e.g. 250ms")
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *target <= 0 {
+		return errors.New("-target must be positive")
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter, Target: *target}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Password Hashing", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Password Hashing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "Target: at least %s per hash. Slower is better here: every guess\n", *target)
+	fmt.Fprintln(w, "an attacker makes at a stolen hash costs one hash.")
+	fmt.Fprintf(w, "What each stores for %q:\n", password)
+	hashers := make([]hasher, len(tiers))
+	stored := make([]string, len(tiers))
+	for i, t := range tiers {
+		hashers[i] = t.newHasher(*target)
+		s, err := hashers[i].Hash(password)
+		if err != nil {
+			return err
+		}
+		if !hashers[i].Verify(password, s) {
+			return fmt.Errorf("verification failed: %s: the password doesn't match its own hash", t.name)
+		}
+		stored[i] = s
+		fmt.Fprintf(w, "  %-14s %s\n", t.name+":", s)
+	}
+
+	// Hashing a new password and checking a login each cost one hash;
+	// both are timed, one per run.
+	operations := []struct {
+		title, input string
+		run          func(h hasher, stored string) error
+	}{
+		{"Hashing a new password", "hash", func(h hasher, _ string) error {
+			_, err := h.Hash(password)
+			return err
+		}},
+		{"Checking a login", "verify", func(h hasher, stored string) error {
+			if !h.Verify(password, stored) {
+				return errors.New("the right password was rejected")
+			}
+			return nil
+		}},
+	}
+	for _, op := range operations {
+		fmt.Fprintf(out.Table, "\n%s, one per run:\n", op.title)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+		impls := make([]bench.Implementation, len(tiers))
+		for i, t := range tiers {
+			h, s := hashers[i], stored[i]
+			impls[i] = bench.Implementation{
+				Name: t.name, Complexity: t.complexity,
+				RunContext: func(ctx context.Context) error {
+					if err := op.run(h, s); err != nil {
+						return fmt.Errorf("%s: %w", t.name, err)
+					}
+					return ctx.Err()
+				},
+			}
+		}
+		results, err := bench.CompareContext(ctx, opts, impls...)
+		if err != nil {
+			return err
+		}
+		if err := csvLog.Append(op.input, 1, results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append(op.input, 1, results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		rep.Add(op.title, results)
+	}
+
+	// What the cost of a login means to someone who has stolen the
+	// hashes: each guess is one check. One run of a fast hash is mostly
+	// the harness's own overhead, so the guesses are timed in a loop.
+	fmt.Fprintln(out.Table)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(out.Table, "An attacker's time on one core, per user")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(out.Table, "\n  %-14s %14s", "", "guesses/s")
+	for _, space := range searchSpaces {
+		fmt.Fprintf(out.Table, " %24s", space.name)
+	}
+	fmt.Fprintln(out.Table)
+	for i, t := range tiers {
+		rate, err := guessesPerSecond(ctx, hashers[i], stored[i])
+		if err != nil {
+			return err
+		}
+		perGuess := 1 / rate
+		fmt.Fprintf(out.Table, "  %-14s %14.0f", t.name+":", rate)
+		cells := []string{fmt.Sprintf("%.0f guesses per second", rate)}
+		for _, space := range searchSpaces {
+			fmt.Fprintf(out.Table, " %24s", approx(space.count*perGuess))
+			cells = append(cells, fmt.Sprintf("%s in %s", space.name, approx(space.count*perGuess)))
+		}
+		fmt.Fprintln(out.Table)
+		rep.AddEdgeCase("Attacking one user, "+t.name, strings.Join(cells, "; "))
+	}
+	fmt.Fprintln(w, "\n  💡 Those are for one core. A GPU tries billions of SHA-256")
+	fmt.Fprintln(w, "     hashes a second, and without a salt one pass cracks every")
+	fmt.Fprintln(w, "     user at once. PBKDF2 is slow on a GPU too, but not as slow")
+	fmt.Fprintln(w, "     as memory-hard Argon2id or scrypt would be.")
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	// A table of common passwords' unsalted hashes, computed once, in
+	// advance: a rainbow table in miniature.
+	precomputed := make(map[string]string, len(commonPasswords))
+	for _, p := range commonPasswords {
+		sum := sha256.Sum256([]byte(p))
+		precomputed[hex.EncodeToString(sum[:])] = p
+	}
+
+	edgeCases := []struct {
+		desc, want string
+		check      func(h hasher) (bool, string)
+	}{
+		{"two users with the same password", "different stored hashes", func(h hasher) (bool, string) {
+			a, errA := h.Hash("sunshine")
+			b, errB := h.Hash("sunshine")
+			if err := errors.Join(errA, errB); err != nil {
+				return false, err.Error()
+			}
+			if a == b {
+				return false, "the same hash: one reveals the other"
+			}
+			return true, "different hashes"
+		}},
+		{"the right password", "accepted", func(h hasher) (bool, string) {
+			s, err := h.Hash("Tr0ub4dor&3")
+			if err != nil {
+				return false, err.Error()
+			}
+			if !h.Verify("Tr0ub4dor&3", s) {
+				return false, "rejected"
+			}
+			return true, "accepted"
+		}},
+		{"a password one letter off", "rejected", func(h hasher) (bool, string) {
+			s, err := h.Hash("Tr0ub4dor&3")
+			if err != nil {
+				return false, err.Error()
+			}
+			if h.Verify("Tr0ub4dor&4", s) {
+				return false, "accepted"
+			}
+			return true, "rejected"
+		}},
+		{`"password", looked up in a table of common passwords' hashes`, "not found", func(h hasher) (bool, string) {
+			s, err := h.Hash("password")
+			if err != nil {
+				return false, err.Error()
+			}
+			if p, ok := precomputed[s]; ok {
+				return false, fmt.Sprintf("found: %q, without a single guess", p)
+			}
+			return true, "not found: the salt makes every hash new"
+		}},
+	}
+	for _, tc := range edgeCases {
+		fmt.Fprintf(w, "%s (want: %s):\n", tc.desc, tc.want)
+		for i, t := range tiers {
+			good, result := tc.check(hashers[i])
+			status := "✅"
+			if !good {
+				status = "❌"
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
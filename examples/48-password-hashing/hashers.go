@@ -0,0 +1,156 @@
+package passwords
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// hasher turns a password into the string stored for it, and checks a
+// password against a stored string.
+type hasher interface {
+	Hash(password string) (string, error)
+	Verify(password, stored string) bool
+}
+
+// VIBE CODING: SHA-256 of the password
+type vibeHasher struct{}
+
+func (vibeHasher) Hash(password string) (string, error) {
+	/*
+	   SHA-256 is a cryptographic hash: it can't be reversed, so the
+	   password itself is never stored. One line, and it looks secure.
+
+	   But it was designed to be fast, and that is the problem. An
+	   attacker with the stored hashes doesn't reverse them, they
+	   guess: hash a candidate, compare, next - millions of guesses a
+	   second on one core, billions on a GPU. With no salt, the same
+	   password always gives the same hash, so one table of common
+	   passwords' hashes, computed once, cracks every user who picked
+	   one, and users who share a password share a hash.
+	*/
+	sum := sha256.Sum256([]byte(password))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (h vibeHasher) Verify(password, stored string) bool {
+	hash, _ := h.Hash(password)
+	return hash == stored
+}
+
+// saltSize is the length of a random salt, in bytes.
+const saltSize = 16
+
+// HUMAN CODING: A random salt, then SHA-256
+type humanHasher struct{}
+
+func (humanHasher) Hash(password string) (string, error) {
+	/*
+	   Sixteen random bytes of salt, stored next to the hash, make every
+	   user's hash different even for the same password. A table of
+	   common passwords' hashes computed in advance is now useless: the
+	   attacker has to start over for every salt, so for every user.
+
+	   But each guess still costs one SHA-256: a fraction of a
+	   microsecond. Salt stops attacking all users at once; it doesn't
+	   slow down attacking any one of them, and a user with a weak
+	   password is found in milliseconds. The == in Verify also stops
+	   at the first byte that differs, which can leak how close a guess
+	   was.
+	*/
+	salt := make([]byte, saltSize)
+	rand.Read(salt) // It never returns an error, since Go 1.24
+	sum := sha256.Sum256(append(salt, password...))
+	return hex.EncodeToString(salt) + "$" + hex.EncodeToString(sum[:]), nil
+}
+
+func (humanHasher) Verify(password, stored string) bool {
+	saltHex, _, _ := strings.Cut(stored, "$")
+	salt, err := hex.DecodeString(saltHex)
+	if err != nil {
+		return false
+	}
+	sum := sha256.Sum256(append(salt, password...))
+	return saltHex+"$"+hex.EncodeToString(sum[:]) == stored
+}
+
+// keySize is the length of the derived key PBKDF2 stores, in bytes.
+const keySize = 32
+
+// minIterations is the fewest iterations of PBKDF2-HMAC-SHA256 OWASP's
+// password storage guidance recommends.
+const minIterations = 600_000
+
+// EXPERT CODING: PBKDF2, tuned to a target cost
+type expertHasher struct {
+	iterations int
+}
+
+// newExpertHasher returns a hasher whose hashes take at least target
+// on this machine, and no fewer than minIterations.
+func newExpertHasher(target time.Duration) *expertHasher {
+	return &expertHasher{iterations: calibrate(target)}
+}
+
+func (h *expertHasher) Hash(password string) (string, error) {
+	/*
+	   A password hash should be slow on purpose. PBKDF2 runs HMAC-SHA256
+	   hundreds of thousands of times in a chain, so each guess costs
+	   the attacker what a login costs the server - a tenth of a second
+	   rather than a tenth of a microsecond. The count is chosen by
+	   timing this machine against a target cost, not by guessing, and
+	   stored in the hash with the salt, so it can be raised later
+	   without breaking the hashes already stored. Verify compares in
+	   constant time.
+
+	   bcrypt, scrypt and Argon2id are better still: scrypt and Argon2
+	   also need memory, which is what GPUs lack. They aren't in the
+	   standard library, though (golang.org/x/crypto has them), and
+	   PBKDF2 is, since Go 1.24 - and the tuning is the same idea.
+	*/
+	salt := make([]byte, saltSize)
+	rand.Read(salt)
+	key, err := pbkdf2.Key(sha256.New, password, salt, h.iterations, keySize)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("$pbkdf2-sha256$i=%d$%s$%s", h.iterations,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (h *expertHasher) Verify(password, stored string) bool {
+	var iterations int
+	fields := strings.Split(stored, "$")
+	if len(fields) != 5 || fields[1] != "pbkdf2-sha256" {
+		return false
+	}
+	if _, err := fmt.Sscanf(fields[2], "i=%d", &iterations); err != nil || iterations < 1 {
+		return false
+	}
+	salt, err1 := base64.RawStdEncoding.DecodeString(fields[3])
+	want, err2 := base64.RawStdEncoding.DecodeString(fields[4])
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(want))
+	return err == nil && subtle.ConstantTimeCompare(key, want) == 1
+}
+
+// calibrate returns how many iterations of PBKDF2-HMAC-SHA256 take at
+// least target here, with a quarter more so that timing noise doesn't
+// take a hash under it, rounded up to a multiple of 10,000 and no fewer
+// than minIterations.
+func calibrate(target time.Duration) int {
+	const probe = 50_000
+	start := time.Now()
+	pbkdf2.Key(sha256.New, "calibrate", make([]byte, saltSize), probe, keySize)
+	elapsed := time.Since(start)
+	needed := int(float64(target) / float64(max(elapsed, 1)) * probe * 1.25)
+	return max(minIterations, (needed+9_999)/10_000*10_000)
+}
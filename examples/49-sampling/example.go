@@ -0,0 +1,379 @@
+// Package sampling compares three ways to pick k items at random from a
+// stream: storing it all and shuffling, a partial Fisher-Yates shuffle
+// of the stored stream, and reservoir sampling, and how much memory
+// each needs.
+package sampling
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// query is one sampling query: k items from a stream of n.
+type query struct {
+	n, k int
+	seed input.Seed
+}
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	sample           func(stream iter.Seq[int], k int, rng *rand.Rand) []int
+}{
+	{"Vibe coding", "O(n) memory, twice", vibeSample},
+	{"Human coding", "O(n) memory", humanSample},
+	{"Expert coding", "O(k) memory", expertSample},
+}
+
+// count returns a stream of the numbers 0 to n-1, made as they are read,
+// like lines from a file or events from a queue: nothing holds them all.
+func count(n int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := range n {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+// samplers returns the implementations to compare. Each call draws from
+// a generator of its own, made from the seed.
+func samplers() []bench.Impl[query, []int] {
+	impls := make([]bench.Impl[query, []int], len(tiers))
+	for i, t := range tiers {
+		impls[i] = bench.Impl[query, []int]{
+			Name: t.name, Complexity: t.complexity,
+			Func: func(q query) []int { return t.sample(count(q.n), q.k, q.seed.Rand("sample", q.n)) },
+		}
+	}
+	return impls
+}
+
+// defaultK is the k the registry's Impls use.
+const defaultK = 100
+
+// impls returns the implementations timed sampling 100 of n items.
+func impls(n int) []bench.Implementation {
+	q := query{n: n, k: defaultK, seed: input.DefaultSeed}
+	var list []bench.Implementation
+	for _, s := range samplers() {
+		list = append(list, s.Implementation(q))
+	}
+	return list
+}
+
+// valid returns an equality function for CompareImpls that accepts any
+// sample of min(k, n) different items of a stream of n. Samples are
+// random, so there is no one right answer to compare with.
+func valid(n, k int) func(got, _ []int) error {
+	return func(got, _ []int) error {
+		if want := min(k, n); len(got) != want {
+			return fmt.Errorf("got %d items, want %d", len(got), want)
+		}
+		sorted := slices.Sorted(slices.Values(got))
+		for i, v := range sorted {
+			switch {
+			case v < 0 || v >= n:
+				return fmt.Errorf("got %d, which isn't in a stream of %d", v, n)
+			case i > 0 && v == sorted[i-1]:
+				return fmt.Errorf("got %d twice", v)
+			}
+		}
+		return nil
+	}
+}
+
+// countingSource is a rand.Source that counts the numbers drawn from it.
+type countingSource struct {
+	src   rand.Source
+	drawn int
+}
+
+func (c *countingSource) Uint64() uint64 {
+	c.drawn++
+	return c.src.Uint64()
+}
+
+// draws returns how many random numbers sample draws to pick k of n.
+func draws(sample func(iter.Seq[int], int, *rand.Rand) []int, n, k int, seed input.Seed) int {
+	src := &countingSource{src: seed.Rand("sample", n)}
+	sample(count(n), k, rand.New(src))
+	return src.drawn
+}
+
+// sampleSafely calls sample, returning what it panicked with, if it did.
+func sampleSafely(sample func(iter.Seq[int], int, *rand.Rand) []int, n, k int, rng *rand.Rand) (got []int, panicked any) {
+	defer func() { panicked = recover() }()
+	return sample(count(n), k, rng), nil
+}
+
+// chiSquare returns the chi-square statistic of how often each of n
+// items was picked, in trials samples of k of them, against the
+// trials·k/n times each should be.
+func chiSquare(sample func(iter.Seq[int], int, *rand.Rand) []int, n, k, trials int, rng *rand.Rand) float64 {
+	picked := make([]int, n)
+	for range trials {
+		for _, v := range sample(count(n), k, rng) {
+			picked[v]++
+		}
+	}
+	expected := float64(trials*k) / float64(n)
+	var x2 float64
+	for _, p := range picked {
+		d := float64(p) - expected
+		x2 += d * d / expected
+	}
+	return x2
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "collect the stream, rand.Perm its indexes, keep the first k", Complexity: "O(n) time, O(n) memory twice", Notes: []report.Note{
+			report.Strength("Short, and every item is equally likely"),
+			report.Pitfall("Holds the whole stream and a permutation as long, for a sample of k"),
+			report.Pitfall("Draws a random number for every item"),
+			report.Pitfall("Panics when the stream has fewer than k items"),
+		}},
+		{Label: "Human coding", Approach: "collect the stream, then k steps of Fisher-Yates", Complexity: "O(n) time, O(n) memory", Notes: []report.Note{
+			report.Strength("k random numbers, and no second array"),
+			report.Strength("Handles streams shorter than k"),
+			report.Pitfall("Still stores all n items, and can't answer before the stream ends"),
+		}},
+		{Label: "Expert coding", Approach: "a reservoir of k, with Algorithm L's skips", Complexity: "O(n) time, O(k) memory", Notes: []report.Note{
+			report.Strength("Holds k items whatever n is, and reads the stream once"),
+			report.Strength("About 3k·ln(n/k) random numbers, not n"),
+			report.Strength("Has a uniform sample of the stream so far at every point"),
+			report.Pitfall("Floating-point skips are harder to get right than a shuffle"),
+		}},
+	},
+	Takeaway: "To sample a stream, keep a reservoir: k items of memory " +
+		"however long the stream, one pass, and a valid sample whenever it " +
+		"stops. Storing the stream to shuffle it costs memory in n for an " +
+		"answer of size k, and doesn't work on a stream that doesn't end.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "49-sampling",
+		Title:       "Random Sampling",
+		Description: "Pick k items at random from a stream by storing and shuffling it, by a partial Fisher-Yates shuffle and by reservoir sampling, and compare the memory each needs.",
+		Category:    "algorithms",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    100_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("49-sampling", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{10_000, 1_000_000, 10_000_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of items in the stream, e.g. 1e5,1e7")
+	k := fs.Int("k", defaultK, "how many items to sample")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *k < 1 {
+		return fmt.Errorf("-k must be at least 1, not %d", *k)
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Random Sampling", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Random Sampling")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	impls := samplers()
+	memory := make([][]bench.Result, len(sizes))
+	for i, n := range sizes {
+		q := query{n: n, k: *k, seed: *seed}
+		fmt.Fprintf(out.Table, "\n%d items at random from a stream of %d (seed %d):\n", *k, n, *seed)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		timed := impls
+		if *k > n {
+			timed = impls[1:] // The vibe version panics
+		}
+		results, err := bench.CompareImpls(ctx, opts, q, nil, valid(n, *k), timed...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "✔ All implementations returned %d different items of the stream\n", min(*k, n))
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d, k = %d", n, *k), results)
+		memory[i] = results
+
+		if *k > n {
+			note := fmt.Sprintf("Vibe coding skipped: it panics with fewer than k=%d items", *k)
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// The memory each needs, from the bytes allocated per run.
+	fmt.Fprintln(out.Table)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(out.Table, "Memory allocated per sample of %d\n", *k)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(out.Table, "\n  %12s", "stream")
+	for _, t := range tiers {
+		fmt.Fprintf(out.Table, " %14s", t.name)
+	}
+	fmt.Fprintln(out.Table)
+	for i, n := range sizes {
+		fmt.Fprintf(out.Table, "  %12d", n)
+		cells := make([]string, 0, len(tiers))
+		for _, t := range tiers {
+			cell := "skipped"
+			if j := slices.IndexFunc(memory[i], func(r bench.Result) bool { return r.Name == t.name }); j >= 0 {
+				cell = bench.FormatBytes(memory[i][j].Bytes)
+			}
+			fmt.Fprintf(out.Table, " %14s", cell)
+			cells = append(cells, t.name+" "+cell)
+		}
+		fmt.Fprintln(out.Table)
+		rep.AddEdgeCase(fmt.Sprintf("Memory for %d of %d items", *k, n), strings.Join(cells, "; "))
+	}
+	fmt.Fprintln(w, "\n  💡 Collecting the stream costs its 8 bytes an item, plus the copies")
+	fmt.Fprintln(w, "     left behind as the slice doubles; rand.Perm adds 8 bytes an item")
+	fmt.Fprintln(w, "     more. The reservoir is k items whatever the stream's length.")
+
+	// How many random numbers each draws.
+	fmt.Fprintln(out.Table)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(out.Table, "Random numbers drawn per sample of %d\n", *k)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(out.Table, "\n  %12s", "stream")
+	for _, t := range tiers {
+		fmt.Fprintf(out.Table, " %14s", t.name)
+	}
+	fmt.Fprintf(out.Table, " %14s\n", "3k ln(n/k)")
+	for _, n := range sizes {
+		if *k > n {
+			continue
+		}
+		fmt.Fprintf(out.Table, "  %12d", n)
+		cells := make([]string, 0, len(tiers))
+		for _, t := range tiers {
+			d := draws(t.sample, n, *k, *seed)
+			fmt.Fprintf(out.Table, " %14d", d)
+			cells = append(cells, fmt.Sprintf("%s %d", t.name, d))
+		}
+		expected := 3 * float64(*k) * math.Log(float64(n)/float64(*k))
+		fmt.Fprintf(out.Table, " %14.0f\n", expected)
+		rep.AddEdgeCase(fmt.Sprintf("Random numbers drawn for %d of %d items", *k, n), strings.Join(cells, "; "))
+	}
+	fmt.Fprintln(w, "\n  💡 Item i gets into the reservoir with chance k/i, so about")
+	fmt.Fprintln(w, "     k·ln(n/k) of them ever do, and the reservoir draws three numbers")
+	fmt.Fprintln(w, "     for each: the slot, the new weight and the next skip. A stream")
+	fmt.Fprintln(w, "     ten times as long adds only about 3k·ln 10 of them.")
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	// Every item of 10 should be in 3 of every 10 samples of 3. 27.88 is
+	// the chi-square for 9 degrees of freedom that a fair sampler
+	// exceeds one time in a thousand.
+	const trials, limit = 30_000, 27.88
+	fmt.Fprintf(w, "%d samples of 3 of 10 items, how often each is picked (want: chi-square under %.2f):\n", trials, limit)
+	for _, t := range tiers {
+		x2 := chiSquare(t.sample, 10, 3, trials, seed.Rand("uniformity", trials))
+		status := "✅"
+		if x2 >= limit {
+			status = "❌"
+		}
+		result := fmt.Sprintf("chi-square %.2f", x2)
+		fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+		rep.AddEdgeCase(t.name+", uniformity", status+" "+result)
+	}
+
+	edgeCases := []struct {
+		desc string
+		n, k int
+		want []int
+	}{
+		{"k = 3 of a stream of 3", 3, 3, []int{0, 1, 2}},
+		{"k = 5 of a stream of 2", 2, 5, []int{0, 1}},
+		{"k = 3 of an empty stream", 0, 3, []int{}},
+		{"k = 0", 5, 0, []int{}},
+		{"k = 1 of a stream of 1", 1, 1, []int{0}},
+	}
+	for _, tc := range edgeCases {
+		fmt.Fprintf(w, "%s (want: %v, in any order):\n", tc.desc, tc.want)
+		for _, t := range tiers {
+			got, panicked := sampleSafely(t.sample, tc.n, tc.k, seed.Rand("edge", tc.n))
+			status, result := "✅", fmt.Sprint(got)
+			switch {
+			case panicked != nil:
+				status, result = "❌", fmt.Sprintf("panic: %v", panicked)
+			case bench.DiffSlices(slices.Sorted(slices.Values(got)), tc.want) != nil:
+				status = "❌"
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
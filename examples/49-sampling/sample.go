@@ -0,0 +1,98 @@
+package sampling
+
+import (
+	"iter"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// VIBE CODING: Store everything, shuffle, take the first k
+func vibeSample(stream iter.Seq[int], k int, rng *rand.Rand) []int {
+	/*
+	   Collect the stream, make a random permutation of its indexes
+	   with rand.Perm, and take the items at the first k of them.
+	   Every item is equally likely to be picked, and it's short.
+
+	   But it holds the whole stream, and rand.Perm builds a second
+	   slice as long, so a stream of n items costs two arrays of n
+	   ints - for a sample of k. It draws a random number for every
+	   item, though only k matter. It can't start until the stream has
+	   ended, so it can't sample one that doesn't end. And with fewer
+	   than k items, perm[i] runs off the end and panics.
+	*/
+	all := slices.Collect(stream)
+	perm := rng.Perm(len(all))
+	sample := make([]int, k)
+	for i := range sample {
+		sample[i] = all[perm[i]]
+	}
+	return sample
+}
+
+// HUMAN CODING: Store everything, then k steps of Fisher-Yates
+func humanSample(stream iter.Seq[int], k int, rng *rand.Rand) []int {
+	/*
+	   Fisher-Yates shuffles by swapping each position with a random
+	   one at or after it. The first k swaps are all it takes to put a
+	   uniform random sample in the first k positions, so it stops
+	   there: k random numbers instead of n, and no second array.
+
+	   It still collects the whole stream first - n ints for a sample
+	   of k, and a slice that grows by copying as it goes - and can't
+	   answer until the stream ends.
+	*/
+	all := slices.Collect(stream)
+	k = min(k, len(all))
+	for i := range k {
+		j := i + rng.IntN(len(all)-i)
+		all[i], all[j] = all[j], all[i]
+	}
+	return slices.Clone(all[:k])
+}
+
+// EXPERT CODING: Reservoir sampling, skipping ahead
+func expertSample(stream iter.Seq[int], k int, rng *rand.Rand) []int {
+	/*
+	   Keep a reservoir of k items: the first k, then each later item
+	   replaces a random one of them with the right probability - so
+	   at every point, the reservoir is a uniform sample of the stream
+	   so far. It holds k items, never n, reads the stream once, and
+	   has an answer whenever the stream stops.
+
+	   Deciding item by item costs a random number each (Algorithm R).
+	   Li's Algorithm L instead draws how many items to skip before the
+	   next one that gets in, from the distribution the item-by-item
+	   choices would have followed. On a long stream most items are
+	   skipped, so it needs about 3k·ln(n/k) random numbers in all.
+	*/
+	if k <= 0 {
+		return []int{}
+	}
+	reservoir := make([]int, 0, k)
+	w := math.Exp(math.Log(1-rng.Float64()) / float64(k))
+	next := k + skip(w, rng) // The index of the next item that gets in
+	i := 0
+	for v := range stream {
+		switch {
+		case i < k:
+			reservoir = append(reservoir, v)
+		case i == next:
+			reservoir[rng.IntN(k)] = v
+			w *= math.Exp(math.Log(1-rng.Float64()) / float64(k))
+			next += skip(w, rng) + 1
+		}
+		i++
+	}
+	return reservoir
+}
+
+// skip returns how many items Algorithm L passes over before the next
+// one it takes, for the weight w.
+func skip(w float64, rng *rand.Rand) int {
+	s := math.Floor(math.Log(1-rng.Float64()) / math.Log(1-w))
+	if !(s >= 0 && s < 1<<62) { // Not finite once w underflows to 0
+		return 1 << 62
+	}
+	return int(s)
+}
@@ -0,0 +1,419 @@
+// Package polymul compares three ways to multiply large polynomials:
+// the schoolbook method, Karatsuba and an FFT, each checked against the
+// product's values at random points, computed modulo large primes with
+// big.Int.
+package polymul
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// The schoolbook method above this size takes seconds per run.
+const maxVibeN = 20_000
+
+// query is one multiplication: the polynomials a and b, lowest power
+// first.
+type query struct {
+	a, b []int
+}
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	multiply         func(a, b []int) []int
+}{
+	{"Vibe coding", "O(n²)", vibeMultiply},
+	{"Human coding", "O(n^1.585)", humanMultiply},
+	{"Expert coding", "O(n log n)", expertMultiply},
+}
+
+// multipliersFor returns the implementations to compare on polynomials
+// of n coefficients, leaving out the schoolbook method where it would
+// take seconds.
+func multipliersFor(n int) []bench.Impl[query, []int] {
+	var impls []bench.Impl[query, []int]
+	for _, t := range tiers {
+		if t.name == "Vibe coding" && n > maxVibeN {
+			continue
+		}
+		impls = append(impls, bench.Impl[query, []int]{
+			Name: t.name, Complexity: t.complexity,
+			Func: func(q query) []int { return t.multiply(q.a, q.b) },
+		})
+	}
+	return impls
+}
+
+// defaultLimit bounds the coefficients: three decimal digits, as when
+// multiplying big numbers stored in base 1,000.
+const defaultLimit = 1_000
+
+// impls returns the implementations timed multiplying two polynomials
+// of n coefficients below 1,000.
+func impls(n int) []bench.Implementation {
+	q := polynomials(input.DefaultSeed, n, defaultLimit)
+	var list []bench.Implementation
+	for _, m := range multipliersFor(n) {
+		list = append(list, m.Implementation(q))
+	}
+	return list
+}
+
+// polynomials returns two polynomials of n random coefficients in
+// [0, limit).
+func polynomials(seed input.Seed, n, limit int) query {
+	return query{a: seed.Ints("a", n, limit), b: seed.Ints("b", n, limit)}
+}
+
+// moduli are the Mersenne primes 2⁶¹-1 and 2¹²⁷-1, which products are
+// checked modulo.
+var moduli = []*big.Int{
+	new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 61), big.NewInt(1)),
+	new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1)),
+}
+
+// point is where a product is checked: at x, modulo m.
+type point struct {
+	x, m *big.Int
+}
+
+// points returns a random point below each of the moduli.
+func points(seed input.Seed) []point {
+	rng := seed.Rand("points", 0)
+	pts := make([]point, len(moduli))
+	for i, m := range moduli {
+		x := new(big.Int).SetUint64(rng.Uint64())
+		x.Lsh(x, 64).Or(x, new(big.Int).SetUint64(rng.Uint64()))
+		pts[i] = point{x: x.Mod(x, m), m: m}
+	}
+	return pts
+}
+
+// residue returns p(x) mod m, by Horner's rule.
+func residue(p []int, x, m *big.Int) *big.Int {
+	r, c := new(big.Int), new(big.Int)
+	for _, v := range slices.Backward(p) {
+		r.Mul(r, x)
+		r.Add(r, c.SetInt64(int64(v)))
+		r.Mod(r, m)
+	}
+	return r
+}
+
+// checked returns an equality function for CompareImpls that accepts a
+// product of q's polynomials if it has the right length and, at every
+// point, p(x) = a(x)·b(x) modulo m. Two different polynomials of degree
+// d agree at a random point with a chance of at most d/m - for these
+// moduli, never in practice - so no exact product is needed to check
+// against, and none has to be computed the slow way.
+func checked(q query, pts []point) func(got, _ []int) error {
+	want := make([]*big.Int, len(pts))
+	for i, p := range pts {
+		want[i] = residue(q.a, p.x, p.m)
+		want[i].Mul(want[i], residue(q.b, p.x, p.m)).Mod(want[i], p.m)
+	}
+	return func(got, _ []int) error {
+		if n := productLen(q.a, q.b); len(got) != n {
+			return fmt.Errorf("got %d coefficients, want %d", len(got), n)
+		}
+		for i, p := range pts {
+			if r := residue(got, p.x, p.m); r.Cmp(want[i]) != 0 {
+				return fmt.Errorf("p(x) mod %d is %d, but a(x)·b(x) mod %[1]d is %[3]d", p.m, r, want[i])
+			}
+		}
+		return nil
+	}
+}
+
+// exact returns the product of a and b computed with big.Int, the
+// reference the edge cases are checked against.
+func exact(a, b []int) []int {
+	product := make([]*big.Int, productLen(a, b))
+	for i := range product {
+		product[i] = new(big.Int)
+	}
+	var term big.Int
+	for i, x := range a {
+		for j, y := range b {
+			product[i+j].Add(product[i+j], term.Mul(big.NewInt(int64(x)), big.NewInt(int64(y))))
+		}
+	}
+	ints := make([]int, len(product))
+	for i, c := range product {
+		ints[i] = int(c.Int64())
+	}
+	return ints
+}
+
+// show returns p as a list, or its length and ends if it is long.
+func show(p []int) string {
+	if len(p) <= 8 {
+		return fmt.Sprint(p)
+	}
+	return fmt.Sprintf("[%d %d ... %d %d] (%d coefficients)", p[0], p[1], p[len(p)-2], p[len(p)-1], len(p))
+}
+
+// multiplySafely calls multiply, returning what it panicked with, if it
+// did.
+func multiplySafely(multiply func(a, b []int) []int, a, b []int) (product []int, panicked any) {
+	defer func() { panicked = recover() }()
+	return multiply(a, b), nil
+}
+
+// repeat returns n copies of v.
+func repeat(v, n int) []int {
+	return slices.Repeat([]int{v}, n)
+}
+
+// alternating returns n coefficients of v, -v, v, ...
+func alternating(v, n int) []int {
+	a := make([]int, n)
+	for i := range a {
+		a[i] = v
+		if i%2 == 1 {
+			a[i] = -v
+		}
+	}
+	return a
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "every coefficient by every coefficient", Complexity: "O(n²)", Notes: []report.Note{
+			report.Strength("Exact, obvious, and the fastest for a few dozen coefficients"),
+			report.Pitfall("Ten times the coefficients is a hundred times the work"),
+		}},
+		{Label: "Human coding", Approach: "Karatsuba: three half-size products instead of four", Complexity: "O(n^1.585)", Notes: []report.Note{
+			report.Strength("Exact, in integers, and subquadratic"),
+			report.Strength("Multiplies the schoolbook way below 32 coefficients, where that is faster"),
+			report.Pitfall("Allocates the sums and products at every level of the recursion"),
+			report.Pitfall("Pads the shorter polynomial to the longer one's length"),
+		}},
+		{Label: "Expert coding", Approach: "one complex FFT of both, squared, with a float64 precision guard", Complexity: "O(n log n)", Notes: []report.Note{
+			report.Strength("Fastest from a few hundred coefficients, and by far at 100,000"),
+			report.Strength("Splits coefficients too large to round back exactly"),
+			report.Pitfall("float64 rounding is a real limit: without the guard, large coefficients come back wrong"),
+			report.Pitfall("Each split triples the number of transforms"),
+		}},
+	},
+	Takeaway: "Multiplying polynomials is convolution, and the FFT does it " +
+		"in O(n log n) - but in floating point, so know how many bits it " +
+		"can carry and split what doesn't fit. Check a fast product " +
+		"without a slow one: at a random point, modulo a large prime, a " +
+		"wrong product almost never agrees with a(x)·b(x).",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "50-polynomial-multiplication",
+		Title:       "Polynomial Multiplication",
+		Description: "Multiply large polynomials the schoolbook way, with Karatsuba and with an iterative FFT, checking every product at random points modulo large primes with big.Int.",
+		Category:    "algorithms",
+		Difficulty:  examples.Advanced,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    10_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("50-polynomial-multiplication", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{1_000, 10_000, 100_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of coefficients in each polynomial, e.g. 1e4,1e6")
+	limit := fs.Int("limit", defaultLimit, "coefficients are random below this")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *limit < 1 {
+		return fmt.Errorf("-limit must be at least 1, not %d", *limit)
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Polynomial Multiplication", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Polynomial Multiplication")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	pts := points(*seed)
+	for _, n := range sizes {
+		q := polynomials(*seed, n, *limit)
+		fmt.Fprintf(out.Table, "\nTwo polynomials of %d coefficients below %d (seed %d):\n", n, *limit, *seed)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		results, err := bench.CompareImpls(ctx, opts, q, nil, checked(q, pts), multipliersFor(n)...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "✔ All implementations agree with a(x)·b(x) at a random x, modulo 2^61-1 and 2^127-1")
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d", n), results)
+
+		if n > maxVibeN {
+			note := fmt.Sprintf("Vibe coding skipped: O(n²) is impractical above n=%d", maxVibeN)
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Where each method starts to pay for itself.
+	fmt.Fprintln(out.Table)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(out.Table, "Small polynomials")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	var small []labelled
+	for _, n := range []int{16, 64, 256, 1_024, 4_096} {
+		small = append(small, labelled{fmt.Sprintf("n = %d", n), polynomials(*seed, n, *limit)})
+	}
+	if err := compareTable(ctx, out, opts, rep, pts, "size", small); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\n  💡 Below %d coefficients Karatsuba multiplies the schoolbook way,\n", karatsubaCutoff)
+	fmt.Fprintf(w, "     and up to %d the FFT version does. Both pay for themselves from\n", schoolbookMax)
+	fmt.Fprintln(w, "     a few hundred coefficients on, and the gap grows with n: n^1.585")
+	fmt.Fprintln(w, "     and n log n against n².")
+
+	// The FFT's float64 precision: larger coefficients need splitting.
+	const bitsN = 10_000
+	fmt.Fprintln(out.Table)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(out.Table, "Larger coefficients, with n = %d\n", bitsN)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	var wide []labelled
+	for _, b := range []int{8, 16, 24} {
+		wide = append(wide, labelled{fmt.Sprintf("below 2^%d", b), polynomials(*seed, bitsN, 1<<b)})
+	}
+	if err := compareTable(ctx, out, opts, rep, pts, "coefficients", wide); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\n  💡 A float64 FFT rounds the product back to integers correctly only\n")
+	fmt.Fprintf(w, "     while it needs at most about %d bits here. Past that, the expert\n", fftBits)
+	fmt.Fprintln(w, "     version splits every coefficient into high and low bits, and")
+	fmt.Fprintln(w, "     multiplies three times, with half the bits each, instead of once.")
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		desc string
+		a, b []int
+	}{
+		{"(1 + x)(1 - x)", []int{1, 1}, []int{1, -1}},
+		{"(1 + 2x + 3x²) times 3", []int{1, 2, 3}, []int{3}},
+		{"two empty polynomials", []int{}, []int{}},
+		{"empty times (1 + x)", []int{}, []int{1, 1}},
+		{"1,000 coefficients times 100", repeat(7, 1_000), repeat(9, 100)},
+		{"300 coefficients of 2^24-1, squared", repeat(1<<24-1, 300), repeat(1<<24-1, 300)},
+		{"300 of ±2^20 times 300 of 2^20", alternating(1<<20, 300), repeat(1<<20, 300)},
+	}
+	for _, tc := range edgeCases {
+		want := exact(tc.a, tc.b)
+		fmt.Fprintf(w, "%s (want: %s):\n", tc.desc, show(want))
+		for _, t := range tiers {
+			got, panicked := multiplySafely(t.multiply, tc.a, tc.b)
+			status, result := "✅", show(got)
+			switch {
+			case panicked != nil:
+				status, result = "❌", fmt.Sprintf("panic: %v", panicked)
+			case bench.DiffSlices(got, want) != nil:
+				status, result = "❌", fmt.Sprintf("%s: %v", result, bench.DiffSlices(got, want))
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
+
+// labelled is a query with a label for its row of a table.
+type labelled struct {
+	label string
+	query query
+}
+
+// compareTable times every implementation on each query, checked at
+// pts, and prints a row per query with the median time of each.
+func compareTable(ctx context.Context, out examples.Output, opts bench.Options, rep *report.Report, pts []point, heading string, queries []labelled) error {
+	fmt.Fprintf(out.Table, "\n  %-14s", heading)
+	for _, t := range tiers {
+		fmt.Fprintf(out.Table, " %14s", t.name)
+	}
+	fmt.Fprintln(out.Table)
+	for _, l := range queries {
+		q := l.query
+		results, err := bench.CompareImpls(ctx, opts, q, nil, checked(q, pts), multipliersFor(len(q.a))...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out.Table, "  %-14s", l.label)
+		for _, t := range tiers {
+			if i := slices.IndexFunc(results, func(r bench.Result) bool { return r.Name == t.name }); i >= 0 {
+				fmt.Fprintf(out.Table, " %12.3fms", results[i].Milliseconds())
+			} else {
+				fmt.Fprintf(out.Table, " %14s", "skipped")
+			}
+		}
+		fmt.Fprintln(out.Table)
+		rep.Add(fmt.Sprintf("%s, n = %d", l.label, len(q.a)), results)
+	}
+	return nil
+}
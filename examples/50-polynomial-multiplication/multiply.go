@@ -0,0 +1,256 @@
+package polymul
+
+import (
+	"math"
+	"math/bits"
+	"math/cmplx"
+)
+
+// VIBE CODING: Schoolbook, every coefficient by every coefficient
+func vibeMultiply(a, b []int) []int {
+	/*
+	   Multiply every coefficient of a by every coefficient of b and add
+	   it in at the sum of their powers - long multiplication, as taught
+	   in school. It is exact and easy to check, and for a few dozen
+	   coefficients nothing is faster.
+
+	   But it is n² multiplications: ten times the coefficients is a
+	   hundred times the work, and two polynomials of a million
+	   coefficients take a trillion.
+	*/
+	product := make([]int, productLen(a, b))
+	for i, x := range a {
+		for j, y := range b {
+			product[i+j] += x * y
+		}
+	}
+	return product
+}
+
+// productLen returns the number of coefficients in the product of a and
+// b. An empty polynomial has none, and neither does its product.
+func productLen(a, b []int) int {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	return len(a) + len(b) - 1
+}
+
+// HUMAN CODING: Karatsuba, three half-size products instead of four
+func humanMultiply(a, b []int) []int {
+	/*
+	   Split each polynomial into a low and a high half: a = a0 + a1·xʰ.
+	   The product needs a0·b0, a1·b1 and the cross terms a0·b1 + a1·b0,
+	   and Karatsuba gets the cross terms from one more product,
+	   (a0+a1)(b0+b1), minus the other two. Three half-size products
+	   instead of four, all the way down, is O(n^1.585), and below
+	   karatsubaCutoff coefficients it multiplies the schoolbook way,
+	   which is faster there.
+
+	   It is exact, in integers, but it allocates the sums and the
+	   products at every level, and pads the shorter polynomial with
+	   zeros to the longer one's length: a million coefficients times a
+	   thousand costs as much as a million times a million. And n^1.585
+	   is still far from n log n.
+	*/
+	if len(a) == 0 || len(b) == 0 {
+		return []int{}
+	}
+	n := max(len(a), len(b))
+	product := karatsuba(pad(a, n), pad(b, n))
+	return product[:productLen(a, b)]
+}
+
+// karatsubaCutoff is the length below which karatsuba multiplies the
+// schoolbook way.
+const karatsubaCutoff = 32
+
+// karatsuba returns the product of a and b, which have the same length.
+func karatsuba(a, b []int) []int {
+	n := len(a)
+	if n < karatsubaCutoff {
+		return vibeMultiply(a, b)
+	}
+	h := n / 2
+	a0, a1 := a[:h], a[h:]
+	b0, b1 := b[:h], b[h:]
+	z0 := karatsuba(a0, b0)
+	z2 := karatsuba(a1, b1)
+	z1 := karatsuba(add(a0, a1), add(b0, b1))
+	product := make([]int, 2*n-1)
+	for i, v := range z0 {
+		product[i] += v
+		z1[i] -= v
+	}
+	for i, v := range z2 {
+		product[i+2*h] += v
+		z1[i] -= v
+	}
+	for i, v := range z1 {
+		product[i+h] += v
+	}
+	return product
+}
+
+// pad returns a with zeros appended to length n.
+func pad(a []int, n int) []int {
+	padded := make([]int, n)
+	copy(padded, a)
+	return padded
+}
+
+// add returns x + y, where y is at least as long as x.
+func add(x, y []int) []int {
+	sum := make([]int, len(y))
+	copy(sum, y)
+	for i, v := range x {
+		sum[i] += v
+	}
+	return sum
+}
+
+// schoolbookMax is the length at or under which the expert version
+// multiplies the schoolbook way: an FFT doesn't pay for itself sooner.
+const schoolbookMax = 64
+
+// fftBits is how many bits a product's coefficients may need, counting
+// the bits of the largest coefficient of each polynomial and of the
+// shorter length, for a float64 FFT to round every one of them to the
+// right integer. Measured with every coefficient at its largest, 42 bits
+// leave a million-coefficient product within 0.003 of the integers; 0.5
+// would round the wrong way.
+const fftBits = 42
+
+// EXPERT CODING: An FFT, splitting coefficients too large for float64
+func expertMultiply(a, b []int) []int {
+	/*
+	   A polynomial of degree below m is fixed by its values at m
+	   points, and at the same points the product's values are just the
+	   products of the values. The FFT evaluates at the m-th roots of
+	   unity in O(m log m), so: transform, multiply pointwise, transform
+	   back, round - O(n log n) in all.
+
+	   Both polynomials go into one complex transform, a as the real
+	   part and b as the imaginary part. Its square is a² - b² + 2ab·i,
+	   so half the imaginary part of the inverse is a·b: two transforms
+	   instead of three. The transform is iterative and in place, with
+	   every root of unity computed directly by Sincos rather than by
+	   repeated multiplication, which would compound the rounding.
+
+	   float64 carries 53 bits, so the FFT is exact only while the
+	   product's coefficients are small enough to round back correctly.
+	   When they might not be, it splits every coefficient into high and
+	   low bits, and multiplies those Karatsuba's way, each with half the
+	   bits. Below schoolbookMax coefficients it doesn't transform at all.
+	*/
+	if len(a) == 0 || len(b) == 0 {
+		return []int{}
+	}
+	if min(len(a), len(b)) <= schoolbookMax {
+		return vibeMultiply(a, b)
+	}
+	bitsA, bitsB := maxBits(a), maxBits(b)
+	if bitsA+bitsB+bits.Len(uint(min(len(a), len(b)))) > fftBits {
+		return splitMultiply(a, b, (max(bitsA, bitsB)+1)/2)
+	}
+
+	m := 1 << bits.Len(uint(len(a)+len(b)-2))
+	values := make([]complex128, m)
+	for i, v := range a {
+		values[i] = complex(float64(v), 0)
+	}
+	for i, v := range b {
+		values[i] += complex(0, float64(v))
+	}
+	roots := rootsOfUnity(m)
+	fft(values, roots)
+	for i, v := range values {
+		values[i] = v * v
+	}
+	for i, w := range roots {
+		roots[i] = cmplx.Conj(w)
+	}
+	fft(values, roots) // The inverse transform, unscaled
+	product := make([]int, len(a)+len(b)-1)
+	for i := range product {
+		product[i] = int(math.Round(imag(values[i]) / float64(2*m)))
+	}
+	return product
+}
+
+// splitMultiply returns the product of a and b by splitting their
+// coefficients at bit s, into a = hi·2ˢ + lo, and multiplying the halves
+// Karatsuba's way: three products with about half the bits each.
+func splitMultiply(a, b []int, s int) []int {
+	aHi, aLo := split(a, s)
+	bHi, bLo := split(b, s)
+	hh := expertMultiply(aHi, bHi)
+	ll := expertMultiply(aLo, bLo)
+	mid := expertMultiply(add(aHi, aLo), add(bHi, bLo))
+	product := make([]int, len(a)+len(b)-1)
+	for i := range product {
+		product[i] = hh[i]<<(2*s) + (mid[i]-hh[i]-ll[i])<<s + ll[i]
+	}
+	return product
+}
+
+// split returns the coefficients of a above bit s and below it.
+func split(a []int, s int) (hi, lo []int) {
+	hi, lo = make([]int, len(a)), make([]int, len(a))
+	for i, v := range a {
+		hi[i], lo[i] = v>>s, v&(1<<s-1)
+	}
+	return hi, lo
+}
+
+// maxBits returns the bits needed for the largest magnitude in a.
+func maxBits(a []int) int {
+	var largest uint
+	for _, v := range a {
+		largest = max(largest, uint(abs(v)))
+	}
+	return bits.Len(largest)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// rootsOfUnity returns e^(-2πik/m) for k below m/2.
+func rootsOfUnity(m int) []complex128 {
+	roots := make([]complex128, m/2)
+	for k := range roots {
+		sin, cos := math.Sincos(-2 * math.Pi * float64(k) / float64(m))
+		roots[k] = complex(cos, sin)
+	}
+	return roots
+}
+
+// fft replaces a, whose length m is a power of two, with its discrete
+// Fourier transform, using roots from rootsOfUnity(m) - or their
+// conjugates, for the inverse transform without the division by m.
+func fft(a []complex128, roots []complex128) {
+	m := len(a)
+	for i, j := 1, 0; i < m; i++ { // Put a in bit-reversed order
+		bit := m >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+	for size := 2; size <= m; size <<= 1 {
+		half, step := size/2, m/size
+		for start := 0; start < m; start += size {
+			for k := range half {
+				w := roots[k*step] * a[start+k+half]
+				a[start+k], a[start+k+half] = a[start+k]+w, a[start+k]-w
+			}
+		}
+	}
+}
@@ -0,0 +1,396 @@
+// Package gcd compares three ways to find greatest common divisors:
+// repeated subtraction, Euclid's remainders and Stein's binary
+// algorithm, all from package numtheory, and two ways to use them for
+// modular inverses.
+package gcd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/numtheory"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// Repeated subtraction manages a few hundred million steps a second, so
+// past this many in all it is reported as skipped.
+const maxVibeSteps = 1_000_000_000
+
+// prime is the Mersenne prime 2^61-1, which inverses are timed modulo.
+const prime = 1<<61 - 1
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	gcd              func(a, b uint64) uint64
+	gcds             func(pairs []pair) []uint64
+}{
+	{"Vibe coding", "O(a/b) subtractions", numtheory.SubtractionGCD, vibeGCDs},
+	{"Human coding", "O(log n) divisions", numtheory.EuclidGCD, humanGCDs},
+	{"Expert coding", "O(log n) shifts", numtheory.BinaryGCD, expertGCDs},
+}
+
+// subtractions returns how many subtractions repeated subtraction would
+// take for all of pairs, stopping at the first total over maxVibeSteps.
+func subtractions(pairs []pair) uint64 {
+	var total uint64
+	for _, p := range pairs {
+		total += min(numtheory.SubtractionSteps(p.a, p.b), maxVibeSteps+1)
+		if total > maxVibeSteps {
+			break
+		}
+	}
+	return total
+}
+
+// gcdsFor returns the implementations to compare on pairs, leaving out
+// repeated subtraction where it would take too long.
+func gcdsFor(pairs []pair) []bench.Impl[[]pair, []uint64] {
+	var impls []bench.Impl[[]pair, []uint64]
+	for _, t := range tiers {
+		if t.name == "Vibe coding" && subtractions(pairs) > maxVibeSteps {
+			continue
+		}
+		impls = append(impls, bench.Impl[[]pair, []uint64]{
+			Name: t.name, Complexity: t.complexity, Func: t.gcds,
+		})
+	}
+	return impls
+}
+
+// impls returns the implementations timed finding the GCDs of n random
+// pairs of uint64s.
+func impls(n int) []bench.Implementation {
+	pairs := randomPairs(input.DefaultSeed, n, 64)
+	var list []bench.Implementation
+	for _, impl := range gcdsFor(pairs) {
+		list = append(list, impl.Implementation(pairs))
+	}
+	return list
+}
+
+// randomPairs returns n pairs of a random uint64 and a random number
+// below 2^bits.
+func randomPairs(seed input.Seed, n, bits int) []pair {
+	rng := seed.Rand("pairs", n)
+	pairs := make([]pair, n)
+	for i := range pairs {
+		pairs[i] = pair{rng.Uint64(), rng.Uint64() >> (64 - bits)}
+	}
+	return pairs
+}
+
+// Consecutive Fibonacci numbers, the pair below 2^64 that takes Euclid's
+// algorithm the most steps.
+const (
+	fib92 = 7_540_113_804_746_346_429
+	fib93 = 12_200_160_415_121_876_738
+)
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "subtract the smaller from the larger until they are equal", Complexity: "O(a/b) subtractions", Notes: []report.Note{
+			report.Strength("Right by a one-line argument, and no division anywhere"),
+			report.Strength("Only a few times slower than Euclid on random pairs of similar size"),
+			report.Pitfall("A large number and a small one take a/b steps: gcd(2^63, 3) would take 3·10^18"),
+		}},
+		{Label: "Human coding", Approach: "Euclid: replace the larger by the remainder", Complexity: "O(log n) divisions", Notes: []report.Note{
+			report.Strength("At most 92 steps for any two uint64s, whatever their sizes"),
+			report.Strength("Carrying the coefficients along gives modular inverses"),
+			report.Pitfall("Every step is a 64-bit division, the slowest integer instruction"),
+		}},
+		{Label: "Expert coding", Approach: "Stein: shift out factors of 2, subtract odd from odd", Complexity: "O(log n) shifts", Notes: []report.Note{
+			report.Strength("Only shifts, subtractions and comparisons, each a cycle or so"),
+			report.Strength("The fastest on random 64-bit pairs"),
+			report.Pitfall("More steps than Euclid, and an easy algorithm to get subtly wrong"),
+			report.Pitfall("Loses to Euclid when one number is far smaller: it shifts through the difference a bit at a time"),
+		}},
+	},
+	Takeaway: "Euclid's remainder step turns a/b subtractions into one " +
+		"division, and Stein's algorithm turns divisions into shifts. " +
+		"Extended Euclid also divides modulo any m, and says when it " +
+		"can't; Fermat's a^(m-2) is slower, and only right for a prime " +
+		"m: otherwise it gives a wrong answer, silently.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "51-gcd",
+		Title:       "GCD and Modular Inverses",
+		Description: "Find greatest common divisors by repeated subtraction, Euclid's algorithm and Stein's binary algorithm from a shared numtheory package, and compare extended Euclid with Fermat for modular inverses.",
+		Category:    "number theory",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    10_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("51-gcd", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{1_000, 10_000, 100_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of pairs, e.g. 1e4,1e6")
+	bitsFlag := fs.Int("bits", 64, "the second number of each pair is random below 2^bits, from 1 to 64")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *bitsFlag < 1 || *bitsFlag > 64 {
+		return fmt.Errorf("-bits must be from 1 to 64, not %d", *bitsFlag)
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("GCD and Modular Inverses", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: GCD and Modular Inverses")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	for _, n := range sizes {
+		pairs := randomPairs(*seed, n, *bitsFlag)
+		fmt.Fprintf(out.Table, "\n%d pairs of a random uint64 and a number below 2^%d (seed %d):\n", n, *bitsFlag, *seed)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+
+		results, err := bench.CompareImpls(ctx, opts, pairs, bigGCDs(pairs), bench.DiffSlices, gcdsFor(pairs)...)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "✔ All implementations agree with math/big")
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		section := rep.Add(fmt.Sprintf("n = %d", n), results)
+
+		if subtractions(pairs) > maxVibeSteps {
+			note := fmt.Sprintf("Vibe coding skipped: these pairs would take over %d subtractions", maxVibeSteps)
+			fmt.Fprintln(w, "  ⏭️  "+note)
+			section.Notes = append(section.Notes, note)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// How many steps each algorithm takes on pairs worth knowing.
+	fmt.Fprintln(out.Table)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(out.Table, "Steps to the answer")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(out.Table, "\n  %-22s %20s %10s %10s\n", "pair", "subtractions", "divisions", "binary")
+	random := randomPairs(*seed, 1, 64)[0]
+	for _, s := range []struct {
+		label string
+		a, b  uint64
+	}{
+		{"1071, 462", 1071, 462},
+		{"F93, F92 (Fibonacci)", fib93, fib92},
+		{"random 64-bit pair", random.a, random.b},
+		{"2^63 + 1, 2^32 + 1", 1<<63 + 1, 1<<32 + 1},
+		{"2^63, 3", 1 << 63, 3},
+	} {
+		fmt.Fprintf(out.Table, "  %-22s %20d %10d %10d\n", s.label,
+			numtheory.SubtractionSteps(s.a, s.b), euclidSteps(s.a, s.b), binarySteps(s.a, s.b))
+	}
+	fmt.Fprintln(w, "\n  💡 Euclid's worst case is consecutive Fibonacci numbers, where every")
+	fmt.Fprintln(w, "     quotient is 1, so subtraction is no slower. Its best case is a")
+	fmt.Fprintln(w, "     lopsided pair, where one division replaces billions of subtractions.")
+	fmt.Fprintln(w, "     Stein's algorithm takes more steps than Euclid, but cheaper ones.")
+
+	// Lopsided pairs: where repeated subtraction falls over.
+	const lopsidedN = 1_000
+	fmt.Fprintln(out.Table)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(out.Table, "Lopsided pairs, with n = %d\n", lopsidedN)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(out.Table, "\n  %-14s", "second number")
+	for _, t := range tiers {
+		fmt.Fprintf(out.Table, " %14s", t.name)
+	}
+	fmt.Fprintln(out.Table)
+	for _, b := range []int{64, 56, 48, 40} {
+		pairs := randomPairs(*seed, lopsidedN, b)
+		results, err := bench.CompareImpls(ctx, opts, pairs, bigGCDs(pairs), bench.DiffSlices, gcdsFor(pairs)...)
+		if err != nil {
+			return err
+		}
+		label := fmt.Sprintf("below 2^%d", b)
+		fmt.Fprintf(out.Table, "  %-14s", label)
+		for _, t := range tiers {
+			if r, ok := find(results, t.name); ok {
+				fmt.Fprintf(out.Table, " %12.3fms", r.Milliseconds())
+			} else {
+				fmt.Fprintf(out.Table, " %14s", "skipped")
+			}
+		}
+		fmt.Fprintln(out.Table)
+		rep.Add(fmt.Sprintf("%s, n = %d", label, lopsidedN), results)
+	}
+	fmt.Fprintln(w, "\n  💡 Every 8 bits between the two sizes is 256 times as many")
+	fmt.Fprintln(w, "     subtractions. Euclid gets faster instead: one division takes off")
+	fmt.Fprintln(w, "     the whole difference in size. Stein takes it off a bit or so per")
+	fmt.Fprintln(w, "     step, so Euclid catches up, and with -bits 16 it wins.")
+
+	// Modular inverses: extended Euclid against Fermat's little theorem.
+	const inverseN = 10_000
+	fmt.Fprintln(out.Table)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(out.Table, "Inverses of %d random numbers modulo 2^61-1\n", inverseN)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	values := make([]uint64, inverseN)
+	rng := seed.Rand("inverses", inverseN)
+	for i := range values {
+		values[i] = 1 + rng.Uint64N(prime-1)
+	}
+	inverseImpls := make([]bench.Impl[[]uint64, []uint64], len(inverseMethods))
+	for i, m := range inverseMethods {
+		inverseImpls[i] = bench.Impl[[]uint64, []uint64]{
+			Name: m.name, Complexity: m.complexity,
+			Func: func(values []uint64) []uint64 { return inverses(m.inv, values, prime) },
+		}
+	}
+	results, err := bench.CompareImpls(ctx, opts, values, inverses(bigInverse, values, prime), bench.DiffSlices, inverseImpls...)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "✔ All methods agree with math/big")
+	bench.Print(out.Table, results)
+	report.WriteBars(w, results)
+	bench.PrintRuns(out.Detail, results)
+	rep.Add(fmt.Sprintf("inverses mod 2^61-1, n = %d", inverseN), results)
+	fmt.Fprintln(w, "\n  💡 Fermat's a^(p-2) is about 120 multiplications modulo p, and")
+	fmt.Fprintln(w, "     extended Euclid about 36 divisions. Fermat is also only right for")
+	fmt.Fprintln(w, "     a prime modulus, as the edge cases show.")
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	gcdCases := []struct {
+		desc string
+		a, b uint64
+	}{
+		{"gcd(0, 0)", 0, 0},
+		{"gcd(0, 7)", 0, 7},
+		{"gcd(a, a)", 1 << 40, 1 << 40},
+		{"gcd(2^63, 2^62)", 1 << 63, 1 << 62},
+		{"gcd(F93, F92)", fib93, fib92},
+		{"gcd(2^64-1, 2^32-1)", 1<<64 - 1, 1<<32 - 1},
+		{"gcd(2^63, 3)", 1 << 63, 3},
+	}
+	for _, tc := range gcdCases {
+		want := bigGCDs([]pair{{tc.a, tc.b}})[0]
+		fmt.Fprintf(w, "%s (want: %d):\n", tc.desc, want)
+		for _, t := range tiers {
+			var status, result string
+			if steps := numtheory.SubtractionSteps(tc.a, tc.b); t.name == "Vibe coding" && steps > maxVibeSteps {
+				status, result = "❌", fmt.Sprintf("not run: would take %d subtractions", steps)
+			} else if got := t.gcd(tc.a, tc.b); got != want {
+				status, result = "❌", fmt.Sprint(got)
+			} else {
+				status, result = "✅", fmt.Sprint(got)
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+result)
+		}
+	}
+
+	inverseCases := []struct {
+		desc string
+		a, m uint64
+	}{
+		{"inverse of 3 mod 7", 3, 7},
+		{"inverse of 3 mod 10", 3, 10},
+		{"inverse of 4 mod 6", 4, 6},
+		{"inverse of 0 mod 7", 0, 7},
+		{"inverse of 3 mod 2^64-59", 3, 1<<64 - 59},
+	}
+	for _, tc := range inverseCases {
+		want, ok := bigInverse(tc.a, tc.m)
+		fmt.Fprintf(w, "%s (want: %s):\n", tc.desc, showInverse(want, ok))
+		for _, m := range inverseMethods {
+			got, gotOK := m.inv(tc.a, tc.m)
+			status := "✅"
+			if got != want || gotOK != ok {
+				status = "❌"
+			}
+			result := showInverse(got, gotOK)
+			fmt.Fprintf(w, "  %-16s %s %s\n", m.name+":", status, result)
+			rep.AddEdgeCase(m.name+", "+tc.desc, status+" "+result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
+
+// inverseMethods lists the ways to find modular inverses compared.
+var inverseMethods = []struct {
+	name, complexity string
+	inv              inverse
+}{
+	{"Fermat", "O(log m) multiplications", fermatInverse},
+	{"Extended Euclid", "O(log m) divisions", numtheory.ModInverse},
+}
+
+// showInverse returns x, or "none" if there is no inverse.
+func showInverse(x uint64, ok bool) string {
+	if !ok {
+		return "none"
+	}
+	return fmt.Sprint(x)
+}
+
+// find returns the result named name, if there is one.
+func find(results []bench.Result, name string) (bench.Result, bool) {
+	for _, r := range results {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return bench.Result{}, false
+}
@@ -0,0 +1,122 @@
+package gcd
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/iportilla/ai-coding/numtheory"
+)
+
+// pair is one greatest common divisor to find.
+type pair struct {
+	a, b uint64
+}
+
+// vibeGCDs finds the GCD of every pair with numtheory.SubtractionGCD.
+//
+// VIBE CODING: subtract the smaller from the larger until they are
+// equal. Right by a one-line argument, and as fast as Euclid on numbers
+// of similar size - but a step takes off one copy of the smaller, so a
+// 64-bit number and a 16-bit one take 2^48 steps.
+func vibeGCDs(pairs []pair) []uint64 {
+	gcds := make([]uint64, len(pairs))
+	for i, p := range pairs {
+		gcds[i] = numtheory.SubtractionGCD(p.a, p.b)
+	}
+	return gcds
+}
+
+// humanGCDs finds the GCD of every pair with numtheory.EuclidGCD.
+//
+// HUMAN CODING: a % b takes off every copy of b in one step, so at most
+// 92 divisions for any two uint64s, whatever their sizes. A 64-bit
+// division takes tens of cycles, though, and each waits for the last.
+func humanGCDs(pairs []pair) []uint64 {
+	gcds := make([]uint64, len(pairs))
+	for i, p := range pairs {
+		gcds[i] = numtheory.EuclidGCD(p.a, p.b)
+	}
+	return gcds
+}
+
+// expertGCDs finds the GCD of every pair with numtheory.BinaryGCD.
+//
+// EXPERT CODING: Stein's algorithm. Common factors of 2 are counted with
+// one instruction, and the rest is subtracting odd numbers and shifting
+// out the factors of 2 that leaves: more steps than Euclid, but each a
+// few cycles, with no division anywhere.
+func expertGCDs(pairs []pair) []uint64 {
+	gcds := make([]uint64, len(pairs))
+	for i, p := range pairs {
+		gcds[i] = numtheory.BinaryGCD(p.a, p.b)
+	}
+	return gcds
+}
+
+// bigGCDs finds the GCD of every pair with math/big, the reference the
+// others are checked against.
+func bigGCDs(pairs []pair) []uint64 {
+	gcds := make([]uint64, len(pairs))
+	var x, y, g big.Int
+	for i, p := range pairs {
+		gcds[i] = g.GCD(nil, nil, x.SetUint64(p.a), y.SetUint64(p.b)).Uint64()
+	}
+	return gcds
+}
+
+// euclidSteps returns how many divisions numtheory.EuclidGCD takes for a
+// and b.
+func euclidSteps(a, b uint64) int {
+	steps := 0
+	for ; b != 0; steps++ {
+		a, b = b, a%b
+	}
+	return steps
+}
+
+// binarySteps returns how many subtractions numtheory.BinaryGCD takes for
+// a and b.
+func binarySteps(a, b uint64) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	a >>= bits.TrailingZeros64(a)
+	steps := 0
+	for ; b != 0; steps++ {
+		b >>= bits.TrailingZeros64(b)
+		if a > b {
+			a, b = b, a
+		}
+		b -= a
+	}
+	return steps
+}
+
+// An inverse is a way to find the x with a·x ≡ 1 (mod m), and whether
+// there is one.
+type inverse func(a, m uint64) (uint64, bool)
+
+// fermatInverse is a^(m-2) mod m, by numtheory.PowMod. Fermat's little
+// theorem makes that the inverse when m is prime and a is not a multiple
+// of it. Otherwise it is a number that isn't, and nothing says so.
+func fermatInverse(a, m uint64) (uint64, bool) {
+	return numtheory.PowMod(a, m-2, m), true
+}
+
+// bigInverse is big.Int.ModInverse.
+func bigInverse(a, m uint64) (uint64, bool) {
+	x := new(big.Int).ModInverse(new(big.Int).SetUint64(a), new(big.Int).SetUint64(m))
+	if x == nil {
+		return 0, false
+	}
+	return x.Uint64(), true
+}
+
+// inverses applies inv to every value modulo m, with 0 for none.
+func inverses(inv inverse, values []uint64, m uint64) []uint64 {
+	xs := make([]uint64, len(values))
+	for i, a := range values {
+		xs[i], _ = inv(a, m)
+	}
+	return xs
+}
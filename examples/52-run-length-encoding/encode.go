@@ -0,0 +1,124 @@
+package rle
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// The encoding is a list of (count, byte) pairs, count from 1 to
+// maxRun: "aaab" is 3 'a' 1 'b'. A run longer than maxRun takes more
+// than one pair.
+const maxRun = 255
+
+// vibeEncode builds the encoding in a string, a pair at a time.
+//
+// VIBE CODING: find the end of the run, append its length and byte,
+// repeat - the obvious loop. But a Go string is immutable, so every +=
+// copies everything so far into a new one: O(n²) bytes copied. And
+// byte(j-i) quietly wraps a run of 256 to 0, and one of 300 to 44.
+func vibeEncode(src []byte) []byte {
+	out := ""
+	for i := 0; i < len(src); {
+		j := i
+		for j < len(src) && src[j] == src[i] {
+			j++
+		}
+		out += string([]byte{byte(j - i), src[i]})
+		i = j
+	}
+	return []byte(out)
+}
+
+// humanEncode writes the pairs to a strings.Builder.
+//
+// HUMAN CODING: the Builder appends to a slice that doubles as it
+// fills, so O(n) with about log n reallocations, and a run ends a pair
+// every maxRun bytes. It still compares one byte at a time, and copies
+// the result once more on the way out as []byte.
+func humanEncode(src []byte) []byte {
+	var b strings.Builder
+	for i := 0; i < len(src); {
+		c := src[i]
+		n := 1
+		for i+n < len(src) && src[i+n] == c && n < maxRun {
+			n++
+		}
+		b.WriteByte(byte(n))
+		b.WriteByte(c)
+		i += n
+	}
+	return []byte(b.String())
+}
+
+// expertEncode appends the pairs to a byte slice allocated once at the
+// most the encoding can take, finding the end of each run 8 bytes at a
+// time.
+//
+// EXPERT CODING: one allocation and no copy - no input encodes to more
+// than twice its length. runEnd XORs 8 bytes with the run's byte
+// repeated 8 times: the result is 0 while the run goes on, and its
+// trailing zero bits count the bytes that match when it stops. No SIMD
+// instructions, but the same idea in a general-purpose register.
+func expertEncode(src []byte) []byte {
+	out := make([]byte, 0, 2*len(src))
+	for i := 0; i < len(src); {
+		c := src[i]
+		j := runEnd(src, i)
+		for n := j - i; n > 0; n -= maxRun {
+			out = append(out, byte(min(n, maxRun)), c)
+		}
+		i = j
+	}
+	return out
+}
+
+// ones has 1 in every byte: c*ones is c repeated 8 times.
+const ones = 0x0101010101010101
+
+// runEnd returns the index after the run of src[i] starting at i.
+func runEnd(src []byte, i int) int {
+	c := src[i]
+	pattern := uint64(c) * ones
+	j := i + 1
+	for ; j+8 <= len(src); j += 8 {
+		if x := binary.LittleEndian.Uint64(src[j:]) ^ pattern; x != 0 {
+			return j + bits.TrailingZeros64(x)/8
+		}
+	}
+	for j < len(src) && src[j] == c {
+		j++
+	}
+	return j
+}
+
+// errOddLength and errZeroCount are what decode reports for data no
+// encoder produces.
+var (
+	errOddLength = errors.New("odd length, so the last pair has no byte")
+	errZeroCount = errors.New("count of 0")
+)
+
+// decode returns the bytes an encoding stands for, the inverse every
+// encoder is fuzzed against.
+func decode(enc []byte) ([]byte, error) {
+	if len(enc)%2 != 0 {
+		return nil, errOddLength
+	}
+	n := 0
+	for i := 0; i < len(enc); i += 2 {
+		if enc[i] == 0 {
+			return nil, fmt.Errorf("pair at byte %d: %w", i, errZeroCount)
+		}
+		n += int(enc[i])
+	}
+	out := make([]byte, 0, n)
+	for i := 0; i < len(enc); i += 2 {
+		for range enc[i] {
+			out = append(out, enc[i+1])
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,86 @@
+package rle
+
+import (
+	"bytes"
+	"errors"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/iportilla/ai-coding/input"
+)
+
+// fuzzBytes are what the seed inputs are made of: 0 and 255, where a
+// count and a byte are easiest to mix up, and a letter.
+var fuzzBytes = []byte{0, 'a', 255}
+
+// fuzzInput returns a short random input: a few runs, most a few bytes
+// long, some crossing an 8-byte word, and some longer than maxRun.
+func fuzzInput(rng *rand.Rand) []byte {
+	var src []byte
+	for range rng.IntN(12) {
+		n := 1 + rng.IntN(9)
+		switch rng.IntN(10) {
+		case 0:
+			n = 250 + rng.IntN(270)
+		case 1, 2:
+			n = 1 + rng.IntN(40)
+		}
+		src = append(src, bytes.Repeat([]byte{fuzzBytes[rng.IntN(len(fuzzBytes))]}, n)...)
+	}
+	return src
+}
+
+// FuzzRoundTrip encodes its input with the human and expert encoders:
+// both encodings must decode back to the input, and be the same. The
+// decoder is the specification, so no encoding has to be written out by
+// hand. go test runs the seed inputs; go test -fuzz=FuzzRoundTrip
+// searches for more.
+func FuzzRoundTrip(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte("aaab"))
+	f.Add(bytes.Repeat([]byte{0}, 256))
+	f.Add(bytes.Repeat([]byte{255}, 300))
+	rng := input.DefaultSeed.Rand("fuzz", 0)
+	for range 1_000 {
+		f.Add(fuzzInput(rng))
+	}
+	f.Fuzz(func(t *testing.T, src []byte) {
+		var first []byte
+		for _, tier := range tiers[1:] {
+			enc := tier.encode(src)
+			if err := roundTrips(src)(enc, nil); err != nil {
+				t.Fatalf("%s: %v encodes to %v, which %v", tier.name, show(src), show(enc), err)
+			}
+			if first != nil && !bytes.Equal(enc, first) {
+				t.Fatalf("%s: %v encodes to %v, not %v", tier.name, show(src), show(enc), show(first))
+			}
+			first = enc
+		}
+	})
+}
+
+// TestVibeEncodeLongRun checks the vibe encoder's bug is still there to
+// teach: a run of 256 wraps to a count of 0.
+func TestVibeEncodeLongRun(t *testing.T) {
+	src := bytes.Repeat([]byte{'a'}, 256)
+	if err := roundTrips(src)(vibeEncode(src), nil); !errors.Is(err, errZeroCount) {
+		t.Errorf("256 bytes of 'a': got %v, want a count of 0", err)
+	}
+}
+
+// TestDecodeRejects checks decode rejects what no correct encoder
+// writes.
+func TestDecodeRejects(t *testing.T) {
+	tests := []struct {
+		enc  []byte
+		want error
+	}{
+		{[]byte{3}, errOddLength},
+		{[]byte{3, 'a', 0, 'b'}, errZeroCount},
+	}
+	for _, tt := range tests {
+		if _, err := decode(tt.enc); !errors.Is(err, tt.want) {
+			t.Errorf("decode(%v): got %v, want %v", tt.enc, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+// Package rle compares three ways to run-length encode bytes: string
+// concatenation, strings.Builder, and a preallocated byte slice that
+// scans runs 8 bytes at a time, each checked by decoding what it encodes.
+package rle
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// String concatenation copies O(n²) bytes, taking seconds above this.
+const maxVibeN = 10_000
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	encode           func([]byte) []byte
+}{
+	{"Vibe coding", "O(n²) string +=", vibeEncode},
+	{"Human coding", "O(n) strings.Builder", humanEncode},
+	{"Expert coding", "O(n), 8 bytes a step", expertEncode},
+}
+
+// encodersFor returns the implementations to compare on n bytes,
+// leaving out string concatenation where it would take too long or
+// return a wrong encoding.
+func encodersFor(n int, vibe bool) []bench.Impl[[]byte, []byte] {
+	var impls []bench.Impl[[]byte, []byte]
+	for _, t := range tiers {
+		if t.name == "Vibe coding" && (n > maxVibeN || !vibe) {
+			continue
+		}
+		impls = append(impls, bench.Impl[[]byte, []byte]{
+			Name: t.name, Complexity: t.complexity, Func: t.encode,
+		})
+	}
+	return impls
+}
+
+// kind is a kind of data to encode: text, where runs are rare and the
+// encoding nearly doubles it, or image, where they are long.
+type kind struct {
+	name string
+	make func(rng *rand.Rand, n int) []byte
+}
+
+var kinds = []kind{{"text", makeText}, {"image", makeImage}}
+
+var words = []string{"the", "a", "coffee", "bookkeeper", "committee", "moon", "sleeps", "aaargh", "of", "in", "balloon", "mississippi", "three", "rolls"}
+
+// makeText returns n bytes of words and spaces.
+func makeText(rng *rand.Rand, n int) []byte {
+	b := make([]byte, 0, n+16)
+	for len(b) < n {
+		b = append(b, words[rng.IntN(len(words))]...)
+		b = append(b, ' ')
+	}
+	return b[:n]
+}
+
+// makeImage returns n bytes of a picture with 256 colours, a byte per
+// pixel, row after row: runs of background, 0, between runs of a colour,
+// from 1 to 600 pixels long, so a run is often longer than one pair can
+// hold.
+func makeImage(rng *rand.Rand, n int) []byte {
+	b := make([]byte, 0, n+600)
+	for len(b) < n {
+		b = append(b, bytes.Repeat([]byte{0}, 1+rng.IntN(600))...)
+		b = append(b, bytes.Repeat([]byte{byte(1 + rng.IntN(255))}, 1+rng.IntN(600))...)
+	}
+	return b[:n]
+}
+
+// impls returns the implementations timed encoding n bytes of text.
+func impls(n int) []bench.Implementation {
+	src := makeText(input.DefaultSeed.Rand("text", n), n)
+	var list []bench.Implementation
+	for _, e := range encodersFor(n, true) {
+		list = append(list, e.Implementation(src))
+	}
+	return list
+}
+
+// roundTrips returns an equality function for CompareImpls that accepts
+// an encoding if it decodes back to src.
+func roundTrips(src []byte) func(got, _ []byte) error {
+	return func(got, _ []byte) error {
+		dec, err := decode(got)
+		if err != nil {
+			return fmt.Errorf("fails to decode: %w", err)
+		}
+		if !bytes.Equal(dec, src) {
+			return fmt.Errorf("decodes to something else: %w", bench.DiffSlices(dec, src))
+		}
+		return nil
+	}
+}
+
+// show returns b as a list, or its length and ends if it is long.
+func show(b []byte) string {
+	if len(b) <= 8 {
+		return fmt.Sprint(b)
+	}
+	return fmt.Sprintf("[%d %d ... %d %d] (%d bytes)", b[0], b[1], b[len(b)-2], b[len(b)-1], len(b))
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "append each pair to a string with +=", Complexity: "O(n²)", Notes: []report.Note{
+			report.Strength("The obvious loop, and right for runs up to 255 bytes"),
+			report.Pitfall("Every += copies the whole string so far: ten times the input is a hundred times the work"),
+			report.Pitfall("byte(j-i) wraps a run of 256 to a count of 0, and one of 300 to 44, without a word"),
+		}},
+		{Label: "Human coding", Approach: "strings.Builder, a byte at a time", Complexity: "O(n)", Notes: []report.Note{
+			report.Strength("Amortised appends, and runs split every 255 bytes"),
+			report.Strength("Round-trips every fuzzed input"),
+			report.Pitfall("Grows by doubling, and copies the result once more as []byte"),
+		}},
+		{Label: "Expert coding", Approach: "preallocated []byte, runs scanned 8 bytes at a time", Complexity: "O(n)", Notes: []report.Note{
+			report.Strength("One allocation, no copies, and long runs scanned a word at a time"),
+			report.Strength("Round-trips every fuzzed input, with the same bytes as the human version"),
+			report.Pitfall("Reserves twice the input up front, even for an image that shrinks to a hundredth"),
+			report.Pitfall("Word-at-a-time tricks gain little on text, where runs are a byte or two"),
+		}},
+	},
+	Takeaway: "Building a string with += in a loop is quadratic; use a " +
+		"strings.Builder, or a byte slice sized once. An encoder is only " +
+		"as good as its round trip, so fuzz it through the decoder: inputs " +
+		"with runs past the count's limit are exactly the ones hand-written " +
+		"tests forget.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "52-run-length-encoding",
+		Title:       "Run-Length Encoding",
+		Description: "Run-length encode text and images with string concatenation, strings.Builder and a preallocated byte slice scanning 8 bytes at a time, fuzzed by decoding every encoding.",
+		Category:    "strings",
+		Difficulty:  examples.Intermediate,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    100_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("52-run-length-encoding", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{1_000, 10_000, 100_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of bytes to encode, e.g. 1e4,1e6")
+	kindNames := fs.String("data", "text,image", "comma-separated data to encode: text, image")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var active []kind
+	for _, name := range strings.Split(*kindNames, ",") {
+		i := slices.IndexFunc(kinds, func(k kind) bool { return k.name == strings.TrimSpace(name) })
+		if i < 0 {
+			return fmt.Errorf("-data: unknown data %q (want text or image)", name)
+		}
+		active = append(active, kinds[i])
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Run-Length Encoding", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Run-Length Encoding")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	for _, n := range sizes {
+		for _, k := range active {
+			src := k.make(seed.Rand(k.name, n), n)
+			title := fmt.Sprintf("%s: %d bytes", k.name, n)
+			fmt.Fprintf(out.Table, "\n%s (seed %d):\n", title, *seed)
+			fmt.Fprintln(w, strings.Repeat("-", 60))
+
+			// String concatenation only takes part where its encoding is
+			// right, and only on sizes where it is worth waiting for.
+			vibeErr := error(nil)
+			if n <= maxVibeN {
+				vibeErr = roundTrips(src)(vibeEncode(src), nil)
+			}
+			results, err := bench.CompareImpls(ctx, opts, src, nil, roundTrips(src), encodersFor(n, vibeErr == nil)...)
+			if err != nil {
+				return err
+			}
+			size := len(expertEncode(src))
+			fmt.Fprintf(w, "✔ Encodings decode back to the input: %d bytes encode to %d (%.1f%%)\n",
+				n, size, 100*float64(size)/float64(n))
+			if err := csvLog.Append(k.name, uint64(n), results); err != nil {
+				return fmt.Errorf("csv: %w", err)
+			}
+			benchLog.Append(k.name, uint64(n), results)
+			bench.Print(out.Table, results)
+			report.WriteBars(w, results)
+			bench.PrintRuns(out.Detail, results)
+			section := rep.Add(title, results)
+
+			var note string
+			switch {
+			case vibeErr != nil:
+				note = fmt.Sprintf("Vibe coding left out: wrong encoding, which %v", vibeErr)
+				fmt.Fprintln(w, "  ❌ "+note)
+			case n > maxVibeN:
+				note = fmt.Sprintf("Vibe coding skipped: O(n²) is impractical above n=%d", maxVibeN)
+				fmt.Fprintln(w, "  ⏭️  "+note)
+			}
+			if note != "" {
+				section.Notes = append(section.Notes, note)
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	allBytes := make([]byte, 256)
+	for i := range allBytes {
+		allBytes[i] = byte(i)
+	}
+	edgeCases := []struct {
+		desc string
+		src  []byte
+		want string
+	}{
+		{"empty", []byte{}, "[]"},
+		{`"aaab"`, []byte("aaab"), "[3 97 1 98]"},
+		{"a run of 8 after one byte", append([]byte{1}, bytes.Repeat([]byte{2}, 8)...), "[1 1 8 2]"},
+		{"255 zeros", make([]byte, 255), "[255 0]"},
+		{"256 zeros", make([]byte, 256), "[255 0 1 0]"},
+		{"300 bytes of 255", bytes.Repeat([]byte{255}, 300), "[255 255 45 255]"},
+		{"every byte once", allBytes, "[1 0 ... 1 255] (512 bytes)"},
+	}
+	for _, tc := range edgeCases {
+		fmt.Fprintf(w, "%s (want: %s):\n", tc.desc, tc.want)
+		for _, t := range tiers {
+			got := t.encode(tc.src)
+			status, result := "✅", show(got)
+			if err := roundTrips(tc.src)(got, nil); err != nil {
+				status, result = "❌", fmt.Sprintf("%s, which %v", result, err)
+			}
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,326 @@
+// Package palindrome compares three ways to reverse a string, and so to
+// check for palindromes: reversing its bytes, its runes and its grapheme
+// clusters - the characters a reader sees.
+package palindrome
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// tiers lists the implementations in order.
+var tiers = []struct {
+	name, complexity string
+	reverse          func(string) string
+}{
+	{"Vibe coding", "O(n) bytes", vibeReverse},
+	{"Human coding", "O(n) runes", humanReverse},
+	{"Expert coding", "O(n) grapheme clusters", expertReverse},
+}
+
+// reversersFor returns the implementations to compare, leaving out
+// those whose reversal is wrong.
+func reversersFor(wrong []string) []bench.Impl[string, string] {
+	var impls []bench.Impl[string, string]
+	for _, t := range tiers {
+		if slices.Contains(wrong, t.name) {
+			continue
+		}
+		impls = append(impls, bench.Impl[string, string]{
+			Name: t.name, Complexity: t.complexity, Func: t.reverse,
+		})
+	}
+	return impls
+}
+
+// A word is a list of grapheme clusters, so reversing it needs no
+// segmenting: the clusters are written out by hand.
+type word []string
+
+// letters returns s as a word of one cluster per rune, for words with
+// no combining marks or emoji.
+func letters(s string) word {
+	return strings.Split(s, "")
+}
+
+// kind is a kind of text to reverse: ascii, which every way reverses
+// right; accented, with precomposed letters such as é, which takes
+// runes; and emoji, with flags, skin tones, families and combining
+// accents, which takes grapheme clusters.
+type kind struct {
+	name  string
+	words []word
+}
+
+var (
+	asciiWords    = []word{letters("level"), letters("racecar"), letters("hello"), letters("world"), letters("gopher"), letters("kayak"), letters("string")}
+	accentedWords = []word{letters("café"), letters("niño"), letters("über"), letters("résumé"), letters("naïve"), letters("été"), letters("こんにちは")}
+	emojiWords    = []word{
+		// Thumbs up with a skin tone, a family joined by zero-width
+		// joiners, two flags of two regional indicators each, and a
+		// heart with the emoji variation selector.
+		{"👍\U0001F3FD"}, {"👨\u200D👩\u200D👧"}, {"🇺🇸"}, {"🇯🇵"}, {"❤\uFE0F"},
+		// café and ñoñ, with combining accents.
+		{"c", "a", "f", "e\u0301"}, {"n\u0303", "o", "n\u0303"},
+	}
+)
+
+var kinds = []kind{
+	{"ascii", asciiWords},
+	{"accented", slices.Concat(asciiWords, accentedWords)},
+	{"emoji", slices.Concat(asciiWords, accentedWords, emojiWords)},
+}
+
+// makeText returns text of random words of k and spaces, at least n
+// bytes long, and the text reversed cluster by cluster.
+func makeText(rng *rand.Rand, k kind, n int) (text, reversed string) {
+	var clusters []string
+	size := 0
+	for size < n {
+		if len(clusters) > 0 {
+			clusters = append(clusters, " ")
+			size++
+		}
+		for _, c := range k.words[rng.IntN(len(k.words))] {
+			clusters = append(clusters, c)
+			size += len(c)
+		}
+	}
+	text = strings.Join(clusters, "")
+	slices.Reverse(clusters)
+	return text, strings.Join(clusters, "")
+}
+
+// impls returns the implementations timed reversing n bytes of ASCII
+// text, which all of them get right.
+func impls(n int) []bench.Implementation {
+	text, _ := makeText(input.DefaultSeed.Rand("ascii", n), kinds[0], n)
+	var list []bench.Implementation
+	for _, r := range reversersFor(nil) {
+		list = append(list, r.Implementation(text))
+	}
+	return list
+}
+
+// sameText is the equality function for reversals: it reports the first
+// byte that differs, with a little of the text around it.
+func sameText(got, want string) error {
+	if got == want {
+		return nil
+	}
+	i := 0
+	for i < min(len(got), len(want)) && got[i] == want[i] {
+		i++
+	}
+	return fmt.Errorf("at byte %d, got %q, want %q", i, around(got, i), around(want, i))
+}
+
+// around returns about 12 bytes of s from a little before i, so the
+// mismatch shows with some context, widened to whole UTF-8 sequences
+// where there are any.
+func around(s string, i int) string {
+	start, end := max(0, i-4), min(len(s), i+8)
+	for n := 0; n < 3 && start > 0 && !utf8.RuneStart(s[start]); n++ {
+		start--
+	}
+	for n := 0; n < 3 && end < len(s) && !utf8.RuneStart(s[end]); n++ {
+		end++
+	}
+	return s[start:end]
+}
+
+// lesson is what the example teaches, printed as its summary.
+var lesson = report.Lesson{
+	Tiers: []report.Tier{
+		{Label: "Vibe coding", Approach: "reverse the bytes", Complexity: "O(n)", Notes: []report.Note{
+			report.Strength("Simple and fast: one copy, no decoding"),
+			report.Pitfall("Right only for ASCII: é, ñ and every emoji come back as invalid UTF-8"),
+			report.Pitfall("\"été\" is a palindrome, and its reversed bytes aren't"),
+		}},
+		{Label: "Human coding", Approach: "reverse a []rune", Complexity: "O(n)", Notes: []report.Note{
+			report.Strength("Right for any text where one code point is one character"),
+			report.Pitfall("Moves combining accents onto the wrong letter, and splits flags, skin tones and emoji families"),
+			report.Pitfall("Turns invalid bytes into U+FFFD, and \\r\\n into \\n\\r"),
+			report.Pitfall("Allocates 4 bytes per character for the []rune, then a string"),
+		}},
+		{Label: "Expert coding", Approach: "reverse grapheme clusters, found by UAX #29's main rules", Complexity: "O(n)", Notes: []report.Note{
+			report.Strength("Reverses what a reader sees: accents, flags and emoji families stay whole"),
+			report.Strength("One allocation of exactly len(s), bytes copied unchanged, ASCII on a fast path"),
+			report.Pitfall("Implements the common rules only - use x/text or uniseg for all of UAX #29"),
+		}},
+	},
+	Takeaway: "A Go string is UTF-8 bytes, a rune is a code point, and " +
+		"neither is what a reader calls a character. Reversing, " +
+		"truncating or counting text by bytes or runes is right only " +
+		"until the first accent or emoji. Decide which of the three you " +
+		"mean, and test with text that tells them apart.",
+}
+
+func init() {
+	examples.Register(examples.Example{
+		Name:        "53-palindromes",
+		Title:       "Palindromes and String Reversal",
+		Description: "Reverse strings and check palindromes by bytes, by runes and by grapheme clusters, showing where each breaks on accents, flags, skin tones and emoji sequences.",
+		Category:    "strings",
+		Difficulty:  examples.Beginner,
+		Lesson:      lesson,
+		Run:         Run,
+		Impls:       impls,
+		DefaultN:    10_000,
+	})
+}
+
+// Run runs the example with the given command-line arguments.
+func Run(ctx context.Context, w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("53-palindromes", flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes{100, 10_000, 1_000_000}
+	fs.Var(&sizes, "n", "comma-separated numbers of bytes of text to reverse, e.g. 1e3,1e5")
+	kindNames := fs.String("text", "ascii,accented,emoji", "comma-separated text to reverse: ascii, accented, emoji")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var active []kind
+	for _, name := range strings.Split(*kindNames, ",") {
+		i := slices.IndexFunc(kinds, func(k kind) bool { return k.name == strings.TrimSpace(name) })
+		if i < 0 {
+			return fmt.Errorf("-text: unknown text %q (want ascii, accented or emoji)", name)
+		}
+		active = append(active, kinds[i])
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := examples.NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, fs.Name())
+	benchLog := report.NewBenchLog(out.Bench, fs.Name())
+	rep := report.New("Palindromes and String Reversal", opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w, "EXAMPLE: Palindromes and String Reversal")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	for _, n := range sizes {
+		for _, k := range active {
+			text, want := makeText(seed.Rand(k.name, n), k, n)
+			title := fmt.Sprintf("%s: %d bytes", k.name, n)
+			fmt.Fprintf(out.Table, "\n%s, %d characters (seed %d):\n", title, utf8.RuneCountInString(text), *seed)
+			fmt.Fprintln(w, strings.Repeat("-", 60))
+
+			// Only the reversals that are right are timed: a wrong
+			// answer is not faster, just wrong.
+			var wrong, notes []string
+			for _, t := range tiers {
+				if err := sameText(t.reverse(text), want); err != nil {
+					wrong = append(wrong, t.name)
+					notes = append(notes, fmt.Sprintf("%s left out: wrong reversal, %v", t.name, err))
+				}
+			}
+			results, err := bench.CompareImpls(ctx, opts, text, want, sameText, reversersFor(wrong)...)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "✔ %d of %d implementations reverse it right\n", len(tiers)-len(wrong), len(tiers))
+			if err := csvLog.Append(k.name, uint64(n), results); err != nil {
+				return fmt.Errorf("csv: %w", err)
+			}
+			benchLog.Append(k.name, uint64(n), results)
+			bench.Print(out.Table, results)
+			report.WriteBars(w, results)
+			bench.PrintRuns(out.Detail, results)
+			section := rep.Add(title, results)
+			for _, note := range notes {
+				fmt.Fprintln(w, "  ❌ "+note)
+				section.Notes = append(section.Notes, note)
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Edge case testing
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "Edge Case Testing: reversed, and is it a palindrome?")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+
+	edgeCases := []struct {
+		desc       string
+		s, want    string
+		palindrome bool
+	}{
+		{"empty", "", "", true},
+		{"ASCII palindrome", "racecar", "racecar", true},
+		{"ASCII", "hello", "olleh", false},
+		{"precomposed accents", "été", "été", true},
+		{"combining accents", "e\u0301te\u0301", "e\u0301te\u0301", true},
+		{"flags of the US and Japan", "🇺🇸🇯🇵", "🇯🇵🇺🇸", false},
+		{"a skin tone", "👍🏽 ok", "ko 👍🏽", false},
+		{"a family of three", "👨\u200D👩\u200D👧", "👨\u200D👩\u200D👧", true},
+		{"a Windows line ending", "a\r\nb", "b\r\na", false},
+		{"invalid UTF-8", "a\xffb", "b\xffa", false},
+	}
+	for _, tc := range edgeCases {
+		fmt.Fprintf(w, "%s, %q (want: %q, %s):\n", tc.desc, tc.s, tc.want, verdict(tc.palindrome))
+		for _, t := range tiers {
+			got := t.reverse(tc.s)
+			palindrome := isPalindrome(t.reverse, tc.s)
+			status := "✅"
+			if got != tc.want || palindrome != tc.palindrome {
+				status = "❌"
+			}
+			result := fmt.Sprintf("%q, %s", got, verdict(palindrome))
+			fmt.Fprintf(w, "  %-14s %s %s\n", t.name+":", status, result)
+			rep.AddEdgeCase(t.name+", "+tc.desc, status+" "+result)
+		}
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+
+	return nil
+}
+
+// verdict describes whether a string is a palindrome.
+func verdict(palindrome bool) string {
+	if palindrome {
+		return "palindrome"
+	}
+	return "not a palindrome"
+}
@@ -0,0 +1,141 @@
+package palindrome
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// VIBE CODING: Reverse the bytes
+func vibeReverse(s string) string {
+	/*
+	   Copy the string into a byte slice, swap the first and last bytes,
+	   the second and the second to last, and so on to the middle.
+
+	   Right for ASCII, where a byte is a character. But Go strings are
+	   UTF-8, and every character past ASCII takes two to four bytes:
+	   "é" is C3 A9, and reversed, A9 C3 is not a character at all. So
+	   "été" - a palindrome - comes back as invalid UTF-8, and is not
+	   one any more.
+	*/
+	b := []byte(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
+
+// HUMAN CODING: Reverse the runes
+func humanReverse(s string) string {
+	/*
+	   Convert the string to []rune, one code point each, and reverse
+	   that: the answer in most tutorials, and right for "été" and
+	   "こんにちは".
+
+	   But what a reader sees as one character can be several code
+	   points. "é" can also be written e followed by U+0301, a combining
+	   accent, which reversed lands on the letter before it. A flag is
+	   two regional indicator letters: reversed, 🇺🇸 reads "SU". A
+	   thumbs-up with a skin tone, or a family joined by zero-width
+	   joiners, falls apart into pieces. And []rune turns every invalid
+	   byte into U+FFFD, so even the bytes change.
+	*/
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+// EXPERT CODING: Reverse the grapheme clusters
+func expertReverse(s string) string {
+	/*
+	   Reverse what a reader sees as characters: grapheme clusters,
+	   defined by Unicode's UAX #29. clusterEnd finds where each one
+	   ends, and it is copied, bytes unchanged, to the mirror position
+	   at the end of the result - one pass, one allocation of exactly
+	   len(s) bytes.
+
+	   clusterEnd covers the rules that matter for text and emoji:
+	   combining marks, \r\n, flags, skin tones and zero-width joiner
+	   sequences. It leaves out the rest of UAX #29, such as decomposed
+	   Hangul and Indic conjuncts. golang.org/x/text and
+	   github.com/rivo/uniseg implement all of it, at the cost of a
+	   dependency and their tables.
+	*/
+	b := make([]byte, len(s))
+	for i := 0; i < len(s); {
+		j := clusterEnd(s, i)
+		copy(b[len(s)-j:], s[i:j])
+		i = j
+	}
+	return string(b)
+}
+
+// zwj is the zero-width joiner, which glues emoji into one: 👨 ZWJ 👩
+// ZWJ 👧 is a family.
+const zwj = '\u200D'
+
+// clusterEnd returns the index in s where the grapheme cluster starting
+// at i ends.
+func clusterEnd(s string, i int) int {
+	// ASCII followed by ASCII is a cluster of one byte, except \r\n.
+	if c := s[i]; c < utf8.RuneSelf && (i+1 == len(s) || s[i+1] < utf8.RuneSelf) {
+		if c == '\r' && i+1 < len(s) && s[i+1] == '\n' {
+			return i + 2
+		}
+		return i + 1
+	}
+	first, size := utf8.DecodeRuneInString(s[i:])
+	j := i + size
+	if unicode.IsControl(first) {
+		return j // Nothing attaches to a control character
+	}
+	prev, regionals := first, 0
+	if isRegional(first) {
+		regionals = 1
+	}
+	for j < len(s) {
+		r, size := utf8.DecodeRuneInString(s[j:])
+		switch {
+		case isExtend(r) || r == zwj:
+			// Accents, variation selectors and skin tones attach to
+			// what comes before.
+		case prev == zwj && isPictographic(first) && isPictographic(r):
+			// An emoji after a zero-width joiner joins the sequence.
+		case regionals == 1 && prev == first && isRegional(r):
+			// Regional indicators pair up into flags.
+			regionals++
+		default:
+			return j
+		}
+		prev = r
+		j += size
+	}
+	return j
+}
+
+// isExtend reports whether r attaches to the character before it:
+// combining marks, the zero-width non-joiner, emoji skin tones and the
+// tag characters of subdivision flags.
+func isExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		r == '\u200C' ||
+		'\U0001F3FB' <= r && r <= '\U0001F3FF' ||
+		'\U000E0020' <= r && r <= '\U000E007F'
+}
+
+// isRegional reports whether r is one of the regional indicator
+// letters 🇦 to 🇿, which make flags in pairs.
+func isRegional(r rune) bool {
+	return '\U0001F1E6' <= r && r <= '\U0001F1FF'
+}
+
+// isPictographic reports whether r is an emoji or other pictograph.
+func isPictographic(r rune) bool {
+	return unicode.Is(unicode.So, r) || '\U0001F000' <= r && r <= '\U0001FAFF'
+}
+
+// isPalindrome reports whether s reads the same reversed by reverse.
+func isPalindrome(reverse func(string) string, s string) bool {
+	return reverse(s) == s
+}
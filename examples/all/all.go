@@ -0,0 +1,60 @@
+// Package all registers every Go example with the examples registry.
+// Import it for its side effects:
+//
+//	import _ "github.com/iportilla/ai-coding/examples/all"
+package all
+
+import (
+	_ "github.com/iportilla/ai-coding/examples/02-prime-algorithms"
+	_ "github.com/iportilla/ai-coding/examples/03-sorting"
+	_ "github.com/iportilla/ai-coding/examples/04-search"
+	_ "github.com/iportilla/ai-coding/examples/05-primality"
+	_ "github.com/iportilla/ai-coding/examples/06-fibonacci"
+	_ "github.com/iportilla/ai-coding/examples/07-string-building"
+	_ "github.com/iportilla/ai-coding/examples/08-factorization"
+	_ "github.com/iportilla/ai-coding/examples/09-worker-pool"
+	_ "github.com/iportilla/ai-coding/examples/10-lru-cache"
+	_ "github.com/iportilla/ai-coding/examples/11-json-parsing"
+	_ "github.com/iportilla/ai-coding/examples/12-shortest-paths"
+	_ "github.com/iportilla/ai-coding/examples/13-hash-maps"
+	_ "github.com/iportilla/ai-coding/examples/14-matrix-multiply"
+	_ "github.com/iportilla/ai-coding/examples/15-goldbach"
+	_ "github.com/iportilla/ai-coding/examples/16-modular-exponentiation"
+	_ "github.com/iportilla/ai-coding/examples/17-word-frequency"
+	_ "github.com/iportilla/ai-coding/examples/18-csv-parsing"
+	_ "github.com/iportilla/ai-coding/examples/19-http-retry"
+	_ "github.com/iportilla/ai-coding/examples/20-rate-limiter"
+	_ "github.com/iportilla/ai-coding/examples/21-counters"
+	_ "github.com/iportilla/ai-coding/examples/22-pipeline"
+	_ "github.com/iportilla/ai-coding/examples/23-prefix-search"
+	_ "github.com/iportilla/ai-coding/examples/24-priority-queue"
+	_ "github.com/iportilla/ai-coding/examples/25-union-find"
+	_ "github.com/iportilla/ai-coding/examples/26-knapsack"
+	_ "github.com/iportilla/ai-coding/examples/27-lcs"
+	_ "github.com/iportilla/ai-coding/examples/28-slice-growth"
+	_ "github.com/iportilla/ai-coding/examples/29-string-interning"
+	_ "github.com/iportilla/ai-coding/examples/30-error-handling"
+	_ "github.com/iportilla/ai-coding/examples/31-context-propagation"
+	_ "github.com/iportilla/ai-coding/examples/32-checksums"
+	_ "github.com/iportilla/ai-coding/examples/33-compression"
+	_ "github.com/iportilla/ai-coding/examples/34-serialization"
+	_ "github.com/iportilla/ai-coding/examples/35-code-generation"
+	_ "github.com/iportilla/ai-coding/examples/36-dispatch"
+	_ "github.com/iportilla/ai-coding/examples/37-buffer-pool"
+	_ "github.com/iportilla/ai-coding/examples/38-goroutine-leak"
+	_ "github.com/iportilla/ai-coding/examples/39-echo-server"
+	_ "github.com/iportilla/ai-coding/examples/40-http-middleware"
+	_ "github.com/iportilla/ai-coding/examples/41-templates"
+	_ "github.com/iportilla/ai-coding/examples/42-file-reading"
+	_ "github.com/iportilla/ai-coding/examples/43-duplicates"
+	_ "github.com/iportilla/ai-coding/examples/44-anagrams"
+	_ "github.com/iportilla/ai-coding/examples/45-top-k"
+	_ "github.com/iportilla/ai-coding/examples/46-time-parsing"
+	_ "github.com/iportilla/ai-coding/examples/47-unique-ids"
+	_ "github.com/iportilla/ai-coding/examples/48-password-hashing"
+	_ "github.com/iportilla/ai-coding/examples/49-sampling"
+	_ "github.com/iportilla/ai-coding/examples/50-polynomial-multiplication"
+	_ "github.com/iportilla/ai-coding/examples/51-gcd"
+	_ "github.com/iportilla/ai-coding/examples/52-run-length-encoding"
+	_ "github.com/iportilla/ai-coding/examples/53-palindromes"
+)
@@ -0,0 +1,69 @@
+package examples
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/input"
+)
+
+// Exercise turns an example into a task for `ai-coding exercise`.
+// Students are shown only the Vibe implementation and write their own in
+// its place, in File; it passes once it agrees with the Expert
+// implementation and runs within Target times the Expert's time. The
+// Expert's code and approach stay hidden: only its timing is shown, as
+// the bar to reach.
+type Exercise struct {
+	// File is the solution students edit, relative to the repository
+	// root, e.g. "exercises/03-sorting/solution.go". It starts out as
+	// the Vibe implementation, so every exercise begins failing its
+	// target.
+	File string
+	// Signature is the function File must keep defining.
+	Signature string
+	// Vibe is the source of the Vibe implementation, the starting point
+	// shown to students; see FuncSource.
+	Vibe string
+
+	// N is the input size the solution is timed at.
+	N int
+	// Target is how many times the Expert's median time the solution
+	// may take, e.g. 2.
+	Target float64
+
+	// Check runs the solution and the Expert on inputs drawn from seed,
+	// edge cases included, and describes the first input they disagree
+	// on.
+	Check func(seed input.Seed) error
+	// Impls returns the solution and the Expert implementation, ready to
+	// time on an input of size n.
+	Impls func(n int) (solution, expert bench.Implementation)
+}
+
+// FuncSource returns the declaration of the function called name in the
+// Go source src, doc comment included, as it is written there. Examples
+// embed their implementation file and use it to fill Exercise.Vibe, so
+// what students are shown can't drift from the code the example times.
+// It panics if src doesn't declare the function, a programming error
+// caught on first start.
+func FuncSource(src, name string) string {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		panic("examples: FuncSource: " + err.Error())
+	}
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Name.Name != name {
+			continue
+		}
+		start := fn.Pos()
+		if fn.Doc != nil {
+			start = fn.Doc.Pos()
+		}
+		return src[fset.Position(start).Offset:fset.Position(fn.End()).Offset]
+	}
+	panic("examples: FuncSource: no function " + name)
+}
@@ -0,0 +1,33 @@
+package examples
+
+import "io"
+
+// Output is where an example writes, split by how much the reader asked
+// to see. Every example has a -q flag for scripting, which keeps only
+// the results tables, and a -v flag for debugging, which adds the detail
+// behind them. A -benchfmt flag replaces all of it with Go benchmark
+// format lines for benchstat.
+type Output struct {
+	Table  io.Writer // Results tables and the headings that label them; always written
+	Text   io.Writer // Explanations, verdicts and summaries; discarded with -q
+	Detail io.Writer // Individual runs, GC activity and algorithm statistics; only with -v
+	Bench  io.Writer // Go benchmark format lines; only with -benchfmt, otherwise nil
+}
+
+// NewOutput returns the Output for an example writing to w. quiet and
+// verbose may both be set, giving the tables and their detail without
+// the surrounding text. benchfmt overrides both: only Bench is written,
+// so the output can be piped straight into benchstat.
+func NewOutput(w io.Writer, quiet, verbose, benchfmt bool) Output {
+	if benchfmt {
+		return Output{Table: io.Discard, Text: io.Discard, Detail: io.Discard, Bench: w}
+	}
+	out := Output{Table: w, Text: w, Detail: io.Discard}
+	if quiet {
+		out.Text = io.Discard
+	}
+	if verbose {
+		out.Detail = w
+	}
+	return out
+}
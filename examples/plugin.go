@@ -0,0 +1,205 @@
+package examples
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/input"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// Plugin is an example contributed from outside this repository. A Go
+// module implements it for its own vibe, human and expert
+// implementations, and either registers it with
+// Register(FromPlugin(p)) in a program that imports this package, or
+// serves it with ServePlugin as a program of its own, which the
+// ai-coding command finds on PATH and runs like any other example.
+type Plugin interface {
+	// Name is the example's name on the command line, e.g. "15-tries".
+	Name() string
+	// Tiers describe the implementations Compare times, in the order
+	// it returns their results.
+	Tiers() []report.Tier
+	// GenerateInput returns the input of size n the implementations are
+	// compared on, drawn from seed so that runs can be repeated.
+	GenerateInput(seed input.Seed, n int) any
+	// Compare checks every implementation gives the right answer on in,
+	// a value GenerateInput returned, and times them with opts, like
+	// bench.CompareImpls.
+	Compare(ctx context.Context, opts bench.Options, in any) ([]bench.Result, error)
+}
+
+// PluginInfo is what a Plugin can say about itself besides its tiers.
+// Plugins provide it by implementing Describer; otherwise the name
+// stands in for the title and the sizes default to 1,000, 10,000 and
+// 100,000.
+type PluginInfo struct {
+	Title       string
+	Description string
+	Category    string
+	Difficulty  Difficulty
+	Takeaway    string
+	Sizes       []int // The sizes of n run by default
+}
+
+// Describer is implemented by plugins that describe themselves.
+type Describer interface {
+	Info() PluginInfo
+}
+
+// FromPlugin returns p as an Example. Its Run takes the flags every
+// example takes - -n, -seed, -runs, -warmup, -timeout, -dnf-after,
+// -csv, -q, -v, -benchfmt, -report and -o - and prints what the
+// examples in this repository print for each size of input.
+func FromPlugin(p Plugin) Example {
+	info := PluginInfo{Title: p.Name()}
+	if d, ok := p.(Describer); ok {
+		info = d.Info()
+		if info.Title == "" {
+			info.Title = p.Name()
+		}
+	}
+	if len(info.Sizes) == 0 {
+		info.Sizes = []int{1_000, 10_000, 100_000}
+	}
+	lesson := report.Lesson{Tiers: p.Tiers(), Takeaway: info.Takeaway}
+	return Example{
+		Name:        p.Name(),
+		Title:       info.Title,
+		Description: info.Description,
+		Category:    info.Category,
+		Difficulty:  info.Difficulty,
+		Lesson:      lesson,
+		Run: func(ctx context.Context, w io.Writer, args []string) error {
+			return runPlugin(ctx, w, p, info, lesson, args)
+		},
+	}
+}
+
+// runPlugin is the Run of a plugin's Example.
+func runPlugin(ctx context.Context, w io.Writer, p Plugin, info PluginInfo, lesson report.Lesson, args []string) error {
+	fs := flag.NewFlagSet(p.Name(), flag.ContinueOnError)
+	runs := fs.Int("runs", 5, "timed runs per algorithm (median is reported)")
+	warmup := fs.Int("warmup", 1, "untimed warm-up runs per algorithm")
+	timeout := fs.Duration("timeout", 0, "give up after this long, e.g. 30s (0 means no limit; Ctrl-C also stops the run)")
+	dnfAfter := fs.Duration("dnf-after", 0, "stop an algorithm that takes longer than this, e.g. 10s, and report it as DNF (0 means never)")
+	csvPath := fs.String("csv", "", "append one row per algorithm and n to this CSV file, e.g. results.csv")
+	quiet := fs.Bool("q", false, "print only the results tables")
+	verbose := fs.Bool("v", false, "also print every run's timing and GC activity")
+	benchfmt := fs.Bool("benchfmt", false, "print only Go benchmark format lines, e.g. to pipe into benchstat")
+	sizes := bench.Sizes(info.Sizes)
+	fs.Var(&sizes, "n", "comma-separated input sizes, e.g. 1e4,1e5")
+	seed := input.Flag(fs)
+	reportFormat := fs.String("report", "", "also write a report in this format: markdown or html")
+	reportPath := fs.String("o", "", "report file to write (default report.md or report.html)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	out := NewOutput(w, *quiet, *verbose, *benchfmt)
+	w = out.Text
+	opts := bench.Options{Runs: *runs, Warmup: *warmup, Limit: *dnfAfter}
+	csvLog := report.NewCSVLog(*csvPath, p.Name())
+	benchLog := report.NewBenchLog(out.Bench, p.Name())
+	rep := report.New(info.Title, opts)
+	rep.Lesson = lesson
+
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintf(w, "EXAMPLE: %s\n", info.Title)
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	if info.Description != "" {
+		fmt.Fprintln(w, info.Description)
+	}
+
+	for _, n := range sizes {
+		fmt.Fprintf(out.Table, "\nn = %d:\n", n)
+		fmt.Fprintln(w, strings.Repeat("-", 60))
+		results, err := p.Compare(ctx, opts, p.GenerateInput(*seed, n))
+		if err != nil {
+			return err
+		}
+		if err := csvLog.Append("", uint64(n), results); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		benchLog.Append("", uint64(n), results)
+		bench.Print(out.Table, results)
+		report.WriteBars(w, results)
+		bench.PrintRuns(out.Detail, results)
+		rep.Add(fmt.Sprintf("n = %d", n), results)
+	}
+
+	report.WriteLesson(w, lesson)
+
+	if *reportFormat != "" {
+		path := *reportPath
+		if path == "" {
+			path = "report" + report.Extension(*reportFormat)
+		}
+		if err := report.WriteFile(path, *reportFormat, rep); err != nil {
+			return fmt.Errorf("report: %w", err)
+		}
+		fmt.Fprintf(w, "📝 Report written to %s\n", path)
+	}
+	return nil
+}
+
+// PluginPrefix starts the name of every plugin program: the ai-coding
+// command runs each executable on PATH called PluginPrefix + something
+// as an example.
+const PluginPrefix = "ai-coding-"
+
+// DescribeArg is the argument the ai-coding command runs a plugin
+// program with to learn about it. The program prints its Manifest as
+// JSON and exits.
+const DescribeArg = "-describe-plugin"
+
+// Manifest is how a plugin program describes itself to the ai-coding
+// command.
+type Manifest struct {
+	Name        string        `json:"name"`
+	Title       string        `json:"title"`
+	Description string        `json:"description,omitempty"`
+	Category    string        `json:"category,omitempty"`
+	Difficulty  Difficulty    `json:"difficulty,omitempty"`
+	Lesson      report.Lesson `json:"lesson"`
+}
+
+// ServePlugin is the whole main function of a plugin program: build it
+// as PluginPrefix + p.Name(), e.g. ai-coding-15-tries, and put it on
+// PATH. Run with DescribeArg, it prints p's Manifest; otherwise it runs
+// p with the program's arguments, stopping cleanly on an interrupt.
+func ServePlugin(p Plugin) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	ex := FromPlugin(p)
+	var err error
+	if len(os.Args) == 2 && os.Args[1] == DescribeArg {
+		err = json.NewEncoder(os.Stdout).Encode(Manifest{
+			Name:        ex.Name,
+			Title:       ex.Title,
+			Description: ex.Description,
+			Category:    ex.Category,
+			Difficulty:  ex.Difficulty,
+			Lesson:      ex.Lesson,
+		})
+	} else {
+		err = ex.Run(ctx, os.Stdout, os.Args[1:])
+	}
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", ex.Name, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,167 @@
+// Package examples is the registry of runnable Go examples.
+//
+// Each example package registers itself from an init function, describing
+// what it teaches and how to run it:
+//
+//	func init() {
+//		examples.Register(examples.Example{
+//			Name:       "03-sorting",
+//			Title:      "Sorting Algorithms",
+//			Category:   "sorting",
+//			Difficulty: examples.Beginner,
+//			Lesson:     lesson,
+//			Run:        Run,
+//		})
+//	}
+//
+// The lesson is declared at package level, next to the implementations
+// it describes, so that Run can print it as well.
+//
+// Programs that want every example import
+// github.com/iportilla/ai-coding/examples/all for its side effects, then
+// enumerate, filter and run them through this package.
+package examples
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/report"
+)
+
+// Difficulty is how much background an example assumes.
+type Difficulty int
+
+const (
+	Beginner Difficulty = iota + 1
+	Intermediate
+	Advanced
+)
+
+func (d Difficulty) String() string {
+	switch d {
+	case Beginner:
+		return "beginner"
+	case Intermediate:
+		return "intermediate"
+	case Advanced:
+		return "advanced"
+	}
+	return fmt.Sprintf("Difficulty(%d)", int(d))
+}
+
+// ParseDifficulty converts "beginner", "intermediate" or "advanced" (any
+// case) to a Difficulty.
+func ParseDifficulty(s string) (Difficulty, error) {
+	for _, d := range []Difficulty{Beginner, Intermediate, Advanced} {
+		if strings.EqualFold(s, d.String()) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown difficulty %q (want beginner, intermediate or advanced)", s)
+}
+
+// Example is a registered, runnable example.
+type Example struct {
+	Name        string // Directory name, e.g. "03-sorting"; also the CLI name
+	Title       string
+	Description string // One sentence on what the example teaches
+	Category    string // Algorithm family, e.g. "sorting", "number theory"
+	Difficulty  Difficulty
+
+	// Lesson is what the example teaches: a tier per implementation,
+	// with its teaching notes, and the takeaway. Run prints it as its
+	// summary with report.WriteLesson and adds it to its reports.
+	Lesson report.Lesson
+
+	// Run runs the example with command-line style arguments, writing
+	// its comparison to w. It stops early, returning ctx.Err(), once ctx
+	// is done.
+	Run func(ctx context.Context, w io.Writer, args []string) error
+
+	// Impls, if set, returns the implementations Run compares, ready to
+	// time on an input of size n. Like the impls passed to bench.Sweep,
+	// it may leave one out at sizes where it would be too slow. Tools
+	// that let the user choose n, such as `ai-coding tui`, offer only the
+	// examples that set it.
+	Impls func(n int) []bench.Implementation
+	// DefaultN is the size to start at with Impls.
+	DefaultN int
+	// MaxN, if set, is the largest n Impls honors; it times anything
+	// larger at MaxN instead.
+	MaxN int
+
+	// Exercise, if set, offers the example as a task for students with
+	// `ai-coding exercise`.
+	Exercise *Exercise
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]Example{}
+)
+
+// Register adds an example to the registry. It panics if the name is
+// empty, already registered, or the example has no Run function, since
+// those are programming errors caught on first start.
+func Register(e Example) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	switch {
+	case e.Name == "":
+		panic("examples: Register with empty name")
+	case e.Run == nil:
+		panic("examples: Register " + e.Name + " without a Run function")
+	}
+	if _, dup := registry[e.Name]; dup {
+		panic("examples: Register called twice for " + e.Name)
+	}
+	registry[e.Name] = e
+}
+
+// All returns every registered example, ordered by name.
+func All() []Example {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	all := make([]Example, 0, len(registry))
+	for _, e := range registry {
+		all = append(all, e)
+	}
+	slices.SortFunc(all, func(a, b Example) int { return strings.Compare(a.Name, b.Name) })
+	return all
+}
+
+// Lookup finds an example by its full name or by its numeric prefix, so
+// "02" finds "02-prime-algorithms".
+func Lookup(name string) (Example, error) {
+	for _, e := range All() {
+		if e.Name == name || strings.HasPrefix(e.Name, name+"-") {
+			return e, nil
+		}
+	}
+	return Example{}, fmt.Errorf("unknown example %q", name)
+}
+
+// Filter returns the registered examples matching every non-zero field
+// of the query: Category is compared case-insensitively, and Difficulty
+// must match exactly.
+func Filter(category string, difficulty Difficulty) []Example {
+	var matched []Example
+	for _, e := range All() {
+		if category != "" && !strings.EqualFold(e.Category, category) {
+			continue
+		}
+		if difficulty != 0 && e.Difficulty != difficulty {
+			continue
+		}
+		matched = append(matched, e)
+	}
+	return matched
+}
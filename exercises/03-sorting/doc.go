@@ -0,0 +1,11 @@
+// Package sorting is the exercise based on example 03: write a Sort
+// fast enough to pass its target.
+//
+// Edit solution.go, or replace it with a file of your own that defines
+//
+//	func Sort(a []int)
+//
+// then check it, and time it against the hidden expert baseline, with
+//
+//	go run ./cmd/ai-coding exercise 03
+package sorting
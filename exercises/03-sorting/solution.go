@@ -0,0 +1,20 @@
+package sorting
+
+// Sort sorts a in ascending order, in place.
+//
+// It starts out as the Vibe implementation, bubble sort, which is
+// correct but O(n²): replace it with something faster.
+func Sort(a []int) {
+	for end := len(a) - 1; end > 0; end-- {
+		swapped := false
+		for i := 0; i < end; i++ {
+			if a[i] > a[i+1] {
+				a[i], a[i+1] = a[i+1], a[i]
+				swapped = true
+			}
+		}
+		if !swapped {
+			return
+		}
+	}
+}
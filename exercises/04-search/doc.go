@@ -0,0 +1,11 @@
+// Package search is the exercise based on example 04: write a Search
+// fast enough to pass its target.
+//
+// Edit solution.go, or replace it with a file of your own that defines
+//
+//	func Search(xs []int, target int) int
+//
+// then check it, and time it against the hidden expert baseline, with
+//
+//	go run ./cmd/ai-coding exercise 04
+package search
@@ -0,0 +1,15 @@
+package search
+
+// Search returns the index of the first occurrence of target in xs,
+// which is sorted in ascending order, or -1 if target isn't there.
+//
+// It starts out as the Vibe implementation, a linear scan, which is
+// correct but O(n): replace it with something faster.
+func Search(xs []int, target int) int {
+	for i, x := range xs {
+		if x == target {
+			return i
+		}
+	}
+	return -1
+}
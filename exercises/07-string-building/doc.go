@@ -0,0 +1,11 @@
+// Package stringbuilding is the exercise based on example 07: write a
+// Join fast enough to pass its target.
+//
+// Edit solution.go, or replace it with a file of your own that defines
+//
+//	func Join(parts []string) string
+//
+// then check it, and time it against the hidden expert baseline, with
+//
+//	go run ./cmd/ai-coding exercise 07
+package stringbuilding
@@ -0,0 +1,14 @@
+package stringbuilding
+
+// Join returns the parts concatenated, in order, with nothing between
+// them.
+//
+// It starts out as the Vibe implementation, += in a loop, which is
+// correct but copies O(n²) bytes: replace it with something faster.
+func Join(parts []string) string {
+	s := ""
+	for _, p := range parts {
+		s += p
+	}
+	return s
+}
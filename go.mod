@@ -0,0 +1,3 @@
+module github.com/iportilla/ai-coding
+
+go 1.24
@@ -0,0 +1,264 @@
+// Package grade scores students' solutions to the exercises, so that
+// instructors can auto-grade submissions built on `ai-coding exercise`.
+//
+// A solution earns points on three counts, weighted by a Rubric:
+//
+//   - Correctness: it gives the same answers as the Expert
+//     implementation on the exercise's checks. Wrong answers earn
+//     nothing, since a fast wrong answer isn't worth timing.
+//   - Complexity: timed across a range of n, it grows no faster than the
+//     Expert's does. Growth is compared as the slope of the times on
+//     log-log axes, the k in n^k, which unlike the best-fitting complexity
+//     class doesn't jump between neighbours such as O(n) and O(n log n)
+//     on a little noise: up to a quarter more earns full points, up to
+//     half more earns half.
+//   - Performance: it takes at most the exercise's Target times the
+//     Expert's time at the exercise's n. Slower earns a share, e.g. a
+//     third of the points at three times the target.
+//
+// Scores for one student are gathered into a Report and saved as JSON.
+package grade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/examples"
+	"github.com/iportilla/ai-coding/input"
+)
+
+// Rubric is how many points each count is worth. It can be used as a
+// flag.Value, written as the three weights in order, e.g. "40,30,30".
+type Rubric struct {
+	Correctness float64 `json:"correctness"`
+	Complexity  float64 `json:"complexity"`
+	Performance float64 `json:"performance"`
+}
+
+// DefaultRubric weighs correctness most, then growth and speed alike,
+// out of 100.
+var DefaultRubric = Rubric{Correctness: 40, Complexity: 30, Performance: 30}
+
+// Max is the most points a solution can earn.
+func (r Rubric) Max() float64 {
+	return r.Correctness + r.Complexity + r.Performance
+}
+
+// String implements flag.Value.
+func (r *Rubric) String() string {
+	return fmt.Sprintf("%g,%g,%g", r.Correctness, r.Complexity, r.Performance)
+}
+
+// Set implements flag.Value.
+func (r *Rubric) Set(value string) error {
+	parts := strings.Split(value, ",")
+	if len(parts) != 3 {
+		return fmt.Errorf("want three weights, correctness,complexity,performance, e.g. %s", DefaultRubric.String())
+	}
+	var w [3]float64
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil || f < 0 {
+			return fmt.Errorf("bad weight %q", p)
+		}
+		w[i] = f
+	}
+	*r = Rubric{Correctness: w[0], Complexity: w[1], Performance: w[2]}
+	return nil
+}
+
+// Curves are the complexity classes solutions are told apart by, from
+// slowest-growing to fastest-growing: bench.Curves, with the sublinear
+// classes of searches and lookups in front.
+var Curves = append([]bench.Curve{
+	{Name: "O(1)", F: func(n float64) float64 { return 1 }},
+	{Name: "O(log n)", F: math.Log},
+}, bench.Curves...)
+
+// Points are what a solution earned on each count.
+type Points struct {
+	Correctness float64 `json:"correctness"`
+	Complexity  float64 `json:"complexity"`
+	Performance float64 `json:"performance"`
+}
+
+// Total adds up the points.
+func (p Points) Total() float64 {
+	return p.Correctness + p.Complexity + p.Performance
+}
+
+// Score is the grade for one exercise, with what it was based on.
+type Score struct {
+	Exercise string `json:"exercise"`
+	Correct  bool   `json:"correct"`
+	Error    string `json:"error,omitempty"` // The first wrong answer, if any
+
+	N                int           `json:"n,omitempty"`                 // Size the times are compared at
+	Median           time.Duration `json:"median_ns,omitempty"`         // The solution's median time at N
+	ExpertMedian     time.Duration `json:"expert_median_ns,omitempty"`  // The Expert's, likewise
+	Ratio            float64       `json:"ratio,omitempty"`             // Median / ExpertMedian
+	Target           float64       `json:"target"`                      // The most Ratio may be for full points
+	DNF              bool          `json:"dnf,omitempty"`               // The solution ran out of time; Ratio is a lower bound
+	Complexity       string        `json:"complexity,omitempty"`        // The curve the solution's times fit best
+	ExpertComplexity string        `json:"expert_complexity,omitempty"` // The Expert's, likewise
+	Slope            float64       `json:"slope,omitempty"`             // The solution's log-log slope
+	ExpertSlope      float64       `json:"expert_slope,omitempty"`      // The Expert's, likewise
+
+	Points Points  `json:"points"`
+	Total  float64 `json:"total"`
+	Max    float64 `json:"max"`
+}
+
+// Exercise grades the solution to ex, the exercise of the example called
+// name. It checks the solution with inputs from seed, then times it and
+// the Expert with opts at a few sizes up to ex.N. A wrong or slow
+// solution is graded, not an error; Exercise only fails if ctx is done
+// before the timing is.
+func Exercise(ctx context.Context, opts bench.Options, seed input.Seed, rubric Rubric, name string, ex *examples.Exercise) (Score, error) {
+	s := Score{Exercise: name, Target: ex.Target, Max: rubric.Max()}
+	if err := ex.Check(seed); err != nil {
+		s.Error = err.Error()
+		return s, nil
+	}
+	s.Correct = true
+	s.Points.Correctness = rubric.Correctness
+
+	sizes := bench.GeometricSizes(max(ex.N/100, 1), ex.N, 3)
+	series, err := bench.SweepContext(ctx, opts, sizes, func(n int) []bench.Implementation {
+		solution, expert := ex.Impls(n)
+		solution.Name, expert.Name = "solution", "expert"
+		return []bench.Implementation{solution, expert}
+	})
+	if err != nil {
+		return s, err
+	}
+	solution, expert := series[0], series[1]
+
+	last := solution.Results[len(solution.Results)-1]
+	s.N = solution.Sizes[len(solution.Sizes)-1]
+	s.Median, s.DNF = last.Duration, last.DNF
+	if i := slices.Index(expert.Sizes, s.N); i >= 0 {
+		s.ExpertMedian = expert.Results[i].Duration
+		s.Ratio = bench.Speedup(last, expert.Results[i])
+	}
+	if s.Ratio > 0 {
+		s.Points.Performance = rubric.Performance * min(1, ex.Target/s.Ratio)
+	}
+
+	if enoughSizes(solution) && enoughSizes(expert) {
+		s.Complexity, s.ExpertComplexity = solution.Fit(Curves...)[0].Curve.Name, expert.Fit(Curves...)[0].Curve.Name
+		s.Slope, s.ExpertSlope = slope(solution), slope(expert)
+		switch {
+		case s.Slope <= s.ExpertSlope+0.25:
+			s.Points.Complexity = rubric.Complexity
+		case s.Slope <= s.ExpertSlope+0.5:
+			s.Points.Complexity = rubric.Complexity / 2
+		}
+		s.Slope, s.ExpertSlope = math.Round(s.Slope*100)/100, math.Round(s.ExpertSlope*100)/100
+	}
+
+	s.Points = Points{round(s.Points.Correctness), round(s.Points.Complexity), round(s.Points.Performance)}
+	s.Total = round(s.Points.Total())
+	return s, nil
+}
+
+// enoughSizes reports whether s finished at three sizes or more, enough
+// to tell how it grows.
+func enoughSizes(s bench.Series) bool {
+	finished := 0
+	for _, r := range s.Results {
+		if !r.DNF {
+			finished++
+		}
+	}
+	return finished >= 3
+}
+
+// slope fits log t = k·log n + c to the finished results of s by least
+// squares and returns k: 1 for O(n), 2 for O(n²), a little over 1 for
+// O(n log n) and near 0 for O(log n).
+func slope(s bench.Series) float64 {
+	var xs, ys []float64
+	for i, r := range s.Results {
+		if !r.DNF && r.Duration > 0 {
+			xs = append(xs, math.Log(float64(s.Sizes[i])))
+			ys = append(ys, math.Log(float64(r.Duration)))
+		}
+	}
+	var mx, my float64
+	for i := range xs {
+		mx += xs[i] / float64(len(xs))
+		my += ys[i] / float64(len(ys))
+	}
+	var sxy, sxx float64
+	for i := range xs {
+		sxy += (xs[i] - mx) * (ys[i] - my)
+		sxx += (xs[i] - mx) * (xs[i] - mx)
+	}
+	if sxx == 0 {
+		return 0
+	}
+	return sxy / sxx
+}
+
+// round rounds points to one decimal place, as they are shown.
+func round(points float64) float64 {
+	return math.Round(points*10) / 10
+}
+
+// Report is one student's grades, with the machine they were timed on,
+// since the times and so the performance points depend on it.
+type Report struct {
+	Student   string    `json:"student"`
+	Graded    time.Time `json:"graded"`
+	Host      string    `json:"host"`
+	OS        string    `json:"os"`
+	Arch      string    `json:"arch"`
+	CPUs      int       `json:"cpus"`
+	GoVersion string    `json:"go_version"`
+	Rubric    Rubric    `json:"rubric"`
+	Scores    []Score   `json:"scores"`
+	Total     float64   `json:"total"`
+	Max       float64   `json:"max"`
+}
+
+// NewReport returns an empty report for student, graded with rubric on
+// this machine.
+func NewReport(student string, rubric Rubric) *Report {
+	host, _ := os.Hostname()
+	return &Report{
+		Student:   student,
+		Graded:    time.Now().UTC().Truncate(time.Second),
+		Host:      host,
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		CPUs:      runtime.NumCPU(),
+		GoVersion: runtime.Version(),
+		Rubric:    rubric,
+	}
+}
+
+// Add records s and adds it to the totals.
+func (r *Report) Add(s Score) {
+	r.Scores = append(r.Scores, s)
+	r.Total = round(r.Total + s.Total)
+	r.Max += s.Max
+}
+
+// WriteFile saves r to path as indented JSON.
+func (r *Report) WriteFile(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,81 @@
+// Package input generates the random data the examples are timed on,
+// reproducibly.
+//
+// Every dataset comes from a Seed, the dataset's name and its size, and
+// from nothing else: asking for n = 10,000 gives the same numbers
+// whether or not the run also asked for other sizes or other datasets,
+// and in whatever order. Two runs with the same -seed time the same
+// data, so their results can be compared and a failure can be replayed.
+package input
+
+import (
+	"flag"
+	"hash/fnv"
+	"math/rand/v2"
+	"strconv"
+)
+
+// DefaultSeed is the seed examples use unless -seed says otherwise, so
+// a plain run sees the same data every time.
+const DefaultSeed Seed = 1
+
+// Seed is the seed every dataset in a run derives from. It can be used
+// as a flag.Value; setting it to 0 picks a seed at random, which String
+// then reports so the run can be repeated.
+type Seed uint64
+
+// Flag defines a -seed flag on fs, defaulting to DefaultSeed.
+func Flag(fs *flag.FlagSet) *Seed {
+	s := DefaultSeed
+	fs.Var(&s, "seed", "seed for the random inputs; the same seed gives the same data (0 picks one at random)")
+	return &s
+}
+
+// String implements flag.Value.
+func (s *Seed) String() string {
+	return strconv.FormatUint(uint64(*s), 10)
+}
+
+// Set implements flag.Value.
+func (s *Seed) Set(value string) error {
+	n, err := strconv.ParseUint(value, 0, 64)
+	if err != nil {
+		return err
+	}
+	for n == 0 {
+		n = rand.Uint64()
+	}
+	*s = Seed(n)
+	return nil
+}
+
+// Rand returns a generator for the dataset called name at size n. The
+// same seed, name and n always give the same sequence, and different
+// names or sizes give unrelated ones.
+func (s Seed) Rand(name string, n int) *rand.Rand {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	h.Write(strconv.AppendInt(nil, int64(n), 10))
+	return rand.New(rand.NewPCG(uint64(s), h.Sum64()))
+}
+
+// Ints returns n integers in [0, limit), from the dataset called name.
+func (s Seed) Ints(name string, n, limit int) []int {
+	rng := s.Rand(name, n)
+	a := make([]int, n)
+	for i := range a {
+		a[i] = rng.IntN(limit)
+	}
+	return a
+}
+
+// Float64s returns n values in [-1, 1), from the dataset called name.
+func (s Seed) Float64s(name string, n int) []float64 {
+	rng := s.Rand(name, n)
+	a := make([]float64, n)
+	for i := range a {
+		a[i] = 2*rng.Float64() - 1
+	}
+	return a
+}
@@ -0,0 +1,80 @@
+package numtheory_test
+
+import (
+	"fmt"
+
+	"github.com/iportilla/ai-coding/numtheory"
+)
+
+func ExampleSubtractionGCD() {
+	fmt.Println(numtheory.SubtractionGCD(1071, 462))
+	// Output: 21
+}
+
+func ExampleEuclidGCD() {
+	fmt.Println(numtheory.EuclidGCD(1071, 462))
+	// Output: 21
+}
+
+func ExampleBinaryGCD() {
+	fmt.Println(numtheory.BinaryGCD(1071, 462))
+	// Output: 21
+}
+
+// Every implementation takes gcd(a, 0) to be a.
+func ExampleBinaryGCD_zero() {
+	fmt.Println(numtheory.BinaryGCD(12, 0), numtheory.EuclidGCD(0, 12), numtheory.SubtractionGCD(0, 0))
+	// Output: 12 12 0
+}
+
+func ExampleSubtractionSteps() {
+	// 1071 - 462 = 609, 609 - 462 = 147, then 462 - 147 three times to
+	// 21, and 147 - 21 six times to 21.
+	fmt.Println(numtheory.SubtractionSteps(1071, 462))
+
+	// Too many to take one at a time.
+	fmt.Println(numtheory.SubtractionSteps(1<<63, 3))
+	// Output:
+	// 11
+	// 3074457345618258604
+}
+
+func ExampleExtendedGCD() {
+	g, x, y := numtheory.ExtendedGCD(240, 46)
+	fmt.Println(g, x, y, 240*x+46*y)
+	// Output: 2 -9 47 2
+}
+
+func ExampleModInverse() {
+	// 3·5 = 15 ≡ 1 (mod 7), so dividing by 3 mod 7 is multiplying by 5.
+	fmt.Println(numtheory.ModInverse(3, 7))
+
+	// 4 and 6 share a factor of 2: no multiple of 4 is 1 more than a
+	// multiple of 6.
+	fmt.Println(numtheory.ModInverse(4, 6))
+
+	// Moduli past 2^63 work too: 2^64-59 is the largest prime that fits.
+	x, _ := numtheory.ModInverse(3, 1<<64-59)
+	fmt.Println(numtheory.MulMod(3, x, 1<<64-59))
+	// Output:
+	// 5 true
+	// 0 false
+	// 1
+}
+
+func ExampleMulMod() {
+	// (2^63)·4 overflows a uint64, but its remainder mod 1,000,003 doesn't.
+	fmt.Println(numtheory.MulMod(1<<63, 4, 1_000_003))
+	// Output: 701374
+}
+
+func ExamplePowMod() {
+	fmt.Println(numtheory.PowMod(4, 13, 497))
+
+	// Fermat's little theorem: a^(p-1) ≡ 1 (mod p) for a prime p, here
+	// after 61 squarings rather than 2^61 multiplications.
+	fmt.Println(numtheory.PowMod(3, 1<<61-2, 1<<61-1))
+	// Output:
+	// 445
+	// 1
+}
@@ -0,0 +1,99 @@
+package numtheory
+
+import "math/bits"
+
+// SubtractionGCD returns the greatest common divisor of a and b by
+// repeatedly subtracting the smaller from the larger, as Euclid first
+// described it.
+//
+// VIBE CODING: obviously correct - anything dividing a and b divides
+// a-b - but one step takes off only one copy of the smaller number, so
+// gcd(2^63, 3) takes about 3·10^18 of them. It is fast only when the two
+// are close in size at every step.
+func SubtractionGCD(a, b uint64) uint64 {
+	if a == 0 || b == 0 {
+		return a | b // Otherwise it would subtract 0 forever
+	}
+	for a != b {
+		if a > b {
+			a -= b
+		} else {
+			b -= a
+		}
+	}
+	return a
+}
+
+// SubtractionSteps returns how many subtractions SubtractionGCD takes
+// for a and b, without taking them: the sum of the quotients Euclid's
+// algorithm finds, less one for the final step, which reaches a = b.
+func SubtractionSteps(a, b uint64) uint64 {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	var steps uint64
+	for b != 0 {
+		steps += a / b
+		a, b = b, a%b
+	}
+	return steps - 1
+}
+
+// EuclidGCD returns the greatest common divisor of a and b by Euclid's
+// algorithm with remainders.
+//
+// HUMAN CODING: a % b takes off every copy of b at once, and the
+// remainders at least halve every two steps, so it takes O(log n)
+// divisions - at most 92 for numbers below 2^64, reached by consecutive
+// Fibonacci numbers. Each is a hardware division, the slowest integer
+// instruction there is.
+func EuclidGCD(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// BinaryGCD returns the greatest common divisor of a and b by Stein's
+// binary algorithm.
+//
+// EXPERT CODING: the factors of 2 in common come off first, counted with
+// one instruction. After that, gcd(a, b) = gcd(a, b-a) for odd a and b,
+// and b-a is even, so its factors of 2 can go too: only shifts,
+// comparisons and subtractions, and each step takes at least one bit
+// off. O(log n) steps, each cheaper than a division.
+func BinaryGCD(a, b uint64) uint64 {
+	if a == 0 || b == 0 {
+		return a | b
+	}
+	shift := bits.TrailingZeros64(a | b)
+	a >>= bits.TrailingZeros64(a)
+	for b != 0 {
+		b >>= bits.TrailingZeros64(b)
+		if a > b {
+			a, b = b, a
+		}
+		b -= a // Both odd, so b is now even, or 0
+	}
+	return a << shift
+}
+
+// ExtendedGCD returns g = gcd(a, b) together with x and y such that
+// a·x + b·y = g: the coefficients of Bézout's identity. g is never
+// negative. It is Euclid's algorithm, carrying along how each remainder
+// is made from a and b.
+func ExtendedGCD(a, b int64) (g, x, y int64) {
+	oldR, r := a, b
+	oldX, x := int64(1), int64(0)
+	oldY, y := int64(0), int64(1)
+	for r != 0 {
+		q := oldR / r
+		oldR, r = r, oldR-q*r
+		oldX, x = x, oldX-q*x
+		oldY, y = y, oldY-q*y
+	}
+	if oldR < 0 {
+		return -oldR, -oldX, -oldY
+	}
+	return oldR, oldX, oldY
+}
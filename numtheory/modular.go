@@ -0,0 +1,57 @@
+package numtheory
+
+import "math/bits"
+
+// MulMod returns a·b mod m without overflowing, using the full 128-bit
+// product. m must not be 0.
+func MulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return bits.Rem64(hi, lo, m)
+}
+
+// PowMod returns base^exp mod m by square-and-multiply: one squaring per
+// bit of exp, and one more multiplication per 1 bit, so O(log exp)
+// multiplications where multiplying base by itself exp times takes exp.
+// It is the step Miller–Rabin repeats for every base; example 16 times
+// it against repeated multiplication and math/big. m must not be 0.
+func PowMod(base, exp, m uint64) uint64 {
+	result := 1 % m
+	base %= m
+	for ; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			result = MulMod(result, base, m)
+		}
+		base = MulMod(base, base, m)
+	}
+	return result
+}
+
+// ModInverse returns the x in [0, m) with a·x ≡ 1 (mod m), and whether
+// there is one: there is exactly when gcd(a, m) = 1. It is how to divide
+// by a modulo m.
+//
+// It runs ExtendedGCD's steps on m and a, keeping only a's coefficients.
+// Those alternate in sign and never exceed m in size, so it tracks their
+// magnitudes in a uint64, and any m up to 2^64-1 works: with int64, as
+// in ExtendedGCD, m would have to stay below 2^63.
+func ModInverse(a, m uint64) (uint64, bool) {
+	if m == 0 {
+		return 0, false
+	}
+	r0, r1 := m, a%m
+	u0, u1 := uint64(0), uint64(1) // |coefficient of a| in r0 and r1
+	positive := false              // Whether r0's coefficient is +u0
+	for r1 != 0 {
+		q := r0 / r1
+		r0, r1 = r1, r0-q*r1
+		u0, u1 = u1, u0+q*u1
+		positive = !positive
+	}
+	if r0 != 1 {
+		return 0, false
+	}
+	if positive {
+		return u0 % m, true
+	}
+	return (m - u0) % m, true
+}
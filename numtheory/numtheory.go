@@ -0,0 +1,17 @@
+// Package numtheory contains the integer arithmetic the number-theory
+// examples share: greatest common divisors, modular inverses and modular
+// multiplication and exponentiation on uint64. The primes package builds
+// Miller–Rabin on it, and the factorization example Pollard's rho.
+//
+// The GCD implementations mirror the GCD example's teaching tiers:
+//
+//   - SubtractionGCD: subtract the smaller from the larger, O(a/b) steps
+//   - EuclidGCD: replace the larger by the remainder, O(log n) divisions
+//   - BinaryGCD: Stein's algorithm, shifts and subtractions, no division
+//
+// All of them return gcd(a, 0) = a, so gcd(0, 0) = 0.
+//
+// ExtendedGCD also finds the coefficients of Bézout's identity, and
+// ModInverse uses them to divide modulo m. MulMod and PowMod multiply
+// and raise to a power modulo m without overflowing.
+package numtheory
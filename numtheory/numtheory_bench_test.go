@@ -0,0 +1,56 @@
+package numtheory_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/iportilla/ai-coding/numtheory"
+)
+
+// pairs are random 64-bit pairs, the same in every run. The subtraction
+// GCD gets pairs of 16-bit numbers, since 64-bit ones can take it up to
+// 2^64 steps.
+var (
+	pairs      = randomPairs(1_024, 64)
+	smallPairs = randomPairs(1_024, 16)
+)
+
+// sink keeps the compiler from discarding benchmarked calls.
+var sink uint64
+
+func randomPairs(n, bits int) [][2]uint64 {
+	rng := rand.New(rand.NewPCG(1, 2))
+	p := make([][2]uint64, n)
+	for i := range p {
+		p[i] = [2]uint64{rng.Uint64() >> (64 - bits), rng.Uint64() >> (64 - bits)}
+	}
+	return p
+}
+
+func benchmarkGCD(b *testing.B, pairs [][2]uint64, gcd func(a, b uint64) uint64) {
+	for i := 0; i < b.N; i++ {
+		p := pairs[i%len(pairs)]
+		sink = gcd(p[0], p[1])
+	}
+}
+
+func BenchmarkSubtractionGCD(b *testing.B) {
+	benchmarkGCD(b, smallPairs, numtheory.SubtractionGCD)
+}
+
+func BenchmarkEuclidGCD(b *testing.B) {
+	b.Run("bits=16", func(b *testing.B) { benchmarkGCD(b, smallPairs, numtheory.EuclidGCD) })
+	b.Run("bits=64", func(b *testing.B) { benchmarkGCD(b, pairs, numtheory.EuclidGCD) })
+}
+
+func BenchmarkBinaryGCD(b *testing.B) {
+	b.Run("bits=16", func(b *testing.B) { benchmarkGCD(b, smallPairs, numtheory.BinaryGCD) })
+	b.Run("bits=64", func(b *testing.B) { benchmarkGCD(b, pairs, numtheory.BinaryGCD) })
+}
+
+func BenchmarkModInverse(b *testing.B) {
+	const m = 1<<61 - 1
+	for i := 0; i < b.N; i++ {
+		sink, _ = numtheory.ModInverse(pairs[i%len(pairs)][0], m)
+	}
+}
@@ -0,0 +1,73 @@
+package primes
+
+import "context"
+
+// AtkinSieve finds all prime numbers up to n with the Sieve of Atkin.
+//
+// ALTERNATIVE EXPERT CODING: instead of crossing off multiples, Atkin
+// classifies candidates by their remainder mod 12 and counts solutions
+// of three quadratic forms. A number is prime exactly when it has an odd
+// number of solutions to the form for its remainder and is squarefree,
+// so the sieve toggles a flag per solution and then clears multiples of
+// prime squares. That is O(n) operations against Eratosthenes'
+// O(n log log n) - on paper. Whether it wins in practice, with its
+// modulo arithmetic and scattered memory writes, is for the benchmark
+// to answer.
+func AtkinSieve(n int) []int {
+	primes, _ := AtkinSieveContext(context.Background(), n)
+	return primes
+}
+
+// AtkinSieveContext is like AtkinSieve but returns ctx.Err() if ctx is
+// done before it finishes.
+func AtkinSieveContext(ctx context.Context, n int) ([]int, error) {
+	if n < 2 {
+		return []int{}, nil
+	}
+
+	isPrime := make([]bool, n+1)
+
+	// Toggle every n that solves one of the forms; primes end up with an
+	// odd count. Each form only applies to certain residues mod 12.
+	for x := 1; x*x <= n; x++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for y := 1; y*y <= n; y++ {
+			m := 4*x*x + y*y
+			if m <= n && (m%12 == 1 || m%12 == 5) {
+				isPrime[m] = !isPrime[m]
+			}
+			m = 3*x*x + y*y
+			if m <= n && m%12 == 7 {
+				isPrime[m] = !isPrime[m]
+			}
+			m = 3*x*x - y*y
+			if x > y && m <= n && m%12 == 11 {
+				isPrime[m] = !isPrime[m]
+			}
+		}
+	}
+
+	// The forms also admit numbers with a squared prime factor; remove them.
+	for r := 5; r*r <= n; r++ {
+		if isPrime[r] {
+			for k := r * r; k <= n; k += r * r {
+				isPrime[k] = false
+			}
+		}
+	}
+
+	// 2 and 3 divide 12, so the forms never see them.
+	primes := []int{2}
+	if n >= 3 {
+		primes = append(primes, 3)
+	}
+	for i := 5; i <= n; i++ {
+		if isPrime[i] {
+			primes = append(primes, i)
+		}
+	}
+
+	return primes, nil // O(n) - in theory
+}
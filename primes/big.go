@@ -0,0 +1,103 @@
+package primes
+
+import (
+	"context"
+	"math/big"
+)
+
+// bigRounds is how many Miller–Rabin rounds IsPrimeBig asks
+// ProbablyPrime for above 2^64, on top of its Baillie–PSW test.
+const bigRounds = 20
+
+// bigSieveLimit bounds the small primes FindPrimesBig crosses off before
+// testing what is left. The primes below 2^16 remove about 95% of large
+// candidates, and crossing one off costs far less than a Miller–Rabin
+// round on a 256-bit number.
+const bigSieveLimit = 1 << 16
+
+// IsPrimeBig reports whether n is prime, for n of any size.
+//
+// Below 2^64 it is exact, using IsPrimeMillerRabin. Above, it is a
+// probable-prime test: math/big's ProbablyPrime with 20 Miller–Rabin
+// rounds on pseudorandom bases plus a Baillie–PSW test. A composite
+// survives the rounds with probability at most 4^-20, and no composite is
+// known to pass Baillie–PSW - the same standard cryptographic libraries
+// use when generating RSA and Diffie–Hellman primes.
+func IsPrimeBig(n *big.Int) bool {
+	if n.Sign() <= 0 {
+		return false
+	}
+	if n.IsUint64() {
+		return IsPrimeMillerRabin(n.Uint64())
+	}
+	return n.ProbablyPrime(bigRounds)
+}
+
+// FindPrimesBig returns the primes p with a ≤ p ≤ b in increasing order,
+// or an empty slice if there are none. Above 2^64 they are probable
+// primes, as decided by IsPrimeBig.
+//
+// There is no sieving everything up to a 256-bit b, so like
+// PrimesInRange it looks only at [a, b]: it crosses off multiples of the
+// primes below 2^16, then tests each survivor with IsPrimeBig. The cost
+// grows with the width of the range and the size of its numbers, not
+// with b itself.
+func FindPrimesBig(a, b *big.Int) []*big.Int {
+	primes, _ := FindPrimesBigContext(context.Background(), a, b)
+	return primes
+}
+
+// FindPrimesBigContext is like FindPrimesBig but returns ctx.Err() if ctx
+// is done before it finishes.
+func FindPrimesBigContext(ctx context.Context, a, b *big.Int) ([]*big.Int, error) {
+	primes := []*big.Int{}
+	low := new(big.Int).Set(a)
+	if low.Cmp(big.NewInt(2)) < 0 {
+		low.SetInt64(2)
+	}
+	small := ExpertFindPrimes(bigSieveLimit)
+
+	// Sieve [low, low+n) for one window at a time; a narrow range gets a
+	// window just large enough.
+	var composite []bool
+	var x, d, r big.Int
+	for low.Cmp(b) <= 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		n := SegmentSize
+		if x.Sub(b, low); x.IsInt64() && x.Int64() < int64(n) {
+			n = int(x.Int64()) + 1
+		}
+		if composite == nil {
+			composite = make([]bool, n)
+		}
+		clear(composite[:n])
+
+		for _, p := range small {
+			// The first multiple of p at or above low, but not p itself.
+			i := (p - int(r.Mod(low, d.SetInt64(int64(p))).Int64())) % p
+			if low.IsInt64() && low.Int64()+int64(i) == int64(p) {
+				i += p
+			}
+			for ; i < n; i += p {
+				composite[i] = true
+			}
+		}
+
+		for i, c := range composite[:n] {
+			if c {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			x.Add(low, d.SetInt64(int64(i)))
+			if IsPrimeBig(&x) {
+				primes = append(primes, new(big.Int).Set(&x))
+			}
+		}
+		low.Add(low, d.SetInt64(int64(n)))
+	}
+	return primes, nil
+}
@@ -0,0 +1,55 @@
+package primes
+
+import "context"
+
+// BitsetSieve finds all prime numbers up to n with a Sieve of
+// Eratosthenes stored as a bitset.
+//
+// MEMORY-EXPERT CODING: ExpertFindPrimes spends a whole byte on every
+// number up to n. Here each candidate costs a single bit, and only odd
+// candidates are stored at all - 16x less sieve memory for the same
+// O(n log log n) work. Bit i of the set stands for the odd number 2i+1
+// and is set once that number is known to be composite.
+func BitsetSieve(n int) []int {
+	primes, _ := BitsetSieveContext(context.Background(), n)
+	return primes
+}
+
+// BitsetSieveContext is like BitsetSieve but returns ctx.Err() if ctx is
+// done before it finishes.
+func BitsetSieveContext(ctx context.Context, n int) ([]int, error) {
+	if n < 2 {
+		return []int{}, nil
+	}
+
+	odds := (n + 1) / 2 // odd numbers 1, 3, ..., ≤ n
+	composite := make([]uint64, (odds+63)/64)
+
+	isComposite := func(num int) bool {
+		i := num / 2
+		return composite[i/64]&(1<<(i%64)) != 0
+	}
+
+	for p := 3; p*p <= n; p += 2 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if isComposite(p) {
+			continue
+		}
+		// Even multiples of p are never stored, so step by 2p.
+		for m := p * p; m <= n; m += 2 * p {
+			i := m / 2
+			composite[i/64] |= 1 << (i % 64)
+		}
+	}
+
+	primes := []int{2}
+	for num := 3; num <= n; num += 2 {
+		if !isComposite(num) {
+			primes = append(primes, num)
+		}
+	}
+
+	return primes, nil // O(n log log n) time, n/16 bytes of sieve
+}
@@ -0,0 +1,106 @@
+package primes
+
+import (
+	"context"
+	"iter"
+	"slices"
+	"sync"
+)
+
+// Cache remembers the primes it has found, so that calls with growing n
+// extend one sieve instead of sieving from zero each time: a dashboard
+// where n is nudged up a step at a time, or a loop asking for more and
+// more primes. A call with n at or below the cache's limit only looks the
+// answer up; a call above it sieves the numbers between the two, window
+// by window like SegmentedSieve, and at least doubles the limit, so a
+// run of calls each a little above the last costs about as much in total
+// as one sieve up to the largest n.
+//
+// The zero value is an empty cache ready to use. A Cache is safe for
+// concurrent use. It never forgets, so it holds every prime up to its
+// limit: about n/ln n ints, 5.7 MiB at n = 10^7.
+type Cache struct {
+	mu     sync.Mutex
+	limit  int   // every prime ≤ limit is in primes
+	primes []int // in increasing order
+}
+
+// FindPrimes returns the primes ≤ n in increasing order, or an empty
+// slice if n < 2, like ExpertFindPrimes. The slice shares the cache's
+// memory and must not be modified.
+func (c *Cache) FindPrimes(n int) []int {
+	primes, _ := c.FindPrimesContext(context.Background(), n)
+	return primes
+}
+
+// FindPrimesContext is like FindPrimes but returns ctx.Err() if ctx is
+// done before it finishes. The cache keeps none of an extension that was
+// interrupted.
+func (c *Cache) FindPrimesContext(ctx context.Context, n int) ([]int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if n > c.limit {
+		if err := c.extend(ctx, max(n, 2*c.limit)); err != nil {
+			return nil, err
+		}
+	}
+	i, _ := slices.BinarySearch(c.primes, n+1) // the primes ≤ n come before it
+	if i == 0 {
+		return []int{}, nil
+	}
+	return c.primes[:i:i], nil
+}
+
+// Limit returns the largest n the cache can answer without sieving.
+func (c *Cache) Limit() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.limit
+}
+
+// All returns an iterator over every prime, like the package's All, that
+// reads the cache and extends it as the loop climbs past its limit, so
+// the second loop over the first k primes finds them already sieved.
+func (c *Cache) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			c.mu.Lock()
+			for i >= len(c.primes) {
+				c.extend(context.Background(), max(2*c.limit, 1024))
+			}
+			p := c.primes[i]
+			c.mu.Unlock()
+			if !yield(p) {
+				return
+			}
+		}
+	}
+}
+
+// extend sieves the numbers above c.limit up to n and adds the primes
+// found. The caller must hold c.mu.
+func (c *Cache) extend(ctx context.Context, n int) error {
+	if n < 2 {
+		c.limit = n
+		return nil
+	}
+	found := len(c.primes)
+	if c.limit < 2 {
+		c.primes = append(c.primes, 2)
+	}
+	low := max(c.limit+1, 3)
+	if low%2 == 0 {
+		low++
+	}
+	window := max(min(SegmentSize, (n-low)/2+1), 1)
+	err := sieveOddRange(ctx, low, n, oddBasePrimes(n), make([]bool, window), func(p int) bool {
+		c.primes = append(c.primes, p)
+		return true
+	})
+	if err != nil {
+		c.primes = c.primes[:found]
+		return err
+	}
+	c.limit = n
+	return nil
+}
@@ -0,0 +1,209 @@
+package primes_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/iportilla/ai-coding/primes"
+)
+
+func ExampleVibeFindPrimes() {
+	fmt.Println(primes.VibeFindPrimes(30))
+	// Output: [2 3 5 7 11 13 17 19 23 29]
+}
+
+func ExampleHumanFindPrimes() {
+	fmt.Println(primes.HumanFindPrimes(30))
+	// Output: [2 3 5 7 11 13 17 19 23 29]
+}
+
+func ExampleExpertFindPrimes() {
+	fmt.Println(primes.ExpertFindPrimes(30))
+	// Output: [2 3 5 7 11 13 17 19 23 29]
+}
+
+// Every implementation returns an empty slice when there are no primes.
+func ExampleExpertFindPrimes_noPrimes() {
+	fmt.Println(len(primes.ExpertFindPrimes(1)), len(primes.ExpertFindPrimes(-5)))
+	// Output: 0 0
+}
+
+func ExampleWheelSieve() {
+	fmt.Println(primes.WheelSieve(30))
+	// Output: [2 3 5 7 11 13 17 19 23 29]
+}
+
+func ExampleAtkinSieve() {
+	fmt.Println(primes.AtkinSieve(30))
+	// Output: [2 3 5 7 11 13 17 19 23 29]
+}
+
+func ExampleSegmentedSieve() {
+	fmt.Println(primes.SegmentedSieve(30))
+	// Output: [2 3 5 7 11 13 17 19 23 29]
+}
+
+func ExampleCountPrimes() {
+	fmt.Println(primes.CountPrimes(1_000_000))
+	// Output: 78498
+}
+
+func ExampleVibeFindPrimesContext() {
+	// O(n²) at n = 10^9 would run for days; give up after 10ms instead.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := primes.VibeFindPrimesContext(ctx, 1_000_000_000)
+	fmt.Println(err)
+	// Output: context deadline exceeded
+}
+
+func ExampleUpTo() {
+	for p := range primes.UpTo(50) {
+		if p > 20 {
+			fmt.Print(p, " ")
+		}
+	}
+	fmt.Println()
+	// Output: 23 29 31 37 41 43 47
+}
+
+func ExampleAll() {
+	// The first prime above ten million, without sieving up to a guessed
+	// bound first.
+	for p := range primes.All() {
+		if p > 10_000_000 {
+			fmt.Println(p)
+			break
+		}
+	}
+	// Output: 10000019
+}
+
+func ExampleFirst() {
+	fmt.Println(primes.First(10))
+	// Output: [2 3 5 7 11 13 17 19 23 29]
+}
+
+func ExamplePrimesInRange() {
+	// Sieves 100 numbers near 10^12, not the trillion below them.
+	fmt.Println(primes.PrimesInRange(1_000_000_000_000, 1_000_000_000_100))
+	// Output: [1000000000039 1000000000061 1000000000063 1000000000091]
+}
+
+func ExampleBitsetSieve() {
+	fmt.Println(primes.BitsetSieve(30))
+	// Output: [2 3 5 7 11 13 17 19 23 29]
+}
+
+func ExampleParallelSieve() {
+	fmt.Println(primes.ParallelSieve(30, 4))
+	// Output: [2 3 5 7 11 13 17 19 23 29]
+}
+
+func ExampleIsPrimeMillerRabin() {
+	// 3215031751 = 151·751·28351 fools Miller–Rabin with bases 2, 3, 5
+	// and 7, but not the full deterministic base set.
+	fmt.Println(primes.IsPrimeMillerRabin(3215031751))
+	fmt.Println(primes.IsPrimeMillerRabin(1<<61 - 1))
+	// Output:
+	// false
+	// true
+}
+
+func ExamplePowMod() {
+	fmt.Println(primes.PowMod(4, 13, 497))
+
+	// Fermat's little theorem: a^(p-1) ≡ 1 (mod p) for a prime p, here
+	// after 61 squarings rather than 2^61 multiplications.
+	fmt.Println(primes.PowMod(3, 1<<61-2, 1<<61-1))
+	// Output:
+	// 445
+	// 1
+}
+
+func ExampleIsPrimeBig() {
+	// 2^255 - 19, the prime behind Curve25519.
+	p := new(big.Int).Lsh(big.NewInt(1), 255)
+	p.Sub(p, big.NewInt(19))
+	fmt.Println(primes.IsPrimeBig(p))
+
+	// The product of two Mersenne primes has no small factors to find.
+	q := new(big.Int).Lsh(big.NewInt(1), 127)
+	q.Sub(q, big.NewInt(1))
+	fmt.Println(primes.IsPrimeBig(q.Mul(q, big.NewInt(1<<61-1))))
+	// Output:
+	// true
+	// false
+}
+
+func ExampleFindPrimesBig() {
+	// The first primes past the end of uint64.
+	a := new(big.Int).Lsh(big.NewInt(1), 64)
+	b := new(big.Int).Add(a, big.NewInt(100))
+	for _, p := range primes.FindPrimesBig(a, b) {
+		fmt.Println("2^64 +", new(big.Int).Sub(p, a))
+	}
+	// Output:
+	// 2^64 + 13
+	// 2^64 + 37
+	// 2^64 + 51
+	// 2^64 + 81
+	// 2^64 + 93
+}
+
+func ExampleVerify() {
+	buggy := primes.Implementation{
+		Name: "OffByOne",
+		Find: func(n int) []int { return primes.ExpertFindPrimes(n - 1) },
+	}
+	fmt.Println(primes.Verify(100, primes.Implementations()...))
+	fmt.Println(primes.Verify(97, buggy))
+	// Output:
+	// <nil>
+	// OffByOne(97): found 24 primes, want 25; first missing is 97
+}
+
+func ExampleTwinPrimes() {
+	for p, q := range primes.TwinPrimes(primes.UpTo(50)) {
+		fmt.Print("(", p, ", ", q, ") ")
+	}
+	fmt.Println()
+	// Output: (3, 5) (5, 7) (11, 13) (17, 19) (29, 31) (41, 43)
+}
+
+func ExampleAnalyzeGaps() {
+	s := primes.AnalyzeGaps(primes.UpTo(1_000_000))
+	fmt.Println("twin pairs:", s.Twins)
+	fmt.Println("largest gap:", s.Largest.Size, "after", s.Largest.After)
+	fmt.Printf("mean gap: %.2f\n", s.MeanGap())
+	// Output:
+	// twin pairs: 8169
+	// largest gap: 114 after 492113
+	// mean gap: 12.74
+}
+
+// A Cache answers a call at or below what it has sieved without sieving
+// again, and extends its sieve for one above.
+func ExampleCache() {
+	var c primes.Cache
+	fmt.Println(c.FindPrimes(20))
+	fmt.Println(c.FindPrimes(10), c.Limit())
+	fmt.Println(c.FindPrimes(50), c.Limit())
+	// Output:
+	// [2 3 5 7 11 13 17 19]
+	// [2 3 5 7] 20
+	// [2 3 5 7 11 13 17 19 23 29 31 37 41 43 47] 50
+}
+
+func ExampleIncrementalSieve() {
+	for p := range primes.IncrementalSieve() {
+		if p > 1_000_000 {
+			fmt.Println("first prime above a million:", p)
+			break
+		}
+	}
+	// Output: first prime above a million: 1000003
+}
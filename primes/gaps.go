@@ -0,0 +1,78 @@
+package primes
+
+import "iter"
+
+// Gap is the distance from one prime to the next.
+type Gap struct {
+	After int // The smaller prime
+	Size  int // Next prime minus After
+}
+
+// GapStats describes the gaps between consecutive primes, as collected by
+// AnalyzeGaps.
+type GapStats struct {
+	Primes    int         // Primes seen
+	Last      int         // The largest prime seen
+	Twins     int         // Pairs of primes (p, p+2)
+	LastTwin  int         // Smaller member of the last twin pair, or 0 if none
+	Largest   Gap         // The largest gap; the first, if several are as large
+	Records   []Gap       // Maximal gaps: each larger than every gap before it
+	Histogram map[int]int // Number of gaps of each size
+
+	first int // The smallest prime seen
+}
+
+// MeanGap returns the average gap between consecutive primes seen, or 0
+// if there were fewer than two.
+func (s GapStats) MeanGap() float64 {
+	if s.Primes < 2 {
+		return 0
+	}
+	return float64(s.Last-s.first) / float64(s.Primes-1)
+}
+
+// AnalyzeGaps collects GapStats from ps, which must yield primes in
+// increasing order with none skipped, such as UpTo(n) or
+// slices.Values(ExpertFindPrimes(n)). It keeps only the statistics, not
+// the primes, so it runs in O(1) memory beyond the histogram.
+func AnalyzeGaps(ps iter.Seq[int]) GapStats {
+	s := GapStats{Histogram: map[int]int{}}
+	for p := range ps {
+		s.Primes++
+		prev := s.Last
+		s.Last = p
+		if s.Primes == 1 {
+			s.first = p
+			continue
+		}
+		g := Gap{After: prev, Size: p - prev}
+		s.Histogram[g.Size]++
+		if g.Size == 2 {
+			s.Twins++
+			s.LastTwin = prev
+		}
+		if g.Size > s.Largest.Size {
+			s.Largest = g
+			s.Records = append(s.Records, g)
+		}
+	}
+	return s
+}
+
+// TwinPrimes returns an iterator over the twin prime pairs (p, p+2) among
+// ps, which must yield primes in increasing order with none skipped:
+//
+//	for p, q := range primes.TwinPrimes(primes.UpTo(100)) {
+//		fmt.Println(p, q) // 3 5, 5 7, 11 13, ...
+//	}
+func TwinPrimes(ps iter.Seq[int]) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		prev := 0
+		for p := range ps {
+			if prev > 0 && p-prev == 2 && !yield(prev, p) {
+				return
+			}
+			prev = p
+		}
+	}
+}
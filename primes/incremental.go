@@ -0,0 +1,63 @@
+package primes
+
+import "iter"
+
+// IncrementalSieve returns an iterator over every prime, 2, 3, 5, 7, ...
+// with no upper bound, found by the incremental Sieve of Eratosthenes.
+//
+// Where the bounded sieves cross off every multiple of a prime at once in
+// a table up to n, the incremental sieve keeps a map from each upcoming
+// composite to the step between the odd multiples of the prime that
+// crosses it off, and walks the odd numbers one by one: a number in the
+// map is composite, so its prime moves on to its next odd multiple not
+// already taken; a number not in the map is prime. A prime joins the map
+// only once the walk reaches its square, and the primes to add come from
+// a second incremental sieve running to √ of where this one is, so the
+// map never holds more than the primes up to √p. After yielding the
+// primes up to p it holds O(√p / log p) entries, on all levels together.
+//
+// It costs O(log log n) map operations per number, against the bounded
+// sieve's O(log log n) slice writes: the same growth, with a much larger
+// constant, the price of not needing n in advance. All gets the same
+// freedom more cheaply by sieving windows; example 02 compares the three
+// per prime found.
+func IncrementalSieve() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for _, p := range []int{2, 3, 5, 7} {
+			if !yield(p) {
+				return
+			}
+		}
+
+		// The base primes, from 3 up; a new prime joins the map when the
+		// walk reaches its square q.
+		base, stop := iter.Pull(IncrementalSieve())
+		defer stop()
+		base()
+		p, _ := base()
+		q := p * p
+		next := map[int]int{} // upcoming odd composite → 2·its prime
+
+		for c := 9; ; c += 2 {
+			step, composite := next[c]
+			switch {
+			case composite:
+				delete(next, c)
+			case c < q:
+				if !yield(c) {
+					return
+				}
+				continue
+			default: // c == q: start crossing off multiples of p
+				step = 2 * p
+				p, _ = base()
+				q = p * p
+			}
+			m := c + step
+			for next[m] != 0 {
+				m += step // taken by a smaller prime; it's composite either way
+			}
+			next[m] = step
+		}
+	}
+}
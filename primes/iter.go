@@ -0,0 +1,74 @@
+package primes
+
+import (
+	"context"
+	"iter"
+)
+
+// UpTo returns an iterator over the primes up to n in increasing order.
+// Unlike the slice-returning finders it never holds more than one sieve
+// window in memory, and stopping the loop early stops the sieving:
+//
+//	for p := range primes.UpTo(1_000_000_000) {
+//		if p%10_000 == 9_999 {
+//			fmt.Println(p)
+//			break
+//		}
+//	}
+func UpTo(n int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		SegmentedSieveFunc(n, yield)
+	}
+}
+
+// All returns an iterator over every prime, 2, 3, 5, 7, ... with no
+// upper bound. Use it when the bound isn't known in advance, such as
+// "the first k primes" or "the first prime after x"; the loop decides
+// when to stop.
+//
+// All sieves one window at a time like SegmentedSieve, and widens its
+// table of base primes as the windows climb, so after yielding primes up
+// to p it holds O(√p) base primes plus a single window.
+func All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		if !yield(2) {
+			return
+		}
+
+		composite := make([]bool, SegmentSize)
+		var base []int
+		baseLimit := 0 // base holds the odd primes up to √baseLimit
+		for low := 3; ; low += 2 * SegmentSize {
+			high := low + 2*(SegmentSize-1)
+			if high > baseLimit {
+				// Overshoot so the base primes are recomputed only a
+				// logarithmic number of times.
+				baseLimit = 4 * high
+				base = oddBasePrimes(baseLimit)
+			}
+			stopped := false
+			sieveOddRange(context.Background(), low, high, base, composite, func(p int) bool {
+				stopped = !yield(p)
+				return !stopped
+			})
+			if stopped {
+				return
+			}
+		}
+	}
+}
+
+// First returns the first k primes, or an empty slice if k ≤ 0.
+func First(k int) []int {
+	primes := make([]int, 0, max(k, 0))
+	if k <= 0 {
+		return primes
+	}
+	for p := range All() {
+		primes = append(primes, p)
+		if len(primes) == k {
+			break
+		}
+	}
+	return primes
+}
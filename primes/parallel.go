@@ -0,0 +1,77 @@
+package primes
+
+import (
+	"context"
+	"sync"
+)
+
+// ParallelSieve finds all primes up to n by splitting the range into
+// disjoint chunks and sieving them on separate goroutines.
+//
+// CONCURRENT CODING: every chunk only needs the shared, read-only base
+// primes up to √n, so workers never coordinate while marking - each owns
+// its window buffer and its output slice, and the chunks are stitched
+// together in order at the end. That is the best case for parallelism,
+// yet for small n spawning goroutines and merging results still costs
+// more than the sieving itself.
+func ParallelSieve(n, workers int) []int {
+	primes, _ := ParallelSieveContext(context.Background(), n, workers)
+	return primes
+}
+
+// ParallelSieveContext is like ParallelSieve but returns ctx.Err() if
+// ctx is done before every worker finishes. Each worker checks the
+// context once per window.
+func ParallelSieveContext(ctx context.Context, n, workers int) ([]int, error) {
+	if n < 2 {
+		return []int{}, nil
+	}
+	workers = max(workers, 1)
+
+	base := oddBasePrimes(n)
+
+	// Split the odd numbers in [3, n] into one chunk per worker, but never
+	// hand a worker less than a full window - tiny chunks are all overhead.
+	odds := (n - 1) / 2
+	perWorker := max((odds+workers-1)/workers, SegmentSize)
+
+	var chunks [][]int
+	for low := 3; low <= n; low += 2 * perWorker {
+		chunks = append(chunks, nil)
+	}
+
+	errs := make([]error, len(chunks))
+	var wg sync.WaitGroup
+	for i := range chunks {
+		low := 3 + 2*perWorker*i
+		high := min(low+2*(perWorker-1), n)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			found := []int{}
+			errs[i] = sieveOddRange(ctx, low, high, base, make([]bool, SegmentSize), func(p int) bool {
+				found = append(found, p)
+				return true
+			})
+			chunks[i] = found
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	total := 1
+	for _, c := range chunks {
+		total += len(c)
+	}
+	primes := make([]int, 0, total)
+	primes = append(primes, 2)
+	for _, c := range chunks {
+		primes = append(primes, c...)
+	}
+	return primes, nil
+}
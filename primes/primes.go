@@ -0,0 +1,180 @@
+// Package primes contains the prime-finding implementations used by the
+// prime algorithms example, exported so other programs and student
+// exercises can import them directly.
+//
+// The three implementations mirror the example's teaching tiers:
+//
+//   - VibeFindPrimes: quick implementation without optimization, O(n²)
+//   - HumanFindPrimes: trial division up to √n, skipping evens, O(n√n)
+//   - ExpertFindPrimes: Sieve of Eratosthenes, O(n log log n)
+//
+// All of them return the primes ≤ n in increasing order, and an empty
+// (non-nil) slice when n < 2.
+//
+// Each finder has a Context variant, e.g. VibeFindPrimesContext, that
+// checks its context as it goes and gives up with ctx.Err() once the
+// context is cancelled or its deadline passes - at large n even the
+// expert sieve runs for seconds, and the vibe one for hours.
+//
+// UpTo and All yield primes lazily instead of building a slice, for
+// callers that want only the first few primes or don't know the bound
+// in advance.
+//
+// A Cache remembers the primes found so far, for callers asking again
+// and again with a growing n.
+//
+// IsPrimeTrialDivision and IsPrimeMillerRabin test a single uint64.
+// Miller–Rabin is built on the modular arithmetic of package numtheory,
+// which PowMod and MulMod here forward to.
+//
+// IsPrimeBig and FindPrimesBig work on math/big integers, for numbers
+// past uint64 such as the 256-bit primes of cryptography.
+//
+// AnalyzeGaps and TwinPrimes look at what the finders produce: twin
+// primes, the largest gap between primes, and how the gaps are
+// distributed.
+package primes
+
+import (
+	"context"
+	"math"
+)
+
+// cancelCheckInterval is how many candidates the trial-division finders
+// test between context checks: often enough to stop within milliseconds,
+// rarely enough that the check never shows up in a timing.
+const cancelCheckInterval = 1 << 10
+
+// VibeFindPrimes finds all prime numbers up to n - simple but inefficient.
+//
+// VIBE CODING: quick implementation without optimization. Every candidate
+// is tested against every smaller number.
+func VibeFindPrimes(n int) []int {
+	primes, _ := VibeFindPrimesContext(context.Background(), n)
+	return primes
+}
+
+// VibeFindPrimesContext is like VibeFindPrimes but returns ctx.Err()
+// if ctx is done before it finishes.
+func VibeFindPrimesContext(ctx context.Context, n int) ([]int, error) {
+	primes := []int{}
+
+	for num := 2; num <= n; num++ {
+		if num%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		isPrime := true
+
+		// Check if num is divisible by any number from 2 to num-1
+		for i := 2; i < num; i++ {
+			if num%i == 0 {
+				isPrime = false
+				break
+			}
+		}
+
+		if isPrime {
+			primes = append(primes, num)
+		}
+	}
+
+	return primes, nil // O(n²) - very slow for large n!
+}
+
+// HumanFindPrimes finds all prime numbers up to n using an optimized
+// trial division.
+//
+// HUMAN CODING: optimized implementation with mathematical insights:
+//  1. Only check divisibility up to sqrt(num)
+//  2. Skip even numbers after 2
+//  3. Early exit when divisor found
+func HumanFindPrimes(n int) []int {
+	primes, _ := HumanFindPrimesContext(context.Background(), n)
+	return primes
+}
+
+// HumanFindPrimesContext is like HumanFindPrimes but returns ctx.Err()
+// if ctx is done before it finishes.
+func HumanFindPrimesContext(ctx context.Context, n int) ([]int, error) {
+	if n < 2 {
+		return []int{}, nil
+	}
+
+	primes := []int{2} // Start with 2, the only even prime
+
+	// Only check odd numbers
+	for num := 3; num <= n; num += 2 {
+		if num%(2*cancelCheckInterval) == 1 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		isPrime := true
+		sqrtNum := int(math.Sqrt(float64(num)))
+
+		// Only need to check up to square root of num
+		for i := 3; i <= sqrtNum; i += 2 {
+			if num%i == 0 {
+				isPrime = false
+				break
+			}
+		}
+
+		if isPrime {
+			primes = append(primes, num)
+		}
+	}
+
+	return primes, nil // Much faster: O(n√n) with constant factor improvements
+}
+
+// ExpertFindPrimes finds all prime numbers up to n using the Sieve of
+// Eratosthenes.
+//
+// EXPERT CODING: the classic algorithm, and the most efficient way to find
+// all primes up to n when O(n) memory is affordable.
+func ExpertFindPrimes(n int) []int {
+	primes, _ := ExpertFindPrimesContext(context.Background(), n)
+	return primes
+}
+
+// ExpertFindPrimesContext is like ExpertFindPrimes but returns ctx.Err()
+// if ctx is done before it finishes.
+func ExpertFindPrimesContext(ctx context.Context, n int) ([]int, error) {
+	if n < 2 {
+		return []int{}, nil
+	}
+
+	// Create slice of boolean values, initially all true
+	isPrime := make([]bool, n+1)
+	for i := range isPrime {
+		isPrime[i] = true
+	}
+	isPrime[0] = false
+	isPrime[1] = false
+
+	// Sieve algorithm
+	for i := 2; i*i <= n; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if isPrime[i] {
+			// Mark all multiples of i as not prime
+			for j := i * i; j <= n; j += i {
+				isPrime[j] = false
+			}
+		}
+	}
+
+	// Collect all numbers that are still marked as prime
+	primes := []int{}
+	for i := 2; i <= n; i++ {
+		if isPrime[i] {
+			primes = append(primes, i)
+		}
+	}
+
+	return primes, nil // O(n log log n) - optimal for this problem!
+}
@@ -0,0 +1,103 @@
+package primes_test
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/iportilla/ai-coding/primes"
+)
+
+// Sizes are powers of ten so results line up across implementations in
+// benchstat; the vibe version stops early because each step up costs it
+// roughly 100x.
+var (
+	vibeSizes  = []int{100, 1_000, 10_000}
+	humanSizes = []int{100, 1_000, 10_000, 100_000}
+	sieveSizes = []int{100, 1_000, 10_000, 100_000, 1_000_000}
+)
+
+// sink keeps the compiler from discarding benchmarked calls.
+var sink []int
+
+func benchmarkFind(b *testing.B, sizes []int, find func(int) []int) {
+	for _, n := range sizes {
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sink = find(n)
+			}
+		})
+	}
+}
+
+func BenchmarkVibeFindPrimes(b *testing.B) {
+	benchmarkFind(b, vibeSizes, primes.VibeFindPrimes)
+}
+
+func BenchmarkHumanFindPrimes(b *testing.B) {
+	benchmarkFind(b, humanSizes, primes.HumanFindPrimes)
+}
+
+func BenchmarkExpertFindPrimes(b *testing.B) {
+	benchmarkFind(b, sieveSizes, primes.ExpertFindPrimes)
+}
+
+func BenchmarkBitsetSieve(b *testing.B) {
+	benchmarkFind(b, sieveSizes, primes.BitsetSieve)
+}
+
+func BenchmarkWheelSieve(b *testing.B) {
+	benchmarkFind(b, sieveSizes, primes.WheelSieve)
+}
+
+func BenchmarkAtkinSieve(b *testing.B) {
+	benchmarkFind(b, sieveSizes, primes.AtkinSieve)
+}
+
+func BenchmarkSegmentedSieve(b *testing.B) {
+	benchmarkFind(b, sieveSizes, primes.SegmentedSieve)
+}
+
+func BenchmarkIncrementalSieve(b *testing.B) {
+	benchmarkFind(b, sieveSizes, func(n int) []int {
+		var found []int
+		for p := range primes.IncrementalSieve() {
+			if p > n {
+				break
+			}
+			found = append(found, p)
+		}
+		return found
+	})
+}
+
+func BenchmarkParallelSieve(b *testing.B) {
+	benchmarkFind(b, sieveSizes, func(n int) []int {
+		return primes.ParallelSieve(n, runtime.GOMAXPROCS(0))
+	})
+}
+
+// BenchmarkStepUp asks for the primes up to n = 10,000, 20,000, ...,
+// 1,000,000 in turn, as a dashboard does when n is nudged up a step at a
+// time: from scratch every time, and from a Cache that extends its sieve.
+func BenchmarkStepUp(b *testing.B) {
+	const step, last = 10_000, 1_000_000
+	b.Run("ExpertFindPrimes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for n := step; n <= last; n += step {
+				sink = primes.ExpertFindPrimes(n)
+			}
+		}
+	})
+	b.Run("Cache", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var c primes.Cache
+			for n := step; n <= last; n += step {
+				sink = c.FindPrimes(n)
+			}
+		}
+	})
+}
@@ -0,0 +1,187 @@
+package primes
+
+import (
+	"context"
+	"math"
+)
+
+// SegmentSize is the number of odd candidates the segmented sieve marks
+// per window. 1<<18 bools is 256 KiB - small enough to stay in L2 cache,
+// large enough that the per-window overhead of walking the base primes
+// stays negligible.
+const SegmentSize = 1 << 18
+
+// SegmentedSieve returns all primes up to n using a segmented Sieve of
+// Eratosthenes.
+//
+// The sieve itself needs only O(√n) memory; the returned slice is of
+// course proportional to the number of primes found. Use
+// SegmentedSieveFunc or CountPrimes when even that is too much.
+func SegmentedSieve(n int) []int {
+	primes, _ := SegmentedSieveContext(context.Background(), n)
+	return primes
+}
+
+// SegmentedSieveContext is like SegmentedSieve but returns ctx.Err() if
+// ctx is done before it finishes. The context is checked once per window.
+func SegmentedSieveContext(ctx context.Context, n int) ([]int, error) {
+	primes := []int{}
+	err := segmentedSieve(ctx, n, func(p int) bool {
+		primes = append(primes, p)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return primes, nil
+}
+
+// CountPrimes returns π(n), the number of primes up to n, without
+// storing them. This is how the example reaches n = 10^10: a plain sieve
+// would need a 10 GB bool slice, the segmented one needs a few hundred KB.
+func CountPrimes(n int) int {
+	count, _ := CountPrimesContext(context.Background(), n)
+	return count
+}
+
+// CountPrimesContext is like CountPrimes but returns ctx.Err() if ctx is
+// done before it finishes - counting to 10^10 takes a while.
+func CountPrimesContext(ctx context.Context, n int) (int, error) {
+	count := 0
+	err := segmentedSieve(ctx, n, func(int) bool {
+		count++
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// SegmentedSieveFunc calls yield for every prime up to n in increasing
+// order, stopping early if yield returns false.
+//
+// EXPERT CODING: instead of one n-sized table, sieve fixed-size windows
+// [low, low+2·SegmentSize) one after another. Only the base primes up to
+// √n (found with a small classic sieve) and a single window are kept in
+// memory, and only odd numbers are stored since 2 is the only even prime.
+func SegmentedSieveFunc(n int, yield func(p int) bool) {
+	segmentedSieve(context.Background(), n, yield)
+}
+
+// segmentedSieve is SegmentedSieveFunc with cancellation: it stops with
+// ctx.Err() if ctx is done before the last window is sieved.
+func segmentedSieve(ctx context.Context, n int, yield func(p int) bool) error {
+	if n < 2 || !yield(2) {
+		return nil
+	}
+	// Small n don't need a full-sized window: there are (n-1)/2 odd
+	// candidates in [3, n].
+	window := max(min(SegmentSize, (n-1)/2), 1)
+	return sieveOddRange(ctx, 3, n, oddBasePrimes(n), make([]bool, window), yield)
+}
+
+// isqrt returns ⌊√n⌋ for n ≥ 0, correcting float64 rounding for large n.
+func isqrt(n int) int {
+	r := int(math.Sqrt(float64(n)))
+	for r*r > n {
+		r--
+	}
+	for (r+1)*(r+1) <= n {
+		r++
+	}
+	return r
+}
+
+// oddBasePrimes returns the odd primes up to √n. These are the only
+// factors ever needed to cross off composites anywhere below n.
+func oddBasePrimes(n int) []int {
+	base := ExpertFindPrimes(isqrt(n))
+	if len(base) > 0 {
+		base = base[1:] // drop 2
+	}
+	return base
+}
+
+// sieveOddRange calls yield for every odd prime in [low, high], sieving
+// one window of len(composite) odd numbers at a time. low must be odd
+// and ≥ 3, and base must hold the odd primes up to √high. It returns
+// ctx.Err() if ctx is done before a window starts, and nil when the
+// range is finished or yield returns false.
+func sieveOddRange(ctx context.Context, low, high int, base []int, composite []bool, yield func(p int) bool) error {
+	for ; low <= high; low += 2 * len(composite) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		windowHigh := min(low+2*(len(composite)-1), high)
+		clear(composite) // composite[i] describes the odd number low + 2*i
+
+		for _, p := range base {
+			if p*p > windowHigh {
+				break
+			}
+			// First odd multiple of p inside the window, never below p²
+			// (smaller multiples were crossed off by smaller primes).
+			start := p * p
+			if start < low {
+				start = (low + p - 1) / p * p
+				if start%2 == 0 {
+					start += p
+				}
+			}
+			for m := start; m <= windowHigh; m += 2 * p {
+				composite[(m-low)/2] = true
+			}
+		}
+
+		for i := 0; low+2*i <= windowHigh; i++ {
+			if !composite[i] && !yield(low+2*i) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// PrimesInRange returns the primes p with a ≤ p ≤ b in increasing order,
+// or an empty slice if there are none.
+//
+// Only [a, b] is sieved, using the base primes up to √b, so a narrow
+// range far from zero is cheap: the primes between 10^12 and
+// 10^12 + 10^6 need a million-number window and the 78,498 primes below
+// 10^6, not a sieve over everything below 10^12.
+func PrimesInRange(a, b int) []int {
+	primes, _ := PrimesInRangeContext(context.Background(), a, b)
+	return primes
+}
+
+// PrimesInRangeContext is like PrimesInRange but returns ctx.Err() if ctx
+// is done before it finishes.
+func PrimesInRangeContext(ctx context.Context, a, b int) ([]int, error) {
+	primes := []int{}
+	if b < 2 || a > b {
+		return primes, nil
+	}
+	if a <= 2 {
+		primes = append(primes, 2)
+	}
+
+	low := max(a, 3)
+	if low%2 == 0 {
+		low++
+	}
+	if low > b {
+		return primes, nil
+	}
+
+	// A short range doesn't need a full-sized window.
+	composite := make([]bool, min(SegmentSize, (b-low)/2+1))
+	err := sieveOddRange(ctx, low, b, oddBasePrimes(b), composite, func(p int) bool {
+		primes = append(primes, p)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return primes, nil
+}
@@ -0,0 +1,121 @@
+package primes
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"runtime"
+	"slices"
+)
+
+// Implementation names one of the package's prime-finding functions so
+// callers can run, time or cross-check all of them uniformly.
+type Implementation struct {
+	Name string
+	Find func(n int) []int
+
+	// FindContext, if set, is the cancellable form of Find and is used
+	// by VerifyContext in its place.
+	FindContext func(ctx context.Context, n int) ([]int, error)
+}
+
+// Implementations returns every prime-finding implementation in the
+// package, slowest first.
+func Implementations() []Implementation {
+	parallel := func(ctx context.Context, n int) ([]int, error) {
+		return ParallelSieveContext(ctx, n, runtime.NumCPU())
+	}
+	return []Implementation{
+		{"VibeFindPrimes", VibeFindPrimes, VibeFindPrimesContext},
+		{"HumanFindPrimes", HumanFindPrimes, HumanFindPrimesContext},
+		{"ExpertFindPrimes", ExpertFindPrimes, ExpertFindPrimesContext},
+		{"BitsetSieve", BitsetSieve, BitsetSieveContext},
+		{"WheelSieve", WheelSieve, WheelSieveContext},
+		{"AtkinSieve", AtkinSieve, AtkinSieveContext},
+		{"SegmentedSieve", SegmentedSieve, SegmentedSieveContext},
+		{"ParallelSieve", func(n int) []int { return ParallelSieve(n, runtime.NumCPU()) }, parallel},
+		{"UpTo", func(n int) []int { return slices.AppendSeq([]int{}, UpTo(n)) }, nil},
+		{"All", func(n int) []int { return upTo(All(), n) }, nil},
+		{"IncrementalSieve", func(n int) []int { return upTo(IncrementalSieve(), n) }, nil},
+		{"Cache", cacheGrowth, nil},
+		{"PrimesInRange", rangeSplit, nil},
+	}
+}
+
+// rangeSplit finds the primes up to n with two adjoining PrimesInRange
+// queries, so Verify also exercises ranges that start part-way up.
+func rangeSplit(n int) []int {
+	mid := n / 3
+	return append(PrimesInRange(0, mid), PrimesInRange(mid+1, n)...)
+}
+
+// cacheGrowth finds the primes up to n with a new Cache asked for n/3
+// and n/2 first, so Verify exercises the cache both extending itself and
+// answering from primes sieved past the n asked for.
+func cacheGrowth(n int) []int {
+	var c Cache
+	c.FindPrimes(n / 3)
+	c.FindPrimes(n / 2) // sieves up to 2n/3
+	return c.FindPrimes(n)
+}
+
+// upTo collects the primes up to n from ps, one of the unbounded
+// iterators, so Verify exercises their growing tables of base primes.
+func upTo(ps iter.Seq[int], n int) []int {
+	primes := []int{}
+	for p := range ps {
+		if p > n {
+			break
+		}
+		primes = append(primes, p)
+	}
+	return primes
+}
+
+// Verify runs each implementation for n and compares its output with
+// ExpertFindPrimes. It returns an error describing the first
+// implementation that disagrees, or nil if they all match.
+//
+// A fast but wrong implementation is worse than a slow one, so the
+// examples verify before they time anything.
+func Verify(n int, impls ...Implementation) error {
+	return VerifyContext(context.Background(), n, impls...)
+}
+
+// VerifyContext is like Verify but returns ctx.Err() if ctx is done
+// before every implementation has been checked.
+func VerifyContext(ctx context.Context, n int, impls ...Implementation) error {
+	want, err := ExpertFindPrimesContext(ctx, n)
+	if err != nil {
+		return err
+	}
+	for _, impl := range impls {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var got []int
+		if impl.FindContext != nil {
+			if got, err = impl.FindContext(ctx, n); err != nil {
+				return err
+			}
+		} else {
+			got = impl.Find(n)
+		}
+		if slices.Equal(got, want) {
+			continue
+		}
+		i := 0
+		for i < len(got) && i < len(want) && got[i] == want[i] {
+			i++
+		}
+		switch {
+		case i < len(got) && i < len(want):
+			return fmt.Errorf("%s(%d): prime #%d is %d, want %d", impl.Name, n, i+1, got[i], want[i])
+		case i < len(got):
+			return fmt.Errorf("%s(%d): found %d primes, want %d; first extra is %d", impl.Name, n, len(got), len(want), got[i])
+		default:
+			return fmt.Errorf("%s(%d): found %d primes, want %d; first missing is %d", impl.Name, n, len(got), len(want), want[i])
+		}
+	}
+	return nil
+}
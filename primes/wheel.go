@@ -0,0 +1,79 @@
+package primes
+
+import "context"
+
+// The 2-3-5 wheel: of every 30 consecutive numbers only the 8 with these
+// remainders mod 30 are coprime to 2, 3 and 5, so only they can be prime
+// (apart from 2, 3 and 5 themselves).
+var wheelResidues = [8]int{1, 7, 11, 13, 17, 19, 23, 29}
+
+// wheelIndex maps a remainder mod 30 to its position in wheelResidues,
+// or -1 for the 22 remainders the wheel skips.
+var wheelIndex = func() (index [30]int) {
+	for i := range index {
+		index[i] = -1
+	}
+	for i, r := range wheelResidues {
+		index[r] = i
+	}
+	return index
+}()
+
+// WheelCandidateFraction is the share of numbers a 2-3-5 wheel sieve
+// stores and scans: 8/30 ≈ 26.7%, i.e. about 73% fewer candidates than
+// a plain sieve.
+const WheelCandidateFraction = 8.0 / 30.0
+
+// WheelSieve finds all prime numbers up to n with a Sieve of
+// Eratosthenes over a 2-3-5 wheel.
+//
+// EXPERT+ CODING: skipping even numbers halves the work; also skipping
+// multiples of 3 and 5 leaves 8 candidates in every 30. The sieve table
+// holds only those candidates, and since the product of two candidates
+// is again a candidate, crossing off p·q for candidates q ≥ p never
+// touches a number the wheel already excluded. Same O(n log log n) time
+// as ExpertFindPrimes - only the constant factor shrinks.
+func WheelSieve(n int) []int {
+	primes, _ := WheelSieveContext(context.Background(), n)
+	return primes
+}
+
+// WheelSieveContext is like WheelSieve but returns ctx.Err() if ctx is
+// done before it finishes.
+func WheelSieveContext(ctx context.Context, n int) ([]int, error) {
+	primes := []int{}
+	for _, p := range []int{2, 3, 5} {
+		if p <= n {
+			primes = append(primes, p)
+		}
+	}
+	if n < 7 {
+		return primes, nil
+	}
+
+	// Candidate i stands for the number value(i); candidate 0 is 1.
+	value := func(i int) int { return i/8*30 + wheelResidues[i%8] }
+	index := func(m int) int { return m/30*8 + wheelIndex[m%30] }
+	composite := make([]bool, (n/30+1)*8) // every candidate below the next multiple of 30
+
+	for i := 1; value(i)*value(i) <= n; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if composite[i] {
+			continue
+		}
+		p := value(i)
+		for j := i; p*value(j) <= n; j++ {
+			composite[index(p*value(j))] = true
+		}
+	}
+
+	for i := 1; value(i) <= n; i++ {
+		if !composite[i] {
+			primes = append(primes, value(i))
+		}
+	}
+
+	return primes, nil // O(n log log n), over 8/30 of the numbers
+}
@@ -0,0 +1,82 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/iportilla/ai-coding/bench"
+	"github.com/iportilla/ai-coding/style"
+)
+
+// terminalBarWidth is the length, in characters, of the slowest
+// result's bar in WriteBars.
+const terminalBarWidth = 40
+
+// eighths are the block characters from one to eight eighths wide, so a
+// bar can end part-way through a character cell.
+var eighths = []rune("▏▎▍▌▋▊▉█")
+
+// WriteBars draws each result's median duration as a row of block
+// characters proportional to the slowest one, for terminals. Like the
+// HTML report's charts it uses a linear scale: next to a 100x slower
+// algorithm, the fast one is a sliver. A result that did not finish is
+// drawn at its time limit, the least it would have taken. On a writer
+// marked by style.Color the bars take the HTML report's colors: green
+// for the fastest, red for the slowest and yellow in between. Results
+// measured against a target are green if they reach it and red if not,
+// and a dotted row below them shows the target itself.
+func WriteBars(w io.Writer, results []bench.Result) {
+	s := Section{Results: results}
+	target := s.Target()
+	var slowest float64
+	nameWidth := 0
+	for _, r := range results {
+		slowest = max(slowest, float64(r.Duration))
+		nameWidth = max(nameWidth, utf8.RuneCountInString(r.Name))
+	}
+	scale := max(slowest, float64(target))
+	if target > 0 {
+		nameWidth = max(nameWidth, len("Target"))
+	}
+
+	fmt.Fprintln(w)
+	for i, r := range results {
+		bar := terminalBar(float64(r.Duration), scale)
+		color := style.Yellow
+		switch {
+		case target > 0 && r.OnTarget():
+			color = style.Green
+		case target > 0:
+			color = style.Red
+		case i == s.Fastest():
+			color = style.Green
+		case float64(r.Duration) == slowest:
+			color = style.Red
+		}
+		pad := strings.Repeat(" ", terminalBarWidth-utf8.RuneCountInString(bar))
+		fmt.Fprintf(w, "  %-*s │%s%s %s (%s)\n", nameWidth, r.Name,
+			color.Paint(w, bar), pad, timing(r), s.Relative(i))
+	}
+	if target > 0 {
+		line := strings.Repeat("┄", max(utf8.RuneCountInString(terminalBar(float64(target), scale)), 1))
+		pad := strings.Repeat(" ", terminalBarWidth-utf8.RuneCountInString(line))
+		fmt.Fprintf(w, "  %-*s │%s%s %.4fms\n", nameWidth, "Target", line, pad, float64(target)/float64(time.Millisecond))
+	}
+}
+
+// terminalBar returns a bar of block characters for d, out of
+// terminalBarWidth characters for scale.
+func terminalBar(d, scale float64) string {
+	units := 1 // keep even the fastest bar visible
+	if scale > 0 {
+		units = max(units, int(d/scale*terminalBarWidth*8))
+	}
+	bar := strings.Repeat(string(eighths[7]), units/8)
+	if units%8 > 0 {
+		bar += string(eighths[units%8-1])
+	}
+	return bar
+}
@@ -0,0 +1,160 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Baseline is a saved set of median timings, one per example, input,
+// size and algorithm, to compare later runs against. It is built from the
+// rows a CSVLog writes and stored as JSON.
+type Baseline struct {
+	Created   time.Time        `json:"created"`
+	Host      string           `json:"host"`
+	OS        string           `json:"os"`
+	Arch      string           `json:"arch"`
+	CPUs      int              `json:"cpus"`
+	GoVersion string           `json:"go_version"`
+	Results   []BaselineResult `json:"results"`
+}
+
+// BaselineResult is one algorithm's median time on one input.
+type BaselineResult struct {
+	Example   string        `json:"example"`
+	Input     string        `json:"input,omitempty"`
+	N         uint64        `json:"n"`
+	Algorithm string        `json:"algorithm"`
+	Median    time.Duration `json:"median_ns"`
+}
+
+// Key identifies what r measured, for matching it across baselines.
+func (r BaselineResult) Key() string {
+	return fmt.Sprintf("%s/%s/n=%d/%s", r.Example, r.Input, r.N, r.Algorithm)
+}
+
+// BaselineFromCSV builds a Baseline from CSV rows written by CSVLog,
+// taking the machine details from the first row. If the same result
+// appears more than once, the last row wins.
+func BaselineFromCSV(r io.Reader) (*Baseline, error) {
+	rows, err := ReadCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("no results recorded")
+	}
+
+	first := rows[0]
+	b := &Baseline{
+		Created:   first.Started,
+		Host:      first.Host,
+		OS:        first.OS,
+		Arch:      first.Arch,
+		CPUs:      first.CPUs,
+		GoVersion: first.GoVersion,
+	}
+	index := map[string]int{}
+	for _, row := range rows {
+		res := BaselineResult{
+			Example:   row.Example,
+			Input:     row.Input,
+			N:         row.N,
+			Algorithm: row.Algorithm,
+			Median:    row.Median,
+		}
+		if j, ok := index[res.Key()]; ok {
+			b.Results[j] = res
+			continue
+		}
+		index[res.Key()] = len(b.Results)
+		b.Results = append(b.Results, res)
+	}
+	return b, nil
+}
+
+// WriteFile saves b to path as indented JSON.
+func (b *Baseline) WriteFile(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// ReadBaseline loads a baseline saved by WriteFile.
+func ReadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &b, nil
+}
+
+// Change is one result whose median moved by more than the threshold
+// passed to CompareBaselines.
+type Change struct {
+	Old, New BaselineResult
+}
+
+// Ratio is how many times longer the new run took, e.g. 1.5 for 50%
+// slower or 0.5 for twice as fast.
+func (c Change) Ratio() float64 {
+	return float64(c.New.Median) / float64(c.Old.Median)
+}
+
+// Regression reports whether the new run was slower.
+func (c Change) Regression() bool {
+	return c.New.Median > c.Old.Median
+}
+
+// Comparison is the outcome of comparing a run against a baseline.
+type Comparison struct {
+	Changes   []Change         // Results that moved by more than the threshold, in cur's order
+	Matched   int              // Results present in both
+	Unmatched []BaselineResult // Results in cur with nothing to compare against
+}
+
+// Regressions returns how many of the changes are regressions.
+func (c Comparison) Regressions() int {
+	n := 0
+	for _, ch := range c.Changes {
+		if ch.Regression() {
+			n++
+		}
+	}
+	return n
+}
+
+// CompareBaselines matches every result in cur with the same result in
+// old and collects those whose median moved by more than threshold, a
+// fraction such as 0.2 for 20%, in either direction. Both directions
+// matter for teaching: a slower expert implementation and a faster vibe
+// one both shrink the gap an example is meant to show.
+func CompareBaselines(old, cur *Baseline, threshold float64) Comparison {
+	byKey := map[string]BaselineResult{}
+	for _, r := range old.Results {
+		byKey[r.Key()] = r
+	}
+	var c Comparison
+	for _, r := range cur.Results {
+		o, ok := byKey[r.Key()]
+		if !ok || o.Median <= 0 {
+			c.Unmatched = append(c.Unmatched, r)
+			continue
+		}
+		c.Matched++
+		ch := Change{Old: o, New: r}
+		if ratio := ch.Ratio(); ratio > 1+threshold || ratio < 1/(1+threshold) {
+			c.Changes = append(c.Changes, ch)
+		}
+	}
+	return c
+}
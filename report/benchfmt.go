@@ -0,0 +1,78 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+)
+
+// BenchLog writes results in the Go benchmark format that `go test
+// -bench` prints, so they can be piped into benchstat to test whether a
+// difference between two runs, or two machines, is significant:
+//
+//	ai-coding run 03 -benchfmt > old.txt
+//	... change something ...
+//	ai-coding run 03 -benchfmt > new.txt
+//	benchstat old.txt new.txt
+//
+// Each measured run becomes its own line with an iteration count of 1,
+// the way `go test -count` repeats a benchmark, so benchstat sees every
+// sample rather than only the median.
+type BenchLog struct {
+	W       io.Writer // Where the lines go
+	Example string    // Example name, e.g. "03-sorting"
+	header  bool      // Whether the configuration lines have been written
+}
+
+// NewBenchLog returns a log writing to w for the named example, or nil
+// if w is nil. Like a nil *CSVLog, a nil *BenchLog discards everything.
+func NewBenchLog(w io.Writer, example string) *BenchLog {
+	if w == nil {
+		return nil
+	}
+	return &BenchLog{W: w, Example: example}
+}
+
+// Append writes one line per measured run of every result, all measured
+// at size n. input describes the kind of input as for CSVLog.Append; it
+// may be empty. Like CSVLog, it leaves out results that did not finish.
+func (l *BenchLog) Append(input string, n uint64, results []bench.Result) {
+	if l == nil {
+		return
+	}
+	if !l.header {
+		fmt.Fprintf(l.W, "goos: %s\ngoarch: %s\ngo: %s\nexample: %s\n",
+			runtime.GOOS, runtime.GOARCH, runtime.Version(), l.Example)
+		l.header = true
+	}
+	for _, r := range results {
+		if r.DNF {
+			continue
+		}
+		name := BenchmarkName(l.Example, input, n, r.Name)
+		for _, s := range r.Samples {
+			fmt.Fprintf(l.W, "%s\t1\t%d ns/op\t%d B/op\t%d allocs/op\n", name, s.Nanoseconds(), r.Bytes, r.Allocs)
+		}
+	}
+}
+
+// BenchmarkName returns the benchmark name for one algorithm, e.g.
+// "Benchmark03-sorting/reversed/n=1000/Human_coding-8", with spaces
+// replaced so the name is a single field. Like `go test`, it ends in
+// -GOMAXPROCS when that is above 1.
+func BenchmarkName(example, input string, n uint64, algorithm string) string {
+	parts := []string{"Benchmark" + example}
+	if input != "" {
+		parts = append(parts, input)
+	}
+	parts = append(parts, "n="+strconv.FormatUint(n, 10), algorithm)
+	name := strings.Join(strings.Fields(strings.Join(parts, "/")), "_")
+	if procs := runtime.GOMAXPROCS(0); procs > 1 {
+		name += "-" + strconv.Itoa(procs)
+	}
+	return name
+}
@@ -0,0 +1,186 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+)
+
+// CSVHeader is the first row of every file written by CSVLog. Durations
+// are in nanoseconds; bytes and allocs are per run.
+var CSVHeader = []string{
+	"run_started", "host", "os", "arch", "cpus", "go_version",
+	"example", "input", "algorithm", "complexity", "n",
+	"runs", "median_ns", "min_ns", "mean_ns", "stddev_ns", "bytes", "allocs",
+}
+
+// CSVLog appends one row per measured result to a CSV file, so that runs
+// repeated on different machines - a whole class's laptops, say - can be
+// concatenated and compared in a spreadsheet. Every row carries the
+// machine it was measured on and the time its run started.
+type CSVLog struct {
+	Path    string    // File to append to; created with a CSVHeader row if new
+	Example string    // Example name, e.g. "03-sorting"
+	Started time.Time // Shared by every row of one run
+}
+
+// NewCSVLog returns a log appending to path for the named example, or
+// nil if path is empty. A nil *CSVLog discards everything, so examples
+// can call Append unconditionally.
+func NewCSVLog(path, example string) *CSVLog {
+	if path == "" {
+		return nil
+	}
+	return &CSVLog{Path: path, Example: example, Started: time.Now()}
+}
+
+// Append writes one row per result, all measured at size n. input
+// describes the kind of input when an example uses several at the same
+// n, e.g. "reversed"; it may be empty. Results that did not finish have
+// no timing to record and are left out.
+func (l *CSVLog) Append(input string, n uint64, results []bench.Result) error {
+	if l == nil {
+		return nil
+	}
+
+	f, err := os.OpenFile(l.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	host, _ := os.Hostname()
+	w := csv.NewWriter(f)
+	if info.Size() == 0 {
+		w.Write(CSVHeader)
+	}
+	for _, r := range results {
+		if r.DNF {
+			continue
+		}
+		w.Write([]string{
+			l.Started.UTC().Format(time.RFC3339),
+			host,
+			runtime.GOOS,
+			runtime.GOARCH,
+			strconv.Itoa(runtime.NumCPU()),
+			runtime.Version(),
+			l.Example,
+			input,
+			r.Name,
+			r.Complexity,
+			strconv.FormatUint(n, 10),
+			strconv.Itoa(r.Stats.Runs),
+			strconv.FormatInt(int64(r.Duration), 10),
+			strconv.FormatInt(int64(r.Stats.Min), 10),
+			strconv.FormatInt(int64(r.Stats.Mean), 10),
+			strconv.FormatInt(int64(r.Stats.StdDev), 10),
+			strconv.FormatUint(r.Bytes, 10),
+			strconv.FormatUint(r.Allocs, 10),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// CSVRow is one row written by CSVLog: one algorithm's timing on one
+// input, and the machine it was measured on.
+type CSVRow struct {
+	Started    time.Time     `json:"run_started"`
+	Host       string        `json:"host"`
+	OS         string        `json:"os"`
+	Arch       string        `json:"arch"`
+	CPUs       int           `json:"cpus"`
+	GoVersion  string        `json:"go_version"`
+	Example    string        `json:"example"`
+	Input      string        `json:"input,omitempty"`
+	Algorithm  string        `json:"algorithm"`
+	Complexity string        `json:"complexity"`
+	N          uint64        `json:"n"`
+	Runs       int           `json:"runs"`
+	Median     time.Duration `json:"median_ns"`
+	Min        time.Duration `json:"min_ns"`
+	Mean       time.Duration `json:"mean_ns"`
+	StdDev     time.Duration `json:"stddev_ns"`
+	Bytes      uint64        `json:"bytes"`
+	Allocs     uint64        `json:"allocs"`
+}
+
+// ReadCSV parses the rows written by CSVLog, finding each column by its
+// name in the header row. Empty input has no rows.
+func ReadCSV(r io.Reader) ([]CSVRow, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+	col := map[string]int{}
+	for i, name := range records[0] {
+		col[name] = i
+	}
+	for _, name := range CSVHeader {
+		if _, ok := col[name]; !ok {
+			return nil, fmt.Errorf("CSV has no %q column", name)
+		}
+	}
+
+	rows := make([]CSVRow, 0, len(records)-1)
+	for i, rec := range records[1:] {
+		var err error
+		field := func(name string) string { return rec[col[name]] }
+		integer := func(name string) int64 {
+			x, e := strconv.ParseInt(field(name), 10, 64)
+			if e != nil && err == nil {
+				err = fmt.Errorf("row %d: %s: %w", i+2, name, e)
+			}
+			return x
+		}
+		unsigned := func(name string) uint64 {
+			x, e := strconv.ParseUint(field(name), 10, 64)
+			if e != nil && err == nil {
+				err = fmt.Errorf("row %d: %s: %w", i+2, name, e)
+			}
+			return x
+		}
+		row := CSVRow{
+			Host:       field("host"),
+			OS:         field("os"),
+			Arch:       field("arch"),
+			CPUs:       int(integer("cpus")),
+			GoVersion:  field("go_version"),
+			Example:    field("example"),
+			Input:      field("input"),
+			Algorithm:  field("algorithm"),
+			Complexity: field("complexity"),
+			N:          unsigned("n"),
+			Runs:       int(integer("runs")),
+			Median:     time.Duration(integer("median_ns")),
+			Min:        time.Duration(integer("min_ns")),
+			Mean:       time.Duration(integer("mean_ns")),
+			StdDev:     time.Duration(integer("stddev_ns")),
+			Bytes:      unsigned("bytes"),
+			Allocs:     unsigned("allocs"),
+		}
+		if err != nil {
+			return nil, err
+		}
+		if row.Started, err = time.Parse(time.RFC3339, field("run_started")); err != nil {
+			return nil, fmt.Errorf("row %d: run_started: %w", i+2, err)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
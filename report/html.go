@@ -0,0 +1,158 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+)
+
+// Bar chart geometry, in SVG user units.
+const (
+	chartLabelWidth = 200
+	chartBarWidth   = 420
+	chartValueWidth = 160
+	chartRowHeight  = 28
+	chartBarHeight  = 18
+)
+
+// bar is one row of a section's bar chart.
+type bar struct {
+	Label, Value string
+	Y, Width     int
+	Color        string
+}
+
+// chart is the data for one inline SVG bar chart.
+type chart struct {
+	Width, Height int
+	Bars          []bar
+}
+
+// barChart draws each result's median duration as a bar proportional to
+// the slowest one. A linear scale is deliberate: a 100x gap should look
+// like a 100x gap. Against a target, results that reach it are green and
+// the others red, and a grey bar below them shows the target.
+func barChart(s Section) chart {
+	target := s.Target()
+	var slowest float64
+	for _, r := range s.Results {
+		slowest = max(slowest, float64(r.Duration))
+	}
+	scale := max(slowest, float64(target))
+	fastest := s.Fastest()
+
+	c := chart{Width: chartLabelWidth + chartBarWidth + chartValueWidth}
+	add := func(label, value string, d float64, color string) {
+		width := 2 // keep even the fastest bar visible
+		if scale > 0 {
+			width = max(width, int(d/scale*chartBarWidth))
+		}
+		c.Bars = append(c.Bars, bar{
+			Label: label,
+			Value: value,
+			Y:     len(c.Bars)*chartRowHeight + 4,
+			Width: width,
+			Color: color,
+		})
+	}
+	for i, r := range s.Results {
+		color := "#f0ad4e" // amber
+		switch {
+		case target > 0 && r.OnTarget():
+			color = "#5cb85c" // green
+		case target > 0:
+			color = "#d9534f" // red
+		case i == fastest:
+			color = "#5cb85c" // green
+		case float64(r.Duration) == slowest:
+			color = "#d9534f" // red
+		}
+		add(r.Name, fmt.Sprintf("%s (%s)", timing(r), s.Relative(i)), float64(r.Duration), color)
+	}
+	if target > 0 {
+		add("Target", fmt.Sprintf("%.4fms", float64(target)/float64(time.Millisecond)), float64(target), "#999999")
+	}
+	c.Height = len(c.Bars)*chartRowHeight + 4
+	return c
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"chart": barChart,
+	"bytes": bench.FormatBytes,
+	"ms": func(r bench.Result) string {
+		if r.DNF {
+			return r.DNFLabel()
+		}
+		return fmt.Sprintf("%.4f", r.Milliseconds())
+	},
+	"msOf":        func(d time.Duration) float64 { return d.Seconds() * 1000 },
+	"env":         environment,
+	"options":     describeOptions,
+	"labelX":      func() int { return chartLabelWidth - 8 },
+	"barX":        func() int { return chartLabelWidth },
+	"valueX":      func(b bar) int { return chartLabelWidth + b.Width + 8 },
+	"textY":       func(b bar) int { return b.Y + chartBarHeight - 5 },
+	"barHeight":   func() int { return chartBarHeight },
+	"highlighted": func(s Section, i int) bool { return s.best(i) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  body { font-family: system-ui, sans-serif; max-width: 900px; margin: 2em auto; padding: 0 1em; color: #222; }
+  h1 { margin-bottom: 0.2em; }
+  .meta { color: #666; font-size: 0.9em; }
+  table { border-collapse: collapse; margin: 1em 0; font-size: 0.9em; }
+  th, td { border: 1px solid #ddd; padding: 0.3em 0.6em; }
+  th { background: #f5f5f5; }
+  td.num { text-align: right; font-variant-numeric: tabular-nums; }
+  tr.fastest { font-weight: bold; background: #eef8ee; }
+  .note { color: #8a6d3b; background: #fcf8e3; padding: 0.4em 0.8em; border-left: 4px solid #f0ad4e; }
+  ul.notes { list-style: none; padding-left: 0.5em; }
+  .takeaway { border-left: 4px solid #5cb85c; padding: 0.4em 0.8em; background: #eef8ee; }
+  svg text { font-size: 13px; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">Generated {{.Generated.Format "2006-01-02 15:04 MST"}} on {{env}} · {{options .Options}}</p>
+{{range .Sections}}{{$section := .}}
+<h2>{{.Title}}</h2>
+{{with chart .}}<svg width="{{.Width}}" height="{{.Height}}" viewBox="0 0 {{.Width}} {{.Height}}" role="img" aria-label="Median time per implementation">
+{{range .Bars}}  <text x="{{labelX}}" y="{{textY .}}" text-anchor="end">{{.Label}}</text>
+  <rect x="{{barX}}" y="{{.Y}}" width="{{.Width}}" height="{{barHeight}}" fill="{{.Color}}"></rect>
+  <text x="{{valueX .}}" y="{{textY .}}">{{.Value}}</text>
+{{end}}</svg>{{end}}
+<table>
+<tr><th>Implementation</th><th>Complexity</th><th>Median (ms)</th><th>Allocated</th><th>Allocs</th><th>GCs</th><th>GC pause (ms)</th><th>Relative</th></tr>
+{{range $i, $r := .Results}}<tr{{if highlighted $section $i}} class="fastest"{{end}}><td>{{$r.Name}}</td><td>{{$r.Complexity}}</td><td class="num">{{ms $r}}</td><td class="num">{{bytes $r.Bytes}}</td><td class="num">{{$r.Allocs}}</td><td class="num">{{printf "%.1f" $r.GCsPerRun}}</td><td class="num">{{printf "%.4f" (msOf $r.GCPausePerRun)}}</td><td>{{$section.Relative $i}}</td></tr>
+{{end}}</table>
+{{range .Notes}}<p class="note">{{.}}</p>
+{{end}}{{end}}
+{{if .EdgeCases}}<h2>Edge Cases</h2>
+<table>
+<tr><th>Input</th><th>Result</th></tr>
+{{range .EdgeCases}}<tr><td>{{.Input}}</td><td><code>{{.Output}}</code></td></tr>
+{{end}}</table>{{end}}
+{{with .Lesson}}{{if or .Tiers .Takeaway}}<h2>Summary</h2>
+{{range .Tiers}}<h3>{{.Heading}}</h3>
+<ul class="notes">
+{{range .Notes}}  <li>{{.Kind.Marker}} {{.Text}}</li>
+{{end}}</ul>
+{{end}}{{if .Takeaway}}<p class="takeaway"><strong>Key takeaway:</strong> {{.Takeaway}}</p>
+{{end}}{{end}}{{end}}
+</body>
+</html>
+`))
+
+// WriteHTML renders r as a self-contained HTML page with an inline SVG
+// bar chart per section and the lesson at the end - no scripts or
+// external assets, so the file can be opened offline or attached to an
+// LMS as is.
+func WriteHTML(w io.Writer, r *Report) error {
+	return htmlTemplate.Execute(w, r)
+}
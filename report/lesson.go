@@ -0,0 +1,150 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Lesson is what an example teaches: a Tier for each implementation,
+// annotated with notes, and the takeaway for the example as a whole.
+// Examples declare it next to the code it describes and register it, so
+// the summary printed by WriteLesson and the one in a Report's Markdown
+// or HTML can't drift apart.
+type Lesson struct {
+	Tiers    []Tier `json:"tiers"`
+	Takeaway string `json:"takeaway,omitempty"`
+}
+
+// Tier describes one of an example's implementations.
+type Tier struct {
+	Label      string `json:"label"`      // Teaching tier, e.g. "Vibe coding"
+	Approach   string `json:"approach"`   // What the implementation does, e.g. "Bubble sort"
+	Complexity string `json:"complexity"` // e.g. "O(n²)"
+	Notes      []Note `json:"notes,omitempty"`
+}
+
+// NoteKind says whether a Note counts for an implementation, against
+// it, or is advice about it.
+type NoteKind int
+
+const (
+	StrengthNote NoteKind = iota + 1
+	PitfallNote
+	TipNote
+)
+
+func (k NoteKind) String() string {
+	switch k {
+	case StrengthNote:
+		return "strength"
+	case PitfallNote:
+		return "pitfall"
+	case TipNote:
+		return "tip"
+	}
+	return fmt.Sprintf("NoteKind(%d)", int(k))
+}
+
+// MarshalText encodes the kind by name, e.g. in the JSON API.
+func (k NoteKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText decodes a kind encoded by MarshalText.
+func (k *NoteKind) UnmarshalText(text []byte) error {
+	for _, kind := range []NoteKind{StrengthNote, PitfallNote, TipNote} {
+		if string(text) == kind.String() {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown note kind %q", text)
+}
+
+// Marker is the symbol a note of this kind is shown with.
+func (k NoteKind) Marker() string {
+	switch k {
+	case StrengthNote:
+		return "✅"
+	case PitfallNote:
+		return "❌"
+	}
+	return "💡"
+}
+
+// Note is one teaching point about an implementation.
+type Note struct {
+	Kind NoteKind `json:"kind"`
+	Text string   `json:"text"`
+}
+
+// Strength returns a note on something an implementation does well.
+func Strength(text string) Note { return Note{StrengthNote, text} }
+
+// Pitfall returns a note on a cost or a trap of an implementation.
+func Pitfall(text string) Note { return Note{PitfallNote, text} }
+
+// Tip returns a note of advice, e.g. an alternative worth knowing.
+func Tip(text string) Note { return Note{TipNote, text} }
+
+// Heading names the tier with what it does and how it scales, e.g.
+// "Vibe coding: bubble sort, O(n²)".
+func (t Tier) Heading() string {
+	return fmt.Sprintf("%s: %s, %s", t.Label, t.Approach, t.Complexity)
+}
+
+// lessonWidth is the column WriteLesson wraps notes at.
+const lessonWidth = 72
+
+// WriteLesson prints l as the SUMMARY block that ends an example's
+// terminal output: each tier's notes under its heading, then the
+// takeaway, wrapped to fit a terminal.
+func WriteLesson(w io.Writer, l Lesson) {
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 60))
+	fmt.Fprintln(w, "SUMMARY")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	for _, t := range l.Tiers {
+		fmt.Fprintf(w, "\n%s (%s, %s):\n", strings.ToUpper(t.Label), t.Approach, t.Complexity)
+		for _, note := range t.Notes {
+			fmt.Fprint(w, wrap(note.Text, note.Kind.Marker()+" ", "   "))
+		}
+	}
+	if l.Takeaway != "" {
+		fmt.Fprintln(w, "\nKey Takeaway:")
+		fmt.Fprint(w, wrap(l.Takeaway, "", ""))
+	}
+}
+
+// wrap breaks text into lines of at most lessonWidth columns, starting
+// the first with first and the rest with indent. Emoji count as two
+// columns, as terminals draw them.
+func wrap(text, first, indent string) string {
+	var b strings.Builder
+	line, width := first, columns(first)
+	for i, word := range strings.Fields(text) {
+		if i > 0 && width+1+columns(word) > lessonWidth {
+			b.WriteString(line + "\n")
+			line, width = indent+word, columns(indent)+columns(word)
+			continue
+		}
+		if i > 0 {
+			line, width = line+" ", width+1
+		}
+		line, width = line+word, width+columns(word)
+	}
+	b.WriteString(line + "\n")
+	return b.String()
+}
+
+// columns estimates how many terminal columns s takes.
+func columns(s string) int {
+	n := utf8.RuneCountInString(s)
+	for _, r := range s {
+		if r >= 0x1F000 || r == '✅' || r == '❌' {
+			n++
+		}
+	}
+	return n
+}
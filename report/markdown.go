@@ -0,0 +1,96 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/iportilla/ai-coding/bench"
+)
+
+// WriteMarkdown renders r as GitHub-flavoured Markdown: one timing table
+// per section with speedup ratios, or ratios to the target, and the
+// garbage collection each implementation caused, followed by the edge
+// cases and the lesson.
+func WriteMarkdown(w io.Writer, r *Report) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "# %s\n\n", r.Title)
+	fmt.Fprintf(bw, "_Generated %s on %s · %s_\n",
+		r.Generated.Format("2006-01-02 15:04 MST"), environment(), describeOptions(r.Options))
+
+	for _, s := range r.Sections {
+		fmt.Fprintf(bw, "\n## %s\n\n", s.Title)
+		fmt.Fprintln(bw, "| Implementation | Complexity | Median | Min | Mean ± StdDev | Allocated | Allocs | GCs | GC pause | Relative |")
+		fmt.Fprintln(bw, "|---|---|--:|--:|--:|--:|--:|--:|--:|---|")
+		for i, res := range s.Results {
+			name, relative := mdEscape(res.Name), s.Relative(i)
+			if s.best(i) {
+				name, relative = "**"+name+"**", "**"+relative+"**"
+			}
+			if res.DNF {
+				fmt.Fprintf(bw, "| %s | %s | %s | – | – | – | – | – | – | %s |\n", name, mdEscape(res.Complexity), res.DNFLabel(), relative)
+				continue
+			}
+			fmt.Fprintf(bw, "| %s | %s | %s | %s | %s ± %s | %s | %d | %.1f | %s | %s |\n",
+				name, mdEscape(res.Complexity),
+				formatMs(res.Duration.Seconds()*1000), formatMs(res.Stats.Min.Seconds()*1000),
+				formatMs(res.Stats.Mean.Seconds()*1000), formatMs(res.Stats.StdDev.Seconds()*1000),
+				bench.FormatBytes(res.Bytes), res.Allocs,
+				res.GCsPerRun(), formatMs(res.GCPausePerRun().Seconds()*1000), relative)
+		}
+		if len(s.Notes) > 0 {
+			fmt.Fprintln(bw)
+			for _, note := range s.Notes {
+				fmt.Fprintf(bw, "> %s\n", note)
+			}
+		}
+	}
+
+	if len(r.EdgeCases) > 0 {
+		fmt.Fprintln(bw, "\n## Edge Cases")
+		fmt.Fprintln(bw)
+		fmt.Fprintln(bw, "| Input | Result |")
+		fmt.Fprintln(bw, "|---|---|")
+		for _, e := range r.EdgeCases {
+			fmt.Fprintf(bw, "| %s | `%s` |\n", mdEscape(e.Input), e.Output)
+		}
+	}
+
+	if len(r.Lesson.Tiers) > 0 || r.Lesson.Takeaway != "" {
+		fmt.Fprintln(bw, "\n## Summary")
+		for _, t := range r.Lesson.Tiers {
+			fmt.Fprintf(bw, "\n### %s\n\n", t.Heading())
+			for _, note := range t.Notes {
+				fmt.Fprintf(bw, "- %s %s\n", note.Kind.Marker(), note.Text)
+			}
+		}
+		if r.Lesson.Takeaway != "" {
+			fmt.Fprintf(bw, "\n**Key takeaway:** %s\n", r.Lesson.Takeaway)
+		}
+	}
+
+	return bw.Flush()
+}
+
+func describeOptions(o bench.Options) string {
+	runs := max(o.Runs, 1)
+	s := fmt.Sprintf("median of %d runs after %d warm-up", runs, o.Warmup)
+	if o.Limit > 0 {
+		s += fmt.Sprintf(", at most %s per implementation", o.Limit)
+	}
+	if o.Target > 0 {
+		s += fmt.Sprintf(", against a target of at least %s per run", o.Target)
+	}
+	return s
+}
+
+func formatMs(ms float64) string {
+	return fmt.Sprintf("%.4f ms", ms)
+}
+
+// mdEscape keeps table cells intact when text contains a pipe.
+func mdEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,138 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+)
+
+// Size of WritePlot's plotting area, in character cells.
+const (
+	plotWidth  = 60
+	plotHeight = 16
+)
+
+// plotMarkers mark the points of each series in WritePlot, in order;
+// plotShared marks a cell where points of several series land.
+var plotMarkers = []rune("●▲■◆★✚")
+
+const plotShared = '✱'
+
+// WritePlot draws each series' median durations against n on log-log
+// axes, for terminals. On log-log axes O(n^k) is a straight line of
+// slope k, so a glance shows which implementations grow alike: parallel
+// lines differ by a constant factor, steeper ones pull away as n grows.
+// Points of different series that land in the same cell are drawn as
+// one shared marker; results that did not finish aren't drawn.
+func WritePlot(w io.Writer, series []bench.Series) {
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, s := range series {
+		for i, n := range s.Sizes {
+			if n < 1 || s.Results[i].Duration <= 0 || s.Results[i].DNF {
+				continue
+			}
+			x, y := math.Log10(float64(n)), math.Log10(float64(s.Results[i].Duration))
+			minX, maxX = min(minX, x), max(maxX, x)
+			minY, maxY = min(minY, y), max(maxY, y)
+		}
+	}
+	if minX > maxX {
+		return // nothing to plot
+	}
+	// A single size or duration still needs a range to place it in.
+	if maxX-minX < 1 {
+		minX, maxX = (minX+maxX)/2-0.5, (minX+maxX)/2+0.5
+	}
+	if maxY-minY < 1 {
+		minY, maxY = (minY+maxY)/2-0.5, (minY+maxY)/2+0.5
+	}
+	col := func(x float64) int { return int(math.Round((x - minX) / (maxX - minX) * (plotWidth - 1))) }
+	row := func(y float64) int { return plotHeight - 1 - int(math.Round((y-minY)/(maxY-minY)*(plotHeight-1))) }
+
+	grid := make([][]rune, plotHeight)
+	for r := range grid {
+		grid[r] = []rune(strings.Repeat(" ", plotWidth))
+	}
+	shared := false
+	for si, s := range series {
+		for i, n := range s.Sizes {
+			if n < 1 || s.Results[i].Duration <= 0 || s.Results[i].DNF {
+				continue
+			}
+			x, y := math.Log10(float64(n)), math.Log10(float64(s.Results[i].Duration))
+			cell, marker := &grid[row(y)][col(x)], plotMarkers[si%len(plotMarkers)]
+			if *cell != ' ' && *cell != marker {
+				marker, shared = plotShared, true
+			}
+			*cell = marker
+		}
+	}
+
+	// Label the rows and columns where powers of ten fall, and the top
+	// and bottom rows too if that leaves fewer than two labels.
+	labels := make([]string, plotHeight)
+	for k := math.Ceil(minY); k <= maxY; k++ {
+		labels[row(k)] = roundDuration(k).String()
+	}
+	if math.Floor(maxY)-math.Ceil(minY) < 1 {
+		labels[0], labels[plotHeight-1] = roundDuration(maxY).String(), roundDuration(minY).String()
+	}
+	labelWidth := 0
+	for _, l := range labels {
+		labelWidth = max(labelWidth, len(l))
+	}
+
+	fmt.Fprintln(w)
+	for r, line := range grid {
+		axis := "│"
+		if labels[r] != "" {
+			axis = "┤"
+		}
+		fmt.Fprintf(w, "  %*s %s%s\n", labelWidth, labels[r], axis, strings.TrimRight(string(line), " "))
+	}
+	ticks := []rune(strings.Repeat("─", plotWidth))
+	under := []rune(strings.Repeat(" ", plotWidth+8))
+	free := 0 // first column a new label may start at
+	for k := math.Ceil(minX); k <= maxX; k++ {
+		c := col(k)
+		ticks[c] = '┬'
+		label := []rune(sizeLabel(int(math.Round(math.Pow(10, k)))))
+		start := max(c-len(label)/2, 0)
+		if start < free {
+			continue
+		}
+		copy(under[start:], label)
+		free = start + len(label) + 1
+	}
+	fmt.Fprintf(w, "  %*s └%s\n", labelWidth, "", string(ticks))
+	fmt.Fprintf(w, "  %*s  %s n\n", labelWidth, "", strings.TrimRight(string(under), " "))
+
+	var legend []string
+	for si, s := range series {
+		legend = append(legend, string(plotMarkers[si%len(plotMarkers)])+" "+s.Name)
+	}
+	if shared {
+		legend = append(legend, string(plotShared)+" several")
+	}
+	fmt.Fprintf(w, "  %*s  %s\n", labelWidth, "", strings.Join(legend, "   "))
+}
+
+// roundDuration returns 10^y nanoseconds to two significant digits.
+func roundDuration(y float64) time.Duration {
+	unit := time.Duration(math.Pow(10, math.Max(math.Floor(y)-1, 0)))
+	return time.Duration(math.Pow(10, y)).Round(unit)
+}
+
+// sizeLabel formats a power of ten briefly: 1, 10, 100, then 1e3, 1e4...
+func sizeLabel(n int) string {
+	if n < 1000 {
+		return strconv.Itoa(n)
+	}
+	return fmt.Sprintf("1e%d", len(strconv.Itoa(n))-1)
+}
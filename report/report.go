@@ -0,0 +1,172 @@
+// Package report turns benchmark results into documents that can be
+// pasted into course materials.
+//
+// An example collects what it measured into a Report - one Section per
+// input size plus any edge-case results - and a renderer such as
+// WriteMarkdown formats it. Keeping the data separate from the
+// formatting means every example gets every output format for free:
+// Markdown tables for course notes, or an HTML page with bar charts for
+// the classroom projector. WriteBars draws the same bars with block
+// characters, so the examples can show them in the terminal too, and
+// WritePlot draws a sweep across many sizes on log-log axes.
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/iportilla/ai-coding/bench"
+)
+
+// Report is everything an example measured in one run.
+type Report struct {
+	Title     string
+	Generated time.Time
+	Options   bench.Options // How the results were measured
+	Sections  []Section
+	EdgeCases []EdgeCase
+	Lesson    Lesson // What the example teaches, rendered after the results
+}
+
+// Section is one comparison, typically one input size.
+type Section struct {
+	Title   string         // e.g. "n = 1000"
+	Results []bench.Result // In the order the implementations ran
+	Notes   []string       // Free-form remarks, e.g. skipped implementations
+}
+
+// EdgeCase records the output for one unusual input.
+type EdgeCase struct {
+	Input  string
+	Output string
+}
+
+// New returns an empty report stamped with the current time.
+func New(title string, opts bench.Options) *Report {
+	return &Report{Title: title, Generated: time.Now(), Options: opts}
+}
+
+// Add appends a section and returns it so notes can be attached.
+func (r *Report) Add(title string, results []bench.Result) *Section {
+	r.Sections = append(r.Sections, Section{Title: title, Results: results})
+	return &r.Sections[len(r.Sections)-1]
+}
+
+// AddEdgeCase records the output for an edge-case input.
+func (r *Report) AddEdgeCase(input, output string) {
+	r.EdgeCases = append(r.EdgeCases, EdgeCase{Input: input, Output: output})
+}
+
+// Fastest returns the index of the quickest result that finished, or -1
+// if there are none.
+func (s Section) Fastest() int {
+	best := -1
+	for i, r := range s.Results {
+		if r.DNF {
+			continue
+		}
+		if best < 0 || r.Duration < s.Results[best].Duration {
+			best = i
+		}
+	}
+	return best
+}
+
+// Target returns the cost the section's results were measured against,
+// or 0 if they are ranked by speed.
+func (s Section) Target() time.Duration {
+	if len(s.Results) == 0 {
+		return 0
+	}
+	return s.Results[0].Target
+}
+
+// Relative describes how result i compares with the section's fastest
+// result, e.g. "fastest", "12.3x slower" or, if it did not finish,
+// "over 50.0x slower". Against a target, where slower is better, it
+// compares result i with the target instead: "on target" or "12.3x too
+// fast".
+func (s Section) Relative(i int) string {
+	if target := s.Target(); target > 0 {
+		if s.Results[i].OnTarget() {
+			return "on target"
+		}
+		return fmt.Sprintf("%.1fx too fast", float64(target)/float64(max(s.Results[i].Duration, 1)))
+	}
+	fastest := s.Fastest()
+	switch {
+	case i == fastest:
+		return "fastest"
+	case fastest < 0:
+		return "did not finish"
+	case s.Results[i].DNF:
+		return fmt.Sprintf("over %.1fx slower", bench.Speedup(s.Results[i], s.Results[fastest]))
+	}
+	return fmt.Sprintf("%.1fx slower", bench.Speedup(s.Results[i], s.Results[fastest]))
+}
+
+// best reports whether result i is the one to highlight: the fastest,
+// or, against a target, any result that reaches it.
+func (s Section) best(i int) bool {
+	if s.Target() > 0 {
+		return s.Results[i].OnTarget()
+	}
+	return i == s.Fastest()
+}
+
+// timing is a result's median in milliseconds, or its DNF label.
+func timing(r bench.Result) string {
+	if r.DNF {
+		return r.DNFLabel()
+	}
+	return fmt.Sprintf("%.4fms", r.Milliseconds())
+}
+
+// environment describes the machine the report was generated on.
+func environment() string {
+	return fmt.Sprintf("%s %s/%s, %d CPUs", runtime.Version(), runtime.GOOS, runtime.GOARCH, runtime.NumCPU())
+}
+
+// Formats lists the values accepted by Write.
+var Formats = []string{"markdown", "html"}
+
+// Extension returns the conventional file extension for format,
+// including the dot, or "" if the format is unknown.
+func Extension(format string) string {
+	switch format {
+	case "markdown", "md":
+		return ".md"
+	case "html":
+		return ".html"
+	}
+	return ""
+}
+
+// Write renders r in the named format.
+func Write(w io.Writer, format string, r *Report) error {
+	switch format {
+	case "markdown", "md":
+		return WriteMarkdown(w, r)
+	case "html":
+		return WriteHTML(w, r)
+	default:
+		return fmt.Errorf("unknown report format %q (want one of %v)", format, Formats)
+	}
+}
+
+// WriteFile renders r in the named format to path, creating or
+// truncating it.
+func WriteFile(path, format string, r *Report) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := Write(f, format, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -62,6 +62,618 @@ else
     echo "Skipped (Go not available)"
 fi
 
+echo ""
+echo "=================================="
+echo "Example 3: Sorting Algorithms (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 03-sorting
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 4: Searching a Sorted Slice (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 04-search
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 5: Primality Testing (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 05-primality
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 6: Fibonacci Numbers (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 06-fibonacci
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 7: String Building (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 07-string-building
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 8: Prime Factorization (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 08-factorization
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 9: Concurrent Task Processing (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 09-worker-pool
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 10: LRU Cache (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 10-lru-cache
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 11: JSON Parsing (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 11-json-parsing
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 12: Shortest Paths (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 12-shortest-paths
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 13: Hash Maps (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 13-hash-maps
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 14: Matrix Multiplication (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 14-matrix-multiply
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 15: Goldbach's Conjecture (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 15-goldbach
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 16: Modular Exponentiation (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 16-modular-exponentiation
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 17: Word Frequency (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 17-word-frequency
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 18: CSV Parsing (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 18-csv-parsing
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 19: HTTP Retries (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 19-http-retry
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 20: Rate Limiting (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 20-rate-limiter
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 21: Concurrent Counters (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 21-counters
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 22: Producer/Consumer Pipeline (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 22-pipeline
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 23: Prefix Search (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 23-prefix-search
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 24: Priority Queue (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 24-priority-queue
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 25: Union-Find (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 25-union-find
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 26: 0/1 Knapsack (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 26-knapsack
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 27: Longest Common Subsequence (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 27-lcs
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 28: Slice Growth (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 28-slice-growth
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 29: String Interning (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 29-string-interning
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 30: Error Handling (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 30-error-handling
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 31: Context Propagation (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 31-context-propagation
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 32: Checksums (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 32-checksums
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 33: Compression Trade-offs (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 33-compression
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 34: Binary Serialization (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 34-serialization
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 35: Reflection vs Code Generation (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 35-code-generation
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 36: Interface Dispatch vs Generics (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 36-dispatch
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 37: Buffer Reuse with sync.Pool (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 37-buffer-pool
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 38: Goroutine Leaks (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 38-goroutine-leak
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 39: TCP Echo Servers (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 39-echo-server
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 40: HTTP Middleware (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 40-http-middleware
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 41: HTML Templates (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 41-templates
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 42: Reading Large Files (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 42-file-reading
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 43: Finding Duplicates (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 43-duplicates
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 44: Anagram Grouping (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 44-anagrams
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 45: Top-K Selection (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 45-top-k
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 46: Timestamp Parsing (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 46-time-parsing
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 47: Unique IDs (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 47-unique-ids
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 48: Password Hashing (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 48-password-hashing
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 49: Random Sampling (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 49-sampling
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 50: Polynomial Multiplication (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 50-polynomial-multiplication
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 51: GCD and Modular Inverses (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 51-gcd
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 52: Run-Length Encoding (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 52-run-length-encoding
+else
+    echo "Skipped (Go not available)"
+fi
+
+echo ""
+echo "=================================="
+echo "Example 53: Palindromes and String Reversal (Go)"
+echo "=================================="
+echo ""
+
+if [ -z "$SKIP_GO" ]; then
+    go run ./cmd/ai-coding run 53-palindromes
+else
+    echo "Skipped (Go not available)"
+fi
+
 echo ""
 echo "=================================="
 echo "All examples completed!"
@@ -0,0 +1,71 @@
+// Package style colors terminal output, for writers that asked for it.
+//
+// Color is opt-in per writer: the command-line tool marks standard
+// output with Color when it is a terminal and NO_COLOR is not set, and
+// printers such as bench.Print call Paint, which leaves text alone on
+// every other writer. Output piped to a file, a CSV or benchstat stays
+// free of escape codes without any printer having to check.
+package style
+
+import (
+	"io"
+	"os"
+	"runtime"
+)
+
+// Style is an ANSI escape sequence that sets the text color.
+type Style string
+
+const (
+	Green  Style = "\x1b[32m" // The fastest result
+	Yellow Style = "\x1b[33m" // Results in between
+	Red    Style = "\x1b[31m" // The slowest result, or one that did not finish
+)
+
+const reset = "\x1b[0m"
+
+// colorWriter is a writer marked by Color.
+type colorWriter struct {
+	io.Writer
+}
+
+// Color returns w marked as accepting ANSI color, so Paint colors what
+// is written to it.
+func Color(w io.Writer) io.Writer {
+	if Enabled(w) {
+		return w
+	}
+	return colorWriter{w}
+}
+
+// Enabled reports whether w was marked by Color.
+func Enabled(w io.Writer) bool {
+	_, ok := w.(colorWriter)
+	return ok
+}
+
+// Paint returns s in st's color if w accepts color, and s unchanged
+// otherwise. Paint whole padded fields rather than the text inside them,
+// since the escape codes count towards fmt's widths but take up no
+// columns.
+func (st Style) Paint(w io.Writer, s string) string {
+	if st == "" || s == "" || !Enabled(w) {
+		return s
+	}
+	return string(st) + s + reset
+}
+
+// Terminal reports whether f should get color by default: it is a
+// terminal, NO_COLOR (https://no-color.org) is not set, and TERM is not
+// "dumb". On Windows the terminal must also be one that understands ANSI
+// escape codes, which the classic console does only when asked to.
+func Terminal(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	if runtime.GOOS == "windows" && os.Getenv("WT_SESSION") == "" && os.Getenv("TERM_PROGRAM") != "vscode" {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,312 @@
+// Package trace records OpenTelemetry spans of benchmark runs - one per
+// example, one per implementation and one per run - so a run in CI or on
+// the server can be opened in Jaeger, Tempo or any other OpenTelemetry
+// backend, and the time an example spends setting up, generating inputs
+// and checking answers, seen next to the time it spends computing.
+//
+// It speaks just enough of the protocol for that, OTLP's JSON encoding,
+// rather than depending on the OpenTelemetry SDK:
+//
+//	t := trace.New("ai-coding", "http://localhost:4318")
+//	ctx, span := trace.Start(trace.WithTracer(ctx, t), "03-sorting")
+//	results, err := bench.CompareContext(ctx, opts, impls...)
+//	span.End(err)
+//	err = t.Flush(ctx)
+//
+// Without a Tracer in the context, Start, Record and End do nothing, so
+// the harness can call them unconditionally.
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Attr is an attribute of a span, e.g. the implementation's complexity.
+type Attr struct {
+	Key   string
+	Value any // A string, int, int64, float64 or bool
+}
+
+// String returns a string attribute.
+func String(key, value string) Attr { return Attr{key, value} }
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attr { return Attr{key, int64(value)} }
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) Attr { return Attr{key, value} }
+
+// Span is one timed operation. Spans started from a context that carries
+// a span are its children, in the same trace.
+type Span struct {
+	TraceID [16]byte
+	ID      [8]byte
+	Parent  [8]byte // All zero for a trace's root span
+	Name    string
+	Begin   time.Time
+	Finish  time.Time
+	Attrs   []Attr
+	Error   string // Why the operation failed, if it did
+
+	tracer *Tracer
+}
+
+// Tracer collects finished spans until they are flushed to its
+// destination.
+type Tracer struct {
+	Service     string // The service.name of every span, e.g. "ai-coding"
+	Destination string // An OTLP/HTTP endpoint URL, or a file to append to
+
+	mu    sync.Mutex
+	spans []*Span
+}
+
+// New returns a Tracer that flushes spans to dest: an http:// or
+// https:// URL of an OTLP/HTTP collector, such as
+// http://localhost:4318, or otherwise a file that gets one line of OTLP
+// JSON per flush, the format of the OpenTelemetry Collector's file
+// exporter.
+func New(service, dest string) *Tracer {
+	return &Tracer{Service: service, Destination: dest}
+}
+
+type tracerKey struct{}
+
+type spanKey struct{}
+
+// WithTracer returns a copy of ctx whose spans t records.
+func WithTracer(ctx context.Context, t *Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, t)
+}
+
+// Start starts a span called name, the child of ctx's span if it has
+// one, and returns it with a copy of ctx that carries it. Without a
+// Tracer in ctx it returns ctx and a nil Span.
+func Start(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	s := newSpan(ctx, name, time.Now(), attrs)
+	if s == nil {
+		return ctx, nil
+	}
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// Record adds a span that has already finished, from start to end, as
+// a child of ctx's span. It lets the harness time a run first and trace
+// it afterwards, keeping the tracer out of the timed section.
+func Record(ctx context.Context, name string, start, end time.Time, attrs ...Attr) {
+	if s := newSpan(ctx, name, start, attrs); s != nil {
+		s.finish(end, nil)
+	}
+}
+
+// newSpan returns a span of ctx's Tracer, or nil if ctx has none.
+func newSpan(ctx context.Context, name string, start time.Time, attrs []Attr) *Span {
+	t, _ := ctx.Value(tracerKey{}).(*Tracer)
+	if t == nil {
+		return nil
+	}
+	s := &Span{Name: name, Begin: start, Attrs: attrs, tracer: t}
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok {
+		s.TraceID, s.Parent = parent.TraceID, parent.ID
+	} else {
+		randomID(s.TraceID[:])
+	}
+	randomID(s.ID[:])
+	return s
+}
+
+// randomID fills id with random bytes, never all zero.
+func randomID(id []byte) {
+	for i := range id {
+		id[i] = byte(rand.Uint32())
+	}
+	id[0] |= 1
+}
+
+// SetAttrs adds attributes to s. A nil Span ignores them.
+func (s *Span) SetAttrs(attrs ...Attr) {
+	if s != nil {
+		s.Attrs = append(s.Attrs, attrs...)
+	}
+}
+
+// End finishes s, marking it failed if err is not nil. A nil Span does
+// nothing.
+func (s *Span) End(err error) {
+	if s != nil {
+		s.finish(time.Now(), err)
+	}
+}
+
+func (s *Span) finish(end time.Time, err error) {
+	s.Finish = end
+	if err != nil {
+		s.Error = err.Error()
+	}
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.tracer.spans = append(s.tracer.spans, s)
+}
+
+// Flush sends the spans finished so far to t's destination and forgets
+// them. A nil Tracer, or one with no spans, sends nothing.
+func (t *Tracer) Flush(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	spans := t.spans
+	t.spans = nil
+	t.mu.Unlock()
+	if len(spans) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(t.request(spans))
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(t.Destination, "http://") && !strings.HasPrefix(t.Destination, "https://") {
+		f, err := os.OpenFile(t.Destination, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+
+	u, err := url.Parse(t.Destination)
+	if err != nil {
+		return err
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/v1/traces" // a collector's base URL, as in OTEL_EXPORTER_OTLP_ENDPOINT
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s: %s", u, resp.Status)
+	}
+	return nil
+}
+
+// The OTLP JSON encoding of an ExportTraceServiceRequest, as much of it
+// as the spans here use. IDs are hex and times are nanoseconds since the
+// Unix epoch, in strings.
+type (
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpAttr `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID      string     `json:"traceId"`
+		SpanID       string     `json:"spanId"`
+		ParentSpanID string     `json:"parentSpanId,omitempty"`
+		Name         string     `json:"name"`
+		Kind         int        `json:"kind"`
+		Start        string     `json:"startTimeUnixNano"`
+		End          string     `json:"endTimeUnixNano"`
+		Attributes   []otlpAttr `json:"attributes,omitempty"`
+		Status       otlpStatus `json:"status"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code,omitempty"` // 2 for an error
+		Message string `json:"message,omitempty"`
+	}
+	otlpAttr struct {
+		Key   string         `json:"key"`
+		Value map[string]any `json:"value"`
+	}
+)
+
+// request encodes spans for OTLP.
+func (t *Tracer) request(spans []*Span) otlpRequest {
+	host, _ := os.Hostname()
+	scope := otlpScopeSpans{Scope: otlpScope{Name: "github.com/iportilla/ai-coding/trace"}}
+	for _, s := range spans {
+		span := otlpSpan{
+			TraceID:    hex.EncodeToString(s.TraceID[:]),
+			SpanID:     hex.EncodeToString(s.ID[:]),
+			Name:       s.Name,
+			Kind:       1, // internal
+			Start:      strconv.FormatInt(s.Begin.UnixNano(), 10),
+			End:        strconv.FormatInt(s.Finish.UnixNano(), 10),
+			Attributes: encodeAttrs(s.Attrs),
+		}
+		if s.Parent != [8]byte{} {
+			span.ParentSpanID = hex.EncodeToString(s.Parent[:])
+		}
+		if s.Error != "" {
+			span.Status = otlpStatus{Code: 2, Message: s.Error}
+		}
+		scope.Spans = append(scope.Spans, span)
+	}
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: encodeAttrs([]Attr{
+			String("service.name", t.Service),
+			String("host.name", host),
+		})},
+		ScopeSpans: []otlpScopeSpans{scope},
+	}}}
+}
+
+// encodeAttrs encodes attributes as OTLP AnyValues. Integers are strings
+// in OTLP JSON, since JSON numbers can't hold every int64.
+func encodeAttrs(attrs []Attr) []otlpAttr {
+	var out []otlpAttr
+	for _, a := range attrs {
+		var v map[string]any
+		switch x := a.Value.(type) {
+		case string:
+			v = map[string]any{"stringValue": x}
+		case int:
+			v = map[string]any{"intValue": strconv.Itoa(x)}
+		case int64:
+			v = map[string]any{"intValue": strconv.FormatInt(x, 10)}
+		case float64:
+			v = map[string]any{"doubleValue": x}
+		case bool:
+			v = map[string]any{"boolValue": x}
+		default:
+			v = map[string]any{"stringValue": fmt.Sprint(x)}
+		}
+		out = append(out, otlpAttr{a.Key, v})
+	}
+	return out
+}